@@ -11,6 +11,7 @@ import (
 	"os/signal"
 	"strconv"
 	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	api "github.com/guidewire-oss/fern-platform/internal/api"
@@ -57,14 +58,21 @@ func main() {
 	logger.WithService("fern-platform").Info("Database migrations completed successfully")
 
 	// Initialize domain factory for DDD architecture
-	domainFactory := domains.NewDomainFactory(db.DB, logger, &cfg.Auth)
+	domainFactory := domains.NewDomainFactory(db.DB, logger, &cfg.Auth, &cfg.Ingestion, &cfg.Storage)
 
 	// Get domain services directly
 	testingService := domainFactory.GetTestingService()
+	idempotencyService := domainFactory.GetIdempotencyService()
+	ingestionQueueService := domainFactory.GetIngestionQueueService()
+	attachmentService := domainFactory.GetAttachmentService()
+	quarantineService := domainFactory.GetQuarantineService()
 	projectService := domainFactory.GetProjectDomainService()
 	tagService := domainFactory.GetTagDomainService()
 	flakyDetectionService := domainFactory.GetFlakyDetectionService()
 	jiraConnectionService := domainFactory.GetJiraConnectionService()
+	ingestionTokenService := domainFactory.GetIngestionTokenService()
+	accessTokenService := domainFactory.GetAccessTokenService()
+	ciFederationService := domainFactory.GetCIFederationService()
 	authMiddleware := domainFactory.GetAuthMiddleware()
 
 	// Initialize HTTP server
@@ -100,10 +108,17 @@ func main() {
 		// Use the new split handler architecture
 		domainHandler := api.NewDomainHandlerV2(
 			testingService,
+			idempotencyService,
+			ingestionQueueService,
+			attachmentService,
+			quarantineService,
 			projectService,
 			tagService,
 			flakyDetectionService,
 			jiraConnectionService,
+			ingestionTokenService,
+			accessTokenService,
+			ciFederationService,
 			authMiddleware,
 			logger,
 		)
@@ -113,10 +128,17 @@ func main() {
 		// Use the original monolithic handler for backward compatibility
 		domainHandler := api.NewDomainHandler(
 			testingService,
+			idempotencyService,
+			ingestionQueueService,
+			attachmentService,
+			quarantineService,
 			projectService,
 			tagService,
 			flakyDetectionService,
 			jiraConnectionService,
+			ingestionTokenService,
+			accessTokenService,
+			ciFederationService,
 			authMiddleware,
 			logger,
 		)
@@ -126,7 +148,7 @@ func main() {
 
 	// GraphQL routes with role group names from config
 	// Initialize GraphQL resolver with domain services
-	resolver := graphql.NewResolver(testingService, projectService, tagService, flakyDetectionService, jiraConnectionService, db.DB, logger)
+	resolver := graphql.NewResolver(testingService, idempotencyService, attachmentService, projectService, tagService, flakyDetectionService, jiraConnectionService, db.DB, logger)
 
 	roleGroupNames := &graphql.RoleGroupNames{
 		AdminGroup:   cfg.Auth.OAuth.AdminGroupName,
@@ -139,6 +161,50 @@ func main() {
 
 	// Note: Static file serving is handled by the API handler
 
+	// Periodically drop expired ingestion idempotency keys and old finished ingestion jobs
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			if _, err := idempotencyService.PurgeExpired(context.Background()); err != nil {
+				logger.WithService("fern-platform").WithError(err).Warn("Failed to purge expired idempotency keys")
+			}
+			if _, err := ingestionQueueService.PurgeFinished(context.Background()); err != nil {
+				logger.WithService("fern-platform").WithError(err).Warn("Failed to purge finished ingestion jobs")
+			}
+		}
+	}()
+
+	// Close sharded runs whose remaining shards never arrived
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			if _, err := testingService.FinalizeTimedOutShardGroups(context.Background(), cfg.Ingestion.ShardTimeout); err != nil {
+				logger.WithService("fern-platform").WithError(err).Warn("Failed to finalize timed out shard groups")
+			}
+		}
+	}()
+
+	// Abort streamed runs whose reporter stopped sending heartbeats
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			if _, err := testingService.AbortStalledRuns(context.Background()); err != nil {
+				logger.WithService("fern-platform").WithError(err).Warn("Failed to abort stalled streamed runs")
+			}
+		}
+	}()
+
+	// Process asynchronous ingestions in the background
+	ingestionWorkers := domainFactory.NewIngestionWorkerPool()
+	ingestionWorkers.Start()
+	logger.WithService("fern-platform").
+		WithFields(map[string]interface{}{
+			"workers": cfg.Ingestion.Workers,
+		}).Info("Started ingestion worker pool")
+
 	// Create HTTP server
 	srv := &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
@@ -182,5 +248,15 @@ func main() {
 		logger.WithService("fern-platform").WithError(err).Fatal("Server forced to shutdown")
 	}
 
+	// Let in-flight ingestions finish; unfinished ones are released back to the queue
+	if err := ingestionWorkers.Shutdown(ctx); err != nil {
+		logger.WithService("fern-platform").WithError(err).Warn("Ingestion workers did not drain before shutdown timeout")
+	}
+
+	// Analyze the completed runs still waiting out their debounce
+	if err := domainFactory.GetFlakyAnalysisScheduler().Shutdown(ctx); err != nil {
+		logger.WithService("fern-platform").WithError(err).Warn("Flaky analysis did not finish before shutdown timeout")
+	}
+
 	logger.WithService("fern-platform").Info("Server exited")
 }
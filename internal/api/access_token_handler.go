@@ -0,0 +1,283 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	authApp "github.com/guidewire-oss/fern-platform/internal/domains/auth/application"
+	authDomain "github.com/guidewire-oss/fern-platform/internal/domains/auth/domain"
+	"github.com/guidewire-oss/fern-platform/internal/domains/auth/interfaces"
+)
+
+// AccessTokenHandler handles personal access tokens and service accounts
+type AccessTokenHandler struct {
+	*BaseHandler
+	tokenService *authApp.AccessTokenService
+}
+
+// NewAccessTokenHandler creates a new access token handler
+func NewAccessTokenHandler(baseHandler *BaseHandler, tokenService *authApp.AccessTokenService) *AccessTokenHandler {
+	return &AccessTokenHandler{
+		BaseHandler:  baseHandler,
+		tokenService: tokenService,
+	}
+}
+
+// CreateAccessTokenRequest represents the request to create an access token
+type CreateAccessTokenRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// AccessTokenResponse represents an access token. Token is only set when the token has just
+// been created.
+type AccessTokenResponse struct {
+	ID          string     `json:"id"`
+	UserID      string     `json:"userId"`
+	Name        string     `json:"name"`
+	TokenPrefix string     `json:"tokenPrefix"`
+	Token       string     `json:"token,omitempty"`
+	Scopes      []string   `json:"scopes"`
+	CreatedBy   string     `json:"createdBy,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt  *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt   *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+// CreateServiceAccountRequest represents the request to create a service account
+type CreateServiceAccountRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// ServiceAccountResponse represents a service account
+type ServiceAccountResponse struct {
+	UserID    string    `json:"userId"`
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// RegisterRoutes registers access token routes. Users manage their own tokens; admins manage
+// service accounts and their tokens.
+func (h *AccessTokenHandler) RegisterRoutes(userGroup, adminGroup *gin.RouterGroup) {
+	tokens := userGroup.Group("/user/tokens")
+	{
+		tokens.GET("", h.GetUserTokens)
+		tokens.POST("", h.CreateUserToken)
+		tokens.DELETE("/:tokenId", h.RevokeUserToken)
+	}
+
+	accounts := adminGroup.Group("/service-accounts")
+	{
+		accounts.GET("", h.GetServiceAccounts)
+		accounts.POST("", h.CreateServiceAccount)
+		accounts.DELETE("/:userId", h.DisableServiceAccount)
+		accounts.GET("/:userId/tokens", h.GetServiceAccountTokens)
+		accounts.POST("/:userId/tokens", h.CreateServiceAccountToken)
+		accounts.DELETE("/:userId/tokens/:tokenId", h.RevokeServiceAccountToken)
+	}
+}
+
+// GetUserTokens lists the current user's access tokens
+func (h *AccessTokenHandler) GetUserTokens(c *gin.Context) {
+	userID, ok := h.requireSessionUser(c)
+	if !ok {
+		return
+	}
+
+	h.listTokens(c, userID)
+}
+
+// CreateUserToken issues a personal access token for the current user
+func (h *AccessTokenHandler) CreateUserToken(c *gin.Context) {
+	userID, ok := h.requireSessionUser(c)
+	if !ok {
+		return
+	}
+
+	h.createToken(c, userID)
+}
+
+// RevokeUserToken permanently disables one of the current user's access tokens
+func (h *AccessTokenHandler) RevokeUserToken(c *gin.Context) {
+	userID, ok := h.requireSessionUser(c)
+	if !ok {
+		return
+	}
+
+	h.revokeToken(c, userID)
+}
+
+// GetServiceAccounts lists all service accounts
+func (h *AccessTokenHandler) GetServiceAccounts(c *gin.Context) {
+	accounts, err := h.tokenService.ListServiceAccounts(c.Request.Context())
+	if err != nil {
+		h.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	responses := make([]ServiceAccountResponse, len(accounts))
+	for i, account := range accounts {
+		responses[i] = h.convertServiceAccountToResponse(account)
+	}
+
+	h.respondWithJSON(c, http.StatusOK, responses)
+}
+
+// CreateServiceAccount creates a service account
+func (h *AccessTokenHandler) CreateServiceAccount(c *gin.Context) {
+	var req CreateServiceAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	account, err := h.tokenService.CreateServiceAccount(c.Request.Context(), req.Name)
+	if err != nil {
+		h.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.respondWithJSON(c, http.StatusCreated, h.convertServiceAccountToResponse(account))
+}
+
+// DisableServiceAccount deactivates a service account and revokes its tokens
+func (h *AccessTokenHandler) DisableServiceAccount(c *gin.Context) {
+	if err := h.tokenService.DisableServiceAccount(c.Request.Context(), c.Param("userId")); err != nil {
+		h.ErrorResponse(c, accessTokenErrorStatus(err), err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetServiceAccountTokens lists a service account's tokens
+func (h *AccessTokenHandler) GetServiceAccountTokens(c *gin.Context) {
+	if !h.serviceAccountExists(c) {
+		return
+	}
+
+	h.listTokens(c, c.Param("userId"))
+}
+
+// CreateServiceAccountToken issues a token for a service account
+func (h *AccessTokenHandler) CreateServiceAccountToken(c *gin.Context) {
+	if !h.serviceAccountExists(c) {
+		return
+	}
+
+	h.createToken(c, c.Param("userId"))
+}
+
+// RevokeServiceAccountToken permanently disables a service account token
+func (h *AccessTokenHandler) RevokeServiceAccountToken(c *gin.Context) {
+	if !h.serviceAccountExists(c) {
+		return
+	}
+
+	h.revokeToken(c, c.Param("userId"))
+}
+
+func (h *AccessTokenHandler) listTokens(c *gin.Context, userID string) {
+	tokens, err := h.tokenService.ListTokens(c.Request.Context(), userID)
+	if err != nil {
+		h.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	responses := make([]AccessTokenResponse, len(tokens))
+	for i, token := range tokens {
+		responses[i] = *h.convertToResponse(token)
+	}
+
+	h.respondWithJSON(c, http.StatusOK, responses)
+}
+
+func (h *AccessTokenHandler) createToken(c *gin.Context, userID string) {
+	var req CreateAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	token, secret, err := h.tokenService.CreateToken(c.Request.Context(), userID, req.Name, req.Scopes, h.getUserID(c), req.ExpiresAt)
+	if err != nil {
+		h.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := h.convertToResponse(token)
+	response.Token = secret
+	h.respondWithJSON(c, http.StatusCreated, response)
+}
+
+func (h *AccessTokenHandler) revokeToken(c *gin.Context, userID string) {
+	if err := h.tokenService.RevokeToken(c.Request.Context(), userID, c.Param("tokenId")); err != nil {
+		h.ErrorResponse(c, accessTokenErrorStatus(err), err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// requireSessionUser returns the current user's ID. Tokens cannot be managed without a signed-in
+// user, and not with an access token, so that a leaked token cannot mint further tokens.
+func (h *AccessTokenHandler) requireSessionUser(c *gin.Context) (string, bool) {
+	if _, ok := interfaces.GetAccessToken(c); ok {
+		h.ErrorResponse(c, http.StatusForbidden, "access tokens cannot be managed with an access token")
+		return "", false
+	}
+
+	userID := h.getUserID(c)
+	if userID == "" {
+		h.ErrorResponse(c, http.StatusUnauthorized, "Authentication required")
+		return "", false
+	}
+	return userID, true
+}
+
+// serviceAccountExists responds with 404 if the :userId parameter is not a service account
+func (h *AccessTokenHandler) serviceAccountExists(c *gin.Context) bool {
+	if _, err := h.tokenService.GetServiceAccount(c.Request.Context(), c.Param("userId")); err != nil {
+		h.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return false
+	}
+	return true
+}
+
+// accessTokenErrorStatus maps unknown tokens and service accounts to 404 and storage failures to 500
+func accessTokenErrorStatus(err error) int {
+	if errors.Is(err, authDomain.ErrAccessTokenNotFound) || errors.Is(err, authDomain.ErrServiceAccountNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// convertToResponse converts a token to its response, never including the stored hash
+func (h *AccessTokenHandler) convertToResponse(token *authDomain.AccessToken) *AccessTokenResponse {
+	return &AccessTokenResponse{
+		ID:          token.ID,
+		UserID:      token.UserID,
+		Name:        token.Name,
+		TokenPrefix: token.TokenPrefix,
+		Scopes:      token.Scopes,
+		CreatedBy:   token.CreatedBy,
+		ExpiresAt:   token.ExpiresAt,
+		LastUsedAt:  token.LastUsedAt,
+		RevokedAt:   token.RevokedAt,
+		CreatedAt:   token.CreatedAt,
+	}
+}
+
+func (h *AccessTokenHandler) convertServiceAccountToResponse(account *authDomain.User) ServiceAccountResponse {
+	return ServiceAccountResponse{
+		UserID:    account.UserID,
+		Name:      account.Name,
+		Status:    string(account.Status),
+		CreatedAt: account.CreatedAt,
+	}
+}
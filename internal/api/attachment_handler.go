@@ -0,0 +1,332 @@
+// Package api provides domain-based REST API handlers
+package api
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	authInterfaces "github.com/guidewire-oss/fern-platform/internal/domains/auth/interfaces"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+	"github.com/guidewire-oss/fern-platform/pkg/logging"
+)
+
+// maxAttachmentFormOverhead allows for the multipart framing around an uploaded file
+const maxAttachmentFormOverhead = 1 << 20
+
+// AttachmentHandler handles test attachment upload and download endpoints
+type AttachmentHandler struct {
+	*BaseHandler
+	attachmentService *application.AttachmentService
+	testingService    *application.TestRunService
+}
+
+// NewAttachmentHandler creates a new attachment handler
+func NewAttachmentHandler(
+	attachmentService *application.AttachmentService,
+	testingService *application.TestRunService,
+	logger *logging.Logger,
+) *AttachmentHandler {
+	return &AttachmentHandler{
+		BaseHandler:       NewBaseHandler(logger),
+		attachmentService: attachmentService,
+		testingService:    testingService,
+	}
+}
+
+// RegisterRoutes registers attachment routes. Downloads are authorized by their signed URL
+// and so are registered on the public group.
+func (h *AttachmentHandler) RegisterRoutes(publicGroup, ingestGroup, userGroup *gin.RouterGroup) {
+	publicGroup.GET("/attachments/:id/content", h.downloadAttachment)
+	ingestGroup.POST("/projects/:projectId/test-runs/:runId/attachments", h.uploadIngestedAttachment)
+	userGroup.POST("/test-runs/:id/attachments", h.uploadAttachment)
+	userGroup.GET("/test-runs/:id/attachments", h.listAttachments)
+	userGroup.GET("/attachments/:id", h.getAttachment)
+}
+
+// uploadIngestedAttachment handles POST /api/v1/projects/:projectId/test-runs/:runId/attachments,
+// letting CI attach files to a run it reported by the run ID it chose
+func (h *AttachmentHandler) uploadIngestedAttachment(c *gin.Context) {
+	testRun, err := h.testingService.GetTestRunByRunID(c.Request.Context(), c.Param("runId"))
+	if err != nil || testRun.ProjectID != c.Param("projectId") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Test run not found"})
+		return
+	}
+
+	h.upload(c, testRun.ID)
+}
+
+// uploadAttachment handles POST /api/v1/test-runs/:id/attachments
+func (h *AttachmentHandler) uploadAttachment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid test run ID"})
+		return
+	}
+	if !h.checkTestRunScope(c, uint(id)) {
+		return
+	}
+
+	h.upload(c, uint(id))
+}
+
+// checkTestRunScope checks that a request made with an access token has a scope on the project
+// of a test run, responding with 404 or 403 and returning false otherwise
+func (h *AttachmentHandler) checkTestRunScope(c *gin.Context, testRunID uint) bool {
+	if _, ok := authInterfaces.GetAccessToken(c); !ok {
+		return true
+	}
+	testRun, err := h.testingService.GetTestRun(c.Request.Context(), testRunID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Test run not found"})
+		return false
+	}
+	return authInterfaces.CheckProjectScope(c, testRun.ProjectID)
+}
+
+// upload stores the multipart "file" field, attached to the suite or spec named by the
+// suiteRunId/specRunId or suite/spec form fields, or to the run itself
+func (h *AttachmentHandler) upload(c *gin.Context, testRunID uint) {
+	maxUploadSize := h.attachmentService.MaxSize()
+	if maxUploadSize <= 0 {
+		maxUploadSize = maxIngestBodySize
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadSize+maxAttachmentFormOverhead)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": domain.ErrAttachmentTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a multipart \"file\" field is required"})
+		return
+	}
+
+	req := application.UploadAttachmentRequest{
+		TestRunID:   testRunID,
+		Name:        c.DefaultPostForm("name", header.Filename),
+		ContentType: header.Header.Get("Content-Type"),
+		Size:        header.Size,
+	}
+	if err := h.resolveTarget(c, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read uploaded file"})
+		return
+	}
+	defer file.Close()
+	req.Content = file
+
+	attachment, err := h.attachmentService.Upload(c.Request.Context(), req)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to upload attachment")
+		c.JSON(attachmentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, h.convertAttachmentToAPI(attachment))
+}
+
+// resolveTarget reads the suite and spec an upload is attached to, looking them up by name
+// when the form names them rather than giving their IDs
+func (h *AttachmentHandler) resolveTarget(c *gin.Context, req *application.UploadAttachmentRequest) error {
+	for field, target := range map[string]**uint{"suiteRunId": &req.SuiteRunID, "specRunId": &req.SpecRunID} {
+		if value := c.PostForm(field); value != "" {
+			id, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return fmt.Errorf("invalid %s: %q", field, value)
+			}
+			runID := uint(id)
+			*target = &runID
+		}
+	}
+
+	suiteName, specName := c.PostForm("suite"), c.PostForm("spec")
+	if suiteName == "" && specName == "" {
+		return nil
+	}
+
+	testRun, err := h.testingService.GetTestRunWithDetails(c.Request.Context(), req.TestRunID)
+	if err != nil {
+		return fmt.Errorf("test run not found")
+	}
+	var matches int
+	for i := range testRun.SuiteRuns {
+		suite := &testRun.SuiteRuns[i]
+		if suiteName != "" && suite.Name != suiteName {
+			continue
+		}
+		if specName == "" {
+			req.SuiteRunID = &suite.ID
+			matches++
+			continue
+		}
+		for _, spec := range suite.SpecRuns {
+			if spec.Name == specName {
+				req.SuiteRunID, req.SpecRunID = &suite.ID, &spec.ID
+				matches++
+			}
+		}
+	}
+
+	switch {
+	case matches == 0:
+		return fmt.Errorf("no suite or spec in the test run matches suite %q and spec %q", suiteName, specName)
+	case matches > 1:
+		return fmt.Errorf("suite %q and spec %q match more than one target in the test run", suiteName, specName)
+	}
+	return nil
+}
+
+// listAttachments handles GET /api/v1/test-runs/:id/attachments
+func (h *AttachmentHandler) listAttachments(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid test run ID"})
+		return
+	}
+
+	if !h.checkTestRunScope(c, uint(id)) {
+		return
+	}
+
+	filter := domain.AttachmentFilter{TestRunID: uint(id)}
+	for param, target := range map[string]**uint{"suiteRunId": &filter.SuiteRunID, "specRunId": &filter.SpecRunID} {
+		if value := c.Query(param); value != "" {
+			runID, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid %s: %q", param, value)})
+				return
+			}
+			targetID := uint(runID)
+			*target = &targetID
+		}
+	}
+
+	attachments, err := h.attachmentService.ListAttachments(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list attachments")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	result := make([]gin.H, len(attachments))
+	for i, attachment := range attachments {
+		result[i] = h.convertAttachmentToAPI(attachment)
+	}
+	c.JSON(http.StatusOK, gin.H{"attachments": result})
+}
+
+// getAttachment handles GET /api/v1/attachments/:id
+func (h *AttachmentHandler) getAttachment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID"})
+		return
+	}
+
+	attachment, err := h.attachmentService.GetAttachment(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(attachmentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if !authInterfaces.CheckProjectScope(c, attachment.ProjectID) {
+		return
+	}
+
+	c.JSON(http.StatusOK, h.convertAttachmentToAPI(attachment))
+}
+
+// downloadAttachment handles GET /api/v1/attachments/:id/content?expires=...&signature=...
+func (h *AttachmentHandler) downloadAttachment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID"})
+		return
+	}
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": application.ErrInvalidAttachmentSignature.Error()})
+		return
+	}
+	if err := h.attachmentService.VerifySignature(uint(id), expires, c.Query("signature")); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	attachment, content, err := h.attachmentService.OpenAttachment(c.Request.Context(), uint(id))
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to open attachment")
+		c.JSON(attachmentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	defer content.Close()
+
+	// Only media types that cannot carry scripts are shown inline
+	disposition := "attachment"
+	if isInlineContentType(attachment.ContentType) {
+		disposition = "inline"
+	}
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Name}),
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private, max-age=300",
+	})
+}
+
+// isInlineContentType reports whether a media type is safe to display in the browser
+func isInlineContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case mediaType == "image/svg+xml":
+		return false
+	case strings.HasPrefix(mediaType, "image/"), strings.HasPrefix(mediaType, "video/"):
+		return true
+	case mediaType == "text/plain", mediaType == "application/json":
+		return true
+	}
+	return false
+}
+
+// attachmentErrorStatus maps missing attachments to 404, oversized ones to 413, bad targets
+// to 400 and storage failures to 500
+func attachmentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrAttachmentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrAttachmentTooLarge), errors.Is(err, domain.ErrAttachmentQuotaExceeded):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, application.ErrInvalidAttachmentTarget):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// convertAttachmentToAPI describes an attachment together with a signed download URL
+func (h *AttachmentHandler) convertAttachmentToAPI(attachment *domain.Attachment) gin.H {
+	return gin.H{
+		"id":          attachment.ID,
+		"testRunId":   attachment.TestRunID,
+		"suiteRunId":  attachment.SuiteRunID,
+		"specRunId":   attachment.SpecRunID,
+		"name":        attachment.Name,
+		"contentType": attachment.ContentType,
+		"size":        attachment.Size,
+		"sha256":      attachment.SHA256,
+		"createdAt":   attachment.CreatedAt,
+		"url":         h.attachmentService.SignedURL(attachment),
+	}
+}
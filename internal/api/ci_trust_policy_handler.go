@@ -0,0 +1,147 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	authApp "github.com/guidewire-oss/fern-platform/internal/domains/auth/application"
+	authDomain "github.com/guidewire-oss/fern-platform/internal/domains/auth/domain"
+	projectsApp "github.com/guidewire-oss/fern-platform/internal/domains/projects/application"
+	projectsDomain "github.com/guidewire-oss/fern-platform/internal/domains/projects/domain"
+)
+
+// CITrustPolicyHandler handles the trust policies that let CI jobs upload with OIDC tokens
+type CITrustPolicyHandler struct {
+	*BaseHandler
+	federationService *authApp.CIFederationService
+	projectService    *projectsApp.ProjectService
+}
+
+// NewCITrustPolicyHandler creates a new CI trust policy handler
+func NewCITrustPolicyHandler(
+	baseHandler *BaseHandler,
+	federationService *authApp.CIFederationService,
+	projectService *projectsApp.ProjectService,
+) *CITrustPolicyHandler {
+	return &CITrustPolicyHandler{
+		BaseHandler:       baseHandler,
+		federationService: federationService,
+		projectService:    projectService,
+	}
+}
+
+// CreateCITrustPolicyRequest represents the request to create a CI trust policy
+type CreateCITrustPolicyRequest struct {
+	Issuer     string `json:"issuer" binding:"required"`
+	Repository string `json:"repository" binding:"required"`
+	Ref        string `json:"ref"`
+	Workflow   string `json:"workflow"`
+}
+
+// CITrustPolicyResponse represents a CI trust policy
+type CITrustPolicyResponse struct {
+	ID         string    `json:"id"`
+	ProjectID  string    `json:"projectId"`
+	Issuer     string    `json:"issuer"`
+	Repository string    `json:"repository"`
+	Ref        string    `json:"ref,omitempty"`
+	Workflow   string    `json:"workflow,omitempty"`
+	CreatedBy  string    `json:"createdBy,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// CreatePolicy adds a trust policy to a project
+func (h *CITrustPolicyHandler) CreatePolicy(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if !h.projectExists(c, projectID) {
+		return
+	}
+
+	var req CreateCITrustPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	policy, err := h.federationService.CreatePolicy(c.Request.Context(), projectID, &authDomain.CITrustPolicy{
+		Issuer:     req.Issuer,
+		Repository: req.Repository,
+		Ref:        req.Ref,
+		Workflow:   req.Workflow,
+	}, c.GetString("user_id"))
+	if err != nil {
+		h.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.respondWithJSON(c, http.StatusCreated, h.convertToResponse(policy))
+}
+
+// GetPolicies lists a project's trust policies
+func (h *CITrustPolicyHandler) GetPolicies(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if !h.projectExists(c, projectID) {
+		return
+	}
+
+	policies, err := h.federationService.ListPolicies(c.Request.Context(), projectID)
+	if err != nil {
+		h.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	responses := make([]CITrustPolicyResponse, len(policies))
+	for i, policy := range policies {
+		responses[i] = h.convertToResponse(policy)
+	}
+
+	h.respondWithJSON(c, http.StatusOK, responses)
+}
+
+// DeletePolicy removes a trust policy; CI jobs it admitted can no longer upload
+func (h *CITrustPolicyHandler) DeletePolicy(c *gin.Context) {
+	if err := h.federationService.DeletePolicy(c.Request.Context(), c.Param("projectId"), c.Param("policyId")); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, authDomain.ErrCITrustPolicyNotFound) {
+			status = http.StatusNotFound
+		}
+		h.ErrorResponse(c, status, err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RegisterRoutes registers CI trust policy routes
+func (h *CITrustPolicyHandler) RegisterRoutes(managerGroup *gin.RouterGroup) {
+	policies := managerGroup.Group("/projects/:projectId/ci-trust-policies")
+	{
+		policies.GET("", h.GetPolicies)
+		policies.POST("", h.CreatePolicy)
+		policies.DELETE("/:policyId", h.DeletePolicy)
+	}
+}
+
+// projectExists responds with 404 if the project does not exist
+func (h *CITrustPolicyHandler) projectExists(c *gin.Context, projectID string) bool {
+	if _, err := h.projectService.GetProject(c.Request.Context(), projectsDomain.ProjectID(projectID)); err != nil {
+		h.ErrorResponse(c, http.StatusNotFound, "project not found")
+		return false
+	}
+	return true
+}
+
+func (h *CITrustPolicyHandler) convertToResponse(policy *authDomain.CITrustPolicy) CITrustPolicyResponse {
+	return CITrustPolicyResponse{
+		ID:         policy.ID,
+		ProjectID:  policy.ProjectID,
+		Issuer:     policy.Issuer,
+		Repository: policy.Repository,
+		Ref:        policy.Ref,
+		Workflow:   policy.Workflow,
+		CreatedBy:  policy.CreatedBy,
+		CreatedAt:  policy.CreatedAt,
+	}
+}
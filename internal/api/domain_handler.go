@@ -10,6 +10,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	analyticsApp "github.com/guidewire-oss/fern-platform/internal/domains/analytics/application"
+	authApp "github.com/guidewire-oss/fern-platform/internal/domains/auth/application"
 	authDomain "github.com/guidewire-oss/fern-platform/internal/domains/auth/domain"
 	"github.com/guidewire-oss/fern-platform/internal/domains/auth/interfaces"
 	"github.com/guidewire-oss/fern-platform/internal/domains/integrations"
@@ -30,16 +31,29 @@ type DomainHandler struct {
 	flakyDetectionService *analyticsApp.FlakyDetectionService
 	jiraConnectionService *integrations.JiraConnectionService
 	authMiddleware        *interfaces.AuthMiddlewareAdapter
+	ingestionHandler      *IngestionHandler
+	attachmentHandler     *AttachmentHandler
+	quarantineHandler     *QuarantineHandler
+	ingestionTokenHandler *IngestionTokenHandler
+	accessTokenHandler    *AccessTokenHandler
+	ciTrustPolicyHandler  *CITrustPolicyHandler
 	logger                *logging.Logger
 }
 
 // NewDomainHandler creates a new domain handler
 func NewDomainHandler(
 	testingService *testingApp.TestRunService,
+	idempotencyService *testingApp.IdempotencyService,
+	ingestionQueueService *testingApp.IngestionQueueService,
+	attachmentService *testingApp.AttachmentService,
+	quarantineService *testingApp.QuarantineService,
 	projectService *projectsApp.ProjectService,
 	tagService *tagsApp.TagService,
 	flakyDetectionService *analyticsApp.FlakyDetectionService,
 	jiraConnectionService *integrations.JiraConnectionService,
+	ingestionTokenService *authApp.IngestionTokenService,
+	accessTokenService *authApp.AccessTokenService,
+	ciFederationService *authApp.CIFederationService,
 	authMiddleware *interfaces.AuthMiddlewareAdapter,
 	logger *logging.Logger,
 ) *DomainHandler {
@@ -50,6 +64,12 @@ func NewDomainHandler(
 		flakyDetectionService: flakyDetectionService,
 		jiraConnectionService: jiraConnectionService,
 		authMiddleware:        authMiddleware,
+		ingestionHandler:      NewIngestionHandler(testingService, projectService, idempotencyService, ingestionQueueService, logger),
+		attachmentHandler:     NewAttachmentHandler(attachmentService, testingService, logger),
+		quarantineHandler:     NewQuarantineHandler(quarantineService, logger),
+		ingestionTokenHandler: NewIngestionTokenHandler(NewBaseHandler(logger), ingestionTokenService, projectService),
+		accessTokenHandler:    NewAccessTokenHandler(NewBaseHandler(logger), accessTokenService),
+		ciTrustPolicyHandler:  NewCITrustPolicyHandler(NewBaseHandler(logger), ciFederationService, projectService),
 		logger:                logger,
 	}
 }
@@ -62,14 +82,21 @@ func (h *DomainHandler) RegisterRoutes(router *gin.Engine) {
 	// API v1 routes
 	apiV1 := router.Group("/api/v1")
 	{
-		// Public routes for test result submission
+		// Test result submission - requires a project ingestion token
 		// These are compatible with the legacy Fern Reporter API
-		apiV1.POST("/test-runs", h.recordTestRun)
-		apiV1.POST("/test-runs/start", h.startTestRun)
-		apiV1.POST("/test-runs/complete", h.completeTestRun)
-		apiV1.POST("/suite-runs", h.addSuiteRun)
-		apiV1.POST("/spec-runs", h.addSpecRun)
-		apiV1.PUT("/test-runs/:id", h.updateTestRun)
+		ingest := apiV1.Group("/")
+		ingest.Use(h.authMiddleware.RequireIngestionToken())
+		{
+			ingest.POST("/test-runs", h.recordTestRun)
+			ingest.POST("/test-runs/start", h.startTestRun)
+			ingest.POST("/test-runs/complete", h.completeTestRun)
+			ingest.POST("/suite-runs", h.addSuiteRun)
+			ingest.POST("/spec-runs", h.addSpecRun)
+			ingest.PUT("/test-runs/:id", h.updateTestRun)
+
+			// Native report ingestion (JUnit XML, ...)
+			h.ingestionHandler.RegisterRoutes(ingest)
+		}
 
 		// Protected routes - require authentication
 		protected := apiV1.Group("/")
@@ -78,6 +105,7 @@ func (h *DomainHandler) RegisterRoutes(router *gin.Engine) {
 			// Test runs
 			protected.GET("/test-runs", h.getTestRuns)
 			protected.GET("/test-runs/:id", h.getTestRun)
+			protected.GET("/test-runs/:id/export", NewTestRunHandler(h.testingService, h.logger).exportTestRun)
 			protected.GET("/test-runs/by-run-id/:id", h.getTestRunByRunId)
 			protected.DELETE("/test-runs/:id", h.deleteTestRun)
 
@@ -88,10 +116,22 @@ func (h *DomainHandler) RegisterRoutes(router *gin.Engine) {
 			// Specs
 			protected.GET("/test-runs/:id/suite-runs/:suiteId/spec-runs", h.getSpecRuns)
 			protected.GET("/test-runs/:id/suite-runs/:suiteId/spec-runs/:specId", h.getSpecRun)
+			protected.GET("/spec-runs/:id/output", NewTestRunHandler(h.testingService, h.logger).getSpecOutput)
+			protected.GET("/projects/:projectId/spec-output/search", NewTestRunHandler(h.testingService, h.logger).searchSpecOutput)
+
+			// Tests and their history across runs
+			protected.GET("/projects/:projectId/tests", NewTestRunHandler(h.testingService, h.logger).listTestCases)
+			protected.GET("/tests/:testId", NewTestRunHandler(h.testingService, h.logger).getTestCase)
+			protected.GET("/tests/:testId/history", NewTestRunHandler(h.testingService, h.logger).getTestCaseHistory)
+
+			// Flaky test detection
+			protected.GET("/projects/:projectId/flaky-tests", NewFlakyTestHandler(h.flakyDetectionService, h.logger).listFlakyTests)
+			protected.GET("/projects/:projectId/flaky-tests/evaluation", NewFlakyTestHandler(h.flakyDetectionService, h.logger).evaluateStrategies)
+			protected.GET("/projects/:projectId/flaky-tests/trends", NewFlakyTestHandler(h.flakyDetectionService, h.logger).getTrends)
 
 			// Projects
 			protected.GET("/projects", h.getProjects)
-			protected.GET("/projects/:id", h.getProject)
+			protected.GET("/projects/:projectId", h.getProject)
 			protected.GET("/projects/by-project-id/:projectId", h.getProjectByProjectId)
 
 			// Manager-only routes
@@ -100,16 +140,23 @@ func (h *DomainHandler) RegisterRoutes(router *gin.Engine) {
 			{
 				// Project management
 				managerRoutes.POST("/projects", h.createProject)
-				managerRoutes.PUT("/projects/:id", h.updateProject)
-				managerRoutes.DELETE("/projects/:id", h.deleteProject)
+				managerRoutes.PUT("/projects/:projectId", h.updateProject)
+				managerRoutes.DELETE("/projects/:projectId", h.deleteProject)
 
 				// JIRA connections
-				managerRoutes.GET("/projects/:id/jira-connections", h.getJiraConnections)
-				managerRoutes.POST("/projects/:id/jira-connections", h.createJiraConnection)
+				managerRoutes.GET("/projects/:projectId/jira-connections", h.getJiraConnections)
+				managerRoutes.POST("/projects/:projectId/jira-connections", h.createJiraConnection)
 				managerRoutes.PUT("/jira-connections/:connectionId", h.updateJiraConnection)
 				managerRoutes.PUT("/jira-connections/:connectionId/credentials", h.updateJiraCredentials)
 				managerRoutes.POST("/jira-connections/:connectionId/test", h.testJiraConnection)
 				managerRoutes.DELETE("/jira-connections/:connectionId", h.deleteJiraConnection)
+
+				// Ingestion tokens and CI OIDC trust policies
+				h.ingestionTokenHandler.RegisterRoutes(managerRoutes)
+				h.ciTrustPolicyHandler.RegisterRoutes(managerRoutes)
+
+				// Test quarantines, which CI fetches with its ingestion token
+				h.quarantineHandler.RegisterRoutes(ingest, protected, managerRoutes)
 			}
 
 			// Tags
@@ -120,6 +167,19 @@ func (h *DomainHandler) RegisterRoutes(router *gin.Engine) {
 			protected.GET("/flaky-tests", h.getFlakyTests)
 			protected.POST("/flaky-tests/:id/resolve", h.resolveFlakyTest)
 			protected.POST("/flaky-tests/:id/ignore", h.ignoreFlakyTest)
+
+			// Test attachments
+			h.attachmentHandler.RegisterRoutes(apiV1, ingest, protected)
+
+			// Admin-only routes
+			adminRoutes := protected.Group("/admin")
+			adminRoutes.Use(h.requireAdminRole())
+			adminRoutes.POST("/test-runs/backfill-legacy-suites", NewTestRunHandler(h.testingService, h.logger).backfillLegacySuiteRuns)
+			adminRoutes.POST("/tests/backfill", NewTestRunHandler(h.testingService, h.logger).backfillTestCases)
+			adminRoutes.POST("/projects/:projectId/flaky-tests/backfill", NewFlakyTestHandler(h.flakyDetectionService, h.logger).backfillTrends)
+
+			// Personal access tokens and service accounts
+			h.accessTokenHandler.RegisterRoutes(protected, adminRoutes)
 		}
 	}
 
@@ -173,6 +233,33 @@ func (h *DomainHandler) requireManagerRole() gin.HandlerFunc {
 	}
 }
 
+// requireAdminRole returns middleware that checks for admin role
+func (h *DomainHandler) requireAdminRole() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, exists := c.Get("user")
+		if !exists || user == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			c.Abort()
+			return
+		}
+
+		authUser, ok := user.(*authDomain.User)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user data"})
+			c.Abort()
+			return
+		}
+
+		if !authUser.IsAdmin() {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Admin role required"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // Health check handler
 func (h *DomainHandler) healthCheck(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
@@ -197,6 +284,9 @@ func (h *DomainHandler) recordTestRun(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if !requireIngestionProject(c, req.ProjectID) {
+		return
+	}
 
 	// Generate a unique run ID if not provided
 	if req.RunID == "" {
@@ -245,6 +335,9 @@ func (h *DomainHandler) startTestRun(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if !requireIngestionProject(c, req.ProjectID) {
+		return
+	}
 
 	// Generate run ID if not provided
 	if req.RunID == "" {
@@ -303,6 +396,9 @@ func (h *DomainHandler) completeTestRun(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Test run not found"})
 		return
 	}
+	if !requireIngestionProject(c, testRun.ProjectID) {
+		return
+	}
 
 	// Complete the test run using the internal ID
 	if err := h.testingService.CompleteTestRun(c.Request.Context(), testRun.ID, req.Status); err != nil {
@@ -337,6 +433,9 @@ func (h *DomainHandler) addSuiteRun(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Test run not found"})
 		return
 	}
+	if !requireIngestionProject(c, testRun.ProjectID) {
+		return
+	}
 
 	// Create suite run
 	suiteRun := &testingDomain.SuiteRun{
@@ -375,17 +474,20 @@ func (h *DomainHandler) addSuiteRun(c *gin.Context) {
 
 func (h *DomainHandler) addSpecRun(c *gin.Context) {
 	var req struct {
-		SuiteRunID   uint       `json:"suiteRunId" binding:"required"`
-		SpecName     string     `json:"specName" binding:"required"`
-		Status       string     `json:"status"`
-		StartTime    *time.Time `json:"startTime"`
-		EndTime      *time.Time `json:"endTime"`
-		Duration     int64      `json:"duration"`
-		ErrorMessage string     `json:"errorMessage"`
-		StackTrace   string     `json:"stackTrace"`
-		Stdout       string     `json:"stdout"`
-		Stderr       string     `json:"stderr"`
-		Retries      int        `json:"retries"`
+		SuiteRunID   uint                           `json:"suiteRunId" binding:"required"`
+		SpecName     string                         `json:"specName" binding:"required"`
+		Status       string                         `json:"status"`
+		StartTime    *time.Time                     `json:"startTime"`
+		EndTime      *time.Time                     `json:"endTime"`
+		Duration     int64                          `json:"duration"`
+		ErrorMessage string                         `json:"errorMessage"`
+		StackTrace   string                         `json:"stackTrace"`
+		Stdout       string                         `json:"stdout"`
+		Stderr       string                         `json:"stderr"`
+		Retries      int                            `json:"retries"`
+		Attempts     []testingApp.SpecAttemptReport `json:"attempts"`
+		ParentName   string                         `json:"parentName"`
+		Parameters   map[string]string              `json:"parameters"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -393,6 +495,21 @@ func (h *DomainHandler) addSpecRun(c *gin.Context) {
 		return
 	}
 
+	// Check the suite's run belongs to the token's project
+	suiteRun, err := h.testingService.GetSuiteRun(c.Request.Context(), req.SuiteRunID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Suite run not found"})
+		return
+	}
+	testRun, err := h.testingService.GetTestRun(c.Request.Context(), suiteRun.TestRunID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Test run not found"})
+		return
+	}
+	if !requireIngestionProject(c, testRun.ProjectID) {
+		return
+	}
+
 	// Create spec run
 	specRun := &testingDomain.SpecRun{
 		SuiteRunID:     req.SuiteRunID,
@@ -402,6 +519,10 @@ func (h *DomainHandler) addSpecRun(c *gin.Context) {
 		ErrorMessage:   req.ErrorMessage,
 		StackTrace:     req.StackTrace,
 		RetryCount:     req.Retries,
+		ParentName:     req.ParentName,
+		Parameters:     req.Parameters,
+		Output:         testingDomain.NewSpecOutput(req.Stdout, req.Stderr),
+		Attempts:       testingApp.ToSpecAttempts(req.Attempts),
 	}
 
 	if req.StartTime != nil {
@@ -414,7 +535,7 @@ func (h *DomainHandler) addSpecRun(c *gin.Context) {
 		specRun.Duration = time.Duration(req.Duration)
 	}
 
-	err := h.testingService.AddSpecRun(c.Request.Context(), req.SuiteRunID, specRun)
+	err = h.testingService.AddSpecRun(c.Request.Context(), req.SuiteRunID, specRun)
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -428,7 +549,38 @@ func (h *DomainHandler) addSpecRun(c *gin.Context) {
 }
 
 func (h *DomainHandler) updateTestRun(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "Update test run not yet implemented"})
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid test run ID"})
+		return
+	}
+
+	var req struct {
+		Status  string     `json:"status" binding:"required"`
+		EndTime *time.Time `json:"endTime"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	testRun, err := h.testingService.GetTestRun(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Test run not found"})
+		return
+	}
+	if !requireIngestionProject(c, testRun.ProjectID) {
+		return
+	}
+
+	// A run that moves to a terminal status is announced as completed by the service
+	testRun, err = h.testingService.UpdateTestRunStatus(c.Request.Context(), testRun.ID, req.Status, req.EndTime)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.convertTestRunToAPI(testRun))
 }
 
 func (h *DomainHandler) getTestRuns(c *gin.Context) {
@@ -598,6 +750,9 @@ func (h *DomainHandler) getTestRun(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Test run not found"})
 		return
 	}
+	if !interfaces.CheckProjectScope(c, testRun.ProjectID) {
+		return
+	}
 
 	c.JSON(http.StatusOK, h.convertTestRunToAPI(testRun))
 }
@@ -652,9 +807,16 @@ func (h *DomainHandler) getTag(c *gin.Context) {
 	c.JSON(http.StatusNotImplemented, gin.H{"error": "Get tag not yet implemented"})
 }
 
+// getFlakyTests handles GET /api/v1/flaky-tests?projectId=...; prefer
+// /api/v1/projects/:projectId/flaky-tests
 func (h *DomainHandler) getFlakyTests(c *gin.Context) {
-	// This method is deprecated - use domain_handler_v2.go
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "This endpoint is deprecated"})
+	projectID := c.Query("projectId")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "projectId is required"})
+		return
+	}
+
+	NewFlakyTestHandler(h.flakyDetectionService, h.logger).respondFlakyTests(c, projectID)
 }
 
 func (h *DomainHandler) resolveFlakyTest(c *gin.Context) {
@@ -685,6 +847,8 @@ func (h *DomainHandler) convertTestRunToAPI(tr *testingDomain.TestRun) gin.H {
 		"skippedTests": tr.SkippedTests,
 		"environment":  tr.Environment,
 		"metadata":     tr.Metadata,
+		// Set while the run's results are streamed in
+		"lastHeartbeatAt": tr.LastHeartbeatAt,
 	}
 }
 
@@ -705,15 +869,10 @@ func (h *DomainHandler) convertProjectToAPI(p *projectsDomain.Project) gin.H {
 	}
 }
 
-// convertFlakyTestToAPI is deprecated - flaky test types have changed
-// func (h *DomainHandler) convertFlakyTestToAPI(ft *testingDomain.FlakyTest) gin.H {
-// 	return gin.H{}
-// }
-
 // JIRA Connection Handlers
 
 func (h *DomainHandler) getJiraConnections(c *gin.Context) {
-	projectID := c.Param("id")
+	projectID := c.Param("projectId")
 	
 	connections, err := h.jiraConnectionService.GetProjectConnections(c.Request.Context(), projectID)
 	if err != nil {
@@ -745,7 +904,7 @@ func (h *DomainHandler) getJiraConnections(c *gin.Context) {
 }
 
 func (h *DomainHandler) createJiraConnection(c *gin.Context) {
-	projectID := c.Param("id")
+	projectID := c.Param("projectId")
 
 	var req struct {
 		Name               string `json:"connectionName" binding:"required"`
@@ -99,7 +99,7 @@ var _ = Describe("DomainHandler Integration Tests", func() {
 		It("should return healthy status", func() {
 			// Create a handler - health check doesn't require services
 			// This is one of the few endpoints that works with nil services
-			handler := api.NewDomainHandler(nil, nil, nil, nil, nil, nil, logger)
+			handler := api.NewDomainHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, logger)
 			
 			// Register routes
 			handler.RegisterRoutes(router)
@@ -133,7 +133,7 @@ var _ = Describe("DomainHandler Integration Tests", func() {
 	
 	Describe("Route Registration", func() {
 		It("should register all expected routes", func() {
-			handler := api.NewDomainHandler(nil, nil, nil, nil, nil, nil, logger)
+			handler := api.NewDomainHandler(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, logger)
 			handler.RegisterRoutes(router)
 			
 			routes := router.Routes()
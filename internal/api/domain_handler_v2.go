@@ -4,6 +4,7 @@ package api
 import (
 	"github.com/gin-gonic/gin"
 	analyticsApp "github.com/guidewire-oss/fern-platform/internal/domains/analytics/application"
+	authApp "github.com/guidewire-oss/fern-platform/internal/domains/auth/application"
 	"github.com/guidewire-oss/fern-platform/internal/domains/auth/interfaces"
 	"github.com/guidewire-oss/fern-platform/internal/domains/integrations"
 	projectsApp "github.com/guidewire-oss/fern-platform/internal/domains/projects/application"
@@ -23,6 +24,13 @@ type DomainHandlerV2 struct {
 	systemHandler         *SystemHandler
 	fernLegacyHandler     *FernLegacyHandler
 	jiraConnectionHandler *JiraConnectionHandler
+	ingestionHandler      *IngestionHandler
+	attachmentHandler     *AttachmentHandler
+	quarantineHandler     *QuarantineHandler
+	ingestionTokenHandler *IngestionTokenHandler
+	accessTokenHandler    *AccessTokenHandler
+	ciTrustPolicyHandler  *CITrustPolicyHandler
+	flakyTestHandler      *FlakyTestHandler
 
 	// Middleware
 	authMiddleware *interfaces.AuthMiddlewareAdapter
@@ -32,10 +40,17 @@ type DomainHandlerV2 struct {
 // NewDomainHandlerV2 creates a new domain-based API handler with split handlers
 func NewDomainHandlerV2(
 	testingService *application.TestRunService,
+	idempotencyService *application.IdempotencyService,
+	ingestionQueueService *application.IngestionQueueService,
+	attachmentService *application.AttachmentService,
+	quarantineService *application.QuarantineService,
 	projectService *projectsApp.ProjectService,
 	tagService *tagsApp.TagService,
 	flakyDetectionService *analyticsApp.FlakyDetectionService,
 	jiraConnectionService *integrations.JiraConnectionService,
+	ingestionTokenService *authApp.IngestionTokenService,
+	accessTokenService *authApp.AccessTokenService,
+	ciFederationService *authApp.CIFederationService,
 	authMiddleware *interfaces.AuthMiddlewareAdapter,
 	logger *logging.Logger,
 ) *DomainHandlerV2 {
@@ -47,8 +62,15 @@ func NewDomainHandlerV2(
 		projectHandler:        NewProjectHandler(projectService, logger),
 		tagHandler:            NewTagHandler(tagService, logger),
 		systemHandler:         NewSystemHandler(logger),
-		fernLegacyHandler:     NewFernLegacyHandler(testingService, projectService, logger),
+		fernLegacyHandler:     NewFernLegacyHandler(testingService, projectService, idempotencyService, logger),
 		jiraConnectionHandler: NewJiraConnectionHandler(baseHandler, jiraConnectionService, projectService),
+		ingestionHandler:      NewIngestionHandler(testingService, projectService, idempotencyService, ingestionQueueService, logger),
+		attachmentHandler:     NewAttachmentHandler(attachmentService, testingService, logger),
+		quarantineHandler:     NewQuarantineHandler(quarantineService, logger),
+		ingestionTokenHandler: NewIngestionTokenHandler(baseHandler, ingestionTokenService, projectService),
+		accessTokenHandler:    NewAccessTokenHandler(baseHandler, accessTokenService),
+		ciTrustPolicyHandler:  NewCITrustPolicyHandler(baseHandler, ciFederationService, projectService),
+		flakyTestHandler:      NewFlakyTestHandler(flakyDetectionService, logger),
 		authMiddleware:        authMiddleware,
 		logger:                logger,
 	}
@@ -82,6 +104,11 @@ func (h *DomainHandlerV2) RegisterRoutes(router *gin.Engine) {
 	publicGroup := v1.Group("")
 	h.healthHandler.RegisterRoutes(publicGroup)
 
+	// Ingestion routes (require a project ingestion token)
+	ingestGroup := v1.Group("")
+	ingestGroup.Use(h.authMiddleware.RequireIngestionToken())
+	h.ingestionHandler.RegisterRoutes(ingestGroup)
+
 	// User routes (require authentication)
 	userGroup := v1.Group("")
 	userGroup.Use(h.authMiddleware.RequireAuth())
@@ -97,16 +124,28 @@ func (h *DomainHandlerV2) RegisterRoutes(router *gin.Engine) {
 	// Register all handler routes
 	h.authHandler.RegisterRoutes(router, authGroup, userGroup, adminGroup)
 	h.testRunHandler.RegisterRoutes(userGroup, adminGroup)
+	h.attachmentHandler.RegisterRoutes(publicGroup, ingestGroup, userGroup)
 	h.projectHandler.RegisterRoutes(userGroup, managerGroup, adminGroup)
 	h.tagHandler.RegisterRoutes(userGroup, adminGroup)
+	h.flakyTestHandler.RegisterRoutes(userGroup, adminGroup)
+	h.quarantineHandler.RegisterRoutes(ingestGroup, userGroup, managerGroup)
 	h.systemHandler.RegisterRoutes(adminGroup)
 	
 	// Register JIRA connection routes
 	h.registerJiraConnectionRoutes(managerGroup)
 
+	// Register ingestion token routes - managers issue tokens for their projects' CI
+	h.ingestionTokenHandler.RegisterRoutes(managerGroup)
+
+	// Register CI trust policy routes - managers let their projects' pipelines upload with OIDC tokens
+	h.ciTrustPolicyHandler.RegisterRoutes(managerGroup)
+
+	// Register access token routes - users issue their own tokens, admins manage service accounts
+	h.accessTokenHandler.RegisterRoutes(userGroup, adminGroup)
+
 	// Legacy fern-reporter compatible API endpoints
 	apiGroup := router.Group("/api")
-	h.fernLegacyHandler.RegisterRoutes(apiGroup)
+	h.fernLegacyHandler.RegisterRoutes(apiGroup, h.authMiddleware.RequireIngestionToken())
 
 	// Log route registration
 	h.logger.Info("All routes registered successfully with split handlers")
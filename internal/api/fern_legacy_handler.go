@@ -20,20 +20,23 @@ import (
 // FernLegacyHandler handles legacy fern-reporter compatible endpoints
 type FernLegacyHandler struct {
 	*BaseHandler
-	testingService *application.TestRunService
-	projectService *projectsApp.ProjectService
+	testingService     *application.TestRunService
+	projectService     *projectsApp.ProjectService
+	idempotencyService *application.IdempotencyService
 }
 
 // NewFernLegacyHandler creates a new fern legacy handler
 func NewFernLegacyHandler(
 	testingService *application.TestRunService,
 	projectService *projectsApp.ProjectService,
+	idempotencyService *application.IdempotencyService,
 	logger *logging.Logger,
 ) *FernLegacyHandler {
 	return &FernLegacyHandler{
-		BaseHandler:    NewBaseHandler(logger),
-		testingService: testingService,
-		projectService: projectService,
+		BaseHandler:        NewBaseHandler(logger),
+		testingService:     testingService,
+		projectService:     projectService,
+		idempotencyService: idempotencyService,
 	}
 }
 
@@ -150,37 +153,19 @@ func (h *FernLegacyHandler) createFernTestReport(c *gin.Context) {
 
 	// Parse the structured input
 	var input struct {
-		ID                uint64 `json:"id"`
-		TestProjectName   string `json:"test_project_name"`
-		TestProjectID     string `json:"test_project_id"`
-		TestSeed          uint64 `json:"test_seed"`
-		StartTime         string `json:"start_time"`
-		EndTime           string `json:"end_time"`
-		GitBranch         string `json:"git_branch"`
-		GitSha            string `json:"git_sha"`
-		BuildTriggerActor string `json:"build_trigger_actor"`
-		BuildUrl          string `json:"build_url"`
-		ClientType        string `json:"client_type"`
-		SuiteRuns         []struct {
-			ID        uint64 `json:"id"`
-			TestRunID uint64 `json:"test_run_id"`
-			SuiteName string `json:"suite_name"`
-			StartTime string `json:"start_time"`
-			EndTime   string `json:"end_time"`
-			SpecRuns  []struct {
-				ID              uint64 `json:"id"`
-				SuiteID         uint64 `json:"suite_id"`
-				SpecDescription string `json:"spec_description"`
-				Status          string `json:"status"`
-				Message         string `json:"message"`
-				Tags            []struct {
-					ID   uint64 `json:"id"`
-					Name string `json:"name"`
-				} `json:"tags"`
-				StartTime string `json:"start_time"`
-				EndTime   string `json:"end_time"`
-			} `json:"spec_runs"`
-		} `json:"suite_runs"`
+		ID                uint64                     `json:"id"`
+		RunID             string                     `json:"run_id"`
+		TestProjectName   string                     `json:"test_project_name"`
+		TestProjectID     string                     `json:"test_project_id"`
+		TestSeed          uint64                     `json:"test_seed"`
+		StartTime         string                     `json:"start_time"`
+		EndTime           string                     `json:"end_time"`
+		GitBranch         string                     `json:"git_branch"`
+		GitSha            string                     `json:"git_sha"`
+		BuildTriggerActor string                     `json:"build_trigger_actor"`
+		BuildUrl          string                     `json:"build_url"`
+		ClientType        string                     `json:"client_type"`
+		SuiteRuns         []application.FernSuiteRun `json:"suite_runs"`
 	}
 
 	if err := json.Unmarshal(bodyBytes, &input); err != nil {
@@ -203,6 +188,9 @@ func (h *FernLegacyHandler) createFernTestReport(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "test_project_id is required"})
 		return
 	}
+	if !requireIngestionProject(c, projectID) {
+		return
+	}
 
 	// Look up the project by ID from the project_details table
 	h.logger.Info("Looking up project", "project_id", projectID)
@@ -234,22 +222,6 @@ func (h *FernLegacyHandler) createFernTestReport(c *gin.Context) {
 		}
 	}
 
-	// Calculate test statistics from suite runs
-	var totalTests, passedTests, failedTests, skippedTests int
-	for _, suiteRun := range input.SuiteRuns {
-		for _, specRun := range suiteRun.SpecRuns {
-			totalTests++
-			switch specRun.Status {
-			case "passed":
-				passedTests++
-			case "failed":
-				failedTests++
-			case "skipped", "pending":
-				skippedTests++
-			}
-		}
-	}
-
 	// Use git information from fern-ginkgo-client
 	branch := input.GitBranch
 	if branch == "" {
@@ -258,70 +230,76 @@ func (h *FernLegacyHandler) createFernTestReport(c *gin.Context) {
 
 	commitSHA := input.GitSha
 
-	// Create run ID with test seed
-	runID := fmt.Sprintf("%s-run-%d", project.Name(), input.TestSeed)
-
-	// Check if test run already exists with this run_id
-	h.logger.Info("Checking if test run already exists", "run_id", runID)
-	existingTestRun, err := h.testingService.GetTestRunByRunID(c.Request.Context(), runID)
-
-	var testRun *domain.TestRun
-	if err != nil || existingTestRun == nil {
-		// Test run doesn't exist, create a new one
-		h.logger.Info("Test run does not exist, creating new one", "run_id", runID)
-
-		testRun = &domain.TestRun{
-			ProjectID:    string(project.ProjectID()),
-			RunID:        runID,
-			Name:         project.Name(), // Use project name as test run name
-			GitBranch:    branch,
-			GitCommit:    commitSHA,
-			Environment:  "test",
-			Source:       input.ClientType,
-			Status:       "completed",
-			StartTime:    startTime,
-			EndTime:      endTime,
-			TotalTests:   totalTests,
-			PassedTests:  passedTests,
-			FailedTests:  failedTests,
-			SkippedTests: skippedTests,
-		}
+	// A report that names its run, or is sent with an Idempotency-Key, replaces the run
+	// recorded under that run ID. Otherwise the run ID comes from the project and test
+	// seed, and a report resubmitted with the same seed returns the run recorded first.
+	runID := input.RunID
+	replace := runID != "" || c.GetHeader(idempotencyKeyHeader) != ""
+	if runID == "" {
+		runID = fmt.Sprintf("%s-run-%d", project.ProjectID(), input.TestSeed)
+	}
 
-		// Store additional metadata
-		if input.BuildUrl != "" || input.BuildTriggerActor != "" {
-			testRun.Metadata = map[string]interface{}{
-				"test_seed":           input.TestSeed,
-				"build_url":           input.BuildUrl,
-				"build_trigger_actor": input.BuildTriggerActor,
-				"suite_runs":          input.SuiteRuns,
-			}
+	if !replace {
+		h.logger.Info("Checking if test run already exists", "run_id", runID)
+		existingTestRun, err := h.testingService.GetTestRunByRunID(c.Request.Context(), runID)
+		if err == nil && existingTestRun != nil {
+			h.logger.Info("Test run already exists", "run_id", runID, "test_run_id", existingTestRun.ID)
+			c.JSON(http.StatusCreated, convertTestRunToFernResponse(existingTestRun))
+			return
 		}
+	}
 
-		h.logger.Info("Creating test run",
-			"run_id", testRun.RunID,
-			"project_id", testRun.ProjectID,
-			"name", testRun.Name,
-			"total_tests", testRun.TotalTests,
-			"status", testRun.Status)
+	testRun := &domain.TestRun{
+		ProjectID:   string(project.ProjectID()),
+		RunID:       runID,
+		Name:        project.Name(), // Use project name as test run name
+		GitBranch:   branch,
+		GitCommit:   commitSHA,
+		Environment: "test",
+		Source:      input.ClientType,
+		Status:      "completed",
+		StartTime:   startTime,
+		EndTime:     endTime,
+	}
 
-		if err := h.testingService.CreateTestRun(c.Request.Context(), testRun); err != nil {
-			h.logger.WithError(err).Error("Failed to create test run")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
+	// Store additional metadata
+	if input.BuildUrl != "" || input.BuildTriggerActor != "" {
+		testRun.Metadata = map[string]interface{}{
+			"test_seed":           input.TestSeed,
+			"build_url":           input.BuildUrl,
+			"build_trigger_actor": input.BuildTriggerActor,
 		}
-	} else {
-		// Test run already exists
-		testRun = existingTestRun
-		h.logger.Info("Test run already exists", "run_id", runID, "test_run_id", testRun.ID)
 	}
 
-	h.logger.Info("Test run created/found successfully",
+	h.logger.Info("Recording test run",
+		"run_id", testRun.RunID,
+		"project_id", testRun.ProjectID,
+		"name", testRun.Name,
+		"suite_runs_count", len(input.SuiteRuns),
+		"status", testRun.Status)
+
+	// Persist the run with its suite/spec hierarchy and spec labels as platform tags
+	if err := h.testingService.IngestFernTestRun(c.Request.Context(), testRun, input.SuiteRuns); err != nil {
+		h.logger.WithError(err).Error("Failed to create test run")
+		c.JSON(ingestionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Set(ingestedTestRunIDKey, testRun.ID)
+
+	h.logger.Info("Test run recorded successfully",
 		"test_run_id", testRun.ID,
 		"run_id", testRun.RunID,
 		"project_id", testRun.ProjectID)
 
-	// Return Fern-compatible response structure
-	response := gin.H{
+	response := convertTestRunToFernResponse(testRun)
+	h.logger.Info("Sending response", "response", response)
+	c.JSON(http.StatusCreated, response)
+}
+
+// convertTestRunToFernResponse converts a recorded test run to the Fern-compatible
+// response of a test report submission
+func convertTestRunToFernResponse(testRun *domain.TestRun) gin.H {
+	return gin.H{
 		"test_run_id": testRun.ID,
 		"run_id":      testRun.RunID,
 		"project_id":  testRun.ProjectID,
@@ -329,9 +307,6 @@ func (h *FernLegacyHandler) createFernTestReport(c *gin.Context) {
 		"status":      testRun.Status,
 		"created_at":  testRun.StartTime.Format(time.RFC3339),
 	}
-
-	h.logger.Info("Sending response", "response", response)
-	c.JSON(http.StatusCreated, response)
 }
 
 // listFernTestReports handles GET /api/reports/testruns
@@ -386,7 +361,7 @@ func (h *FernLegacyHandler) convertTestRunToLegacyAPI(tr *domain.TestRun) gin.H
 	if tr.EndTime != nil {
 		endTime = tr.EndTime.Format(time.RFC3339)
 	}
-	
+
 	return gin.H{
 		"uuid":         tr.RunID,
 		"project_uuid": tr.ProjectID,
@@ -407,18 +382,29 @@ func (h *FernLegacyHandler) convertTestRunToLegacyAPI(tr *domain.TestRun) gin.H
 	}
 }
 
-// RegisterRoutes registers legacy fern routes
-func (h *FernLegacyHandler) RegisterRoutes(apiGroup *gin.RouterGroup) {
+// idempotent scopes Idempotency-Key handling to the project named in the report body
+func (h *FernLegacyHandler) idempotent(next gin.HandlerFunc) gin.HandlerFunc {
+	return withIdempotency(h.idempotencyService, h.logger, func(_ *gin.Context, body []byte) string {
+		var report struct {
+			TestProjectID string `json:"test_project_id"`
+		}
+		_ = json.Unmarshal(body, &report)
+		return report.TestProjectID
+	}, next)
+}
+
+// RegisterRoutes registers legacy fern routes. Report submissions are guarded by requireIngestionToken.
+func (h *FernLegacyHandler) RegisterRoutes(apiGroup *gin.RouterGroup, requireIngestionToken gin.HandlerFunc) {
 	// Project endpoints compatible with fern-ginkgo-client
 	apiGroup.POST("/project", h.createFernProject)
 	apiGroup.GET("/project/:uuid", h.getFernProject)
 	apiGroup.GET("/projects", h.listFernProjects)
 
 	// Test reports endpoints
-	apiGroup.POST("/reports/testrun", h.createFernTestReport)
+	apiGroup.POST("/reports/testrun", requireIngestionToken, h.idempotent(h.createFernTestReport))
 	apiGroup.GET("/reports/testruns", h.listFernTestReports)
 	apiGroup.GET("/reports/testrun/:uuid", h.getFernTestReport)
 
 	// Additional endpoints that fern-ginkgo-client might expect
-	apiGroup.POST("/testrun", h.createFernTestReport) // Alias for test run creation
-}
\ No newline at end of file
+	apiGroup.POST("/testrun", requireIngestionToken, h.idempotent(h.createFernTestReport)) // Alias for test run creation
+}
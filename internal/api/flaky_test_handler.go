@@ -0,0 +1,188 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	analyticsApp "github.com/guidewire-oss/fern-platform/internal/domains/analytics/application"
+	analyticsDomain "github.com/guidewire-oss/fern-platform/internal/domains/analytics/domain"
+	"github.com/guidewire-oss/fern-platform/pkg/logging"
+)
+
+const (
+	// defaultEvaluationDays is the holdout period scoring strategies are evaluated on by default
+	defaultEvaluationDays = 7
+
+	// defaultTrendDays is how many days of flaky test trends are returned by default
+	defaultTrendDays = 30
+
+	// defaultBackfillDays is how many days of flaky test trends are rebuilt by default
+	defaultBackfillDays = 90
+)
+
+// FlakyTestHandler handles flaky test detection endpoints
+type FlakyTestHandler struct {
+	*BaseHandler
+	flakyDetectionService *analyticsApp.FlakyDetectionService
+}
+
+// NewFlakyTestHandler creates a new flaky test handler
+func NewFlakyTestHandler(flakyDetectionService *analyticsApp.FlakyDetectionService, logger *logging.Logger) *FlakyTestHandler {
+	return &FlakyTestHandler{
+		BaseHandler:           NewBaseHandler(logger),
+		flakyDetectionService: flakyDetectionService,
+	}
+}
+
+// listFlakyTests handles GET /api/v1/projects/:projectId/flaky-tests?status=active
+func (h *FlakyTestHandler) listFlakyTests(c *gin.Context) {
+	h.respondFlakyTests(c, c.Param("projectId"))
+}
+
+// respondFlakyTests answers with the flaky tests of a project that have the status in the
+// status query parameter, active ones by default
+func (h *FlakyTestHandler) respondFlakyTests(c *gin.Context, projectID string) {
+	status := analyticsDomain.StatusActive
+	if value := c.Query("status"); value != "" {
+		status = analyticsDomain.FlakyTestStatus(value)
+		if !status.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "status must be active, resolved or ignored"})
+			return
+		}
+	}
+
+	flakyTests, err := h.flakyDetectionService.ListFlakyTests(c.Request.Context(), projectID, status)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list flaky tests")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]gin.H, len(flakyTests))
+	for i, flakyTest := range flakyTests {
+		response[i] = convertFlakyTestToAPI(flakyTest)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"projectId":  projectID,
+		"flakyTests": response,
+	})
+}
+
+// evaluateStrategies handles GET /api/v1/projects/:projectId/flaky-tests/evaluation?days=7
+func (h *FlakyTestHandler) evaluateStrategies(c *gin.Context) {
+	days, ok := queryDays(c, defaultEvaluationDays)
+	if !ok {
+		return
+	}
+
+	evaluation, err := h.flakyDetectionService.EvaluateStrategies(c.Request.Context(), c.Param("projectId"), time.Duration(days)*24*time.Hour)
+	if errors.Is(err, analyticsDomain.ErrInvalidSettings) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to evaluate flakiness scoring strategies")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, evaluation)
+}
+
+// getTrends handles GET /api/v1/projects/:projectId/flaky-tests/trends?days=30
+func (h *FlakyTestHandler) getTrends(c *gin.Context) {
+	days, ok := queryDays(c, defaultTrendDays)
+	if !ok {
+		return
+	}
+
+	projectID := c.Param("projectId")
+	trends, err := h.flakyDetectionService.GetFlakyTestTrends(c.Request.Context(), projectID, time.Duration(days)*24*time.Hour)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get flaky test trends")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"projectId": projectID,
+		"days":      days,
+		"trends":    trends,
+	})
+}
+
+// backfillTrends handles POST /api/v1/admin/projects/:projectId/flaky-tests/backfill?days=90
+func (h *FlakyTestHandler) backfillTrends(c *gin.Context) {
+	days, ok := queryDays(c, defaultBackfillDays)
+	if !ok {
+		return
+	}
+
+	rebuilt, err := h.flakyDetectionService.BackfillFlakyTestTrends(c.Request.Context(), c.Param("projectId"), days)
+	if errors.Is(err, analyticsDomain.ErrInvalidSettings) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to backfill flaky test trends")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"days": rebuilt})
+}
+
+// RegisterRoutes registers flaky test routes
+func (h *FlakyTestHandler) RegisterRoutes(userGroup, adminGroup *gin.RouterGroup) {
+	userGroup.GET("/projects/:projectId/flaky-tests", h.listFlakyTests)
+	userGroup.GET("/projects/:projectId/flaky-tests/evaluation", h.evaluateStrategies)
+	userGroup.GET("/projects/:projectId/flaky-tests/trends", h.getTrends)
+
+	adminGroup.POST("/projects/:projectId/flaky-tests/backfill", h.backfillTrends)
+}
+
+// queryDays reads the days query parameter, answering 400 Bad Request when it is not a
+// positive number of at most analyticsApp.MaxHistoryDays
+func queryDays(c *gin.Context, defaultDays int) (int, bool) {
+	value := c.Query("days")
+	if value == "" {
+		return defaultDays, true
+	}
+
+	days, err := strconv.Atoi(value)
+	if err != nil || days <= 0 || days > analyticsApp.MaxHistoryDays {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("days must be a positive number of at most %d", analyticsApp.MaxHistoryDays)})
+		return 0, false
+	}
+	return days, true
+}
+
+// convertFlakyTestToAPI converts a flaky test to its API response, with the flips showing it
+// both passed and failed on the same code
+func convertFlakyTestToAPI(ft *analyticsDomain.FlakyTest) gin.H {
+	flips := ft.Metadata.Flips
+	if flips == nil {
+		flips = []analyticsDomain.FlipEvidence{}
+	}
+
+	return gin.H{
+		"id":               ft.TestID,
+		"projectId":        ft.ProjectID,
+		"testName":         ft.TestName,
+		"suiteName":        ft.SuiteName,
+		"flakeRate":        ft.FlakeScore,
+		"totalExecutions":  ft.TotalRuns,
+		"flakyExecutions":  ft.FailureCount,
+		"status":           string(ft.Status),
+		"severity":         ft.Severity(),
+		"lastErrorMessage": ft.LastErrorMessage(),
+		"firstSeenAt":      ft.FirstSeen,
+		"lastSeenAt":       ft.LastSeen,
+		"flips":            flips,
+	}
+}
@@ -0,0 +1,183 @@
+package api_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/guidewire-oss/fern-platform/internal/api"
+	analyticsApp "github.com/guidewire-oss/fern-platform/internal/domains/analytics/application"
+	analyticsDomain "github.com/guidewire-oss/fern-platform/internal/domains/analytics/domain"
+	"github.com/guidewire-oss/fern-platform/internal/testhelpers"
+	"github.com/guidewire-oss/fern-platform/pkg/config"
+	"github.com/guidewire-oss/fern-platform/pkg/logging"
+)
+
+var _ = Describe("FlakyTestHandler", Label("unit", "api", "analytics"), func() {
+	var (
+		mockRepo *testhelpers.MockFlakyDetectionRepository
+		router   *gin.Engine
+	)
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+
+		logger, err := logging.NewLogger(&config.LoggingConfig{Level: "error", Format: "json"})
+		Expect(err).NotTo(HaveOccurred())
+
+		mockRepo = new(testhelpers.MockFlakyDetectionRepository)
+		service := analyticsApp.NewFlakyDetectionService(mockRepo, analyticsDomain.DefaultFlakyTestDetectionConfig())
+
+		router = gin.New()
+		userGroup := router.Group("/api/v1")
+		api.NewFlakyTestHandler(service, logger).RegisterRoutes(userGroup, userGroup.Group("/admin"))
+	})
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	Describe("listing flaky tests", func() {
+		It("should return the active flaky tests of a project with their flips", func() {
+			seen := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+			mockRepo.On("FindFlakyTestsByProject", mock.Anything, "proj-1", analyticsDomain.StatusActive).Return([]*analyticsDomain.FlakyTest{
+				{
+					TestID:       "42",
+					ProjectID:    "proj-1",
+					TestName:     "should log in",
+					SuiteName:    "Login",
+					FirstSeen:    seen,
+					LastSeen:     seen,
+					TotalRuns:    10,
+					FailureCount: 3,
+					FlakeScore:   0.45,
+					Status:       analyticsDomain.StatusActive,
+					Metadata: analyticsDomain.FlakyTestMetadata{
+						FailurePatterns: []string{"timeout"},
+						Flips: []analyticsDomain.FlipEvidence{
+							{Commit: "abc", PassedRunIDs: []string{"run-2"}, FailedRunIDs: []string{"run-1"}},
+							{PassedRunIDs: []string{"run-3"}, FailedRunIDs: []string{"run-3"}, InRun: true},
+						},
+					},
+				},
+			}, nil)
+
+			w := get("/api/v1/projects/proj-1/flaky-tests")
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response struct {
+				ProjectID  string                   `json:"projectId"`
+				FlakyTests []map[string]interface{} `json:"flakyTests"`
+			}
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.ProjectID).To(Equal("proj-1"))
+			Expect(response.FlakyTests).To(HaveLen(1))
+
+			flakyTest := response.FlakyTests[0]
+			Expect(flakyTest["id"]).To(Equal("42"))
+			Expect(flakyTest["flakeRate"]).To(Equal(0.45))
+			Expect(flakyTest["severity"]).To(Equal("high"))
+			Expect(flakyTest["lastErrorMessage"]).To(Equal("timeout"))
+			Expect(flakyTest["flips"]).To(Equal([]interface{}{
+				map[string]interface{}{"commit": "abc", "passedRunIds": []interface{}{"run-2"}, "failedRunIds": []interface{}{"run-1"}, "inRun": false},
+				map[string]interface{}{"passedRunIds": []interface{}{"run-3"}, "failedRunIds": []interface{}{"run-3"}, "inRun": true},
+			}))
+		})
+
+		It("should return an empty list of flips for a flaky test without any", func() {
+			mockRepo.On("FindFlakyTestsByProject", mock.Anything, "proj-1", analyticsDomain.StatusResolved).Return([]*analyticsDomain.FlakyTest{
+				{TestID: "7", ProjectID: "proj-1", Status: analyticsDomain.StatusResolved},
+			}, nil)
+
+			w := get("/api/v1/projects/proj-1/flaky-tests?status=resolved")
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Body.String()).To(ContainSubstring(`"flips":[]`))
+		})
+
+		It("should reject an unknown status", func() {
+			w := get("/api/v1/projects/proj-1/flaky-tests?status=broken")
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+			mockRepo.AssertNotCalled(GinkgoT(), "FindFlakyTestsByProject", mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		It("should answer 500 when the flaky tests cannot be loaded", func() {
+			mockRepo.On("FindFlakyTestsByProject", mock.Anything, "proj-1", analyticsDomain.StatusActive).Return(nil, errors.New("connection refused"))
+
+			w := get("/api/v1/projects/proj-1/flaky-tests")
+
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Describe("trends", func() {
+		It("should return a count of flaky tests for every day of the period", func() {
+			today := analyticsDomain.TrendDate(time.Now())
+			mockRepo.On("GetFlakyTestTrends", mock.Anything, "proj-1", today.AddDate(0, 0, -1)).Return([]analyticsDomain.FlakyTestTrend{
+				{Date: today.AddDate(0, 0, -3), ActiveCount: 1},
+				{Date: today, ActiveCount: 2, NewCount: 1},
+			}, nil)
+
+			w := get("/api/v1/projects/proj-1/flaky-tests/trends?days=2")
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response struct {
+				ProjectID string                           `json:"projectId"`
+				Days      int                              `json:"days"`
+				Trends    []analyticsDomain.FlakyTestTrend `json:"trends"`
+			}
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.ProjectID).To(Equal("proj-1"))
+			Expect(response.Days).To(Equal(2))
+			Expect(response.Trends).To(Equal([]analyticsDomain.FlakyTestTrend{
+				{Date: today.AddDate(0, 0, -1), ActiveCount: 1},
+				{Date: today, ActiveCount: 2, NewCount: 1},
+			}))
+		})
+
+		It("should answer 500 when the trends cannot be loaded", func() {
+			mockRepo.On("GetFlakyTestTrends", mock.Anything, "proj-1", mock.Anything).Return(nil, errors.New("connection refused"))
+
+			w := get("/api/v1/projects/proj-1/flaky-tests/trends")
+
+			Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Describe("days of history", func() {
+		DescribeTable("rejecting days that are not a positive number of at most a year",
+			func(method, path string) {
+				w := httptest.NewRecorder()
+				req, _ := http.NewRequest(method, path, nil)
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+				Expect(w.Body.String()).To(ContainSubstring("days must be a positive number of at most 365"))
+			},
+			Entry("an evaluation over more than a year", http.MethodGet, "/api/v1/projects/proj-1/flaky-tests/evaluation?days=366"),
+			Entry("trends over more than a year", http.MethodGet, "/api/v1/projects/proj-1/flaky-tests/trends?days=100000"),
+			Entry("a backfill of more than a year", http.MethodPost, "/api/v1/admin/projects/proj-1/flaky-tests/backfill?days=366"),
+			Entry("no days", http.MethodGet, "/api/v1/projects/proj-1/flaky-tests/trends?days=0"),
+			Entry("days that are not a number", http.MethodGet, "/api/v1/projects/proj-1/flaky-tests/trends?days=week"),
+		)
+
+		It("should evaluate strategies over a year", func() {
+			mockRepo.On("GetRecentTests", mock.Anything, "proj-1", mock.Anything).Return([]analyticsDomain.TestIdentity{}, nil)
+
+			w := get("/api/v1/projects/proj-1/flaky-tests/evaluation?days=365")
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+	})
+})
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+	"github.com/guidewire-oss/fern-platform/pkg/logging"
+)
+
+const (
+	// idempotencyKeyHeader carries a client-chosen key that makes a submission safe to retry
+	idempotencyKeyHeader = "Idempotency-Key"
+
+	// idempotentReplayHeader marks responses replayed from an earlier request
+	idempotentReplayHeader = "Idempotent-Replayed"
+
+	// ingestedTestRunIDKey is set on the gin context by ingestion handlers after recording a run
+	ingestedTestRunIDKey = "ingested_test_run_id"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// idempotencyWriter tees the response body so that it can be stored for replays
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// withIdempotency wraps an ingestion handler so that requests sent with an Idempotency-Key header
+// run at most once per project. projectID extracts the owning project from the request and body.
+// Successful responses are stored and replayed for retries with the same payload; failed requests
+// release the key so that they can be retried. A key left claimed by a request that never got to
+// release it, e.g. because the server crashed, may be claimed by a retry once its lease runs out.
+func withIdempotency(
+	idempotencyService *application.IdempotencyService,
+	logger *logging.Logger,
+	projectID func(c *gin.Context, body []byte) string,
+	next gin.HandlerFunc,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyHeader)
+		if key == "" || idempotencyService == nil {
+			next(c)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key must be at most 255 characters"})
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIngestBodySize))
+		if err != nil {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		project := projectID(c, body)
+		// Never replay another project's response
+		if !requireIngestionProject(c, project) {
+			return
+		}
+		requestHash := application.HashRequest([]byte(c.Request.Method), []byte(c.Request.URL.RequestURI()), body)
+
+		ctx := c.Request.Context()
+		record, err := idempotencyService.Begin(ctx, project, key, requestHash)
+		if err != nil {
+			c.JSON(idempotencyErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		if record != nil {
+			c.Header(idempotentReplayHeader, "true")
+			c.Data(record.ResponseStatus, "application/json; charset=utf-8", record.ResponseBody)
+			return
+		}
+
+		writer := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		next(c)
+
+		status := writer.Status()
+		if status >= 200 && status < 300 {
+			err = idempotencyService.Complete(ctx, project, key, c.GetUint(ingestedTestRunIDKey), status, writer.body.Bytes())
+		} else {
+			err = idempotencyService.Release(ctx, project, key)
+		}
+		if err != nil {
+			logger.WithError(err).Error("Failed to update idempotency key", "project_id", project)
+		}
+	}
+}
+
+// idempotencyErrorStatus maps idempotency failures to HTTP status codes
+func idempotencyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrIdempotencyKeyMismatch):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrIdempotencyKeyInProgress):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
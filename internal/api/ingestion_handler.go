@@ -0,0 +1,323 @@
+// Package api provides domain-based REST API handlers
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	projectsApp "github.com/guidewire-oss/fern-platform/internal/domains/projects/application"
+	projectsDomain "github.com/guidewire-oss/fern-platform/internal/domains/projects/domain"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+	"github.com/guidewire-oss/fern-platform/pkg/logging"
+)
+
+// maxIngestBodySize bounds the size of uploaded test reports
+const maxIngestBodySize = 64 << 20
+
+// ingestionRetryAfterSeconds is the Retry-After hint sent when the ingestion queue is full
+const ingestionRetryAfterSeconds = 30
+
+// IngestionHandler handles native test report ingestion endpoints
+type IngestionHandler struct {
+	*BaseHandler
+	testingService        *application.TestRunService
+	projectService        *projectsApp.ProjectService
+	idempotencyService    *application.IdempotencyService
+	ingestionQueueService *application.IngestionQueueService
+}
+
+// NewIngestionHandler creates a new ingestion handler
+func NewIngestionHandler(
+	testingService *application.TestRunService,
+	projectService *projectsApp.ProjectService,
+	idempotencyService *application.IdempotencyService,
+	ingestionQueueService *application.IngestionQueueService,
+	logger *logging.Logger,
+) *IngestionHandler {
+	return &IngestionHandler{
+		BaseHandler:           NewBaseHandler(logger),
+		testingService:        testingService,
+		projectService:        projectService,
+		idempotencyService:    idempotencyService,
+		ingestionQueueService: ingestionQueueService,
+	}
+}
+
+// ingestReport returns a handler for POST /api/v1/projects/:projectId/ingest/<format>.
+// Reports are queued and answered with 202 unless the request is sent with ?async=false or
+// "Prefer: wait", in which case the run is recorded before responding with 201.
+func (h *IngestionHandler) ingestReport(format string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		opts, ok := h.importOptions(c)
+		if !ok {
+			return
+		}
+
+		body := http.MaxBytesReader(c.Writer, c.Request.Body, maxIngestBodySize)
+		if !wantsSync(c) {
+			h.enqueueReport(c, format, body, opts)
+			return
+		}
+
+		testRun, err := h.testingService.ImportReport(c.Request.Context(), format, body, opts)
+		if err != nil {
+			h.logger.WithError(err).Error("Failed to ingest report", "format", format)
+			c.JSON(ingestionErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Set(ingestedTestRunIDKey, testRun.ID)
+		c.JSON(http.StatusCreated, h.convertIngestedRunToAPI(testRun))
+	}
+}
+
+// enqueueReport queues a report for the ingestion worker pool
+func (h *IngestionHandler) enqueueReport(c *gin.Context, format string, body io.Reader, opts application.ImportOptions) {
+	payload, err := io.ReadAll(body)
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := h.ingestionQueueService.Enqueue(c.Request.Context(), format, payload, opts)
+	if err != nil {
+		if errors.Is(err, domain.ErrIngestionQueueFull) {
+			c.Header("Retry-After", strconv.Itoa(ingestionRetryAfterSeconds))
+		} else {
+			h.logger.WithError(err).Error("Failed to queue report", "format", format)
+		}
+		c.JSON(ingestionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Location", "/api/v1/ingestions/"+job.ID)
+	c.JSON(http.StatusAccepted, h.convertIngestionJobToAPI(job))
+}
+
+// streamTestRun handles POST /api/v1/projects/:projectId/ingest/stream. The body is a stream of
+// newline-delimited JSON events that are recorded as they arrive, so the run's counters can be
+// followed while it is still in progress. The response is sent once the stream ends.
+func (h *IngestionHandler) streamTestRun(c *gin.Context) {
+	opts, ok := h.importOptions(c)
+	if !ok {
+		return
+	}
+
+	testRun, err := h.testingService.StartLiveRun(c.Request.Context(), opts)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to start streamed run")
+		c.JSON(ingestionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	// A stream lasts as long as the run rather than the server's read timeout; it is cut off
+	// only when the reporter stays silent for longer than the heartbeat timeout
+	controller := http.NewResponseController(c.Writer)
+	extendDeadline := func() {
+		_ = controller.SetReadDeadline(time.Now().Add(h.testingService.HeartbeatTimeout()))
+		_ = controller.SetWriteDeadline(time.Now().Add(h.testingService.HeartbeatTimeout()))
+	}
+	extendDeadline()
+
+	if err := h.testingService.StreamTestRun(c.Request.Context(), testRun, c.Request.Body, extendDeadline); err != nil {
+		h.logger.WithError(err).Error("Failed to record streamed run", "runId", testRun.RunID)
+		c.JSON(ingestionErrorStatus(err), gin.H{"error": err.Error(), "testRun": h.convertIngestedRunToAPI(testRun)})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.convertIngestedRunToAPI(testRun))
+}
+
+// getIngestion handles GET /api/v1/ingestions/:id
+func (h *IngestionHandler) getIngestion(c *gin.Context) {
+	job, err := h.ingestionQueueService.GetJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrIngestionJobNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.WithError(err).Error("Failed to get ingestion job")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !requireIngestionProject(c, job.ProjectID) {
+		return
+	}
+
+	c.JSON(http.StatusOK, h.convertIngestionJobToAPI(job))
+}
+
+// wantsSync reports whether the client asked to wait for the report to be recorded
+// instead of having it queued
+func wantsSync(c *gin.Context) bool {
+	if async, err := strconv.ParseBool(c.Query("async")); err == nil {
+		return !async
+	}
+	for _, prefer := range c.Request.Header.Values("Prefer") {
+		for _, preference := range strings.Split(prefer, ",") {
+			name, _, _ := strings.Cut(strings.TrimSpace(preference), "=")
+			if strings.EqualFold(strings.TrimSpace(name), "wait") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// importOptions resolves the target project and reads run details from the query string.
+// It writes an error response and returns false when the project does not exist.
+func (h *IngestionHandler) importOptions(c *gin.Context) (application.ImportOptions, bool) {
+	projectID := c.Param("projectId")
+	if _, err := h.projectService.GetProject(c.Request.Context(), projectsDomain.ProjectID(projectID)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "project not found"})
+		return application.ImportOptions{}, false
+	}
+
+	opts := application.ImportOptions{
+		ProjectID:   projectID,
+		RunID:       c.Query("runId"),
+		Branch:      c.Query("branch"),
+		GitCommit:   c.Query("commitSha"),
+		Environment: c.Query("environment"),
+	}
+
+	// Shards of a parallel CI run are merged into the run named by groupId (or runId)
+	if c.Query("shardTotal") != "" || c.Query("shardIndex") != "" {
+		shard, err := shardFromQuery(c, opts.RunID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return application.ImportOptions{}, false
+		}
+		opts.Shard = shard
+	}
+
+	return opts, true
+}
+
+// shardFromQuery reads the groupId, shardIndex and shardTotal query parameters
+func shardFromQuery(c *gin.Context, runID string) (*domain.ShardInfo, error) {
+	index, err := strconv.Atoi(c.Query("shardIndex"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid shardIndex: %q", c.Query("shardIndex"))
+	}
+	total, err := strconv.Atoi(c.Query("shardTotal"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid shardTotal: %q", c.Query("shardTotal"))
+	}
+
+	shard := &domain.ShardInfo{GroupID: c.DefaultQuery("groupId", runID), Index: index, Total: total}
+	if err := shard.Validate(); err != nil {
+		return nil, err
+	}
+	return shard, nil
+}
+
+// ingestionErrorStatus maps unparseable reports to 400, run ID and shard clashes and streams to
+// finished runs to 409, a full queue to 503 and storage failures to 500
+func ingestionErrorStatus(err error) int {
+	var invalid *application.InvalidReportError
+	if errors.As(err, &invalid) {
+		return http.StatusBadRequest
+	}
+	if errors.Is(err, domain.ErrRunIDConflict) || errors.Is(err, domain.ErrShardMismatch) || errors.Is(err, domain.ErrTestRunFinished) {
+		return http.StatusConflict
+	}
+	if errors.Is(err, domain.ErrIngestionQueueFull) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// convertIngestedRunToAPI summarises an ingested test run
+func (h *IngestionHandler) convertIngestedRunToAPI(tr *domain.TestRun) gin.H {
+	result := gin.H{
+		"id":           tr.ID,
+		"runId":        tr.RunID,
+		"projectId":    tr.ProjectID,
+		"status":       tr.Status,
+		"startTime":    tr.StartTime,
+		"endTime":      tr.EndTime,
+		"duration":     tr.Duration.Milliseconds(),
+		"totalTests":   tr.TotalTests,
+		"passedTests":  tr.PassedTests,
+		"failedTests":  tr.FailedTests,
+		"skippedTests": tr.SkippedTests,
+		"suiteCount":   len(tr.SuiteRuns),
+	}
+	if tr.ShardTotal > 0 {
+		result["shardTotal"] = tr.ShardTotal
+		result["shardsReceived"] = tr.ShardsReceived
+	}
+	if tr.LastHeartbeatAt != nil {
+		result["lastHeartbeatAt"] = tr.LastHeartbeatAt
+	}
+	return result
+}
+
+// convertIngestionJobToAPI reports the progress of a queued ingestion
+func (h *IngestionHandler) convertIngestionJobToAPI(job *domain.IngestionJob) gin.H {
+	jobErrors := make([]gin.H, len(job.Errors))
+	for i, jobErr := range job.Errors {
+		jobErrors[i] = gin.H{
+			"attempt":    jobErr.Attempt,
+			"message":    jobErr.Message,
+			"retryable":  jobErr.Retryable,
+			"occurredAt": jobErr.OccurredAt,
+		}
+	}
+
+	result := gin.H{
+		"ingestionId": job.ID,
+		"projectId":   job.ProjectID,
+		"format":      job.Format,
+		"runId":       job.RunID,
+		"status":      job.Status,
+		"attempts":    job.Attempts,
+		"maxAttempts": job.MaxAttempts,
+		"errors":      jobErrors,
+		"createdAt":   job.CreatedAt,
+		"startedAt":   job.StartedAt,
+		"completedAt": job.CompletedAt,
+	}
+	if job.Shard != nil {
+		result["groupId"] = job.Shard.GroupID
+		result["shardIndex"] = job.Shard.Index
+		result["shardTotal"] = job.Shard.Total
+	}
+	if job.TestRunID != 0 {
+		result["testRunId"] = job.TestRunID
+	}
+	if job.Status == domain.IngestionJobQueued {
+		result["nextAttemptAt"] = job.NextAttemptAt
+	}
+	return result
+}
+
+// idempotent scopes Idempotency-Key handling to the project in the request path
+func (h *IngestionHandler) idempotent(next gin.HandlerFunc) gin.HandlerFunc {
+	return withIdempotency(h.idempotencyService, h.logger, func(c *gin.Context, _ []byte) string {
+		return c.Param("projectId")
+	}, next)
+}
+
+// RegisterRoutes registers ingestion routes
+func (h *IngestionHandler) RegisterRoutes(ingestGroup *gin.RouterGroup) {
+	ingestGroup.POST("/projects/:projectId/ingest/junit", h.idempotent(h.ingestReport(application.ReportFormatJUnit)))
+	ingestGroup.POST("/projects/:projectId/ingest/go-test", h.idempotent(h.ingestReport(application.ReportFormatGoTest)))
+	ingestGroup.POST("/projects/:projectId/ingest/test-run", h.idempotent(h.ingestReport(application.ReportFormatTestRun)))
+	ingestGroup.POST("/projects/:projectId/ingest/trx", h.idempotent(h.ingestReport(application.ReportFormatTRX)))
+	ingestGroup.POST("/projects/:projectId/ingest/nunit", h.idempotent(h.ingestReport(application.ReportFormatNUnit)))
+	ingestGroup.POST("/projects/:projectId/ingest/xunit", h.idempotent(h.ingestReport(application.ReportFormatXUnit)))
+	ingestGroup.POST("/projects/:projectId/ingest/cucumber", h.idempotent(h.ingestReport(application.ReportFormatCucumber)))
+	ingestGroup.POST("/projects/:projectId/ingest/allure", h.idempotent(h.ingestReport(application.ReportFormatAllure)))
+	ingestGroup.POST("/projects/:projectId/ingest/ctrf", h.idempotent(h.ingestReport(application.ReportFormatCTRF)))
+	ingestGroup.POST("/projects/:projectId/ingest/stream", h.streamTestRun)
+	ingestGroup.GET("/ingestions/:id", h.getIngestion)
+}
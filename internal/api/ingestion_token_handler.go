@@ -0,0 +1,178 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	authApp "github.com/guidewire-oss/fern-platform/internal/domains/auth/application"
+	authDomain "github.com/guidewire-oss/fern-platform/internal/domains/auth/domain"
+	"github.com/guidewire-oss/fern-platform/internal/domains/auth/interfaces"
+	projectsApp "github.com/guidewire-oss/fern-platform/internal/domains/projects/application"
+	projectsDomain "github.com/guidewire-oss/fern-platform/internal/domains/projects/domain"
+)
+
+// IngestionTokenHandler handles management of project-scoped ingestion tokens
+type IngestionTokenHandler struct {
+	*BaseHandler
+	tokenService   *authApp.IngestionTokenService
+	projectService *projectsApp.ProjectService
+}
+
+// NewIngestionTokenHandler creates a new ingestion token handler
+func NewIngestionTokenHandler(
+	baseHandler *BaseHandler,
+	tokenService *authApp.IngestionTokenService,
+	projectService *projectsApp.ProjectService,
+) *IngestionTokenHandler {
+	return &IngestionTokenHandler{
+		BaseHandler:    baseHandler,
+		tokenService:   tokenService,
+		projectService: projectService,
+	}
+}
+
+// CreateIngestionTokenRequest represents the request to create an ingestion token
+type CreateIngestionTokenRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// IngestionTokenResponse represents an ingestion token. Token is only set when the
+// token has just been created or rotated.
+type IngestionTokenResponse struct {
+	ID          string     `json:"id"`
+	ProjectID   string     `json:"projectId"`
+	Name        string     `json:"name"`
+	TokenPrefix string     `json:"tokenPrefix"`
+	Token       string     `json:"token,omitempty"`
+	CreatedBy   string     `json:"createdBy,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt  *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt   *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+// CreateToken issues a new ingestion token for a project
+func (h *IngestionTokenHandler) CreateToken(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if !h.projectExists(c, projectID) {
+		return
+	}
+
+	var req CreateIngestionTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	token, secret, err := h.tokenService.CreateToken(c.Request.Context(), projectID, req.Name, c.GetString("user_id"), req.ExpiresAt)
+	if err != nil {
+		h.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := h.convertToResponse(token)
+	response.Token = secret
+	h.respondWithJSON(c, http.StatusCreated, response)
+}
+
+// GetTokens lists a project's ingestion tokens
+func (h *IngestionTokenHandler) GetTokens(c *gin.Context) {
+	projectID := c.Param("projectId")
+	if !h.projectExists(c, projectID) {
+		return
+	}
+
+	tokens, err := h.tokenService.ListTokens(c.Request.Context(), projectID)
+	if err != nil {
+		h.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	responses := make([]IngestionTokenResponse, len(tokens))
+	for i, token := range tokens {
+		responses[i] = *h.convertToResponse(token)
+	}
+
+	h.respondWithJSON(c, http.StatusOK, responses)
+}
+
+// RotateToken replaces a token's secret
+func (h *IngestionTokenHandler) RotateToken(c *gin.Context) {
+	token, secret, err := h.tokenService.RotateToken(c.Request.Context(), c.Param("projectId"), c.Param("tokenId"))
+	if err != nil {
+		h.ErrorResponse(c, tokenErrorStatus(err), err.Error())
+		return
+	}
+
+	response := h.convertToResponse(token)
+	response.Token = secret
+	h.respondWithJSON(c, http.StatusOK, response)
+}
+
+// RevokeToken permanently disables a token
+func (h *IngestionTokenHandler) RevokeToken(c *gin.Context) {
+	if err := h.tokenService.RevokeToken(c.Request.Context(), c.Param("projectId"), c.Param("tokenId")); err != nil {
+		h.ErrorResponse(c, tokenErrorStatus(err), err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RegisterRoutes registers ingestion token routes
+func (h *IngestionTokenHandler) RegisterRoutes(managerGroup *gin.RouterGroup) {
+	tokens := managerGroup.Group("/projects/:projectId/ingestion-tokens")
+	{
+		tokens.GET("", h.GetTokens)
+		tokens.POST("", h.CreateToken)
+		tokens.POST("/:tokenId/rotate", h.RotateToken)
+		tokens.DELETE("/:tokenId", h.RevokeToken)
+	}
+}
+
+// projectExists responds with 404 if the project does not exist
+func (h *IngestionTokenHandler) projectExists(c *gin.Context, projectID string) bool {
+	if _, err := h.projectService.GetProject(c.Request.Context(), projectsDomain.ProjectID(projectID)); err != nil {
+		h.ErrorResponse(c, http.StatusNotFound, "project not found")
+		return false
+	}
+	return true
+}
+
+// tokenErrorStatus maps unknown tokens to 404, revoked tokens to 409 and storage failures to 500
+func tokenErrorStatus(err error) int {
+	if errors.Is(err, authDomain.ErrIngestionTokenNotFound) {
+		return http.StatusNotFound
+	}
+	if errors.Is(err, authDomain.ErrIngestionTokenRevoked) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// convertToResponse converts a token to its response, never including the stored hash
+func (h *IngestionTokenHandler) convertToResponse(token *authDomain.IngestionToken) *IngestionTokenResponse {
+	return &IngestionTokenResponse{
+		ID:          token.ID,
+		ProjectID:   token.ProjectID,
+		Name:        token.Name,
+		TokenPrefix: token.TokenPrefix,
+		CreatedBy:   token.CreatedBy,
+		ExpiresAt:   token.ExpiresAt,
+		LastUsedAt:  token.LastUsedAt,
+		RevokedAt:   token.RevokedAt,
+		CreatedAt:   token.CreatedAt,
+	}
+}
+
+// requireIngestionProject responds with 403 if the request's ingestion token belongs to another project
+func requireIngestionProject(c *gin.Context, projectID string) bool {
+	if interfaces.IngestionTokenAllowsProject(c, projectID) {
+		return true
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": "Ingestion token is not valid for this project"})
+	return false
+}
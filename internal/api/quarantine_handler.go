@@ -0,0 +1,203 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+	"github.com/guidewire-oss/fern-platform/pkg/logging"
+)
+
+// QuarantineHandler handles test quarantine endpoints
+type QuarantineHandler struct {
+	*BaseHandler
+	quarantineService *application.QuarantineService
+}
+
+// NewQuarantineHandler creates a new quarantine handler
+func NewQuarantineHandler(quarantineService *application.QuarantineService, logger *logging.Logger) *QuarantineHandler {
+	return &QuarantineHandler{
+		BaseHandler:       NewBaseHandler(logger),
+		quarantineService: quarantineService,
+	}
+}
+
+// QuarantineTestRequest represents the request to quarantine a test
+type QuarantineTestRequest struct {
+	TestID    uint       `json:"testId" binding:"required"`
+	Reason    string     `json:"reason" binding:"required"`
+	Owner     string     `json:"owner"`
+	JiraIssue string     `json:"jiraIssue"`
+	ExpiresAt *time.Time `json:"expiresAt"` // Defaults to 30 days from now
+}
+
+// exportQuarantines handles GET /api/v1/projects/:projectId/quarantine?format=json|ginkgo-skip|junit-exclude,
+// which test runners fetch at startup to skip or soft-fail quarantined tests
+func (h *QuarantineHandler) exportQuarantines(c *gin.Context) {
+	format := c.DefaultQuery("format", application.QuarantineFormatJSON)
+
+	quarantines, err := h.quarantineService.ListQuarantines(c.Request.Context(), c.Param("projectId"), true)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list quarantines")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if format == application.QuarantineFormatJSON {
+		tests := make([]gin.H, len(quarantines))
+		for i, quarantine := range quarantines {
+			tests[i] = convertQuarantineToAPI(quarantine)
+		}
+		c.JSON(http.StatusOK, gin.H{"projectId": c.Param("projectId"), "quarantined": tests})
+		return
+	}
+
+	body, contentType, err := application.FormatQuarantines(format, quarantines)
+	if errors.Is(err, application.ErrUnknownQuarantineFormat) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json, ginkgo-skip or junit-exclude"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, contentType, []byte(body))
+}
+
+// listQuarantines handles GET /api/v1/projects/:projectId/quarantines?status=active|all
+func (h *QuarantineHandler) listQuarantines(c *gin.Context) {
+	status := c.DefaultQuery("status", string(domain.QuarantineActive))
+	if status != string(domain.QuarantineActive) && status != "all" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be active or all"})
+		return
+	}
+
+	quarantines, err := h.quarantineService.ListQuarantines(c.Request.Context(), c.Param("projectId"), status == string(domain.QuarantineActive))
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list quarantines")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	result := make([]gin.H, len(quarantines))
+	for i, quarantine := range quarantines {
+		result[i] = convertQuarantineToAPI(quarantine)
+	}
+	c.JSON(http.StatusOK, gin.H{"quarantines": result, "total": len(result)})
+}
+
+// getQuarantine handles GET /api/v1/projects/:projectId/quarantines/:quarantineId
+func (h *QuarantineHandler) getQuarantine(c *gin.Context) {
+	id, ok := quarantineID(c)
+	if !ok {
+		return
+	}
+
+	quarantine, err := h.quarantineService.GetQuarantine(c.Request.Context(), c.Param("projectId"), id)
+	if err != nil {
+		h.quarantineError(c, err, "Failed to get quarantine")
+		return
+	}
+	c.JSON(http.StatusOK, convertQuarantineToAPI(quarantine))
+}
+
+// quarantineTest handles POST /api/v1/projects/:projectId/quarantines
+func (h *QuarantineHandler) quarantineTest(c *gin.Context) {
+	var req QuarantineTestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quarantine, err := h.quarantineService.QuarantineTest(c.Request.Context(), application.QuarantineTestRequest{
+		ProjectID:  c.Param("projectId"),
+		TestCaseID: req.TestID,
+		Reason:     req.Reason,
+		Owner:      req.Owner,
+		JiraIssue:  req.JiraIssue,
+		CreatedBy:  c.GetString("user_id"),
+		ExpiresAt:  req.ExpiresAt,
+	})
+	if err != nil {
+		h.quarantineError(c, err, "Failed to quarantine test")
+		return
+	}
+	c.JSON(http.StatusCreated, convertQuarantineToAPI(quarantine))
+}
+
+// liftQuarantine handles DELETE /api/v1/projects/:projectId/quarantines/:quarantineId
+func (h *QuarantineHandler) liftQuarantine(c *gin.Context) {
+	id, ok := quarantineID(c)
+	if !ok {
+		return
+	}
+
+	quarantine, err := h.quarantineService.LiftQuarantine(c.Request.Context(), c.Param("projectId"), id, c.GetString("user_id"))
+	if err != nil {
+		h.quarantineError(c, err, "Failed to lift quarantine")
+		return
+	}
+	c.JSON(http.StatusOK, convertQuarantineToAPI(quarantine))
+}
+
+// quarantineError responds with the status a quarantine service error maps to
+func (h *QuarantineHandler) quarantineError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrQuarantineNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Quarantine not found"})
+	case errors.Is(err, domain.ErrTestCaseNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Test not found"})
+	case errors.Is(err, domain.ErrTestAlreadyQuarantined):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.WithError(err).Error(message)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+// quarantineID parses the quarantine ID of a request, responding with 400 when it is invalid
+func quarantineID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("quarantineId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid quarantine ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// convertQuarantineToAPI converts a domain quarantine to API response format
+func convertQuarantineToAPI(quarantine *domain.Quarantine) gin.H {
+	result := gin.H{
+		"id":        quarantine.ID,
+		"projectId": quarantine.ProjectID,
+		"testId":    quarantine.TestCaseID,
+		"status":    quarantine.Status(time.Now()),
+		"reason":    quarantine.Reason,
+		"owner":     quarantine.Owner,
+		"jiraIssue": quarantine.JiraIssue,
+		"createdBy": quarantine.CreatedBy,
+		"createdAt": quarantine.CreatedAt,
+		"expiresAt": quarantine.ExpiresAt,
+		"liftedAt":  quarantine.LiftedAt,
+		"liftedBy":  quarantine.LiftedBy,
+	}
+	if quarantine.TestCase != nil {
+		result["testCase"] = convertTestCaseToAPI(quarantine.TestCase)
+		result["skipPattern"] = quarantine.SkipPattern()
+	}
+	return result
+}
+
+// RegisterRoutes registers quarantine routes. CI fetches the active quarantines of its project
+// with its ingestion token; managers quarantine tests and lift quarantines.
+func (h *QuarantineHandler) RegisterRoutes(ingestGroup, userGroup, managerGroup *gin.RouterGroup) {
+	ingestGroup.GET("/projects/:projectId/quarantine", h.exportQuarantines)
+	userGroup.GET("/projects/:projectId/quarantines", h.listQuarantines)
+	userGroup.GET("/projects/:projectId/quarantines/:quarantineId", h.getQuarantine)
+	managerGroup.POST("/projects/:projectId/quarantines", h.quarantineTest)
+	managerGroup.DELETE("/projects/:projectId/quarantines/:quarantineId", h.liftQuarantine)
+}
@@ -2,12 +2,14 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	authInterfaces "github.com/guidewire-oss/fern-platform/internal/domains/auth/interfaces"
 	"github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
 	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
 	"github.com/guidewire-oss/fern-platform/pkg/logging"
@@ -96,11 +98,309 @@ func (h *TestRunHandler) getTestRun(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Test run not found"})
 		return
 	}
+	if !authInterfaces.CheckProjectScope(c, testRun.ProjectID) {
+		return
+	}
 
 	// Convert to API response format
 	c.JSON(http.StatusOK, h.convertTestRunToAPI(testRun))
 }
 
+// exportTestRun handles GET /api/v1/test-runs/:id/export?format=ctrf
+func (h *TestRunHandler) exportTestRun(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid test run ID"})
+		return
+	}
+
+	format := c.DefaultQuery("format", application.ReportFormatCTRF)
+	if format != application.ReportFormatCTRF {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unsupported export format %q", format)})
+		return
+	}
+
+	testRun, err := h.testingService.GetTestRunWithDetails(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Test run not found"})
+		return
+	}
+	if !authInterfaces.CheckProjectScope(c, testRun.ProjectID) {
+		return
+	}
+
+	c.JSON(http.StatusOK, application.ExportCTRF(testRun))
+}
+
+// getSpecOutput handles GET /api/v1/spec-runs/:id/output
+func (h *TestRunHandler) getSpecOutput(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid spec run ID"})
+		return
+	}
+
+	output, err := h.testingService.GetSpecOutput(c.Request.Context(), uint(id))
+	if errors.Is(err, domain.ErrSpecOutputNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No output was captured for this spec run"})
+		return
+	}
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get spec output")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !authInterfaces.CheckProjectScope(c, output.ProjectID) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"specRunId":  output.SpecRunID,
+		"stdout":     output.Stdout,
+		"stderr":     output.Stderr,
+		"stdoutSize": output.StdoutSize,
+		"stderrSize": output.StderrSize,
+		"truncated":  output.Truncated,
+	})
+}
+
+// searchSpecOutput handles GET /api/v1/projects/:projectId/spec-output/search?q=...&days=7&status=failed&limit=50
+func (h *TestRunHandler) searchSpecOutput(c *gin.Context) {
+	search := domain.SpecOutputSearch{
+		ProjectID: c.Param("projectId"),
+		Query:     c.Query("q"),
+		Status:    c.Query("status"),
+	}
+	if search.Query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "query parameter q is required"})
+		return
+	}
+	if days := c.Query("days"); days != "" {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a positive number"})
+			return
+		}
+		search.Since = time.Now().AddDate(0, 0, -n)
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+			return
+		}
+		search.Limit = n
+	}
+
+	matches, err := h.testingService.SearchSpecOutput(c.Request.Context(), search)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to search spec output")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	results := make([]gin.H, len(matches))
+	for i, match := range matches {
+		results[i] = gin.H{
+			"specRunId":  match.SpecRun.ID,
+			"specName":   match.SpecRun.Name,
+			"status":     match.SpecRun.Status,
+			"suiteRunId": match.SpecRun.SuiteRunID,
+			"suiteName":  match.SuiteName,
+			"testRunId":  match.TestRunID,
+			"runId":      match.RunID,
+			"branch":     match.Branch,
+			"startTime":  match.StartTime,
+			"snippet":    match.Snippet,
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"results": results, "total": len(results)})
+}
+
+// listTestCases handles GET /api/v1/projects/:projectId/tests?q=...&limit=50
+func (h *TestRunHandler) listTestCases(c *gin.Context) {
+	limit := 0
+	if l := c.Query("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+			return
+		}
+		limit = n
+	}
+
+	testCases, err := h.testingService.ListTestCases(c.Request.Context(), c.Param("projectId"), c.Query("q"), limit)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list test cases")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	tests := make([]gin.H, len(testCases))
+	for i, testCase := range testCases {
+		tests[i] = convertTestCaseToAPI(testCase)
+	}
+	c.JSON(http.StatusOK, gin.H{"tests": tests, "total": len(tests)})
+}
+
+// getTestCase handles GET /api/v1/tests/:testId
+func (h *TestRunHandler) getTestCase(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("testId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid test ID"})
+		return
+	}
+
+	testCase, err := h.testingService.GetTestCase(c.Request.Context(), uint(id))
+	if errors.Is(err, domain.ErrTestCaseNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Test not found"})
+		return
+	}
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get test case")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !authInterfaces.CheckProjectScope(c, testCase.ProjectID) {
+		return
+	}
+
+	c.JSON(http.StatusOK, convertTestCaseToAPI(testCase))
+}
+
+// getTestCaseHistory handles GET /api/v1/tests/:testId/history?days=30&branch=main&environment=staging&limit=200
+func (h *TestRunHandler) getTestCaseHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("testId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid test ID"})
+		return
+	}
+
+	filter := domain.TestCaseHistoryFilter{
+		Branch:      c.Query("branch"),
+		Environment: c.Query("environment"),
+	}
+	if days := c.Query("days"); days != "" {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a positive number"})
+			return
+		}
+		filter.Since = time.Now().AddDate(0, 0, -n)
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+			return
+		}
+		filter.Limit = n
+	}
+
+	history, err := h.testingService.GetTestCaseHistory(c.Request.Context(), uint(id), filter)
+	if errors.Is(err, domain.ErrTestCaseNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Test not found"})
+		return
+	}
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get test case history")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !authInterfaces.CheckProjectScope(c, history.TestCase.ProjectID) {
+		return
+	}
+
+	executions := make([]gin.H, len(history.Executions))
+	for i, execution := range history.Executions {
+		executions[i] = gin.H{
+			"specRunId":    execution.SpecRunID,
+			"testCaseId":   execution.TestCaseID,
+			"testRunId":    execution.TestRunID,
+			"runId":        execution.RunID,
+			"branch":       execution.Branch,
+			"environment":  execution.Environment,
+			"gitCommit":    execution.GitCommit,
+			"parameters":   execution.Parameters,
+			"status":       execution.Status,
+			"startTime":    execution.StartTime,
+			"duration":     execution.Duration.Milliseconds(),
+			"errorMessage": execution.ErrorMessage,
+			"retryCount":   execution.RetryCount,
+			"isFlaky":      execution.IsFlaky,
+			"quarantined":  execution.Quarantined,
+		}
+	}
+
+	parameterSets := make([]gin.H, len(history.ParameterSets))
+	for i, testCase := range history.ParameterSets {
+		parameterSets[i] = convertTestCaseToAPI(testCase)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"test":          convertTestCaseToAPI(history.TestCase),
+		"executions":    executions,
+		"summary":       convertTestCaseStatsToAPI(history.Summary),
+		"byBranch":      convertTestCaseStatsListToAPI(history.ByBranch),
+		"byEnvironment": convertTestCaseStatsListToAPI(history.ByEnvironment),
+		"byParameters":  convertTestCaseStatsListToAPI(history.ByParameters),
+		"parameterSets": parameterSets,
+	})
+}
+
+// backfillTestCases handles POST /api/v1/admin/tests/backfill
+func (h *TestRunHandler) backfillTestCases(c *gin.Context) {
+	linked, err := h.testingService.BackfillTestCases(c.Request.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to backfill test cases", "linked", linked)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "linked": linked})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"linked": linked})
+}
+
+// convertTestCaseToAPI converts a domain test case to API response format
+func convertTestCaseToAPI(testCase *domain.TestCase) gin.H {
+	return gin.H{
+		"id":          testCase.ID,
+		"projectId":   testCase.ProjectID,
+		"parentId":    testCase.ParentID,
+		"suiteName":   testCase.SuiteName,
+		"packageName": testCase.PackageName,
+		"className":   testCase.ClassName,
+		"name":        testCase.Name,
+		"parameters":  testCase.Parameters,
+		"fingerprint": testCase.Fingerprint,
+		"firstSeenAt": testCase.FirstSeenAt,
+		"lastSeenAt":  testCase.LastSeenAt,
+	}
+}
+
+// convertTestCaseStatsToAPI converts test case statistics to API response format
+func convertTestCaseStatsToAPI(stats domain.TestCaseStats) gin.H {
+	return gin.H{
+		"total":           stats.Total,
+		"passed":          stats.Passed,
+		"failed":          stats.Failed,
+		"skipped":         stats.Skipped,
+		"flaky":           stats.Flaky,
+		"quarantined":     stats.Quarantined,
+		"passRate":        stats.PassRate,
+		"averageDuration": stats.AverageDuration.Milliseconds(),
+	}
+}
+
+// convertTestCaseStatsListToAPI converts per-branch or per-environment statistics to API response format
+func convertTestCaseStatsListToAPI(statsList []domain.TestCaseStats) []gin.H {
+	result := make([]gin.H, len(statsList))
+	for i, stats := range statsList {
+		result[i] = convertTestCaseStatsToAPI(stats)
+		result[i]["key"] = stats.Key
+	}
+	return result
+}
+
 // getTestRunByRunID handles GET /api/v1/test-runs/by-run-id/:runId
 func (h *TestRunHandler) getTestRunByRunID(c *gin.Context) {
 	_ = c.Param("runId")
@@ -290,6 +590,18 @@ func (h *TestRunHandler) bulkDeleteTestRuns(c *gin.Context) {
 	c.JSON(http.StatusNotImplemented, gin.H{"error": "Bulk delete not yet implemented"})
 }
 
+// backfillLegacySuiteRuns handles POST /api/v1/admin/test-runs/backfill-legacy-suites
+func (h *TestRunHandler) backfillLegacySuiteRuns(c *gin.Context) {
+	backfilled, err := h.testingService.BackfillLegacySuiteRuns(c.Request.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to backfill legacy suite runs", "backfilled", backfilled)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "backfilled": backfilled})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"backfilled": backfilled})
+}
+
 // convertTestRunToAPI converts a domain test run to API response format
 func (h *TestRunHandler) convertTestRunToAPI(tr *domain.TestRun) gin.H {
 	return gin.H{
@@ -312,6 +624,10 @@ func (h *TestRunHandler) convertTestRunToAPI(tr *domain.TestRun) gin.H {
 		"metadata":     tr.Metadata,
 		"createdAt":    tr.StartTime,
 		"updatedAt":    tr.EndTime,
+		// Set while the run's results are streamed in
+		"lastHeartbeatAt": tr.LastHeartbeatAt,
+		// Failures of quarantined tests, included in failedTests but left out of pass rates
+		"quarantinedTests": tr.QuarantinedTests,
 	}
 }
 
@@ -321,14 +637,22 @@ func (h *TestRunHandler) RegisterRoutes(userGroup, adminGroup *gin.RouterGroup)
 	userGroup.GET("/test-runs", h.listTestRuns)
 	userGroup.GET("/test-runs/count", h.countTestRuns)
 	userGroup.GET("/test-runs/:id", h.getTestRun)
+	userGroup.GET("/test-runs/:id/export", h.exportTestRun)
 	userGroup.GET("/test-runs/by-run-id/:runId", h.getTestRunByRunID)
 	userGroup.GET("/test-runs/stats", h.getTestRunStats)
 	userGroup.GET("/test-runs/recent", h.getRecentTestRuns)
 	userGroup.POST("/test-runs/:id/tags", h.assignTagsToTestRun)
+	userGroup.GET("/spec-runs/:id/output", h.getSpecOutput)
+	userGroup.GET("/projects/:projectId/spec-output/search", h.searchSpecOutput)
+	userGroup.GET("/projects/:projectId/tests", h.listTestCases)
+	userGroup.GET("/tests/:testId", h.getTestCase)
+	userGroup.GET("/tests/:testId/history", h.getTestCaseHistory)
 
 	// Admin routes (create/update/delete)
 	adminGroup.POST("/test-runs", h.createTestRun)
 	adminGroup.PUT("/test-runs/:runId/status", h.updateTestRunStatus)
 	adminGroup.DELETE("/test-runs/:id", h.deleteTestRun)
 	adminGroup.POST("/test-runs/bulk-delete", h.bulkDeleteTestRuns)
+	adminGroup.POST("/test-runs/backfill-legacy-suites", h.backfillLegacySuiteRuns)
+	adminGroup.POST("/tests/backfill", h.backfillTestCases)
 }
\ No newline at end of file
@@ -0,0 +1,144 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/pkg/logging"
+)
+
+// FlakyAnalysisScheduler analyzes the tests of completed test runs in the background. Runs of
+// a project that complete in quick succession, such as the shards of one run, are analyzed
+// together once the project has been quiet for the debounce period.
+type FlakyAnalysisScheduler struct {
+	service  *FlakyDetectionService
+	debounce time.Duration
+	maxDelay time.Duration
+	logger   *logging.Logger
+
+	mu      sync.Mutex
+	pending map[string]*pendingAnalysis
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// pendingAnalysis collects the completed runs of a project waiting to be analyzed
+type pendingAnalysis struct {
+	testRunIDs []uint
+	firstAt    time.Time
+	timer      *time.Timer
+}
+
+// NewFlakyAnalysisScheduler creates a scheduler that waits debounce after the latest completed
+// run of a project, but no longer than maxDelay after the first, before analyzing them
+func NewFlakyAnalysisScheduler(service *FlakyDetectionService, debounce, maxDelay time.Duration, logger *logging.Logger) *FlakyAnalysisScheduler {
+	if maxDelay < debounce {
+		maxDelay = debounce
+	}
+
+	return &FlakyAnalysisScheduler{
+		service:  service,
+		debounce: debounce,
+		maxDelay: maxDelay,
+		logger:   logger,
+		pending:  make(map[string]*pendingAnalysis),
+	}
+}
+
+// Schedule queues the tests of a completed test run for analysis
+func (s *FlakyAnalysisScheduler) Schedule(projectID string, testRunID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	p, ok := s.pending[projectID]
+	if !ok {
+		p = &pendingAnalysis{firstAt: time.Now()}
+		p.timer = time.AfterFunc(s.debounce, func() { s.flush(projectID, p) })
+		s.pending[projectID] = p
+	}
+
+	for _, id := range p.testRunIDs {
+		if id == testRunID {
+			return
+		}
+	}
+	p.testRunIDs = append(p.testRunIDs, testRunID)
+
+	if ok {
+		wait := s.debounce
+		if remaining := time.Until(p.firstAt.Add(s.maxDelay)); remaining < wait {
+			wait = max(remaining, 0)
+		}
+		p.timer.Reset(wait)
+	}
+}
+
+// Shutdown stops scheduling, analyzes the runs still waiting and waits for analyses in
+// progress to finish
+func (s *FlakyAnalysisScheduler) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	pending := s.pending
+	s.pending = make(map[string]*pendingAnalysis)
+	for _, p := range pending {
+		p.timer.Stop()
+	}
+	s.mu.Unlock()
+
+	for projectID, p := range pending {
+		s.analyze(ctx, projectID, p.testRunIDs)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// flush analyzes the runs collected for a project once its timer fires, unless they were
+// already taken by an earlier flush or by Shutdown
+func (s *FlakyAnalysisScheduler) flush(projectID string, p *pendingAnalysis) {
+	s.mu.Lock()
+	if s.pending[projectID] != p {
+		s.mu.Unlock()
+		return
+	}
+	delete(s.pending, projectID)
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	defer s.wg.Done()
+	s.analyze(context.Background(), projectID, p.testRunIDs)
+}
+
+func (s *FlakyAnalysisScheduler) analyze(ctx context.Context, projectID string, testRunIDs []uint) {
+	log := s.logger.WithService("flaky-analysis").WithFields(map[string]interface{}{
+		"project_id": projectID,
+		"test_runs":  len(testRunIDs),
+	})
+
+	analysis, err := s.service.AnalyzeTestRuns(ctx, projectID, testRunIDs)
+	if err != nil {
+		log.WithError(err).Error("Failed to analyze completed test runs")
+		return
+	}
+
+	log.WithFields(map[string]interface{}{
+		"tests":     analysis.TotalTests,
+		"new_flaky": len(analysis.NewFlaky),
+		"resolved":  len(analysis.ResolvedFlaky),
+	}).Info("Analyzed completed test runs for flaky tests")
+}
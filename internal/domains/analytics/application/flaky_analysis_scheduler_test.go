@@ -0,0 +1,89 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/analytics/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/analytics/domain"
+	"github.com/guidewire-oss/fern-platform/internal/testhelpers"
+	"github.com/guidewire-oss/fern-platform/pkg/config"
+	"github.com/guidewire-oss/fern-platform/pkg/logging"
+)
+
+func TestApplication(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Analytics Application Suite")
+}
+
+var _ = Describe("FlakyAnalysisScheduler", Label("unit", "application", "analytics"), func() {
+	var (
+		mockRepo *testhelpers.MockFlakyDetectionRepository
+		service  *application.FlakyDetectionService
+		logger   *logging.Logger
+		analyzed chan []uint
+	)
+
+	BeforeEach(func() {
+		mockRepo = new(testhelpers.MockFlakyDetectionRepository)
+		service = application.NewFlakyDetectionService(mockRepo, domain.DefaultFlakyTestDetectionConfig())
+
+		var err error
+		logger, err = logging.NewLogger(&config.LoggingConfig{Level: "error", Format: "json"})
+		Expect(err).NotTo(HaveOccurred())
+
+		// Every analysis reports the test runs it covered
+		analyzed = make(chan []uint, 10)
+		mockRepo.On("GetTestsInRuns", mock.Anything, "proj-1", mock.Anything).
+			Run(func(args mock.Arguments) { analyzed <- args.Get(2).([]uint) }).
+			Return([]domain.TestIdentity{}, nil)
+		mockRepo.On("SaveTestRunAnalysis", mock.Anything, mock.Anything).Return(nil)
+	})
+
+	It("should analyze runs completed within the debounce period together", func() {
+		scheduler := application.NewFlakyAnalysisScheduler(service, 100*time.Millisecond, time.Minute, logger)
+
+		scheduler.Schedule("proj-1", 1)
+		scheduler.Schedule("proj-1", 2)
+		scheduler.Schedule("proj-1", 2)
+
+		Consistently(analyzed, 50*time.Millisecond).ShouldNot(Receive())
+		Eventually(analyzed).Should(Receive(Equal([]uint{1, 2})))
+		Consistently(analyzed, 200*time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("should not wait longer than the max delay for a project that keeps completing runs", func() {
+		scheduler := application.NewFlakyAnalysisScheduler(service, 80*time.Millisecond, 150*time.Millisecond, logger)
+
+		// Runs keep completing more often than the debounce period for longer than the max delay
+		var firstAnalysis []uint
+		for id := uint(1); id <= 20 && firstAnalysis == nil; id++ {
+			scheduler.Schedule("proj-1", id)
+			select {
+			case firstAnalysis = <-analyzed:
+			case <-time.After(20 * time.Millisecond):
+			}
+		}
+
+		Expect(firstAnalysis).NotTo(BeEmpty())
+		Expect(firstAnalysis[0]).To(Equal(uint(1)))
+		Expect(len(firstAnalysis)).To(BeNumerically("<", 20))
+		Expect(scheduler.Shutdown(context.Background())).To(Succeed())
+	})
+
+	It("should analyze the runs still waiting on shutdown and ignore runs completed after it", func() {
+		scheduler := application.NewFlakyAnalysisScheduler(service, time.Hour, time.Hour, logger)
+		scheduler.Schedule("proj-1", 7)
+
+		Expect(scheduler.Shutdown(context.Background())).To(Succeed())
+		Expect(analyzed).To(Receive(Equal([]uint{7})))
+
+		scheduler.Schedule("proj-1", 8)
+		Consistently(analyzed, 50*time.Millisecond).ShouldNot(Receive())
+	})
+})
@@ -3,7 +3,8 @@ package application
 import (
 	"context"
 	"fmt"
-	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/guidewire-oss/fern-platform/internal/domains/analytics/domain"
@@ -11,10 +12,14 @@ import (
 
 // FlakyDetectionService handles flaky test detection and analysis
 type FlakyDetectionService struct {
-	repo   domain.FlakyDetectionRepository
-	config domain.FlakyTestDetectionConfig
+	repo            domain.FlakyDetectionRepository
+	config          domain.FlakyTestDetectionConfig
+	projectSettings ProjectSettingsFunc
 }
 
+// ProjectSettingsFunc returns a project's flaky detection settings, or false when it has none
+type ProjectSettingsFunc func(ctx context.Context, projectID string) (map[string]interface{}, bool)
+
 // NewFlakyDetectionService creates a new flaky detection service
 func NewFlakyDetectionService(repo domain.FlakyDetectionRepository, config domain.FlakyTestDetectionConfig) *FlakyDetectionService {
 	return &FlakyDetectionService{
@@ -23,6 +28,24 @@ func NewFlakyDetectionService(repo domain.FlakyDetectionRepository, config domai
 	}
 }
 
+// SetProjectSettings lets projects override the detection configuration with their own settings
+func (s *FlakyDetectionService) SetProjectSettings(projectSettings ProjectSettingsFunc) {
+	s.projectSettings = projectSettings
+}
+
+// ProjectConfig returns the detection configuration of a project: the service's configuration
+// overridden by the project's settings
+func (s *FlakyDetectionService) ProjectConfig(ctx context.Context, projectID string) (domain.FlakyTestDetectionConfig, error) {
+	if s.projectSettings == nil {
+		return s.config, nil
+	}
+	settings, ok := s.projectSettings(ctx, projectID)
+	if !ok {
+		return s.config, nil
+	}
+	return s.config.WithSettings(settings)
+}
+
 // AnalyzeTestRun analyzes a test run for flaky tests
 func (s *FlakyDetectionService) AnalyzeTestRun(ctx context.Context, projectID string, testRunID string) (*domain.TestRunAnalysis, error) {
 	analysis := &domain.TestRunAnalysis{
@@ -31,18 +54,61 @@ func (s *FlakyDetectionService) AnalyzeTestRun(ctx context.Context, projectID st
 		AnalyzedAt: time.Now(),
 	}
 
-	// Get all unique test names from recent history
-	since := time.Now().Add(-s.config.AnalysisWindow)
-	testNames, err := s.getUniqueTestNames(ctx, projectID, since)
+	config, err := s.ProjectConfig(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get all tests that ran recently
+	since := time.Now().Add(-config.AnalysisWindow)
+	tests, err := s.repo.GetRecentTests(ctx, projectID, since)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get test names: %w", err)
+		return nil, fmt.Errorf("failed to get tests: %w", err)
 	}
 
-	analysis.TotalTests = len(testNames)
+	return s.analyzeTests(ctx, analysis, tests, config, since)
+}
+
+// AnalyzeTestRuns analyzes only the tests that appeared in the given test runs, so a
+// completed run does not re-examine every test of its project
+func (s *FlakyDetectionService) AnalyzeTestRuns(ctx context.Context, projectID string, testRunIDs []uint) (*domain.TestRunAnalysis, error) {
+	ids := make([]string, len(testRunIDs))
+	for i, id := range testRunIDs {
+		ids[i] = strconv.FormatUint(uint64(id), 10)
+	}
+
+	analysis := &domain.TestRunAnalysis{
+		TestRunID:  strings.Join(ids, ","),
+		ProjectID:  projectID,
+		AnalyzedAt: time.Now(),
+	}
+
+	config, err := s.ProjectConfig(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	tests, err := s.repo.GetTestsInRuns(ctx, projectID, testRunIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tests: %w", err)
+	}
+
+	return s.analyzeTests(ctx, analysis, tests, config, time.Now().Add(-config.AnalysisWindow))
+}
+
+// analyzeTests analyzes the history of each test since a given time and saves the analysis
+func (s *FlakyDetectionService) analyzeTests(ctx context.Context, analysis *domain.TestRunAnalysis, tests []domain.TestIdentity, config domain.FlakyTestDetectionConfig, since time.Time) (*domain.TestRunAnalysis, error) {
+	projectID := analysis.ProjectID
+	analysis.TotalTests = len(tests)
+
+	scorer, err := domain.NewFlakinessScorer(config)
+	if err != nil {
+		return nil, err
+	}
 
 	// Analyze each test
-	for _, testName := range testNames {
-		result, err := s.analyzeTest(ctx, projectID, testName, since)
+	for _, test := range tests {
+		result, err := s.analyzeTest(ctx, projectID, test, config, scorer, since)
 		if err != nil {
 			// Log error but continue with other tests
 			continue
@@ -71,6 +137,20 @@ func (s *FlakyDetectionService) GetFlakyTests(ctx context.Context, projectID str
 	return s.repo.FindFlakyTestsByProject(ctx, projectID, domain.StatusActive)
 }
 
+// ListFlakyTests returns the flaky tests of a project with a status, most flaky first. An
+// empty status returns them all.
+func (s *FlakyDetectionService) ListFlakyTests(ctx context.Context, projectID string, status domain.FlakyTestStatus) ([]*domain.FlakyTest, error) {
+	if status != "" && !status.IsValid() {
+		return nil, fmt.Errorf("invalid flaky test status: %s", status)
+	}
+	return s.repo.FindFlakyTestsByProject(ctx, projectID, status)
+}
+
+// GetFlakyTest returns a flaky test by the ID of its test case
+func (s *FlakyDetectionService) GetFlakyTest(ctx context.Context, testID string) (*domain.FlakyTest, error) {
+	return s.repo.GetFlakyTest(ctx, testID)
+}
+
 // MarkTestResolved marks a flaky test as resolved
 func (s *FlakyDetectionService) MarkTestResolved(ctx context.Context, testID string) error {
 	return s.repo.UpdateFlakyTestStatus(ctx, testID, domain.StatusResolved)
@@ -92,34 +172,25 @@ const (
 	actionResolved   analysisAction = "resolved"
 )
 
+// maxFlipEvidence bounds the flips kept as evidence of a flaky test
+const maxFlipEvidence = 10
+
 type testAnalysisResult struct {
 	testID string
 	action analysisAction
 }
 
-func (s *FlakyDetectionService) analyzeTest(ctx context.Context, projectID string, testName string, since time.Time) (*testAnalysisResult, error) {
+func (s *FlakyDetectionService) analyzeTest(ctx context.Context, projectID string, test domain.TestIdentity, config domain.FlakyTestDetectionConfig, scorer domain.FlakinessScorer, since time.Time) (*testAnalysisResult, error) {
 	// Get test execution history
-	history, err := s.repo.GetTestRunHistory(ctx, projectID, testName, since)
+	history, err := s.repo.GetTestRunHistory(ctx, test.TestCaseID, since)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get test history: %w", err)
 	}
 
-	// Not enough runs to determine flakiness
-	if len(history) < s.config.MinimumRuns {
-		return &testAnalysisResult{action: actionNone}, nil
-	}
-
-	// Calculate failure rate
-	failureCount := 0
-	consecutivePasses := 0
+	// Find the latest failure. A pass that needed a retry counts as a failure.
 	var lastFailure *domain.TestFailureInfo
-	var suiteName, packageName string
-
 	for _, exec := range history {
-		if exec.Status == "failed" {
-			failureCount++
-			consecutivePasses = 0
-
+		if exec.Status == "failed" || exec.FlakyInRun {
 			if lastFailure == nil || exec.ExecutedAt.After(lastFailure.FailedAt) {
 				lastFailure = &domain.TestFailureInfo{
 					TestRunID:    exec.TestRunID,
@@ -129,18 +200,23 @@ func (s *FlakyDetectionService) analyzeTest(ctx context.Context, projectID strin
 					Environment:  fmt.Sprintf("%v", exec.Environment),
 				}
 			}
-		} else if exec.Status == "passed" {
-			consecutivePasses++
 		}
+	}
 
-		// Capture suite and package name from any execution
-		if suiteName == "" && exec.SuiteName != "" {
-			suiteName = exec.SuiteName
-		}
+	// A test that both passed and failed on the same code is flaky, whatever its failure rate
+	summary := domain.SummarizeHistory(history)
+
+	// Not enough runs to determine flakiness, unless a flip already proved it
+	if summary.Executions < config.MinimumRuns && len(summary.Flips) == 0 {
+		return &testAnalysisResult{action: actionNone}, nil
 	}
 
-	failureRate := float64(failureCount) / float64(len(history))
-	testID := generateTestID(projectID, testName)
+	flips := summary.Flips
+	if len(flips) > maxFlipEvidence {
+		flips = flips[:maxFlipEvidence]
+	}
+	failureCount := summary.Failures
+	testID := strconv.FormatUint(uint64(test.TestCaseID), 10)
 
 	// Check if test is already tracked
 	existingFlaky, err := s.repo.GetFlakyTest(ctx, testID)
@@ -148,19 +224,20 @@ func (s *FlakyDetectionService) analyzeTest(ctx context.Context, projectID strin
 		return nil, fmt.Errorf("failed to get existing flaky test: %w", err)
 	}
 
-	// Determine action based on failure rate and existing status
-	if failureRate >= s.config.MinFailureRate && failureRate <= s.config.MaxFailureRate {
+	// Determine action based on flips, failure rate, flake score and existing status
+	flakeScore := scorer.Score(history, summary, time.Now())
+	if config.IsFlaky(summary, flakeScore) {
 		// Test is flaky
-		flakeScore := s.calculateFlakeScore(failureRate, len(history), consecutivePasses)
 
 		if existingFlaky == nil {
 			// New flaky test
 			flaky := &domain.FlakyTest{
 				TestID:       testID,
+				TestCaseID:   test.TestCaseID,
 				ProjectID:    projectID,
-				TestName:     testName,
-				SuiteName:    suiteName,
-				PackageName:  packageName,
+				TestName:     test.TestName,
+				SuiteName:    test.SuiteName,
+				PackageName:  test.PackageName,
 				FirstSeen:    time.Now(),
 				LastSeen:     time.Now(),
 				TotalRuns:    len(history),
@@ -169,6 +246,7 @@ func (s *FlakyDetectionService) analyzeTest(ctx context.Context, projectID strin
 				Status:       domain.StatusActive,
 				Metadata: domain.FlakyTestMetadata{
 					RecentFailures: []domain.TestFailureInfo{},
+					Flips:          flips,
 				},
 			}
 
@@ -188,6 +266,7 @@ func (s *FlakyDetectionService) analyzeTest(ctx context.Context, projectID strin
 			existingFlaky.FailureCount = failureCount
 			existingFlaky.FlakeScore = flakeScore
 			existingFlaky.Status = domain.StatusActive
+			existingFlaky.Metadata.Flips = flips
 
 			if lastFailure != nil {
 				// Add to recent failures, keep only last 10
@@ -205,7 +284,7 @@ func (s *FlakyDetectionService) analyzeTest(ctx context.Context, projectID strin
 		}
 	} else if existingFlaky != nil && existingFlaky.Status == domain.StatusActive {
 		// Test is no longer flaky
-		if consecutivePasses >= s.config.ConsecutivePassesForResolution {
+		if summary.ConsecutivePasses >= config.ConsecutivePassesForResolution {
 			existingFlaky.Status = domain.StatusResolved
 			if err := s.repo.SaveFlakyTest(ctx, existingFlaky); err != nil {
 				return nil, fmt.Errorf("failed to update resolved test: %w", err)
@@ -216,47 +295,3 @@ func (s *FlakyDetectionService) analyzeTest(ctx context.Context, projectID strin
 
 	return &testAnalysisResult{action: actionNone}, nil
 }
-
-func (s *FlakyDetectionService) calculateFlakeScore(failureRate float64, totalRuns int, consecutivePasses int) float64 {
-	// Base score is the failure rate
-	score := failureRate
-
-	// Adjust based on total runs (more runs = more confidence)
-	runConfidence := math.Min(float64(totalRuns)/100.0, 1.0)
-	score = score * (0.7 + 0.3*runConfidence)
-
-	// Adjust based on recent stability
-	if consecutivePasses > 5 {
-		stabilityFactor := math.Min(float64(consecutivePasses)/20.0, 0.5)
-		score = score * (1.0 - stabilityFactor)
-	}
-
-	return math.Min(math.Max(score, 0.0), 1.0)
-}
-
-func (s *FlakyDetectionService) getUniqueTestNames(ctx context.Context, projectID string, since time.Time) ([]string, error) {
-	return s.repo.GetUniqueTestNames(ctx, projectID, since)
-}
-
-func generateTestID(projectID, testName string) string {
-	return fmt.Sprintf("%s_%s", projectID, testName)
-}
-
-// GetFlakyTestTrends returns trend data for flaky tests over time
-func (s *FlakyDetectionService) GetFlakyTestTrends(ctx context.Context, projectID string, period time.Duration) ([]FlakyTestTrend, error) {
-	// Get all analyses for the project within the period
-	// endTime := time.Now()
-	// startTime := endTime.Add(-period)
-
-	// This would be implemented by the repository
-	// For now, return empty trends
-	return []FlakyTestTrend{}, nil
-}
-
-// FlakyTestTrend represents flaky test counts over time
-type FlakyTestTrend struct {
-	Date          time.Time
-	ActiveCount   int
-	NewCount      int
-	ResolvedCount int
-}
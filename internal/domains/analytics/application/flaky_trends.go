@@ -0,0 +1,156 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/analytics/domain"
+)
+
+// trendDay is the length of a day of flaky test trends, which are kept in UTC
+const trendDay = 24 * time.Hour
+
+// MaxHistoryDays is the most days of history that trends, backfills and strategy evaluations
+// may be asked to cover
+const MaxHistoryDays = 365
+
+// GetFlakyTestTrends returns the flaky test counts of a project for every day of a period ending
+// today, oldest first. Days without an analysis keep the active count of the day before.
+func (s *FlakyDetectionService) GetFlakyTestTrends(ctx context.Context, projectID string, period time.Duration) ([]domain.FlakyTestTrend, error) {
+	days := max(int((period+trendDay-1)/trendDay), 1)
+	start := domain.TrendDate(time.Now()).AddDate(0, 0, 1-days)
+
+	snapshots, err := s.repo.GetFlakyTestTrends(ctx, projectID, start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flaky test trends: %w", err)
+	}
+
+	trends := make([]domain.FlakyTestTrend, days)
+	active, next := 0, 0
+	for i := range trends {
+		date := start.AddDate(0, 0, i)
+		for next < len(snapshots) && !snapshots[next].Date.After(date) {
+			if snapshots[next].Date.Equal(date) {
+				trends[i] = snapshots[next]
+			}
+			active = snapshots[next].ActiveCount
+			next++
+		}
+		trends[i].Date = date
+		trends[i].ActiveCount = active
+	}
+
+	return trends, nil
+}
+
+// BackfillFlakyTestTrends rebuilds the flaky test counts of a project for the given number of days
+// ending today from its spec runs, replacing those recorded by analyses. The project's detection
+// configuration is replayed over the history of each test at the end of every day. Replay starts
+// an analysis window before the first day, so tests that were already flaky then do not count as
+// new on it. It returns the number of days rebuilt.
+func (s *FlakyDetectionService) BackfillFlakyTestTrends(ctx context.Context, projectID string, days int) (int, error) {
+	if days <= 0 {
+		return 0, fmt.Errorf("days must be positive")
+	}
+
+	config, err := s.ProjectConfig(ctx, projectID)
+	if err != nil {
+		return 0, err
+	}
+	scorer, err := domain.NewFlakinessScorer(config)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	today := domain.TrendDate(now)
+	start := today.AddDate(0, 0, 1-days)
+	replayFrom := domain.TrendDate(start.Add(-config.AnalysisWindow))
+	since := replayFrom.Add(-config.AnalysisWindow)
+
+	tests, err := s.repo.GetRecentTests(ctx, projectID, since)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get tests: %w", err)
+	}
+
+	trends := make([]domain.FlakyTestTrend, days)
+	for i := range trends {
+		trends[i].Date = start.AddDate(0, 0, i)
+	}
+
+	for _, test := range tests {
+		history, err := s.repo.GetTestRunHistory(ctx, test.TestCaseID, since)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get test history: %w", err)
+		}
+
+		flaky := false
+		for date := replayFrom; !date.After(today); date = date.AddDate(0, 0, 1) {
+			end := date.Add(trendDay)
+			if end.After(now) {
+				end = now
+			}
+
+			var action analysisAction
+			window := executionsBetween(history, end.Add(-config.AnalysisWindow), end)
+			flaky, action = replayAnalysis(window, config, scorer, flaky, end)
+
+			if date.Before(start) {
+				continue
+			}
+			trend := &trends[int(date.Sub(start)/trendDay)]
+			switch action {
+			case actionNewFlaky:
+				trend.NewCount++
+			case actionResolved:
+				trend.ResolvedCount++
+			}
+			if flaky {
+				trend.ActiveCount++
+			}
+		}
+	}
+
+	if err := s.repo.SaveFlakyTestTrends(ctx, projectID, trends); err != nil {
+		return 0, fmt.Errorf("failed to save flaky test trends: %w", err)
+	}
+
+	return len(trends), nil
+}
+
+// replayAnalysis decides, the way an analysis at the given time would, whether a test that was or
+// was not flaky is flaky given its executions in the analysis window
+func replayAnalysis(window []domain.TestExecutionResult, config domain.FlakyTestDetectionConfig, scorer domain.FlakinessScorer, flaky bool, now time.Time) (bool, analysisAction) {
+	// Tests that did not run are not analyzed
+	if len(window) == 0 {
+		return flaky, actionNone
+	}
+
+	summary := domain.SummarizeHistory(window)
+	if summary.Executions < config.MinimumRuns && len(summary.Flips) == 0 {
+		return flaky, actionNone
+	}
+
+	switch {
+	case config.IsFlaky(summary, scorer.Score(window, summary, now)):
+		if flaky {
+			return true, actionStillFlaky
+		}
+		return true, actionNewFlaky
+	case flaky && summary.ConsecutivePasses >= config.ConsecutivePassesForResolution:
+		return false, actionResolved
+	}
+	return flaky, actionNone
+}
+
+// executionsBetween returns the executions from a given time up to, but excluding, another
+func executionsBetween(history []domain.TestExecutionResult, from, to time.Time) []domain.TestExecutionResult {
+	var executions []domain.TestExecutionResult
+	for _, exec := range history {
+		if !exec.ExecutedAt.Before(from) && exec.ExecutedAt.Before(to) {
+			executions = append(executions, exec)
+		}
+	}
+	return executions
+}
@@ -0,0 +1,128 @@
+package application_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/analytics/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/analytics/domain"
+	"github.com/guidewire-oss/fern-platform/internal/testhelpers"
+)
+
+var _ = Describe("Flaky test trends", Label("unit", "application", "analytics"), func() {
+	var (
+		mockRepo *testhelpers.MockFlakyDetectionRepository
+		service  *application.FlakyDetectionService
+		ctx      context.Context
+		today    time.Time
+	)
+
+	BeforeEach(func() {
+		mockRepo = new(testhelpers.MockFlakyDetectionRepository)
+		service = application.NewFlakyDetectionService(mockRepo, domain.DefaultFlakyTestDetectionConfig())
+		ctx = context.Background()
+		today = domain.TrendDate(time.Now())
+	})
+
+	Describe("GetFlakyTestTrends", func() {
+		It("should return every day of the period, carrying the active count into days without a snapshot", func() {
+			start := today.AddDate(0, 0, -4)
+			mockRepo.On("GetFlakyTestTrends", ctx, "proj-1", start).Return([]domain.FlakyTestTrend{
+				{Date: start.AddDate(0, 0, -2), ActiveCount: 3, NewCount: 1},
+				{Date: start.AddDate(0, 0, 1), ActiveCount: 5, NewCount: 2},
+				{Date: start.AddDate(0, 0, 3), ActiveCount: 4, ResolvedCount: 1},
+			}, nil)
+
+			trends, err := service.GetFlakyTestTrends(ctx, "proj-1", 5*24*time.Hour)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(trends).To(Equal([]domain.FlakyTestTrend{
+				{Date: start, ActiveCount: 3},
+				{Date: start.AddDate(0, 0, 1), ActiveCount: 5, NewCount: 2},
+				{Date: start.AddDate(0, 0, 2), ActiveCount: 5},
+				{Date: start.AddDate(0, 0, 3), ActiveCount: 4, ResolvedCount: 1},
+				{Date: today, ActiveCount: 4},
+			}))
+		})
+
+		It("should return empty days for a project without snapshots", func() {
+			mockRepo.On("GetFlakyTestTrends", ctx, "proj-1", today.AddDate(0, 0, -1)).Return([]domain.FlakyTestTrend{}, nil)
+
+			// A period of a day and a half covers two days
+			trends, err := service.GetFlakyTestTrends(ctx, "proj-1", 36*time.Hour)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(trends).To(Equal([]domain.FlakyTestTrend{
+				{Date: today.AddDate(0, 0, -1)},
+				{Date: today},
+			}))
+		})
+	})
+
+	Describe("BackfillFlakyTestTrends", func() {
+		var saved []domain.FlakyTestTrend
+
+		// flip builds a pass and a failure of a test on one commit, an hour apart
+		flip := func(testCaseID uint, at time.Time) []domain.TestExecutionResult {
+			commit := map[string]string{"commit": "abc"}
+			return []domain.TestExecutionResult{
+				{TestCaseID: testCaseID, TestRunID: "run-2", Status: "failed", ExecutedAt: at.Add(time.Hour), Environment: commit},
+				{TestCaseID: testCaseID, TestRunID: "run-1", Status: "passed", ExecutedAt: at, Environment: commit},
+			}
+		}
+
+		BeforeEach(func() {
+			saved = nil
+			mockRepo.On("SaveFlakyTestTrends", ctx, "proj-1", mock.Anything).
+				Run(func(args mock.Arguments) { saved = args.Get(2).([]domain.FlakyTestTrend) }).
+				Return(nil)
+		})
+
+		It("should replace every day of the period with counts replayed from the tests' histories", func() {
+			start := today.AddDate(0, 0, -2)
+			mockRepo.On("GetRecentTests", ctx, "proj-1", mock.Anything).Return([]domain.TestIdentity{{TestCaseID: 1}, {TestCaseID: 2}}, nil)
+			// Found flaky on the first day
+			mockRepo.On("GetTestRunHistory", ctx, uint(1), mock.Anything).Return(flip(1, start.Add(time.Hour)), nil)
+			// Already flaky before the first day, so it is not new on it
+			mockRepo.On("GetTestRunHistory", ctx, uint(2), mock.Anything).Return(flip(2, start.AddDate(0, 0, -3)), nil)
+
+			days, err := service.BackfillFlakyTestTrends(ctx, "proj-1", 3)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(days).To(Equal(3))
+			// Days without changes are saved too, so counts recorded by analyses are replaced
+			Expect(saved).To(Equal([]domain.FlakyTestTrend{
+				{Date: start, ActiveCount: 2, NewCount: 1},
+				{Date: start.AddDate(0, 0, 1), ActiveCount: 2},
+				{Date: today, ActiveCount: 2},
+			}))
+		})
+
+		It("should zero the days of a project whose tests did not run", func() {
+			mockRepo.On("GetRecentTests", ctx, "proj-1", mock.Anything).Return([]domain.TestIdentity{}, nil)
+
+			_, err := service.BackfillFlakyTestTrends(ctx, "proj-1", 2)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(saved).To(Equal([]domain.FlakyTestTrend{
+				{Date: today.AddDate(0, 0, -1)},
+				{Date: today},
+			}))
+		})
+
+		It("should reject invalid project settings without saving", func() {
+			service.SetProjectSettings(func(context.Context, string) (map[string]interface{}, bool) {
+				return map[string]interface{}{"minimumRuns": 0.0}, true
+			})
+
+			_, err := service.BackfillFlakyTestTrends(ctx, "proj-1", 3)
+
+			Expect(err).To(MatchError(domain.ErrInvalidSettings))
+			mockRepo.AssertNotCalled(GinkgoT(), "SaveFlakyTestTrends", mock.Anything, mock.Anything, mock.Anything)
+		})
+	})
+})
@@ -0,0 +1,134 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/analytics/domain"
+)
+
+// EvaluateStrategies compares the built-in scoring strategies on a project's history, using the
+// project's thresholds. Every test that ran in the holdout period is scored from its history
+// before the period, and counts as flaky if it flipped during it.
+func (s *FlakyDetectionService) EvaluateStrategies(ctx context.Context, projectID string, holdout time.Duration) (*domain.StrategyEvaluation, error) {
+	config, err := s.ProjectConfig(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-holdout)
+	since := cutoff.Add(-config.AnalysisWindow)
+
+	scorers := make([]domain.FlakinessScorer, len(domain.ScoringStrategies))
+	for i, strategy := range domain.ScoringStrategies {
+		strategyConfig := config
+		strategyConfig.Strategy = strategy
+		if scorers[i], err = domain.NewFlakinessScorer(strategyConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	tests, err := s.repo.GetRecentTests(ctx, projectID, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tests: %w", err)
+	}
+
+	evaluation := &domain.StrategyEvaluation{
+		ProjectID:  projectID,
+		Strategy:   config.Strategy,
+		Cutoff:     cutoff,
+		Strategies: make([]domain.StrategyResult, len(scorers)),
+	}
+	for i, strategy := range domain.ScoringStrategies {
+		evaluation.Strategies[i].Strategy = strategy
+	}
+	scores := make([][]float64, len(scorers))
+	var flipped []bool
+
+	for _, test := range tests {
+		history, err := s.repo.GetTestRunHistory(ctx, test.TestCaseID, since)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get test history: %w", err)
+		}
+
+		var before, after []domain.TestExecutionResult
+		for _, exec := range history {
+			if exec.ExecutedAt.Before(cutoff) {
+				before = append(before, exec)
+			} else {
+				after = append(after, exec)
+			}
+		}
+		if len(before) == 0 || len(after) == 0 {
+			continue
+		}
+
+		summary := domain.SummarizeHistory(before)
+		afterFlips, _ := domain.DetectFlips(after)
+		isFlaky := len(afterFlips) > 0
+
+		evaluation.TestsEvaluated++
+		if isFlaky {
+			evaluation.FlakyTests++
+		}
+		flipped = append(flipped, isFlaky)
+
+		for i, scorer := range scorers {
+			score := scorer.Score(before, summary, cutoff)
+			scores[i] = append(scores[i], score)
+
+			result := &evaluation.Strategies[i]
+			if config.IsFlaky(summary, score) {
+				result.Flagged++
+				if isFlaky {
+					result.TruePositives++
+				} else {
+					result.FalsePositives++
+				}
+			}
+		}
+	}
+
+	for i := range evaluation.Strategies {
+		result := &evaluation.Strategies[i]
+		if result.Flagged > 0 {
+			result.Precision = float64(result.TruePositives) / float64(result.Flagged)
+		}
+		if evaluation.FlakyTests > 0 {
+			result.Recall = float64(result.TruePositives) / float64(evaluation.FlakyTests)
+		}
+		result.AUC = rankAUC(scores[i], flipped)
+	}
+
+	return evaluation, nil
+}
+
+// rankAUC is the chance that a positive scored higher than a negative, counting ties as half,
+// or nil without both positives and negatives
+func rankAUC(scores []float64, positive []bool) *float64 {
+	var pairs, wins float64
+	for i := range scores {
+		if !positive[i] {
+			continue
+		}
+		for j := range scores {
+			if positive[j] {
+				continue
+			}
+			pairs++
+			switch {
+			case scores[i] > scores[j]:
+				wins++
+			case scores[i] == scores[j]:
+				wins += 0.5
+			}
+		}
+	}
+	if pairs == 0 {
+		return nil
+	}
+
+	auc := wins / pairs
+	return &auc
+}
@@ -0,0 +1,140 @@
+package application_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/analytics/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/analytics/domain"
+	"github.com/guidewire-oss/fern-platform/internal/testhelpers"
+)
+
+var _ = Describe("EvaluateStrategies", Label("unit", "application", "analytics"), func() {
+	var (
+		mockRepo *testhelpers.MockFlakyDetectionRepository
+		service  *application.FlakyDetectionService
+		ctx      context.Context
+		cutoff   time.Time
+	)
+
+	// executions builds a test's executions from outcomes listed newest first, P passed and F
+	// failed, an hour apart: ending an hour before the cutoff, or starting an hour after it. Each
+	// ran on its own commit unless one is given.
+	executions := func(testCaseID uint, outcomes, commit string, beforeCutoff bool) []domain.TestExecutionResult {
+		result := make([]domain.TestExecutionResult, len(outcomes))
+		for i, outcome := range outcomes {
+			executedAt := cutoff.Add(time.Duration(len(outcomes)-i) * time.Hour)
+			if beforeCutoff {
+				executedAt = cutoff.Add(-time.Duration(i+1) * time.Hour)
+			}
+			status := "passed"
+			if outcome == 'F' {
+				status = "failed"
+			}
+			runID := fmt.Sprintf("run-%d-%t-%d", testCaseID, beforeCutoff, i)
+			code := commit
+			if code == "" {
+				code = "commit-" + runID
+			}
+			result[i] = domain.TestExecutionResult{
+				TestCaseID:  testCaseID,
+				TestRunID:   runID,
+				Status:      status,
+				ExecutedAt:  executedAt,
+				Environment: map[string]string{"commit": code},
+			}
+		}
+		return result
+	}
+
+	// history builds a test's history, newest first, from its outcomes before and after the
+	// cutoff. After the cutoff it runs on one commit when it flips there.
+	history := func(testCaseID uint, before, after string, flipsAfter bool) []domain.TestExecutionResult {
+		commit := ""
+		if flipsAfter {
+			commit = "abc"
+		}
+		return append(executions(testCaseID, after, commit, false), executions(testCaseID, before, "", true)...)
+	}
+
+	BeforeEach(func() {
+		mockRepo = new(testhelpers.MockFlakyDetectionRepository)
+		service = application.NewFlakyDetectionService(mockRepo, domain.DefaultFlakyTestDetectionConfig())
+		service.SetProjectSettings(func(context.Context, string) (map[string]interface{}, bool) {
+			return map[string]interface{}{"minimumRuns": 3.0}, true
+		})
+		ctx = context.Background()
+		cutoff = time.Now().Add(-24 * time.Hour)
+	})
+
+	It("should score tests on their history before the cutoff against their flips after it", func() {
+		mockRepo.On("GetRecentTests", ctx, "proj-1", mock.Anything).Return([]domain.TestIdentity{
+			{TestCaseID: 1}, {TestCaseID: 2}, {TestCaseID: 3}, {TestCaseID: 4},
+		}, nil)
+		// Fails often and flips after the cutoff
+		mockRepo.On("GetTestRunHistory", ctx, uint(1), mock.Anything).Return(history(1, "PFF", "PF", true), nil)
+		// Passes throughout
+		mockRepo.On("GetTestRunHistory", ctx, uint(2), mock.Anything).Return(history(2, "PPP", "P", false), nil)
+		// Fails now and then but does not flip after the cutoff
+		mockRepo.On("GetTestRunHistory", ctx, uint(3), mock.Anything).Return(history(3, "PPF", "PP", false), nil)
+		// Only ran after the cutoff, so it cannot be scored
+		mockRepo.On("GetTestRunHistory", ctx, uint(4), mock.Anything).Return(history(4, "", "PF", true), nil)
+
+		evaluation, err := service.EvaluateStrategies(ctx, "proj-1", 24*time.Hour)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(evaluation.ProjectID).To(Equal("proj-1"))
+		Expect(evaluation.Strategy).To(Equal(domain.ScoringHeuristic))
+		Expect(evaluation.Cutoff).To(BeTemporally("~", cutoff, time.Minute))
+		Expect(evaluation.TestsEvaluated).To(Equal(3))
+		Expect(evaluation.FlakyTests).To(Equal(1))
+
+		Expect(evaluation.Strategies).To(HaveLen(len(domain.ScoringStrategies)))
+		for i, result := range evaluation.Strategies {
+			Expect(result.Strategy).To(Equal(domain.ScoringStrategies[i]))
+			// With a score threshold of 0 every strategy flags the tests failing within the bounds
+			Expect(result.Flagged).To(Equal(2))
+			Expect(result.TruePositives).To(Equal(1))
+			Expect(result.FalsePositives).To(Equal(1))
+			Expect(result.Precision).To(Equal(0.5))
+			Expect(result.Recall).To(Equal(1.0))
+			Expect(result.AUC).NotTo(BeNil())
+		}
+
+		// The heuristic ranks the flaky test above both others
+		Expect(*evaluation.Strategies[0].AUC).To(Equal(1.0))
+		// The beta scores of a 1 in 3 and a 2 in 3 failure rate tie, which counts as half
+		Expect(evaluation.Strategies[1].Strategy).To(Equal(domain.ScoringBeta))
+		Expect(*evaluation.Strategies[1].AUC).To(Equal(0.75))
+	})
+
+	It("should leave the AUC out when no test flipped", func() {
+		mockRepo.On("GetRecentTests", ctx, "proj-1", mock.Anything).Return([]domain.TestIdentity{{TestCaseID: 1}}, nil)
+		mockRepo.On("GetTestRunHistory", ctx, uint(1), mock.Anything).Return(history(1, "PFP", "PP", false), nil)
+
+		evaluation, err := service.EvaluateStrategies(ctx, "proj-1", 24*time.Hour)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(evaluation.FlakyTests).To(BeZero())
+		for _, result := range evaluation.Strategies {
+			Expect(result.AUC).To(BeNil())
+			Expect(result.Recall).To(BeZero())
+		}
+	})
+
+	It("should reject invalid project settings", func() {
+		service.SetProjectSettings(func(context.Context, string) (map[string]interface{}, bool) {
+			return map[string]interface{}{"strategy": "magic"}, true
+		})
+
+		_, err := service.EvaluateStrategies(ctx, "proj-1", 24*time.Hour)
+
+		Expect(err).To(MatchError(domain.ErrInvalidSettings))
+		mockRepo.AssertNotCalled(GinkgoT(), "GetRecentTests", mock.Anything, mock.Anything, mock.Anything)
+	})
+})
@@ -6,7 +6,8 @@ import (
 
 // FlakyTest represents a test that has been identified as flaky
 type FlakyTest struct {
-	TestID       string
+	TestID       string // ID of the test case, as a string
+	TestCaseID   uint
 	ProjectID    string
 	TestName     string
 	SuiteName    string
@@ -20,6 +21,29 @@ type FlakyTest struct {
 	Metadata     FlakyTestMetadata
 }
 
+// Severity grades how flaky the test is from its flake score
+func (f *FlakyTest) Severity() string {
+	if f.FlakeScore < 0.1 {
+		return "low"
+	} else if f.FlakeScore < 0.3 {
+		return "medium"
+	} else if f.FlakeScore < 0.6 {
+		return "high"
+	}
+	return "critical"
+}
+
+// LastErrorMessage returns the error of the test's most recent failure, if known
+func (f *FlakyTest) LastErrorMessage() string {
+	if len(f.Metadata.RecentFailures) > 0 {
+		return f.Metadata.RecentFailures[len(f.Metadata.RecentFailures)-1].ErrorMessage
+	}
+	if len(f.Metadata.FailurePatterns) > 0 {
+		return f.Metadata.FailurePatterns[0]
+	}
+	return ""
+}
+
 // FlakyTestStatus represents the current status of a flaky test
 type FlakyTestStatus string
 
@@ -29,14 +53,33 @@ const (
 	StatusIgnored  FlakyTestStatus = "ignored"  // Manually ignored
 )
 
+// IsValid reports whether a flaky test can have the status
+func (s FlakyTestStatus) IsValid() bool {
+	switch s {
+	case StatusActive, StatusResolved, StatusIgnored:
+		return true
+	}
+	return false
+}
+
 // FlakyTestMetadata contains additional information about the flaky test
 type FlakyTestMetadata struct {
 	FailurePatterns []string          // Common failure messages
 	Environments    []string          // Environments where it fails
 	RecentFailures  []TestFailureInfo // Recent failure details
+	Flips           []FlipEvidence    // Code the test both passed and failed on, most recent first
 	Tags            []string          // User-defined tags
 }
 
+// FlipEvidence shows a test both passing and failing on the same code: in different runs of
+// one commit, or within one run when it passed on a retry
+type FlipEvidence struct {
+	Commit       string   `json:"commit,omitempty"` // Empty for a flip within one run of unknown commit
+	PassedRunIDs []string `json:"passedRunIds"`     // Test runs in which the test passed
+	FailedRunIDs []string `json:"failedRunIds"`     // Test runs in which the test failed
+	InRun        bool     `json:"inRun"`            // The test passed on a retry after failing within a run
+}
+
 // TestFailureInfo contains information about a specific test failure
 type TestFailureInfo struct {
 	TestRunID    string
@@ -57,6 +100,20 @@ type TestRunAnalysis struct {
 	ResolvedFlaky []string // Test IDs no longer flaky
 }
 
+// FlakyTestTrend counts a project's flaky tests on one day
+type FlakyTestTrend struct {
+	Date          time.Time `json:"date"`          // Midnight UTC of the day
+	ActiveCount   int       `json:"activeCount"`   // Active flaky tests at the end of the day
+	NewCount      int       `json:"newCount"`      // Tests found flaky during the day
+	ResolvedCount int       `json:"resolvedCount"` // Flaky tests resolved during the day
+}
+
+// TrendDate returns the day a time falls on, as midnight UTC
+func TrendDate(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
 // FlakyTestDetectionConfig contains configuration for flaky test detection
 type FlakyTestDetectionConfig struct {
 	// Minimum number of runs before a test can be considered flaky
@@ -74,6 +131,27 @@ type FlakyTestDetectionConfig struct {
 
 	// Number of consecutive passes required to mark as resolved
 	ConsecutivePassesForResolution int
+
+	// Strategy scoring how flaky a test is
+	Strategy ScoringStrategy
+
+	// Minimum flake score for a test failing within the failure rate bounds to be flaky.
+	// Tests that flipped are flaky whatever their score.
+	ScoreThreshold float64
+
+	// Time for an execution's weight to halve under the decayed strategy
+	DecayHalfLife time.Duration
+
+	// Passes and failures assumed before any run under the beta strategy
+	PriorPasses   float64
+	PriorFailures float64
+
+	// How long to wait for further completed runs of a project before analyzing them together,
+	// so a burst of shards is analyzed once
+	AnalysisDebounce time.Duration
+
+	// Longest a completed run waits to be analyzed while further runs keep completing
+	MaxAnalysisDelay time.Duration
 }
 
 // DefaultFlakyTestDetectionConfig returns the default configuration
@@ -84,5 +162,27 @@ func DefaultFlakyTestDetectionConfig() FlakyTestDetectionConfig {
 		MaxFailureRate:                 0.95,               // 95%
 		AnalysisWindow:                 7 * 24 * time.Hour, // 7 days
 		ConsecutivePassesForResolution: 20,
+		Strategy:                       ScoringHeuristic,
+		ScoreThreshold:                 0,
+		DecayHalfLife:                  3 * 24 * time.Hour, // 3 days
+		PriorPasses:                    1,
+		PriorFailures:                  1,
+		AnalysisDebounce:               30 * time.Second,
+		MaxAnalysisDelay:               5 * time.Minute,
+	}
+}
+
+// IsFlaky decides whether a test with the given history summary and flake score is flaky: it
+// flipped, or it has enough runs, fails within the failure rate bounds and scores at least the
+// threshold
+func (c FlakyTestDetectionConfig) IsFlaky(summary HistorySummary, score float64) bool {
+	if len(summary.Flips) > 0 {
+		return true
+	}
+	if summary.Executions < c.MinimumRuns {
+		return false
 	}
+
+	failureRate := summary.FailureRate()
+	return failureRate >= c.MinFailureRate && failureRate <= c.MaxFailureRate && score >= c.ScoreThreshold
 }
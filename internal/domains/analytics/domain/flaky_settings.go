@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// FlakyDetectionSettingsKey is the project setting holding a project's flaky detection settings
+const FlakyDetectionSettingsKey = "flakyDetection"
+
+// ErrInvalidSettings is returned for flaky detection settings that cannot be applied
+var ErrInvalidSettings = errors.New("invalid flaky detection settings")
+
+// WithSettings overrides a configuration with a project's flaky detection settings. Settings
+// left out keep their configured value. Durations are given in days.
+//
+//	{"strategy": "beta", "scoreThreshold": 0.3, "minimumRuns": 5, "minFailureRate": 0.05,
+//	 "maxFailureRate": 0.95, "analysisWindowDays": 14, "consecutivePassesForResolution": 20,
+//	 "decayHalfLifeDays": 3, "priorPasses": 1, "priorFailures": 1}
+func (c FlakyTestDetectionConfig) WithSettings(settings map[string]interface{}) (FlakyTestDetectionConfig, error) {
+	for key, value := range settings {
+		if key == "strategy" {
+			strategy, ok := value.(string)
+			if !ok {
+				return c, fmt.Errorf("%w: strategy must be a string", ErrInvalidSettings)
+			}
+			c.Strategy = ScoringStrategy(strategy)
+			continue
+		}
+
+		number, ok := settingNumber(value)
+		if !ok {
+			return c, fmt.Errorf("%w: %s must be a number", ErrInvalidSettings, key)
+		}
+
+		switch key {
+		case "scoreThreshold":
+			c.ScoreThreshold = number
+		case "minimumRuns":
+			c.MinimumRuns = int(number)
+		case "minFailureRate":
+			c.MinFailureRate = number
+		case "maxFailureRate":
+			c.MaxFailureRate = number
+		case "analysisWindowDays":
+			c.AnalysisWindow = time.Duration(number * float64(24*time.Hour))
+		case "consecutivePassesForResolution":
+			c.ConsecutivePassesForResolution = int(number)
+		case "decayHalfLifeDays":
+			c.DecayHalfLife = time.Duration(number * float64(24*time.Hour))
+		case "priorPasses":
+			c.PriorPasses = number
+		case "priorFailures":
+			c.PriorFailures = number
+		default:
+			return c, fmt.Errorf("%w: unknown setting %s", ErrInvalidSettings, key)
+		}
+	}
+
+	if err := c.validate(); err != nil {
+		return c, fmt.Errorf("%w: %v", ErrInvalidSettings, err)
+	}
+	return c, nil
+}
+
+// validate checks that a configuration can be used to detect flaky tests
+func (c FlakyTestDetectionConfig) validate() error {
+	if _, err := NewFlakinessScorer(c); err != nil {
+		return err
+	}
+
+	switch {
+	case c.MinimumRuns < 1:
+		return fmt.Errorf("minimumRuns must be at least 1")
+	case c.MinFailureRate < 0 || c.MaxFailureRate > 1 || c.MinFailureRate > c.MaxFailureRate:
+		return fmt.Errorf("failure rates must satisfy 0 <= minFailureRate <= maxFailureRate <= 1")
+	case c.ScoreThreshold < 0 || c.ScoreThreshold > 1:
+		return fmt.Errorf("scoreThreshold must be between 0 and 1")
+	case c.AnalysisWindow <= 0:
+		return fmt.Errorf("analysisWindowDays must be positive")
+	case c.ConsecutivePassesForResolution < 0:
+		return fmt.Errorf("consecutivePassesForResolution cannot be negative")
+	case c.DecayHalfLife <= 0:
+		return fmt.Errorf("decayHalfLifeDays must be positive")
+	case c.PriorPasses <= 0 || c.PriorFailures <= 0:
+		return fmt.Errorf("priorPasses and priorFailures must be positive")
+	}
+	return nil
+}
+
+// settingNumber reads a numeric setting, which is a float64 when decoded from JSON
+func settingNumber(value interface{}) (float64, bool) {
+	var number float64
+	switch v := value.(type) {
+	case float64:
+		number = v
+	case int:
+		number = float64(v)
+	case int64:
+		number = float64(v)
+	default:
+		return 0, false
+	}
+	return number, !math.IsNaN(number) && !math.IsInf(number, 0)
+}
@@ -0,0 +1,65 @@
+package domain_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/analytics/domain"
+)
+
+var _ = Describe("FlakyTestDetectionConfig", Label("unit", "domain", "analytics"), func() {
+	Describe("WithSettings", func() {
+		It("should override the settings given and keep the others", func() {
+			defaults := domain.DefaultFlakyTestDetectionConfig()
+
+			config, err := defaults.WithSettings(map[string]interface{}{
+				"strategy":           "beta",
+				"scoreThreshold":     0.3,
+				"minimumRuns":        float64(5),
+				"analysisWindowDays": 14.0,
+				"decayHalfLifeDays":  0.5,
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Strategy).To(Equal(domain.ScoringBeta))
+			Expect(config.ScoreThreshold).To(Equal(0.3))
+			Expect(config.MinimumRuns).To(Equal(5))
+			Expect(config.AnalysisWindow).To(Equal(14 * 24 * time.Hour))
+			Expect(config.DecayHalfLife).To(Equal(12 * time.Hour))
+			Expect(config.MinFailureRate).To(Equal(defaults.MinFailureRate))
+			Expect(config.PriorPasses).To(Equal(defaults.PriorPasses))
+		})
+
+		It("should accept no settings", func() {
+			config, err := domain.DefaultFlakyTestDetectionConfig().WithSettings(nil)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config).To(Equal(domain.DefaultFlakyTestDetectionConfig()))
+		})
+
+		DescribeTable("rejecting settings that cannot be applied",
+			func(settings map[string]interface{}, message string) {
+				_, err := domain.DefaultFlakyTestDetectionConfig().WithSettings(settings)
+
+				Expect(err).To(MatchError(domain.ErrInvalidSettings))
+				Expect(err).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("an unknown strategy", map[string]interface{}{"strategy": "magic"}, `unknown scoring strategy "magic"`),
+			Entry("a strategy that is not a string", map[string]interface{}{"strategy": 3.0}, "strategy must be a string"),
+			Entry("an unknown setting", map[string]interface{}{"flakiness": 0.5}, "unknown setting flakiness"),
+			Entry("a setting that is not a number", map[string]interface{}{"minimumRuns": "ten"}, "minimumRuns must be a number"),
+			Entry("a score threshold above 1", map[string]interface{}{"scoreThreshold": 1.5}, "scoreThreshold must be between 0 and 1"),
+			Entry("a negative score threshold", map[string]interface{}{"scoreThreshold": -0.1}, "scoreThreshold must be between 0 and 1"),
+			Entry("a negative minimum failure rate", map[string]interface{}{"minFailureRate": -0.1}, "failure rates must satisfy"),
+			Entry("a maximum failure rate above 1", map[string]interface{}{"maxFailureRate": 1.2}, "failure rates must satisfy"),
+			Entry("a minimum failure rate above the maximum", map[string]interface{}{"minFailureRate": 0.6, "maxFailureRate": 0.4}, "failure rates must satisfy"),
+			Entry("no minimum runs", map[string]interface{}{"minimumRuns": 0.0}, "minimumRuns must be at least 1"),
+			Entry("an empty analysis window", map[string]interface{}{"analysisWindowDays": 0.0}, "analysisWindowDays must be positive"),
+			Entry("negative passes for resolution", map[string]interface{}{"consecutivePassesForResolution": -1.0}, "consecutivePassesForResolution cannot be negative"),
+			Entry("a negative half-life", map[string]interface{}{"decayHalfLifeDays": -1.0}, "decayHalfLifeDays must be positive"),
+			Entry("no prior passes", map[string]interface{}{"priorPasses": 0.0}, "priorPasses and priorFailures must be positive"),
+		)
+	})
+})
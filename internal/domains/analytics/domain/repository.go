@@ -19,18 +19,38 @@ type FlakyDetectionRepository interface {
 	// Update flaky test status
 	UpdateFlakyTestStatus(ctx context.Context, testID string, status FlakyTestStatus) error
 
-	// Record a test run analysis
+	// Record a test run analysis in the daily flaky test counts of its project
 	SaveTestRunAnalysis(ctx context.Context, analysis *TestRunAnalysis) error
 
-	// Get test run history for flaky detection
-	GetTestRunHistory(ctx context.Context, projectID string, testName string, since time.Time) ([]TestExecutionResult, error)
+	// Replace the daily flaky test counts of a project on the days given
+	SaveFlakyTestTrends(ctx context.Context, projectID string, trends []FlakyTestTrend) error
 
-	// Get unique test names for a project
-	GetUniqueTestNames(ctx context.Context, projectID string, since time.Time) ([]string, error)
+	// Get the daily flaky test counts of a project recorded since a given day, oldest first,
+	// preceded by the last one recorded before it
+	GetFlakyTestTrends(ctx context.Context, projectID string, since time.Time) ([]FlakyTestTrend, error)
+
+	// Get the execution history of a test case, and of its parameter sets, for flaky detection
+	GetTestRunHistory(ctx context.Context, testCaseID uint, since time.Time) ([]TestExecutionResult, error)
+
+	// Get the tests of a project that ran since a given time
+	GetRecentTests(ctx context.Context, projectID string, since time.Time) ([]TestIdentity, error)
+
+	// Get the tests of a project that ran in the given test runs, together with the
+	// parameterised tests they are parameter sets of
+	GetTestsInRuns(ctx context.Context, projectID string, testRunIDs []uint) ([]TestIdentity, error)
+}
+
+// TestIdentity identifies a test by the test case its spec runs are linked to
+type TestIdentity struct {
+	TestCaseID  uint
+	TestName    string
+	SuiteName   string
+	PackageName string
 }
 
 // TestExecutionResult represents a single test execution result
 type TestExecutionResult struct {
+	TestCaseID  uint // Test case executed, a parameter set when rolled up into its parameterised test
 	TestRunID   string
 	TestName    string
 	SuiteName   string
@@ -38,5 +58,6 @@ type TestExecutionResult struct {
 	Duration    time.Duration
 	ExecutedAt  time.Time
 	Error       string
+	FlakyInRun  bool // Passed after failing an earlier attempt in the same run
 	Environment map[string]string
 }
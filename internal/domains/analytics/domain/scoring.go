@@ -0,0 +1,298 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ScoringStrategy names a built-in way of scoring how flaky a test is
+type ScoringStrategy string
+
+const (
+	// ScoringHeuristic weighs the failure rate and flips by how many runs back them up and how
+	// long the test has been passing
+	ScoringHeuristic ScoringStrategy = "heuristic"
+
+	// ScoringBeta scores how uncertain the outcome of the next run is under a Beta posterior of
+	// the test's pass probability
+	ScoringBeta ScoringStrategy = "beta"
+
+	// ScoringDecayed is the failure rate with every execution weighted down exponentially by age
+	ScoringDecayed ScoringStrategy = "decayed"
+
+	// ScoringFlipRate is the share of consecutive executions whose outcome changed
+	ScoringFlipRate ScoringStrategy = "flip_rate"
+)
+
+// ScoringStrategies lists the built-in scoring strategies
+var ScoringStrategies = []ScoringStrategy{ScoringHeuristic, ScoringBeta, ScoringDecayed, ScoringFlipRate}
+
+// flipScoreWeight is how much more a flip counts towards the heuristic flake score than a failure
+const flipScoreWeight = 4.0
+
+// FlakinessScorer scores how flaky a test is from its execution history
+type FlakinessScorer interface {
+	// Score returns a flake score from 0 (stable) to 1 (flaky) as of now, for executions listed
+	// newest first and their summary
+	Score(history []TestExecutionResult, summary HistorySummary, now time.Time) float64
+}
+
+// NewFlakinessScorer creates the scorer of a configuration's strategy
+func NewFlakinessScorer(config FlakyTestDetectionConfig) (FlakinessScorer, error) {
+	switch config.Strategy {
+	case ScoringHeuristic, "":
+		return HeuristicScorer{}, nil
+	case ScoringBeta:
+		return BetaScorer{PriorPasses: config.PriorPasses, PriorFailures: config.PriorFailures}, nil
+	case ScoringDecayed:
+		return DecayedRateScorer{HalfLife: config.DecayHalfLife}, nil
+	case ScoringFlipRate:
+		return FlipRateScorer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown scoring strategy %q", config.Strategy)
+	}
+}
+
+// HistorySummary summarises the execution history of a test
+type HistorySummary struct {
+	Executions        int            // Executions in the history, skipped ones included
+	Passes            int            // Passes that did not need a retry
+	Failures          int            // Failed executions and passes that needed a retry
+	ConsecutivePasses int            // Passes since the last failure
+	Flips             []FlipEvidence // Code the test both passed and failed on, most recent first
+	CodeVersions      int            // Commits, or runs without one, the test passed or failed on
+}
+
+// FailureRate is the share of executions that failed
+func (h HistorySummary) FailureRate() float64 {
+	if h.Executions == 0 {
+		return 0
+	}
+	return float64(h.Failures) / float64(h.Executions)
+}
+
+// FlipRate is the share of code the test ran on that it both passed and failed on
+func (h HistorySummary) FlipRate() float64 {
+	if h.CodeVersions == 0 {
+		return 0
+	}
+	return float64(len(h.Flips)) / float64(h.CodeVersions)
+}
+
+// SummarizeHistory summarises executions listed newest first. A pass that needed a retry counts
+// as a failure.
+func SummarizeHistory(history []TestExecutionResult) HistorySummary {
+	summary := HistorySummary{Executions: len(history)}
+	failureSeen := false // Passes older than the latest failure are not consecutive
+	for _, exec := range history {
+		if exec.Status == "failed" || exec.FlakyInRun {
+			summary.Failures++
+			failureSeen = true
+		} else if exec.Status == "passed" {
+			summary.Passes++
+			if !failureSeen {
+				summary.ConsecutivePasses++
+			}
+		}
+	}
+	summary.Flips, summary.CodeVersions = DetectFlips(history)
+	return summary
+}
+
+// DetectFlips finds the code a test both passed and failed on, most recent first, and counts the
+// pieces of code it passed or failed on. Code is identified by commit, or by run for runs without
+// one, and executions are compared per test case so the parameter sets rolled up into a history
+// are not mistaken for each other. A pass after a failed attempt is a flip within its run.
+func DetectFlips(history []TestExecutionResult) ([]FlipEvidence, int) {
+	type codeVersion struct {
+		testCaseID uint
+		code       string
+	}
+
+	var versions []codeVersion
+	outcomes := make(map[codeVersion]*FlipEvidence)
+	for _, exec := range history {
+		if exec.Status != "passed" && exec.Status != "failed" {
+			continue
+		}
+
+		commit := exec.Environment["commit"]
+		version := codeVersion{testCaseID: exec.TestCaseID, code: commit}
+		if commit == "" {
+			version.code = "run:" + exec.TestRunID
+		}
+
+		outcome, ok := outcomes[version]
+		if !ok {
+			outcome = &FlipEvidence{Commit: commit}
+			outcomes[version] = outcome
+			versions = append(versions, version)
+		}
+
+		if exec.Status == "passed" {
+			outcome.PassedRunIDs = appendRunID(outcome.PassedRunIDs, exec.TestRunID)
+		}
+		if exec.Status == "failed" || exec.FlakyInRun {
+			outcome.FailedRunIDs = appendRunID(outcome.FailedRunIDs, exec.TestRunID)
+		}
+		if exec.Status == "passed" && exec.FlakyInRun {
+			outcome.InRun = true
+		}
+	}
+
+	var flips []FlipEvidence
+	for _, version := range versions {
+		if outcome := outcomes[version]; len(outcome.PassedRunIDs) > 0 && len(outcome.FailedRunIDs) > 0 {
+			flips = append(flips, *outcome)
+		}
+	}
+	return flips, len(versions)
+}
+
+// appendRunID adds a test run to a list of runs unless it is already listed
+func appendRunID(runIDs []string, runID string) []string {
+	for _, id := range runIDs {
+		if id == runID {
+			return runIDs
+		}
+	}
+	return append(runIDs, runID)
+}
+
+// HeuristicScorer scores the failure rate, with flips weighing far more since failures alone may
+// as well come from broken commits, while a flip proves the test is nondeterministic
+type HeuristicScorer struct{}
+
+// Score implements FlakinessScorer
+func (HeuristicScorer) Score(_ []TestExecutionResult, summary HistorySummary, _ time.Time) float64 {
+	score := summary.FailureRate() + flipScoreWeight*summary.FlipRate()
+
+	// Adjust based on total runs (more runs = more confidence)
+	runConfidence := math.Min(float64(summary.Executions)/100.0, 1.0)
+	score = score * (0.7 + 0.3*runConfidence)
+
+	// Adjust based on recent stability
+	if summary.ConsecutivePasses > 5 {
+		stabilityFactor := math.Min(float64(summary.ConsecutivePasses)/20.0, 0.5)
+		score = score * (1.0 - stabilityFactor)
+	}
+
+	return clampScore(score)
+}
+
+// BetaScorer models the pass probability p of a test with a Beta posterior, starting from prior
+// pseudo-counts of passes and failures. The score is 4·E[p(1-p)]: near 0 for tests that reliably
+// pass or reliably fail, and approaching 1 for tests whose next outcome is a coin toss.
+type BetaScorer struct {
+	PriorPasses   float64
+	PriorFailures float64
+}
+
+// Score implements FlakinessScorer
+func (b BetaScorer) Score(_ []TestExecutionResult, summary HistorySummary, _ time.Time) float64 {
+	alpha := float64(summary.Passes) + b.PriorPasses
+	beta := float64(summary.Failures) + b.PriorFailures
+	if alpha <= 0 || beta <= 0 {
+		return 0
+	}
+
+	return clampScore(4 * alpha * beta / ((alpha + beta) * (alpha + beta + 1)))
+}
+
+// DecayedRateScorer is the failure rate with each execution weighted by 2^(-age/HalfLife), so
+// recent failures count more than old ones
+type DecayedRateScorer struct {
+	HalfLife time.Duration
+}
+
+// Score implements FlakinessScorer
+func (d DecayedRateScorer) Score(history []TestExecutionResult, _ HistorySummary, now time.Time) float64 {
+	if d.HalfLife <= 0 {
+		return 0
+	}
+
+	var failed, total float64
+	for _, exec := range history {
+		if exec.Status != "passed" && exec.Status != "failed" {
+			continue
+		}
+
+		age := math.Max(now.Sub(exec.ExecutedAt).Seconds(), 0)
+		weight := math.Exp2(-age / d.HalfLife.Seconds())
+		total += weight
+		if exec.Status == "failed" || exec.FlakyInRun {
+			failed += weight
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+
+	return clampScore(failed / total)
+}
+
+// FlipRateScorer is the share of consecutive executions of a test whose outcome changed. A pass
+// after a failed attempt is a change within its run. Tests that keep passing or keep failing
+// score 0 however often they fail.
+type FlipRateScorer struct{}
+
+// Score implements FlakinessScorer
+func (FlipRateScorer) Score(history []TestExecutionResult, _ HistorySummary, _ time.Time) float64 {
+	// Parameter sets rolled up into one history are followed separately
+	lastPassed := make(map[uint]bool)
+	var transitions, pairs int
+	for i := len(history) - 1; i >= 0; i-- {
+		exec := history[i]
+		if exec.Status != "passed" && exec.Status != "failed" {
+			continue
+		}
+
+		passed := exec.Status == "passed"
+		if exec.FlakyInRun {
+			transitions++
+			pairs++
+		}
+		if previous, ok := lastPassed[exec.TestCaseID]; ok {
+			pairs++
+			if previous != passed {
+				transitions++
+			}
+		}
+		lastPassed[exec.TestCaseID] = passed
+	}
+	if pairs == 0 {
+		return 0
+	}
+
+	return clampScore(float64(transitions) / float64(pairs))
+}
+
+// clampScore bounds a flake score to [0, 1]
+func clampScore(score float64) float64 {
+	return math.Min(math.Max(score, 0.0), 1.0)
+}
+
+// StrategyEvaluation compares the scoring strategies on a project's history. Tests are scored
+// from their history before a cutoff and checked against whether they flipped after it.
+type StrategyEvaluation struct {
+	ProjectID      string           `json:"projectId"`
+	Strategy       ScoringStrategy  `json:"strategy"` // Strategy the project uses
+	Cutoff         time.Time        `json:"cutoff"`
+	TestsEvaluated int              `json:"testsEvaluated"`
+	FlakyTests     int              `json:"flakyTests"` // Tests that flipped after the cutoff
+	Strategies     []StrategyResult `json:"strategies"`
+}
+
+// StrategyResult is how well a scoring strategy predicted which tests would flip
+type StrategyResult struct {
+	Strategy       ScoringStrategy `json:"strategy"`
+	Flagged        int             `json:"flagged"`       // Tests the detector would have flagged as flaky
+	TruePositives  int             `json:"truePositives"` // Flagged tests that flipped
+	FalsePositives int             `json:"falsePositives"`
+	Precision      float64         `json:"precision"`
+	Recall         float64         `json:"recall"`
+	// AUC is the chance that a test that flipped scored higher than one that did not, or nil
+	// unless some tests flipped and some did not
+	AUC *float64 `json:"auc,omitempty"`
+}
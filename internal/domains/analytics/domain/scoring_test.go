@@ -0,0 +1,261 @@
+package domain_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/analytics/domain"
+)
+
+func TestDomain(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Analytics Domain Suite")
+}
+
+// execution builds an execution of a test case in a test run, on a commit unless it is empty
+func execution(testCaseID uint, testRunID, commit, status string) domain.TestExecutionResult {
+	exec := domain.TestExecutionResult{
+		TestCaseID: testCaseID,
+		TestRunID:  testRunID,
+		Status:     status,
+		ExecutedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	if commit != "" {
+		exec.Environment = map[string]string{"commit": commit}
+	}
+	return exec
+}
+
+// retried marks an execution as having passed after failing an earlier attempt in its run
+func retried(exec domain.TestExecutionResult) domain.TestExecutionResult {
+	exec.FlakyInRun = true
+	return exec
+}
+
+var _ = Describe("DetectFlips", Label("unit", "domain", "analytics"), func() {
+	DescribeTable("finding the code a test both passed and failed on",
+		func(history []domain.TestExecutionResult, expectedFlips []domain.FlipEvidence, expectedVersions int) {
+			flips, versions := domain.DetectFlips(history)
+
+			Expect(flips).To(Equal(expectedFlips))
+			Expect(versions).To(Equal(expectedVersions))
+		},
+		Entry("a pass and a failure in different runs of one commit",
+			[]domain.TestExecutionResult{
+				execution(1, "run-2", "abc", "passed"),
+				execution(1, "run-1", "abc", "failed"),
+			},
+			[]domain.FlipEvidence{{Commit: "abc", PassedRunIDs: []string{"run-2"}, FailedRunIDs: []string{"run-1"}}},
+			1,
+		),
+		Entry("a pass on a retry within a run without a commit",
+			[]domain.TestExecutionResult{
+				retried(execution(1, "run-1", "", "passed")),
+			},
+			[]domain.FlipEvidence{{PassedRunIDs: []string{"run-1"}, FailedRunIDs: []string{"run-1"}, InRun: true}},
+			1,
+		),
+		Entry("a commit the test only ever failed on",
+			[]domain.TestExecutionResult{
+				execution(1, "run-3", "def", "passed"),
+				execution(1, "run-2", "abc", "failed"),
+				execution(1, "run-1", "abc", "failed"),
+			},
+			nil,
+			2,
+		),
+		Entry("parameter sets passing and failing on the same commit",
+			[]domain.TestExecutionResult{
+				execution(2, "run-1", "abc", "passed"),
+				execution(1, "run-1", "abc", "failed"),
+			},
+			nil,
+			2,
+		),
+		Entry("runs without a commit, each its own code",
+			[]domain.TestExecutionResult{
+				execution(1, "run-2", "", "passed"),
+				execution(1, "run-1", "", "failed"),
+			},
+			nil,
+			2,
+		),
+		Entry("skipped executions",
+			[]domain.TestExecutionResult{
+				execution(1, "run-2", "abc", "skipped"),
+				execution(1, "run-1", "abc", "failed"),
+			},
+			nil,
+			1,
+		),
+		Entry("flips on several commits, most recent first",
+			[]domain.TestExecutionResult{
+				execution(1, "run-4", "def", "failed"),
+				execution(1, "run-3", "def", "passed"),
+				execution(1, "run-2", "abc", "passed"),
+				execution(1, "run-1", "abc", "failed"),
+			},
+			[]domain.FlipEvidence{
+				{Commit: "def", PassedRunIDs: []string{"run-3"}, FailedRunIDs: []string{"run-4"}},
+				{Commit: "abc", PassedRunIDs: []string{"run-2"}, FailedRunIDs: []string{"run-1"}},
+			},
+			2,
+		),
+	)
+})
+
+var _ = Describe("HeuristicScorer", Label("unit", "domain", "analytics"), func() {
+	now := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	score := func(history []domain.TestExecutionResult) float64 {
+		return domain.HeuristicScorer{}.Score(history, domain.SummarizeHistory(history), now)
+	}
+
+	It("should weigh a flip above the raw failure rate", func() {
+		flipping := []domain.TestExecutionResult{
+			execution(1, "run-1", "abc", "failed"),
+			execution(1, "run-2", "abc", "passed"),
+			execution(1, "run-3", "def", "passed"),
+			execution(1, "run-4", "ghi", "passed"),
+		}
+		brokenCommit := []domain.TestExecutionResult{
+			execution(1, "run-1", "abc", "failed"),
+			execution(1, "run-2", "def", "passed"),
+			execution(1, "run-3", "def", "passed"),
+			execution(1, "run-4", "ghi", "passed"),
+		}
+
+		Expect(domain.SummarizeHistory(flipping).FailureRate()).To(Equal(0.25))
+		Expect(domain.SummarizeHistory(brokenCommit).FailureRate()).To(Equal(0.25))
+		Expect(score(flipping)).To(BeNumerically(">", 0.25))
+		Expect(score(brokenCommit)).To(BeNumerically("<=", 0.25))
+		Expect(score(flipping)).To(BeNumerically(">", score(brokenCommit)))
+	})
+})
+
+// history builds the executions of a test, newest first, one run apart and without commits,
+// from a string of outcomes such as "PFP": P passed, F failed, R passed on a retry, S skipped
+func history(outcomes string, newest time.Time) []domain.TestExecutionResult {
+	statuses := map[rune]string{'P': "passed", 'F': "failed", 'R': "passed", 'S': "skipped"}
+	executions := make([]domain.TestExecutionResult, 0, len(outcomes))
+	for i, outcome := range outcomes {
+		exec := execution(1, fmt.Sprintf("run-%d", len(outcomes)-i), "", statuses[outcome])
+		exec.ExecutedAt = newest.Add(-time.Duration(i) * time.Hour)
+		exec.FlakyInRun = outcome == 'R'
+		executions = append(executions, exec)
+	}
+	return executions
+}
+
+var _ = Describe("Scorers", Label("unit", "domain", "analytics"), func() {
+	now := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	score := func(scorer domain.FlakinessScorer, executions []domain.TestExecutionResult) float64 {
+		return scorer.Score(executions, domain.SummarizeHistory(executions), now)
+	}
+
+	DescribeTable("HeuristicScorer",
+		func(outcomes string, expected float64) {
+			Expect(score(domain.HeuristicScorer{}, history(outcomes, now))).To(BeNumerically("~", expected, 1e-9))
+		},
+		Entry("a test that always passes", strings.Repeat("P", 100), 0.0),
+		Entry("the failure rate of a test with many runs", strings.Repeat("F", 10)+strings.Repeat("P", 90), 0.1),
+		Entry("the failure rate discounted for few runs", "F"+strings.Repeat("P", 9), 0.1*0.73),
+		Entry("the failure rate halved after a long passing streak", strings.Repeat("P", 90)+strings.Repeat("F", 10), 0.05),
+		Entry("a pass on a retry, counted as a failure and a flip, capped at 1", "R", 1.0),
+	)
+
+	DescribeTable("BetaScorer with one prior pass and failure",
+		func(outcomes string, expected float64) {
+			scorer := domain.BetaScorer{PriorPasses: 1, PriorFailures: 1}
+			Expect(score(scorer, history(outcomes, now))).To(BeNumerically("~", expected, 1e-9))
+		},
+		Entry("a test without history", "", 4.0*1*1/(2*3)),
+		Entry("a test that mostly passes", "PFPP", 4.0*4*2/(6*7)),
+		Entry("a test that always passes", strings.Repeat("P", 20), 4.0*21*1/(22*23)),
+		Entry("a test that always fails", strings.Repeat("F", 20), 4.0*1*21/(22*23)),
+	)
+
+	It("should score 0 under the beta strategy without positive priors", func() {
+		Expect(score(domain.BetaScorer{}, history("", now))).To(BeZero())
+	})
+
+	DescribeTable("DecayedRateScorer with a half-life of an hour",
+		func(outcomes string, expected float64) {
+			scorer := domain.DecayedRateScorer{HalfLife: time.Hour}
+			Expect(score(scorer, history(outcomes, now))).To(BeNumerically("~", expected, 1e-9))
+		},
+		Entry("a failure followed by a pass an hour later", "PF", 0.5/1.5),
+		Entry("a pass followed by a failure an hour later", "FP", 1/1.5),
+		Entry("a pass on a retry, counted as a failure", "RP", 1/1.5),
+		Entry("skipped executions", "SSF", 1.0),
+		Entry("a test without history", "", 0.0),
+	)
+
+	It("should score 0 under the decayed strategy without a half-life", func() {
+		Expect(score(domain.DecayedRateScorer{}, history("FF", now))).To(BeZero())
+	})
+
+	DescribeTable("FlipRateScorer",
+		func(outcomes string, expected float64) {
+			Expect(score(domain.FlipRateScorer{}, history(outcomes, now))).To(BeNumerically("~", expected, 1e-9))
+		},
+		Entry("outcomes changing every run", "PFPF", 1.0),
+		Entry("outcomes changing once", "PPFF", 1.0/3),
+		Entry("a test that always fails", "FFFF", 0.0),
+		Entry("a pass on a retry", "R", 1.0),
+		Entry("skipped executions between a pass and a failure", "PSF", 1.0),
+		Entry("a test without history", "", 0.0),
+	)
+
+	It("should follow parameter sets separately under the flip rate strategy", func() {
+		executions := []domain.TestExecutionResult{
+			execution(2, "run-2", "", "failed"),
+			execution(1, "run-2", "", "passed"),
+			execution(2, "run-1", "", "failed"),
+			execution(1, "run-1", "", "passed"),
+		}
+
+		Expect(score(domain.FlipRateScorer{}, executions)).To(BeZero())
+	})
+
+	It("should create the scorer of each strategy and reject unknown ones", func() {
+		config := domain.DefaultFlakyTestDetectionConfig()
+		for _, strategy := range domain.ScoringStrategies {
+			config.Strategy = strategy
+			_, err := domain.NewFlakinessScorer(config)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		config.Strategy = "magic"
+		_, err := domain.NewFlakinessScorer(config)
+		Expect(err).To(MatchError(ContainSubstring(`unknown scoring strategy "magic"`)))
+	})
+})
+
+var _ = Describe("SummarizeHistory", Label("unit", "domain", "analytics"), func() {
+	now := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	It("should count the passes since the latest failure as consecutive", func() {
+		summary := domain.SummarizeHistory(history("PPSPFPP", now))
+
+		Expect(summary.Executions).To(Equal(7))
+		Expect(summary.Passes).To(Equal(5))
+		Expect(summary.Failures).To(Equal(1))
+		Expect(summary.ConsecutivePasses).To(Equal(3))
+	})
+
+	It("should count a pass on a retry as a failure", func() {
+		summary := domain.SummarizeHistory(history("PRP", now))
+
+		Expect(summary.Passes).To(Equal(2))
+		Expect(summary.Failures).To(Equal(1))
+		Expect(summary.ConsecutivePasses).To(Equal(1))
+		Expect(summary.Flips).To(HaveLen(1))
+	})
+})
@@ -2,12 +2,15 @@ package infrastructure
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/guidewire-oss/fern-platform/internal/domains/analytics/domain"
 	"github.com/guidewire-oss/fern-platform/pkg/database"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GormFlakyDetectionRepository implements FlakyDetectionRepository using GORM
@@ -22,23 +25,35 @@ func NewGormFlakyDetectionRepository(db *gorm.DB) *GormFlakyDetectionRepository
 
 // SaveFlakyTest saves or updates a flaky test record
 func (r *GormFlakyDetectionRepository) SaveFlakyTest(ctx context.Context, flaky *domain.FlakyTest) error {
+	flips, err := json.Marshal(flaky.Metadata.Flips)
+	if err != nil {
+		return fmt.Errorf("failed to encode flip evidence: %w", err)
+	}
+
 	// Convert domain model to database model
 	dbFlaky := &database.FlakyTest{
 		ProjectID:        flaky.ProjectID,
+		TestCaseID:       flaky.TestCaseID,
 		TestName:         flaky.TestName,
 		SuiteName:        flaky.SuiteName,
-		FlakeRate:        flaky.FlakeScore * 100, // Convert to percentage
+		FlakeRate:        flaky.FlakeScore,
 		TotalExecutions:  flaky.TotalRuns,
 		FlakyExecutions:  flaky.FailureCount,
 		FirstSeenAt:      flaky.FirstSeen,
 		LastSeenAt:       flaky.LastSeen,
 		Status:           string(flaky.Status),
-		Severity:         calculateSeverity(flaky.FlakeScore),
-		LastErrorMessage: getLastErrorMessage(flaky.Metadata),
+		Severity:         flaky.Severity(),
+		LastErrorMessage: flaky.LastErrorMessage(),
+		FlipEvidence:     flips,
 	}
 
-	// Use upsert pattern
-	result := r.db.WithContext(ctx).Save(dbFlaky)
+	// A test is tracked once per test case
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "test_case_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"flake_rate", "total_executions", "flaky_executions", "last_seen_at", "status", "severity", "last_error_message", "flip_evidence", "updated_at",
+		}),
+	}).Create(dbFlaky)
 	if result.Error != nil {
 		return fmt.Errorf("failed to save flaky test: %w", result.Error)
 	}
@@ -48,8 +63,13 @@ func (r *GormFlakyDetectionRepository) SaveFlakyTest(ctx context.Context, flaky
 
 // GetFlakyTest retrieves a flaky test by ID
 func (r *GormFlakyDetectionRepository) GetFlakyTest(ctx context.Context, testID string) (*domain.FlakyTest, error) {
+	testCaseID, err := parseTestID(testID)
+	if err != nil {
+		return nil, err
+	}
+
 	var dbFlaky database.FlakyTest
-	if err := r.db.WithContext(ctx).Where("test_id = ?", testID).First(&dbFlaky).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("test_case_id = ?", testCaseID).First(&dbFlaky).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("flaky test not found")
 		}
@@ -68,18 +88,18 @@ func (r *GormFlakyDetectionRepository) FindFlakyTestsByProject(ctx context.Conte
 		query = query.Where("status = ?", string(status))
 	}
 
-	if err := query.Order("flake_score DESC").Find(&dbFlakyTests).Error; err != nil {
+	if err := query.Order("flake_rate DESC").Find(&dbFlakyTests).Error; err != nil {
 		return nil, fmt.Errorf("failed to find flaky tests: %w", err)
 	}
 
-	flakyTests := make([]*domain.FlakyTest, len(dbFlakyTests))
-	for i, dbFlaky := range dbFlakyTests {
+	flakyTests := make([]*domain.FlakyTest, 0, len(dbFlakyTests))
+	for _, dbFlaky := range dbFlakyTests {
 		flaky, err := r.toDomainFlakyTest(&dbFlaky)
 		if err != nil {
-			// Log error but continue
+			// Skip tests whose record cannot be decoded rather than return nil entries
 			continue
 		}
-		flakyTests[i] = flaky
+		flakyTests = append(flakyTests, flaky)
 	}
 
 	return flakyTests, nil
@@ -87,8 +107,13 @@ func (r *GormFlakyDetectionRepository) FindFlakyTestsByProject(ctx context.Conte
 
 // UpdateFlakyTestStatus updates the status of a flaky test
 func (r *GormFlakyDetectionRepository) UpdateFlakyTestStatus(ctx context.Context, testID string, status domain.FlakyTestStatus) error {
+	testCaseID, err := parseTestID(testID)
+	if err != nil {
+		return err
+	}
+
 	result := r.db.WithContext(ctx).Model(&database.FlakyTest{}).
-		Where("test_id = ?", testID).
+		Where("test_case_id = ?", testCaseID).
 		Update("status", string(status))
 
 	if result.Error != nil {
@@ -102,36 +127,142 @@ func (r *GormFlakyDetectionRepository) UpdateFlakyTestStatus(ctx context.Context
 	return nil
 }
 
-// SaveTestRunAnalysis saves a test run analysis
+// SaveTestRunAnalysis records a test run analysis in the snapshot of its project for the day it
+// ran. Analyses of the same day add up their new and resolved tests, and the last one sets the
+// active count.
 func (r *GormFlakyDetectionRepository) SaveTestRunAnalysis(ctx context.Context, analysis *domain.TestRunAnalysis) error {
-	// For now, we'll just log this. In a real implementation, we'd have a dedicated table
-	// This could be used for tracking analysis history and generating reports
+	db := r.db.WithContext(ctx)
+
+	var active int64
+	err := db.Model(&database.FlakyTest{}).
+		Where("project_id = ? AND status = ?", analysis.ProjectID, string(domain.StatusActive)).
+		Count(&active).Error
+	if err != nil {
+		return fmt.Errorf("failed to count active flaky tests: %w", err)
+	}
+
+	snapshot := &database.FlakyTestSnapshot{
+		ProjectID:     analysis.ProjectID,
+		Date:          domain.TrendDate(analysis.AnalyzedAt),
+		ActiveCount:   int(active),
+		NewCount:      len(analysis.NewFlaky),
+		ResolvedCount: len(analysis.ResolvedFlaky),
+	}
+
+	result := db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "project_id"}, {Name: "date"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"active_count":   gorm.Expr("excluded.active_count"),
+			"new_count":      gorm.Expr("flaky_test_snapshots.new_count + excluded.new_count"),
+			"resolved_count": gorm.Expr("flaky_test_snapshots.resolved_count + excluded.resolved_count"),
+			"updated_at":     gorm.Expr("excluded.updated_at"),
+		}),
+	}).Create(snapshot)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save flaky test snapshot: %w", result.Error)
+	}
+
 	return nil
 }
 
-// GetTestRunHistory retrieves test execution history for a specific test
-func (r *GormFlakyDetectionRepository) GetTestRunHistory(ctx context.Context, projectID string, testName string, since time.Time) ([]domain.TestExecutionResult, error) {
-	// Use a raw query to get all the needed data in one query
+// SaveFlakyTestTrends replaces the snapshots of a project on the days given
+func (r *GormFlakyDetectionRepository) SaveFlakyTestTrends(ctx context.Context, projectID string, trends []domain.FlakyTestTrend) error {
+	if len(trends) == 0 {
+		return nil
+	}
+
+	snapshots := make([]database.FlakyTestSnapshot, len(trends))
+	for i, trend := range trends {
+		snapshots[i] = database.FlakyTestSnapshot{
+			ProjectID:     projectID,
+			Date:          domain.TrendDate(trend.Date),
+			ActiveCount:   trend.ActiveCount,
+			NewCount:      trend.NewCount,
+			ResolvedCount: trend.ResolvedCount,
+		}
+	}
+
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"active_count", "new_count", "resolved_count", "updated_at"}),
+	}).CreateInBatches(snapshots, 500)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save flaky test snapshots: %w", result.Error)
+	}
+
+	return nil
+}
+
+// GetFlakyTestTrends returns the snapshots of a project since a given day, oldest first, preceded
+// by the last snapshot before it so counts can be carried into days without one
+func (r *GormFlakyDetectionRepository) GetFlakyTestTrends(ctx context.Context, projectID string, since time.Time) ([]domain.FlakyTestTrend, error) {
+	db := r.db.WithContext(ctx)
+	since = domain.TrendDate(since)
+
+	var previous []database.FlakyTestSnapshot
+	err := db.Where("project_id = ? AND date < ?", projectID, since).
+		Order("date DESC").
+		Limit(1).
+		Find(&previous).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flaky test snapshots: %w", err)
+	}
+
+	var snapshots []database.FlakyTestSnapshot
+	err = db.Where("project_id = ? AND date >= ?", projectID, since).
+		Order("date").
+		Find(&snapshots).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flaky test snapshots: %w", err)
+	}
+
+	trends := make([]domain.FlakyTestTrend, 0, len(previous)+len(snapshots))
+	for _, snapshot := range append(previous, snapshots...) {
+		trends = append(trends, domain.FlakyTestTrend{
+			Date:          domain.TrendDate(snapshot.Date),
+			ActiveCount:   snapshot.ActiveCount,
+			NewCount:      snapshot.NewCount,
+			ResolvedCount: snapshot.ResolvedCount,
+		})
+	}
+
+	return trends, nil
+}
+
+// GetTestRunHistory retrieves test execution history for a specific test, newest first. The
+// history of a parameterised test rolls up the executions of all its parameter sets. A passing
+// execution that failed an earlier attempt in the same run is flagged FlakyInRun and carries the
+// error of its last failed attempt.
+func (r *GormFlakyDetectionRepository) GetTestRunHistory(ctx context.Context, testCaseID uint, since time.Time) ([]domain.TestExecutionResult, error) {
 	query := `
-		SELECT 
-			sr.id as spec_run_id,
-			sr.name as test_name,
+		SELECT
+			sr.test_case_id,
+			sr.spec_name,
 			sr.status,
-			sr.duration,
-			sr.failure_message,
+			sr.duration_ms,
+			COALESCE(sr.error_message, '') AS error_message,
 			sr.created_at,
-			sur.name as suite_name,
-			tr.id as test_run_id,
-			tr.git_branch,
-			tr.git_commit
+			sur.suite_name,
+			tr.id AS test_run_id,
+			COALESCE(tr.branch, '') AS branch,
+			COALESCE(tr.commit_sha, '') AS commit_sha,
+			fa.error_message AS attempt_error
 		FROM spec_runs sr
-		JOIN suite_runs sur ON sur.id = sr.suite_run_id
-		JOIN test_runs tr ON tr.id = sur.test_run_id
-		WHERE tr.project_id = ? AND sr.name = ? AND tr.created_at >= ?
+		JOIN test_cases tc ON tc.id = sr.test_case_id
+		JOIN suite_runs sur ON sur.id = sr.suite_run_id AND sur.deleted_at IS NULL
+		JOIN test_runs tr ON tr.id = sur.test_run_id AND tr.deleted_at IS NULL
+		LEFT JOIN LATERAL (
+			SELECT COALESCE(a.error_message, '') AS error_message
+			FROM spec_attempts a
+			WHERE a.spec_run_id = sr.id AND a.status = 'failed'
+			ORDER BY a.attempt DESC
+			LIMIT 1
+		) fa ON TRUE
+		WHERE (tc.id = ? OR tc.parent_id = ?) AND tr.created_at >= ? AND sr.deleted_at IS NULL
 		ORDER BY tr.created_at DESC
 	`
 
-	rows, err := r.db.WithContext(ctx).Raw(query, projectID, testName, since).Rows()
+	rows, err := r.db.WithContext(ctx).Raw(query, testCaseID, testCaseID, since).Rows()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get test run history: %w", err)
 	}
@@ -140,100 +271,123 @@ func (r *GormFlakyDetectionRepository) GetTestRunHistory(ctx context.Context, pr
 	var results []domain.TestExecutionResult
 	for rows.Next() {
 		var (
-			specRunID      uint
-			testName       string
-			status         string
-			duration       int64
-			failureMessage *string
-			createdAt      time.Time
-			suiteName      string
-			testRunID      uint
-			gitBranch      string
-			gitCommit      string
+			testCaseID   uint
+			specName     string
+			status       string
+			duration     int64
+			errorMessage string
+			createdAt    time.Time
+			suiteName    string
+			testRunID    uint
+			branch       string
+			commitSHA    string
+			attemptError *string
 		)
 
-		err := rows.Scan(
-			&specRunID,
-			&testName,
+		if err := rows.Scan(
+			&testCaseID,
+			&specName,
 			&status,
 			&duration,
-			&failureMessage,
+			&errorMessage,
 			&createdAt,
 			&suiteName,
 			&testRunID,
-			&gitBranch,
-			&gitCommit,
-		)
-		if err != nil {
-			continue
-		}
-
-		errorMsg := ""
-		if failureMessage != nil {
-			errorMsg = *failureMessage
+			&branch,
+			&commitSHA,
+			&attemptError,
+		); err != nil {
+			return nil, fmt.Errorf("failed to read test run history: %w", err)
 		}
 
 		result := domain.TestExecutionResult{
+			TestCaseID: testCaseID,
 			TestRunID:  fmt.Sprintf("%d", testRunID),
-			TestName:   testName,
+			TestName:   specName,
 			SuiteName:  suiteName,
 			Status:     status,
 			Duration:   time.Duration(duration) * time.Millisecond,
 			ExecutedAt: createdAt,
-			Error:      errorMsg,
+			Error:      errorMessage,
+			FlakyInRun: status == "passed" && attemptError != nil,
 			Environment: map[string]string{
-				"branch": gitBranch,
-				"commit": gitCommit,
+				"branch": branch,
+				"commit": commitSHA,
 			},
 		}
+		if result.FlakyInRun {
+			result.Error = *attemptError
+		}
 		results = append(results, result)
 	}
 
-	return results, nil
+	return results, rows.Err()
 }
 
-// GetUniqueTestNames returns all unique test names for a project since a given time
-func (r *GormFlakyDetectionRepository) GetUniqueTestNames(ctx context.Context, projectID string, since time.Time) ([]string, error) {
-	var testNames []string
+// GetRecentTests returns the tests of a project that ran since a given time
+func (r *GormFlakyDetectionRepository) GetRecentTests(ctx context.Context, projectID string, since time.Time) ([]domain.TestIdentity, error) {
+	var testCases []database.TestCase
+	err := r.db.WithContext(ctx).
+		Select("id", "name", "suite_name", "package_name").
+		Where("project_id = ? AND last_seen_at >= ?", projectID, since).
+		Order("id").
+		Find(&testCases).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent tests: %w", err)
+	}
 
-	query := `
-		SELECT DISTINCT sr.name
-		FROM spec_runs sr
-		JOIN suite_runs sur ON sur.id = sr.suite_run_id
-		JOIN test_runs tr ON tr.id = sur.test_run_id
-		WHERE tr.project_id = ? AND tr.created_at >= ?
-		ORDER BY sr.name
-	`
+	return toTestIdentities(testCases), nil
+}
+
+// GetTestsInRuns returns the tests of a project whose spec runs belong to the given test runs.
+// Parameterised tests are included with their parameter sets, as their history rolls them up.
+func (r *GormFlakyDetectionRepository) GetTestsInRuns(ctx context.Context, projectID string, testRunIDs []uint) ([]domain.TestIdentity, error) {
+	if len(testRunIDs) == 0 {
+		return nil, nil
+	}
 
-	err := r.db.WithContext(ctx).Raw(query, projectID, since).Pluck("name", &testNames).Error
+	db := r.db.WithContext(ctx)
+	ran := db.Model(&database.SpecRun{}).
+		Select("spec_runs.test_case_id").
+		Joins("JOIN suite_runs ON suite_runs.id = spec_runs.suite_run_id").
+		Where("suite_runs.test_run_id IN ? AND spec_runs.test_case_id IS NOT NULL", testRunIDs)
+	parents := db.Model(&database.TestCase{}).
+		Select("parent_id").
+		Where("id IN (?) AND parent_id IS NOT NULL", ran)
+
+	var testCases []database.TestCase
+	err := db.Select("id", "name", "suite_name", "package_name").
+		Where("project_id = ? AND (id IN (?) OR id IN (?))", projectID, ran, parents).
+		Order("id").
+		Find(&testCases).Error
 	if err != nil {
-		return nil, fmt.Errorf("failed to get unique test names: %w", err)
+		return nil, fmt.Errorf("failed to get tests in runs: %w", err)
 	}
 
-	return testNames, nil
+	return toTestIdentities(testCases), nil
 }
 
-// Helper function to calculate severity based on flake score
-func calculateSeverity(flakeScore float64) string {
-	if flakeScore < 0.1 {
-		return "low"
-	} else if flakeScore < 0.3 {
-		return "medium"
-	} else if flakeScore < 0.6 {
-		return "high"
+// toTestIdentities identifies tests by their test cases
+func toTestIdentities(testCases []database.TestCase) []domain.TestIdentity {
+	tests := make([]domain.TestIdentity, len(testCases))
+	for i, testCase := range testCases {
+		tests[i] = domain.TestIdentity{
+			TestCaseID:  testCase.ID,
+			TestName:    testCase.Name,
+			SuiteName:   testCase.SuiteName,
+			PackageName: testCase.PackageName,
+		}
 	}
-	return "critical"
+	return tests
 }
 
-// Helper function to get last error message from metadata
-func getLastErrorMessage(metadata domain.FlakyTestMetadata) string {
-	if len(metadata.RecentFailures) > 0 {
-		return metadata.RecentFailures[len(metadata.RecentFailures)-1].ErrorMessage
-	}
-	if len(metadata.FailurePatterns) > 0 {
-		return metadata.FailurePatterns[0]
+// parseTestID parses a flaky test ID, which is the ID of its test case
+func parseTestID(testID string) (uint, error) {
+	id, err := strconv.ParseUint(testID, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid flaky test ID: %s", testID)
 	}
-	return ""
+	return uint(id), nil
 }
 
 // Helper method to convert database model to domain model
@@ -243,12 +397,15 @@ func (r *GormFlakyDetectionRepository) toDomainFlakyTest(dbFlaky *database.Flaky
 	if dbFlaky.LastErrorMessage != "" {
 		metadata.FailurePatterns = []string{dbFlaky.LastErrorMessage}
 	}
-
-	// Generate TestID from project and test name
-	testID := fmt.Sprintf("%s:%s", dbFlaky.ProjectID, dbFlaky.TestName)
+	if len(dbFlaky.FlipEvidence) > 0 {
+		if err := json.Unmarshal(dbFlaky.FlipEvidence, &metadata.Flips); err != nil {
+			return nil, fmt.Errorf("failed to decode flip evidence: %w", err)
+		}
+	}
 
 	return &domain.FlakyTest{
-		TestID:       testID,
+		TestID:       strconv.FormatUint(uint64(dbFlaky.TestCaseID), 10),
+		TestCaseID:   dbFlaky.TestCaseID,
 		ProjectID:    dbFlaky.ProjectID,
 		TestName:     dbFlaky.TestName,
 		SuiteName:    dbFlaky.SuiteName,
@@ -257,7 +414,7 @@ func (r *GormFlakyDetectionRepository) toDomainFlakyTest(dbFlaky *database.Flaky
 		LastSeen:     dbFlaky.LastSeenAt,
 		TotalRuns:    dbFlaky.TotalExecutions,
 		FailureCount: dbFlaky.FlakyExecutions,
-		FlakeScore:   dbFlaky.FlakeRate / 100, // Convert from percentage
+		FlakeScore:   dbFlaky.FlakeRate,
 		Status:       domain.FlakyTestStatus(dbFlaky.Status),
 		Metadata:     metadata,
 	}, nil
@@ -0,0 +1,113 @@
+package infrastructure_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/guidewire-oss/fern-platform/internal/domains/analytics/domain"
+	"github.com/guidewire-oss/fern-platform/internal/domains/analytics/infrastructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func setupMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *gorm.DB) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{})
+	require.NoError(t, err)
+
+	return db, mock, gormDB
+}
+
+func TestGormFlakyDetectionRepository_SaveFlakyTestTrends_ReplacesExistingSnapshots(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormFlakyDetectionRepository(gormDB)
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// Existing snapshots of the days are overwritten, not added to as analyses do
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "flaky_test_snapshots" .* ON CONFLICT \("project_id","date"\) DO UPDATE SET "active_count"="excluded"."active_count","new_count"="excluded"."new_count","resolved_count"="excluded"."resolved_count","updated_at"="excluded"."updated_at" RETURNING "id"`).
+		WithArgs("proj-1", day, 4, 1, 0, sqlmock.AnyArg(), sqlmock.AnyArg(),
+			"proj-1", day.AddDate(0, 0, 1), 3, 0, 1, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7).AddRow(8))
+	mock.ExpectCommit()
+
+	// Act
+	err := repo.SaveFlakyTestTrends(context.Background(), "proj-1", []domain.FlakyTestTrend{
+		{Date: day.Add(15 * time.Hour), ActiveCount: 4, NewCount: 1},
+		{Date: day.AddDate(0, 0, 1), ActiveCount: 3, ResolvedCount: 1},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormFlakyDetectionRepository_SaveTestRunAnalysis_AddsToTheDaysSnapshot(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormFlakyDetectionRepository(gormDB)
+	analyzedAt := time.Date(2026, 3, 1, 15, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "flaky_tests" WHERE \(project_id = \$1 AND status = \$2\)`).
+		WithArgs("proj-1", "active").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(6))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "flaky_test_snapshots" .* ON CONFLICT \("project_id","date"\) DO UPDATE SET "active_count"=excluded.active_count,"new_count"=flaky_test_snapshots.new_count \+ excluded.new_count,"resolved_count"=flaky_test_snapshots.resolved_count \+ excluded.resolved_count`).
+		WithArgs("proj-1", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), 6, 2, 1, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectCommit()
+
+	// Act
+	err := repo.SaveTestRunAnalysis(context.Background(), &domain.TestRunAnalysis{
+		ProjectID:     "proj-1",
+		AnalyzedAt:    analyzedAt,
+		NewFlaky:      []string{"1", "2"},
+		ResolvedFlaky: []string{"3"},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormFlakyDetectionRepository_GetFlakyTestTrends_StartsWithTheSnapshotBeforeTheRange(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormFlakyDetectionRepository(gormDB)
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "project_id", "date", "active_count", "new_count", "resolved_count"}
+
+	mock.ExpectQuery(`SELECT \* FROM "flaky_test_snapshots" WHERE project_id = \$1 AND date < \$2 ORDER BY date DESC LIMIT \$3`).
+		WithArgs("proj-1", since, 1).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "proj-1", since.AddDate(0, 0, -5), 2, 0, 0))
+	mock.ExpectQuery(`SELECT \* FROM "flaky_test_snapshots" WHERE project_id = \$1 AND date >= \$2 ORDER BY date`).
+		WithArgs("proj-1", since).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "proj-1", since.AddDate(0, 0, 2), 3, 1, 0))
+
+	// Act
+	trends, err := repo.GetFlakyTestTrends(context.Background(), "proj-1", since.Add(9*time.Hour))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []domain.FlakyTestTrend{
+		{Date: since.AddDate(0, 0, -5), ActiveCount: 2},
+		{Date: since.AddDate(0, 0, 2), ActiveCount: 3, NewCount: 1},
+	}, trends)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
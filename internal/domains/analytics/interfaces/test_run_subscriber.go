@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/analytics/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/events"
+	testingDomain "github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// SubscribeToTestRuns schedules flaky analysis of every test run the testing domain completes
+func SubscribeToTestRuns(bus *events.Bus, scheduler *application.FlakyAnalysisScheduler) {
+	bus.Subscribe(testingDomain.TestRunCompletedEvent, func(ctx context.Context, event events.Event) {
+		completed, ok := event.(testingDomain.TestRunCompleted)
+		if !ok || completed.TestRunID == 0 {
+			return
+		}
+		scheduler.Schedule(completed.ProjectID, completed.TestRunID)
+	})
+}
@@ -0,0 +1,229 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guidewire-oss/fern-platform/internal/domains/auth/domain"
+)
+
+const (
+	// accessTokenPrefix marks fern personal access tokens and service account tokens
+	accessTokenPrefix = "fern_pat_"
+
+	// accessTokenDisplayLength is how much of a token is kept to help users tell tokens apart
+	accessTokenDisplayLength = len(accessTokenPrefix) + 6
+
+	// serviceAccountEmailDomain gives service accounts a unique, undeliverable email address
+	serviceAccountEmailDomain = "service-accounts.fern.invalid"
+)
+
+// AccessTokenService manages personal access tokens, service accounts and their tokens
+type AccessTokenService struct {
+	tokenRepo domain.AccessTokenRepository
+	userRepo  domain.UserRepository
+	now       func() time.Time
+}
+
+// NewAccessTokenService creates a new access token service
+func NewAccessTokenService(tokenRepo domain.AccessTokenRepository, userRepo domain.UserRepository) *AccessTokenService {
+	return &AccessTokenService{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+		now:       time.Now,
+	}
+}
+
+// CreateToken issues a new token for a user or service account. The returned secret is only
+// available here; only its hash is stored.
+func (s *AccessTokenService) CreateToken(ctx context.Context, userID, name string, scopes []string, createdBy string, expiresAt *time.Time) (*domain.AccessToken, string, error) {
+	if userID == "" {
+		return nil, "", fmt.Errorf("user ID is required")
+	}
+	if strings.TrimSpace(name) == "" {
+		return nil, "", fmt.Errorf("token name is required")
+	}
+	scopes, err := normalizeScopes(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	now := s.now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, "", fmt.Errorf("expiry must be in the future")
+	}
+
+	secret, err := generateToken(accessTokenPrefix)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	token := &domain.AccessToken{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		Name:        strings.TrimSpace(name),
+		TokenPrefix: secret[:accessTokenDisplayLength],
+		TokenHash:   HashToken(secret),
+		Scopes:      scopes,
+		CreatedBy:   createdBy,
+		ExpiresAt:   expiresAt,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.tokenRepo.Create(ctx, token); err != nil {
+		return nil, "", err
+	}
+
+	return token, secret, nil
+}
+
+// ListTokens returns a user's tokens, including revoked and expired ones
+func (s *AccessTokenService) ListTokens(ctx context.Context, userID string) ([]*domain.AccessToken, error) {
+	return s.tokenRepo.FindByUser(ctx, userID)
+}
+
+// RevokeToken permanently disables one of a user's tokens
+func (s *AccessTokenService) RevokeToken(ctx context.Context, userID, tokenID string) error {
+	if _, err := uuid.Parse(tokenID); err != nil {
+		return domain.ErrAccessTokenNotFound
+	}
+
+	token, err := s.tokenRepo.FindByID(ctx, tokenID)
+	if err != nil {
+		return err
+	}
+	if token.UserID != userID {
+		return domain.ErrAccessTokenNotFound
+	}
+	if token.IsRevoked() {
+		return nil
+	}
+
+	return s.tokenRepo.Revoke(ctx, token.ID, s.now())
+}
+
+// Authenticate resolves a presented secret to the token's user, failing with
+// domain.ErrInvalidAccessToken for unknown, expired and revoked tokens and inactive users.
+//
+// The returned user only has the token's scopes: its role is reduced to a plain user and its
+// groups are dropped, so a token never grants admin or manager rights, whoever it belongs to.
+func (s *AccessTokenService) Authenticate(ctx context.Context, secret string) (*domain.User, *domain.AccessToken, error) {
+	if !strings.HasPrefix(secret, accessTokenPrefix) {
+		return nil, nil, domain.ErrInvalidAccessToken
+	}
+
+	token, err := s.tokenRepo.FindByHash(ctx, HashToken(secret))
+	if errors.Is(err, domain.ErrAccessTokenNotFound) {
+		return nil, nil, domain.ErrInvalidAccessToken
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := s.now()
+	if !token.IsValid(now) {
+		return nil, nil, domain.ErrInvalidAccessToken
+	}
+
+	owner, err := s.userRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !owner.IsActive() {
+		return nil, nil, domain.ErrInvalidAccessToken
+	}
+
+	// Recording every use would write on every request; a minute's resolution is enough to spot stale tokens
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedResolution {
+		if err := s.tokenRepo.UpdateLastUsed(ctx, token.ID, now); err != nil {
+			return nil, nil, err
+		}
+		token.LastUsedAt = &now
+	}
+
+	user := *owner
+	user.Role = domain.RoleUser
+	user.Groups = nil
+	user.Scopes = token.UserScopes()
+
+	return &user, token, nil
+}
+
+// CreateServiceAccount creates a non-human account that authenticates with access tokens
+func (s *AccessTokenService) CreateServiceAccount(ctx context.Context, name string) (*domain.User, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("service account name is required")
+	}
+
+	id := uuid.New().String()
+	now := s.now()
+	account := &domain.User{
+		UserID:         "sa-" + id,
+		Email:          id + "@" + serviceAccountEmailDomain,
+		Name:           name,
+		Role:           domain.RoleUser,
+		Status:         domain.StatusActive,
+		ServiceAccount: true,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.userRepo.Create(ctx, account); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// ListServiceAccounts returns all service accounts, including disabled ones
+func (s *AccessTokenService) ListServiceAccounts(ctx context.Context) ([]*domain.User, error) {
+	return s.userRepo.FindServiceAccounts(ctx)
+}
+
+// GetServiceAccount retrieves a service account, treating human users as missing
+func (s *AccessTokenService) GetServiceAccount(ctx context.Context, userID string) (*domain.User, error) {
+	account, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil || !account.ServiceAccount {
+		return nil, domain.ErrServiceAccountNotFound
+	}
+	return account, nil
+}
+
+// DisableServiceAccount deactivates a service account and revokes all of its tokens
+func (s *AccessTokenService) DisableServiceAccount(ctx context.Context, userID string) error {
+	account, err := s.GetServiceAccount(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	account.Status = domain.StatusInactive
+	if err := s.userRepo.Update(ctx, account); err != nil {
+		return err
+	}
+
+	return s.tokenRepo.RevokeAllForUser(ctx, account.UserID, s.now())
+}
+
+// normalizeScopes validates scopes and removes duplicates. A token needs at least one scope,
+// since requests made with it can do nothing else.
+func normalizeScopes(scopes []string) ([]string, error) {
+	seen := make(map[string]bool, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if err := domain.ValidateProjectScope(scope); err != nil {
+			return nil, err
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			normalized = append(normalized, scope)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil, fmt.Errorf("at least one scope is required")
+	}
+	return normalized, nil
+}
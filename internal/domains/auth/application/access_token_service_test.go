@@ -0,0 +1,234 @@
+package application_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/auth/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/auth/domain"
+)
+
+type MockAccessTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockAccessTokenRepository) Create(ctx context.Context, token *domain.AccessToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockAccessTokenRepository) FindByID(ctx context.Context, tokenID string) (*domain.AccessToken, error) {
+	args := m.Called(ctx, tokenID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AccessToken), args.Error(1)
+}
+
+func (m *MockAccessTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.AccessToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AccessToken), args.Error(1)
+}
+
+func (m *MockAccessTokenRepository) FindByUser(ctx context.Context, userID string) ([]*domain.AccessToken, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.AccessToken), args.Error(1)
+}
+
+func (m *MockAccessTokenRepository) Revoke(ctx context.Context, tokenID string, revokedAt time.Time) error {
+	args := m.Called(ctx, tokenID, revokedAt)
+	return args.Error(0)
+}
+
+func (m *MockAccessTokenRepository) RevokeAllForUser(ctx context.Context, userID string, revokedAt time.Time) error {
+	args := m.Called(ctx, userID, revokedAt)
+	return args.Error(0)
+}
+
+func (m *MockAccessTokenRepository) UpdateLastUsed(ctx context.Context, tokenID string, usedAt time.Time) error {
+	args := m.Called(ctx, tokenID, usedAt)
+	return args.Error(0)
+}
+
+var _ = Describe("AccessTokenService", func() {
+	const tokenID = "5d0c7e2a-9b41-4f6e-8c3d-2a1b0f9e8d7c"
+
+	var (
+		tokenRepo *MockAccessTokenRepository
+		userRepo  *MockUserRepository
+		service   *application.AccessTokenService
+		ctx       context.Context
+	)
+
+	BeforeEach(func() {
+		tokenRepo = new(MockAccessTokenRepository)
+		userRepo = new(MockUserRepository)
+		service = application.NewAccessTokenService(tokenRepo, userRepo)
+		ctx = context.Background()
+	})
+
+	Describe("CreateToken", func() {
+		It("should store only the hash of the returned secret", func() {
+			var stored *domain.AccessToken
+			tokenRepo.On("Create", ctx, mock.AnythingOfType("*domain.AccessToken")).
+				Run(func(args mock.Arguments) { stored = args.Get(1).(*domain.AccessToken) }).
+				Return(nil)
+
+			token, secret, err := service.CreateToken(ctx, "user-1", "nightly-report", []string{"project:read:*", "project:read:*"}, "user-1", nil)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(secret).To(HavePrefix("fern_pat_"))
+			Expect(token).To(Equal(stored))
+			Expect(stored.UserID).To(Equal("user-1"))
+			Expect(stored.Scopes).To(Equal([]string{"project:read:*"}))
+			Expect(stored.TokenHash).To(Equal(application.HashToken(secret)))
+			Expect(strings.HasPrefix(secret, stored.TokenPrefix)).To(BeTrue())
+		})
+
+		It("should reject malformed scopes", func() {
+			for _, scopes := range [][]string{nil, {"admin"}, {"project:delete:*"}, {"project:read:"}} {
+				_, _, err := service.CreateToken(ctx, "user-1", "bot", scopes, "user-1", nil)
+				Expect(err).To(HaveOccurred(), fmt.Sprintf("scopes %v", scopes))
+			}
+			tokenRepo.AssertNotCalled(GinkgoT(), "Create", mock.Anything, mock.Anything)
+		})
+	})
+
+	Describe("Authenticate", func() {
+		var admin *domain.User
+
+		BeforeEach(func() {
+			admin = &domain.User{
+				UserID: "user-1",
+				Role:   domain.RoleAdmin,
+				Status: domain.StatusActive,
+				Groups: []domain.UserGroup{{UserID: "user-1", GroupName: "team-a-managers"}},
+			}
+		})
+
+		It("should resolve the token's user with only the token's scopes", func() {
+			secret := "fern_pat_valid"
+			tokenRepo.On("FindByHash", ctx, application.HashToken(secret)).
+				Return(&domain.AccessToken{ID: tokenID, UserID: "user-1", Scopes: []string{"project:read:*"}}, nil)
+			tokenRepo.On("UpdateLastUsed", ctx, tokenID, mock.AnythingOfType("time.Time")).Return(nil)
+			userRepo.On("FindByID", ctx, "user-1").Return(admin, nil)
+
+			user, token, err := service.Authenticate(ctx, secret)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.ID).To(Equal(tokenID))
+			Expect(user.UserID).To(Equal("user-1"))
+			Expect(user.IsAdmin()).To(BeFalse())
+			Expect(user.IsTeamManager()).To(BeFalse())
+			Expect(application.HasProjectScope(user, "proj-1", domain.ScopeActionRead)).To(BeTrue())
+			Expect(application.HasProjectScope(user, "*", domain.ScopeActionRead)).To(BeTrue())
+			Expect(application.HasProjectScope(user, "proj-1", domain.ScopeActionWrite)).To(BeFalse())
+			Expect(admin.IsAdmin()).To(BeTrue(), "the stored user must not be modified")
+		})
+
+		It("should limit project scopes to their project", func() {
+			secret := "fern_pat_valid"
+			tokenRepo.On("FindByHash", ctx, application.HashToken(secret)).
+				Return(&domain.AccessToken{ID: tokenID, UserID: "user-1", Scopes: []string{"project:*:proj-1"}}, nil)
+			tokenRepo.On("UpdateLastUsed", ctx, tokenID, mock.AnythingOfType("time.Time")).Return(nil)
+			userRepo.On("FindByID", ctx, "user-1").Return(admin, nil)
+
+			user, _, err := service.Authenticate(ctx, secret)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(application.HasProjectScope(user, "proj-1", domain.ScopeActionWrite)).To(BeTrue())
+			Expect(application.HasProjectScope(user, "proj-2", domain.ScopeActionRead)).To(BeFalse())
+			Expect(application.HasProjectScope(user, "*", domain.ScopeActionRead)).To(BeFalse())
+		})
+
+		It("should reject tokens of inactive users", func() {
+			secret := "fern_pat_valid"
+			admin.Status = domain.StatusInactive
+			tokenRepo.On("FindByHash", ctx, application.HashToken(secret)).
+				Return(&domain.AccessToken{ID: tokenID, UserID: "user-1", Scopes: []string{"project:read:*"}}, nil)
+			userRepo.On("FindByID", ctx, "user-1").Return(admin, nil)
+
+			_, _, err := service.Authenticate(ctx, secret)
+
+			Expect(err).To(MatchError(domain.ErrInvalidAccessToken))
+			tokenRepo.AssertNotCalled(GinkgoT(), "UpdateLastUsed", mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		It("should reject expired, revoked, unknown and foreign tokens", func() {
+			past := time.Now().Add(-time.Hour)
+			tokenRepo.On("FindByHash", ctx, application.HashToken("fern_pat_expired")).
+				Return(&domain.AccessToken{ID: tokenID, ExpiresAt: &past}, nil)
+			tokenRepo.On("FindByHash", ctx, application.HashToken("fern_pat_revoked")).
+				Return(&domain.AccessToken{ID: tokenID, RevokedAt: &past}, nil)
+			tokenRepo.On("FindByHash", ctx, application.HashToken("fern_pat_unknown")).
+				Return(nil, domain.ErrAccessTokenNotFound)
+
+			for _, secret := range []string{"fern_pat_expired", "fern_pat_revoked", "fern_pat_unknown", "fern_it_ingestion"} {
+				_, _, err := service.Authenticate(ctx, secret)
+				Expect(err).To(MatchError(domain.ErrInvalidAccessToken), secret)
+			}
+		})
+	})
+
+	Describe("RevokeToken", func() {
+		It("should revoke the user's token", func() {
+			tokenRepo.On("FindByID", ctx, tokenID).Return(&domain.AccessToken{ID: tokenID, UserID: "user-1"}, nil)
+			tokenRepo.On("Revoke", ctx, tokenID, mock.AnythingOfType("time.Time")).Return(nil)
+
+			Expect(service.RevokeToken(ctx, "user-1", tokenID)).To(Succeed())
+			tokenRepo.AssertExpectations(GinkgoT())
+		})
+
+		It("should not find another user's token", func() {
+			tokenRepo.On("FindByID", ctx, tokenID).Return(&domain.AccessToken{ID: tokenID, UserID: "user-2"}, nil)
+
+			Expect(service.RevokeToken(ctx, "user-1", tokenID)).To(MatchError(domain.ErrAccessTokenNotFound))
+			tokenRepo.AssertNotCalled(GinkgoT(), "Revoke", mock.Anything, mock.Anything, mock.Anything)
+		})
+	})
+
+	Describe("service accounts", func() {
+		It("should create an active, non-admin service account", func() {
+			userRepo.On("Create", ctx, mock.AnythingOfType("*domain.User")).Return(nil)
+
+			account, err := service.CreateServiceAccount(ctx, "release-bot")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(account.ServiceAccount).To(BeTrue())
+			Expect(account.IsActive()).To(BeTrue())
+			Expect(account.IsAdmin()).To(BeFalse())
+			Expect(account.UserID).To(HavePrefix("sa-"))
+		})
+
+		It("should not treat human users as service accounts", func() {
+			userRepo.On("FindByID", ctx, "user-1").Return(&domain.User{UserID: "user-1"}, nil)
+
+			_, err := service.GetServiceAccount(ctx, "user-1")
+
+			Expect(err).To(MatchError(domain.ErrServiceAccountNotFound))
+		})
+
+		It("should deactivate a disabled service account and revoke its tokens", func() {
+			account := &domain.User{UserID: "sa-1", Status: domain.StatusActive, ServiceAccount: true}
+			userRepo.On("FindByID", ctx, "sa-1").Return(account, nil)
+			userRepo.On("Update", ctx, account).Return(nil)
+			tokenRepo.On("RevokeAllForUser", ctx, "sa-1", mock.AnythingOfType("time.Time")).Return(nil)
+
+			Expect(service.DisableServiceAccount(ctx, "sa-1")).To(Succeed())
+			Expect(account.IsActive()).To(BeFalse())
+			tokenRepo.AssertExpectations(GinkgoT())
+		})
+	})
+})
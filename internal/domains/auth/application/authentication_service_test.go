@@ -63,6 +63,14 @@ func (m *MockUserRepository) UpdateLastLogin(ctx context.Context, userID string,
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) FindServiceAccounts(ctx context.Context) ([]*domain.User, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.User), args.Error(1)
+}
+
 func (m *MockUserRepository) SetUserGroups(ctx context.Context, userID string, groups []string) error {
 	args := m.Called(ctx, userID, groups)
 	return args.Error(0)
@@ -34,18 +34,31 @@ func (s *AuthorizationService) CanAccessProject(ctx context.Context, user *domai
 		return false, fmt.Errorf("failed to get user scopes: %w", err)
 	}
 
-	// Check if user has matching scope
+	return hasProjectScope(scopes, projectID, requiredAction), nil
+}
+
+// HasProjectScope checks if the scopes loaded on a user allow an action on a project. Unlike
+// CanAccessProject it does not consult the repository or the user's role, so it is used for
+// users authenticated with an access token, whose scopes are those of the token. Pass "*" as
+// projectID for requests that are not limited to one project; only scopes on all projects
+// match it.
+func HasProjectScope(user *domain.User, projectID, requiredAction string) bool {
+	return hasProjectScope(user.Scopes, projectID, requiredAction)
+}
+
+// hasProjectScope checks if any unexpired scope matches the required project action
+func hasProjectScope(scopes []domain.UserScope, projectID, requiredAction string) bool {
 	for _, scope := range scopes {
 		if scope.ExpiresAt != nil && scope.ExpiresAt.Before(time.Now()) {
 			continue // Skip expired scopes
 		}
 
-		if s.matchProjectScope(scope.Scope, projectID, requiredAction) {
-			return true, nil
+		if matchProjectScope(scope.Scope, projectID, requiredAction) {
+			return true
 		}
 	}
 
-	return false, nil
+	return false
 }
 
 // CanManageTeam checks if a user can manage a specific team
@@ -64,7 +77,7 @@ func (s *AuthorizationService) RevokeScope(ctx context.Context, userID, scope st
 }
 
 // matchProjectScope checks if a user scope matches the required project action
-func (s *AuthorizationService) matchProjectScope(userScope, projectID, action string) bool {
+func matchProjectScope(userScope, projectID, action string) bool {
 	// Expected scope formats:
 	// - project:read:PROJECT_ID
 	// - project:write:PROJECT_ID
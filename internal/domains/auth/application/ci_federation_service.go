@@ -0,0 +1,108 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guidewire-oss/fern-platform/internal/domains/auth/domain"
+)
+
+// CIFederationService authenticates CI jobs with the OIDC ID tokens their CI system issues, so
+// that pipelines can upload test reports without a stored secret
+type CIFederationService struct {
+	policyRepo domain.CITrustPolicyRepository
+	verifier   domain.CITokenVerifier
+	now        func() time.Time
+}
+
+// NewCIFederationService creates a new CI federation service
+func NewCIFederationService(policyRepo domain.CITrustPolicyRepository, verifier domain.CITokenVerifier) *CIFederationService {
+	return &CIFederationService{
+		policyRepo: policyRepo,
+		verifier:   verifier,
+		now:        time.Now,
+	}
+}
+
+// CreatePolicy adds a trust policy to a project
+func (s *CIFederationService) CreatePolicy(ctx context.Context, projectID string, policy *domain.CITrustPolicy, createdBy string) (*domain.CITrustPolicy, error) {
+	if projectID == "" {
+		return nil, fmt.Errorf("project ID is required")
+	}
+
+	now := s.now()
+	policy = &domain.CITrustPolicy{
+		ID:         uuid.New().String(),
+		ProjectID:  projectID,
+		Issuer:     strings.TrimSpace(policy.Issuer),
+		Repository: strings.TrimSpace(policy.Repository),
+		Ref:        strings.TrimSpace(policy.Ref),
+		Workflow:   strings.TrimSpace(policy.Workflow),
+		CreatedBy:  createdBy,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	if !s.verifier.HasIssuer(policy.Issuer) {
+		return nil, fmt.Errorf("unknown CI OIDC issuer %q", policy.Issuer)
+	}
+
+	if err := s.policyRepo.Create(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// ListPolicies returns a project's trust policies
+func (s *CIFederationService) ListPolicies(ctx context.Context, projectID string) ([]*domain.CITrustPolicy, error) {
+	return s.policyRepo.FindByProject(ctx, projectID)
+}
+
+// DeletePolicy removes one of a project's trust policies
+func (s *CIFederationService) DeletePolicy(ctx context.Context, projectID, policyID string) error {
+	if _, err := uuid.Parse(policyID); err != nil {
+		return domain.ErrCITrustPolicyNotFound
+	}
+
+	policy, err := s.policyRepo.FindByID(ctx, policyID)
+	if err != nil {
+		return err
+	}
+	if policy.ProjectID != projectID {
+		return domain.ErrCITrustPolicyNotFound
+	}
+
+	return s.policyRepo.Delete(ctx, policy.ID)
+}
+
+// Authenticate verifies a CI OIDC token and resolves the projects whose trust policies match it.
+// It fails with domain.ErrInvalidCIToken for unacceptable tokens and domain.ErrNoMatchingTrustPolicy
+// if no project trusts the token.
+func (s *CIFederationService) Authenticate(ctx context.Context, rawToken string) (*domain.CIIdentity, error) {
+	claims, err := s.verifier.Verify(ctx, rawToken)
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := s.policyRepo.FindByIssuer(ctx, claims.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &domain.CIIdentity{Claims: *claims}
+	for _, policy := range policies {
+		if policy.Matches(claims) && !identity.AllowsProject(policy.ProjectID) {
+			identity.ProjectIDs = append(identity.ProjectIDs, policy.ProjectID)
+		}
+	}
+	if len(identity.ProjectIDs) == 0 {
+		return nil, domain.ErrNoMatchingTrustPolicy
+	}
+
+	return identity, nil
+}
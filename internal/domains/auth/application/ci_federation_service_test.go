@@ -0,0 +1,164 @@
+package application_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/auth/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/auth/domain"
+)
+
+type MockCITrustPolicyRepository struct {
+	mock.Mock
+}
+
+func (m *MockCITrustPolicyRepository) Create(ctx context.Context, policy *domain.CITrustPolicy) error {
+	args := m.Called(ctx, policy)
+	return args.Error(0)
+}
+
+func (m *MockCITrustPolicyRepository) FindByID(ctx context.Context, policyID string) (*domain.CITrustPolicy, error) {
+	args := m.Called(ctx, policyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CITrustPolicy), args.Error(1)
+}
+
+func (m *MockCITrustPolicyRepository) FindByProject(ctx context.Context, projectID string) ([]*domain.CITrustPolicy, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.CITrustPolicy), args.Error(1)
+}
+
+func (m *MockCITrustPolicyRepository) FindByIssuer(ctx context.Context, issuer string) ([]*domain.CITrustPolicy, error) {
+	args := m.Called(ctx, issuer)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.CITrustPolicy), args.Error(1)
+}
+
+func (m *MockCITrustPolicyRepository) Delete(ctx context.Context, policyID string) error {
+	args := m.Called(ctx, policyID)
+	return args.Error(0)
+}
+
+type MockCITokenVerifier struct {
+	mock.Mock
+}
+
+func (m *MockCITokenVerifier) Verify(ctx context.Context, rawToken string) (*domain.CIClaims, error) {
+	args := m.Called(ctx, rawToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CIClaims), args.Error(1)
+}
+
+func (m *MockCITokenVerifier) HasIssuer(name string) bool {
+	args := m.Called(name)
+	return args.Bool(0)
+}
+
+var _ = Describe("CIFederationService", func() {
+	var (
+		policyRepo *MockCITrustPolicyRepository
+		verifier   *MockCITokenVerifier
+		service    *application.CIFederationService
+		ctx        context.Context
+		claims     *domain.CIClaims
+	)
+
+	BeforeEach(func() {
+		policyRepo = new(MockCITrustPolicyRepository)
+		verifier = new(MockCITokenVerifier)
+		service = application.NewCIFederationService(policyRepo, verifier)
+		ctx = context.Background()
+		claims = &domain.CIClaims{
+			Issuer:     "github-actions",
+			Repository: "acme/widgets",
+			Ref:        "refs/heads/main",
+			Workflow:   "CI",
+		}
+	})
+
+	Describe("Authenticate", func() {
+		It("should allow the projects whose policies match the token's claims", func() {
+			verifier.On("Verify", ctx, "jwt").Return(claims, nil)
+			policyRepo.On("FindByIssuer", ctx, "github-actions").Return([]*domain.CITrustPolicy{
+				{ProjectID: "proj-1", Issuer: "github-actions", Repository: "acme/widgets", Ref: "refs/heads/*"},
+				{ProjectID: "proj-1", Issuer: "github-actions", Repository: "acme/*"},
+				{ProjectID: "proj-2", Issuer: "github-actions", Repository: "acme/widgets", Workflow: "CI"},
+				{ProjectID: "proj-3", Issuer: "github-actions", Repository: "acme/widgets", Ref: "refs/tags/*"},
+				{ProjectID: "proj-4", Issuer: "github-actions", Repository: "acme/gadgets"},
+				{ProjectID: "proj-5", Issuer: "github-actions", Repository: "acme/widgets", Workflow: "Release"},
+			}, nil)
+
+			identity, err := service.Authenticate(ctx, "jwt")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(identity.ProjectIDs).To(Equal([]string{"proj-1", "proj-2"}))
+			Expect(identity.AllowsProject("proj-3")).To(BeFalse())
+		})
+
+		It("should reject tokens that no policy matches", func() {
+			verifier.On("Verify", ctx, "jwt").Return(claims, nil)
+			policyRepo.On("FindByIssuer", ctx, "github-actions").Return([]*domain.CITrustPolicy{
+				{ProjectID: "proj-1", Issuer: "github-actions", Repository: "acme/gadgets"},
+			}, nil)
+
+			_, err := service.Authenticate(ctx, "jwt")
+
+			Expect(err).To(MatchError(domain.ErrNoMatchingTrustPolicy))
+		})
+
+		It("should not consult policies for invalid tokens", func() {
+			verifier.On("Verify", ctx, "jwt").Return(nil, domain.ErrInvalidCIToken)
+
+			_, err := service.Authenticate(ctx, "jwt")
+
+			Expect(err).To(MatchError(domain.ErrInvalidCIToken))
+			policyRepo.AssertNotCalled(GinkgoT(), "FindByIssuer", mock.Anything, mock.Anything)
+		})
+	})
+
+	Describe("CreatePolicy", func() {
+		It("should store a policy for a configured issuer", func() {
+			verifier.On("HasIssuer", "github-actions").Return(true)
+			policyRepo.On("Create", ctx, mock.AnythingOfType("*domain.CITrustPolicy")).Return(nil)
+
+			policy, err := service.CreatePolicy(ctx, "proj-1", &domain.CITrustPolicy{
+				Issuer:     "github-actions",
+				Repository: " acme/widgets ",
+				Ref:        "refs/heads/main",
+			}, "user-1")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy.ID).NotTo(BeEmpty())
+			Expect(policy.ProjectID).To(Equal("proj-1"))
+			Expect(policy.Repository).To(Equal("acme/widgets"))
+			Expect(policy.CreatedBy).To(Equal("user-1"))
+		})
+
+		It("should reject unknown issuers and invalid patterns", func() {
+			verifier.On("HasIssuer", "jenkins").Return(false)
+
+			_, err := service.CreatePolicy(ctx, "proj-1", &domain.CITrustPolicy{Issuer: "jenkins", Repository: "acme/widgets"}, "user-1")
+			Expect(err).To(MatchError(ContainSubstring("unknown CI OIDC issuer")))
+
+			_, err = service.CreatePolicy(ctx, "proj-1", &domain.CITrustPolicy{Issuer: "github-actions", Repository: "acme/[widgets"}, "user-1")
+			Expect(err).To(MatchError(ContainSubstring("invalid pattern")))
+
+			_, err = service.CreatePolicy(ctx, "proj-1", &domain.CITrustPolicy{Issuer: "github-actions"}, "user-1")
+			Expect(err).To(MatchError(ContainSubstring("repository is required")))
+
+			policyRepo.AssertNotCalled(GinkgoT(), "Create", mock.Anything, mock.Anything)
+		})
+	})
+})
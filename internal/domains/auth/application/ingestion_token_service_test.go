@@ -0,0 +1,201 @@
+package application_test
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/auth/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/auth/domain"
+)
+
+type MockIngestionTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockIngestionTokenRepository) Create(ctx context.Context, token *domain.IngestionToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockIngestionTokenRepository) FindByID(ctx context.Context, tokenID string) (*domain.IngestionToken, error) {
+	args := m.Called(ctx, tokenID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.IngestionToken), args.Error(1)
+}
+
+func (m *MockIngestionTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.IngestionToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.IngestionToken), args.Error(1)
+}
+
+func (m *MockIngestionTokenRepository) FindByProject(ctx context.Context, projectID string) ([]*domain.IngestionToken, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.IngestionToken), args.Error(1)
+}
+
+func (m *MockIngestionTokenRepository) UpdateSecret(ctx context.Context, tokenID, tokenPrefix, tokenHash string) error {
+	args := m.Called(ctx, tokenID, tokenPrefix, tokenHash)
+	return args.Error(0)
+}
+
+func (m *MockIngestionTokenRepository) Revoke(ctx context.Context, tokenID string, revokedAt time.Time) error {
+	args := m.Called(ctx, tokenID, revokedAt)
+	return args.Error(0)
+}
+
+func (m *MockIngestionTokenRepository) UpdateLastUsed(ctx context.Context, tokenID string, usedAt time.Time) error {
+	args := m.Called(ctx, tokenID, usedAt)
+	return args.Error(0)
+}
+
+var _ = Describe("IngestionTokenService", func() {
+	const tokenID = "0b6f0c52-4a8e-4c1b-9a57-3f1c2d9e8a10"
+
+	var (
+		tokenRepo *MockIngestionTokenRepository
+		service   *application.IngestionTokenService
+		ctx       context.Context
+	)
+
+	BeforeEach(func() {
+		tokenRepo = new(MockIngestionTokenRepository)
+		service = application.NewIngestionTokenService(tokenRepo)
+		ctx = context.Background()
+	})
+
+	Describe("CreateToken", func() {
+		It("should store only the hash of the returned secret", func() {
+			var stored *domain.IngestionToken
+			tokenRepo.On("Create", ctx, mock.AnythingOfType("*domain.IngestionToken")).
+				Run(func(args mock.Arguments) { stored = args.Get(1).(*domain.IngestionToken) }).
+				Return(nil)
+
+			token, secret, err := service.CreateToken(ctx, "proj-1", "github-actions", "user-1", nil)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(secret).To(HavePrefix("fern_it_"))
+			Expect(token).To(Equal(stored))
+			Expect(stored.ProjectID).To(Equal("proj-1"))
+			Expect(stored.TokenHash).To(Equal(application.HashToken(secret)))
+			Expect(stored.TokenHash).NotTo(ContainSubstring(secret))
+			Expect(strings.HasPrefix(secret, stored.TokenPrefix)).To(BeTrue())
+			Expect(len(stored.TokenPrefix)).To(BeNumerically("<", len(secret)))
+		})
+
+		It("should reject an expiry in the past", func() {
+			past := time.Now().Add(-time.Hour)
+
+			_, _, err := service.CreateToken(ctx, "proj-1", "github-actions", "user-1", &past)
+
+			Expect(err).To(MatchError(ContainSubstring("expiry must be in the future")))
+			tokenRepo.AssertNotCalled(GinkgoT(), "Create", mock.Anything, mock.Anything)
+		})
+	})
+
+	Describe("Authenticate", func() {
+		It("should resolve a valid token and record its use", func() {
+			secret := "fern_it_valid"
+			tokenRepo.On("FindByHash", ctx, application.HashToken(secret)).
+				Return(&domain.IngestionToken{ID: tokenID, ProjectID: "proj-1"}, nil)
+			tokenRepo.On("UpdateLastUsed", ctx, tokenID, mock.AnythingOfType("time.Time")).Return(nil)
+
+			token, err := service.Authenticate(ctx, secret)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.ProjectID).To(Equal("proj-1"))
+			Expect(token.LastUsedAt).NotTo(BeNil())
+			tokenRepo.AssertExpectations(GinkgoT())
+		})
+
+		It("should not record use again within a minute", func() {
+			secret := "fern_it_valid"
+			recently := time.Now().Add(-10 * time.Second)
+			tokenRepo.On("FindByHash", ctx, application.HashToken(secret)).
+				Return(&domain.IngestionToken{ID: tokenID, ProjectID: "proj-1", LastUsedAt: &recently}, nil)
+
+			_, err := service.Authenticate(ctx, secret)
+
+			Expect(err).NotTo(HaveOccurred())
+			tokenRepo.AssertNotCalled(GinkgoT(), "UpdateLastUsed", mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		It("should reject expired and revoked tokens", func() {
+			past := time.Now().Add(-time.Hour)
+			tokenRepo.On("FindByHash", ctx, application.HashToken("fern_it_expired")).
+				Return(&domain.IngestionToken{ID: tokenID, ExpiresAt: &past}, nil)
+			tokenRepo.On("FindByHash", ctx, application.HashToken("fern_it_revoked")).
+				Return(&domain.IngestionToken{ID: tokenID, RevokedAt: &past}, nil)
+
+			_, err := service.Authenticate(ctx, "fern_it_expired")
+			Expect(err).To(MatchError(domain.ErrInvalidIngestionToken))
+
+			_, err = service.Authenticate(ctx, "fern_it_revoked")
+			Expect(err).To(MatchError(domain.ErrInvalidIngestionToken))
+		})
+
+		It("should reject unknown tokens", func() {
+			tokenRepo.On("FindByHash", ctx, mock.Anything).Return(nil, domain.ErrIngestionTokenNotFound)
+
+			_, err := service.Authenticate(ctx, "fern_it_unknown")
+			Expect(err).To(MatchError(domain.ErrInvalidIngestionToken))
+
+			_, err = service.Authenticate(ctx, "session-cookie-value")
+			Expect(err).To(MatchError(domain.ErrInvalidIngestionToken))
+		})
+	})
+
+	Describe("RotateToken", func() {
+		It("should replace the secret", func() {
+			tokenRepo.On("FindByID", ctx, tokenID).
+				Return(&domain.IngestionToken{ID: tokenID, ProjectID: "proj-1", TokenHash: "old"}, nil)
+			tokenRepo.On("UpdateSecret", ctx, tokenID, mock.Anything, mock.Anything).Return(nil)
+
+			token, secret, err := service.RotateToken(ctx, "proj-1", tokenID)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.TokenHash).To(Equal(application.HashToken(secret)))
+			tokenRepo.AssertCalled(GinkgoT(), "UpdateSecret", ctx, tokenID, token.TokenPrefix, token.TokenHash)
+		})
+
+		It("should not find another project's token", func() {
+			tokenRepo.On("FindByID", ctx, tokenID).Return(&domain.IngestionToken{ID: tokenID, ProjectID: "proj-2"}, nil)
+
+			_, _, err := service.RotateToken(ctx, "proj-1", tokenID)
+
+			Expect(err).To(MatchError(domain.ErrIngestionTokenNotFound))
+		})
+
+		It("should refuse to revive a revoked token", func() {
+			revokedAt := time.Now()
+			tokenRepo.On("FindByID", ctx, tokenID).
+				Return(&domain.IngestionToken{ID: tokenID, ProjectID: "proj-1", RevokedAt: &revokedAt}, nil)
+
+			_, _, err := service.RotateToken(ctx, "proj-1", tokenID)
+
+			Expect(err).To(MatchError(domain.ErrIngestionTokenRevoked))
+		})
+	})
+
+	Describe("RevokeToken", func() {
+		It("should revoke the project's token", func() {
+			tokenRepo.On("FindByID", ctx, tokenID).Return(&domain.IngestionToken{ID: tokenID, ProjectID: "proj-1"}, nil)
+			tokenRepo.On("Revoke", ctx, tokenID, mock.AnythingOfType("time.Time")).Return(nil)
+
+			Expect(service.RevokeToken(ctx, "proj-1", tokenID)).To(Succeed())
+			tokenRepo.AssertExpectations(GinkgoT())
+		})
+	})
+})
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrAccessTokenNotFound is returned when a user has no token with the given ID
+	ErrAccessTokenNotFound = errors.New("access token not found")
+
+	// ErrInvalidAccessToken is returned for unknown, expired and revoked tokens and for tokens
+	// of inactive users
+	ErrInvalidAccessToken = errors.New("invalid or expired access token")
+
+	// ErrServiceAccountNotFound is returned when there is no service account with the given ID
+	ErrServiceAccountNotFound = errors.New("service account not found")
+)
+
+// Scope actions understood by project scopes
+const (
+	ScopeActionRead  = "read"
+	ScopeActionWrite = "write"
+)
+
+// AccessToken authenticates scripts and bots as a user or service account. Requests made with
+// the token are limited to its scopes.
+type AccessToken struct {
+	ID          string
+	UserID      string
+	Name        string
+	TokenPrefix string
+	TokenHash   string
+	Scopes      []string
+	CreatedBy   string
+	ExpiresAt   *time.Time
+	LastUsedAt  *time.Time
+	RevokedAt   *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// IsExpired checks if the token is past its expiry
+func (t *AccessToken) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// IsRevoked checks if the token has been revoked
+func (t *AccessToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// IsValid checks if the token may still be used
+func (t *AccessToken) IsValid(now time.Time) bool {
+	return !t.IsRevoked() && !t.IsExpired(now)
+}
+
+// UserScopes returns the token's scopes as scopes of its user, expiring with the token
+func (t *AccessToken) UserScopes() []UserScope {
+	scopes := make([]UserScope, len(t.Scopes))
+	for i, scope := range t.Scopes {
+		scopes[i] = UserScope{
+			UserID:    t.UserID,
+			Scope:     scope,
+			ExpiresAt: t.ExpiresAt,
+			GrantedBy: t.CreatedBy,
+			GrantedAt: t.CreatedAt,
+		}
+	}
+	return scopes
+}
+
+// ValidateProjectScope checks that a scope has the form project:ACTION:PROJECT_ID, where
+// ACTION is read, write or * and PROJECT_ID may be * for all projects
+func ValidateProjectScope(scope string) error {
+	parts := strings.Split(scope, ":")
+	if len(parts) != 3 || parts[0] != "project" || parts[2] == "" {
+		return fmt.Errorf("invalid scope %q: expected project:ACTION:PROJECT_ID", scope)
+	}
+	switch parts[1] {
+	case ScopeActionRead, ScopeActionWrite, "*":
+		return nil
+	default:
+		return fmt.Errorf("invalid scope %q: action must be read, write or *", scope)
+	}
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+)
+
+var (
+	// ErrCITrustPolicyNotFound is returned when a project has no trust policy with the given ID
+	ErrCITrustPolicyNotFound = errors.New("CI trust policy not found")
+
+	// ErrInvalidCIToken is returned for CI OIDC tokens that are malformed, expired, badly signed
+	// or issued by an issuer that is not configured
+	ErrInvalidCIToken = errors.New("invalid CI OIDC token")
+
+	// ErrNoMatchingTrustPolicy is returned for valid CI OIDC tokens that no project trusts
+	ErrNoMatchingTrustPolicy = errors.New("no CI trust policy matches the token")
+)
+
+// CIClaims are the claims of a verified CI OIDC token that trust policies match on
+type CIClaims struct {
+	Issuer     string // Name of the configured issuer
+	Subject    string
+	Repository string
+	Ref        string
+	Workflow   string
+}
+
+// CITrustPolicy grants CI jobs whose OIDC tokens match it permission to upload test reports to a
+// project. Repository, Ref and Workflow are glob patterns as understood by path.Match; empty Ref
+// and Workflow patterns match anything.
+type CITrustPolicy struct {
+	ID         string
+	ProjectID  string
+	Issuer     string
+	Repository string
+	Ref        string
+	Workflow   string
+	CreatedBy  string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Validate checks that the policy names an issuer and repository and that its patterns are valid
+func (p *CITrustPolicy) Validate() error {
+	if p.Issuer == "" {
+		return fmt.Errorf("issuer is required")
+	}
+	if p.Repository == "" {
+		return fmt.Errorf("repository is required")
+	}
+	for _, pattern := range []string{p.Repository, p.Ref, p.Workflow} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Matches checks if a token's claims satisfy the policy
+func (p *CITrustPolicy) Matches(claims *CIClaims) bool {
+	return p.Issuer == claims.Issuer &&
+		matchClaim(p.Repository, claims.Repository) &&
+		(p.Ref == "" || matchClaim(p.Ref, claims.Ref)) &&
+		(p.Workflow == "" || matchClaim(p.Workflow, claims.Workflow))
+}
+
+func matchClaim(pattern, value string) bool {
+	if value == "" {
+		return false
+	}
+	matched, err := path.Match(pattern, value)
+	return err == nil && matched
+}
+
+// CIIdentity is a CI job authenticated with an OIDC token, and the projects it may upload to
+type CIIdentity struct {
+	Claims     CIClaims
+	ProjectIDs []string
+}
+
+// AllowsProject checks if the job may upload test reports to a project
+func (i *CIIdentity) AllowsProject(projectID string) bool {
+	for _, id := range i.ProjectIDs {
+		if id == projectID {
+			return true
+		}
+	}
+	return false
+}
+
+// CITokenVerifier verifies OIDC ID tokens of the configured CI issuers
+type CITokenVerifier interface {
+	// Verify checks a token's signature and claims, failing with ErrInvalidCIToken if it is not
+	// acceptable
+	Verify(ctx context.Context, rawToken string) (*CIClaims, error)
+
+	// HasIssuer checks if an issuer with the given name is configured
+	HasIssuer(name string) bool
+}
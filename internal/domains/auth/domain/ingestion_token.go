@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrIngestionTokenNotFound is returned when a project has no token with the given ID
+	ErrIngestionTokenNotFound = errors.New("ingestion token not found")
+
+	// ErrIngestionTokenRevoked is returned when rotating a token that has been revoked
+	ErrIngestionTokenRevoked = errors.New("ingestion token has been revoked")
+
+	// ErrInvalidIngestionToken is returned for unknown, expired and revoked tokens
+	ErrInvalidIngestionToken = errors.New("invalid or expired ingestion token")
+)
+
+// IngestionToken authorizes CI systems to submit test reports for a single project
+type IngestionToken struct {
+	ID          string
+	ProjectID   string
+	Name        string
+	TokenPrefix string
+	TokenHash   string
+	CreatedBy   string
+	ExpiresAt   *time.Time
+	LastUsedAt  *time.Time
+	RevokedAt   *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// IsExpired checks if the token is past its expiry
+func (t *IngestionToken) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// IsRevoked checks if the token has been revoked
+func (t *IngestionToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// IsValid checks if the token may still be used
+func (t *IngestionToken) IsValid(now time.Time) bool {
+	return !t.IsRevoked() && !t.IsExpired(now)
+}
@@ -14,6 +14,7 @@ type UserRepository interface {
 	FindByEmail(ctx context.Context, email string) (*User, error)
 	FindByIDOrEmail(ctx context.Context, userID, email string) (*User, error)
 	UpdateLastLogin(ctx context.Context, userID string, loginTime time.Time) error
+	FindServiceAccounts(ctx context.Context) ([]*User, error)
 
 	// Group operations
 	SetUserGroups(ctx context.Context, userID string, groups []string) error
@@ -35,3 +36,34 @@ type SessionRepository interface {
 	InvalidateAllForUser(ctx context.Context, userID string) error
 	CleanupExpired(ctx context.Context) error
 }
+
+// IngestionTokenRepository defines the interface for ingestion token persistence
+type IngestionTokenRepository interface {
+	Create(ctx context.Context, token *IngestionToken) error
+	FindByID(ctx context.Context, tokenID string) (*IngestionToken, error)
+	FindByHash(ctx context.Context, tokenHash string) (*IngestionToken, error)
+	FindByProject(ctx context.Context, projectID string) ([]*IngestionToken, error)
+	UpdateSecret(ctx context.Context, tokenID, tokenPrefix, tokenHash string) error
+	Revoke(ctx context.Context, tokenID string, revokedAt time.Time) error
+	UpdateLastUsed(ctx context.Context, tokenID string, usedAt time.Time) error
+}
+
+// AccessTokenRepository defines the interface for personal access token persistence
+type AccessTokenRepository interface {
+	Create(ctx context.Context, token *AccessToken) error
+	FindByID(ctx context.Context, tokenID string) (*AccessToken, error)
+	FindByHash(ctx context.Context, tokenHash string) (*AccessToken, error)
+	FindByUser(ctx context.Context, userID string) ([]*AccessToken, error)
+	Revoke(ctx context.Context, tokenID string, revokedAt time.Time) error
+	RevokeAllForUser(ctx context.Context, userID string, revokedAt time.Time) error
+	UpdateLastUsed(ctx context.Context, tokenID string, usedAt time.Time) error
+}
+
+// CITrustPolicyRepository defines the interface for CI trust policy persistence
+type CITrustPolicyRepository interface {
+	Create(ctx context.Context, policy *CITrustPolicy) error
+	FindByID(ctx context.Context, policyID string) (*CITrustPolicy, error)
+	FindByProject(ctx context.Context, projectID string) ([]*CITrustPolicy, error)
+	FindByIssuer(ctx context.Context, issuer string) ([]*CITrustPolicy, error)
+	Delete(ctx context.Context, policyID string) error
+}
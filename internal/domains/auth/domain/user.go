@@ -16,8 +16,10 @@ type User struct {
 	ProfileURL    string
 	EmailVerified bool
 	LastLoginAt   *time.Time
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	// ServiceAccount marks non-human accounts that only authenticate with access tokens
+	ServiceAccount bool
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 
 	// Relationships
 	Groups []UserGroup
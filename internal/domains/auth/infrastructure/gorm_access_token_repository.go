@@ -0,0 +1,132 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/auth/domain"
+	"github.com/guidewire-oss/fern-platform/pkg/database"
+	"gorm.io/gorm"
+)
+
+// GormAccessTokenRepository implements AccessTokenRepository using GORM
+type GormAccessTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewGormAccessTokenRepository creates a new GORM-based access token repository
+func NewGormAccessTokenRepository(db *gorm.DB) *GormAccessTokenRepository {
+	return &GormAccessTokenRepository{db: db}
+}
+
+// Create stores a new token
+func (r *GormAccessTokenRepository) Create(ctx context.Context, token *domain.AccessToken) error {
+	dbToken := &database.AccessToken{
+		ID:          token.ID,
+		UserID:      token.UserID,
+		Name:        token.Name,
+		TokenPrefix: token.TokenPrefix,
+		TokenHash:   token.TokenHash,
+		Scopes:      strings.Join(token.Scopes, " "),
+		CreatedBy:   token.CreatedBy,
+		ExpiresAt:   token.ExpiresAt,
+		CreatedAt:   token.CreatedAt,
+		UpdatedAt:   token.UpdatedAt,
+	}
+
+	if err := r.db.WithContext(ctx).Create(dbToken).Error; err != nil {
+		return fmt.Errorf("failed to create access token: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID finds a token by ID
+func (r *GormAccessTokenRepository) FindByID(ctx context.Context, tokenID string) (*domain.AccessToken, error) {
+	return r.findOne(ctx, "id = ?", tokenID)
+}
+
+// FindByHash finds a token by the hash of its secret
+func (r *GormAccessTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.AccessToken, error) {
+	return r.findOne(ctx, "token_hash = ?", tokenHash)
+}
+
+// FindByUser finds all tokens of a user, newest first
+func (r *GormAccessTokenRepository) FindByUser(ctx context.Context, userID string) ([]*domain.AccessToken, error) {
+	var dbTokens []database.AccessToken
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&dbTokens).Error; err != nil {
+		return nil, fmt.Errorf("failed to find access tokens: %w", err)
+	}
+
+	tokens := make([]*domain.AccessToken, len(dbTokens))
+	for i := range dbTokens {
+		tokens[i] = r.toDomainToken(&dbTokens[i])
+	}
+	return tokens, nil
+}
+
+// Revoke marks a token as revoked
+func (r *GormAccessTokenRepository) Revoke(ctx context.Context, tokenID string, revokedAt time.Time) error {
+	result := r.db.WithContext(ctx).Model(&database.AccessToken{}).Where("id = ?", tokenID).Updates(map[string]interface{}{
+		"revoked_at": revokedAt,
+		"updated_at": revokedAt,
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke access token: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrAccessTokenNotFound
+	}
+	return nil
+}
+
+// RevokeAllForUser revokes all of a user's tokens that are not revoked yet
+func (r *GormAccessTokenRepository) RevokeAllForUser(ctx context.Context, userID string, revokedAt time.Time) error {
+	err := r.db.WithContext(ctx).Model(&database.AccessToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Updates(map[string]interface{}{
+			"revoked_at": revokedAt,
+			"updated_at": revokedAt,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to revoke access tokens: %w", err)
+	}
+	return nil
+}
+
+// UpdateLastUsed records when a token was last used
+func (r *GormAccessTokenRepository) UpdateLastUsed(ctx context.Context, tokenID string, usedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&database.AccessToken{}).Where("id = ?", tokenID).Update("last_used_at", usedAt).Error
+}
+
+func (r *GormAccessTokenRepository) findOne(ctx context.Context, query string, arg interface{}) (*domain.AccessToken, error) {
+	var dbToken database.AccessToken
+	if err := r.db.WithContext(ctx).Where(query, arg).First(&dbToken).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrAccessTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to find access token: %w", err)
+	}
+
+	return r.toDomainToken(&dbToken), nil
+}
+
+// Helper method to convert database token to domain token
+func (r *GormAccessTokenRepository) toDomainToken(dbToken *database.AccessToken) *domain.AccessToken {
+	return &domain.AccessToken{
+		ID:          dbToken.ID,
+		UserID:      dbToken.UserID,
+		Name:        dbToken.Name,
+		TokenPrefix: dbToken.TokenPrefix,
+		TokenHash:   dbToken.TokenHash,
+		Scopes:      strings.Fields(dbToken.Scopes),
+		CreatedBy:   dbToken.CreatedBy,
+		ExpiresAt:   dbToken.ExpiresAt,
+		LastUsedAt:  dbToken.LastUsedAt,
+		RevokedAt:   dbToken.RevokedAt,
+		CreatedAt:   dbToken.CreatedAt,
+		UpdatedAt:   dbToken.UpdatedAt,
+	}
+}
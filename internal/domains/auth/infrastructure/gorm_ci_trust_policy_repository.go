@@ -0,0 +1,104 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/auth/domain"
+	"github.com/guidewire-oss/fern-platform/pkg/database"
+	"gorm.io/gorm"
+)
+
+// GormCITrustPolicyRepository implements CITrustPolicyRepository using GORM
+type GormCITrustPolicyRepository struct {
+	db *gorm.DB
+}
+
+// NewGormCITrustPolicyRepository creates a new GORM-based CI trust policy repository
+func NewGormCITrustPolicyRepository(db *gorm.DB) *GormCITrustPolicyRepository {
+	return &GormCITrustPolicyRepository{db: db}
+}
+
+// Create stores a new policy
+func (r *GormCITrustPolicyRepository) Create(ctx context.Context, policy *domain.CITrustPolicy) error {
+	dbPolicy := &database.CITrustPolicy{
+		ID:         policy.ID,
+		ProjectID:  policy.ProjectID,
+		Issuer:     policy.Issuer,
+		Repository: policy.Repository,
+		Ref:        policy.Ref,
+		Workflow:   policy.Workflow,
+		CreatedBy:  policy.CreatedBy,
+		CreatedAt:  policy.CreatedAt,
+		UpdatedAt:  policy.UpdatedAt,
+	}
+
+	if err := r.db.WithContext(ctx).Create(dbPolicy).Error; err != nil {
+		return fmt.Errorf("failed to create CI trust policy: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID finds a policy by ID
+func (r *GormCITrustPolicyRepository) FindByID(ctx context.Context, policyID string) (*domain.CITrustPolicy, error) {
+	var dbPolicy database.CITrustPolicy
+	if err := r.db.WithContext(ctx).Where("id = ?", policyID).First(&dbPolicy).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrCITrustPolicyNotFound
+		}
+		return nil, fmt.Errorf("failed to find CI trust policy: %w", err)
+	}
+
+	return r.toDomainPolicy(&dbPolicy), nil
+}
+
+// FindByProject finds all policies of a project, oldest first
+func (r *GormCITrustPolicyRepository) FindByProject(ctx context.Context, projectID string) ([]*domain.CITrustPolicy, error) {
+	return r.findAll(ctx, "project_id = ?", projectID)
+}
+
+// FindByIssuer finds all policies for an issuer
+func (r *GormCITrustPolicyRepository) FindByIssuer(ctx context.Context, issuer string) ([]*domain.CITrustPolicy, error) {
+	return r.findAll(ctx, "issuer = ?", issuer)
+}
+
+// Delete removes a policy
+func (r *GormCITrustPolicyRepository) Delete(ctx context.Context, policyID string) error {
+	result := r.db.WithContext(ctx).Where("id = ?", policyID).Delete(&database.CITrustPolicy{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete CI trust policy: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrCITrustPolicyNotFound
+	}
+	return nil
+}
+
+func (r *GormCITrustPolicyRepository) findAll(ctx context.Context, query string, arg interface{}) ([]*domain.CITrustPolicy, error) {
+	var dbPolicies []database.CITrustPolicy
+	if err := r.db.WithContext(ctx).Where(query, arg).Order("created_at").Find(&dbPolicies).Error; err != nil {
+		return nil, fmt.Errorf("failed to find CI trust policies: %w", err)
+	}
+
+	policies := make([]*domain.CITrustPolicy, len(dbPolicies))
+	for i := range dbPolicies {
+		policies[i] = r.toDomainPolicy(&dbPolicies[i])
+	}
+	return policies, nil
+}
+
+// Helper method to convert database policy to domain policy
+func (r *GormCITrustPolicyRepository) toDomainPolicy(dbPolicy *database.CITrustPolicy) *domain.CITrustPolicy {
+	return &domain.CITrustPolicy{
+		ID:         dbPolicy.ID,
+		ProjectID:  dbPolicy.ProjectID,
+		Issuer:     dbPolicy.Issuer,
+		Repository: dbPolicy.Repository,
+		Ref:        dbPolicy.Ref,
+		Workflow:   dbPolicy.Workflow,
+		CreatedBy:  dbPolicy.CreatedBy,
+		CreatedAt:  dbPolicy.CreatedAt,
+		UpdatedAt:  dbPolicy.UpdatedAt,
+	}
+}
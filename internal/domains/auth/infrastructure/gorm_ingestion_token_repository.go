@@ -0,0 +1,131 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/auth/domain"
+	"github.com/guidewire-oss/fern-platform/pkg/database"
+	"gorm.io/gorm"
+)
+
+// GormIngestionTokenRepository implements IngestionTokenRepository using GORM
+type GormIngestionTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewGormIngestionTokenRepository creates a new GORM-based ingestion token repository
+func NewGormIngestionTokenRepository(db *gorm.DB) *GormIngestionTokenRepository {
+	return &GormIngestionTokenRepository{db: db}
+}
+
+// Create stores a new token
+func (r *GormIngestionTokenRepository) Create(ctx context.Context, token *domain.IngestionToken) error {
+	dbToken := &database.IngestionToken{
+		ID:          token.ID,
+		ProjectID:   token.ProjectID,
+		Name:        token.Name,
+		TokenPrefix: token.TokenPrefix,
+		TokenHash:   token.TokenHash,
+		CreatedBy:   token.CreatedBy,
+		ExpiresAt:   token.ExpiresAt,
+		CreatedAt:   token.CreatedAt,
+		UpdatedAt:   token.UpdatedAt,
+	}
+
+	if err := r.db.WithContext(ctx).Create(dbToken).Error; err != nil {
+		return fmt.Errorf("failed to create ingestion token: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID finds a token by ID
+func (r *GormIngestionTokenRepository) FindByID(ctx context.Context, tokenID string) (*domain.IngestionToken, error) {
+	return r.findOne(ctx, "id = ?", tokenID)
+}
+
+// FindByHash finds a token by the hash of its secret
+func (r *GormIngestionTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.IngestionToken, error) {
+	return r.findOne(ctx, "token_hash = ?", tokenHash)
+}
+
+// FindByProject finds all tokens of a project, newest first
+func (r *GormIngestionTokenRepository) FindByProject(ctx context.Context, projectID string) ([]*domain.IngestionToken, error) {
+	var dbTokens []database.IngestionToken
+	if err := r.db.WithContext(ctx).Where("project_id = ?", projectID).Order("created_at DESC").Find(&dbTokens).Error; err != nil {
+		return nil, fmt.Errorf("failed to find ingestion tokens: %w", err)
+	}
+
+	tokens := make([]*domain.IngestionToken, len(dbTokens))
+	for i := range dbTokens {
+		tokens[i] = r.toDomainToken(&dbTokens[i])
+	}
+	return tokens, nil
+}
+
+// UpdateSecret replaces a token's secret
+func (r *GormIngestionTokenRepository) UpdateSecret(ctx context.Context, tokenID, tokenPrefix, tokenHash string) error {
+	return r.update(ctx, tokenID, map[string]interface{}{
+		"token_prefix": tokenPrefix,
+		"token_hash":   tokenHash,
+		"last_used_at": nil,
+		"updated_at":   time.Now(),
+	})
+}
+
+// Revoke marks a token as revoked
+func (r *GormIngestionTokenRepository) Revoke(ctx context.Context, tokenID string, revokedAt time.Time) error {
+	return r.update(ctx, tokenID, map[string]interface{}{
+		"revoked_at": revokedAt,
+		"updated_at": revokedAt,
+	})
+}
+
+// UpdateLastUsed records when a token was last used
+func (r *GormIngestionTokenRepository) UpdateLastUsed(ctx context.Context, tokenID string, usedAt time.Time) error {
+	return r.update(ctx, tokenID, map[string]interface{}{
+		"last_used_at": usedAt,
+	})
+}
+
+func (r *GormIngestionTokenRepository) findOne(ctx context.Context, query string, arg interface{}) (*domain.IngestionToken, error) {
+	var dbToken database.IngestionToken
+	if err := r.db.WithContext(ctx).Where(query, arg).First(&dbToken).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrIngestionTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to find ingestion token: %w", err)
+	}
+
+	return r.toDomainToken(&dbToken), nil
+}
+
+func (r *GormIngestionTokenRepository) update(ctx context.Context, tokenID string, updates map[string]interface{}) error {
+	result := r.db.WithContext(ctx).Model(&database.IngestionToken{}).Where("id = ?", tokenID).Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update ingestion token: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrIngestionTokenNotFound
+	}
+	return nil
+}
+
+// Helper method to convert database token to domain token
+func (r *GormIngestionTokenRepository) toDomainToken(dbToken *database.IngestionToken) *domain.IngestionToken {
+	return &domain.IngestionToken{
+		ID:          dbToken.ID,
+		ProjectID:   dbToken.ProjectID,
+		Name:        dbToken.Name,
+		TokenPrefix: dbToken.TokenPrefix,
+		TokenHash:   dbToken.TokenHash,
+		CreatedBy:   dbToken.CreatedBy,
+		ExpiresAt:   dbToken.ExpiresAt,
+		LastUsedAt:  dbToken.LastUsedAt,
+		RevokedAt:   dbToken.RevokedAt,
+		CreatedAt:   dbToken.CreatedAt,
+		UpdatedAt:   dbToken.UpdatedAt,
+	}
+}
@@ -23,16 +23,17 @@ func NewGormUserRepository(db *gorm.DB) *GormUserRepository {
 // Create creates a new user
 func (r *GormUserRepository) Create(ctx context.Context, user *domain.User) error {
 	dbUser := &database.User{
-		UserID:        user.UserID,
-		Email:         user.Email,
-		Name:          user.Name,
-		FirstName:     user.FirstName,
-		LastName:      user.LastName,
-		Role:          string(user.Role),
-		Status:        string(user.Status),
-		ProfileURL:    user.ProfileURL,
-		EmailVerified: user.EmailVerified,
-		LastLoginAt:   user.LastLoginAt,
+		UserID:         user.UserID,
+		Email:          user.Email,
+		Name:           user.Name,
+		FirstName:      user.FirstName,
+		LastName:       user.LastName,
+		Role:           string(user.Role),
+		Status:         string(user.Status),
+		ProfileURL:     user.ProfileURL,
+		EmailVerified:  user.EmailVerified,
+		LastLoginAt:    user.LastLoginAt,
+		ServiceAccount: user.ServiceAccount,
 	}
 
 	if err := r.db.WithContext(ctx).Create(dbUser).Error; err != nil {
@@ -112,6 +113,20 @@ func (r *GormUserRepository) UpdateLastLogin(ctx context.Context, userID string,
 	return r.db.WithContext(ctx).Model(&database.User{}).Where("user_id = ?", userID).Update("last_login_at", loginTime).Error
 }
 
+// FindServiceAccounts finds all service accounts, ordered by name
+func (r *GormUserRepository) FindServiceAccounts(ctx context.Context) ([]*domain.User, error) {
+	var dbUsers []database.User
+	if err := r.db.WithContext(ctx).Where("service_account = ?", true).Order("name").Find(&dbUsers).Error; err != nil {
+		return nil, fmt.Errorf("failed to find service accounts: %w", err)
+	}
+
+	users := make([]*domain.User, len(dbUsers))
+	for i := range dbUsers {
+		users[i] = r.toDomainUser(&dbUsers[i])
+	}
+	return users, nil
+}
+
 // SetUserGroups sets the user's group memberships
 func (r *GormUserRepository) SetUserGroups(ctx context.Context, userID string, groups []string) error {
 	// Start transaction
@@ -198,20 +213,21 @@ func (r *GormUserRepository) GetUserScopes(ctx context.Context, userID string) (
 // Helper method to convert database user to domain user
 func (r *GormUserRepository) toDomainUser(dbUser *database.User) *domain.User {
 	user := &domain.User{
-		UserID:        dbUser.UserID,
-		Email:         dbUser.Email,
-		Name:          dbUser.Name,
-		FirstName:     dbUser.FirstName,
-		LastName:      dbUser.LastName,
-		Role:          domain.UserRole(dbUser.Role),
-		Status:        domain.UserStatus(dbUser.Status),
-		ProfileURL:    dbUser.ProfileURL,
-		EmailVerified: dbUser.EmailVerified,
-		LastLoginAt:   dbUser.LastLoginAt,
-		CreatedAt:     dbUser.CreatedAt,
-		UpdatedAt:     dbUser.UpdatedAt,
-		Groups:        make([]domain.UserGroup, len(dbUser.UserGroups)),
-		Scopes:        make([]domain.UserScope, len(dbUser.UserScopes)),
+		UserID:         dbUser.UserID,
+		Email:          dbUser.Email,
+		Name:           dbUser.Name,
+		FirstName:      dbUser.FirstName,
+		LastName:       dbUser.LastName,
+		Role:           domain.UserRole(dbUser.Role),
+		Status:         domain.UserStatus(dbUser.Status),
+		ProfileURL:     dbUser.ProfileURL,
+		EmailVerified:  dbUser.EmailVerified,
+		LastLoginAt:    dbUser.LastLoginAt,
+		ServiceAccount: dbUser.ServiceAccount,
+		CreatedAt:      dbUser.CreatedAt,
+		UpdatedAt:      dbUser.UpdatedAt,
+		Groups:         make([]domain.UserGroup, len(dbUser.UserGroups)),
+		Scopes:         make([]domain.UserScope, len(dbUser.UserScopes)),
 	}
 
 	for i, group := range dbUser.UserGroups {
@@ -0,0 +1,128 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/guidewire-oss/fern-platform/internal/domains/auth/domain"
+	"github.com/guidewire-oss/fern-platform/pkg/config"
+	"github.com/guidewire-oss/fern-platform/pkg/middleware"
+)
+
+// OIDCCITokenVerifier verifies OIDC ID tokens issued by CI systems against the JWKS of the
+// configured issuers
+type OIDCCITokenVerifier struct {
+	issuers map[string]*ciIssuer // keyed by issuer URL
+	client  *http.Client
+}
+
+// ciIssuer is a configured issuer and its lazily created key set
+type ciIssuer struct {
+	config config.CIOIDCIssuerConfig
+
+	mu   sync.Mutex
+	jwks *middleware.JWKS
+}
+
+// NewOIDCCITokenVerifier creates a verifier for the configured issuers
+func NewOIDCCITokenVerifier(issuers []config.CIOIDCIssuerConfig, client *http.Client) *OIDCCITokenVerifier {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	v := &OIDCCITokenVerifier{
+		issuers: make(map[string]*ciIssuer, len(issuers)),
+		client:  client,
+	}
+	for _, issuer := range issuers {
+		if issuer.RepositoryClaim == "" {
+			issuer.RepositoryClaim = "repository"
+		}
+		if issuer.RefClaim == "" {
+			issuer.RefClaim = "ref"
+		}
+		if issuer.WorkflowClaim == "" {
+			issuer.WorkflowClaim = "workflow"
+		}
+		v.issuers[issuer.IssuerURL] = &ciIssuer{config: issuer}
+	}
+	return v
+}
+
+// Verify checks a token's signature, expiry, issuer and audience and extracts its claims
+func (v *OIDCCITokenVerifier) Verify(ctx context.Context, rawToken string) (*domain.CIClaims, error) {
+	// The issuer decides which keys the token must be signed with, so read it before verifying
+	unverified, _, err := jwt.NewParser().ParseUnverified(rawToken, jwt.MapClaims{})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidCIToken, err)
+	}
+	iss, _ := unverified.Claims.GetIssuer()
+	issuer, ok := v.issuers[iss]
+	if !ok {
+		return nil, fmt.Errorf("%w: untrusted issuer %q", domain.ErrInvalidCIToken, iss)
+	}
+
+	jwks, err := v.keySet(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := middleware.ParseJWT(rawToken, jwks.Keyfunc, middleware.JWTValidation{
+		Issuer:        issuer.config.IssuerURL,
+		Audience:      issuer.config.Audience,
+		Methods:       middleware.AsymmetricSigningMethods,
+		RequireExpiry: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidCIToken, err)
+	}
+
+	subject, _ := claims.GetSubject()
+	return &domain.CIClaims{
+		Issuer:     issuer.config.Name,
+		Subject:    subject,
+		Repository: stringClaim(claims, issuer.config.RepositoryClaim),
+		Ref:        stringClaim(claims, issuer.config.RefClaim),
+		Workflow:   stringClaim(claims, issuer.config.WorkflowClaim),
+	}, nil
+}
+
+// HasIssuer checks if an issuer with the given name is configured
+func (v *OIDCCITokenVerifier) HasIssuer(name string) bool {
+	for _, issuer := range v.issuers {
+		if issuer.config.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// keySet returns the issuer's key set, discovering its JWKS URL on first use unless configured
+func (v *OIDCCITokenVerifier) keySet(ctx context.Context, issuer *ciIssuer) (*middleware.JWKS, error) {
+	issuer.mu.Lock()
+	defer issuer.mu.Unlock()
+
+	if issuer.jwks != nil {
+		return issuer.jwks, nil
+	}
+
+	url := issuer.config.JWKSUrl
+	if url == "" {
+		discovered, err := middleware.DiscoverJWKSURL(ctx, v.client, issuer.config.IssuerURL)
+		if err != nil {
+			return nil, err
+		}
+		url = discovered
+	}
+
+	issuer.jwks = middleware.NewJWKS(url, v.client)
+	return issuer.jwks, nil
+}
+
+func stringClaim(claims jwt.MapClaims, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
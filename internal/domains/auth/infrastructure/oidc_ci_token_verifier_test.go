@@ -0,0 +1,179 @@
+package infrastructure_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/guidewire-oss/fern-platform/internal/domains/auth/domain"
+	"github.com/guidewire-oss/fern-platform/internal/domains/auth/infrastructure"
+	"github.com/guidewire-oss/fern-platform/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testIssuer is a local OIDC issuer publishing its discovery document and JWKS
+type testIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	kid    string
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	issuer := &testIssuer{key: key, kid: "key-1"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   issuer.server.URL,
+			"jwks_uri": issuer.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": issuer.kid,
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func (i *testIssuer) config() config.CIOIDCIssuerConfig {
+	return config.CIOIDCIssuerConfig{
+		Name:      "github-actions",
+		IssuerURL: i.server.URL,
+		Audience:  "fern-platform",
+	}
+}
+
+func (i *testIssuer) sign(t *testing.T, key *rsa.PrivateKey, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = i.kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func (i *testIssuer) claims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":        i.server.URL,
+		"aud":        "fern-platform",
+		"sub":        "repo:acme/widgets:ref:refs/heads/main",
+		"repository": "acme/widgets",
+		"ref":        "refs/heads/main",
+		"workflow":   "CI",
+		"exp":        time.Now().Add(5 * time.Minute).Unix(),
+		"iat":        time.Now().Unix(),
+	}
+}
+
+func TestOIDCCITokenVerifier_Verify_ValidToken(t *testing.T) {
+	// Arrange
+	issuer := newTestIssuer(t)
+	verifier := infrastructure.NewOIDCCITokenVerifier([]config.CIOIDCIssuerConfig{issuer.config()}, issuer.server.Client())
+
+	// Act
+	claims, err := verifier.Verify(context.Background(), issuer.sign(t, issuer.key, issuer.claims()))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, &domain.CIClaims{
+		Issuer:     "github-actions",
+		Subject:    "repo:acme/widgets:ref:refs/heads/main",
+		Repository: "acme/widgets",
+		Ref:        "refs/heads/main",
+		Workflow:   "CI",
+	}, claims)
+}
+
+func TestOIDCCITokenVerifier_Verify_MapsConfiguredClaims(t *testing.T) {
+	// Arrange
+	issuer := newTestIssuer(t)
+	cfg := issuer.config()
+	cfg.Name = "gitlab"
+	cfg.JWKSUrl = issuer.server.URL + "/jwks"
+	cfg.RepositoryClaim = "project_path"
+	cfg.WorkflowClaim = "ci_config_ref_uri"
+	verifier := infrastructure.NewOIDCCITokenVerifier([]config.CIOIDCIssuerConfig{cfg}, issuer.server.Client())
+
+	tokenClaims := issuer.claims()
+	tokenClaims["project_path"] = "acme/widgets"
+	tokenClaims["ref"] = "main"
+	tokenClaims["ci_config_ref_uri"] = "gitlab.com/acme/widgets//.gitlab-ci.yml@refs/heads/main"
+	delete(tokenClaims, "repository")
+
+	// Act
+	claims, err := verifier.Verify(context.Background(), issuer.sign(t, issuer.key, tokenClaims))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "gitlab", claims.Issuer)
+	assert.Equal(t, "acme/widgets", claims.Repository)
+	assert.Equal(t, "main", claims.Ref)
+	assert.Equal(t, "gitlab.com/acme/widgets//.gitlab-ci.yml@refs/heads/main", claims.Workflow)
+}
+
+func TestOIDCCITokenVerifier_Verify_RejectsInvalidTokens(t *testing.T) {
+	issuer := newTestIssuer(t)
+	verifier := infrastructure.NewOIDCCITokenVerifier([]config.CIOIDCIssuerConfig{issuer.config()}, issuer.server.Client())
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	withClaim := func(name string, value interface{}) jwt.MapClaims {
+		claims := issuer.claims()
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+
+	tests := map[string]string{
+		"expired":          issuer.sign(t, issuer.key, withClaim("exp", time.Now().Add(-time.Minute).Unix())),
+		"without expiry":   issuer.sign(t, issuer.key, withClaim("exp", nil)),
+		"wrong audience":   issuer.sign(t, issuer.key, withClaim("aud", "someone-else")),
+		"untrusted issuer": issuer.sign(t, issuer.key, withClaim("iss", "https://evil.example.com")),
+		"wrong key":        issuer.sign(t, otherKey, issuer.claims()),
+		"not a JWT":        "not.a.jwt",
+	}
+
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			_, err := verifier.Verify(context.Background(), token)
+
+			// Assert
+			assert.ErrorIs(t, err, domain.ErrInvalidCIToken)
+		})
+	}
+}
+
+func TestOIDCCITokenVerifier_HasIssuer(t *testing.T) {
+	verifier := infrastructure.NewOIDCCITokenVerifier([]config.CIOIDCIssuerConfig{{
+		Name:      "github-actions",
+		IssuerURL: "https://token.actions.githubusercontent.com",
+		Audience:  "fern-platform",
+	}}, nil)
+
+	assert.True(t, verifier.HasIssuer("github-actions"))
+	assert.False(t, verifier.HasIssuer("gitlab"))
+}
@@ -0,0 +1,135 @@
+package interfaces
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/guidewire-oss/fern-platform/internal/domains/auth/domain"
+)
+
+const (
+	// ingestionTokenKey holds the authenticated ingestion token on the gin context
+	ingestionTokenKey = "ingestion_token"
+
+	// ciIdentityKey holds the CI job authenticated with an OIDC token on the gin context
+	ciIdentityKey = "ci_identity"
+)
+
+// RequireIngestionToken middleware authenticates test report submissions with a project-scoped
+// ingestion token or a CI OIDC ID token sent as "Authorization: Bearer <token>". Routes with a
+// :projectId parameter are rejected if the token is not valid for the project; handlers that read
+// the project from the request body check it with IngestionTokenAllowsProject.
+func (m *AuthMiddlewareAdapter) RequireIngestionToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !m.config.Enabled {
+			c.Next()
+			return
+		}
+
+		secret, found := bearerToken(c)
+		if !found {
+			c.JSON(401, gin.H{"error": "Ingestion token required"})
+			c.Abort()
+			return
+		}
+
+		if isJWT(secret) {
+			m.authenticateCIToken(c, secret)
+			return
+		}
+
+		token, err := m.ingestionTokenService.Authenticate(c.Request.Context(), secret)
+		if err != nil {
+			if errors.Is(err, domain.ErrInvalidIngestionToken) {
+				c.JSON(401, gin.H{"error": err.Error()})
+			} else {
+				m.logger.WithError(err).Error("Failed to authenticate ingestion token")
+				c.JSON(500, gin.H{"error": "Failed to authenticate ingestion token"})
+			}
+			c.Abort()
+			return
+		}
+
+		c.Set(ingestionTokenKey, token)
+		m.requireIngestionProjectParam(c)
+	}
+}
+
+// authenticateCIToken authenticates a submission with an OIDC ID token issued by a CI system.
+// The token may upload to every project with a matching trust policy.
+func (m *AuthMiddlewareAdapter) authenticateCIToken(c *gin.Context, rawToken string) {
+	identity, err := m.ciFederationService.Authenticate(c.Request.Context(), rawToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidCIToken):
+			m.logger.WithRequest(c.GetString("request_id"), c.Request.Method, c.Request.URL.Path).
+				WithError(err).Debug("CI OIDC token rejected")
+			c.JSON(401, gin.H{"error": domain.ErrInvalidCIToken.Error()})
+		case errors.Is(err, domain.ErrNoMatchingTrustPolicy):
+			c.JSON(403, gin.H{"error": err.Error()})
+		default:
+			m.logger.WithError(err).Error("Failed to authenticate CI OIDC token")
+			c.JSON(500, gin.H{"error": "Failed to authenticate CI OIDC token"})
+		}
+		c.Abort()
+		return
+	}
+
+	c.Set(ciIdentityKey, identity)
+	m.requireIngestionProjectParam(c)
+}
+
+// requireIngestionProjectParam continues the chain if the authenticated token is valid for the
+// route's :projectId, if any, and rejects the request otherwise
+func (m *AuthMiddlewareAdapter) requireIngestionProjectParam(c *gin.Context) {
+	if projectID := c.Param("projectId"); projectID != "" && !IngestionTokenAllowsProject(c, projectID) {
+		m.logger.WithRequest(c.GetString("request_id"), c.Request.Method, c.Request.URL.Path).
+			WithField("project_id", projectID).
+			Warn("Ingestion token used for another project")
+		c.JSON(403, gin.H{"error": "Ingestion token is not valid for this project"})
+		c.Abort()
+		return
+	}
+
+	c.Next()
+}
+
+// isJWT tells OIDC ID tokens, which are JWTs of three dot-separated parts, from ingestion tokens
+func isJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// GetIngestionToken extracts the authenticated ingestion token from Gin context
+func GetIngestionToken(c *gin.Context) (*domain.IngestionToken, bool) {
+	token, exists := c.Get(ingestionTokenKey)
+	if !exists {
+		return nil, false
+	}
+
+	t, ok := token.(*domain.IngestionToken)
+	return t, ok
+}
+
+// GetCIIdentity extracts the CI job authenticated with an OIDC token from Gin context
+func GetCIIdentity(c *gin.Context) (*domain.CIIdentity, bool) {
+	identity, exists := c.Get(ciIdentityKey)
+	if !exists {
+		return nil, false
+	}
+
+	i, ok := identity.(*domain.CIIdentity)
+	return i, ok
+}
+
+// IngestionTokenAllowsProject checks if the request may submit results for a project. Requests
+// that were let through without a token, because authentication is disabled, are allowed.
+func IngestionTokenAllowsProject(c *gin.Context, projectID string) bool {
+	if token, exists := GetIngestionToken(c); exists {
+		return token.ProjectID == projectID
+	}
+	if identity, exists := GetCIIdentity(c); exists {
+		return identity.AllowsProject(projectID)
+	}
+	return true
+}
@@ -0,0 +1,167 @@
+package interfaces_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/auth/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/auth/domain"
+	"github.com/guidewire-oss/fern-platform/internal/domains/auth/interfaces"
+	"github.com/guidewire-oss/fern-platform/pkg/config"
+	"github.com/guidewire-oss/fern-platform/pkg/logging"
+)
+
+func TestInterfaces(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Auth Interfaces Suite")
+}
+
+const tokenSecret = "fern_pat_secret"
+
+// tokenRepository holds a single access token, found by the hash of tokenSecret
+type tokenRepository struct {
+	domain.AccessTokenRepository
+	token *domain.AccessToken
+}
+
+func (r *tokenRepository) FindByHash(_ context.Context, tokenHash string) (*domain.AccessToken, error) {
+	if r.token == nil || tokenHash != application.HashToken(tokenSecret) {
+		return nil, domain.ErrAccessTokenNotFound
+	}
+	return r.token, nil
+}
+
+func (r *tokenRepository) UpdateLastUsed(context.Context, string, time.Time) error {
+	return nil
+}
+
+// userRepository holds the owner of the access token
+type userRepository struct {
+	domain.UserRepository
+}
+
+func (r *userRepository) FindByID(_ context.Context, userID string) (*domain.User, error) {
+	return &domain.User{UserID: userID, Role: domain.RoleAdmin, Status: domain.StatusActive}, nil
+}
+
+var _ = Describe("AuthMiddlewareAdapter", Label("unit", "interfaces", "auth"), func() {
+	var (
+		tokens            *tokenRepository
+		authConfig        *config.AuthConfig
+		router            *gin.Engine
+		attachmentProject string
+	)
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+
+		logger, err := logging.NewLogger(&config.LoggingConfig{Level: "error", Format: "json"})
+		Expect(err).NotTo(HaveOccurred())
+
+		tokens = &tokenRepository{}
+		authConfig = &config.AuthConfig{Enabled: true}
+		accessTokenService := application.NewAccessTokenService(tokens, &userRepository{})
+		middleware := interfaces.NewAuthMiddlewareAdapter(nil, nil, nil, accessTokenService, nil, nil, authConfig, logger)
+
+		ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+		router = gin.New()
+		router.GET("/api/v1/admin/users", middleware.RequireAdmin(), ok)
+		router.GET("/api/v1/manager/projects", middleware.RequireManager(), ok)
+		api := router.Group("/api/v1", middleware.RequireAuth())
+		api.GET("/projects/:projectId/flaky-tests", ok)
+		api.GET("/test-runs/:id", ok)
+		api.POST("/test-runs/:id/attachments", ok)
+		api.GET("/projects/:projectId/attachments/:id", func(c *gin.Context) {
+			// The attachment loaded belongs to attachmentProject
+			if interfaces.CheckProjectScope(c, attachmentProject) {
+				c.Status(http.StatusOK)
+			}
+		})
+	})
+
+	request := func(method, path string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+tokenSecret)
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	anonymousRequest := func(path string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	withScopes := func(scopes ...string) {
+		tokens.token = &domain.AccessToken{ID: "token-1", UserID: "bot", Scopes: scopes}
+	}
+
+	Describe("access token scopes", func() {
+		It("should allow a token scoped to the project in the path", func() {
+			withScopes("project:read:proj-a")
+
+			Expect(request(http.MethodGet, "/api/v1/projects/proj-a/flaky-tests")).To(Equal(http.StatusOK))
+			Expect(request(http.MethodGet, "/api/v1/projects/proj-b/flaky-tests")).To(Equal(http.StatusForbidden))
+		})
+
+		It("should not take the project from the query string", func() {
+			withScopes("project:write:proj-a")
+
+			Expect(request(http.MethodGet, "/api/v1/test-runs/7?projectId=proj-a")).To(Equal(http.StatusForbidden))
+			Expect(request(http.MethodPost, "/api/v1/test-runs/7/attachments?projectId=proj-a")).To(Equal(http.StatusForbidden))
+		})
+
+		It("should allow a token scoped to all projects on routes without a project", func() {
+			withScopes("project:read:*")
+
+			Expect(request(http.MethodGet, "/api/v1/test-runs/7")).To(Equal(http.StatusOK))
+			Expect(request(http.MethodPost, "/api/v1/test-runs/7/attachments")).To(Equal(http.StatusForbidden))
+		})
+
+		It("should reject unknown tokens", func() {
+			Expect(request(http.MethodGet, "/api/v1/test-runs/7")).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	Describe("role checks", func() {
+		It("should refuse anonymous requests when OAuth is disabled", func() {
+			Expect(anonymousRequest("/api/v1/admin/users")).To(Equal(http.StatusForbidden))
+			Expect(anonymousRequest("/api/v1/manager/projects")).To(Equal(http.StatusForbidden))
+		})
+
+		It("should refuse access tokens, which do not carry the role of their owner", func() {
+			withScopes("project:read:*")
+
+			Expect(request(http.MethodGet, "/api/v1/admin/users")).To(Equal(http.StatusForbidden))
+			Expect(request(http.MethodGet, "/api/v1/manager/projects")).To(Equal(http.StatusForbidden))
+		})
+
+		It("should allow anonymous requests when authentication is disabled", func() {
+			authConfig.Enabled = false
+
+			Expect(anonymousRequest("/api/v1/admin/users")).To(Equal(http.StatusOK))
+			Expect(anonymousRequest("/api/v1/manager/projects")).To(Equal(http.StatusOK))
+		})
+	})
+
+	Describe("CheckProjectScope", func() {
+		It("should refuse a token without a scope on the project of the resource loaded", func() {
+			withScopes("project:read:proj-a")
+
+			attachmentProject = "proj-a"
+			Expect(request(http.MethodGet, "/api/v1/projects/proj-a/attachments/3")).To(Equal(http.StatusOK))
+
+			attachmentProject = "proj-b"
+			Expect(request(http.MethodGet, "/api/v1/projects/proj-a/attachments/3")).To(Equal(http.StatusForbidden))
+		})
+	})
+})
@@ -0,0 +1,52 @@
+// Package events lets one domain react to what happened in another without depending on it
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// Event is something that happened in a domain that other domains may react to
+type Event interface {
+	// EventName identifies the kind of event subscribers register for
+	EventName() string
+}
+
+// Handler reacts to a published event. Handlers run on the publisher's goroutine, so anything
+// slow should be handed off rather than done inline.
+type Handler func(ctx context.Context, event Event)
+
+// Publisher publishes domain events
+type Publisher interface {
+	Publish(ctx context.Context, event Event)
+}
+
+// Bus delivers published events to the handlers subscribed to their name, in the order they
+// subscribed
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+// NewBus creates an event bus without subscribers
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[string][]Handler)}
+}
+
+// Subscribe registers a handler for events with the given name
+func (b *Bus) Subscribe(name string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[name] = append(b.handlers[name], handler)
+}
+
+// Publish delivers an event to its subscribers
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	b.mu.RLock()
+	handlers := b.handlers[event.EventName()]
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(ctx, event)
+	}
+}
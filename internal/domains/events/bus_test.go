@@ -0,0 +1,72 @@
+package events_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/events"
+)
+
+func TestEvents(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Events Suite")
+}
+
+// namedEvent is an event identified only by its name
+type namedEvent string
+
+func (e namedEvent) EventName() string {
+	return string(e)
+}
+
+var _ = Describe("Bus", Label("unit", "events"), func() {
+	var (
+		bus *events.Bus
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		bus = events.NewBus()
+		ctx = context.Background()
+	})
+
+	It("should deliver an event to its subscribers in the order they subscribed", func() {
+		var delivered []string
+		bus.Subscribe("run.completed", func(_ context.Context, event events.Event) {
+			delivered = append(delivered, "first:"+event.EventName())
+		})
+		bus.Subscribe("run.completed", func(_ context.Context, event events.Event) {
+			delivered = append(delivered, "second:"+event.EventName())
+		})
+
+		bus.Publish(ctx, namedEvent("run.completed"))
+
+		Expect(delivered).To(Equal([]string{"first:run.completed", "second:run.completed"}))
+	})
+
+	It("should not deliver events to subscribers of other events", func() {
+		delivered := 0
+		bus.Subscribe("run.started", func(context.Context, events.Event) { delivered++ })
+
+		bus.Publish(ctx, namedEvent("run.completed"))
+
+		Expect(delivered).To(BeZero())
+	})
+
+	It("should publish events nobody subscribed to", func() {
+		Expect(func() { bus.Publish(ctx, namedEvent("run.completed")) }).NotTo(Panic())
+	})
+
+	It("should pass the publisher's context to subscribers", func() {
+		type key struct{}
+		var received interface{}
+		bus.Subscribe("run.completed", func(ctx context.Context, _ events.Event) { received = ctx.Value(key{}) })
+
+		bus.Publish(context.WithValue(ctx, key{}, "request-1"), namedEvent("run.completed"))
+
+		Expect(received).To(Equal("request-1"))
+	})
+})
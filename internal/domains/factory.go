@@ -1,6 +1,12 @@
 package domains
 
 import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"time"
+
 	"gorm.io/gorm"
 
 	// Auth domain
@@ -16,11 +22,13 @@ import (
 
 	// Testing domain
 	testingApp "github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
+	testingDomain "github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
 	testingInfra "github.com/guidewire-oss/fern-platform/internal/domains/testing/infrastructure"
 	testingInterfaces "github.com/guidewire-oss/fern-platform/internal/domains/testing/interfaces"
 
 	// Projects domain
 	projectsApp "github.com/guidewire-oss/fern-platform/internal/domains/projects/application"
+	projectsDomain "github.com/guidewire-oss/fern-platform/internal/domains/projects/domain"
 	projectsInfra "github.com/guidewire-oss/fern-platform/internal/domains/projects/infrastructure"
 
 	// Tags domain
@@ -31,28 +39,44 @@ import (
 	"github.com/guidewire-oss/fern-platform/internal/domains/integrations"
 	integrationsInfra "github.com/guidewire-oss/fern-platform/internal/infrastructure/repositories"
 
+	// Domain events
+	"github.com/guidewire-oss/fern-platform/internal/domains/events"
+
 	"github.com/guidewire-oss/fern-platform/pkg/config"
 	"github.com/guidewire-oss/fern-platform/pkg/logging"
 )
 
 // DomainFactory creates and wires all domain components
 type DomainFactory struct {
-	db         *gorm.DB
-	logger     *logging.Logger
-	authConfig *config.AuthConfig
+	db              *gorm.DB
+	logger          *logging.Logger
+	authConfig      *config.AuthConfig
+	ingestionConfig *config.IngestionConfig
+	storageConfig   *config.StorageConfig
+
+	// Delivers domain events between domains
+	eventBus *events.Bus
 
 	// Auth domain
-	authService    *authApp.AuthenticationService
-	authzService   *authApp.AuthorizationService
-	authMiddleware *authInterfaces.AuthMiddlewareAdapter
+	authService           *authApp.AuthenticationService
+	authzService          *authApp.AuthorizationService
+	ingestionTokenService *authApp.IngestionTokenService
+	accessTokenService    *authApp.AccessTokenService
+	ciFederationService   *authApp.CIFederationService
+	authMiddleware        *authInterfaces.AuthMiddlewareAdapter
 
 	// Analytics domain
-	flakyDetectionService *analyticsApp.FlakyDetectionService
-	flakyDetectionAdapter *analyticsInterfaces.FlakyDetectionAdapter
+	flakyDetectionService  *analyticsApp.FlakyDetectionService
+	flakyDetectionAdapter  *analyticsInterfaces.FlakyDetectionAdapter
+	flakyAnalysisScheduler *analyticsApp.FlakyAnalysisScheduler
 
 	// Testing domain
-	testRunService *testingApp.TestRunService
-	testingAdapter *testingInterfaces.TestServiceAdapter
+	testRunService        *testingApp.TestRunService
+	idempotencyService    *testingApp.IdempotencyService
+	ingestionQueueService *testingApp.IngestionQueueService
+	attachmentService     *testingApp.AttachmentService
+	quarantineService     *testingApp.QuarantineService
+	testingAdapter        *testingInterfaces.TestServiceAdapter
 
 	// Projects domain
 	projectService *projectsApp.ProjectService
@@ -65,11 +89,14 @@ type DomainFactory struct {
 }
 
 // NewDomainFactory creates a new domain factory
-func NewDomainFactory(db *gorm.DB, logger *logging.Logger, authConfig *config.AuthConfig) *DomainFactory {
+func NewDomainFactory(db *gorm.DB, logger *logging.Logger, authConfig *config.AuthConfig, ingestionConfig *config.IngestionConfig, storageConfig *config.StorageConfig) *DomainFactory {
 	factory := &DomainFactory{
-		db:         db,
-		logger:     logger,
-		authConfig: authConfig,
+		db:              db,
+		logger:          logger,
+		authConfig:      authConfig,
+		ingestionConfig: ingestionConfig,
+		storageConfig:   storageConfig,
+		eventBus:        events.NewBus(),
 	}
 
 	// Initialize Auth domain (must be first as others may depend on it)
@@ -106,6 +133,48 @@ func (f *DomainFactory) initTestingDomain() {
 		suiteRunRepo,
 		specRunRepo,
 	)
+	f.idempotencyService = testingApp.NewIdempotencyService(
+		testingInfra.NewGormIdempotencyKeyRepository(f.db),
+		testingApp.DefaultIdempotencyKeyTTL,
+	)
+	f.ingestionQueueService = testingApp.NewIngestionQueueService(
+		testingInfra.NewGormIngestionJobRepository(f.db),
+		f.testRunService,
+		testingApp.IngestionQueueConfig{
+			Capacity:        f.ingestionConfig.QueueCapacity,
+			MaxAttempts:     f.ingestionConfig.MaxAttempts,
+			RetryBackoff:    f.ingestionConfig.RetryBackoff,
+			MaxRetryBackoff: f.ingestionConfig.MaxRetryBackoff,
+			LeaseDuration:   f.ingestionConfig.LeaseDuration,
+			Retention:       f.ingestionConfig.JobRetention,
+		},
+	)
+
+	blobStore, err := f.newBlobStore()
+	if err != nil {
+		f.logger.WithService("fern-platform").WithError(err).Fatal("Failed to initialize attachment storage")
+	}
+	f.attachmentService = testingApp.NewAttachmentService(
+		testingInfra.NewGormAttachmentRepository(f.db),
+		testRunRepo,
+		suiteRunRepo,
+		specRunRepo,
+		blobStore,
+		testingApp.AttachmentConfig{
+			MaxSize:      f.storageConfig.MaxAttachmentSize,
+			ProjectQuota: f.storageConfig.ProjectQuota,
+			SigningKey:   f.attachmentSigningKey(),
+			URLExpiry:    f.storageConfig.URLExpiry,
+		},
+		f.projectAttachmentQuota,
+	)
+	f.testRunService.SetAttachmentService(f.attachmentService)
+	f.testRunService.SetSpecOutputRepository(testingInfra.NewGormSpecOutputRepository(f.db))
+	testCaseRepo := testingInfra.NewGormTestCaseRepository(f.db)
+	f.testRunService.SetTestCaseRepository(testCaseRepo)
+	f.testRunService.SetMaxSpecOutputSize(f.ingestionConfig.MaxSpecOutputSize)
+	f.testRunService.SetHeartbeatTimeout(f.ingestionConfig.HeartbeatTimeout)
+	f.testRunService.SetEventPublisher(f.eventBus)
 
 	// Create adapter
 	f.testingAdapter = testingInterfaces.NewTestServiceAdapter(
@@ -114,6 +183,80 @@ func (f *DomainFactory) initTestingDomain() {
 	)
 }
 
+// newBlobStore creates the configured attachment blob store
+func (f *DomainFactory) newBlobStore() (testingDomain.BlobStore, error) {
+	switch f.storageConfig.Backend {
+	case "s3":
+		s3 := f.storageConfig.S3
+		return testingInfra.NewS3BlobStore(testingInfra.S3BlobStoreConfig{
+			Endpoint:        s3.Endpoint,
+			Region:          s3.Region,
+			Bucket:          s3.Bucket,
+			AccessKeyID:     s3.AccessKeyID,
+			SecretAccessKey: s3.SecretAccessKey,
+			Prefix:          s3.Prefix,
+			PathStyle:       s3.PathStyle,
+		}, &http.Client{Timeout: 5 * time.Minute})
+	case "local", "":
+		return testingInfra.NewLocalBlobStore(f.storageConfig.Local.Path)
+	default:
+		return nil, fmt.Errorf("unknown attachment storage backend %q", f.storageConfig.Backend)
+	}
+}
+
+// attachmentSigningKey returns the key for signed attachment URLs. Without a configured key
+// a random one is used, so URLs only work against this process and until it restarts.
+func (f *DomainFactory) attachmentSigningKey() []byte {
+	if f.storageConfig.SigningKey != "" {
+		return []byte(f.storageConfig.SigningKey)
+	}
+
+	f.logger.WithService("fern-platform").Warn("No storage.signingKey configured; attachment download URLs will not survive a restart")
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		f.logger.WithService("fern-platform").WithError(err).Fatal("Failed to generate attachment signing key")
+	}
+	return key
+}
+
+// projectAttachmentQuota reads a project's "attachmentQuotaBytes" setting
+func (f *DomainFactory) projectAttachmentQuota(ctx context.Context, projectID string) (int64, bool) {
+	project, err := f.projectService.GetProject(ctx, projectsDomain.ProjectID(projectID))
+	if err != nil {
+		return 0, false
+	}
+	value, ok := project.GetSetting("attachmentQuotaBytes")
+	if !ok {
+		return 0, false
+	}
+
+	switch quota := value.(type) {
+	case float64:
+		return int64(quota), true
+	case int:
+		return int64(quota), true
+	case int64:
+		return quota, true
+	default:
+		return 0, false
+	}
+}
+
+// projectFlakyDetectionSettings reads a project's "flakyDetection" setting
+func (f *DomainFactory) projectFlakyDetectionSettings(ctx context.Context, projectID string) (map[string]interface{}, bool) {
+	project, err := f.projectService.GetProject(ctx, projectsDomain.ProjectID(projectID))
+	if err != nil {
+		return nil, false
+	}
+	value, ok := project.GetSetting(analyticsDomain.FlakyDetectionSettingsKey)
+	if !ok {
+		return nil, false
+	}
+
+	settings, ok := value.(map[string]interface{})
+	return settings, ok
+}
+
 // initProjectsDomain initializes the projects domain components
 func (f *DomainFactory) initProjectsDomain() {
 	// Create repositories
@@ -130,6 +273,36 @@ func (f *DomainFactory) GetTestingService() *testingApp.TestRunService {
 	return f.testRunService
 }
 
+// GetIdempotencyService returns the ingestion idempotency service
+func (f *DomainFactory) GetIdempotencyService() *testingApp.IdempotencyService {
+	return f.idempotencyService
+}
+
+// GetIngestionQueueService returns the asynchronous ingestion queue service
+func (f *DomainFactory) GetIngestionQueueService() *testingApp.IngestionQueueService {
+	return f.ingestionQueueService
+}
+
+// GetAttachmentService returns the test attachment service
+func (f *DomainFactory) GetAttachmentService() *testingApp.AttachmentService {
+	return f.attachmentService
+}
+
+// GetQuarantineService returns the test quarantine service
+func (f *DomainFactory) GetQuarantineService() *testingApp.QuarantineService {
+	return f.quarantineService
+}
+
+// NewIngestionWorkerPool creates a worker pool for the asynchronous ingestion queue
+func (f *DomainFactory) NewIngestionWorkerPool() *testingApp.IngestionWorkerPool {
+	return testingApp.NewIngestionWorkerPool(
+		f.ingestionQueueService,
+		f.ingestionConfig.Workers,
+		f.ingestionConfig.PollInterval,
+		f.logger,
+	)
+}
+
 // GetTestingAdapter returns the testing adapter for HTTP/GraphQL
 func (f *DomainFactory) GetTestingAdapter() *testingInterfaces.TestServiceAdapter {
 	return f.testingAdapter
@@ -160,10 +333,17 @@ func (f *DomainFactory) initAuthDomain() {
 	// Create repositories
 	userRepo := authInfra.NewGormUserRepository(f.db)
 	sessionRepo := authInfra.NewGormSessionRepository(f.db)
+	ingestionTokenRepo := authInfra.NewGormIngestionTokenRepository(f.db)
+	accessTokenRepo := authInfra.NewGormAccessTokenRepository(f.db)
+	ciTrustPolicyRepo := authInfra.NewGormCITrustPolicyRepository(f.db)
+	ciTokenVerifier := authInfra.NewOIDCCITokenVerifier(f.authConfig.CIOIDC.Issuers, &http.Client{Timeout: 10 * time.Second})
 
 	// Create application services
 	f.authService = authApp.NewAuthenticationService(userRepo, sessionRepo)
 	f.authzService = authApp.NewAuthorizationService(userRepo)
+	f.ingestionTokenService = authApp.NewIngestionTokenService(ingestionTokenRepo)
+	f.accessTokenService = authApp.NewAccessTokenService(accessTokenRepo, userRepo)
+	f.ciFederationService = authApp.NewCIFederationService(ciTrustPolicyRepo, ciTokenVerifier)
 
 	// Create OAuth adapter
 	oauthAdapter := authInterfaces.NewOAuthAdapter(f.authConfig, f.logger)
@@ -172,6 +352,9 @@ func (f *DomainFactory) initAuthDomain() {
 	f.authMiddleware = authInterfaces.NewAuthMiddlewareAdapter(
 		f.authService,
 		f.authzService,
+		f.ingestionTokenService,
+		f.accessTokenService,
+		f.ciFederationService,
 		oauthAdapter,
 		f.authConfig,
 		f.logger,
@@ -188,6 +371,21 @@ func (f *DomainFactory) GetAuthorizationService() *authApp.AuthorizationService
 	return f.authzService
 }
 
+// GetIngestionTokenService returns the ingestion token service
+func (f *DomainFactory) GetIngestionTokenService() *authApp.IngestionTokenService {
+	return f.ingestionTokenService
+}
+
+// GetAccessTokenService returns the access token service
+func (f *DomainFactory) GetAccessTokenService() *authApp.AccessTokenService {
+	return f.accessTokenService
+}
+
+// GetCIFederationService returns the CI OIDC federation service
+func (f *DomainFactory) GetCIFederationService() *authApp.CIFederationService {
+	return f.ciFederationService
+}
+
 // GetAuthMiddleware returns the auth middleware adapter
 func (f *DomainFactory) GetAuthMiddleware() *authInterfaces.AuthMiddlewareAdapter {
 	return f.authMiddleware
@@ -201,9 +399,14 @@ func (f *DomainFactory) initAnalyticsDomain() {
 	// Create service with default config
 	config := analyticsDomain.DefaultFlakyTestDetectionConfig()
 	f.flakyDetectionService = analyticsApp.NewFlakyDetectionService(flakyRepo, config)
+	f.flakyDetectionService.SetProjectSettings(f.projectFlakyDetectionSettings)
 
 	// Create adapter
 	f.flakyDetectionAdapter = analyticsInterfaces.NewFlakyDetectionAdapter(f.flakyDetectionService, f.logger)
+
+	// Analyze the tests of every completed test run in the background
+	f.flakyAnalysisScheduler = analyticsApp.NewFlakyAnalysisScheduler(f.flakyDetectionService, config.AnalysisDebounce, config.MaxAnalysisDelay, f.logger)
+	analyticsInterfaces.SubscribeToTestRuns(f.eventBus, f.flakyAnalysisScheduler)
 }
 
 // GetFlakyDetectionService returns the flaky detection service
@@ -211,6 +414,11 @@ func (f *DomainFactory) GetFlakyDetectionService() *analyticsApp.FlakyDetectionS
 	return f.flakyDetectionService
 }
 
+// GetFlakyAnalysisScheduler returns the scheduler analyzing completed test runs
+func (f *DomainFactory) GetFlakyAnalysisScheduler() *analyticsApp.FlakyAnalysisScheduler {
+	return f.flakyAnalysisScheduler
+}
+
 // GetFlakyDetectionAdapter returns the flaky detection adapter
 func (f *DomainFactory) GetFlakyDetectionAdapter() *analyticsInterfaces.FlakyDetectionAdapter {
 	return f.flakyDetectionAdapter
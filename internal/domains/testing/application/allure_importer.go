@@ -0,0 +1,702 @@
+package application
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// allureResult is a *-result.json file written by an Allure 2 adapter
+type allureResult struct {
+	UUID          string              `json:"uuid"`
+	HistoryID     string              `json:"historyId"`
+	FullName      string              `json:"fullName"`
+	Name          string              `json:"name"`
+	Description   string              `json:"description"`
+	Status        string              `json:"status"`
+	StatusDetails allureStatusDetails `json:"statusDetails"`
+	Start         int64               `json:"start"`
+	Stop          int64               `json:"stop"`
+	Labels        []allureLabel       `json:"labels"`
+	Links         []allureLink        `json:"links"`
+	Parameters    []allureParameter   `json:"parameters"`
+	Attachments   []allureAttachment  `json:"attachments"`
+	Steps         []allureStep        `json:"steps"`
+}
+
+// allureStatusDetails explains a result's status; flaky and known are set by Allure's
+// categories, or by the test itself through the adapter's API
+type allureStatusDetails struct {
+	Known   bool   `json:"known"`
+	Muted   bool   `json:"muted"`
+	Flaky   bool   `json:"flaky"`
+	Message string `json:"message"`
+	Trace   string `json:"trace"`
+}
+
+// allureStep is a step of a result, or a before/after fixture of a container
+type allureStep struct {
+	Name          string              `json:"name"`
+	Status        string              `json:"status"`
+	StatusDetails allureStatusDetails `json:"statusDetails"`
+	Start         int64               `json:"start"`
+	Stop          int64               `json:"stop"`
+	Steps         []allureStep        `json:"steps"`
+	Attachments   []allureAttachment  `json:"attachments"`
+}
+
+// allureContainer is a *-container.json file that groups results with the fixtures run around them
+type allureContainer struct {
+	UUID     string       `json:"uuid"`
+	Name     string       `json:"name"`
+	Children []string     `json:"children"`
+	Befores  []allureStep `json:"befores"`
+	Afters   []allureStep `json:"afters"`
+}
+
+type allureLabel struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type allureLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	Type string `json:"type"`
+}
+
+type allureParameter struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Excluded bool   `json:"excluded"`
+	Mode     string `json:"mode"`
+}
+
+// allureAttachment refers to a file in the results directory by its name
+type allureAttachment struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	Type   string `json:"type"`
+}
+
+// allureSuiteLabels are the labels Allure builds its suite tree from, outermost first
+var allureSuiteLabels = []string{"parentSuite", "suite", "subSuite"}
+
+// jiraIssueKeyPattern matches Jira issue keys such as PAY-123
+var jiraIssueKeyPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9_]+-[1-9][0-9]*\b`)
+
+const (
+	// maxAllureFileSize caps each result, container and environment file of an archive
+	maxAllureFileSize = 16 << 20
+	// maxAllureArchiveSize caps the uncompressed size of everything read from an archive, so a
+	// small upload cannot expand into more than the server can hold
+	maxAllureArchiveSize = 1 << 30
+)
+
+// allureArchive reads the files of a zipped allure-results directory within a size budget
+type allureArchive struct {
+	files     map[string]*zip.File
+	remaining int64
+}
+
+// open opens a file of at most limit bytes and charges it to the archive's budget. The size in
+// the file's header is checked before anything is decompressed, and reads stop at that size
+// in case the header lies.
+func (a *allureArchive) open(file *zip.File, limit int64) (io.ReadCloser, error) {
+	size := file.UncompressedSize64
+	if size > uint64(limit) {
+		return nil, fmt.Errorf("%s is larger than %d bytes", file.Name, limit)
+	}
+	if size > uint64(a.remaining) {
+		return nil, fmt.Errorf("allure results archive expands to more than %d bytes", maxAllureArchiveSize)
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", file.Name, err)
+	}
+	a.remaining -= int64(size)
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(rc, int64(size)), rc}, nil
+}
+
+// ParseAllureResults converts a zipped allure-results directory into a test run hierarchy.
+// Suites are rebuilt from the parentSuite, suite and subSuite labels, and the results of one
+// test that was retried (sharing a historyId) become a single spec carrying its retry count.
+// Attachments referenced by results, steps and fixtures are read from the archive, leaving out
+// those larger than maxAttachmentSize (0 for no limit) before they are decompressed.
+// The returned test run has no project or run ID assigned.
+func ParseAllureResults(r io.Reader, maxAttachmentSize int64) (*domain.TestRun, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read allure results: %w", err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid allure results archive: %w", err)
+	}
+
+	// Allure refers to attachments by file name, wherever the directory sits in the archive
+	files := make(map[string]*zip.File)
+	reader := &allureArchive{files: files, remaining: maxAllureArchiveSize}
+	var results []allureResult
+	var containers []allureContainer
+	var environment map[string]string
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		name := path.Base(file.Name)
+		files[name] = file
+
+		switch {
+		case strings.HasSuffix(name, "-result.json"):
+			var result allureResult
+			if err := readAllureJSON(reader, file, &result); err != nil {
+				return nil, err
+			}
+			results = append(results, result)
+		case strings.HasSuffix(name, "-container.json"):
+			var container allureContainer
+			if err := readAllureJSON(reader, file, &container); err != nil {
+				return nil, err
+			}
+			containers = append(containers, container)
+		case name == "environment.properties":
+			if environment, err = readAllureEnvironment(reader, file); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no allure results found in archive")
+	}
+
+	fixtures := make(map[string][]*allureContainer)
+	for i := range containers {
+		for _, child := range containers[i].Children {
+			fixtures[child] = append(fixtures[child], &containers[i])
+		}
+	}
+
+	testRun := &domain.TestRun{Source: ReportFormatAllure}
+	specMetadata := make(map[string]interface{})
+	jiraIssues := make(map[string]interface{})
+	parameterized := make(map[string]interface{})
+	suites := make(map[string]int)
+	var droppedAttachments []map[string]interface{}
+
+	for _, attempts := range groupAllureAttempts(results) {
+		result := attempts[len(attempts)-1]
+		suiteName := allureSuiteName(result)
+		i, ok := suites[suiteName]
+		if !ok {
+			i = len(testRun.SuiteRuns)
+			suites[suiteName] = i
+			testRun.SuiteRuns = append(testRun.SuiteRuns, domain.SuiteRun{
+				Name:        suiteName,
+				PackageName: allureLabelValue(result.Labels, "package"),
+				Status:      "passed",
+			})
+		}
+		suite := &testRun.SuiteRuns[i]
+
+		spec, meta, dropped := newAllureSpecRun(attempts, suiteName, fixtures[result.UUID], reader, maxAttachmentSize)
+		appendSpecRun(suite, spec)
+		droppedAttachments = append(droppedAttachments, dropped...)
+
+		key := suiteName + "/" + spec.Name
+		specMetadata[key] = meta
+		if issues, ok := meta["jira_issues"].([]string); ok {
+			for _, issue := range issues {
+				specs, _ := jiraIssues[issue].([]string)
+				jiraIssues[issue] = append(specs, key)
+			}
+		}
+		if _, ok := meta["parameters"]; ok && result.FullName != "" {
+			specs, _ := parameterized[result.FullName].([]string)
+			parameterized[result.FullName] = append(specs, key)
+		}
+	}
+
+	// The run started with the first attempt of any test, including attempts that were retried
+	for _, result := range results {
+		if start := time.UnixMilli(result.Start).UTC(); result.Start > 0 && (testRun.StartTime.IsZero() || start.Before(testRun.StartTime)) {
+			testRun.StartTime = start
+		}
+	}
+	var endTime time.Time
+	for i := range testRun.SuiteRuns {
+		suite := &testRun.SuiteRuns[i]
+		summariseSuiteTimes(suite)
+		if suite.EndTime != nil && suite.EndTime.After(endTime) {
+			endTime = *suite.EndTime
+		}
+	}
+	if !testRun.StartTime.IsZero() && !endTime.IsZero() {
+		testRun.EndTime = &endTime
+		testRun.Duration = endTime.Sub(testRun.StartTime)
+	}
+	summariseTestRun(testRun)
+
+	allure := map[string]interface{}{
+		"specs": specMetadata,
+	}
+	if len(jiraIssues) > 0 {
+		allure["jira_issues"] = jiraIssues
+	}
+	if len(parameterized) > 0 {
+		allure["parameterized_tests"] = parameterized
+	}
+	if len(environment) > 0 {
+		allure["environment"] = environment
+	}
+	testRun.Metadata = map[string]interface{}{
+		"source_format": ReportFormatAllure,
+		"allure":        allure,
+	}
+	if len(droppedAttachments) > 0 {
+		testRun.Metadata["dropped_attachments"] = droppedAttachments
+	}
+
+	return testRun, nil
+}
+
+// groupAllureAttempts groups the results of each test, oldest attempt first, ordering tests
+// by when they first ran. Results without a historyId are never treated as retries.
+func groupAllureAttempts(results []allureResult) [][]allureResult {
+	groups := make(map[string][]allureResult)
+	var keys []string
+	for _, result := range results {
+		key := result.HistoryID
+		if key == "" {
+			key = "uuid:" + result.UUID
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], result)
+	}
+
+	attempts := make([][]allureResult, 0, len(keys))
+	for _, key := range keys {
+		group := groups[key]
+		sort.SliceStable(group, func(i, j int) bool { return group[i].Start < group[j].Start })
+		attempts = append(attempts, group)
+	}
+	sort.SliceStable(attempts, func(i, j int) bool {
+		if attempts[i][0].Start != attempts[j][0].Start {
+			return attempts[i][0].Start < attempts[j][0].Start
+		}
+		return attempts[i][0].FullName < attempts[j][0].FullName
+	})
+	return attempts
+}
+
+// newAllureSpecRun converts the latest attempt of a test into a spec run, recording every
+// attempt when it was retried. It returns the metadata kept for the spec alongside it, and the
+// attachments left out for their size in the shape of the run's "dropped_attachments".
+func newAllureSpecRun(attempts []allureResult, suiteName string, containers []*allureContainer, archive *allureArchive, maxAttachmentSize int64) (*domain.SpecRun, map[string]interface{}, []map[string]interface{}) {
+	result := attempts[len(attempts)-1]
+	spec := &domain.SpecRun{
+		Name:       allureSpecName(result),
+		ClassName:  allureLabelValue(result.Labels, "testClass"),
+		Status:     allureStatus(result.Status),
+		RetryCount: len(attempts) - 1,
+		IsFlaky:    result.StatusDetails.Flaky,
+	}
+	if spec.ClassName == "" {
+		spec.ClassName = suiteName
+	}
+	if params := allureSpecParameters(result); len(params) > 0 {
+		spec.ParentName = allureTestName(result)
+		spec.Parameters = params
+	}
+	if result.Start > 0 {
+		spec.StartTime = time.UnixMilli(result.Start).UTC()
+		if result.Stop >= result.Start {
+			endTime := time.UnixMilli(result.Stop).UTC()
+			spec.EndTime = &endTime
+			spec.Duration = endTime.Sub(spec.StartTime)
+		}
+	}
+	if spec.Status == "failed" {
+		spec.ErrorMessage = strings.TrimSpace(result.StatusDetails.Message)
+		if spec.ErrorMessage == "" {
+			spec.ErrorMessage = fmt.Sprintf("test %s", result.Status)
+		}
+		spec.FailureMessage = spec.ErrorMessage
+		spec.StackTrace = result.StatusDetails.Trace
+	}
+
+	meta := make(map[string]interface{})
+	if result.FullName != "" {
+		meta["test_identity"] = result.FullName
+	}
+	if result.HistoryID != "" {
+		meta["history_id"] = result.HistoryID
+	}
+	if result.Description != "" {
+		meta["description"] = result.Description
+	}
+	if s := strings.ToLower(result.Status); s == "broken" || s == "unknown" {
+		meta["outcome"] = s
+	}
+	if result.StatusDetails.Known {
+		meta["known_issue"] = true
+	}
+	if result.StatusDetails.Muted {
+		meta["muted"] = true
+	}
+
+	// A test that passed after failing attempts is flaky even when Allure didn't mark it
+	if len(attempts) > 1 {
+		for _, attempt := range attempts {
+			specAttempt := domain.SpecAttempt{Status: allureStatus(attempt.Status)}
+			if specAttempt.Status == "failed" {
+				specAttempt.ErrorMessage = strings.TrimSpace(attempt.StatusDetails.Message)
+				specAttempt.StackTrace = attempt.StatusDetails.Trace
+			}
+			if attempt.Start > 0 {
+				specAttempt.StartTime = time.UnixMilli(attempt.Start).UTC()
+				if attempt.Stop >= attempt.Start {
+					specAttempt.Duration = time.Duration(attempt.Stop-attempt.Start) * time.Millisecond
+				}
+			}
+			spec.Attempts = append(spec.Attempts, specAttempt)
+		}
+		spec.ClassifyAttempts()
+	}
+
+	labels := make(map[string]string)
+	var tags []string
+	for _, label := range result.Labels {
+		switch label.Name {
+		case "parentSuite", "suite", "subSuite":
+		case "tag":
+			tags = append(tags, label.Value)
+		default:
+			labels[label.Name] = label.Value
+		}
+	}
+	if len(labels) > 0 {
+		meta["labels"] = labels
+	}
+	if len(tags) > 0 {
+		meta["tags"] = tags
+	}
+
+	if params := allureParameters(result.Parameters); len(params) > 0 {
+		meta["parameters"] = params
+	}
+
+	if len(result.Links) > 0 {
+		links := make([]map[string]interface{}, 0, len(result.Links))
+		var issues []string
+		for _, link := range result.Links {
+			links = append(links, map[string]interface{}{"name": link.Name, "url": link.URL, "type": link.Type})
+			if issue := jiraIssueKey(link); issue != "" {
+				issues = append(issues, issue)
+			}
+		}
+		meta["links"] = links
+		if len(issues) > 0 {
+			meta["jira_issues"] = issues
+		}
+	}
+
+	// Steps are flattened depth-first; fixtures are only recorded when they did not pass
+	attachments := append([]allureAttachment(nil), result.Attachments...)
+	var addSteps func(steps []allureStep, keyword string)
+	addSteps = func(steps []allureStep, keyword string) {
+		for _, s := range steps {
+			status := allureStatus(s.Status)
+			attachments = append(attachments, s.Attachments...)
+			if keyword == "Step" || status != "passed" {
+				spec.Steps = append(spec.Steps, domain.SpecStep{
+					Keyword:      keyword,
+					Name:         s.Name,
+					Status:       status,
+					Duration:     allureDuration(s.Start, s.Stop),
+					ErrorMessage: strings.TrimSpace(s.StatusDetails.Message),
+				})
+			}
+			addSteps(s.Steps, keyword)
+		}
+	}
+	for _, container := range containers {
+		addSteps(container.Befores, "Before")
+	}
+	addSteps(result.Steps, "Step")
+	for _, container := range containers {
+		addSteps(container.Afters, "After")
+	}
+
+	var names, missing []string
+	var dropped []map[string]interface{}
+	for _, a := range attachments {
+		attachment, err := readAllureAttachment(archive, a, maxAttachmentSize)
+		if errors.Is(err, errAllureAttachmentNotFound) {
+			missing = append(missing, a.Source)
+			continue
+		}
+		if err != nil {
+			name := a.Name
+			if name == "" {
+				name = a.Source
+			}
+			dropped = append(dropped, map[string]interface{}{
+				"suite":  suiteName,
+				"spec":   spec.Name,
+				"name":   name,
+				"size":   int64(archive.files[path.Base(a.Source)].UncompressedSize64),
+				"reason": err.Error(),
+			})
+			continue
+		}
+		spec.Attachments = append(spec.Attachments, *attachment)
+		names = append(names, attachment.Name)
+	}
+	if len(names) > 0 {
+		meta["attachments"] = names
+	}
+	if len(missing) > 0 {
+		meta["missing_attachments"] = missing
+	}
+
+	return spec, meta, dropped
+}
+
+// allureStatus maps Allure statuses onto spec statuses: broken tests failed outside of an
+// assertion, and unknown tests never reported a result
+func allureStatus(status string) string {
+	switch strings.ToLower(status) {
+	case "passed":
+		return "passed"
+	case "skipped", "unknown", "":
+		return "skipped"
+	default:
+		return "failed"
+	}
+}
+
+// allureSuiteName joins the suite labels of a result, falling back to its test class or package
+func allureSuiteName(result allureResult) string {
+	var parts []string
+	for _, name := range allureSuiteLabels {
+		if value := allureLabelValue(result.Labels, name); value != "" {
+			parts = append(parts, value)
+		}
+	}
+	if len(parts) > 0 {
+		return strings.Join(parts, " > ")
+	}
+	for _, name := range []string{"testClass", "package"} {
+		if value := allureLabelValue(result.Labels, name); value != "" {
+			return value
+		}
+	}
+	return "allure"
+}
+
+// allureSpecName names a spec after its test, followed by its parameters so that
+// each parameterised row stays distinct
+func allureSpecName(result allureResult) string {
+	name := allureTestName(result)
+
+	var params []string
+	for _, p := range result.Parameters {
+		if p.Excluded || p.Mode == "hidden" {
+			continue
+		}
+		value := p.Value
+		if p.Mode == "masked" {
+			value = "******"
+		}
+		params = append(params, p.Name+"="+value)
+	}
+	if len(params) == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s [%s]", name, strings.Join(params, ", "))
+}
+
+// allureTestName returns the name of a result's test, without its parameters
+func allureTestName(result allureResult) string {
+	if result.Name != "" {
+		return result.Name
+	}
+	return result.FullName
+}
+
+// allureSpecParameters returns the parameters that tell a result's parameter set apart, as
+// they appear in its spec name
+func allureSpecParameters(result allureResult) map[string]string {
+	params := make(map[string]string)
+	for _, p := range result.Parameters {
+		if p.Excluded || p.Mode == "hidden" {
+			continue
+		}
+		params[p.Name] = p.Value
+		if p.Mode == "masked" {
+			params[p.Name] = "******"
+		}
+	}
+	return params
+}
+
+// allureParameters returns the parameters of a result by name, leaving out hidden and masked values
+func allureParameters(parameters []allureParameter) map[string]string {
+	params := make(map[string]string)
+	for _, p := range parameters {
+		if p.Mode == "hidden" || p.Mode == "masked" {
+			continue
+		}
+		params[p.Name] = p.Value
+	}
+	return params
+}
+
+func allureLabelValue(labels []allureLabel, name string) string {
+	for _, label := range labels {
+		if label.Name == name {
+			return label.Value
+		}
+	}
+	return ""
+}
+
+// allureDuration returns the time between two millisecond timestamps, or zero if either is missing
+func allureDuration(start, stop int64) time.Duration {
+	if start <= 0 || stop < start {
+		return 0
+	}
+	return time.Duration(stop-start) * time.Millisecond
+}
+
+// jiraIssueKey returns the Jira issue a link points to, or "" for links to anything else.
+// Adapters write issue links with type "issue" (or "jira"), named after the issue key.
+func jiraIssueKey(link allureLink) string {
+	switch strings.ToLower(link.Type) {
+	case "issue", "jira":
+	default:
+		if !strings.Contains(link.URL, "/browse/") {
+			return ""
+		}
+	}
+
+	if key := jiraIssueKeyPattern.FindString(link.Name); key != "" {
+		return key
+	}
+	return jiraIssueKeyPattern.FindString(link.URL)
+}
+
+func readAllureJSON(archive *allureArchive, file *zip.File, v interface{}) error {
+	rc, err := archive.open(file, maxAllureFileSize)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if err := json.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("invalid allure file %s: %w", file.Name, err)
+	}
+	return nil
+}
+
+// readAllureEnvironment reads the key=value pairs of environment.properties
+func readAllureEnvironment(archive *allureArchive, file *zip.File) (map[string]string, error) {
+	rc, err := archive.open(file, maxAllureFileSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	environment := make(map[string]string)
+	scanner := bufio.NewScanner(rc)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			environment[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file.Name, err)
+	}
+	return environment, nil
+}
+
+// errAllureAttachmentNotFound is returned for attachments missing from the archive
+var errAllureAttachmentNotFound = errors.New("attachment not found")
+
+// readAllureAttachment loads an attachment from the archive, sniffing its content type
+// when the adapter didn't record one. Attachments larger than maxAttachmentSize are
+// rejected with domain.ErrAttachmentTooLarge before they are decompressed.
+func readAllureAttachment(archive *allureArchive, a allureAttachment, maxAttachmentSize int64) (*domain.Attachment, error) {
+	file, ok := archive.files[path.Base(a.Source)]
+	if !ok || a.Source == "" {
+		return nil, fmt.Errorf("%w: %q", errAllureAttachmentNotFound, a.Source)
+	}
+	if maxAttachmentSize > 0 && file.UncompressedSize64 > uint64(maxAttachmentSize) {
+		return nil, domain.ErrAttachmentTooLarge
+	}
+	rc, err := archive.open(file, archive.remaining)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := &domain.Attachment{
+		Name:        a.Name,
+		ContentType: a.Type,
+		Size:        int64(len(content)),
+		Content:     content,
+	}
+	if attachment.Name == "" {
+		attachment.Name = a.Source
+	}
+	if attachment.ContentType == "" {
+		attachment.ContentType = detectAttachmentContentType("", a.Source, content)
+	}
+	return attachment, nil
+}
+
+// ImportAllureResults parses a zipped allure-results directory and records it as a new test run
+func (s *TestRunService) ImportAllureResults(ctx context.Context, r io.Reader, opts ImportOptions) (*domain.TestRun, error) {
+	var maxAttachmentSize int64
+	if s.attachments != nil {
+		maxAttachmentSize = s.attachments.MaxSize()
+	}
+	testRun, err := ParseAllureResults(r, maxAttachmentSize)
+	if err != nil {
+		return nil, &InvalidReportError{Err: err}
+	}
+
+	if err := s.importTestRun(ctx, testRun, opts); err != nil {
+		return nil, err
+	}
+
+	return testRun, nil
+}
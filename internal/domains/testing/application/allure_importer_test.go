@@ -0,0 +1,258 @@
+package application_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// allureResults are the files of an allure-results directory written by allure-pytest
+var allureResults = map[string]string{
+	"allure-results/a1-result.json": `{
+  "uuid": "a1", "historyId": "h-login", "fullName": "tests.test_auth#test_login", "name": "test_login",
+  "status": "failed", "statusDetails": {"message": "AssertionError: 500 != 200", "trace": "tests/test_auth.py:12"},
+  "start": 1709287200000, "stop": 1709287200500,
+  "labels": [{"name": "parentSuite", "value": "tests"}, {"name": "suite", "value": "test_auth"}, {"name": "package", "value": "tests.test_auth"}]
+}`,
+	"allure-results/a2-result.json": `{
+  "uuid": "a2", "historyId": "h-login", "fullName": "tests.test_auth#test_login", "name": "test_login",
+  "status": "passed", "start": 1709287201000, "stop": 1709287201400,
+  "labels": [
+    {"name": "parentSuite", "value": "tests"}, {"name": "suite", "value": "test_auth"}, {"name": "package", "value": "tests.test_auth"},
+    {"name": "tag", "value": "smoke"}, {"name": "severity", "value": "critical"}
+  ],
+  "links": [{"name": "AUTH-42", "url": "https://acme.atlassian.net/browse/AUTH-42", "type": "issue"}, {"name": "docs", "url": "https://docs.acme.dev", "type": "link"}],
+  "steps": [
+    {"name": "open login page", "status": "passed", "start": 1709287201000, "stop": 1709287201100,
+     "steps": [{"name": "wait for form", "status": "passed", "start": 1709287201000, "stop": 1709287201050}]},
+    {"name": "submit credentials", "status": "passed", "start": 1709287201100, "stop": 1709287201400,
+     "attachments": [{"name": "after submit", "source": "s1-attachment.png", "type": "image/png"}]}
+  ],
+  "attachments": [{"name": "log", "source": "s2-attachment.txt"}]
+}`,
+	"allure-results/b1-result.json": `{
+  "uuid": "b1", "historyId": "h-logout", "fullName": "tests.test_auth#test_logout", "name": "test_logout",
+  "status": "broken", "statusDetails": {"known": true, "message": "ConnectionError: reset by peer"},
+  "start": 1709287202000, "stop": 1709287202300,
+  "labels": [{"name": "parentSuite", "value": "tests"}, {"name": "suite", "value": "test_auth"}, {"name": "subSuite", "value": "Session"}],
+  "links": [{"name": "https://acme.atlassian.net/browse/AUTH-7", "url": "https://acme.atlassian.net/browse/AUTH-7", "type": "issue"}]
+}`,
+	"allure-results/c1-result.json": `{
+  "uuid": "c1", "historyId": "h-pay-1", "fullName": "tests.test_pay#test_charge", "name": "test_charge",
+  "status": "passed", "statusDetails": {"flaky": true}, "start": 1709287203000, "stop": 1709287203100,
+  "labels": [{"name": "suite", "value": "test_pay"}],
+  "parameters": [{"name": "amount", "value": "10"}, {"name": "token", "value": "secret", "mode": "masked"}]
+}`,
+	"allure-results/c2-result.json": `{
+  "uuid": "c2", "historyId": "h-pay-2", "fullName": "tests.test_pay#test_charge", "name": "test_charge",
+  "status": "skipped", "statusDetails": {"message": "gateway offline"}, "start": 1709287203100, "stop": 1709287203100,
+  "labels": [{"name": "suite", "value": "test_pay"}],
+  "parameters": [{"name": "amount", "value": "0"}, {"name": "token", "value": "secret", "mode": "masked"}]
+}`,
+	"allure-results/f1-container.json": `{
+  "uuid": "f1", "name": "db", "children": ["c2"],
+  "befores": [{"name": "db", "status": "broken", "statusDetails": {"message": "database unavailable"}, "start": 1709287203000, "stop": 1709287203100}]
+}`,
+	"allure-results/s1-attachment.png":      "\x89PNG\r\n\x1a\nfake image",
+	"allure-results/s2-attachment.txt":      "login succeeded",
+	"allure-results/environment.properties": "# written by conftest\nPython=3.12\nBrowser = chromium\n",
+}
+
+// zipAllureResults builds a zipped allure-results directory
+func zipAllureResults(files map[string]string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = f.Write([]byte(content))
+		Expect(err).NotTo(HaveOccurred())
+	}
+	Expect(w.Close()).To(Succeed())
+	return buf.Bytes()
+}
+
+var _ = Describe("Allure import", Label("unit", "application", "testing"), func() {
+	Describe("ParseAllureResults", func() {
+		It("should rebuild suites from the suite labels", func() {
+			testRun, err := application.ParseAllureResults(bytes.NewReader(zipAllureResults(allureResults)), 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(testRun.Source).To(Equal("allure"))
+			Expect(testRun.Status).To(Equal("failed"))
+			Expect(testRun.TotalTests).To(Equal(4))
+			Expect(testRun.PassedTests).To(Equal(2))
+			Expect(testRun.FailedTests).To(Equal(1))
+			Expect(testRun.SkippedTests).To(Equal(1))
+			Expect(testRun.StartTime).To(Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)))
+			Expect(testRun.Duration).To(Equal(3100 * time.Millisecond))
+
+			Expect(testRun.SuiteRuns).To(HaveLen(3))
+			auth := testRun.SuiteRuns[0]
+			Expect(auth.Name).To(Equal("tests > test_auth"))
+			Expect(auth.PackageName).To(Equal("tests.test_auth"))
+			Expect(testRun.SuiteRuns[1].Name).To(Equal("tests > test_auth > Session"))
+			Expect(testRun.SuiteRuns[2].Name).To(Equal("test_pay"))
+		})
+
+		It("should merge retried results into one spec marked flaky", func() {
+			testRun, err := application.ParseAllureResults(bytes.NewReader(zipAllureResults(allureResults)), 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			login := testRun.SuiteRuns[0].SpecRuns[0]
+			Expect(testRun.SuiteRuns[0].SpecRuns).To(HaveLen(1))
+			Expect(login.Name).To(Equal("test_login"))
+			Expect(login.Status).To(Equal("passed"))
+			Expect(login.RetryCount).To(Equal(1))
+			Expect(login.IsFlaky).To(BeTrue())
+			Expect(login.Duration).To(Equal(400 * time.Millisecond))
+
+			Expect(login.Attempts).To(HaveLen(2))
+			Expect(login.Attempts[0].Attempt).To(Equal(1))
+			Expect(login.Attempts[0].Status).To(Equal("failed"))
+			Expect(login.Attempts[0].ErrorMessage).To(Equal("AssertionError: 500 != 200"))
+			Expect(login.Attempts[0].Duration).To(Equal(500 * time.Millisecond))
+			Expect(login.Attempts[1].Status).To(Equal("passed"))
+			Expect(login.FlakyInRun()).To(BeTrue())
+
+			allure := testRun.Metadata["allure"].(map[string]interface{})
+			specs := allure["specs"].(map[string]interface{})
+			Expect(specs["tests > test_auth/test_login"]).NotTo(HaveKey("retries"))
+		})
+
+		It("should map flaky and known status details", func() {
+			testRun, err := application.ParseAllureResults(bytes.NewReader(zipAllureResults(allureResults)), 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			logout := testRun.SuiteRuns[1].SpecRuns[0]
+			Expect(logout.Status).To(Equal("failed"))
+			Expect(logout.ErrorMessage).To(Equal("ConnectionError: reset by peer"))
+
+			charge := testRun.SuiteRuns[2].SpecRuns[0]
+			Expect(charge.IsFlaky).To(BeTrue())
+
+			allure := testRun.Metadata["allure"].(map[string]interface{})
+			specs := allure["specs"].(map[string]interface{})
+			Expect(specs["tests > test_auth > Session/test_logout"]).To(HaveKeyWithValue("known_issue", true))
+			Expect(specs["tests > test_auth > Session/test_logout"]).To(HaveKeyWithValue("outcome", "broken"))
+		})
+
+		It("should carry over links to Jira issues", func() {
+			testRun, err := application.ParseAllureResults(bytes.NewReader(zipAllureResults(allureResults)), 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			allure := testRun.Metadata["allure"].(map[string]interface{})
+			Expect(allure["jira_issues"]).To(Equal(map[string]interface{}{
+				"AUTH-42": []string{"tests > test_auth/test_login"},
+				"AUTH-7":  []string{"tests > test_auth > Session/test_logout"},
+			}))
+
+			login := allure["specs"].(map[string]interface{})["tests > test_auth/test_login"].(map[string]interface{})
+			Expect(login["jira_issues"]).To(Equal([]string{"AUTH-42"}))
+			Expect(login["links"]).To(HaveLen(2))
+			Expect(login["tags"]).To(Equal([]string{"smoke"}))
+			Expect(login["labels"]).To(HaveKeyWithValue("severity", "critical"))
+		})
+
+		It("should keep steps and attachments", func() {
+			testRun, err := application.ParseAllureResults(bytes.NewReader(zipAllureResults(allureResults)), 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			login := testRun.SuiteRuns[0].SpecRuns[0]
+			Expect(login.Steps).To(HaveLen(3))
+			Expect(login.Steps[0].Name).To(Equal("open login page"))
+			Expect(login.Steps[1].Name).To(Equal("wait for form"))
+			Expect(login.Steps[2].Duration).To(Equal(300 * time.Millisecond))
+
+			Expect(login.Attachments).To(HaveLen(2))
+			Expect(login.Attachments[0].Name).To(Equal("log"))
+			Expect(login.Attachments[0].ContentType).To(HavePrefix("text/plain"))
+			Expect(string(login.Attachments[0].Content)).To(Equal("login succeeded"))
+			Expect(login.Attachments[1].Name).To(Equal("after submit"))
+			Expect(login.Attachments[1].ContentType).To(Equal("image/png"))
+		})
+
+		It("should name parameterised results after their visible parameters", func() {
+			testRun, err := application.ParseAllureResults(bytes.NewReader(zipAllureResults(allureResults)), 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			pay := testRun.SuiteRuns[2]
+			Expect(pay.SpecRuns).To(HaveLen(2))
+			Expect(pay.SpecRuns[0].Name).To(Equal("test_charge [amount=10, token=******]"))
+			Expect(pay.SpecRuns[0].ParentName).To(Equal("test_charge"))
+			Expect(pay.SpecRuns[0].Parameters).To(Equal(map[string]string{"amount": "10", "token": "******"}))
+			skipped := pay.SpecRuns[1]
+			Expect(skipped.Name).To(Equal("test_charge [amount=0, token=******]"))
+			Expect(skipped.Status).To(Equal("skipped"))
+			Expect(skipped.Steps).To(HaveLen(1))
+			Expect(skipped.Steps[0].Keyword).To(Equal("Before"))
+			Expect(skipped.Steps[0].ErrorMessage).To(Equal("database unavailable"))
+
+			allure := testRun.Metadata["allure"].(map[string]interface{})
+			Expect(allure["parameterized_tests"]).To(HaveKeyWithValue("tests.test_pay#test_charge", HaveLen(2)))
+			Expect(allure["environment"]).To(Equal(map[string]string{"Python": "3.12", "Browser": "chromium"}))
+			specs := allure["specs"].(map[string]interface{})
+			Expect(specs["test_pay/test_charge [amount=10, token=******]"]).To(HaveKeyWithValue("parameters", map[string]string{"amount": "10"}))
+		})
+
+		It("should leave out attachments over the size limit without reading them", func() {
+			testRun, err := application.ParseAllureResults(bytes.NewReader(zipAllureResults(allureResults)), 16)
+			Expect(err).NotTo(HaveOccurred())
+
+			login := testRun.SuiteRuns[0].SpecRuns[0]
+			Expect(login.Attachments).To(HaveLen(1))
+			Expect(login.Attachments[0].Name).To(Equal("log"))
+			Expect(testRun.Metadata["dropped_attachments"]).To(Equal([]map[string]interface{}{{
+				"suite":  "tests > test_auth",
+				"spec":   "test_login",
+				"name":   "after submit",
+				"size":   int64(len(allureResults["allure-results/s1-attachment.png"])),
+				"reason": domain.ErrAttachmentTooLarge.Error(),
+			}}))
+		})
+
+		It("should reject result files that expand beyond the file size limit", func() {
+			_, err := application.ParseAllureResults(bytes.NewReader(zipAllureResults(map[string]string{
+				"a-result.json": `{"uuid": "a", "name": "` + strings.Repeat("a", 16<<20) + `"}`,
+			})), 0)
+
+			Expect(err).To(MatchError(ContainSubstring("a-result.json is larger than")))
+		})
+
+		It("should reject uploads that are not zipped allure results", func() {
+			_, err := application.ParseAllureResults(bytes.NewReader([]byte(`<testsuites/>`)), 0)
+			Expect(err).To(MatchError(ContainSubstring("invalid allure results archive")))
+
+			_, err = application.ParseAllureResults(bytes.NewReader(zipAllureResults(map[string]string{"README.md": "empty"})), 0)
+			Expect(err).To(MatchError(ContainSubstring("no allure results found")))
+		})
+	})
+
+	Describe("ImportReport", func() {
+		It("should persist results with their attachments", func() {
+			mockTestRunRepo := new(MockTestRunRepository)
+			service := application.NewTestRunService(mockTestRunRepo, new(MockSuiteRunRepository), new(MockSpecRunRepository))
+			ctx := context.Background()
+
+			mockTestRunRepo.On("CreateWithHierarchy", ctx, mock.MatchedBy(func(tr *domain.TestRun) bool {
+				return tr.ProjectID == "proj-123" && tr.TotalTests == 4 && len(tr.SuiteRuns[0].SpecRuns[0].Attachments) == 2
+			}), []string(nil)).Return(nil)
+
+			Expect(application.IsSupportedReportFormat(application.ReportFormatAllure)).To(BeTrue())
+			_, err := service.ImportReport(ctx, application.ReportFormatAllure, bytes.NewReader(zipAllureResults(allureResults)), application.ImportOptions{
+				ProjectID: "proj-123",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			mockTestRunRepo.AssertExpectations(GinkgoT())
+		})
+	})
+})
@@ -0,0 +1,360 @@
+package application
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// maxAttachmentNameLength bounds stored attachment names
+const maxAttachmentNameLength = 255
+
+// AttachmentConfig bounds attachment sizes and configures signed download URLs
+type AttachmentConfig struct {
+	MaxSize      int64         // Largest single attachment in bytes, 0 for unlimited
+	ProjectQuota int64         // Default total attachment bytes per project, 0 for unlimited
+	SigningKey   []byte        // HMAC key for download URL signatures
+	URLExpiry    time.Duration // Lifetime of signed download URLs
+}
+
+// AttachmentQuotaFunc returns a project's own attachment quota in bytes, or false to use the default
+type AttachmentQuotaFunc func(ctx context.Context, projectID string) (int64, bool)
+
+var (
+	// ErrInvalidAttachmentSignature is returned for download URLs that are tampered with or expired
+	ErrInvalidAttachmentSignature = errors.New("invalid or expired attachment signature")
+
+	// ErrInvalidAttachmentTarget is returned when a suite or spec run is not part of the test run
+	ErrInvalidAttachmentTarget = errors.New("suite or spec run does not belong to the test run")
+)
+
+// UploadAttachmentRequest describes a file to attach to a test run, or to one of its suites or specs
+type UploadAttachmentRequest struct {
+	TestRunID   uint
+	SuiteRunID  *uint
+	SpecRunID   *uint
+	Name        string
+	ContentType string // Declared type; the content is sniffed when it is missing or generic
+	Size        int64
+	Content     io.Reader
+}
+
+// AttachmentService stores test attachments in a blob store and signs their download URLs
+type AttachmentService struct {
+	attachmentRepo domain.AttachmentRepository
+	testRunRepo    domain.TestRunRepository
+	suiteRunRepo   domain.SuiteRunRepository
+	specRunRepo    domain.SpecRunRepository
+	store          domain.BlobStore
+	config         AttachmentConfig
+	projectQuota   AttachmentQuotaFunc
+	now            func() time.Time
+}
+
+// NewAttachmentService creates a new attachment service writing to the given blob store.
+// projectQuota may be nil when projects cannot override the default quota.
+func NewAttachmentService(
+	attachmentRepo domain.AttachmentRepository,
+	testRunRepo domain.TestRunRepository,
+	suiteRunRepo domain.SuiteRunRepository,
+	specRunRepo domain.SpecRunRepository,
+	store domain.BlobStore,
+	config AttachmentConfig,
+	projectQuota AttachmentQuotaFunc,
+) *AttachmentService {
+	if config.URLExpiry <= 0 {
+		config.URLExpiry = 15 * time.Minute
+	}
+	return &AttachmentService{
+		attachmentRepo: attachmentRepo,
+		testRunRepo:    testRunRepo,
+		suiteRunRepo:   suiteRunRepo,
+		specRunRepo:    specRunRepo,
+		store:          store,
+		config:         config,
+		projectQuota:   projectQuota,
+		now:            time.Now,
+	}
+}
+
+// Upload stores a file and links it to a test run, suite or spec. A spec's suite is filled in
+// when only the spec is given.
+func (s *AttachmentService) Upload(ctx context.Context, req UploadAttachmentRequest) (*domain.Attachment, error) {
+	testRun, err := s.testRunRepo.GetByID(ctx, req.TestRunID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get test run: %w", err)
+	}
+	if err := s.resolveTarget(ctx, &req); err != nil {
+		return nil, err
+	}
+	if req.Size < 0 {
+		return nil, fmt.Errorf("attachment size is required")
+	}
+	remaining, err := s.remainingQuota(ctx, testRun.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSize(req.Size, remaining); err != nil {
+		return nil, err
+	}
+
+	content := bufio.NewReaderSize(io.LimitReader(req.Content, req.Size), 512)
+	head, _ := content.Peek(512)
+
+	attachment := &domain.Attachment{
+		ProjectID:   testRun.ProjectID,
+		TestRunID:   testRun.ID,
+		SuiteRunID:  req.SuiteRunID,
+		SpecRunID:   req.SpecRunID,
+		Name:        attachmentName(req.Name),
+		ContentType: detectAttachmentContentType(req.ContentType, req.Name, head),
+		Size:        req.Size,
+	}
+	if err := s.put(ctx, attachment, content); err != nil {
+		return nil, err
+	}
+
+	if err := s.attachmentRepo.Create(ctx, attachment); err != nil {
+		_ = s.store.Delete(ctx, attachment.StorageKey)
+		return nil, fmt.Errorf("failed to record attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+// resolveTarget checks that the suite and spec belong to the test run
+func (s *AttachmentService) resolveTarget(ctx context.Context, req *UploadAttachmentRequest) error {
+	if req.SpecRunID != nil {
+		specRun, err := s.specRunRepo.GetByID(ctx, *req.SpecRunID)
+		if err != nil {
+			return fmt.Errorf("failed to get spec run: %w", err)
+		}
+		if req.SuiteRunID != nil && *req.SuiteRunID != specRun.SuiteRunID {
+			return fmt.Errorf("%w: spec run %d is not in suite run %d", ErrInvalidAttachmentTarget, specRun.ID, *req.SuiteRunID)
+		}
+		suiteRunID := specRun.SuiteRunID
+		req.SuiteRunID = &suiteRunID
+	}
+	if req.SuiteRunID != nil {
+		suiteRun, err := s.suiteRunRepo.GetByID(ctx, *req.SuiteRunID)
+		if err != nil {
+			return fmt.Errorf("failed to get suite run: %w", err)
+		}
+		if suiteRun.TestRunID != req.TestRunID {
+			return fmt.Errorf("%w: suite run %d is not in test run %d", ErrInvalidAttachmentTarget, suiteRun.ID, req.TestRunID)
+		}
+	}
+	return nil
+}
+
+// remainingQuota returns how many more attachment bytes a project may store, or -1 if it is unlimited
+func (s *AttachmentService) remainingQuota(ctx context.Context, projectID string) (int64, error) {
+	quota := s.config.ProjectQuota
+	if s.projectQuota != nil {
+		if projectQuota, ok := s.projectQuota(ctx, projectID); ok {
+			quota = projectQuota
+		}
+	}
+	if quota <= 0 {
+		return -1, nil
+	}
+
+	used, err := s.attachmentRepo.TotalSizeByProject(ctx, projectID)
+	if err != nil {
+		return 0, err
+	}
+	if used >= quota {
+		return 0, nil
+	}
+	return quota - used, nil
+}
+
+// checkSize rejects an attachment that is too large on its own or for the remaining quota
+func (s *AttachmentService) checkSize(size, remaining int64) error {
+	if s.config.MaxSize > 0 && size > s.config.MaxSize {
+		return domain.ErrAttachmentTooLarge
+	}
+	if remaining >= 0 && size > remaining {
+		return domain.ErrAttachmentQuotaExceeded
+	}
+	return nil
+}
+
+// put writes the attachment's content to the blob store under a new key, recording its checksum
+func (s *AttachmentService) put(ctx context.Context, attachment *domain.Attachment, content io.Reader) error {
+	key := fmt.Sprintf("%s/%s/%s", attachment.ProjectID, s.now().UTC().Format("2006/01/02"), uuid.New().String())
+	hash := sha256.New()
+	if err := s.store.Put(ctx, key, io.TeeReader(content, hash), attachment.Size, attachment.ContentType); err != nil {
+		return fmt.Errorf("failed to store attachment: %w", err)
+	}
+
+	attachment.StorageBackend = s.store.Name()
+	attachment.StorageKey = key
+	attachment.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	attachment.Content = nil
+	return nil
+}
+
+// storeImportedAttachments moves the content of attachments parsed from a report into the blob
+// store before the run is recorded. Attachments over the size limit or the project's quota are
+// left out and listed in the run's metadata under "dropped_attachments".
+func (s *AttachmentService) storeImportedAttachments(ctx context.Context, testRun *domain.TestRun) error {
+	remaining, err := s.remainingQuota(ctx, testRun.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	// Parsers may already have left out attachments they would not decompress
+	dropped, _ := testRun.Metadata["dropped_attachments"].([]map[string]interface{})
+	for i := range testRun.SuiteRuns {
+		suite := &testRun.SuiteRuns[i]
+		for _, spec := range suite.SpecRuns {
+			kept := spec.Attachments[:0]
+			for _, attachment := range spec.Attachments {
+				size := int64(len(attachment.Content))
+				if err := s.checkSize(size, remaining); err != nil {
+					dropped = append(dropped, map[string]interface{}{
+						"suite":  suite.Name,
+						"spec":   spec.Name,
+						"name":   attachment.Name,
+						"size":   size,
+						"reason": err.Error(),
+					})
+					continue
+				}
+
+				attachment.ProjectID = testRun.ProjectID
+				attachment.Size = size
+				if err := s.put(ctx, &attachment, bytes.NewReader(attachment.Content)); err != nil {
+					return err
+				}
+				if remaining >= 0 {
+					remaining -= size
+				}
+				kept = append(kept, attachment)
+			}
+			spec.Attachments = kept
+		}
+	}
+
+	if len(dropped) > 0 {
+		if testRun.Metadata == nil {
+			testRun.Metadata = make(map[string]interface{})
+		}
+		testRun.Metadata["dropped_attachments"] = dropped
+	}
+	return nil
+}
+
+// MaxSize returns the largest attachment accepted, or 0 if there is no limit
+func (s *AttachmentService) MaxSize() int64 {
+	return s.config.MaxSize
+}
+
+// GetAttachment retrieves an attachment's details
+func (s *AttachmentService) GetAttachment(ctx context.Context, id uint) (*domain.Attachment, error) {
+	return s.attachmentRepo.GetByID(ctx, id)
+}
+
+// ListAttachments retrieves the attachments of a test run, or of one of its suites or specs
+func (s *AttachmentService) ListAttachments(ctx context.Context, filter domain.AttachmentFilter) ([]*domain.Attachment, error) {
+	return s.attachmentRepo.Find(ctx, filter)
+}
+
+// OpenAttachment retrieves an attachment together with its content
+func (s *AttachmentService) OpenAttachment(ctx context.Context, id uint) (*domain.Attachment, io.ReadCloser, error) {
+	attachment, err := s.attachmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if attachment.StorageBackend == domain.StorageBackendDatabase {
+		content, err := s.attachmentRepo.GetContent(ctx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		return attachment, io.NopCloser(bytes.NewReader(content)), nil
+	}
+	if attachment.StorageBackend != s.store.Name() {
+		return nil, nil, fmt.Errorf("attachment %d is kept in the %q blob store, which is not configured", id, attachment.StorageBackend)
+	}
+
+	content, err := s.store.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	return attachment, content, nil
+}
+
+// SignedURL returns a download URL for an attachment that is valid without other credentials
+// until it expires
+func (s *AttachmentService) SignedURL(attachment *domain.Attachment) string {
+	expires := s.now().Add(s.config.URLExpiry).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", s.sign(attachment.ID, expires))
+	return fmt.Sprintf("/api/v1/attachments/%d/content?%s", attachment.ID, query.Encode())
+}
+
+// VerifySignature checks a download URL's signature and expiry
+func (s *AttachmentService) VerifySignature(id uint, expires int64, signature string) error {
+	if s.now().Unix() >= expires {
+		return ErrInvalidAttachmentSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(id, expires))) {
+		return ErrInvalidAttachmentSignature
+	}
+	return nil
+}
+
+func (s *AttachmentService) sign(id uint, expires int64) string {
+	mac := hmac.New(sha256.New, s.config.SigningKey)
+	fmt.Fprintf(mac, "%d:%d", id, expires)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// detectAttachmentContentType picks an attachment's media type from its leading bytes, falling
+// back to the declared type and then to the file extension when sniffing is inconclusive
+func detectAttachmentContentType(declared, name string, head []byte) string {
+	sniffed := http.DetectContentType(head)
+	if sniffed != "application/octet-stream" && !strings.HasPrefix(sniffed, "text/plain") {
+		return sniffed
+	}
+	if declared != "" && declared != "application/octet-stream" {
+		if _, _, err := mime.ParseMediaType(declared); err == nil {
+			return declared
+		}
+	}
+	if byExtension := mime.TypeByExtension(path.Ext(name)); byExtension != "" {
+		return byExtension
+	}
+	return sniffed
+}
+
+// attachmentName strips directories from an uploaded file name and bounds its length
+func attachmentName(name string) string {
+	name = strings.TrimSpace(path.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	if len(name) > maxAttachmentNameLength {
+		name = name[:maxAttachmentNameLength]
+	}
+	return name
+}
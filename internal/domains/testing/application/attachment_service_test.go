@@ -0,0 +1,291 @@
+package application_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// MockAttachmentRepository is a mock implementation of domain.AttachmentRepository
+type MockAttachmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentRepository) Create(ctx context.Context, attachment *domain.Attachment) error {
+	args := m.Called(ctx, attachment)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) GetByID(ctx context.Context, id uint) (*domain.Attachment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) Find(ctx context.Context, filter domain.AttachmentFilter) ([]*domain.Attachment, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) GetContent(ctx context.Context, id uint) ([]byte, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) TotalSizeByProject(ctx context.Context, projectID string) (int64, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// memoryBlobStore is an in-memory domain.BlobStore
+type memoryBlobStore struct {
+	blobs map[string][]byte
+}
+
+func newMemoryBlobStore() *memoryBlobStore {
+	return &memoryBlobStore{blobs: make(map[string][]byte)}
+}
+
+func (s *memoryBlobStore) Name() string { return "memory" }
+
+func (s *memoryBlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.blobs[key] = content
+	return nil
+}
+
+func (s *memoryBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	content, ok := s.blobs[key]
+	if !ok {
+		return nil, domain.ErrBlobNotFound
+	}
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+func (s *memoryBlobStore) Delete(ctx context.Context, key string) error {
+	delete(s.blobs, key)
+	return nil
+}
+
+// pngHeader is enough of a PNG file for content sniffing
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+var _ = Describe("AttachmentService", Label("unit", "application", "testing"), func() {
+	var (
+		mockAttachmentRepo *MockAttachmentRepository
+		mockTestRunRepo    *MockTestRunRepository
+		mockSuiteRunRepo   *MockSuiteRunRepository
+		mockSpecRunRepo    *MockSpecRunRepository
+		store              *memoryBlobStore
+		config             application.AttachmentConfig
+		projectQuota       application.AttachmentQuotaFunc
+		ctx                context.Context
+	)
+
+	newService := func() *application.AttachmentService {
+		return application.NewAttachmentService(mockAttachmentRepo, mockTestRunRepo, mockSuiteRunRepo, mockSpecRunRepo, store, config, projectQuota)
+	}
+
+	BeforeEach(func() {
+		mockAttachmentRepo = new(MockAttachmentRepository)
+		mockTestRunRepo = new(MockTestRunRepository)
+		mockSuiteRunRepo = new(MockSuiteRunRepository)
+		mockSpecRunRepo = new(MockSpecRunRepository)
+		store = newMemoryBlobStore()
+		config = application.AttachmentConfig{MaxSize: 1024, ProjectQuota: 4096, SigningKey: []byte("test-key")}
+		projectQuota = nil
+		ctx = context.Background()
+
+		mockTestRunRepo.On("GetByID", ctx, uint(1)).Return(&domain.TestRun{ID: 1, ProjectID: "proj-123"}, nil).Maybe()
+	})
+
+	Describe("Upload", func() {
+		It("should store the content and sniff its type", func() {
+			mockAttachmentRepo.On("TotalSizeByProject", ctx, "proj-123").Return(int64(0), nil)
+			mockAttachmentRepo.On("Create", ctx, mock.AnythingOfType("*domain.Attachment")).Return(nil)
+
+			attachment, err := newService().Upload(ctx, application.UploadAttachmentRequest{
+				TestRunID:   1,
+				Name:        "screens/failure.png",
+				ContentType: "application/octet-stream",
+				Size:        int64(len(pngHeader)),
+				Content:     bytes.NewReader(pngHeader),
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attachment.Name).To(Equal("failure.png"))
+			Expect(attachment.ContentType).To(Equal("image/png"))
+			Expect(attachment.StorageBackend).To(Equal("memory"))
+			Expect(attachment.StorageKey).To(HavePrefix("proj-123/"))
+			sum := sha256.Sum256(pngHeader)
+			Expect(attachment.SHA256).To(Equal(hex.EncodeToString(sum[:])))
+			Expect(store.blobs[attachment.StorageKey]).To(Equal(pngHeader))
+		})
+
+		It("should fall back to the declared type and then the extension", func() {
+			mockAttachmentRepo.On("TotalSizeByProject", ctx, "proj-123").Return(int64(0), nil)
+			mockAttachmentRepo.On("Create", ctx, mock.AnythingOfType("*domain.Attachment")).Return(nil)
+			service := newService()
+
+			declared, err := service.Upload(ctx, application.UploadAttachmentRequest{
+				TestRunID: 1, Name: "log", ContentType: "text/x-log", Size: 5, Content: strings.NewReader("hello"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(declared.ContentType).To(Equal("text/x-log"))
+
+			byExtension, err := service.Upload(ctx, application.UploadAttachmentRequest{
+				TestRunID: 1, Name: "report.json", Size: 2, Content: strings.NewReader("{}"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(byExtension.ContentType).To(Equal("application/json"))
+		})
+
+		It("should reject attachments over the size limit", func() {
+			mockAttachmentRepo.On("TotalSizeByProject", ctx, "proj-123").Return(int64(0), nil)
+
+			_, err := newService().Upload(ctx, application.UploadAttachmentRequest{
+				TestRunID: 1, Name: "big.bin", Size: 2048, Content: bytes.NewReader(make([]byte, 2048)),
+			})
+
+			Expect(err).To(MatchError(domain.ErrAttachmentTooLarge))
+			Expect(store.blobs).To(BeEmpty())
+		})
+
+		It("should enforce the project's own quota", func() {
+			projectQuota = func(ctx context.Context, projectID string) (int64, bool) { return 100, true }
+			mockAttachmentRepo.On("TotalSizeByProject", ctx, "proj-123").Return(int64(90), nil)
+
+			_, err := newService().Upload(ctx, application.UploadAttachmentRequest{
+				TestRunID: 1, Name: "log.txt", Size: 20, Content: bytes.NewReader(make([]byte, 20)),
+			})
+
+			Expect(err).To(MatchError(domain.ErrAttachmentQuotaExceeded))
+		})
+
+		It("should reject a suite from another test run", func() {
+			suiteRunID := uint(7)
+			mockSuiteRunRepo.On("GetByID", ctx, suiteRunID).Return(&domain.SuiteRun{ID: 7, TestRunID: 2}, nil)
+
+			_, err := newService().Upload(ctx, application.UploadAttachmentRequest{
+				TestRunID: 1, SuiteRunID: &suiteRunID, Name: "log.txt", Size: 1, Content: strings.NewReader("x"),
+			})
+
+			Expect(err).To(MatchError(application.ErrInvalidAttachmentTarget))
+		})
+
+		It("should remove the blob when the attachment cannot be recorded", func() {
+			mockAttachmentRepo.On("TotalSizeByProject", ctx, "proj-123").Return(int64(0), nil)
+			mockAttachmentRepo.On("Create", ctx, mock.AnythingOfType("*domain.Attachment")).Return(io.ErrUnexpectedEOF)
+
+			_, err := newService().Upload(ctx, application.UploadAttachmentRequest{
+				TestRunID: 1, Name: "log.txt", Size: 1, Content: strings.NewReader("x"),
+			})
+
+			Expect(err).To(HaveOccurred())
+			Expect(store.blobs).To(BeEmpty())
+		})
+	})
+
+	Describe("OpenAttachment", func() {
+		It("should read blobs from the store and legacy content from the database", func() {
+			store.blobs["proj-123/key"] = []byte("from store")
+			mockAttachmentRepo.On("GetByID", ctx, uint(1)).Return(&domain.Attachment{ID: 1, StorageBackend: "memory", StorageKey: "proj-123/key"}, nil)
+			mockAttachmentRepo.On("GetByID", ctx, uint(2)).Return(&domain.Attachment{ID: 2, StorageBackend: domain.StorageBackendDatabase}, nil)
+			mockAttachmentRepo.On("GetContent", ctx, uint(2)).Return([]byte("inline"), nil)
+			service := newService()
+
+			_, content, err := service.OpenAttachment(ctx, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(io.ReadAll(content)).To(Equal([]byte("from store")))
+
+			_, content, err = service.OpenAttachment(ctx, 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(io.ReadAll(content)).To(Equal([]byte("inline")))
+		})
+	})
+
+	Describe("SignedURL", func() {
+		It("should produce URLs that verify only for the same attachment", func() {
+			service := newService()
+
+			signed, err := url.Parse(service.SignedURL(&domain.Attachment{ID: 42}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(signed.Path).To(Equal("/api/v1/attachments/42/content"))
+			expires, err := strconv.ParseInt(signed.Query().Get("expires"), 10, 64)
+			Expect(err).NotTo(HaveOccurred())
+			signature := signed.Query().Get("signature")
+
+			Expect(service.VerifySignature(42, expires, signature)).To(Succeed())
+			Expect(service.VerifySignature(43, expires, signature)).To(MatchError(application.ErrInvalidAttachmentSignature))
+			Expect(service.VerifySignature(42, expires+1, signature)).To(MatchError(application.ErrInvalidAttachmentSignature))
+		})
+
+		It("should reject expired URLs", func() {
+			service := newService()
+			expired := time.Now().Add(-time.Minute).Unix()
+
+			signed, err := url.Parse(service.SignedURL(&domain.Attachment{ID: 42}))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(service.VerifySignature(42, expired, signed.Query().Get("signature"))).To(MatchError(application.ErrInvalidAttachmentSignature))
+		})
+	})
+
+	Describe("report imports", func() {
+		It("should move attachments to the blob store and list the ones dropped", func() {
+			config.MaxSize = 8
+			service := application.NewTestRunService(mockTestRunRepo, mockSuiteRunRepo, mockSpecRunRepo)
+			service.SetAttachmentService(newService())
+			mockAttachmentRepo.On("TotalSizeByProject", ctx, "proj-123").Return(int64(0), nil)
+
+			var stored *domain.TestRun
+			mockTestRunRepo.On("CreateWithHierarchy", ctx, mock.AnythingOfType("*domain.TestRun"), []string(nil)).
+				Run(func(args mock.Arguments) { stored = args.Get(1).(*domain.TestRun) }).
+				Return(nil)
+
+			_, err := service.ImportReport(ctx, application.ReportFormatAllure, bytes.NewReader(zipAllureResults(map[string]string{
+				"a-result.json": `{"uuid": "a", "name": "test_login", "status": "passed", "start": 1709287200000, "stop": 1709287200500,
+  "labels": [{"name": "suite", "value": "test_auth"}],
+  "attachments": [{"name": "small", "source": "s-attachment.txt"}, {"name": "big", "source": "b-attachment.txt"}]}`,
+				"s-attachment.txt": "ok",
+				"b-attachment.txt": "far too large",
+			})), application.ImportOptions{ProjectID: "proj-123"})
+
+			Expect(err).NotTo(HaveOccurred())
+			attachments := stored.SuiteRuns[0].SpecRuns[0].Attachments
+			Expect(attachments).To(HaveLen(1))
+			Expect(attachments[0].StorageBackend).To(Equal("memory"))
+			Expect(attachments[0].Content).To(BeNil())
+			Expect(store.blobs[attachments[0].StorageKey]).To(Equal([]byte("ok")))
+
+			dropped := stored.Metadata["dropped_attachments"].([]map[string]interface{})
+			Expect(dropped).To(HaveLen(1))
+			Expect(dropped[0]["name"]).To(Equal("big"))
+			Expect(dropped[0]["reason"]).To(Equal(domain.ErrAttachmentTooLarge.Error()))
+		})
+	})
+})
@@ -0,0 +1,472 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// ctrfSpecVersion is the version of the Common Test Report Format written by ExportCTRF
+const ctrfSpecVersion = "0.0.0"
+
+// CTRFReport is a Common Test Report Format document, see https://ctrf.io
+type CTRFReport struct {
+	ReportFormat string      `json:"reportFormat,omitempty"`
+	SpecVersion  string      `json:"specVersion,omitempty"`
+	Results      CTRFResults `json:"results"`
+}
+
+// CTRFResults holds the tests of a CTRF report and what they ran on
+type CTRFResults struct {
+	Tool        CTRFTool               `json:"tool"`
+	Summary     CTRFSummary            `json:"summary"`
+	Tests       []CTRFTest             `json:"tests"`
+	Environment *CTRFEnvironment       `json:"environment,omitempty"`
+	Extra       map[string]interface{} `json:"extra,omitempty"`
+}
+
+// CTRFTool names the framework that produced a CTRF report
+type CTRFTool struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// CTRFSummary counts a report's tests by status; start and stop are Unix milliseconds
+type CTRFSummary struct {
+	Tests   int   `json:"tests"`
+	Passed  int   `json:"passed"`
+	Failed  int   `json:"failed"`
+	Pending int   `json:"pending"`
+	Skipped int   `json:"skipped"`
+	Other   int   `json:"other"`
+	Start   int64 `json:"start"`
+	Stop    int64 `json:"stop"`
+}
+
+// CTRFTest is a single test result; duration is in milliseconds and start and stop are
+// Unix milliseconds
+type CTRFTest struct {
+	Name      string                 `json:"name"`
+	Status    string                 `json:"status"`
+	Duration  int64                  `json:"duration"`
+	Start     int64                  `json:"start,omitempty"`
+	Stop      int64                  `json:"stop,omitempty"`
+	Suite     CTRFSuite              `json:"suite,omitempty"`
+	Message   string                 `json:"message,omitempty"`
+	Trace     string                 `json:"trace,omitempty"`
+	RawStatus string                 `json:"rawStatus,omitempty"`
+	Tags      []string               `json:"tags,omitempty"`
+	Type      string                 `json:"type,omitempty"`
+	FilePath  string                 `json:"filePath,omitempty"`
+	Retries   int                    `json:"retries,omitempty"`
+	Flaky     bool                   `json:"flaky,omitempty"`
+	Browser   string                 `json:"browser,omitempty"`
+	Stdout    []string               `json:"stdout,omitempty"`
+	Stderr    []string               `json:"stderr,omitempty"`
+	Extra     map[string]interface{} `json:"extra,omitempty"`
+
+	// Parameters are the parameter set of one case of a parameterised test, which shares its
+	// name with the test's other cases
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+
+	// RetryAttempts are the earlier attempts of a retried test; the test itself is the last one
+	RetryAttempts []CTRFRetryAttempt `json:"retryAttempts,omitempty"`
+}
+
+// CTRFRetryAttempt is one earlier attempt of a retried test
+type CTRFRetryAttempt struct {
+	Attempt  int    `json:"attempt"`
+	Status   string `json:"status"`
+	Duration int64  `json:"duration"`
+	Start    int64  `json:"start,omitempty"`
+	Message  string `json:"message,omitempty"`
+	Trace    string `json:"trace,omitempty"`
+}
+
+// CTRFSuite is the suite of a test. Reporters write it either as a single name or as
+// the path of nested suites, which is joined with " > ".
+type CTRFSuite string
+
+// UnmarshalJSON accepts a suite name or an array of nested suite names
+func (s *CTRFSuite) UnmarshalJSON(data []byte) error {
+	var path []string
+	if err := json.Unmarshal(data, &path); err == nil {
+		*s = CTRFSuite(strings.Join(path, " > "))
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("suite must be a string or an array of strings")
+	}
+	*s = CTRFSuite(name)
+	return nil
+}
+
+// CTRFEnvironment describes the build a report came from
+type CTRFEnvironment struct {
+	AppName         string `json:"appName,omitempty"`
+	AppVersion      string `json:"appVersion,omitempty"`
+	BuildName       string `json:"buildName,omitempty"`
+	BuildNumber     string `json:"buildNumber,omitempty"`
+	BuildURL        string `json:"buildUrl,omitempty"`
+	RepositoryName  string `json:"repositoryName,omitempty"`
+	RepositoryURL   string `json:"repositoryUrl,omitempty"`
+	Commit          string `json:"commit,omitempty"`
+	BranchName      string `json:"branchName,omitempty"`
+	OSPlatform      string `json:"osPlatform,omitempty"`
+	OSRelease       string `json:"osRelease,omitempty"`
+	OSVersion       string `json:"osVersion,omitempty"`
+	TestEnvironment string `json:"testEnvironment,omitempty"`
+}
+
+// ctrfDefaultSuite names the suite of tests that don't report one
+const ctrfDefaultSuite = "default"
+
+// ParseCTRF converts a CTRF JSON report into a test run hierarchy, with one suite run per
+// test suite. Pending and other tests are recorded as skipped specs; their CTRF status is
+// kept in metadata so that ExportCTRF can restore it. The branch, commit and test environment
+// are taken from the report's environment. The returned test run has no project or run ID assigned.
+func ParseCTRF(r io.Reader) (*domain.TestRun, error) {
+	var report CTRFReport
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return nil, fmt.Errorf("invalid ctrf report: %w", err)
+	}
+	if report.Results.Tool.Name == "" && report.Results.Tests == nil {
+		return nil, fmt.Errorf("invalid ctrf report: missing results")
+	}
+	results := report.Results
+
+	testRun := &domain.TestRun{Source: ReportFormatCTRF}
+	specMetadata := make(map[string]interface{})
+	suites := make(map[string]int)
+	for _, test := range results.Tests {
+		suiteName := string(test.Suite)
+		if suiteName == "" {
+			suiteName = ctrfDefaultSuite
+		}
+		i, ok := suites[suiteName]
+		if !ok {
+			i = len(testRun.SuiteRuns)
+			suites[suiteName] = i
+			testRun.SuiteRuns = append(testRun.SuiteRuns, domain.SuiteRun{Name: suiteName, Status: "passed"})
+		}
+
+		spec := newCTRFSpecRun(test)
+		appendSpecRun(&testRun.SuiteRuns[i], spec)
+
+		if meta := ctrfSpecMetadata(test); len(meta) > 0 {
+			specMetadata[suiteName+"/"+spec.Name] = meta
+		}
+	}
+
+	var endTime time.Time
+	for i := range testRun.SuiteRuns {
+		suite := &testRun.SuiteRuns[i]
+		summariseSuiteTimes(suite)
+		if !suite.StartTime.IsZero() && (testRun.StartTime.IsZero() || suite.StartTime.Before(testRun.StartTime)) {
+			testRun.StartTime = suite.StartTime
+		}
+		if suite.EndTime != nil && suite.EndTime.After(endTime) {
+			endTime = *suite.EndTime
+		}
+	}
+	// The summary's timestamps cover the whole run, including setup outside of any test
+	if results.Summary.Start > 0 {
+		testRun.StartTime = time.UnixMilli(results.Summary.Start).UTC()
+		if results.Summary.Stop >= results.Summary.Start {
+			endTime = time.UnixMilli(results.Summary.Stop).UTC()
+		}
+	}
+	if !testRun.StartTime.IsZero() && !endTime.IsZero() {
+		testRun.EndTime = &endTime
+		testRun.Duration = endTime.Sub(testRun.StartTime)
+	}
+	summariseTestRun(testRun)
+
+	ctrf := map[string]interface{}{
+		"tool": results.Tool.Name,
+	}
+	if results.Tool.Version != "" {
+		ctrf["tool_version"] = results.Tool.Version
+	}
+	if len(specMetadata) > 0 {
+		ctrf["specs"] = specMetadata
+	}
+	if len(results.Extra) > 0 {
+		ctrf["extra"] = results.Extra
+	}
+	if env := results.Environment; env != nil {
+		testRun.Branch = env.BranchName
+		testRun.GitBranch = env.BranchName
+		testRun.GitCommit = env.Commit
+		testRun.Environment = env.TestEnvironment
+		ctrf["environment"] = env
+	}
+	testRun.Metadata = map[string]interface{}{
+		"source_format": ReportFormatCTRF,
+		"ctrf":          ctrf,
+	}
+
+	return testRun, nil
+}
+
+func newCTRFSpecRun(test CTRFTest) *domain.SpecRun {
+	spec := &domain.SpecRun{
+		Name:       test.Name,
+		Status:     ctrfStatus(test.Status),
+		Duration:   time.Duration(test.Duration) * time.Millisecond,
+		RetryCount: test.Retries,
+		IsFlaky:    test.Flaky,
+		Output:     domain.NewSpecOutput(strings.Join(test.Stdout, "\n"), strings.Join(test.Stderr, "\n")),
+		Parameters: toParameters(test.Parameters),
+	}
+	if test.Start > 0 {
+		spec.StartTime = time.UnixMilli(test.Start).UTC()
+		endTime := spec.StartTime.Add(spec.Duration)
+		if test.Stop >= test.Start {
+			endTime = time.UnixMilli(test.Stop).UTC()
+		}
+		spec.EndTime = &endTime
+	}
+	if spec.Status == "failed" {
+		spec.ErrorMessage = test.Message
+		if spec.ErrorMessage == "" {
+			spec.ErrorMessage = "test failed"
+		}
+		spec.FailureMessage = spec.ErrorMessage
+		spec.StackTrace = test.Trace
+	}
+	if len(test.RetryAttempts) > 0 {
+		retries := append([]CTRFRetryAttempt(nil), test.RetryAttempts...)
+		sort.SliceStable(retries, func(i, j int) bool { return retries[i].Attempt < retries[j].Attempt })
+		for _, retry := range retries {
+			attempt := domain.SpecAttempt{
+				Status:       ctrfStatus(retry.Status),
+				Duration:     time.Duration(retry.Duration) * time.Millisecond,
+				ErrorMessage: retry.Message,
+				StackTrace:   retry.Trace,
+			}
+			if retry.Start > 0 {
+				attempt.StartTime = time.UnixMilli(retry.Start).UTC()
+			}
+			spec.Attempts = append(spec.Attempts, attempt)
+		}
+		spec.Attempts = append(spec.Attempts, domain.SpecAttempt{
+			Status:       spec.Status,
+			StartTime:    spec.StartTime,
+			Duration:     spec.Duration,
+			ErrorMessage: spec.ErrorMessage,
+			StackTrace:   spec.StackTrace,
+		})
+		spec.ClassifyAttempts()
+	}
+	return spec
+}
+
+// ctrfSpecMetadata keeps the details of a test that spec runs have no field for
+func ctrfSpecMetadata(test CTRFTest) map[string]interface{} {
+	meta := make(map[string]interface{})
+	if status := strings.ToLower(test.Status); status == "pending" || status == "other" {
+		meta["status"] = status
+	}
+	if test.Status != "failed" && test.Message != "" {
+		meta["message"] = test.Message
+	}
+	if test.RawStatus != "" {
+		meta["raw_status"] = test.RawStatus
+	}
+	if len(test.Tags) > 0 {
+		meta["tags"] = test.Tags
+	}
+	if test.Type != "" {
+		meta["type"] = test.Type
+	}
+	if test.FilePath != "" {
+		meta["file_path"] = test.FilePath
+	}
+	if test.Browser != "" {
+		meta["browser"] = test.Browser
+	}
+	if len(test.Extra) > 0 {
+		meta["extra"] = test.Extra
+	}
+	return meta
+}
+
+// ctrfStatus maps CTRF statuses onto spec statuses
+func ctrfStatus(status string) string {
+	switch strings.ToLower(status) {
+	case "passed":
+		return "passed"
+	case "failed":
+		return "failed"
+	default:
+		return "skipped"
+	}
+}
+
+// ExportCTRF converts a test run, loaded with its suites and specs, into a CTRF report.
+// Details recorded when the run was imported from CTRF, such as pending tests and file
+// paths, are restored so that a report survives a round trip through Fern.
+func ExportCTRF(testRun *domain.TestRun) *CTRFReport {
+	ctrf, _ := testRun.Metadata["ctrf"].(map[string]interface{})
+	specMetadata, _ := ctrf["specs"].(map[string]interface{})
+
+	tool := CTRFTool{Name: "fern-platform"}
+	if name, _ := ctrf["tool"].(string); name != "" {
+		tool.Name = name
+		tool.Version, _ = ctrf["tool_version"].(string)
+	} else if format, _ := testRun.Metadata["source_format"].(string); format != "" {
+		tool.Name = format
+	}
+
+	report := &CTRFReport{
+		ReportFormat: "CTRF",
+		SpecVersion:  ctrfSpecVersion,
+		Results: CTRFResults{
+			Tool:  tool,
+			Tests: []CTRFTest{},
+			Extra: map[string]interface{}{
+				"fernTestRunId": testRun.ID,
+				"runId":         testRun.RunID,
+				"projectId":     testRun.ProjectID,
+			},
+		},
+	}
+
+	summary := &report.Results.Summary
+	for _, suite := range testRun.SuiteRuns {
+		for _, spec := range suite.SpecRuns {
+			test := CTRFTest{
+				Name:     spec.Name,
+				Status:   spec.Status,
+				Duration: spec.Duration.Milliseconds(),
+				Suite:    CTRFSuite(suite.Name),
+				Message:  spec.ErrorMessage,
+				Trace:    spec.StackTrace,
+				Retries:  spec.RetryCount,
+				Flaky:    spec.IsFlaky,
+			}
+			if len(spec.Attempts) > 1 {
+				for i, attempt := range spec.Attempts[:len(spec.Attempts)-1] {
+					retry := CTRFRetryAttempt{
+						Attempt:  i + 1,
+						Status:   attempt.Status,
+						Duration: attempt.Duration.Milliseconds(),
+						Message:  attempt.ErrorMessage,
+						Trace:    attempt.StackTrace,
+					}
+					if !attempt.StartTime.IsZero() {
+						retry.Start = attempt.StartTime.UnixMilli()
+					}
+					test.RetryAttempts = append(test.RetryAttempts, retry)
+				}
+			}
+			if suite.Name == ctrfDefaultSuite && ctrf != nil {
+				test.Suite = ""
+			}
+			if !spec.StartTime.IsZero() {
+				test.Start = spec.StartTime.UnixMilli()
+				test.Stop = spec.StartTime.Add(spec.Duration).UnixMilli()
+				if spec.EndTime != nil {
+					test.Stop = spec.EndTime.UnixMilli()
+				}
+			}
+			if meta, ok := specMetadata[suite.Name+"/"+spec.Name].(map[string]interface{}); ok {
+				restoreCTRFTest(&test, meta)
+			}
+
+			switch test.Status {
+			case "passed":
+				summary.Passed++
+			case "failed":
+				summary.Failed++
+			case "pending":
+				summary.Pending++
+			case "skipped":
+				summary.Skipped++
+			default:
+				summary.Other++
+			}
+			report.Results.Tests = append(report.Results.Tests, test)
+		}
+	}
+	summary.Tests = len(report.Results.Tests)
+	if !testRun.StartTime.IsZero() {
+		summary.Start = testRun.StartTime.UnixMilli()
+		summary.Stop = testRun.StartTime.Add(testRun.Duration).UnixMilli()
+		if testRun.EndTime != nil {
+			summary.Stop = testRun.EndTime.UnixMilli()
+		}
+	}
+
+	env := &CTRFEnvironment{}
+	if stored, ok := ctrf["environment"]; ok {
+		// The environment is stored as the report sent it, and comes back from the database as a map
+		if data, err := json.Marshal(stored); err == nil {
+			_ = json.Unmarshal(data, env)
+		}
+	}
+	if testRun.Branch != "" {
+		env.BranchName = testRun.Branch
+	}
+	if testRun.GitCommit != "" {
+		env.Commit = testRun.GitCommit
+	}
+	if testRun.Environment != "" {
+		env.TestEnvironment = testRun.Environment
+	}
+	if *env != (CTRFEnvironment{}) {
+		report.Results.Environment = env
+	}
+
+	return report
+}
+
+// restoreCTRFTest applies the metadata ParseCTRF kept for a test
+func restoreCTRFTest(test *CTRFTest, meta map[string]interface{}) {
+	if status, _ := meta["status"].(string); status != "" {
+		test.Status = status
+	}
+	if message, _ := meta["message"].(string); message != "" {
+		test.Message = message
+	}
+	test.RawStatus, _ = meta["raw_status"].(string)
+	test.Type, _ = meta["type"].(string)
+	test.FilePath, _ = meta["file_path"].(string)
+	test.Browser, _ = meta["browser"].(string)
+	test.Extra, _ = meta["extra"].(map[string]interface{})
+
+	// Tags are []string when parsed and []interface{} once read back from the database
+	switch tags := meta["tags"].(type) {
+	case []string:
+		test.Tags = tags
+	case []interface{}:
+		for _, tag := range tags {
+			if s, ok := tag.(string); ok {
+				test.Tags = append(test.Tags, s)
+			}
+		}
+	}
+}
+
+// ImportCTRF parses a CTRF JSON report and records it as a new test run
+func (s *TestRunService) ImportCTRF(ctx context.Context, r io.Reader, opts ImportOptions) (*domain.TestRun, error) {
+	testRun, err := ParseCTRF(r)
+	if err != nil {
+		return nil, &InvalidReportError{Err: err}
+	}
+
+	if err := s.importTestRun(ctx, testRun, opts); err != nil {
+		return nil, err
+	}
+
+	return testRun, nil
+}
@@ -0,0 +1,175 @@
+package application_test
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
+)
+
+const ctrfReport = `{
+  "reportFormat": "CTRF",
+  "specVersion": "0.0.0",
+  "results": {
+    "tool": {"name": "playwright", "version": "1.42.0"},
+    "summary": {"tests": 5, "passed": 2, "failed": 1, "pending": 1, "skipped": 0, "other": 1, "start": 1709287200000, "stop": 1709287205000},
+    "tests": [
+      {"name": "logs in", "status": "passed", "duration": 1200, "start": 1709287200100, "stop": 1709287201300, "suite": ["auth", "login"], "filePath": "tests/login.spec.ts", "tags": ["@smoke"], "browser": "chromium"},
+      {"name": "logs out", "status": "passed", "duration": 800, "start": 1709287201300, "stop": 1709287202100, "suite": ["auth", "login"], "retries": 2, "flaky": true},
+      {"name": "pays", "status": "failed", "duration": 2000, "start": 1709287202100, "stop": 1709287204100, "suite": "checkout", "message": "expected 200, got 500", "trace": "at checkout.spec.ts:14", "retries": 1},
+      {"name": "refunds", "status": "pending", "duration": 0, "suite": "checkout"},
+      {"name": "exports", "status": "other", "duration": 10, "rawStatus": "interrupted", "extra": {"worker": 3}}
+    ],
+    "environment": {"appName": "shop", "branchName": "main", "commit": "abc123", "testEnvironment": "staging"}
+  }
+}`
+
+var _ = Describe("CTRF", Label("unit", "application", "testing"), func() {
+	Describe("ParseCTRF", func() {
+		It("should build suites from the tests' suites", func() {
+			testRun, err := application.ParseCTRF(strings.NewReader(ctrfReport))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(testRun.Source).To(Equal("ctrf"))
+			Expect(testRun.Status).To(Equal("failed"))
+			Expect(testRun.TotalTests).To(Equal(5))
+			Expect(testRun.PassedTests).To(Equal(2))
+			Expect(testRun.FailedTests).To(Equal(1))
+			Expect(testRun.SkippedTests).To(Equal(2))
+			Expect(testRun.StartTime).To(Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)))
+			Expect(testRun.Duration).To(Equal(5 * time.Second))
+			Expect(testRun.Branch).To(Equal("main"))
+			Expect(testRun.GitCommit).To(Equal("abc123"))
+			Expect(testRun.Environment).To(Equal("staging"))
+
+			Expect(testRun.SuiteRuns).To(HaveLen(3))
+			login := testRun.SuiteRuns[0]
+			Expect(login.Name).To(Equal("auth > login"))
+			Expect(login.SpecRuns[1].RetryCount).To(Equal(2))
+			Expect(login.SpecRuns[1].IsFlaky).To(BeTrue())
+
+			pays := testRun.SuiteRuns[1].SpecRuns[0]
+			Expect(pays.Status).To(Equal("failed"))
+			Expect(pays.Duration).To(Equal(2 * time.Second))
+			Expect(pays.ErrorMessage).To(Equal("expected 200, got 500"))
+			Expect(pays.StackTrace).To(Equal("at checkout.spec.ts:14"))
+			Expect(testRun.SuiteRuns[1].SpecRuns[1].Status).To(Equal("skipped"))
+			Expect(testRun.SuiteRuns[2].Name).To(Equal("default"))
+		})
+
+		It("should record retry attempts and mark tests that passed on retry as flaky", func() {
+			report := `{"results": {"tool": {"name": "playwright"}, "summary": {"tests": 1, "passed": 1}, "tests": [
+  {"name": "checks out", "status": "passed", "duration": 900, "start": 1709287203000, "retries": 2, "retryAttempts": [
+    {"attempt": 2, "status": "failed", "duration": 1100, "start": 1709287201900, "message": "element detached"},
+    {"attempt": 1, "status": "failed", "duration": 1000, "start": 1709287200900, "message": "timeout", "trace": "at cart.spec.ts:3"}
+  ]}
+]}}`
+
+			testRun, err := application.ParseCTRF(strings.NewReader(report))
+			Expect(err).NotTo(HaveOccurred())
+
+			spec := testRun.SuiteRuns[0].SpecRuns[0]
+			Expect(spec.IsFlaky).To(BeTrue())
+			Expect(spec.RetryCount).To(Equal(2))
+			Expect(spec.Attempts).To(HaveLen(3))
+			Expect(spec.Attempts[0].ErrorMessage).To(Equal("timeout"))
+			Expect(spec.Attempts[0].StackTrace).To(Equal("at cart.spec.ts:3"))
+			Expect(spec.Attempts[0].Duration).To(Equal(time.Second))
+			Expect(spec.Attempts[1].ErrorMessage).To(Equal("element detached"))
+			Expect(spec.Attempts[2].Status).To(Equal("passed"))
+			Expect(spec.Attempts[2].Attempt).To(Equal(3))
+
+			exported := application.ExportCTRF(testRun).Results.Tests[0]
+			Expect(exported.RetryAttempts).To(HaveLen(2))
+			Expect(exported.RetryAttempts[0].Attempt).To(Equal(1))
+			Expect(exported.RetryAttempts[0].Message).To(Equal("timeout"))
+			Expect(exported.RetryAttempts[1].Duration).To(Equal(int64(1100)))
+			Expect(exported.Flaky).To(BeTrue())
+		})
+
+		It("should treat tests with parameters as parameter sets of the test they are named after", func() {
+			report := `{"results": {"tool": {"name": "vitest"}, "summary": {"tests": 1, "passed": 1}, "tests": [
+  {"name": "adds", "status": "passed", "duration": 5, "parameters": {"a": 1, "b": "two", "exact": true}}
+]}}`
+
+			testRun, err := application.ParseCTRF(strings.NewReader(report))
+			Expect(err).NotTo(HaveOccurred())
+
+			spec := testRun.SuiteRuns[0].SpecRuns[0]
+			Expect(spec.ParentTestName()).To(Equal("adds"))
+			Expect(spec.Parameters).To(Equal(map[string]string{"a": "1", "b": "two", "exact": "true"}))
+		})
+
+		It("should reject documents that are not CTRF reports", func() {
+			_, err := application.ParseCTRF(strings.NewReader(`{"testsuites": []}`))
+			Expect(err).To(MatchError(ContainSubstring("missing results")))
+
+			_, err = application.ParseCTRF(strings.NewReader(`<testsuites/>`))
+			Expect(err).To(MatchError(ContainSubstring("invalid ctrf report")))
+		})
+	})
+
+	Describe("ExportCTRF", func() {
+		It("should round-trip counts, durations, statuses, retries and flaky markers", func() {
+			testRun, err := application.ParseCTRF(strings.NewReader(ctrfReport))
+			Expect(err).NotTo(HaveOccurred())
+
+			// Metadata comes back from the database as plain JSON values
+			data, err := json.Marshal(testRun.Metadata)
+			Expect(err).NotTo(HaveOccurred())
+			testRun.Metadata = nil
+			Expect(json.Unmarshal(data, &testRun.Metadata)).To(Succeed())
+
+			exported, err := json.Marshal(application.ExportCTRF(testRun))
+			Expect(err).NotTo(HaveOccurred())
+
+			var original, roundTripped application.CTRFReport
+			Expect(json.Unmarshal([]byte(ctrfReport), &original)).To(Succeed())
+			Expect(json.Unmarshal(exported, &roundTripped)).To(Succeed())
+
+			Expect(roundTripped.Results.Tool).To(Equal(original.Results.Tool))
+			Expect(roundTripped.Results.Summary).To(Equal(original.Results.Summary))
+			Expect(roundTripped.Results.Environment).To(Equal(original.Results.Environment))
+			Expect(roundTripped.Results.Tests).To(HaveLen(len(original.Results.Tests)))
+			for i, test := range original.Results.Tests {
+				got := roundTripped.Results.Tests[i]
+				Expect(got.Name).To(Equal(test.Name))
+				Expect(got.Status).To(Equal(test.Status), test.Name)
+				Expect(got.Duration).To(Equal(test.Duration), test.Name)
+				Expect(got.Suite).To(Equal(test.Suite), test.Name)
+				Expect(got.Retries).To(Equal(test.Retries), test.Name)
+				Expect(got.Flaky).To(Equal(test.Flaky), test.Name)
+				Expect(got.Message).To(Equal(test.Message), test.Name)
+				Expect(got.Trace).To(Equal(test.Trace), test.Name)
+				Expect(got.Tags).To(Equal(test.Tags), test.Name)
+				Expect(got.FilePath).To(Equal(test.FilePath), test.Name)
+				Expect(got.RawStatus).To(Equal(test.RawStatus), test.Name)
+				if test.Start > 0 {
+					Expect(got.Start).To(Equal(test.Start), test.Name)
+					Expect(got.Stop).To(Equal(test.Stop), test.Name)
+				}
+			}
+		})
+
+		It("should export runs imported from other formats", func() {
+			testRun, err := application.ParseJUnitXML(strings.NewReader(`<testsuite name="cart" tests="2" failures="1">
+  <testcase name="adds" classname="cart" time="0.5"/>
+  <testcase name="removes" classname="cart" time="0.25"><failure message="boom">trace</failure></testcase>
+</testsuite>`))
+			Expect(err).NotTo(HaveOccurred())
+
+			report := application.ExportCTRF(testRun)
+			Expect(report.ReportFormat).To(Equal("CTRF"))
+			Expect(report.Results.Tool.Name).To(Equal("junit"))
+			Expect(report.Results.Summary.Tests).To(Equal(2))
+			Expect(report.Results.Summary.Failed).To(Equal(1))
+			Expect(report.Results.Tests[0].Suite).To(Equal(application.CTRFSuite("cart")))
+			Expect(report.Results.Tests[0].Duration).To(Equal(int64(500)))
+			Expect(report.Results.Tests[1].Message).To(Equal("boom"))
+		})
+	})
+})
@@ -0,0 +1,325 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// cucumberFeature is a feature in the Cucumber JSON report written by cucumber-jvm,
+// cucumber-js, cucumber-ruby, behave and godog
+type cucumberFeature struct {
+	URI      string            `json:"uri"`
+	ID       string            `json:"id"`
+	Keyword  string            `json:"keyword"`
+	Name     string            `json:"name"`
+	Tags     []cucumberTag     `json:"tags"`
+	Elements []cucumberElement `json:"elements"`
+}
+
+// cucumberElement is a scenario or a background. Scenario outlines are reported already
+// expanded, as one scenario per example row.
+type cucumberElement struct {
+	ID             string         `json:"id"`
+	Keyword        string         `json:"keyword"`
+	Type           string         `json:"type"`
+	Name           string         `json:"name"`
+	Line           int            `json:"line"`
+	StartTimestamp string         `json:"start_timestamp"`
+	Tags           []cucumberTag  `json:"tags"`
+	Before         []cucumberStep `json:"before"`
+	Steps          []cucumberStep `json:"steps"`
+	After          []cucumberStep `json:"after"`
+}
+
+// cucumberStep is a step or a hook
+type cucumberStep struct {
+	Keyword string         `json:"keyword"`
+	Name    string         `json:"name"`
+	Line    int            `json:"line"`
+	Result  cucumberResult `json:"result"`
+	Match   struct {
+		Location string `json:"location"`
+	} `json:"match"`
+	Output []string `json:"output"`
+}
+
+// cucumberResult is the outcome of a step or hook; durations are in nanoseconds
+type cucumberResult struct {
+	Status       string `json:"status"`
+	Duration     int64  `json:"duration"`
+	ErrorMessage string `json:"error_message"`
+}
+
+// cucumberTag is a Gherkin tag such as @smoke
+type cucumberTag struct {
+	Name string `json:"name"`
+}
+
+// cucumberOutlineKeywords are the keywords Cucumber reports on the expanded rows of an outline
+var cucumberOutlineKeywords = map[string]bool{
+	"scenario outline":  true,
+	"scenario template": true,
+}
+
+// ParseCucumberJSON converts a Cucumber JSON report into a test run hierarchy: each feature
+// becomes a suite run and each scenario a spec run whose steps are kept in order. Background
+// steps are attached to every scenario that follows them, and each example row of a scenario
+// outline becomes its own spec. The returned test run has no project or run ID assigned.
+func ParseCucumberJSON(r io.Reader) (*domain.TestRun, error) {
+	var features []cucumberFeature
+	if err := json.NewDecoder(r).Decode(&features); err != nil {
+		return nil, fmt.Errorf("invalid cucumber report: %w", err)
+	}
+
+	testRun := &domain.TestRun{Source: ReportFormatCucumber}
+	featureMetadata := make([]map[string]interface{}, 0, len(features))
+	specMetadata := make(map[string]interface{})
+	outlines := make(map[string]interface{})
+
+	var endTime time.Time
+	for _, feature := range features {
+		suiteRun := feature.toSuiteRun(specMetadata, outlines)
+		testRun.SuiteRuns = append(testRun.SuiteRuns, suiteRun)
+
+		meta := map[string]interface{}{
+			"name": feature.Name,
+			"uri":  feature.URI,
+		}
+		if tags := cucumberTagNames(feature.Tags); len(tags) > 0 {
+			meta["tags"] = tags
+		}
+		featureMetadata = append(featureMetadata, meta)
+
+		if !suiteRun.StartTime.IsZero() && (testRun.StartTime.IsZero() || suiteRun.StartTime.Before(testRun.StartTime)) {
+			testRun.StartTime = suiteRun.StartTime
+		}
+		if suiteRun.EndTime != nil && suiteRun.EndTime.After(endTime) {
+			endTime = *suiteRun.EndTime
+		}
+
+		testRun.TotalTests += suiteRun.TotalTests
+		testRun.PassedTests += suiteRun.PassedTests
+		testRun.FailedTests += suiteRun.FailedTests
+		testRun.SkippedTests += suiteRun.SkippedTests
+		testRun.Duration += suiteRun.Duration
+	}
+
+	if !endTime.IsZero() {
+		testRun.EndTime = &endTime
+		testRun.Duration = endTime.Sub(testRun.StartTime)
+	}
+
+	testRun.Status = "passed"
+	if testRun.FailedTests > 0 {
+		testRun.Status = "failed"
+	}
+
+	cucumber := map[string]interface{}{
+		"features": featureMetadata,
+	}
+	if len(specMetadata) > 0 {
+		cucumber["specs"] = specMetadata
+	}
+	if len(outlines) > 0 {
+		cucumber["outlines"] = outlines
+	}
+	testRun.Metadata = map[string]interface{}{
+		"source_format": ReportFormatCucumber,
+		"cucumber":      cucumber,
+	}
+
+	return testRun, nil
+}
+
+// toSuiteRun converts the feature's scenarios, recording tags into specMetadata
+// keyed by "<feature>/<scenario>" and the rows of each outline into outlines
+func (f cucumberFeature) toSuiteRun(specMetadata, outlines map[string]interface{}) domain.SuiteRun {
+	suiteRun := domain.SuiteRun{
+		Name:        f.Name,
+		PackageName: f.URI,
+		Status:      "passed",
+	}
+
+	var background []cucumberStep
+	rows := make(map[string]int)
+	var endTime time.Time
+	for _, element := range f.Elements {
+		if element.Type == "background" {
+			background = element.Steps
+			continue
+		}
+
+		name := element.Name
+		outline := cucumberOutlineKeywords[strings.ToLower(strings.TrimSpace(element.Keyword))]
+		if outline {
+			rows[element.Name]++
+			name = fmt.Sprintf("%s [example %d]", element.Name, rows[element.Name])
+		}
+
+		spec := element.toSpecRun(name, f.Name, background)
+		if outline {
+			spec.ParentName = element.Name
+			spec.Parameters = map[string]string{"example": strconv.Itoa(rows[element.Name])}
+		}
+		appendSpecRun(&suiteRun, spec)
+		suiteRun.Duration += spec.Duration
+
+		if !spec.StartTime.IsZero() && (suiteRun.StartTime.IsZero() || spec.StartTime.Before(suiteRun.StartTime)) {
+			suiteRun.StartTime = spec.StartTime
+		}
+		if spec.EndTime != nil && spec.EndTime.After(endTime) {
+			endTime = *spec.EndTime
+		}
+
+		key := f.Name + "/" + name
+		meta := map[string]interface{}{"line": element.Line}
+		if tags := cucumberTagNames(element.Tags); len(tags) > 0 {
+			meta["tags"] = tags
+		}
+		specMetadata[key] = meta
+
+		if outline {
+			outlineKey := f.Name + "/" + element.Name
+			specs, _ := outlines[outlineKey].([]string)
+			outlines[outlineKey] = append(specs, key)
+		}
+	}
+
+	if !suiteRun.StartTime.IsZero() && endTime.After(suiteRun.StartTime) {
+		suiteRun.Duration = endTime.Sub(suiteRun.StartTime)
+		suiteRun.EndTime = &endTime
+	}
+
+	return suiteRun
+}
+
+// toSpecRun converts a scenario, preceded by the background steps that ran before it.
+// Hooks are only recorded as steps when they did not pass, since they otherwise add noise.
+func (e cucumberElement) toSpecRun(name, feature string, background []cucumberStep) *domain.SpecRun {
+	spec := &domain.SpecRun{
+		Name:      name,
+		ClassName: feature,
+		StartTime: parseJUnitTimestamp(e.StartTimestamp),
+	}
+
+	var output []string
+	addSteps := func(steps []cucumberStep, hook string) {
+		for _, s := range steps {
+			status := cucumberStepStatus(s.Result.Status)
+			duration := time.Duration(s.Result.Duration)
+			spec.Duration += duration
+			output = append(output, s.Output...)
+
+			if hook != "" && status == "passed" {
+				continue
+			}
+			step := domain.SpecStep{
+				Keyword:      strings.TrimSpace(s.Keyword),
+				Name:         s.Name,
+				Status:       status,
+				Duration:     duration,
+				ErrorMessage: strings.TrimSpace(s.Result.ErrorMessage),
+			}
+			if hook != "" {
+				step.Keyword = hook
+				step.Name = s.Match.Location
+			}
+			spec.Steps = append(spec.Steps, step)
+		}
+	}
+	addSteps(e.Before, "Before")
+	addSteps(background, "")
+	addSteps(e.Steps, "")
+	addSteps(e.After, "After")
+
+	// A scenario takes the status of its worst step, as Cucumber reports it
+	spec.Status = "passed"
+	if len(spec.Steps) == 0 {
+		spec.Status = "skipped"
+	}
+	for _, step := range spec.Steps {
+		switch step.Status {
+		case "failed", "undefined", "pending", "ambiguous":
+			if spec.Status != "failed" {
+				spec.Status = "failed"
+				spec.ErrorMessage = cucumberErrorMessage(step)
+				spec.FailureMessage = spec.ErrorMessage
+				spec.StackTrace = step.ErrorMessage
+			}
+		case "skipped":
+			if spec.Status == "passed" {
+				spec.Status = "skipped"
+			}
+		}
+	}
+
+	if !spec.StartTime.IsZero() {
+		endTime := spec.StartTime.Add(spec.Duration)
+		spec.EndTime = &endTime
+	}
+
+	spec.Output = domain.NewSpecOutput(strings.Join(output, "\n"), "")
+
+	return spec
+}
+
+// cucumberStepStatus normalises step statuses, which some reporters capitalise
+func cucumberStepStatus(status string) string {
+	status = strings.ToLower(strings.TrimSpace(status))
+	if status == "" {
+		return "skipped"
+	}
+	return status
+}
+
+// cucumberErrorMessage describes why a scenario failed, naming the step that broke.
+// Undefined and pending steps fail a scenario as they do in Cucumber's strict mode.
+func cucumberErrorMessage(step domain.SpecStep) string {
+	location := strings.TrimSpace(step.Keyword + " " + step.Name)
+	switch step.Status {
+	case "undefined":
+		return fmt.Sprintf("step is undefined: %s", location)
+	case "pending":
+		return fmt.Sprintf("step is pending: %s", location)
+	case "ambiguous":
+		return fmt.Sprintf("step is ambiguous: %s", location)
+	}
+
+	message := step.ErrorMessage
+	if i := strings.IndexByte(message, '\n'); i >= 0 {
+		message = message[:i]
+	}
+	if message = strings.TrimSpace(message); message == "" {
+		return fmt.Sprintf("step failed: %s", location)
+	}
+	return fmt.Sprintf("%s: %s", location, message)
+}
+
+func cucumberTagNames(tags []cucumberTag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+// ImportCucumberJSON parses a Cucumber JSON report and records it as a new test run
+func (s *TestRunService) ImportCucumberJSON(ctx context.Context, r io.Reader, opts ImportOptions) (*domain.TestRun, error) {
+	testRun, err := ParseCucumberJSON(r)
+	if err != nil {
+		return nil, &InvalidReportError{Err: err}
+	}
+
+	if err := s.importTestRun(ctx, testRun, opts); err != nil {
+		return nil, err
+	}
+
+	return testRun, nil
+}
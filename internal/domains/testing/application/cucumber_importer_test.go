@@ -0,0 +1,195 @@
+package application_test
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+const cucumberReport = `[
+  {
+    "uri": "features/checkout.feature",
+    "id": "checkout",
+    "keyword": "Feature",
+    "name": "Checkout",
+    "tags": [{"name": "@payments"}],
+    "elements": [
+      {
+        "keyword": "Background",
+        "type": "background",
+        "name": "",
+        "steps": [
+          {"keyword": "Given ", "name": "a signed in customer", "result": {"status": "passed", "duration": 100000000}}
+        ]
+      },
+      {
+        "id": "checkout;pays-by-card",
+        "keyword": "Scenario",
+        "type": "scenario",
+        "name": "Pays by card",
+        "line": 6,
+        "start_timestamp": "2024-03-01T10:00:00.000Z",
+        "tags": [{"name": "@smoke"}],
+        "steps": [
+          {"keyword": "When ", "name": "they pay by card", "result": {"status": "passed", "duration": 200000000}, "output": ["charged 10.00"]},
+          {"keyword": "Then ", "name": "the order is confirmed", "result": {"status": "failed", "duration": 50000000, "error_message": "expected confirmed, got pending\n\tat steps.js:12"}}
+        ],
+        "after": [
+          {"match": {"location": "hooks.js:4"}, "result": {"status": "passed", "duration": 1000000}}
+        ]
+      },
+      {
+        "keyword": "Scenario Outline",
+        "type": "scenario",
+        "name": "Applies a discount",
+        "line": 12,
+        "steps": [
+          {"keyword": "When ", "name": "they apply \"SAVE10\"", "result": {"status": "passed", "duration": 10000000}}
+        ]
+      },
+      {
+        "keyword": "Scenario Outline",
+        "type": "scenario",
+        "name": "Applies a discount",
+        "line": 13,
+        "steps": [
+          {"keyword": "When ", "name": "they apply \"BOGUS\"", "result": {"status": "undefined"}}
+        ]
+      },
+      {
+        "keyword": "Scenario",
+        "type": "scenario",
+        "name": "Pays by voucher",
+        "line": 20,
+        "before": [
+          {"match": {"location": "hooks.js:1"}, "result": {"status": "skipped"}}
+        ],
+        "steps": [
+          {"keyword": "When ", "name": "they pay by voucher", "result": {"status": "skipped"}}
+        ]
+      }
+    ]
+  }
+]`
+
+var _ = Describe("Cucumber import", Label("unit", "application", "testing"), func() {
+	Describe("ParseCucumberJSON", func() {
+		It("should map features to suites and scenarios to specs", func() {
+			testRun, err := application.ParseCucumberJSON(strings.NewReader(cucumberReport))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(testRun.Source).To(Equal("cucumber"))
+			Expect(testRun.Status).To(Equal("failed"))
+			Expect(testRun.TotalTests).To(Equal(4))
+			Expect(testRun.PassedTests).To(Equal(1))
+			Expect(testRun.FailedTests).To(Equal(2))
+			Expect(testRun.SkippedTests).To(Equal(1))
+			Expect(testRun.StartTime).To(Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)))
+			Expect(testRun.SuiteRuns).To(HaveLen(1))
+
+			checkout := testRun.SuiteRuns[0]
+			Expect(checkout.Name).To(Equal("Checkout"))
+			Expect(checkout.PackageName).To(Equal("features/checkout.feature"))
+			Expect(checkout.Status).To(Equal("failed"))
+			Expect(checkout.SpecRuns).To(HaveLen(4))
+			Expect(checkout.SpecRuns[0].ClassName).To(Equal("Checkout"))
+			Expect(checkout.SpecRuns[3].Status).To(Equal("skipped"))
+		})
+
+		It("should keep the steps of each scenario, including the background", func() {
+			testRun, err := application.ParseCucumberJSON(strings.NewReader(cucumberReport))
+			Expect(err).NotTo(HaveOccurred())
+
+			card := testRun.SuiteRuns[0].SpecRuns[0]
+			Expect(card.Name).To(Equal("Pays by card"))
+			Expect(card.Status).To(Equal("failed"))
+			Expect(card.Duration).To(Equal(351 * time.Millisecond))
+			Expect(card.ErrorMessage).To(Equal("Then the order is confirmed: expected confirmed, got pending"))
+			Expect(card.StackTrace).To(ContainSubstring("steps.js:12"))
+
+			Expect(card.Steps).To(HaveLen(3))
+			Expect(card.Steps[0].Keyword).To(Equal("Given"))
+			Expect(card.Steps[0].Name).To(Equal("a signed in customer"))
+			Expect(card.Steps[0].Duration).To(Equal(100 * time.Millisecond))
+			Expect(card.Steps[2].Status).To(Equal("failed"))
+			Expect(card.Steps[2].ErrorMessage).To(HavePrefix("expected confirmed, got pending"))
+		})
+
+		It("should record hooks only when they did not pass", func() {
+			testRun, err := application.ParseCucumberJSON(strings.NewReader(cucumberReport))
+			Expect(err).NotTo(HaveOccurred())
+
+			voucher := testRun.SuiteRuns[0].SpecRuns[3]
+			Expect(voucher.Steps).To(HaveLen(3))
+			Expect(voucher.Steps[0].Keyword).To(Equal("Before"))
+			Expect(voucher.Steps[0].Name).To(Equal("hooks.js:1"))
+		})
+
+		It("should expand scenario outlines into one spec per example row", func() {
+			testRun, err := application.ParseCucumberJSON(strings.NewReader(cucumberReport))
+			Expect(err).NotTo(HaveOccurred())
+
+			specs := testRun.SuiteRuns[0].SpecRuns
+			Expect(specs[1].Name).To(Equal("Applies a discount [example 1]"))
+			Expect(specs[1].Status).To(Equal("passed"))
+			Expect(specs[2].Name).To(Equal("Applies a discount [example 2]"))
+			Expect(specs[2].Status).To(Equal("failed"))
+			Expect(specs[2].ErrorMessage).To(Equal(`step is undefined: When they apply "BOGUS"`))
+			Expect(specs[2].ParentName).To(Equal("Applies a discount"))
+			Expect(specs[2].Parameters).To(Equal(map[string]string{"example": "2"}))
+			Expect(specs[0].ParentName).To(BeEmpty())
+
+			cucumber := testRun.Metadata["cucumber"].(map[string]interface{})
+			Expect(cucumber["outlines"]).To(Equal(map[string]interface{}{
+				"Checkout/Applies a discount": []string{
+					"Checkout/Applies a discount [example 1]",
+					"Checkout/Applies a discount [example 2]",
+				},
+			}))
+		})
+
+		It("should keep tags in metadata and capture step output", func() {
+			testRun, err := application.ParseCucumberJSON(strings.NewReader(cucumberReport))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(testRun.Metadata).To(HaveKeyWithValue("source_format", "cucumber"))
+			cucumber := testRun.Metadata["cucumber"].(map[string]interface{})
+			specs := cucumber["specs"].(map[string]interface{})
+			Expect(specs["Checkout/Pays by card"]).To(HaveKeyWithValue("tags", []string{"@smoke"}))
+			Expect(specs["Checkout/Pays by card"]).NotTo(HaveKey("output"))
+			Expect(testRun.SuiteRuns[0].SpecRuns[0].Output.Stdout).To(Equal("charged 10.00"))
+			Expect(cucumber["features"]).To(ContainElement(HaveKeyWithValue("tags", []string{"@payments"})))
+		})
+
+		It("should reject documents that are not Cucumber JSON reports", func() {
+			_, err := application.ParseCucumberJSON(strings.NewReader(`{"testsuites": []}`))
+			Expect(err).To(MatchError(ContainSubstring("invalid cucumber report")))
+		})
+	})
+
+	Describe("ImportReport", func() {
+		It("should persist scenarios with their steps", func() {
+			mockTestRunRepo := new(MockTestRunRepository)
+			service := application.NewTestRunService(mockTestRunRepo, new(MockSuiteRunRepository), new(MockSpecRunRepository))
+			ctx := context.Background()
+
+			mockTestRunRepo.On("CreateWithHierarchy", ctx, mock.MatchedBy(func(tr *domain.TestRun) bool {
+				return tr.ProjectID == "proj-123" && tr.TotalTests == 4 && len(tr.SuiteRuns[0].SpecRuns[0].Steps) == 3
+			}), []string(nil)).Return(nil)
+
+			Expect(application.IsSupportedReportFormat(application.ReportFormatCucumber)).To(BeTrue())
+			_, err := service.ImportReport(ctx, application.ReportFormatCucumber, strings.NewReader(cucumberReport), application.ImportOptions{
+				ProjectID: "proj-123",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			mockTestRunRepo.AssertExpectations(GinkgoT())
+		})
+	})
+})
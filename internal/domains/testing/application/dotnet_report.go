@@ -0,0 +1,251 @@
+package application
+
+import (
+	"strings"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// dotnetTestResult is one executed test case from a TRX, NUnit or xUnit report,
+// normalised before it is grouped into suites
+type dotnetTestResult struct {
+	assembly string // assembly the test lives in, used as the suite's package
+	class    string // fully qualified class name, used as the suite name
+	spec     *domain.SpecRun
+
+	// identity is "<class>.<method>", shared by every row of a parameterised test
+	identity      string
+	arguments     string
+	parameterized bool
+
+	meta map[string]interface{}
+}
+
+// dotnetTimestampLayouts lists the timestamp formats used by .NET reporters: TRX uses
+// round-trip ("o") timestamps with an offset, NUnit the universal sortable ("u") format
+var dotnetTimestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+}
+
+// newDotnetTestRun groups results into one suite run per class, in the order the classes
+// first appear, and records per-spec extras under metadata[format]. startTime is the run's
+// reported start, if any; tests without timestamps are laid out sequentially from it.
+func newDotnetTestRun(format, name string, startTime time.Time, results []dotnetTestResult, runMetadata map[string]interface{}) *domain.TestRun {
+	testRun := &domain.TestRun{
+		Name:      name,
+		Source:    format,
+		StartTime: startTime,
+	}
+
+	suiteIndex := make(map[string]int)
+	specMetadata := make(map[string]interface{})
+	parameterized := make(map[string]interface{})
+	for _, result := range results {
+		key := result.assembly + "\x00" + result.class
+		i, ok := suiteIndex[key]
+		if !ok {
+			i = len(testRun.SuiteRuns)
+			suiteIndex[key] = i
+			testRun.SuiteRuns = append(testRun.SuiteRuns, domain.SuiteRun{
+				Name:        result.class,
+				PackageName: result.assembly,
+				ClassName:   result.class,
+				Status:      "passed",
+			})
+		}
+		appendSpecRun(&testRun.SuiteRuns[i], result.spec)
+
+		specKey := result.class + "/" + result.spec.Name
+		meta := map[string]interface{}{"test_identity": result.identity}
+		for k, v := range result.meta {
+			meta[k] = v
+		}
+		if result.arguments != "" {
+			meta["arguments"] = result.arguments
+		}
+		specMetadata[specKey] = meta
+
+		// Rows of a data-driven test are separate specs; the shared identity ties them together
+		if result.parameterized {
+			result.spec.ParentName = dotnetShortName(result.identity, result.class)
+			result.spec.Parameters = parseArgumentList(result.arguments)
+			rows, _ := parameterized[result.identity].([]string)
+			parameterized[result.identity] = append(rows, specKey)
+		}
+	}
+
+	var endTime time.Time
+	offset := startTime
+	for i := range testRun.SuiteRuns {
+		suite := &testRun.SuiteRuns[i]
+		summariseSuiteTimes(suite)
+		if !suite.StartTime.IsZero() || !offset.IsZero() {
+			backfillSuiteTimes(suite, offset)
+		}
+		if !suite.StartTime.IsZero() {
+			offset = suite.StartTime.Add(suite.Duration)
+		}
+
+		if !suite.StartTime.IsZero() && (testRun.StartTime.IsZero() || suite.StartTime.Before(testRun.StartTime)) {
+			testRun.StartTime = suite.StartTime
+		}
+		if suite.EndTime != nil && suite.EndTime.After(endTime) {
+			endTime = *suite.EndTime
+		}
+
+		testRun.TotalTests += suite.TotalTests
+		testRun.PassedTests += suite.PassedTests
+		testRun.FailedTests += suite.FailedTests
+		testRun.SkippedTests += suite.SkippedTests
+		testRun.Duration += suite.Duration
+	}
+
+	if !endTime.IsZero() {
+		testRun.EndTime = &endTime
+		testRun.Duration = endTime.Sub(testRun.StartTime)
+	}
+
+	testRun.Status = "passed"
+	if testRun.FailedTests > 0 {
+		testRun.Status = "failed"
+	}
+
+	if runMetadata == nil {
+		runMetadata = make(map[string]interface{})
+	}
+	if len(specMetadata) > 0 {
+		runMetadata["specs"] = specMetadata
+	}
+	if len(parameterized) > 0 {
+		runMetadata["parameterized_tests"] = parameterized
+	}
+	testRun.Metadata = map[string]interface{}{
+		"source_format": format,
+		format:          runMetadata,
+	}
+
+	return testRun
+}
+
+// newDotnetSpecRun creates a spec run from a result's timing; startTime and endTime may be zero
+func newDotnetSpecRun(name, class, outcome string, startTime, endTime time.Time, duration time.Duration) *domain.SpecRun {
+	spec := &domain.SpecRun{
+		Name:      name,
+		ClassName: class,
+		Status:    dotnetStatus(outcome),
+		StartTime: startTime,
+		Duration:  duration,
+	}
+	if duration == 0 && !startTime.IsZero() && endTime.After(startTime) {
+		spec.Duration = endTime.Sub(startTime)
+	}
+	if !startTime.IsZero() {
+		end := startTime.Add(spec.Duration)
+		spec.EndTime = &end
+	}
+	return spec
+}
+
+// setDotnetFailure records a failure's message and stack trace on a failed spec
+func setDotnetFailure(spec *domain.SpecRun, message, stackTrace, fallback string) {
+	if spec.Status != "failed" {
+		return
+	}
+	spec.ErrorMessage = strings.TrimSpace(message)
+	if spec.ErrorMessage == "" {
+		spec.ErrorMessage = fallback
+	}
+	if spec.ErrorMessage == "" {
+		spec.ErrorMessage = "test failed"
+	}
+	spec.FailureMessage = spec.ErrorMessage
+	spec.StackTrace = strings.TrimSpace(stackTrace)
+}
+
+// dotnetStatus normalises the outcome vocabularies of TRX, NUnit and xUnit onto spec
+// statuses. Outcomes that say nothing about whether the code works (Inconclusive,
+// NotExecuted, Ignored, ...) count as skipped; anything unrecognised counts as failed.
+func dotnetStatus(outcome string) string {
+	switch strings.ToLower(strings.TrimSpace(outcome)) {
+	case "passed", "pass", "success", "completed", "warning", "passedbutrunaborted":
+		return "passed"
+	case "skipped", "skip", "notrun", "notexecuted", "notrunnable", "inconclusive",
+		"ignored", "explicit", "pending":
+		return "skipped"
+	default:
+		// failed, fail, error, timeout, aborted, disconnected, inprogress, cancelled, invalid
+		return "failed"
+	}
+}
+
+// isPlainDotnetOutcome reports whether an outcome is a plain pass/fail/skip, which
+// needs no record beyond the spec status
+func isPlainDotnetOutcome(outcome string) bool {
+	switch strings.ToLower(strings.TrimSpace(outcome)) {
+	case "passed", "pass", "failed", "fail", "skipped", "skip":
+		return true
+	default:
+		return false
+	}
+}
+
+// dotnetTestIdentity returns the identity shared by all rows of a test method
+func dotnetTestIdentity(class, method string) string {
+	if class == "" {
+		return method
+	}
+	return class + "." + method
+}
+
+// dotnetArguments extracts the argument list from a parameterised test name such as
+// "Adds(1, 2)" or "Acme.CartTests.Adds (1, 2)", given the class and method it belongs to
+func dotnetArguments(name, class, method string) (string, bool) {
+	short := dotnetShortName(name, class)
+	if method == "" || !strings.HasPrefix(short, method) {
+		return "", false
+	}
+	rest := strings.TrimSpace(short[len(method):])
+	if !strings.HasPrefix(rest, "(") || !strings.HasSuffix(rest, ")") {
+		return "", false
+	}
+	return rest[1 : len(rest)-1], true
+}
+
+// dotnetShortName strips the class prefix that xUnit and vstest put on test names
+func dotnetShortName(name, class string) string {
+	if class != "" && strings.HasPrefix(name, class+".") {
+		return name[len(class)+1:]
+	}
+	return name
+}
+
+// dotnetAssemblyName returns the file name of an assembly path without its extension
+func dotnetAssemblyName(path string) string {
+	if i := strings.LastIndexAny(path, `/\`); i >= 0 {
+		path = path[i+1:]
+	}
+	for _, ext := range []string{".dll", ".exe"} {
+		if strings.HasSuffix(strings.ToLower(path), ext) {
+			return path[:len(path)-len(ext)]
+		}
+	}
+	return path
+}
+
+// parseDotnetTimestamp parses a .NET report timestamp, returning the zero time when absent or malformed
+func parseDotnetTimestamp(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	for _, layout := range dotnetTimestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,292 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// legacyBackfillBatchSize is the number of runs loaded per backfill query
+const legacyBackfillBatchSize = 100
+
+// FernSuiteRun is a suite run as sent by fern-ginkgo-client
+type FernSuiteRun struct {
+	ID        uint64        `json:"id"`
+	TestRunID uint64        `json:"test_run_id"`
+	SuiteName string        `json:"suite_name"`
+	StartTime string        `json:"start_time"`
+	EndTime   string        `json:"end_time"`
+	SpecRuns  []FernSpecRun `json:"spec_runs"`
+}
+
+// FernSpecRun is a spec run as sent by fern-ginkgo-client
+type FernSpecRun struct {
+	ID              uint64    `json:"id"`
+	SuiteID         uint64    `json:"suite_id"`
+	SpecDescription string    `json:"spec_description"`
+	Status          string    `json:"status"`
+	Message         string    `json:"message"`
+	Tags            []FernTag `json:"tags"`
+	StartTime       string    `json:"start_time"`
+	EndTime         string    `json:"end_time"`
+	// Output captured by Ginkgo: CapturedGinkgoWriterOutput and CapturedStdOutErr
+	Stdout string `json:"stdout,omitempty"`
+	Stderr string `json:"stderr,omitempty"`
+	// Every attempt of a spec retried with FlakeAttempts, oldest first
+	Attempts []FernSpecAttempt `json:"attempts,omitempty"`
+	// Text of the DescribeTable a spec is an Entry of, and the entry's parameters
+	TableDescription string            `json:"table_description,omitempty"`
+	Parameters       map[string]string `json:"parameters,omitempty"`
+}
+
+// FernSpecAttempt is one attempt of a spec retried by Ginkgo
+type FernSpecAttempt struct {
+	Status    string `json:"status"`
+	Message   string `json:"message"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+// FernTag is a spec label as sent by fern-ginkgo-client
+type FernTag struct {
+	ID   uint64 `json:"id"`
+	Name string `json:"name"`
+}
+
+// ConvertFernSuiteRuns maps fern-ginkgo-client suites to domain suite runs. It
+// returns the distinct tag names found on their specs, which tag the run, and the
+// labels of each spec keyed "<suite>/<spec>", which stay in the run's metadata.
+func ConvertFernSuiteRuns(fernSuites []FernSuiteRun) ([]*domain.SuiteRun, []string, map[string]interface{}) {
+	suites := make([]*domain.SuiteRun, 0, len(fernSuites))
+	var tagNames []string
+	seenTags := make(map[string]bool)
+	specMetadata := make(map[string]interface{})
+
+	for _, fernSuite := range fernSuites {
+		suite := &domain.SuiteRun{
+			Name:     fernSuite.SuiteName,
+			Status:   "passed",
+			SpecRuns: make([]*domain.SpecRun, 0, len(fernSuite.SpecRuns)),
+		}
+		suite.StartTime, suite.EndTime, suite.Duration = parseFernTimes(fernSuite.StartTime, fernSuite.EndTime)
+
+		for _, fernSpec := range fernSuite.SpecRuns {
+			spec := &domain.SpecRun{
+				Name:       fernSpec.SpecDescription,
+				Status:     fernSpecStatus(fernSpec.Status),
+				ParentName: fernSpec.TableDescription,
+				Parameters: fernSpec.Parameters,
+				Output:     domain.NewSpecOutput(fernSpec.Stdout, fernSpec.Stderr),
+			}
+			// An entry without parameters is told apart by its own text
+			if entry := strings.TrimSpace(strings.TrimPrefix(spec.Name, spec.ParentName)); spec.ParentName != "" && len(spec.Parameters) == 0 && entry != "" {
+				spec.Parameters = map[string]string{"entry": entry}
+			}
+			spec.StartTime, spec.EndTime, spec.Duration = parseFernTimes(fernSpec.StartTime, fernSpec.EndTime)
+			if spec.Status == "failed" {
+				spec.ErrorMessage = fernSpec.Message
+			}
+			for _, fernAttempt := range fernSpec.Attempts {
+				attempt := domain.SpecAttempt{Status: fernSpecStatus(fernAttempt.Status)}
+				attempt.StartTime, _, attempt.Duration = parseFernTimes(fernAttempt.StartTime, fernAttempt.EndTime)
+				if attempt.Status == "failed" {
+					attempt.ErrorMessage = fernAttempt.Message
+				}
+				spec.Attempts = append(spec.Attempts, attempt)
+			}
+			appendSpecRun(suite, spec)
+
+			var labels []string
+			for _, tag := range fernSpec.Tags {
+				label := strings.TrimSpace(tag.Name)
+				if label == "" {
+					continue
+				}
+				labels = append(labels, label)
+
+				name := strings.ToLower(label)
+				if seenTags[name] {
+					continue
+				}
+				seenTags[name] = true
+				tagNames = append(tagNames, name)
+			}
+			if len(labels) > 0 {
+				specMetadata[suite.Name+"/"+spec.Name] = map[string]interface{}{"labels": labels}
+			}
+		}
+
+		suites = append(suites, suite)
+	}
+
+	return suites, tagNames, specMetadata
+}
+
+// addFernSpecMetadata keeps the labels of each spec under metadata["ginkgo"]["specs"],
+// as spec runs have no metadata of their own
+func addFernSpecMetadata(testRun *domain.TestRun, specMetadata map[string]interface{}) {
+	if len(specMetadata) == 0 {
+		return
+	}
+	if testRun.Metadata == nil {
+		testRun.Metadata = make(map[string]interface{})
+	}
+	testRun.Metadata["ginkgo"] = map[string]interface{}{"specs": specMetadata}
+}
+
+// IngestFernTestRun records a new test run together with the suites, specs and
+// spec tags of a fern-ginkgo-client report in a single transaction
+func (s *TestRunService) IngestFernTestRun(ctx context.Context, testRun *domain.TestRun, fernSuites []FernSuiteRun) error {
+	suites, tagNames, specMetadata := ConvertFernSuiteRuns(fernSuites)
+	addFernSpecMetadata(testRun, specMetadata)
+
+	testRun.SuiteRuns = make([]domain.SuiteRun, len(suites))
+	for i, suite := range suites {
+		testRun.SuiteRuns[i] = *suite
+	}
+
+	return s.IngestTestRun(ctx, testRun, tagNames)
+}
+
+// ImportFernSuiteRuns persists the suites, specs and spec tags of a
+// fern-ginkgo-client report under an existing test run. The labels of each spec
+// are added to the run's metadata, which the caller saves.
+func (s *TestRunService) ImportFernSuiteRuns(ctx context.Context, testRun *domain.TestRun, fernSuites []FernSuiteRun) error {
+	testRunID := testRun.ID
+	suites, tagNames, specMetadata := ConvertFernSuiteRuns(fernSuites)
+	addFernSpecMetadata(testRun, specMetadata)
+
+	if err := s.AddSuiteRunsWithSpecs(ctx, testRunID, suites); err != nil {
+		return err
+	}
+
+	if len(tagNames) > 0 {
+		if err := s.testRunRepo.AddTags(ctx, testRunID, tagNames); err != nil {
+			return fmt.Errorf("failed to tag test run: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// AddSuiteRunsWithSpecs persists suites and their specs for an existing test run
+// using one batch insert for the suites and one for the specs
+func (s *TestRunService) AddSuiteRunsWithSpecs(ctx context.Context, testRunID uint, suites []*domain.SuiteRun) error {
+	if len(suites) == 0 {
+		return nil
+	}
+
+	for _, suite := range suites {
+		suite.TestRunID = testRunID
+	}
+	if err := s.suiteRunRepo.CreateBatch(ctx, suites); err != nil {
+		return fmt.Errorf("failed to create suite runs: %w", err)
+	}
+
+	var specs []*domain.SpecRun
+	for _, suite := range suites {
+		for _, spec := range suite.SpecRuns {
+			spec.SuiteRunID = suite.ID
+			specs = append(specs, spec)
+		}
+	}
+	if len(specs) == 0 {
+		return nil
+	}
+
+	s.prepareSpecRuns(specs...)
+	if err := s.specRunRepo.CreateBatch(ctx, specs); err != nil {
+		return fmt.Errorf("failed to create spec runs: %w", err)
+	}
+
+	return nil
+}
+
+// BackfillLegacySuiteRuns persists the hierarchy of legacy runs that only kept their
+// suites in metadata["suite_runs"]. It returns the number of runs backfilled.
+func (s *TestRunService) BackfillLegacySuiteRuns(ctx context.Context) (int, error) {
+	backfilled := 0
+	var afterID uint
+
+	for {
+		testRuns, err := s.testRunRepo.FindWithUnpersistedSuiteRuns(ctx, afterID, legacyBackfillBatchSize)
+		if err != nil {
+			return backfilled, fmt.Errorf("failed to find legacy test runs: %w", err)
+		}
+		if len(testRuns) == 0 {
+			return backfilled, nil
+		}
+
+		for _, testRun := range testRuns {
+			afterID = testRun.ID
+
+			fernSuites, err := decodeFernSuiteRuns(testRun.Metadata["suite_runs"])
+			if err != nil {
+				return backfilled, fmt.Errorf("failed to decode suite runs of test run %d: %w", testRun.ID, err)
+			}
+			if len(fernSuites) == 0 {
+				continue
+			}
+
+			if err := s.ImportFernSuiteRuns(ctx, testRun, fernSuites); err != nil {
+				return backfilled, fmt.Errorf("failed to backfill test run %d: %w", testRun.ID, err)
+			}
+
+			delete(testRun.Metadata, "suite_runs")
+			if err := s.testRunRepo.Update(ctx, testRun); err != nil {
+				return backfilled, fmt.Errorf("failed to update test run %d: %w", testRun.ID, err)
+			}
+			backfilled++
+		}
+	}
+}
+
+// decodeFernSuiteRuns converts suites stored as generic JSON back into their typed form
+func decodeFernSuiteRuns(raw interface{}) ([]FernSuiteRun, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var fernSuites []FernSuiteRun
+	if err := json.Unmarshal(data, &fernSuites); err != nil {
+		return nil, err
+	}
+	return fernSuites, nil
+}
+
+// parseFernTimes parses RFC3339 start/end times, deriving the duration when both are present
+func parseFernTimes(start, end string) (time.Time, *time.Time, time.Duration) {
+	startTime, _ := time.Parse(time.RFC3339, start)
+
+	parsedEnd, err := time.Parse(time.RFC3339, end)
+	if err != nil {
+		return startTime, nil, 0
+	}
+
+	var duration time.Duration
+	if !startTime.IsZero() && parsedEnd.After(startTime) {
+		duration = parsedEnd.Sub(startTime)
+	}
+	return startTime, &parsedEnd, duration
+}
+
+// fernSpecStatus maps Ginkgo spec states onto platform spec statuses
+func fernSpecStatus(status string) string {
+	switch status = strings.ToLower(status); status {
+	case "pending":
+		return "skipped"
+	case "panicked", "interrupted", "timedout", "aborted":
+		return "failed"
+	default:
+		return status
+	}
+}
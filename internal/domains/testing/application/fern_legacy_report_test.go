@@ -0,0 +1,165 @@
+package application_test
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+var fernSuites = []application.FernSuiteRun{
+	{
+		SuiteName: "Cart Suite",
+		StartTime: "2024-03-01T10:00:00Z",
+		EndTime:   "2024-03-01T10:00:05Z",
+		SpecRuns: []application.FernSpecRun{
+			{
+				SpecDescription: "adds an item",
+				Status:          "passed",
+				StartTime:       "2024-03-01T10:00:00Z",
+				EndTime:         "2024-03-01T10:00:02Z",
+				Tags:            []application.FernTag{{Name: "Smoke"}, {Name: "cart"}},
+			},
+			{
+				SpecDescription: "removes an item",
+				Status:          "failed",
+				Message:         "expected 0 to equal 1",
+				StartTime:       "2024-03-01T10:00:02Z",
+				EndTime:         "2024-03-01T10:00:05Z",
+				Tags:            []application.FernTag{{Name: "smoke"}},
+			},
+			{
+				SpecDescription: "applies a coupon",
+				Status:          "pending",
+			},
+		},
+	},
+	{
+		SuiteName: "Empty Suite",
+	},
+}
+
+var _ = Describe("Fern legacy reports", Label("unit", "application", "testing"), func() {
+	Describe("ConvertFernSuiteRuns", func() {
+		It("should map suites, specs and distinct tags", func() {
+			suites, tags, specMetadata := application.ConvertFernSuiteRuns(fernSuites)
+
+			Expect(tags).To(Equal([]string{"smoke", "cart"}))
+			Expect(specMetadata).To(Equal(map[string]interface{}{
+				"Cart Suite/adds an item":    map[string]interface{}{"labels": []string{"Smoke", "cart"}},
+				"Cart Suite/removes an item": map[string]interface{}{"labels": []string{"smoke"}},
+			}))
+			Expect(suites).To(HaveLen(2))
+
+			cart := suites[0]
+			Expect(cart.Name).To(Equal("Cart Suite"))
+			Expect(cart.Status).To(Equal("failed"))
+			Expect(cart.Duration).To(Equal(5 * time.Second))
+			Expect(cart.TotalTests).To(Equal(3))
+			Expect(cart.PassedTests).To(Equal(1))
+			Expect(cart.FailedTests).To(Equal(1))
+			Expect(cart.SkippedTests).To(Equal(1))
+
+			Expect(cart.SpecRuns[1].ErrorMessage).To(Equal("expected 0 to equal 1"))
+			Expect(cart.SpecRuns[1].Duration).To(Equal(3 * time.Second))
+			Expect(cart.SpecRuns[2].Status).To(Equal("skipped"))
+			Expect(cart.SpecRuns[2].EndTime).To(BeNil())
+
+			Expect(suites[1].Status).To(Equal("passed"))
+		})
+	})
+
+	Describe("persistence", func() {
+		var (
+			service         *application.TestRunService
+			mockTestRunRepo *MockTestRunRepository
+			mockSuiteRepo   *MockSuiteRunRepository
+			mockSpecRepo    *MockSpecRunRepository
+			ctx             context.Context
+		)
+
+		BeforeEach(func() {
+			mockTestRunRepo = new(MockTestRunRepository)
+			mockSuiteRepo = new(MockSuiteRunRepository)
+			mockSpecRepo = new(MockSpecRunRepository)
+			service = application.NewTestRunService(mockTestRunRepo, mockSuiteRepo, mockSpecRepo)
+			ctx = context.Background()
+		})
+
+		expectHierarchy := func(testRunID uint) {
+			mockSuiteRepo.On("CreateBatch", ctx, mock.MatchedBy(func(suites []*domain.SuiteRun) bool {
+				return len(suites) == 2 && suites[0].TestRunID == testRunID && suites[1].TestRunID == testRunID
+			})).Run(func(args mock.Arguments) {
+				for i, suite := range args.Get(1).([]*domain.SuiteRun) {
+					suite.ID = uint(100 + i)
+				}
+			}).Return(nil).Once()
+			mockSpecRepo.On("CreateBatch", ctx, mock.MatchedBy(func(specs []*domain.SpecRun) bool {
+				if len(specs) != 3 {
+					return false
+				}
+				for _, spec := range specs {
+					if spec.SuiteRunID != 100 {
+						return false
+					}
+				}
+				return true
+			})).Return(nil).Once()
+			mockTestRunRepo.On("AddTags", ctx, testRunID, []string{"smoke", "cart"}).Return(nil).Once()
+		}
+
+		It("should batch insert suites and specs, tag the run and keep the labels of each spec", func() {
+			expectHierarchy(9)
+			testRun := &domain.TestRun{ID: 9}
+
+			err := service.ImportFernSuiteRuns(ctx, testRun, fernSuites)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testRun.Metadata).To(HaveKeyWithValue("ginkgo", HaveKeyWithValue("specs", HaveKeyWithValue(
+				"Cart Suite/adds an item", map[string]interface{}{"labels": []string{"Smoke", "cart"}},
+			))))
+
+			mockTestRunRepo.AssertExpectations(GinkgoT())
+			mockSuiteRepo.AssertExpectations(GinkgoT())
+			mockSpecRepo.AssertExpectations(GinkgoT())
+		})
+
+		It("should backfill runs whose suites only live in metadata", func() {
+			// Metadata comes back from the database as generic JSON
+			raw, err := json.Marshal(fernSuites)
+			Expect(err).NotTo(HaveOccurred())
+			var storedSuites interface{}
+			Expect(json.Unmarshal(raw, &storedSuites)).To(Succeed())
+
+			legacyRun := &domain.TestRun{ID: 9, Metadata: map[string]interface{}{
+				"test_seed":  float64(42),
+				"suite_runs": storedSuites,
+			}}
+			emptyRun := &domain.TestRun{ID: 12, Metadata: map[string]interface{}{"suite_runs": nil}}
+
+			mockTestRunRepo.On("FindWithUnpersistedSuiteRuns", ctx, uint(0), 100).
+				Return([]*domain.TestRun{legacyRun, emptyRun}, nil).Once()
+			mockTestRunRepo.On("FindWithUnpersistedSuiteRuns", ctx, uint(12), 100).
+				Return([]*domain.TestRun{}, nil).Once()
+			expectHierarchy(9)
+			mockTestRunRepo.On("Update", ctx, mock.MatchedBy(func(tr *domain.TestRun) bool {
+				_, stillThere := tr.Metadata["suite_runs"]
+				_, labelled := tr.Metadata["ginkgo"]
+				return tr.ID == 9 && !stillThere && labelled && tr.Metadata["test_seed"] == float64(42)
+			})).Return(nil).Once()
+
+			backfilled, err := service.BackfillLegacySuiteRuns(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backfilled).To(Equal(1))
+
+			mockTestRunRepo.AssertExpectations(GinkgoT())
+			mockSuiteRepo.AssertExpectations(GinkgoT())
+			mockSpecRepo.AssertExpectations(GinkgoT())
+		})
+	})
+})
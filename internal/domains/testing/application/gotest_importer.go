@@ -0,0 +1,410 @@
+package application
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// goTestEvent is a single line of `go test -json` (test2json) output
+type goTestEvent struct {
+	Time        time.Time `json:"Time"`
+	Action      string    `json:"Action"`
+	Package     string    `json:"Package"`
+	ImportPath  string    `json:"ImportPath"`
+	Test        string    `json:"Test"`
+	Elapsed     *float64  `json:"Elapsed"`
+	Output      string    `json:"Output"`
+	FailedBuild string    `json:"FailedBuild"`
+}
+
+// goTestPackage accumulates events for one package
+type goTestPackage struct {
+	name        string
+	start       time.Time
+	end         time.Time
+	status      string
+	elapsed     *float64
+	failedBuild bool
+	output      strings.Builder
+	tests       map[string]*goTestCase
+	order       []string
+}
+
+// goTestCase accumulates events for one test or subtest
+type goTestCase struct {
+	name     string
+	start    time.Time
+	end      time.Time
+	status   string
+	elapsed  *float64
+	pausedAt time.Time
+	paused   time.Duration
+	output   strings.Builder
+}
+
+// goTestFramingPrefixes are the lines test2json emits around a test's own output
+var goTestFramingPrefixes = []string{
+	"=== RUN", "=== PAUSE", "=== CONT", "=== NAME",
+	"--- PASS", "--- FAIL", "--- SKIP",
+}
+
+// ParseGoTestJSON converts a `go test -json` event stream into a test run hierarchy:
+// each package becomes a suite run and each test or subtest a spec run.
+// Lines that are not JSON (for example compiler errors interleaved on stderr)
+// are kept as unattributed output. The returned test run has no project or run ID assigned.
+func ParseGoTestJSON(r io.Reader) (*domain.TestRun, error) {
+	packages := make(map[string]*goTestPackage)
+	var order []string
+	buildOutput := make(map[string]*strings.Builder)
+	var buildOrder []string
+	var stray strings.Builder
+	events := 0
+
+	getPackage := func(name string) *goTestPackage {
+		pkg, ok := packages[name]
+		if !ok {
+			pkg = &goTestPackage{name: name, tests: make(map[string]*goTestCase)}
+			packages[name] = pkg
+			order = append(order, name)
+		}
+		return pkg
+	}
+
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var event goTestEvent
+			if jsonErr := json.Unmarshal(line, &event); jsonErr != nil || event.Action == "" {
+				stray.Write(line)
+			} else {
+				events++
+				switch {
+				case event.Action == "build-output" || event.Action == "build-fail":
+					importPath := goTestImportPath(event.ImportPath)
+					if _, ok := buildOutput[importPath]; !ok {
+						buildOutput[importPath] = &strings.Builder{}
+						buildOrder = append(buildOrder, importPath)
+					}
+					buildOutput[importPath].WriteString(event.Output)
+				case event.Package != "":
+					getPackage(event.Package).apply(&event)
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read go test output: %w", err)
+		}
+	}
+
+	if events == 0 {
+		return nil, fmt.Errorf("invalid go test report: no test2json events found")
+	}
+
+	// Build failures may be reported only through build events, without any package events
+	for _, importPath := range buildOrder {
+		if _, ok := packages[importPath]; !ok {
+			pkg := getPackage(importPath)
+			pkg.status = "failed"
+		}
+	}
+
+	testRun := &domain.TestRun{Source: "go-test"}
+	packageMetadata := make([]map[string]interface{}, 0, len(order))
+
+	var endTime time.Time
+	for _, name := range order {
+		pkg := packages[name]
+		if build, ok := buildOutput[name]; ok {
+			pkg.failedBuild = pkg.failedBuild || pkg.status == "failed"
+			pkg.output.WriteString(build.String())
+		}
+
+		suiteRun, meta := pkg.toSuiteRun()
+		packageMetadata = append(packageMetadata, meta)
+		if suiteRun.TotalTests == 0 {
+			// Packages without tests ("no test files") carry no results
+			continue
+		}
+		testRun.SuiteRuns = append(testRun.SuiteRuns, suiteRun)
+
+		if !suiteRun.StartTime.IsZero() && (testRun.StartTime.IsZero() || suiteRun.StartTime.Before(testRun.StartTime)) {
+			testRun.StartTime = suiteRun.StartTime
+		}
+		if suiteRun.EndTime != nil && suiteRun.EndTime.After(endTime) {
+			endTime = *suiteRun.EndTime
+		}
+
+		testRun.TotalTests += suiteRun.TotalTests
+		testRun.PassedTests += suiteRun.PassedTests
+		testRun.FailedTests += suiteRun.FailedTests
+		testRun.SkippedTests += suiteRun.SkippedTests
+	}
+
+	if !endTime.IsZero() {
+		testRun.EndTime = &endTime
+		testRun.Duration = endTime.Sub(testRun.StartTime)
+	}
+	if !testRun.StartTime.IsZero() {
+		for i := range testRun.SuiteRuns {
+			backfillSuiteTimes(&testRun.SuiteRuns[i], testRun.StartTime)
+		}
+	}
+
+	testRun.Status = "passed"
+	if testRun.FailedTests > 0 {
+		testRun.Status = "failed"
+	}
+
+	goTest := map[string]interface{}{
+		"packages": packageMetadata,
+	}
+	if out := strings.TrimSpace(stray.String()); out != "" {
+		goTest["unparsed_output"] = out
+	}
+	testRun.Metadata = map[string]interface{}{
+		"source_format": "go-test",
+		"go_test":       goTest,
+	}
+
+	return testRun, nil
+}
+
+// goTestImportPath strips the " [pkg.test]" variant suffix from a build event import path
+func goTestImportPath(importPath string) string {
+	if i := strings.Index(importPath, " ["); i >= 0 {
+		return importPath[:i]
+	}
+	return importPath
+}
+
+// apply folds a package-scoped event into the package state
+func (p *goTestPackage) apply(event *goTestEvent) {
+	if p.start.IsZero() || (!event.Time.IsZero() && event.Time.Before(p.start)) {
+		p.start = event.Time
+	}
+	if event.Time.After(p.end) {
+		p.end = event.Time
+	}
+
+	if event.Test == "" {
+		switch event.Action {
+		case "output":
+			p.output.WriteString(event.Output)
+			if strings.Contains(event.Output, "[build failed]") || strings.Contains(event.Output, "[setup failed]") {
+				p.failedBuild = true
+			}
+		case "pass", "fail", "skip":
+			p.status = goTestStatus(event.Action)
+			p.elapsed = event.Elapsed
+			if event.FailedBuild != "" {
+				p.failedBuild = true
+			}
+		}
+		return
+	}
+
+	tc, ok := p.tests[event.Test]
+	if !ok {
+		tc = &goTestCase{name: event.Test, start: event.Time}
+		p.tests[event.Test] = tc
+		p.order = append(p.order, event.Test)
+	}
+
+	switch event.Action {
+	case "run":
+		tc.start = event.Time
+	case "pause":
+		tc.pausedAt = event.Time
+	case "cont":
+		if !tc.pausedAt.IsZero() {
+			tc.paused += event.Time.Sub(tc.pausedAt)
+			tc.pausedAt = time.Time{}
+		}
+	case "output":
+		tc.output.WriteString(event.Output)
+	case "pass", "fail", "skip":
+		tc.status = goTestStatus(event.Action)
+		tc.elapsed = event.Elapsed
+		tc.end = event.Time
+	}
+}
+
+// toSuiteRun converts the package and its tests
+func (p *goTestPackage) toSuiteRun() (domain.SuiteRun, map[string]interface{}) {
+	suiteRun := domain.SuiteRun{
+		Name:        p.name,
+		PackageName: p.name,
+		StartTime:   p.start,
+		Status:      "passed",
+	}
+
+	for _, name := range p.order {
+		appendSpecRun(&suiteRun, p.tests[name].toSpecRun(p.end))
+	}
+
+	// A failing package without a failing test is a build, setup, TestMain or
+	// panic/timeout failure; record it as a spec so the failure is counted
+	output := strings.TrimSpace(p.output.String())
+	if p.status == "failed" && suiteRun.FailedTests == 0 {
+		name := "[package failed]"
+		if p.failedBuild {
+			name = "[build failed]"
+		}
+		appendSpecRun(&suiteRun, &domain.SpecRun{
+			Name:         name,
+			Status:       "failed",
+			StartTime:    p.start,
+			EndTime:      goTestTimePtr(p.end),
+			Duration:     p.end.Sub(p.start),
+			ErrorMessage: goTestErrorMessage(output, name),
+			StackTrace:   output,
+			Output:       domain.NewSpecOutput(output, ""),
+		})
+	}
+
+	if p.elapsed != nil {
+		suiteRun.Duration = goTestSeconds(*p.elapsed)
+	} else if !p.start.IsZero() {
+		suiteRun.Duration = p.end.Sub(p.start)
+	}
+	if !p.start.IsZero() {
+		suiteRun.EndTime = goTestTimePtr(p.start.Add(suiteRun.Duration))
+	}
+
+	meta := map[string]interface{}{
+		"name":   p.name,
+		"status": p.status,
+	}
+	if p.failedBuild {
+		meta["build_failed"] = true
+	}
+	if p.status == "failed" && output != "" {
+		meta["output"] = output
+	}
+
+	return suiteRun, meta
+}
+
+// toSpecRun converts a test; tests still running when the package ended (panic,
+// timeout) are reported as failed at packageEnd
+func (tc *goTestCase) toSpecRun(packageEnd time.Time) *domain.SpecRun {
+	output := goTestStripFraming(tc.output.String())
+
+	spec := &domain.SpecRun{
+		Name:   tc.name,
+		Status: tc.status,
+		Output: domain.NewSpecOutput(output, ""),
+	}
+	setGoSubtestParameters(spec)
+
+	end := tc.end
+	if tc.status == "" {
+		spec.Status = "failed"
+		end = packageEnd
+		spec.ErrorMessage = "test did not complete"
+	}
+
+	// test2json's Elapsed is the test's own runtime; without it, fall back to wall
+	// clock time minus the time spent paused waiting for parallel siblings
+	switch {
+	case tc.elapsed != nil:
+		spec.Duration = goTestSeconds(*tc.elapsed)
+	case !tc.start.IsZero() && !end.IsZero():
+		spec.Duration = end.Sub(tc.start) - tc.paused
+	}
+	if !end.IsZero() {
+		spec.StartTime = end.Add(-spec.Duration)
+		spec.EndTime = goTestTimePtr(end)
+	} else {
+		spec.StartTime = tc.start
+	}
+
+	if spec.Status == "failed" {
+		if spec.ErrorMessage == "" {
+			spec.ErrorMessage = goTestErrorMessage(output, "test failed")
+		}
+		spec.FailureMessage = spec.ErrorMessage
+		spec.StackTrace = output
+	}
+
+	return spec
+}
+
+// goTestStatus maps test2json terminal actions to spec statuses
+func goTestStatus(action string) string {
+	switch action {
+	case "pass":
+		return "passed"
+	case "fail":
+		return "failed"
+	default:
+		return "skipped"
+	}
+}
+
+// goTestStripFraming removes the === RUN / --- PASS style lines, leaving only what the test logged
+func goTestStripFraming(output string) string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		framing := false
+		for _, prefix := range goTestFramingPrefixes {
+			if strings.HasPrefix(trimmed, prefix) {
+				framing = true
+				break
+			}
+		}
+		if !framing {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// goTestErrorMessage picks the first meaningful line of output as the error message,
+// skipping the "# package" header that precedes compiler output
+func goTestErrorMessage(output, fallback string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "# ") {
+			return line
+		}
+	}
+	return fallback
+}
+
+func goTestSeconds(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func goTestTimePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// ImportGoTestJSON parses a `go test -json` event stream and records it as a new test run
+func (s *TestRunService) ImportGoTestJSON(ctx context.Context, r io.Reader, opts ImportOptions) (*domain.TestRun, error) {
+	testRun, err := ParseGoTestJSON(r)
+	if err != nil {
+		return nil, &InvalidReportError{Err: err}
+	}
+
+	if err := s.importTestRun(ctx, testRun, opts); err != nil {
+		return nil, err
+	}
+
+	return testRun, nil
+}
@@ -0,0 +1,154 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+const goTestStream = `{"Time":"2024-03-01T10:00:00Z","Action":"start","Package":"example.com/cart"}
+{"Time":"2024-03-01T10:00:00.1Z","Action":"run","Package":"example.com/cart","Test":"TestAdd"}
+{"Time":"2024-03-01T10:00:00.1Z","Action":"output","Package":"example.com/cart","Test":"TestAdd","Output":"=== RUN   TestAdd\n"}
+{"Time":"2024-03-01T10:00:00.1Z","Action":"output","Package":"example.com/cart","Test":"TestAdd","Output":"=== PAUSE TestAdd\n"}
+{"Time":"2024-03-01T10:00:00.1Z","Action":"pause","Package":"example.com/cart","Test":"TestAdd"}
+{"Time":"2024-03-01T10:00:00.2Z","Action":"run","Package":"example.com/cart","Test":"TestRemove"}
+{"Time":"2024-03-01T10:00:00.2Z","Action":"run","Package":"example.com/cart","Test":"TestRemove/empty_cart"}
+{"Time":"2024-03-01T10:00:00.3Z","Action":"output","Package":"example.com/cart","Test":"TestRemove/empty_cart","Output":"    cart_test.go:42: expected error, got nil\n"}
+{"Time":"2024-03-01T10:00:00.3Z","Action":"output","Package":"example.com/cart","Test":"TestRemove/empty_cart","Output":"    --- FAIL: TestRemove/empty_cart (0.10s)\n"}
+{"Time":"2024-03-01T10:00:00.3Z","Action":"fail","Package":"example.com/cart","Test":"TestRemove/empty_cart","Elapsed":0.1}
+{"Time":"2024-03-01T10:00:00.3Z","Action":"fail","Package":"example.com/cart","Test":"TestRemove","Elapsed":0.1}
+{"Time":"2024-03-01T10:00:00.3Z","Action":"cont","Package":"example.com/cart","Test":"TestAdd"}
+{"Time":"2024-03-01T10:00:00.3Z","Action":"output","Package":"example.com/cart","Test":"TestAdd","Output":"    cart_test.go:10: added\n"}
+{"Time":"2024-03-01T10:00:00.8Z","Action":"pass","Package":"example.com/cart","Test":"TestAdd","Elapsed":0.5}
+{"Time":"2024-03-01T10:00:00.8Z","Action":"run","Package":"example.com/cart","Test":"TestCoupon"}
+{"Time":"2024-03-01T10:00:00.8Z","Action":"output","Package":"example.com/cart","Test":"TestCoupon","Output":"    cart_test.go:60: coupons disabled\n"}
+{"Time":"2024-03-01T10:00:00.8Z","Action":"skip","Package":"example.com/cart","Test":"TestCoupon","Elapsed":0}
+{"Time":"2024-03-01T10:00:00.9Z","Action":"output","Package":"example.com/cart","Output":"FAIL\n"}
+{"Time":"2024-03-01T10:00:00.9Z","Action":"fail","Package":"example.com/cart","Elapsed":0.9}
+{"Time":"2024-03-01T10:00:00Z","Action":"start","Package":"example.com/docs"}
+{"Time":"2024-03-01T10:00:00Z","Action":"output","Package":"example.com/docs","Output":"?   \texample.com/docs\t[no test files]\n"}
+{"Time":"2024-03-01T10:00:00Z","Action":"skip","Package":"example.com/docs","Elapsed":0}
+`
+
+var _ = Describe("Go test import", Label("unit", "application", "testing"), func() {
+	Describe("ParseGoTestJSON", func() {
+		It("should map packages to suites and tests to specs", func() {
+			testRun, err := application.ParseGoTestJSON(strings.NewReader(goTestStream))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(testRun.Status).To(Equal("failed"))
+			Expect(testRun.SuiteRuns).To(HaveLen(1))
+			Expect(testRun.TotalTests).To(Equal(4))
+			Expect(testRun.PassedTests).To(Equal(1))
+			Expect(testRun.FailedTests).To(Equal(2))
+			Expect(testRun.SkippedTests).To(Equal(1))
+
+			suite := testRun.SuiteRuns[0]
+			Expect(suite.Name).To(Equal("example.com/cart"))
+			Expect(suite.Duration).To(Equal(900 * time.Millisecond))
+
+			names := make([]string, len(suite.SpecRuns))
+			for i, spec := range suite.SpecRuns {
+				names[i] = spec.Name
+			}
+			Expect(names).To(Equal([]string{"TestAdd", "TestRemove", "TestRemove/empty_cart", "TestCoupon"}))
+
+			// Subtests are parameter sets of the test they ran under
+			Expect(suite.SpecRuns[1].ParentName).To(BeEmpty())
+			Expect(suite.SpecRuns[2].ParentName).To(Equal("TestRemove"))
+			Expect(suite.SpecRuns[2].Parameters).To(Equal(map[string]string{"subtest": "empty_cart"}))
+		})
+
+		It("should time paused tests by their own runtime and keep output", func() {
+			testRun, err := application.ParseGoTestJSON(strings.NewReader(goTestStream))
+			Expect(err).NotTo(HaveOccurred())
+
+			add := testRun.SuiteRuns[0].SpecRuns[0]
+			Expect(add.Duration).To(Equal(500 * time.Millisecond))
+			Expect(add.StartTime).To(Equal(time.Date(2024, 3, 1, 10, 0, 0, 300000000, time.UTC)))
+
+			failed := testRun.SuiteRuns[0].SpecRuns[2]
+			Expect(failed.Status).To(Equal("failed"))
+			Expect(failed.ErrorMessage).To(Equal("cart_test.go:42: expected error, got nil"))
+			Expect(failed.StackTrace).NotTo(ContainSubstring("--- FAIL"))
+
+			Expect(add.Output.Stdout).To(Equal("cart_test.go:10: added"))
+			Expect(testRun.SuiteRuns[0].SpecRuns[3].Output.Stdout).To(Equal("cart_test.go:60: coupons disabled"))
+		})
+
+		It("should record build failures as a failed spec", func() {
+			stream := `{"ImportPath":"example.com/broken [example.com/broken.test]","Action":"build-output","Output":"# example.com/broken\n"}
+{"ImportPath":"example.com/broken [example.com/broken.test]","Action":"build-output","Output":"./broken.go:3:1: syntax error: unexpected }\n"}
+{"ImportPath":"example.com/broken [example.com/broken.test]","Action":"build-fail"}
+{"Time":"2024-03-01T10:00:00Z","Action":"start","Package":"example.com/broken"}
+{"Time":"2024-03-01T10:00:00Z","Action":"output","Package":"example.com/broken","Output":"FAIL\texample.com/broken [build failed]\n"}
+{"Time":"2024-03-01T10:00:00Z","Action":"fail","Package":"example.com/broken","Elapsed":0,"FailedBuild":"example.com/broken [example.com/broken.test]"}
+`
+			testRun, err := application.ParseGoTestJSON(strings.NewReader(stream))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testRun.Status).To(Equal("failed"))
+			Expect(testRun.SuiteRuns).To(HaveLen(1))
+
+			spec := testRun.SuiteRuns[0].SpecRuns[0]
+			Expect(spec.Name).To(Equal("[build failed]"))
+			Expect(spec.Status).To(Equal("failed"))
+			Expect(spec.StackTrace).To(ContainSubstring("syntax error"))
+		})
+
+		It("should fail tests that never finished when the package panicked", func() {
+			stream := `{"Time":"2024-03-01T10:00:00Z","Action":"run","Package":"example.com/p","Test":"TestHang"}
+{"Time":"2024-03-01T10:10:00Z","Action":"output","Package":"example.com/p","Output":"panic: test timed out after 10m0s\n"}
+{"Time":"2024-03-01T10:10:00Z","Action":"fail","Package":"example.com/p","Elapsed":600}
+`
+			testRun, err := application.ParseGoTestJSON(strings.NewReader(stream))
+			Expect(err).NotTo(HaveOccurred())
+
+			spec := testRun.SuiteRuns[0].SpecRuns[0]
+			Expect(spec.Name).To(Equal("TestHang"))
+			Expect(spec.Status).To(Equal("failed"))
+			Expect(spec.Duration).To(Equal(10 * time.Minute))
+		})
+
+		It("should reject input without test2json events", func() {
+			_, err := application.ParseGoTestJSON(strings.NewReader("ok  \texample.com/cart\t0.9s\n"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("ImportGoTestJSON", func() {
+		It("should persist the parsed run", func() {
+			mockTestRunRepo := new(MockTestRunRepository)
+			mockSuiteRepo := new(MockSuiteRunRepository)
+			mockSpecRepo := new(MockSpecRunRepository)
+			service := application.NewTestRunService(mockTestRunRepo, mockSuiteRepo, mockSpecRepo)
+			ctx := context.Background()
+
+			mockTestRunRepo.On("CreateWithHierarchy", ctx, mock.MatchedBy(func(tr *domain.TestRun) bool {
+				return len(tr.SuiteRuns) == 1 && len(tr.SuiteRuns[0].SpecRuns) == 4
+			}), []string(nil)).Run(func(args mock.Arguments) {
+				args.Get(1).(*domain.TestRun).ID = 3
+			}).Return(nil)
+
+			testRun, err := service.ImportGoTestJSON(ctx, strings.NewReader(goTestStream), application.ImportOptions{ProjectID: "proj-1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testRun.RunID).NotTo(BeEmpty())
+			mockTestRunRepo.AssertExpectations(GinkgoT())
+		})
+
+		It("should flag unparseable input as an invalid report", func() {
+			service := application.NewTestRunService(nil, nil, nil)
+			_, err := service.ImportGoTestJSON(context.Background(), strings.NewReader(""), application.ImportOptions{ProjectID: "proj-1"})
+
+			var invalid *application.InvalidReportError
+			Expect(errors.As(err, &invalid)).To(BeTrue())
+		})
+	})
+})
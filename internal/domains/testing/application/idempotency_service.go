@@ -0,0 +1,152 @@
+package application
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// DefaultIdempotencyKeyTTL is how long ingestion responses are kept for replay
+const DefaultIdempotencyKeyTTL = 24 * time.Hour
+
+// IdempotencyClaimLease is how long a request may hold a key without completing or releasing
+// it before a retry may claim it again
+const IdempotencyClaimLease = 5 * time.Minute
+
+// IdempotencyService makes ingestion requests carrying an idempotency key run at most once per project
+type IdempotencyService struct {
+	repo domain.IdempotencyKeyRepository
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+// NewIdempotencyService creates a new idempotency service. A non-positive ttl uses DefaultIdempotencyKeyTTL.
+func NewIdempotencyService(repo domain.IdempotencyKeyRepository, ttl time.Duration) *IdempotencyService {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyKeyTTL
+	}
+	return &IdempotencyService{
+		repo: repo,
+		ttl:  ttl,
+		now:  time.Now,
+	}
+}
+
+// Begin claims a key for a request. It returns the stored record when the request is a replay
+// of a completed one, or nil when the key was claimed and the caller should process the request.
+// A key reused with a different payload fails with domain.ErrIdempotencyKeyMismatch, and a key
+// whose original request is still running fails with domain.ErrIdempotencyKeyInProgress. A key
+// held for longer than IdempotencyClaimLease without a response, e.g. because the server
+// processing it crashed, is claimed again.
+func (s *IdempotencyService) Begin(ctx context.Context, projectID, key, requestHash string) (*domain.IdempotencyRecord, error) {
+	record, err := s.lookup(ctx, projectID, key)
+	if err != nil {
+		return nil, err
+	}
+	if record != nil {
+		return s.resume(ctx, record, requestHash)
+	}
+
+	now := s.now()
+	err = s.repo.Create(ctx, &domain.IdempotencyRecord{
+		ProjectID:   projectID,
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ClaimedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+	})
+	if errors.Is(err, domain.ErrIdempotencyKeyExists) {
+		// A concurrent request claimed the key first
+		record, err = s.lookup(ctx, projectID, key)
+		if err != nil {
+			return nil, err
+		}
+		if record == nil {
+			return nil, domain.ErrIdempotencyKeyInProgress
+		}
+		return s.resume(ctx, record, requestHash)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// Complete stores the response of a request so that replays can return it
+func (s *IdempotencyService) Complete(ctx context.Context, projectID, key string, testRunID uint, status int, body []byte) error {
+	return s.repo.Complete(ctx, &domain.IdempotencyRecord{
+		ProjectID:      projectID,
+		Key:            key,
+		TestRunID:      testRunID,
+		ResponseStatus: status,
+		ResponseBody:   body,
+	})
+}
+
+// Release frees a claimed key after a failed request so that the client can retry it
+func (s *IdempotencyService) Release(ctx context.Context, projectID, key string) error {
+	return s.repo.Delete(ctx, projectID, key)
+}
+
+// PurgeExpired removes all expired keys and returns how many were removed
+func (s *IdempotencyService) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.repo.DeleteExpired(ctx, s.now())
+}
+
+// lookup returns the live record for a key, dropping it if it has expired
+func (s *IdempotencyService) lookup(ctx context.Context, projectID, key string) (*domain.IdempotencyRecord, error) {
+	record, err := s.repo.Get(ctx, projectID, key)
+	if err != nil {
+		return nil, err
+	}
+	if record != nil && record.IsExpired(s.now()) {
+		if err := s.repo.Delete(ctx, projectID, key); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+	return record, nil
+}
+
+// resume handles a request for a key that is already claimed: it returns the stored record of a
+// completed request, or nil after claiming the key again from an abandoned one
+func (s *IdempotencyService) resume(ctx context.Context, record *domain.IdempotencyRecord, requestHash string) (*domain.IdempotencyRecord, error) {
+	if record.RequestHash != requestHash {
+		return nil, domain.ErrIdempotencyKeyMismatch
+	}
+	if record.IsCompleted() {
+		return record, nil
+	}
+	now := s.now()
+	if !record.IsAbandoned(now, IdempotencyClaimLease) {
+		return nil, domain.ErrIdempotencyKeyInProgress
+	}
+
+	reclaimed, err := s.repo.Reclaim(ctx, record, now)
+	if err != nil {
+		return nil, err
+	}
+	if !reclaimed {
+		// Another retry claimed the key first, or the original request completed after all
+		return nil, domain.ErrIdempotencyKeyInProgress
+	}
+	return nil, nil
+}
+
+// HashRequest fingerprints the parts of a request that must match on replay
+func HashRequest(parts ...[]byte) string {
+	h := sha256.New()
+	for _, part := range parts {
+		// Length-prefix each part so that boundaries can't shift between parts
+		fmt.Fprintf(h, "%d:", len(part))
+		h.Write(part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
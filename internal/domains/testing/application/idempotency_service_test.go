@@ -0,0 +1,197 @@
+package application_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// Mock idempotency key repository
+type MockIdempotencyKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyKeyRepository) Create(ctx context.Context, record *domain.IdempotencyRecord) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyKeyRepository) Get(ctx context.Context, projectID, key string) (*domain.IdempotencyRecord, error) {
+	args := m.Called(ctx, projectID, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.IdempotencyRecord), args.Error(1)
+}
+
+func (m *MockIdempotencyKeyRepository) Reclaim(ctx context.Context, record *domain.IdempotencyRecord, claimedAt time.Time) (bool, error) {
+	args := m.Called(ctx, record, claimedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockIdempotencyKeyRepository) Complete(ctx context.Context, record *domain.IdempotencyRecord) error {
+	args := m.Called(ctx, record)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyKeyRepository) Delete(ctx context.Context, projectID, key string) error {
+	args := m.Called(ctx, projectID, key)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyKeyRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+var _ = Describe("IdempotencyService", Label("unit", "application", "testing"), func() {
+	var (
+		service  *application.IdempotencyService
+		mockRepo *MockIdempotencyKeyRepository
+		ctx      context.Context
+		hash     string
+	)
+
+	BeforeEach(func() {
+		mockRepo = new(MockIdempotencyKeyRepository)
+		service = application.NewIdempotencyService(mockRepo, time.Hour)
+		ctx = context.Background()
+		hash = application.HashRequest([]byte("POST"), []byte(`{"runId":"build-1"}`))
+	})
+
+	completed := func(requestHash string) *domain.IdempotencyRecord {
+		return &domain.IdempotencyRecord{
+			ProjectID:      "proj-1",
+			Key:            "key-1",
+			RequestHash:    requestHash,
+			TestRunID:      5,
+			ResponseStatus: 201,
+			ResponseBody:   []byte(`{"id":5}`),
+			ExpiresAt:      time.Now().Add(time.Hour),
+		}
+	}
+
+	It("should claim an unused key", func() {
+		mockRepo.On("Get", ctx, "proj-1", "key-1").Return(nil, nil)
+		mockRepo.On("Create", ctx, mock.MatchedBy(func(r *domain.IdempotencyRecord) bool {
+			return r.RequestHash == hash && r.ExpiresAt.Sub(r.CreatedAt) == time.Hour && r.ClaimedAt.Equal(r.CreatedAt)
+		})).Return(nil)
+
+		record, err := service.Begin(ctx, "proj-1", "key-1", hash)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(record).To(BeNil())
+		mockRepo.AssertExpectations(GinkgoT())
+	})
+
+	It("should return the stored response for a replay", func() {
+		mockRepo.On("Get", ctx, "proj-1", "key-1").Return(completed(hash), nil)
+
+		record, err := service.Begin(ctx, "proj-1", "key-1", hash)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(record.ResponseStatus).To(Equal(201))
+		Expect(record.ResponseBody).To(MatchJSON(`{"id":5}`))
+		mockRepo.AssertNotCalled(GinkgoT(), "Create", mock.Anything, mock.Anything)
+	})
+
+	It("should reject a key reused with a different payload", func() {
+		mockRepo.On("Get", ctx, "proj-1", "key-1").Return(completed("other"), nil)
+
+		_, err := service.Begin(ctx, "proj-1", "key-1", hash)
+		Expect(err).To(MatchError(domain.ErrIdempotencyKeyMismatch))
+	})
+
+	It("should reject a replay while the original request is still running", func() {
+		inProgress := completed(hash)
+		inProgress.ResponseStatus = 0
+		inProgress.ClaimedAt = time.Now().Add(-time.Minute)
+		mockRepo.On("Get", ctx, "proj-1", "key-1").Return(inProgress, nil)
+
+		_, err := service.Begin(ctx, "proj-1", "key-1", hash)
+		Expect(err).To(MatchError(domain.ErrIdempotencyKeyInProgress))
+		mockRepo.AssertNotCalled(GinkgoT(), "Reclaim", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	It("should reclaim a key whose original request never completed", func() {
+		abandoned := completed(hash)
+		abandoned.ResponseStatus = 0
+		abandoned.ClaimedAt = time.Now().Add(-application.IdempotencyClaimLease - time.Minute)
+		mockRepo.On("Get", ctx, "proj-1", "key-1").Return(abandoned, nil)
+		mockRepo.On("Reclaim", ctx, abandoned, mock.MatchedBy(func(claimedAt time.Time) bool {
+			return claimedAt.After(abandoned.ClaimedAt)
+		})).Return(true, nil)
+
+		record, err := service.Begin(ctx, "proj-1", "key-1", hash)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(record).To(BeNil())
+		mockRepo.AssertExpectations(GinkgoT())
+	})
+
+	It("should defer to a retry that reclaimed an abandoned key first", func() {
+		abandoned := completed(hash)
+		abandoned.ResponseStatus = 0
+		abandoned.ClaimedAt = time.Now().Add(-application.IdempotencyClaimLease - time.Minute)
+		mockRepo.On("Get", ctx, "proj-1", "key-1").Return(abandoned, nil)
+		mockRepo.On("Reclaim", ctx, abandoned, mock.Anything).Return(false, nil)
+
+		_, err := service.Begin(ctx, "proj-1", "key-1", hash)
+		Expect(err).To(MatchError(domain.ErrIdempotencyKeyInProgress))
+	})
+
+	It("should not reclaim an abandoned key for a different payload", func() {
+		abandoned := completed("other")
+		abandoned.ResponseStatus = 0
+		abandoned.ClaimedAt = time.Now().Add(-application.IdempotencyClaimLease - time.Minute)
+		mockRepo.On("Get", ctx, "proj-1", "key-1").Return(abandoned, nil)
+
+		_, err := service.Begin(ctx, "proj-1", "key-1", hash)
+		Expect(err).To(MatchError(domain.ErrIdempotencyKeyMismatch))
+		mockRepo.AssertNotCalled(GinkgoT(), "Reclaim", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	It("should reclaim an expired key", func() {
+		expired := completed("other")
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+		mockRepo.On("Get", ctx, "proj-1", "key-1").Return(expired, nil)
+		mockRepo.On("Delete", ctx, "proj-1", "key-1").Return(nil)
+		mockRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+		record, err := service.Begin(ctx, "proj-1", "key-1", hash)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(record).To(BeNil())
+		mockRepo.AssertExpectations(GinkgoT())
+	})
+
+	It("should defer to a concurrent request that claimed the key first", func() {
+		inProgress := completed(hash)
+		inProgress.ResponseStatus = 0
+		inProgress.ClaimedAt = time.Now()
+		mockRepo.On("Get", ctx, "proj-1", "key-1").Return(nil, nil).Once()
+		mockRepo.On("Create", ctx, mock.Anything).Return(domain.ErrIdempotencyKeyExists)
+		mockRepo.On("Get", ctx, "proj-1", "key-1").Return(inProgress, nil).Once()
+
+		_, err := service.Begin(ctx, "proj-1", "key-1", hash)
+		Expect(err).To(MatchError(domain.ErrIdempotencyKeyInProgress))
+	})
+
+	It("should store the response on completion", func() {
+		mockRepo.On("Complete", ctx, mock.MatchedBy(func(r *domain.IdempotencyRecord) bool {
+			return r.TestRunID == 5 && r.ResponseStatus == 201 && string(r.ResponseBody) == `{"id":5}`
+		})).Return(nil)
+
+		Expect(service.Complete(ctx, "proj-1", "key-1", 5, 201, []byte(`{"id":5}`))).To(Succeed())
+		mockRepo.AssertExpectations(GinkgoT())
+	})
+
+	Describe("HashRequest", func() {
+		It("should not be fooled by shifting bytes between parts", func() {
+			Expect(application.HashRequest([]byte("ab"), []byte("c"))).
+				NotTo(Equal(application.HashRequest([]byte("a"), []byte("bc"))))
+		})
+	})
+})
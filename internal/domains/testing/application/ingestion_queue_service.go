@@ -0,0 +1,229 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// IngestionQueueConfig bounds the asynchronous ingestion queue
+type IngestionQueueConfig struct {
+	// Capacity is the number of unfinished jobs above which new submissions are rejected
+	Capacity int
+
+	// MaxAttempts is the number of processing attempts before a job is dead-lettered
+	MaxAttempts int
+
+	// RetryBackoff is the delay before the first retry; it doubles for every further attempt
+	RetryBackoff time.Duration
+
+	// MaxRetryBackoff caps the delay between retries
+	MaxRetryBackoff time.Duration
+
+	// LeaseDuration is how long a worker may hold a job before another worker may claim it
+	LeaseDuration time.Duration
+
+	// Retention is how long finished jobs are kept for their status to be looked up
+	Retention time.Duration
+}
+
+// DefaultIngestionQueueConfig returns the queue limits used when none are configured
+func DefaultIngestionQueueConfig() IngestionQueueConfig {
+	return IngestionQueueConfig{
+		Capacity:        1000,
+		MaxAttempts:     5,
+		RetryBackoff:    5 * time.Second,
+		MaxRetryBackoff: 10 * time.Minute,
+		LeaseDuration:   10 * time.Minute,
+		Retention:       7 * 24 * time.Hour,
+	}
+}
+
+// IngestionQueueService queues test reports for ingestion by a worker pool
+type IngestionQueueService struct {
+	repo           domain.IngestionJobRepository
+	testRunService *TestRunService
+	config         IngestionQueueConfig
+	now            func() time.Time
+}
+
+// NewIngestionQueueService creates a new ingestion queue service. Non-positive limits in
+// config fall back to DefaultIngestionQueueConfig.
+func NewIngestionQueueService(repo domain.IngestionJobRepository, testRunService *TestRunService, config IngestionQueueConfig) *IngestionQueueService {
+	defaults := DefaultIngestionQueueConfig()
+	if config.Capacity <= 0 {
+		config.Capacity = defaults.Capacity
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = defaults.RetryBackoff
+	}
+	if config.MaxRetryBackoff <= 0 {
+		config.MaxRetryBackoff = defaults.MaxRetryBackoff
+	}
+	if config.LeaseDuration <= 0 {
+		config.LeaseDuration = defaults.LeaseDuration
+	}
+	if config.Retention <= 0 {
+		config.Retention = defaults.Retention
+	}
+
+	return &IngestionQueueService{
+		repo:           repo,
+		testRunService: testRunService,
+		config:         config,
+		now:            time.Now,
+	}
+}
+
+// Enqueue stores a report for asynchronous ingestion. It fails with domain.ErrIngestionQueueFull
+// when the queue is at capacity.
+func (s *IngestionQueueService) Enqueue(ctx context.Context, format string, payload []byte, opts ImportOptions) (*domain.IngestionJob, error) {
+	if opts.ProjectID == "" {
+		return nil, fmt.Errorf("project ID is required")
+	}
+	if !IsSupportedReportFormat(format) {
+		return nil, &InvalidReportError{Err: fmt.Errorf("unsupported report format %q", format)}
+	}
+	if len(payload) == 0 {
+		return nil, &InvalidReportError{Err: fmt.Errorf("report is empty")}
+	}
+
+	now := s.now()
+	job := &domain.IngestionJob{
+		ID:            uuid.New().String(),
+		ProjectID:     opts.ProjectID,
+		Format:        format,
+		Payload:       payload,
+		RunID:         opts.RunID,
+		Branch:        opts.Branch,
+		GitCommit:     opts.GitCommit,
+		Environment:   opts.Environment,
+		Shard:         opts.Shard,
+		Status:        domain.IngestionJobQueued,
+		MaxAttempts:   s.config.MaxAttempts,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	// Fix the run ID up front so that a retried job replaces its earlier partial run
+	// instead of recording a duplicate
+	if job.RunID == "" {
+		job.RunID = job.ID
+	}
+	if err := s.repo.Enqueue(ctx, job, s.config.Capacity); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// GetJob retrieves an ingestion job, failing with domain.ErrIngestionJobNotFound if it does not exist
+func (s *IngestionQueueService) GetJob(ctx context.Context, id string) (*domain.IngestionJob, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, domain.ErrIngestionJobNotFound
+	}
+
+	job, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, domain.ErrIngestionJobNotFound
+	}
+	return job, nil
+}
+
+// PurgeFinished removes jobs that finished more than the retention period ago and returns how
+// many were removed
+func (s *IngestionQueueService) PurgeFinished(ctx context.Context) (int64, error) {
+	return s.repo.DeleteFinished(ctx, s.now().Add(-s.config.Retention))
+}
+
+// ProcessNext claims and ingests the next due job. It returns false when no job was due.
+// Reports that cannot be parsed are dead-lettered straight away; other failures are retried
+// with exponential backoff until the job runs out of attempts. If ctx is cancelled while the
+// job is being processed, the job is released back to the queue.
+func (s *IngestionQueueService) ProcessNext(ctx context.Context) (bool, error) {
+	now := s.now()
+	job, err := s.repo.ClaimNext(ctx, now, now.Add(s.config.LeaseDuration))
+	if err != nil {
+		return false, err
+	}
+	if job == nil {
+		return false, nil
+	}
+
+	// A job that keeps outliving its lease (e.g. because it crashes its worker) is given up on
+	if job.Attempts > job.MaxAttempts {
+		return true, s.repo.Fail(ctx, job.ID, domain.IngestionJobError{
+			Attempt:    job.Attempts,
+			Message:    "job was abandoned by its worker too many times",
+			OccurredAt: s.now(),
+		}, nil)
+	}
+
+	opts := ImportOptions{
+		ProjectID:   job.ProjectID,
+		RunID:       job.RunID,
+		Branch:      job.Branch,
+		GitCommit:   job.GitCommit,
+		Environment: job.Environment,
+		Shard:       job.Shard,
+	}
+	testRun, importErr := s.testRunService.ImportReport(ctx, job.Format, bytes.NewReader(job.Payload), opts)
+
+	// Record the outcome even if ctx was cancelled mid-import
+	recordCtx := context.WithoutCancel(ctx)
+	if importErr == nil {
+		return true, s.repo.Succeed(recordCtx, job.ID, testRun.ID, s.now())
+	}
+	if ctx.Err() != nil {
+		return true, s.repo.Release(recordCtx, job.ID)
+	}
+
+	jobErr := domain.IngestionJobError{
+		Attempt:    job.Attempts,
+		Message:    importErr.Error(),
+		Retryable:  isRetryableIngestionError(importErr) && job.Attempts < job.MaxAttempts,
+		OccurredAt: s.now(),
+	}
+	var retryAt *time.Time
+	if jobErr.Retryable {
+		next := jobErr.OccurredAt.Add(s.retryBackoff(job.Attempts))
+		retryAt = &next
+	}
+	if err := s.repo.Fail(recordCtx, job.ID, jobErr, retryAt); err != nil {
+		return true, err
+	}
+
+	return true, nil
+}
+
+// retryBackoff returns the delay before retrying a job that has failed the given number of attempts
+func (s *IngestionQueueService) retryBackoff(attempts int) time.Duration {
+	backoff := s.config.RetryBackoff
+	for i := 1; i < attempts && backoff < s.config.MaxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > s.config.MaxRetryBackoff {
+		backoff = s.config.MaxRetryBackoff
+	}
+	return backoff
+}
+
+// isRetryableIngestionError returns false for failures that will recur on every attempt
+func isRetryableIngestionError(err error) bool {
+	var invalid *InvalidReportError
+	if errors.As(err, &invalid) {
+		return false
+	}
+	return !errors.Is(err, domain.ErrRunIDConflict) && !errors.Is(err, domain.ErrShardMismatch)
+}
@@ -0,0 +1,236 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// Mock ingestion job repository
+type MockIngestionJobRepository struct {
+	mock.Mock
+}
+
+func (m *MockIngestionJobRepository) Enqueue(ctx context.Context, job *domain.IngestionJob, capacity int) error {
+	args := m.Called(ctx, job, capacity)
+	return args.Error(0)
+}
+
+func (m *MockIngestionJobRepository) Get(ctx context.Context, id string) (*domain.IngestionJob, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.IngestionJob), args.Error(1)
+}
+
+func (m *MockIngestionJobRepository) ClaimNext(ctx context.Context, now, leaseExpiresAt time.Time) (*domain.IngestionJob, error) {
+	args := m.Called(ctx, now, leaseExpiresAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.IngestionJob), args.Error(1)
+}
+
+func (m *MockIngestionJobRepository) Succeed(ctx context.Context, id string, testRunID uint, completedAt time.Time) error {
+	args := m.Called(ctx, id, testRunID, completedAt)
+	return args.Error(0)
+}
+
+func (m *MockIngestionJobRepository) Fail(ctx context.Context, id string, jobErr domain.IngestionJobError, retryAt *time.Time) error {
+	args := m.Called(ctx, id, jobErr, retryAt)
+	return args.Error(0)
+}
+
+func (m *MockIngestionJobRepository) Release(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockIngestionJobRepository) DeleteFinished(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+const queuedTestRunReport = `{
+	"startTime": "2024-03-01T10:00:00Z",
+	"suites": [{"suiteName": "checkout", "specs": [{"specName": "adds", "status": "passed"}]}]
+}`
+
+var _ = Describe("IngestionQueueService", Label("unit", "application", "testing"), func() {
+	var (
+		service         *application.IngestionQueueService
+		mockJobRepo     *MockIngestionJobRepository
+		mockTestRunRepo *MockTestRunRepository
+		ctx             context.Context
+		opts            application.ImportOptions
+	)
+
+	BeforeEach(func() {
+		mockJobRepo = new(MockIngestionJobRepository)
+		mockTestRunRepo = new(MockTestRunRepository)
+		testRunService := application.NewTestRunService(mockTestRunRepo, new(MockSuiteRunRepository), new(MockSpecRunRepository))
+		service = application.NewIngestionQueueService(mockJobRepo, testRunService, application.IngestionQueueConfig{
+			Capacity:        2,
+			MaxAttempts:     3,
+			RetryBackoff:    time.Second,
+			MaxRetryBackoff: time.Minute,
+			LeaseDuration:   time.Minute,
+		})
+		ctx = context.Background()
+		opts = application.ImportOptions{ProjectID: "proj-1", Branch: "main"}
+	})
+
+	Describe("Enqueue", func() {
+		It("should queue the report under a run ID derived from the job", func() {
+			mockJobRepo.On("Enqueue", ctx, mock.MatchedBy(func(job *domain.IngestionJob) bool {
+				return job.Status == domain.IngestionJobQueued && job.MaxAttempts == 3 && job.Branch == "main"
+			}), 2).Return(nil)
+
+			job, err := service.Enqueue(ctx, application.ReportFormatTestRun, []byte(queuedTestRunReport), opts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(job.ID).NotTo(BeEmpty())
+			Expect(job.RunID).To(Equal(job.ID))
+			mockJobRepo.AssertExpectations(GinkgoT())
+		})
+
+		It("should apply backpressure when the queue is full", func() {
+			mockJobRepo.On("Enqueue", ctx, mock.Anything, 2).Return(domain.ErrIngestionQueueFull)
+
+			_, err := service.Enqueue(ctx, application.ReportFormatJUnit, []byte("<testsuite/>"), opts)
+			Expect(err).To(MatchError(domain.ErrIngestionQueueFull))
+		})
+
+		It("should reject unsupported formats", func() {
+			_, err := service.Enqueue(ctx, "tap", []byte("TAP version 13"), opts)
+			var invalid *application.InvalidReportError
+			Expect(errors.As(err, &invalid)).To(BeTrue())
+		})
+	})
+
+	Describe("GetJob", func() {
+		It("should report unknown and malformed IDs as not found", func() {
+			mockJobRepo.On("Get", ctx, "6f1c1f36-0b0a-4bd3-9d59-0a4f2f1b8c11").Return(nil, nil)
+
+			_, err := service.GetJob(ctx, "6f1c1f36-0b0a-4bd3-9d59-0a4f2f1b8c11")
+			Expect(err).To(MatchError(domain.ErrIngestionJobNotFound))
+			_, err = service.GetJob(ctx, "not-a-uuid")
+			Expect(err).To(MatchError(domain.ErrIngestionJobNotFound))
+		})
+	})
+
+	Describe("PurgeFinished", func() {
+		It("should delete jobs that finished before the retention period", func() {
+			mockJobRepo.On("DeleteFinished", ctx, mock.MatchedBy(func(before time.Time) bool {
+				return time.Since(before) > 7*24*time.Hour-time.Minute && time.Since(before) <= 7*24*time.Hour+time.Minute
+			})).Return(int64(3), nil)
+
+			purged, err := service.PurgeFinished(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(purged).To(Equal(int64(3)))
+		})
+	})
+
+	Describe("ProcessNext", func() {
+		claimed := func(format, payload string, attempts int) *domain.IngestionJob {
+			return &domain.IngestionJob{
+				ID:          "job-1",
+				ProjectID:   "proj-1",
+				Format:      format,
+				Payload:     []byte(payload),
+				RunID:       "job-1",
+				Status:      domain.IngestionJobProcessing,
+				Attempts:    attempts,
+				MaxAttempts: 3,
+			}
+		}
+
+		It("should return false when no job is due", func() {
+			mockJobRepo.On("ClaimNext", ctx, mock.Anything, mock.Anything).Return(nil, nil)
+
+			processed, err := service.ProcessNext(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(processed).To(BeFalse())
+		})
+
+		It("should ingest the report and record the test run", func() {
+			mockJobRepo.On("ClaimNext", ctx, mock.Anything, mock.Anything).
+				Return(claimed(application.ReportFormatTestRun, queuedTestRunReport, 1), nil)
+			mockTestRunRepo.On("CreateWithHierarchy", ctx, mock.MatchedBy(func(tr *domain.TestRun) bool {
+				return tr.RunID == "job-1" && tr.TotalTests == 1
+			}), []string(nil)).Run(func(args mock.Arguments) {
+				args.Get(1).(*domain.TestRun).ID = 42
+			}).Return(nil)
+			mockJobRepo.On("Succeed", mock.Anything, "job-1", uint(42), mock.Anything).Return(nil)
+
+			processed, err := service.ProcessNext(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(processed).To(BeTrue())
+			mockJobRepo.AssertExpectations(GinkgoT())
+		})
+
+		It("should retry storage failures with backoff", func() {
+			mockJobRepo.On("ClaimNext", ctx, mock.Anything, mock.Anything).
+				Return(claimed(application.ReportFormatTestRun, queuedTestRunReport, 2), nil)
+			mockTestRunRepo.On("CreateWithHierarchy", ctx, mock.Anything, mock.Anything).Return(errors.New("connection reset"))
+			mockJobRepo.On("Fail", mock.Anything, "job-1", mock.MatchedBy(func(jobErr domain.IngestionJobError) bool {
+				return jobErr.Attempt == 2 && jobErr.Retryable
+			}), mock.MatchedBy(func(retryAt *time.Time) bool {
+				// Second failure waits twice the base backoff
+				return retryAt != nil && time.Until(*retryAt) > time.Second && time.Until(*retryAt) <= 2*time.Second
+			})).Return(nil)
+
+			_, err := service.ProcessNext(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			mockJobRepo.AssertExpectations(GinkgoT())
+		})
+
+		It("should dead-letter jobs that run out of attempts", func() {
+			mockJobRepo.On("ClaimNext", ctx, mock.Anything, mock.Anything).
+				Return(claimed(application.ReportFormatTestRun, queuedTestRunReport, 3), nil)
+			mockTestRunRepo.On("CreateWithHierarchy", ctx, mock.Anything, mock.Anything).Return(errors.New("connection reset"))
+			mockJobRepo.On("Fail", mock.Anything, "job-1", mock.MatchedBy(func(jobErr domain.IngestionJobError) bool {
+				return !jobErr.Retryable
+			}), (*time.Time)(nil)).Return(nil)
+
+			_, err := service.ProcessNext(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			mockJobRepo.AssertExpectations(GinkgoT())
+		})
+
+		It("should dead-letter unparseable reports without retrying", func() {
+			mockJobRepo.On("ClaimNext", ctx, mock.Anything, mock.Anything).
+				Return(claimed(application.ReportFormatJUnit, "not xml", 1), nil)
+			mockJobRepo.On("Fail", mock.Anything, "job-1", mock.MatchedBy(func(jobErr domain.IngestionJobError) bool {
+				return !jobErr.Retryable && jobErr.Message != ""
+			}), (*time.Time)(nil)).Return(nil)
+
+			_, err := service.ProcessNext(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			mockJobRepo.AssertExpectations(GinkgoT())
+			mockTestRunRepo.AssertNotCalled(GinkgoT(), "CreateWithHierarchy", mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		It("should release the job when processing is cancelled", func() {
+			cancelCtx, cancel := context.WithCancel(ctx)
+			mockJobRepo.On("ClaimNext", cancelCtx, mock.Anything, mock.Anything).
+				Return(claimed(application.ReportFormatTestRun, queuedTestRunReport, 1), nil)
+			mockTestRunRepo.On("CreateWithHierarchy", cancelCtx, mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+				cancel()
+			}).Return(context.Canceled)
+			mockJobRepo.On("Release", mock.Anything, "job-1").Return(nil)
+
+			_, err := service.ProcessNext(cancelCtx)
+			Expect(err).NotTo(HaveOccurred())
+			mockJobRepo.AssertExpectations(GinkgoT())
+			mockJobRepo.AssertNotCalled(GinkgoT(), "Fail", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	})
+})
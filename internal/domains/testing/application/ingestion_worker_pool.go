@@ -0,0 +1,99 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/pkg/logging"
+)
+
+// IngestionWorkerPool processes queued ingestion jobs with a bounded number of workers
+type IngestionWorkerPool struct {
+	queue        *IngestionQueueService
+	workers      int
+	pollInterval time.Duration
+	logger       *logging.Logger
+
+	stop   chan struct{}
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewIngestionWorkerPool creates a worker pool. Workers poll the queue every pollInterval
+// while it is empty and drain it back to back while it is not.
+func NewIngestionWorkerPool(queue *IngestionQueueService, workers int, pollInterval time.Duration, logger *logging.Logger) *IngestionWorkerPool {
+	if workers <= 0 {
+		workers = 1
+	}
+	if pollInterval <= 0 {
+		pollInterval = time.Second
+	}
+
+	return &IngestionWorkerPool{
+		queue:        queue,
+		workers:      workers,
+		pollInterval: pollInterval,
+		logger:       logger,
+		stop:         make(chan struct{}),
+	}
+}
+
+// Start launches the workers
+func (p *IngestionWorkerPool) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	for i := 0; i < p.workers; i++ {
+		p.wg.Add(1)
+		go p.run(ctx)
+	}
+}
+
+// Shutdown stops workers from claiming new jobs and waits for in-flight jobs to finish.
+// If ctx expires first, in-flight jobs are cancelled and released back to the queue.
+func (p *IngestionWorkerPool) Shutdown(ctx context.Context) error {
+	close(p.stop)
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (p *IngestionWorkerPool) run(ctx context.Context) {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		default:
+		}
+
+		processed, err := p.queue.ProcessNext(ctx)
+		if err != nil {
+			p.logger.WithService("ingestion-worker").WithError(err).Error("Failed to process ingestion job")
+		}
+		if processed && err == nil {
+			continue
+		}
+
+		select {
+		case <-p.stop:
+			return
+		case <-time.After(p.pollInterval):
+		}
+	}
+}
@@ -0,0 +1,497 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// junitTestSuites is the <testsuites> aggregate root produced by Surefire, Gradle, Jest, pytest and friends
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	Name       string           `xml:"name,attr"`
+	Timestamp  string           `xml:"timestamp,attr"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite is a single <testsuite> element; suites may nest (Ant style)
+type junitTestSuite struct {
+	XMLName    xml.Name         `xml:"testsuite"`
+	Name       string           `xml:"name,attr"`
+	Package    string           `xml:"package,attr"`
+	Hostname   string           `xml:"hostname,attr"`
+	Timestamp  string           `xml:"timestamp,attr"`
+	Time       string           `xml:"time,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Errors     int              `xml:"errors,attr"`
+	Skipped    int              `xml:"skipped,attr"`
+	Properties []junitProperty  `xml:"properties>property"`
+	TestCases  []junitTestCase  `xml:"testcase"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+	SystemOut  string           `xml:"system-out"`
+	SystemErr  string           `xml:"system-err"`
+}
+
+// junitTestCase is a single <testcase> element
+type junitTestCase struct {
+	Name       string          `xml:"name,attr"`
+	ClassName  string          `xml:"classname,attr"`
+	File       string          `xml:"file,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr"`
+	Properties []junitProperty `xml:"properties>property"`
+	Failure    *junitResult    `xml:"failure"`
+	Error      *junitResult    `xml:"error"`
+	Skipped    *junitResult    `xml:"skipped"`
+	SystemOut  string          `xml:"system-out"`
+	SystemErr  string          `xml:"system-err"`
+
+	// Surefire and Failsafe record the failed runs of a test retried with
+	// rerunFailingTestsCount as flaky runs when it eventually passed, and as reruns when it did not
+	FlakyFailures []junitRerun `xml:"flakyFailure"`
+	FlakyErrors   []junitRerun `xml:"flakyError"`
+	RerunFailures []junitRerun `xml:"rerunFailure"`
+	RerunErrors   []junitRerun `xml:"rerunError"`
+}
+
+// junitResult holds the payload of <failure>, <error> and <skipped> elements
+type junitResult struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// junitRerun is a failed run of a retried test; Surefire writes its stack trace in a child element
+type junitRerun struct {
+	Message    string `xml:"message,attr"`
+	Type       string `xml:"type,attr"`
+	StackTrace string `xml:"stackTrace"`
+	Body       string `xml:",chardata"`
+}
+
+// attempt converts a failed run into a spec attempt
+func (r junitRerun) attempt() domain.SpecAttempt {
+	attempt := domain.SpecAttempt{
+		Status:       "failed",
+		ErrorMessage: r.Message,
+		StackTrace:   strings.TrimSpace(r.StackTrace),
+	}
+	if attempt.ErrorMessage == "" {
+		attempt.ErrorMessage = r.Type
+	}
+	if attempt.StackTrace == "" {
+		attempt.StackTrace = strings.TrimSpace(r.Body)
+	}
+	return attempt
+}
+
+// junitProperty is a name/value pair from a <properties> block
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+	Body  string `xml:",chardata"`
+}
+
+// junitTimestampLayouts lists the timestamp formats seen in the wild; JUnit's own
+// schema uses ISO 8601 without a zone, which is interpreted as UTC
+var junitTimestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+}
+
+// ParseJUnitXML converts a JUnit/Surefire XML report into a test run hierarchy.
+// Both a single <testsuite> root and a <testsuites> aggregate are accepted.
+// The returned test run has no project or run ID assigned.
+func ParseJUnitXML(r io.Reader) (*domain.TestRun, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read junit report: %w", err)
+	}
+
+	root, err := xmlRootElement(data, "junit")
+	if err != nil {
+		return nil, err
+	}
+
+	var report junitTestSuites
+	switch root {
+	case "testsuites":
+		if err := xml.Unmarshal(data, &report); err != nil {
+			return nil, fmt.Errorf("invalid junit report: %w", err)
+		}
+	case "testsuite":
+		var suite junitTestSuite
+		if err := xml.Unmarshal(data, &suite); err != nil {
+			return nil, fmt.Errorf("invalid junit report: %w", err)
+		}
+		report.TestSuites = []junitTestSuite{suite}
+	default:
+		return nil, fmt.Errorf("invalid junit report: unexpected root element <%s>", root)
+	}
+
+	return report.toTestRun(), nil
+}
+
+// xmlRootElement returns the local name of the first element in an XML report;
+// format names the report in errors
+func xmlRootElement(data []byte, format string) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return "", fmt.Errorf("invalid %s report: document is empty", format)
+		}
+		if err != nil {
+			return "", fmt.Errorf("invalid %s report: %w", format, err)
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+// toTestRun flattens nested suites and builds the domain hierarchy
+func (r *junitTestSuites) toTestRun() *domain.TestRun {
+	var suites []junitTestSuite
+	for _, suite := range r.TestSuites {
+		suites = append(suites, suite.flatten()...)
+	}
+
+	testRun := &domain.TestRun{
+		Name:   r.Name,
+		Source: "junit",
+	}
+
+	suiteMetadata := make([]map[string]interface{}, 0, len(suites))
+	specMetadata := make(map[string]interface{})
+	fallbackStart := parseJUnitTimestamp(r.Timestamp)
+
+	var endTime time.Time
+	for _, suite := range suites {
+		suiteRun, meta := suite.toSuiteRun(fallbackStart, specMetadata)
+		testRun.SuiteRuns = append(testRun.SuiteRuns, suiteRun)
+		suiteMetadata = append(suiteMetadata, meta)
+
+		if !suiteRun.StartTime.IsZero() && (testRun.StartTime.IsZero() || suiteRun.StartTime.Before(testRun.StartTime)) {
+			testRun.StartTime = suiteRun.StartTime
+		}
+		if suiteRun.EndTime != nil && suiteRun.EndTime.After(endTime) {
+			endTime = *suiteRun.EndTime
+		}
+
+		testRun.TotalTests += suiteRun.TotalTests
+		testRun.PassedTests += suiteRun.PassedTests
+		testRun.FailedTests += suiteRun.FailedTests
+		testRun.SkippedTests += suiteRun.SkippedTests
+		testRun.Duration += suiteRun.Duration
+	}
+
+	if !endTime.IsZero() {
+		testRun.EndTime = &endTime
+	}
+
+	testRun.Status = "passed"
+	if testRun.FailedTests > 0 {
+		testRun.Status = "failed"
+	}
+
+	junit := map[string]interface{}{
+		"suites": suiteMetadata,
+	}
+	if r.Name != "" {
+		junit["name"] = r.Name
+	}
+	if len(specMetadata) > 0 {
+		junit["specs"] = specMetadata
+	}
+	testRun.Metadata = map[string]interface{}{
+		"source_format": "junit",
+		"junit":         junit,
+	}
+
+	return testRun
+}
+
+// flatten returns the suite followed by all of its nested suites
+func (s junitTestSuite) flatten() []junitTestSuite {
+	suites := []junitTestSuite{s}
+	for _, child := range s.TestSuites {
+		suites = append(suites, child.flatten()...)
+	}
+	return suites
+}
+
+// toSuiteRun converts a suite and records per-spec extras (output, skip reasons,
+// properties) into specMetadata keyed by "<suite>/<classname>.<name>"
+func (s junitTestSuite) toSuiteRun(fallbackStart time.Time, specMetadata map[string]interface{}) (domain.SuiteRun, map[string]interface{}) {
+	startTime := parseJUnitTimestamp(s.Timestamp)
+	if startTime.IsZero() {
+		startTime = fallbackStart
+	}
+
+	suiteRun := domain.SuiteRun{
+		Name:        s.Name,
+		PackageName: s.Package,
+		StartTime:   startTime,
+		Status:      "passed",
+	}
+
+	offset := startTime
+	var specsDuration time.Duration
+	for _, tc := range s.TestCases {
+		spec := tc.toSpecRun(offset)
+		if !spec.StartTime.IsZero() {
+			offset = spec.StartTime.Add(spec.Duration)
+		}
+		specsDuration += spec.Duration
+
+		appendSpecRun(&suiteRun, spec)
+
+		if meta := tc.metadata(); len(meta) > 0 {
+			specMetadata[s.Name+"/"+junitSpecKey(tc)] = meta
+		}
+	}
+
+	suiteRun.Duration = parseJUnitDuration(s.Time)
+	if suiteRun.Duration == 0 {
+		suiteRun.Duration = specsDuration
+	}
+	if !startTime.IsZero() {
+		endTime := startTime.Add(suiteRun.Duration)
+		suiteRun.EndTime = &endTime
+	}
+
+	meta := map[string]interface{}{
+		"name":     s.Name,
+		"tests":    s.Tests,
+		"failures": s.Failures,
+		"errors":   s.Errors,
+		"skipped":  s.Skipped,
+	}
+	if s.Package != "" {
+		meta["package"] = s.Package
+	}
+	if s.Hostname != "" {
+		meta["hostname"] = s.Hostname
+	}
+	if s.Timestamp != "" {
+		meta["timestamp"] = s.Timestamp
+	}
+	if props := junitPropertiesToMap(s.Properties); len(props) > 0 {
+		meta["properties"] = props
+	}
+	if out := strings.TrimSpace(s.SystemOut); out != "" {
+		meta["system_out"] = out
+	}
+	if out := strings.TrimSpace(s.SystemErr); out != "" {
+		meta["system_err"] = out
+	}
+
+	return suiteRun, meta
+}
+
+// toSpecRun converts a test case; startTime is used when the case carries no timestamp
+func (tc junitTestCase) toSpecRun(startTime time.Time) *domain.SpecRun {
+	if ts := parseJUnitTimestamp(tc.Timestamp); !ts.IsZero() {
+		startTime = ts
+	}
+
+	spec := &domain.SpecRun{
+		Name:      tc.Name,
+		ClassName: tc.ClassName,
+		Status:    "passed",
+		StartTime: startTime,
+		Duration:  parseJUnitDuration(tc.Time),
+	}
+	setBracketedParameters(spec)
+	if !startTime.IsZero() {
+		endTime := startTime.Add(spec.Duration)
+		spec.EndTime = &endTime
+	}
+
+	// <error> is an unexpected exception, <failure> an assertion; both count as failed
+	result := tc.Failure
+	if result == nil {
+		result = tc.Error
+	}
+	switch {
+	case result != nil:
+		spec.Status = "failed"
+		spec.ErrorMessage = result.Message
+		if spec.ErrorMessage == "" {
+			spec.ErrorMessage = result.Type
+		}
+		spec.FailureMessage = spec.ErrorMessage
+		spec.StackTrace = strings.TrimSpace(result.Body)
+	case tc.Skipped != nil:
+		spec.Status = "skipped"
+	}
+	spec.Output = domain.NewSpecOutput(tc.SystemOut, tc.SystemErr)
+	spec.Attempts = tc.attempts(spec)
+	spec.ClassifyAttempts()
+
+	return spec
+}
+
+// attempts lists the runs of a test retried by Surefire, oldest first, or nil when it ran once.
+// A test that passed on a retry reports its failed runs as flaky runs; one that kept failing
+// reports its first run as the failure and the others as reruns.
+func (tc junitTestCase) attempts(spec *domain.SpecRun) []domain.SpecAttempt {
+	var reruns []junitRerun
+	if spec.Status == "failed" {
+		reruns = append(append(reruns, tc.RerunFailures...), tc.RerunErrors...)
+	} else {
+		reruns = append(append(reruns, tc.FlakyFailures...), tc.FlakyErrors...)
+	}
+	if len(reruns) == 0 {
+		return nil
+	}
+
+	attempts := make([]domain.SpecAttempt, 0, len(reruns)+1)
+	if spec.Status == "failed" {
+		// The first run is reported as the test's own failure and the reruns follow it
+		attempts = append(attempts, domain.SpecAttempt{
+			Status:       "failed",
+			StartTime:    spec.StartTime,
+			ErrorMessage: spec.ErrorMessage,
+			StackTrace:   spec.StackTrace,
+		})
+		for _, rerun := range reruns {
+			attempts = append(attempts, rerun.attempt())
+		}
+		return attempts
+	}
+
+	for _, rerun := range reruns {
+		attempts = append(attempts, rerun.attempt())
+	}
+	return append(attempts, domain.SpecAttempt{
+		Status:    spec.Status,
+		StartTime: spec.StartTime,
+		Duration:  spec.Duration,
+	})
+}
+
+// metadata returns the per-spec details that have no dedicated domain field
+func (tc junitTestCase) metadata() map[string]interface{} {
+	meta := make(map[string]interface{})
+	if tc.Failure != nil && tc.Failure.Type != "" {
+		meta["failure_type"] = tc.Failure.Type
+	}
+	if tc.Error != nil {
+		meta["error_type"] = tc.Error.Type
+	}
+	if tc.Skipped != nil {
+		reason := tc.Skipped.Message
+		if reason == "" {
+			reason = strings.TrimSpace(tc.Skipped.Body)
+		}
+		if reason != "" {
+			meta["skipped_reason"] = reason
+		}
+	}
+	if tc.File != "" {
+		meta["file"] = tc.File
+	}
+	if props := junitPropertiesToMap(tc.Properties); len(props) > 0 {
+		meta["properties"] = props
+	}
+	return meta
+}
+
+// junitSpecKey identifies a test case within its suite
+func junitSpecKey(tc junitTestCase) string {
+	if tc.ClassName == "" {
+		return tc.Name
+	}
+	return tc.ClassName + "." + tc.Name
+}
+
+// junitPropertiesToMap converts <property> elements, accepting either a value
+// attribute or element text as the value
+func junitPropertiesToMap(props []junitProperty) map[string]interface{} {
+	if len(props) == 0 {
+		return nil
+	}
+	result := make(map[string]interface{}, len(props))
+	for _, p := range props {
+		value := p.Value
+		if value == "" {
+			value = strings.TrimSpace(p.Body)
+		}
+		result[p.Name] = value
+	}
+	return result
+}
+
+// parseJUnitDuration parses a time attribute in (possibly fractional) seconds.
+// Some Surefire versions emit locale-formatted values such as "1,234.5" or "0,5".
+func parseJUnitDuration(value string) time.Duration {
+	value = normaliseJUnitDecimal(strings.TrimSpace(value))
+	if value == "" {
+		return 0
+	}
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// normaliseJUnitDecimal rewrites a locale-formatted number with a dot as its decimal
+// separator. A comma is the decimal separator when it is the only one and there is no
+// dot, or when it follows the last dot ("1.234,5"); otherwise commas separate thousands.
+func normaliseJUnitDecimal(value string) string {
+	comma := strings.LastIndex(value, ",")
+	if comma < 0 {
+		return value
+	}
+	dot := strings.LastIndex(value, ".")
+	switch {
+	case dot > comma:
+		return strings.ReplaceAll(value, ",", "")
+	case dot >= 0:
+		return strings.Replace(strings.ReplaceAll(value, ".", ""), ",", ".", 1)
+	case strings.Count(value, ",") == 1:
+		return strings.Replace(value, ",", ".", 1)
+	default:
+		return strings.ReplaceAll(value, ",", "")
+	}
+}
+
+// parseJUnitTimestamp parses a timestamp attribute, returning the zero time when absent or malformed
+func parseJUnitTimestamp(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	for _, layout := range junitTimestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
+
+// ImportJUnitXML parses a JUnit XML report and records it as a new test run
+func (s *TestRunService) ImportJUnitXML(ctx context.Context, r io.Reader, opts ImportOptions) (*domain.TestRun, error) {
+	testRun, err := ParseJUnitXML(r)
+	if err != nil {
+		return nil, &InvalidReportError{Err: err}
+	}
+
+	if err := s.importTestRun(ctx, testRun, opts); err != nil {
+		return nil, err
+	}
+
+	return testRun, nil
+}
@@ -0,0 +1,245 @@
+package application_test
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+const surefireReport = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="checkout-service">
+  <testsuite name="com.example.CartTest" tests="3" failures="1" errors="0" skipped="1" time="1.5" timestamp="2024-03-01T10:00:00" hostname="ci-runner-1">
+    <properties>
+      <property name="java.version" value="21"/>
+    </properties>
+    <testcase name="addsItem" classname="com.example.CartTest" time="0.5">
+      <system-out>added 1 item</system-out>
+    </testcase>
+    <testcase name="removesItem" classname="com.example.CartTest" time="1,000.0">
+      <failure message="expected 0 but was 1" type="java.lang.AssertionError">java.lang.AssertionError: expected 0 but was 1
+	at com.example.CartTest.removesItem(CartTest.java:42)</failure>
+    </testcase>
+    <testcase name="appliesCoupon" classname="com.example.CartTest" time="0">
+      <skipped message="coupons disabled"/>
+    </testcase>
+    <system-err>warning: slow test</system-err>
+  </testsuite>
+  <testsuite name="com.example.PaymentTest" tests="1" timestamp="2024-03-01T10:00:02">
+    <testcase name="charges" classname="com.example.PaymentTest" time="0.25">
+      <error message="connection refused" type="java.net.ConnectException"/>
+    </testcase>
+  </testsuite>
+</testsuites>`
+
+var _ = Describe("JUnit import", Label("unit", "application", "testing"), func() {
+	Describe("ParseJUnitXML", func() {
+		It("should build suites and specs from a <testsuites> aggregate", func() {
+			testRun, err := application.ParseJUnitXML(strings.NewReader(surefireReport))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(testRun.Status).To(Equal("failed"))
+			Expect(testRun.TotalTests).To(Equal(4))
+			Expect(testRun.PassedTests).To(Equal(1))
+			Expect(testRun.FailedTests).To(Equal(2))
+			Expect(testRun.SkippedTests).To(Equal(1))
+			Expect(testRun.StartTime).To(Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)))
+			Expect(testRun.SuiteRuns).To(HaveLen(2))
+
+			cart := testRun.SuiteRuns[0]
+			Expect(cart.Name).To(Equal("com.example.CartTest"))
+			Expect(cart.Status).To(Equal("failed"))
+			Expect(cart.Duration).To(Equal(1500 * time.Millisecond))
+			Expect(cart.SpecRuns).To(HaveLen(3))
+
+			failed := cart.SpecRuns[1]
+			Expect(failed.Status).To(Equal("failed"))
+			Expect(failed.ClassName).To(Equal("com.example.CartTest"))
+			Expect(failed.Duration).To(Equal(1000 * time.Second))
+			Expect(failed.ErrorMessage).To(Equal("expected 0 but was 1"))
+			Expect(failed.StackTrace).To(ContainSubstring("CartTest.java:42"))
+			Expect(failed.StartTime).To(Equal(cart.StartTime.Add(500 * time.Millisecond)))
+
+			Expect(cart.SpecRuns[2].Status).To(Equal("skipped"))
+			Expect(testRun.SuiteRuns[1].SpecRuns[0].ErrorMessage).To(Equal("connection refused"))
+		})
+
+		It("should capture spec output", func() {
+			testRun, err := application.ParseJUnitXML(strings.NewReader(surefireReport))
+			Expect(err).NotTo(HaveOccurred())
+
+			output := testRun.SuiteRuns[0].SpecRuns[0].Output
+			Expect(output).NotTo(BeNil())
+			Expect(output.Stdout).To(Equal("added 1 item"))
+			Expect(testRun.SuiteRuns[0].SpecRuns[2].Output).To(BeNil())
+		})
+
+		It("should keep suite output, properties and skip reasons in metadata", func() {
+			testRun, err := application.ParseJUnitXML(strings.NewReader(surefireReport))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(testRun.Metadata).To(HaveKeyWithValue("source_format", "junit"))
+			junit := testRun.Metadata["junit"].(map[string]interface{})
+			Expect(junit).To(HaveKeyWithValue("name", "checkout-service"))
+
+			suites := junit["suites"].([]map[string]interface{})
+			Expect(suites[0]).To(HaveKeyWithValue("hostname", "ci-runner-1"))
+			Expect(suites[0]).To(HaveKeyWithValue("system_err", "warning: slow test"))
+			Expect(suites[0]["properties"]).To(HaveKeyWithValue("java.version", "21"))
+
+			specs := junit["specs"].(map[string]interface{})
+			Expect(specs).NotTo(HaveKey("com.example.CartTest/com.example.CartTest.addsItem"))
+			Expect(specs["com.example.CartTest/com.example.CartTest.appliesCoupon"]).To(HaveKeyWithValue("skipped_reason", "coupons disabled"))
+			Expect(specs["com.example.PaymentTest/com.example.PaymentTest.charges"]).To(HaveKeyWithValue("error_type", "java.net.ConnectException"))
+		})
+
+		It("should record Surefire reruns as attempts", func() {
+			report := `<testsuite name="com.example.RetryTest" tests="2" failures="1">
+  <testcase name="passesOnRetry" classname="com.example.RetryTest" time="0.3">
+    <flakyFailure message="timed out" type="java.util.concurrent.TimeoutException">
+      <stackTrace>at RetryTest.java:10</stackTrace>
+    </flakyFailure>
+  </testcase>
+  <testcase name="keepsFailing" classname="com.example.RetryTest" time="0.2">
+    <failure message="expected true">at RetryTest.java:20</failure>
+    <rerunFailure message="expected true again"/>
+  </testcase>
+</testsuite>`
+
+			testRun, err := application.ParseJUnitXML(strings.NewReader(report))
+			Expect(err).NotTo(HaveOccurred())
+
+			flaky := testRun.SuiteRuns[0].SpecRuns[0]
+			Expect(flaky.Status).To(Equal("passed"))
+			Expect(flaky.Attempts).To(HaveLen(2))
+			Expect(flaky.Attempts[0].Attempt).To(Equal(1))
+			Expect(flaky.Attempts[0].Status).To(Equal("failed"))
+			Expect(flaky.Attempts[0].ErrorMessage).To(Equal("timed out"))
+			Expect(flaky.Attempts[0].StackTrace).To(Equal("at RetryTest.java:10"))
+			Expect(flaky.Attempts[1].Status).To(Equal("passed"))
+			Expect(flaky.RetryCount).To(Equal(1))
+			Expect(flaky.IsFlaky).To(BeTrue())
+
+			failing := testRun.SuiteRuns[0].SpecRuns[1]
+			Expect(failing.Attempts).To(HaveLen(2))
+			Expect(failing.Attempts[0].ErrorMessage).To(Equal("expected true"))
+			Expect(failing.Attempts[1].ErrorMessage).To(Equal("expected true again"))
+			Expect(failing.IsFlaky).To(BeFalse())
+
+			single, err := application.ParseJUnitXML(strings.NewReader(surefireReport))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(single.SuiteRuns[0].SpecRuns[1].Attempts).To(BeEmpty())
+		})
+
+		It("should treat pytest and JUnit 5 parameterised cases as parameter sets", func() {
+			report := `<testsuite name="tests">
+  <testcase name="test_add[1-2]" classname="tests.test_calc" time="0.1"/>
+  <testcase name="add(int, int)[1]" classname="com.example.CalcTest" time="0.1"/>
+  <testcase name="handles [brackets]" classname="com.example.CalcTest" time="0.1"/>
+</testsuite>`
+
+			testRun, err := application.ParseJUnitXML(strings.NewReader(report))
+			Expect(err).NotTo(HaveOccurred())
+
+			specs := testRun.SuiteRuns[0].SpecRuns
+			Expect(specs[0].ParentName).To(Equal("test_add"))
+			Expect(specs[0].Parameters).To(Equal(map[string]string{"id": "1-2"}))
+			Expect(specs[1].ParentName).To(Equal("add(int, int)"))
+			Expect(specs[1].Parameters).To(Equal(map[string]string{"id": "1"}))
+			Expect(specs[2].ParentName).To(BeEmpty())
+			Expect(specs[2].Parameters).To(BeNil())
+		})
+
+		It("should accept a single <testsuite> root with nested suites", func() {
+			report := `<testsuite name="root" tests="2">
+  <testsuite name="child">
+    <testcase name="passes" time="0.1"/>
+  </testsuite>
+  <testcase name="alsoPasses" time="0.2"/>
+</testsuite>`
+
+			testRun, err := application.ParseJUnitXML(strings.NewReader(report))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testRun.Status).To(Equal("passed"))
+			Expect(testRun.SuiteRuns).To(HaveLen(2))
+			Expect(testRun.SuiteRuns[0].Name).To(Equal("root"))
+			Expect(testRun.SuiteRuns[1].Name).To(Equal("child"))
+			Expect(testRun.TotalTests).To(Equal(2))
+		})
+
+		DescribeTable("reading locale-formatted times",
+			func(value string, expected time.Duration) {
+				report := `<testsuite name="times"><testcase name="passes" time="` + value + `"/></testsuite>`
+
+				testRun, err := application.ParseJUnitXML(strings.NewReader(report))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(testRun.SuiteRuns[0].SpecRuns[0].Duration).To(Equal(expected))
+			},
+			Entry("a dot as decimal separator", "0.5", 500*time.Millisecond),
+			Entry("a comma as decimal separator", "0,5", 500*time.Millisecond),
+			Entry("a single comma without a dot", "1,234", 1234*time.Millisecond),
+			Entry("commas as thousands separators", "1,234.5", 1234500*time.Millisecond),
+			Entry("several commas without a dot", "1,234,567", 1234567*time.Second),
+			Entry("dots as thousands separators", "1.234,5", 1234500*time.Millisecond),
+			Entry("a value that is not a number", "soon", time.Duration(0)),
+		)
+
+		It("should reject documents that are not JUnit reports", func() {
+			_, err := application.ParseJUnitXML(strings.NewReader(`<html></html>`))
+			Expect(err).To(MatchError(ContainSubstring("unexpected root element")))
+
+			_, err = application.ParseJUnitXML(strings.NewReader(``))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("ImportJUnitXML", func() {
+		var (
+			service         *application.TestRunService
+			mockTestRunRepo *MockTestRunRepository
+			mockSuiteRepo   *MockSuiteRunRepository
+			mockSpecRepo    *MockSpecRunRepository
+			ctx             context.Context
+		)
+
+		BeforeEach(func() {
+			mockTestRunRepo = new(MockTestRunRepository)
+			mockSuiteRepo = new(MockSuiteRunRepository)
+			mockSpecRepo = new(MockSpecRunRepository)
+			service = application.NewTestRunService(mockTestRunRepo, mockSuiteRepo, mockSpecRepo)
+			ctx = context.Background()
+		})
+
+		It("should persist the run, suites and specs in one transaction", func() {
+			mockTestRunRepo.On("CreateWithHierarchy", ctx, mock.MatchedBy(func(tr *domain.TestRun) bool {
+				return tr.ProjectID == "proj-123" && tr.RunID == "build-42" && tr.Branch == "main" &&
+					tr.Status == "failed" && len(tr.SuiteRuns) == 2 && tr.TotalTests == 4
+			}), []string(nil)).Run(func(args mock.Arguments) {
+				args.Get(1).(*domain.TestRun).ID = 7
+			}).Return(nil)
+
+			testRun, err := service.ImportJUnitXML(ctx, strings.NewReader(surefireReport), application.ImportOptions{
+				ProjectID: "proj-123",
+				RunID:     "build-42",
+				Branch:    "main",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testRun.ID).To(Equal(uint(7)))
+
+			mockTestRunRepo.AssertExpectations(GinkgoT())
+			mockSuiteRepo.AssertNotCalled(GinkgoT(), "Create", mock.Anything, mock.Anything)
+			mockSpecRepo.AssertNotCalled(GinkgoT(), "CreateBatch", mock.Anything, mock.Anything)
+		})
+
+		It("should require a project ID", func() {
+			_, err := service.ImportJUnitXML(ctx, strings.NewReader(surefireReport), application.ImportOptions{})
+			Expect(err).To(MatchError(ContainSubstring("project ID is required")))
+		})
+	})
+})
@@ -0,0 +1,168 @@
+package application
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+const (
+	// DefaultHeartbeatTimeout is how long a streamed run may go without hearing from its
+	// reporter before it is aborted
+	DefaultHeartbeatTimeout = 5 * time.Minute
+
+	// maxLiveEventSize bounds a single line of a streamed run
+	maxLiveEventSize = 16 << 20
+)
+
+// Types of the events a reporter streams while a run is in progress
+const (
+	LiveEventSpec      = "spec"      // A spec finished
+	LiveEventHeartbeat = "heartbeat" // The reporter is still alive but has nothing to report
+	LiveEventFinish    = "finish"    // The run finished
+)
+
+// LiveEvent is one line of a streamed run
+type LiveEvent struct {
+	Type string `json:"type"`
+
+	// Suite and Spec are set for spec events; the suite is created for its first spec
+	Suite string         `json:"suite,omitempty"`
+	Spec  *SpecRunReport `json:"spec,omitempty"`
+
+	// Status and EndTime are set for finish events. Without a status the run passes unless a spec failed.
+	Status  string     `json:"status,omitempty"`
+	EndTime *time.Time `json:"endTime,omitempty"`
+}
+
+// HeartbeatTimeout returns how long a streamed run may go without hearing from its reporter
+func (s *TestRunService) HeartbeatTimeout() time.Duration {
+	return s.heartbeatTimeout
+}
+
+// StartLiveRun starts a run whose results are streamed in while it is in progress. A run ID
+// of a streamed run that is still running resumes it, so a reporter can reconnect after its
+// connection dropped.
+func (s *TestRunService) StartLiveRun(ctx context.Context, opts ImportOptions) (*domain.TestRun, error) {
+	if opts.ProjectID == "" {
+		return nil, fmt.Errorf("project ID is required")
+	}
+	if opts.Shard != nil {
+		return nil, &InvalidReportError{Err: fmt.Errorf("streamed runs cannot be sharded")}
+	}
+
+	testRun := &domain.TestRun{
+		ProjectID:   opts.ProjectID,
+		RunID:       opts.RunID,
+		Branch:      opts.Branch,
+		GitBranch:   opts.Branch,
+		GitCommit:   opts.GitCommit,
+		Environment: opts.Environment,
+		Status:      "running",
+		StartTime:   time.Now(),
+		Metadata:    opts.Metadata,
+	}
+	if testRun.RunID == "" {
+		testRun.RunID = uuid.New().String()
+	}
+
+	if err := s.testRunRepo.StartLiveRun(ctx, testRun); err != nil {
+		return nil, fmt.Errorf("failed to start streamed run: %w", err)
+	}
+
+	return testRun, nil
+}
+
+// StreamTestRun applies the newline-delimited LiveEvents read from r to a streamed run as they
+// arrive, until the run finishes or r ends. Every event counts as a heartbeat. A stream that
+// ends before the run finished leaves it running, to be resumed or aborted once it stalls.
+// onEvent, if set, is called after each event is applied.
+func (s *TestRunService) StreamTestRun(ctx context.Context, testRun *domain.TestRun, r io.Reader, onEvent func()) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLiveEventSize)
+
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var event LiveEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return &InvalidReportError{Err: fmt.Errorf("invalid event on line %d: %w", line, err)}
+		}
+
+		finished, err := s.applyLiveEvent(ctx, testRun, event)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if onEvent != nil {
+			onEvent()
+		}
+		if finished {
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read streamed run: %w", err)
+	}
+
+	return nil
+}
+
+// applyLiveEvent records one event of a streamed run and reports whether the run finished
+func (s *TestRunService) applyLiveEvent(ctx context.Context, testRun *domain.TestRun, event LiveEvent) (bool, error) {
+	switch event.Type {
+	case LiveEventSpec:
+		if event.Suite == "" {
+			return false, &InvalidReportError{Err: fmt.Errorf("suite is required")}
+		}
+		if event.Spec == nil || event.Spec.SpecName == "" {
+			return false, &InvalidReportError{Err: fmt.Errorf("spec.specName is required")}
+		}
+		if event.Spec.Status == "" {
+			return false, &InvalidReportError{Err: fmt.Errorf("spec.status is required")}
+		}
+
+		// Specs reported without a start time are taken to have just finished
+		spec := event.Spec.ToSpecRun(time.Now().Add(-time.Duration(event.Spec.Duration) * time.Millisecond))
+		s.prepareSpecRuns(spec)
+		if err := s.testRunRepo.AddLiveSpecRun(ctx, testRun, event.Suite, spec); err != nil {
+			return false, fmt.Errorf("failed to record spec: %w", err)
+		}
+		return false, nil
+
+	case LiveEventHeartbeat:
+		now := time.Now()
+		if err := s.testRunRepo.RecordHeartbeat(ctx, testRun.ID, now); err != nil {
+			return false, fmt.Errorf("failed to record heartbeat: %w", err)
+		}
+		testRun.LastHeartbeatAt = &now
+		return false, nil
+
+	case LiveEventFinish:
+		testRun.Status = event.Status
+		testRun.EndTime = event.EndTime
+		if err := s.testRunRepo.FinishLiveRun(ctx, testRun); err != nil {
+			return false, fmt.Errorf("failed to finish streamed run: %w", err)
+		}
+		s.publishCompleted(ctx, testRun)
+		return true, nil
+
+	default:
+		return false, &InvalidReportError{Err: fmt.Errorf("unknown event type %q", event.Type)}
+	}
+}
+
+// AbortStalledRuns marks streamed runs whose reporter has not been heard from for longer than
+// the heartbeat timeout as aborted instead of leaving them running forever
+func (s *TestRunService) AbortStalledRuns(ctx context.Context) (int, error) {
+	return s.testRunRepo.AbortStalledRuns(ctx, time.Now().Add(-s.heartbeatTimeout))
+}
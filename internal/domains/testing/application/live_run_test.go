@@ -0,0 +1,184 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+var _ = Describe("Streamed runs", Label("unit", "application", "testing"), func() {
+	var (
+		service         *application.TestRunService
+		mockTestRunRepo *MockTestRunRepository
+		ctx             context.Context
+		testRun         *domain.TestRun
+	)
+
+	BeforeEach(func() {
+		mockTestRunRepo = new(MockTestRunRepository)
+		service = application.NewTestRunService(mockTestRunRepo, new(MockSuiteRunRepository), new(MockSpecRunRepository))
+		ctx = context.Background()
+		testRun = &domain.TestRun{ID: 7, ProjectID: "proj-1", RunID: "build-42", Status: "running"}
+	})
+
+	Describe("StartLiveRun", func() {
+		It("should start a running run with the build details", func() {
+			mockTestRunRepo.On("StartLiveRun", ctx, mock.MatchedBy(func(tr *domain.TestRun) bool {
+				return tr.ProjectID == "proj-1" && tr.RunID == "build-42" && tr.Branch == "main" &&
+					tr.Status == "running" && !tr.StartTime.IsZero()
+			})).Return(nil).Once()
+
+			started, err := service.StartLiveRun(ctx, application.ImportOptions{ProjectID: "proj-1", RunID: "build-42", Branch: "main"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(started.GitBranch).To(Equal("main"))
+			mockTestRunRepo.AssertExpectations(GinkgoT())
+		})
+
+		It("should generate a run ID when none is given", func() {
+			mockTestRunRepo.On("StartLiveRun", ctx, mock.Anything).Return(nil).Once()
+
+			started, err := service.StartLiveRun(ctx, application.ImportOptions{ProjectID: "proj-1"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(started.RunID).NotTo(BeEmpty())
+		})
+
+		It("should refuse sharded runs", func() {
+			_, err := service.StartLiveRun(ctx, application.ImportOptions{
+				ProjectID: "proj-1",
+				Shard:     &domain.ShardInfo{GroupID: "build-42", Index: 0, Total: 2},
+			})
+
+			var invalid *application.InvalidReportError
+			Expect(errors.As(err, &invalid)).To(BeTrue())
+			mockTestRunRepo.AssertNotCalled(GinkgoT(), "StartLiveRun", mock.Anything, mock.Anything)
+		})
+
+		It("should not resume a finished run", func() {
+			mockTestRunRepo.On("StartLiveRun", ctx, mock.Anything).Return(domain.ErrTestRunFinished).Once()
+
+			_, err := service.StartLiveRun(ctx, application.ImportOptions{ProjectID: "proj-1", RunID: "build-42"})
+
+			Expect(err).To(MatchError(domain.ErrTestRunFinished))
+		})
+	})
+
+	Describe("StreamTestRun", func() {
+		It("should record each event as it arrives until the run finishes", func() {
+			var recorded []string
+			mockTestRunRepo.On("AddLiveSpecRun", ctx, testRun, "Cart", mock.AnythingOfType("*domain.SpecRun")).
+				Run(func(args mock.Arguments) {
+					spec := args.Get(3).(*domain.SpecRun)
+					recorded = append(recorded, spec.Name+":"+spec.Status)
+				}).Return(nil).Twice()
+			mockTestRunRepo.On("RecordHeartbeat", ctx, uint(7), mock.AnythingOfType("time.Time")).Return(nil).Once()
+			mockTestRunRepo.On("FinishLiveRun", ctx, testRun).Return(nil).Once()
+
+			events := 0
+			stream := strings.NewReader(`{"type":"spec","suite":"Cart","spec":{"specName":"adds","status":"passed","duration":1200}}
+
+{"type":"heartbeat"}
+{"type":"spec","suite":"Cart","spec":{"specName":"removes","status":"failed","errorMessage":"boom"}}
+{"type":"finish"}
+{"type":"spec","suite":"Cart","spec":{"specName":"ignored","status":"passed"}}
+`)
+
+			err := service.StreamTestRun(ctx, testRun, stream, func() { events++ })
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorded).To(Equal([]string{"adds:passed", "removes:failed"}))
+			Expect(events).To(Equal(4))
+			Expect(testRun.Status).To(BeEmpty())
+			mockTestRunRepo.AssertExpectations(GinkgoT())
+		})
+
+		It("should time specs reported without a start time as just finished", func() {
+			var spec *domain.SpecRun
+			mockTestRunRepo.On("AddLiveSpecRun", ctx, testRun, "Cart", mock.Anything).
+				Run(func(args mock.Arguments) { spec = args.Get(3).(*domain.SpecRun) }).Return(nil).Once()
+
+			err := service.StreamTestRun(ctx, testRun, strings.NewReader(`{"type":"spec","suite":"Cart","spec":{"specName":"adds","status":"passed","duration":60000}}`), nil)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.StartTime).To(BeTemporally("~", time.Now().Add(-time.Minute), 5*time.Second))
+			Expect(spec.Duration).To(Equal(time.Minute))
+		})
+
+		It("should pass the reported status and end time to the finished run", func() {
+			mockTestRunRepo.On("FinishLiveRun", ctx, testRun).Return(nil).Once()
+
+			err := service.StreamTestRun(ctx, testRun, strings.NewReader(`{"type":"finish","status":"cancelled","endTime":"2026-10-17T10:00:00Z"}`), nil)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testRun.Status).To(Equal("cancelled"))
+			Expect(testRun.EndTime).NotTo(BeNil())
+			Expect(*testRun.EndTime).To(BeTemporally("==", time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)))
+		})
+
+		It("should leave the run running when the stream ends early", func() {
+			mockTestRunRepo.On("RecordHeartbeat", ctx, uint(7), mock.Anything).Return(nil).Once()
+
+			err := service.StreamTestRun(ctx, testRun, strings.NewReader(`{"type":"heartbeat"}`+"\n"), nil)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testRun.LastHeartbeatAt).NotTo(BeNil())
+			mockTestRunRepo.AssertNotCalled(GinkgoT(), "FinishLiveRun", mock.Anything, mock.Anything)
+		})
+
+		It("should reject malformed events with their line number", func() {
+			mockTestRunRepo.On("RecordHeartbeat", ctx, uint(7), mock.Anything).Return(nil).Once()
+
+			err := service.StreamTestRun(ctx, testRun, strings.NewReader("{\"type\":\"heartbeat\"}\n{\"type\":\"spec\",\"suite\":\"Cart\",\"spec\":{\"specName\":\"adds\"}}\n"), nil)
+
+			var invalid *application.InvalidReportError
+			Expect(errors.As(err, &invalid)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("line 2")))
+			Expect(err).To(MatchError(ContainSubstring("spec.status is required")))
+		})
+
+		It("should reject unknown event types", func() {
+			err := service.StreamTestRun(ctx, testRun, strings.NewReader(`{"type":"progress"}`), nil)
+
+			var invalid *application.InvalidReportError
+			Expect(errors.As(err, &invalid)).To(BeTrue())
+		})
+
+		It("should stop once the run was finished elsewhere", func() {
+			mockTestRunRepo.On("RecordHeartbeat", ctx, uint(7), mock.Anything).Return(domain.ErrTestRunFinished).Once()
+
+			err := service.StreamTestRun(ctx, testRun, strings.NewReader("{\"type\":\"heartbeat\"}\n{\"type\":\"heartbeat\"}\n"), nil)
+
+			Expect(err).To(MatchError(domain.ErrTestRunFinished))
+			mockTestRunRepo.AssertNumberOfCalls(GinkgoT(), "RecordHeartbeat", 1)
+		})
+	})
+
+	Describe("AbortStalledRuns", func() {
+		It("should abort runs not heard from within the heartbeat timeout", func() {
+			service.SetHeartbeatTimeout(10 * time.Minute)
+			mockTestRunRepo.On("AbortStalledRuns", ctx, mock.MatchedBy(func(cutoff time.Time) bool {
+				return cutoff.Before(time.Now().Add(-10*time.Minute+time.Second)) && cutoff.After(time.Now().Add(-11*time.Minute))
+			})).Return(3, nil).Once()
+
+			aborted, err := service.AbortStalledRuns(ctx)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(aborted).To(Equal(3))
+		})
+
+		It("should fall back to the default timeout", func() {
+			service.SetHeartbeatTimeout(0)
+
+			Expect(service.HeartbeatTimeout()).To(Equal(application.DefaultHeartbeatTimeout))
+		})
+	})
+})
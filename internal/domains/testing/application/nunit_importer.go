@@ -0,0 +1,199 @@
+package application
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// nunitTestRun is the <test-run> root of an NUnit 3 result file
+type nunitTestRun struct {
+	XMLName       xml.Name    `xml:"test-run"`
+	ID            string      `xml:"id,attr"`
+	Name          string      `xml:"name,attr"`
+	Result        string      `xml:"result,attr"`
+	Total         int         `xml:"total,attr"`
+	Passed        int         `xml:"passed,attr"`
+	Failed        int         `xml:"failed,attr"`
+	Warnings      int         `xml:"warnings,attr"`
+	Inconclusive  int         `xml:"inconclusive,attr"`
+	Skipped       int         `xml:"skipped,attr"`
+	StartTime     string      `xml:"start-time,attr"`
+	EngineVersion string      `xml:"engine-version,attr"`
+	TestSuites    []nunitNode `xml:"test-suite"`
+}
+
+// nunitNode is a <test-suite> or <test-case> element. Suites are assemblies, namespaces,
+// fixtures or the ParameterizedMethod/Theory grouping the cases of a data-driven test. Both
+// share one type so that a suite's cases and nested suites keep their document order.
+type nunitNode struct {
+	XMLName    xml.Name
+	Type       string          `xml:"type,attr"`
+	Name       string          `xml:"name,attr"`
+	FullName   string          `xml:"fullname,attr"`
+	MethodName string          `xml:"methodname,attr"`
+	ClassName  string          `xml:"classname,attr"`
+	Result     string          `xml:"result,attr"`
+	Label      string          `xml:"label,attr"`
+	Site       string          `xml:"site,attr"`
+	StartTime  string          `xml:"start-time,attr"`
+	EndTime    string          `xml:"end-time,attr"`
+	Duration   string          `xml:"duration,attr"`
+	Properties []junitProperty `xml:"properties>property"`
+	Failure    *nunitFailure   `xml:"failure"`
+	Reason     *nunitFailure   `xml:"reason"`
+	Output     string          `xml:"output"`
+	Children   []nunitNode     `xml:",any"`
+}
+
+// nunitFailure holds the payload of <failure> and <reason> elements
+type nunitFailure struct {
+	Message    string `xml:"message"`
+	StackTrace string `xml:"stack-trace"`
+}
+
+// nunitParameterizedSuiteTypes are the suite types whose cases are rows of one data-driven test
+var nunitParameterizedSuiteTypes = map[string]bool{
+	"ParameterizedMethod": true,
+	"Theory":              true,
+	"GenericMethod":       true,
+}
+
+// ParseNUnitXML converts an NUnit 3 result file into a test run hierarchy: each fixture class
+// becomes a suite run and each test case a spec run. Cases of a parameterised method become
+// separate specs sharing the method's test identity. The returned test run has no project or
+// run ID assigned.
+func ParseNUnitXML(r io.Reader) (*domain.TestRun, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read nunit report: %w", err)
+	}
+
+	root, err := xmlRootElement(data, "nunit")
+	if err != nil {
+		return nil, err
+	}
+	if root != "test-run" {
+		return nil, fmt.Errorf("invalid nunit report: unexpected root element <%s>", root)
+	}
+
+	var report nunitTestRun
+	if err := xml.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("invalid nunit report: %w", err)
+	}
+
+	return report.toTestRun(), nil
+}
+
+// toTestRun collects the test cases of every suite and builds the domain hierarchy
+func (r *nunitTestRun) toTestRun() *domain.TestRun {
+	var results []dotnetTestResult
+	for _, suite := range r.TestSuites {
+		results = suite.collect("", false, results)
+	}
+
+	nunit := map[string]interface{}{
+		"result":       r.Result,
+		"total":        r.Total,
+		"passed":       r.Passed,
+		"failed":       r.Failed,
+		"warnings":     r.Warnings,
+		"inconclusive": r.Inconclusive,
+		"skipped":      r.Skipped,
+	}
+	if r.EngineVersion != "" {
+		nunit["engine_version"] = r.EngineVersion
+	}
+
+	return newDotnetTestRun(ReportFormatNUnit, r.Name, parseDotnetTimestamp(r.StartTime), results, nunit)
+}
+
+// collect appends the results of the suite's cases, including those of nested suites. assembly
+// is the enclosing assembly; parameterized is set inside a data-driven test's suite.
+func (s nunitNode) collect(assembly string, parameterized bool, results []dotnetTestResult) []dotnetTestResult {
+	switch {
+	case s.Type == "Assembly":
+		assembly = dotnetAssemblyName(s.Name)
+	case nunitParameterizedSuiteTypes[s.Type]:
+		parameterized = true
+	}
+
+	for _, child := range s.Children {
+		switch child.XMLName.Local {
+		case "test-case":
+			results = append(results, child.toResult(assembly, s.ClassName, parameterized))
+		case "test-suite":
+			results = child.collect(assembly, parameterized, results)
+		}
+	}
+	return results
+}
+
+// toResult converts a test case; fixtureClass is used for cases without a classname
+func (tc nunitNode) toResult(assembly, fixtureClass string, parameterized bool) dotnetTestResult {
+	class := tc.ClassName
+	if class == "" {
+		class = fixtureClass
+	}
+	method := tc.MethodName
+	if method == "" {
+		method = tc.Name
+	}
+
+	spec := newDotnetSpecRun(tc.Name, class, tc.Result,
+		parseDotnetTimestamp(tc.StartTime), parseDotnetTimestamp(tc.EndTime), parseJUnitDuration(tc.Duration))
+	var failure nunitFailure
+	if tc.Failure != nil {
+		failure = *tc.Failure
+	}
+	setDotnetFailure(spec, failure.Message, failure.StackTrace, "test "+strings.ToLower(tc.Result))
+
+	arguments, hasArguments := dotnetArguments(tc.Name, class, method)
+	result := dotnetTestResult{
+		assembly:      assembly,
+		class:         class,
+		spec:          spec,
+		identity:      dotnetTestIdentity(class, method),
+		arguments:     arguments,
+		parameterized: hasArguments || parameterized,
+		meta:          make(map[string]interface{}),
+	}
+	if !isPlainDotnetOutcome(tc.Result) {
+		result.meta["outcome"] = tc.Result
+	}
+	if tc.Label != "" {
+		result.meta["label"] = tc.Label
+	}
+	if tc.Site != "" && tc.Site != "Test" {
+		result.meta["site"] = tc.Site
+	}
+	if tc.Reason != nil {
+		if reason := strings.TrimSpace(tc.Reason.Message); reason != "" {
+			result.meta["skipped_reason"] = reason
+		}
+	}
+	if props := junitPropertiesToMap(tc.Properties); len(props) > 0 {
+		result.meta["properties"] = props
+	}
+	spec.Output = domain.NewSpecOutput(tc.Output, "")
+
+	return result
+}
+
+// ImportNUnitXML parses an NUnit 3 result file and records it as a new test run
+func (s *TestRunService) ImportNUnitXML(ctx context.Context, r io.Reader, opts ImportOptions) (*domain.TestRun, error) {
+	testRun, err := ParseNUnitXML(r)
+	if err != nil {
+		return nil, &InvalidReportError{Err: err}
+	}
+
+	if err := s.importTestRun(ctx, testRun, opts); err != nil {
+		return nil, err
+	}
+
+	return testRun, nil
+}
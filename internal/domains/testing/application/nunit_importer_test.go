@@ -0,0 +1,128 @@
+package application_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+const nunitReport = `<?xml version="1.0" encoding="utf-8"?>
+<test-run id="2" name="Acme.Tests.dll" testcasecount="6" result="Failed" total="6" passed="2" failed="2" inconclusive="1" skipped="1" start-time="2024-03-01 10:00:00Z" end-time="2024-03-01 10:00:02Z" engine-version="3.16.3">
+  <test-suite type="Assembly" name="Acme.Tests.dll" fullname="/src/tests/bin/Acme.Tests.dll">
+    <test-suite type="TestSuite" name="Acme">
+      <test-suite type="TestFixture" name="CartTests" fullname="Acme.CartTests" classname="Acme.CartTests">
+        <test-case name="AddsItem" fullname="Acme.CartTests.AddsItem" methodname="AddsItem" classname="Acme.CartTests" result="Passed" start-time="2024-03-01 10:00:00.100Z" end-time="2024-03-01 10:00:00.300Z" duration="0.200000">
+          <properties><property name="Category" value="smoke"/></properties>
+          <output><![CDATA[added 1 item]]></output>
+        </test-case>
+        <test-suite type="ParameterizedMethod" name="Adds" fullname="Acme.CartTests.Adds" classname="Acme.CartTests">
+          <test-case name="Adds(1,2)" fullname="Acme.CartTests.Adds(1,2)" methodname="Adds" classname="Acme.CartTests" result="Passed" duration="0.010000"/>
+          <test-case name="Adds(2,2)" fullname="Acme.CartTests.Adds(2,2)" methodname="Adds" classname="Acme.CartTests" result="Failed" label="Error" duration="0.020000">
+            <failure>
+              <message><![CDATA[System.OverflowException : Arithmetic operation resulted in an overflow.]]></message>
+              <stack-trace><![CDATA[at Acme.CartTests.Adds(Int32 a, Int32 b) in CartTests.cs:line 17]]></stack-trace>
+            </failure>
+          </test-case>
+        </test-suite>
+        <test-case name="AppliesCoupon" fullname="Acme.CartTests.AppliesCoupon" methodname="AppliesCoupon" classname="Acme.CartTests" result="Skipped" label="Ignored">
+          <reason><message><![CDATA[coupons disabled]]></message></reason>
+        </test-case>
+      </test-suite>
+      <test-suite type="TestFixture" name="PaymentTests" fullname="Acme.PaymentTests" classname="Acme.PaymentTests">
+        <test-case name="Charges" fullname="Acme.PaymentTests.Charges" methodname="Charges" classname="Acme.PaymentTests" result="Inconclusive" duration="0.050000"/>
+        <test-case name="Refunds" fullname="Acme.PaymentTests.Refunds" methodname="Refunds" classname="Acme.PaymentTests" result="Failed" label="Cancelled" site="Parent" duration="1.000000"/>
+      </test-suite>
+    </test-suite>
+  </test-suite>
+</test-run>`
+
+var _ = Describe("NUnit import", Label("unit", "application", "testing"), func() {
+	Describe("ParseNUnitXML", func() {
+		It("should build suites from fixtures and normalise results", func() {
+			testRun, err := application.ParseNUnitXML(strings.NewReader(nunitReport))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(testRun.Source).To(Equal("nunit"))
+			Expect(testRun.Status).To(Equal("failed"))
+			Expect(testRun.TotalTests).To(Equal(6))
+			Expect(testRun.PassedTests).To(Equal(2))
+			Expect(testRun.FailedTests).To(Equal(2))
+			Expect(testRun.SkippedTests).To(Equal(2))
+			Expect(testRun.StartTime).To(Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)))
+			Expect(testRun.SuiteRuns).To(HaveLen(2))
+
+			cart := testRun.SuiteRuns[0]
+			Expect(cart.Name).To(Equal("Acme.CartTests"))
+			Expect(cart.PackageName).To(Equal("Acme.Tests"))
+			Expect(cart.Status).To(Equal("failed"))
+			Expect(cart.SpecRuns).To(HaveLen(4))
+
+			Expect(cart.SpecRuns[0].Duration).To(Equal(200 * time.Millisecond))
+			failed := cart.SpecRuns[2]
+			Expect(failed.Name).To(Equal("Adds(2,2)"))
+			Expect(failed.Status).To(Equal("failed"))
+			Expect(failed.ErrorMessage).To(ContainSubstring("OverflowException"))
+			Expect(failed.StackTrace).To(ContainSubstring("CartTests.cs:line 17"))
+			Expect(cart.SpecRuns[3].Status).To(Equal("skipped"))
+
+			payments := testRun.SuiteRuns[1]
+			Expect(payments.SpecRuns[0].Status).To(Equal("skipped"))
+			Expect(payments.SpecRuns[1].Status).To(Equal("failed"))
+			Expect(payments.SpecRuns[1].ErrorMessage).To(Equal("test failed"))
+		})
+
+		It("should group the cases of a parameterised method under one test identity", func() {
+			testRun, err := application.ParseNUnitXML(strings.NewReader(nunitReport))
+			Expect(err).NotTo(HaveOccurred())
+
+			nunit := testRun.Metadata["nunit"].(map[string]interface{})
+			Expect(nunit["parameterized_tests"]).To(Equal(map[string]interface{}{
+				"Acme.CartTests.Adds": []string{"Acme.CartTests/Adds(1,2)", "Acme.CartTests/Adds(2,2)"},
+			}))
+
+			specs := nunit["specs"].(map[string]interface{})
+			Expect(specs["Acme.CartTests/Adds(1,2)"]).To(HaveKeyWithValue("test_identity", "Acme.CartTests.Adds"))
+			Expect(specs["Acme.CartTests/Adds(1,2)"]).To(HaveKeyWithValue("arguments", "1,2"))
+			Expect(specs["Acme.CartTests/AddsItem"]).To(HaveKeyWithValue("test_identity", "Acme.CartTests.AddsItem"))
+			Expect(specs["Acme.CartTests/AddsItem"]).NotTo(HaveKey("arguments"))
+
+			byName := make(map[string]*domain.SpecRun)
+			for _, spec := range testRun.SuiteRuns[0].SpecRuns {
+				byName[spec.Name] = spec
+			}
+			Expect(byName).To(HaveKey("Adds(1,2)"))
+			Expect(byName["Adds(1,2)"].ParentName).To(Equal("Adds"))
+			Expect(byName["Adds(1,2)"].Parameters).To(Equal(map[string]string{"0": "1", "1": "2"}))
+			Expect(byName).To(HaveKey("AddsItem"))
+			Expect(byName["AddsItem"].ParentName).To(BeEmpty())
+			Expect(byName["AddsItem"].Parameters).To(BeNil())
+		})
+
+		It("should keep output, labels and skip reasons in metadata", func() {
+			testRun, err := application.ParseNUnitXML(strings.NewReader(nunitReport))
+			Expect(err).NotTo(HaveOccurred())
+
+			nunit := testRun.Metadata["nunit"].(map[string]interface{})
+			Expect(nunit).To(HaveKeyWithValue("engine_version", "3.16.3"))
+
+			specs := nunit["specs"].(map[string]interface{})
+			Expect(specs["Acme.CartTests/AddsItem"]).NotTo(HaveKey("system_out"))
+			Expect(testRun.SuiteRuns[0].SpecRuns[0].Output.Stdout).To(Equal("added 1 item"))
+			Expect(specs["Acme.CartTests/AddsItem"].(map[string]interface{})["properties"]).To(HaveKeyWithValue("Category", "smoke"))
+			Expect(specs["Acme.CartTests/AppliesCoupon"]).To(HaveKeyWithValue("skipped_reason", "coupons disabled"))
+			Expect(specs["Acme.CartTests/AppliesCoupon"]).To(HaveKeyWithValue("label", "Ignored"))
+			Expect(specs["Acme.PaymentTests/Charges"]).To(HaveKeyWithValue("outcome", "Inconclusive"))
+			Expect(specs["Acme.PaymentTests/Refunds"]).To(HaveKeyWithValue("site", "Parent"))
+		})
+
+		It("should reject documents that are not NUnit 3 reports", func() {
+			_, err := application.ParseNUnitXML(strings.NewReader(`<test-results></test-results>`))
+			Expect(err).To(MatchError(ContainSubstring("unexpected root element")))
+		})
+	})
+})
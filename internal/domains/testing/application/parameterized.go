@@ -0,0 +1,112 @@
+package application
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// setBracketedParameters marks a spec named like a pytest parametrize case ("test_add[1-2]") or
+// a JUnit parameterised invocation ("add(int, int)[1]") as a parameter set of the test its name
+// starts with. Names with a space before the bracket, such as Ginkgo labels, are left alone.
+func setBracketedParameters(spec *domain.SpecRun) {
+	name := strings.TrimSpace(spec.Name)
+	if !strings.HasSuffix(name, "]") {
+		return
+	}
+	open := strings.LastIndex(name, "[")
+	if open <= 0 || name[open-1] == ' ' || name[open-1] == '\t' {
+		return
+	}
+	id := name[open+1 : len(name)-1]
+	if strings.TrimSpace(id) == "" {
+		return
+	}
+	spec.ParentName = name[:open]
+	spec.Parameters = map[string]string{"id": id}
+}
+
+// setGoSubtestParameters marks a Go subtest ("TestAdd/negative_numbers") as a parameter set of
+// the test it ran under, so the cases of a table-driven test share a parent
+func setGoSubtestParameters(spec *domain.SpecRun) {
+	i := strings.LastIndex(spec.Name, "/")
+	if i <= 0 || i == len(spec.Name)-1 {
+		return
+	}
+	spec.ParentName = spec.Name[:i]
+	spec.Parameters = map[string]string{"subtest": spec.Name[i+1:]}
+}
+
+// parseArgumentList parses an argument list such as `1, "a, b", [2, 3]` or `x: 1, y: 2` into
+// a parameter set. Named arguments are keyed by name and the others by their position.
+func parseArgumentList(arguments string) map[string]string {
+	if strings.TrimSpace(arguments) == "" {
+		return nil
+	}
+
+	parameters := make(map[string]string)
+	for i, argument := range splitArguments(arguments) {
+		argument = strings.TrimSpace(argument)
+		if name, value, ok := strings.Cut(argument, ":"); ok && isIdentifier(strings.TrimSpace(name)) {
+			parameters[strings.TrimSpace(name)] = strings.TrimSpace(value)
+			continue
+		}
+		parameters[strconv.Itoa(i)] = argument
+	}
+	return parameters
+}
+
+// splitArguments splits an argument list on the commas that aren't inside quotes or brackets
+func splitArguments(arguments string) []string {
+	var parts []string
+	var quote rune
+	depth, start := 0, 0
+	for i, r := range arguments {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(' || r == '[' || r == '{':
+			depth++
+		case r == ')' || r == ']' || r == '}':
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, arguments[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, arguments[start:])
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if r != '_' && !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && (i == 0 || !(r >= '0' && r <= '9')) {
+			return false
+		}
+	}
+	return true
+}
+
+// toParameters converts parameter values of any JSON type to strings
+func toParameters(values map[string]interface{}) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+	parameters := make(map[string]string, len(values))
+	for name, value := range values {
+		if s, ok := value.(string); ok {
+			parameters[name] = s
+		} else if encoded, err := json.Marshal(value); err == nil {
+			parameters[name] = string(encoded)
+		}
+	}
+	return parameters
+}
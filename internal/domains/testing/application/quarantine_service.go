@@ -0,0 +1,185 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+const (
+	// DefaultQuarantineDuration is how long a quarantine lasts when no expiry is given
+	DefaultQuarantineDuration = 30 * 24 * time.Hour
+
+	// MaxQuarantineDuration is how long a quarantine may last before it has to be renewed
+	MaxQuarantineDuration = 90 * 24 * time.Hour
+)
+
+// Formats the quarantines of a project can be exported in for test runners
+const (
+	QuarantineFormatJSON         = "json"
+	QuarantineFormatGinkgoSkip   = "ginkgo-skip"
+	QuarantineFormatJUnitExclude = "junit-exclude"
+)
+
+// ErrUnknownQuarantineFormat is returned when quarantines are exported in a format that is not supported
+var ErrUnknownQuarantineFormat = errors.New("unknown quarantine format")
+
+// QuarantineTestRequest describes a test to quarantine
+type QuarantineTestRequest struct {
+	ProjectID  string
+	TestCaseID uint
+	Reason     string
+	Owner      string // Defaults to CreatedBy
+	JiraIssue  string
+	CreatedBy  string
+	ExpiresAt  *time.Time // Defaults to DefaultQuarantineDuration from now
+}
+
+// QuarantineService manages the quarantines of flaky tests
+type QuarantineService struct {
+	repo         domain.QuarantineRepository
+	testCaseRepo domain.TestCaseRepository
+	now          func() time.Time
+}
+
+// NewQuarantineService creates a new quarantine service
+func NewQuarantineService(repo domain.QuarantineRepository, testCaseRepo domain.TestCaseRepository) *QuarantineService {
+	return &QuarantineService{
+		repo:         repo,
+		testCaseRepo: testCaseRepo,
+		now:          time.Now,
+	}
+}
+
+// QuarantineTest quarantines a test of a project until its quarantine expires or is lifted
+func (s *QuarantineService) QuarantineTest(ctx context.Context, req QuarantineTestRequest) (*domain.Quarantine, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, fmt.Errorf("reason is required")
+	}
+
+	testCase, err := s.testCaseRepo.GetByID(ctx, req.TestCaseID)
+	if err != nil {
+		return nil, err
+	}
+	if testCase.ProjectID != req.ProjectID {
+		return nil, domain.ErrTestCaseNotFound
+	}
+
+	now := s.now()
+	expiresAt := now.Add(DefaultQuarantineDuration)
+	if req.ExpiresAt != nil {
+		expiresAt = *req.ExpiresAt
+	}
+	if !expiresAt.After(now) {
+		return nil, fmt.Errorf("expiry must be in the future")
+	}
+	if expiresAt.After(now.Add(MaxQuarantineDuration)) {
+		return nil, fmt.Errorf("quarantines may last at most %d days", int(MaxQuarantineDuration/(24*time.Hour)))
+	}
+
+	active, err := s.repo.Find(ctx, domain.QuarantineFilter{ProjectID: req.ProjectID, TestCaseID: testCase.ID, ActiveAt: now})
+	if err != nil {
+		return nil, err
+	}
+	if len(active) > 0 {
+		return nil, domain.ErrTestAlreadyQuarantined
+	}
+
+	owner := strings.TrimSpace(req.Owner)
+	if owner == "" {
+		owner = req.CreatedBy
+	}
+	quarantine := &domain.Quarantine{
+		ProjectID:  req.ProjectID,
+		TestCaseID: testCase.ID,
+		TestCase:   testCase,
+		Reason:     reason,
+		Owner:      owner,
+		JiraIssue:  strings.TrimSpace(req.JiraIssue),
+		CreatedBy:  req.CreatedBy,
+		ExpiresAt:  expiresAt,
+	}
+	if err := s.repo.Create(ctx, quarantine); err != nil {
+		return nil, err
+	}
+	return quarantine, nil
+}
+
+// GetQuarantine retrieves a quarantine of a project
+func (s *QuarantineService) GetQuarantine(ctx context.Context, projectID string, id uint) (*domain.Quarantine, error) {
+	quarantine, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if quarantine.ProjectID != projectID {
+		return nil, domain.ErrQuarantineNotFound
+	}
+	return quarantine, nil
+}
+
+// ListQuarantines retrieves the quarantines of a project, newest first. activeOnly leaves out
+// quarantines that expired or were lifted.
+func (s *QuarantineService) ListQuarantines(ctx context.Context, projectID string, activeOnly bool) ([]*domain.Quarantine, error) {
+	filter := domain.QuarantineFilter{ProjectID: projectID}
+	if activeOnly {
+		filter.ActiveAt = s.now()
+	}
+	return s.repo.Find(ctx, filter)
+}
+
+// LiftQuarantine ends a quarantine of a project before it expires
+func (s *QuarantineService) LiftQuarantine(ctx context.Context, projectID string, id uint, liftedBy string) (*domain.Quarantine, error) {
+	quarantine, err := s.GetQuarantine(ctx, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+	if quarantine.LiftedAt != nil {
+		return nil, domain.ErrQuarantineNotFound
+	}
+
+	now := s.now()
+	if err := s.repo.Lift(ctx, id, liftedBy, now); err != nil {
+		return nil, err
+	}
+	quarantine.LiftedAt = &now
+	quarantine.LiftedBy = liftedBy
+	return quarantine, nil
+}
+
+// FormatQuarantines renders quarantines for a test runner in one of the text formats, returning
+// the body and its content type:
+//   - ginkgo-skip: a --skip regular expression matching the specs of the quarantined tests,
+//     empty when nothing is quarantined
+//   - junit-exclude: Surefire and Gradle exclude patterns, one "Class#test" per line
+func FormatQuarantines(format string, quarantines []*domain.Quarantine) (string, string, error) {
+	switch format {
+	case QuarantineFormatGinkgoSkip:
+		var patterns []string
+		for _, quarantine := range quarantines {
+			if pattern := quarantine.SkipPattern(); pattern != "" {
+				patterns = append(patterns, pattern)
+			}
+		}
+		return strings.Join(patterns, "|"), "text/plain; charset=utf-8", nil
+
+	case QuarantineFormatJUnitExclude:
+		var b strings.Builder
+		for _, quarantine := range quarantines {
+			if quarantine.TestCase == nil {
+				continue
+			}
+			className := quarantine.TestCase.ClassName
+			if className == "" {
+				className = quarantine.TestCase.SuiteName
+			}
+			fmt.Fprintf(&b, "%s#%s\n", className, quarantine.TestCase.Name)
+		}
+		return b.String(), "text/plain; charset=utf-8", nil
+	}
+	return "", "", fmt.Errorf("%w: %q", ErrUnknownQuarantineFormat, format)
+}
@@ -0,0 +1,193 @@
+package application_test
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// MockQuarantineRepository is a mock implementation of domain.QuarantineRepository
+type MockQuarantineRepository struct {
+	mock.Mock
+}
+
+func (m *MockQuarantineRepository) Create(ctx context.Context, quarantine *domain.Quarantine) error {
+	args := m.Called(ctx, quarantine)
+	return args.Error(0)
+}
+
+func (m *MockQuarantineRepository) GetByID(ctx context.Context, id uint) (*domain.Quarantine, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Quarantine), args.Error(1)
+}
+
+func (m *MockQuarantineRepository) Find(ctx context.Context, filter domain.QuarantineFilter) ([]*domain.Quarantine, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Quarantine), args.Error(1)
+}
+
+func (m *MockQuarantineRepository) Lift(ctx context.Context, id uint, liftedBy string, at time.Time) error {
+	args := m.Called(ctx, id, liftedBy, at)
+	return args.Error(0)
+}
+
+var _ = Describe("QuarantineService", Label("unit", "application", "testing"), func() {
+	var (
+		service          *application.QuarantineService
+		mockRepo         *MockQuarantineRepository
+		mockTestCaseRepo *MockTestCaseRepository
+		ctx              context.Context
+		testCase         *domain.TestCase
+	)
+
+	BeforeEach(func() {
+		mockRepo = new(MockQuarantineRepository)
+		mockTestCaseRepo = new(MockTestCaseRepository)
+		service = application.NewQuarantineService(mockRepo, mockTestCaseRepo)
+		ctx = context.Background()
+		testCase = &domain.TestCase{ID: 42, ProjectID: "project-1", SuiteName: "Cart", Name: "adds an item"}
+	})
+
+	Describe("QuarantineTest", func() {
+		It("should quarantine a test for the default duration", func() {
+			mockTestCaseRepo.On("GetByID", ctx, uint(42)).Return(testCase, nil)
+			mockRepo.On("Find", ctx, mock.MatchedBy(func(filter domain.QuarantineFilter) bool {
+				return filter.ProjectID == "project-1" && filter.TestCaseID == 42 && !filter.ActiveAt.IsZero()
+			})).Return([]*domain.Quarantine{}, nil)
+			mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.Quarantine")).Return(nil)
+
+			quarantine, err := service.QuarantineTest(ctx, application.QuarantineTestRequest{
+				ProjectID:  "project-1",
+				TestCaseID: 42,
+				Reason:     " flips on the same commit ",
+				JiraIssue:  "PAY-123",
+				CreatedBy:  "user-1",
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(quarantine.Reason).To(Equal("flips on the same commit"))
+			Expect(quarantine.Owner).To(Equal("user-1"))
+			Expect(quarantine.JiraIssue).To(Equal("PAY-123"))
+			Expect(quarantine.ExpiresAt).To(BeTemporally("~", time.Now().Add(application.DefaultQuarantineDuration), time.Minute))
+			Expect(quarantine.Status(time.Now())).To(Equal(domain.QuarantineActive))
+			mockRepo.AssertExpectations(GinkgoT())
+		})
+
+		It("should reject a test that is already quarantined", func() {
+			mockTestCaseRepo.On("GetByID", ctx, uint(42)).Return(testCase, nil)
+			mockRepo.On("Find", ctx, mock.Anything).Return([]*domain.Quarantine{{ID: 1, TestCaseID: 42}}, nil)
+
+			_, err := service.QuarantineTest(ctx, application.QuarantineTestRequest{ProjectID: "project-1", TestCaseID: 42, Reason: "flaky"})
+
+			Expect(err).To(MatchError(domain.ErrTestAlreadyQuarantined))
+			mockRepo.AssertNotCalled(GinkgoT(), "Create", mock.Anything, mock.Anything)
+		})
+
+		It("should not quarantine a test of another project", func() {
+			mockTestCaseRepo.On("GetByID", ctx, uint(42)).Return(testCase, nil)
+
+			_, err := service.QuarantineTest(ctx, application.QuarantineTestRequest{ProjectID: "project-2", TestCaseID: 42, Reason: "flaky"})
+
+			Expect(err).To(MatchError(domain.ErrTestCaseNotFound))
+		})
+
+		It("should require a reason and an expiry in the future", func() {
+			_, err := service.QuarantineTest(ctx, application.QuarantineTestRequest{ProjectID: "project-1", TestCaseID: 42})
+			Expect(err).To(HaveOccurred())
+
+			mockTestCaseRepo.On("GetByID", ctx, uint(42)).Return(testCase, nil)
+			past := time.Now().Add(-time.Hour)
+			_, err = service.QuarantineTest(ctx, application.QuarantineTestRequest{ProjectID: "project-1", TestCaseID: 42, Reason: "flaky", ExpiresAt: &past})
+			Expect(err).To(MatchError(ContainSubstring("future")))
+
+			distant := time.Now().Add(application.MaxQuarantineDuration + time.Hour)
+			_, err = service.QuarantineTest(ctx, application.QuarantineTestRequest{ProjectID: "project-1", TestCaseID: 42, Reason: "flaky", ExpiresAt: &distant})
+			Expect(err).To(HaveOccurred())
+			mockRepo.AssertNotCalled(GinkgoT(), "Create", mock.Anything, mock.Anything)
+		})
+	})
+
+	Describe("LiftQuarantine", func() {
+		It("should lift an active quarantine", func() {
+			mockRepo.On("GetByID", ctx, uint(7)).Return(&domain.Quarantine{ID: 7, ProjectID: "project-1", ExpiresAt: time.Now().Add(time.Hour)}, nil)
+			mockRepo.On("Lift", ctx, uint(7), "user-1", mock.AnythingOfType("time.Time")).Return(nil)
+
+			quarantine, err := service.LiftQuarantine(ctx, "project-1", 7, "user-1")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(quarantine.Status(time.Now())).To(Equal(domain.QuarantineLifted))
+			Expect(quarantine.LiftedBy).To(Equal("user-1"))
+		})
+
+		It("should not lift a quarantine of another project", func() {
+			mockRepo.On("GetByID", ctx, uint(7)).Return(&domain.Quarantine{ID: 7, ProjectID: "project-2"}, nil)
+
+			_, err := service.LiftQuarantine(ctx, "project-1", 7, "user-1")
+
+			Expect(err).To(MatchError(domain.ErrQuarantineNotFound))
+			mockRepo.AssertNotCalled(GinkgoT(), "Lift", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	})
+
+	Describe("FormatQuarantines", func() {
+		var quarantines []*domain.Quarantine
+
+		BeforeEach(func() {
+			quarantines = []*domain.Quarantine{
+				{TestCaseID: 42, TestCase: testCase},
+				{TestCaseID: 43, TestCase: &domain.TestCase{ID: 43, ClassName: "com.example.CartTest", Name: "removesItem"}},
+			}
+		})
+
+		It("should build a Ginkgo skip expression matching the specs of quarantined tests", func() {
+			quarantines = append(quarantines, &domain.Quarantine{TestCaseID: 44, TestCase: &domain.TestCase{ID: 44, Name: "charges $5 (once)"}})
+
+			body, contentType, err := application.FormatQuarantines(application.QuarantineFormatGinkgoSkip, quarantines)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(body).To(Equal(`(^| )adds an item$|(^| )removesItem$|(^| )charges \$5 \(once\)$`))
+			Expect(contentType).To(HavePrefix("text/plain"))
+
+			// Ginkgo matches the expression against the texts of a spec's containers and its own
+			skip := regexp.MustCompile(body)
+			Expect(skip.MatchString("Cart adds an item")).To(BeTrue())
+			Expect(skip.MatchString("adds an item")).To(BeTrue())
+			Expect(skip.MatchString("Checkout charges $5 (once)")).To(BeTrue())
+			Expect(skip.MatchString("Cart readds an item")).To(BeFalse())
+			Expect(skip.MatchString("Cart adds an item twice")).To(BeFalse())
+		})
+
+		It("should leave the skip expression empty when nothing is quarantined", func() {
+			body, _, err := application.FormatQuarantines(application.QuarantineFormatGinkgoSkip, nil)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(body).To(BeEmpty())
+		})
+
+		It("should list JUnit exclude patterns, falling back to the suite name", func() {
+			body, _, err := application.FormatQuarantines(application.QuarantineFormatJUnitExclude, quarantines)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(body).To(Equal("Cart#adds an item\ncom.example.CartTest#removesItem\n"))
+		})
+
+		It("should reject unknown formats", func() {
+			_, _, err := application.FormatQuarantines("xml", quarantines)
+
+			Expect(err).To(MatchError(application.ErrUnknownQuarantineFormat))
+		})
+	})
+})
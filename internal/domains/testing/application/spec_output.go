@@ -0,0 +1,140 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+const (
+	// DefaultMaxSpecOutputSize is how many bytes of each spec's stdout and stderr are kept
+	DefaultMaxSpecOutputSize = 1 << 20
+
+	// DefaultSpecOutputSearchWindow is how far back output searches look by default
+	DefaultSpecOutputSearchWindow = 7 * 24 * time.Hour
+
+	// MaxSpecOutputSearchWindow is how far back output searches may look
+	MaxSpecOutputSearchWindow = 90 * 24 * time.Hour
+
+	defaultSpecOutputSearchLimit = 50
+	maxSpecOutputSearchLimit     = 200
+
+	// maxSnippetLineLength bounds each line of a search result snippet
+	maxSnippetLineLength = 200
+)
+
+// ErrSpecOutputUnavailable is returned when spec output cannot be read back
+var ErrSpecOutputUnavailable = errors.New("spec output is not available")
+
+// GetSpecOutput retrieves the stdout and stderr captured for a spec run
+func (s *TestRunService) GetSpecOutput(ctx context.Context, specRunID uint) (*domain.SpecOutput, error) {
+	if s.specOutputRepo == nil {
+		return nil, ErrSpecOutputUnavailable
+	}
+	return s.specOutputRepo.GetBySpecRunID(ctx, specRunID)
+}
+
+// SearchSpecOutput finds a project's recent spec runs whose output matches a full-text query,
+// newest first. Searches look back DefaultSpecOutputSearchWindow unless Since is set, and never
+// further than MaxSpecOutputSearchWindow.
+func (s *TestRunService) SearchSpecOutput(ctx context.Context, search domain.SpecOutputSearch) ([]*domain.SpecOutputMatch, error) {
+	if s.specOutputRepo == nil {
+		return nil, ErrSpecOutputUnavailable
+	}
+	if search.ProjectID == "" {
+		return nil, fmt.Errorf("project ID is required")
+	}
+	search.Query = strings.TrimSpace(search.Query)
+	if search.Query == "" {
+		return nil, fmt.Errorf("search query is required")
+	}
+
+	now := time.Now()
+	if search.Since.IsZero() {
+		search.Since = now.Add(-DefaultSpecOutputSearchWindow)
+	}
+	if earliest := now.Add(-MaxSpecOutputSearchWindow); search.Since.Before(earliest) {
+		search.Since = earliest
+	}
+	if search.Limit <= 0 {
+		search.Limit = defaultSpecOutputSearchLimit
+	}
+	if search.Limit > maxSpecOutputSearchLimit {
+		search.Limit = maxSpecOutputSearchLimit
+	}
+
+	matches, err := s.specOutputRepo.Search(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := searchTerms(search.Query)
+	for _, match := range matches {
+		if match.Output != nil {
+			match.Snippet = outputSnippet(match.Output, terms)
+		}
+	}
+	return matches, nil
+}
+
+// searchTerms extracts the words and quoted phrases a web search query looks for, leaving out
+// excluded terms and the "or" operator
+func searchTerms(query string) []string {
+	var terms []string
+	for i, part := range strings.Split(query, `"`) {
+		if i%2 == 1 {
+			if phrase := strings.ToLower(strings.Join(strings.Fields(part), " ")); phrase != "" {
+				terms = append(terms, phrase)
+			}
+			continue
+		}
+		for _, word := range strings.Fields(part) {
+			word = strings.ToLower(word)
+			if word == "or" || strings.HasPrefix(word, "-") {
+				continue
+			}
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+// outputSnippet returns the first line of output containing one of the terms, together with
+// the lines either side of it
+func outputSnippet(output *domain.SpecOutput, terms []string) string {
+	for _, stream := range []string{output.Stdout, output.Stderr} {
+		lines := strings.Split(stream, "\n")
+		for i, line := range lines {
+			lower := strings.ToLower(strings.Join(strings.Fields(line), " "))
+			for _, term := range terms {
+				if !strings.Contains(lower, term) {
+					continue
+				}
+				start, end := max(i-1, 0), min(i+2, len(lines))
+				snippet := make([]string, 0, end-start)
+				for _, l := range lines[start:end] {
+					snippet = append(snippet, snippetLine(l))
+				}
+				return strings.Join(snippet, "\n")
+			}
+		}
+	}
+	return ""
+}
+
+// snippetLine shortens a line of output for display
+func snippetLine(line string) string {
+	line = strings.TrimRight(line, " \t\r")
+	if len(line) <= maxSnippetLineLength {
+		return line
+	}
+	cut := maxSnippetLineLength
+	for cut > 0 && line[cut]&0xC0 == 0x80 {
+		cut--
+	}
+	return line[:cut] + "…"
+}
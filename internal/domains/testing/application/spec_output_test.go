@@ -0,0 +1,157 @@
+package application_test
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// MockSpecOutputRepository is a mock implementation of domain.SpecOutputRepository
+type MockSpecOutputRepository struct {
+	mock.Mock
+}
+
+func (m *MockSpecOutputRepository) GetBySpecRunID(ctx context.Context, specRunID uint) (*domain.SpecOutput, error) {
+	args := m.Called(ctx, specRunID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SpecOutput), args.Error(1)
+}
+
+func (m *MockSpecOutputRepository) Search(ctx context.Context, search domain.SpecOutputSearch) ([]*domain.SpecOutputMatch, error) {
+	args := m.Called(ctx, search)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.SpecOutputMatch), args.Error(1)
+}
+
+var _ = Describe("Spec output", Label("unit", "application", "testing"), func() {
+	var (
+		service         *application.TestRunService
+		mockTestRunRepo *MockTestRunRepository
+		mockOutputRepo  *MockSpecOutputRepository
+		ctx             context.Context
+	)
+
+	BeforeEach(func() {
+		mockTestRunRepo = new(MockTestRunRepository)
+		mockOutputRepo = new(MockSpecOutputRepository)
+		service = application.NewTestRunService(mockTestRunRepo, new(MockSuiteRunRepository), new(MockSpecRunRepository))
+		service.SetSpecOutputRepository(mockOutputRepo)
+		ctx = context.Background()
+	})
+
+	Describe("IngestTestRun", func() {
+		It("should truncate spec output to the configured size", func() {
+			service.SetMaxSpecOutputSize(100)
+			mockTestRunRepo.On("CreateWithHierarchy", ctx, mock.Anything, []string(nil)).Return(nil).Once()
+
+			testRun := &domain.TestRun{
+				ProjectID: "proj-1",
+				StartTime: time.Now(),
+				SuiteRuns: []domain.SuiteRun{{
+					Name: "checkout",
+					SpecRuns: []*domain.SpecRun{
+						{Name: "adds", Status: "passed", Output: domain.NewSpecOutput(strings.Repeat("x", 1000), "")},
+						{Name: "removes", Status: "passed"},
+					},
+				}},
+			}
+			Expect(service.IngestTestRun(ctx, testRun, nil)).To(Succeed())
+
+			output := testRun.SuiteRuns[0].SpecRuns[0].Output
+			Expect(len(output.Stdout)).To(BeNumerically("<", 200))
+			Expect(output.StdoutSize).To(Equal(int64(1000)))
+			Expect(output.Truncated).To(BeTrue())
+		})
+	})
+
+	Describe("GetSpecOutput", func() {
+		It("should report output as unavailable without a repository", func() {
+			service = application.NewTestRunService(mockTestRunRepo, new(MockSuiteRunRepository), new(MockSpecRunRepository))
+
+			_, err := service.GetSpecOutput(ctx, 1)
+
+			Expect(err).To(MatchError(application.ErrSpecOutputUnavailable))
+		})
+
+		It("should pass through missing output", func() {
+			mockOutputRepo.On("GetBySpecRunID", ctx, uint(7)).Return(nil, domain.ErrSpecOutputNotFound).Once()
+
+			_, err := service.GetSpecOutput(ctx, 7)
+
+			Expect(err).To(MatchError(domain.ErrSpecOutputNotFound))
+		})
+	})
+
+	Describe("SearchSpecOutput", func() {
+		It("should require a project and a query", func() {
+			_, err := service.SearchSpecOutput(ctx, domain.SpecOutputSearch{Query: "timeout"})
+			Expect(err).To(MatchError(ContainSubstring("project ID is required")))
+
+			_, err = service.SearchSpecOutput(ctx, domain.SpecOutputSearch{ProjectID: "proj-1", Query: "  "})
+			Expect(err).To(MatchError(ContainSubstring("search query is required")))
+		})
+
+		It("should default and cap the window and limit", func() {
+			var searches []domain.SpecOutputSearch
+			mockOutputRepo.On("Search", ctx, mock.Anything).Run(func(args mock.Arguments) {
+				searches = append(searches, args.Get(1).(domain.SpecOutputSearch))
+			}).Return([]*domain.SpecOutputMatch{}, nil)
+
+			_, err := service.SearchSpecOutput(ctx, domain.SpecOutputSearch{ProjectID: "proj-1", Query: " timeout "})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = service.SearchSpecOutput(ctx, domain.SpecOutputSearch{
+				ProjectID: "proj-1",
+				Query:     "timeout",
+				Since:     time.Now().AddDate(-1, 0, 0),
+				Limit:     1000,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(searches[0].Query).To(Equal("timeout"))
+			Expect(searches[0].Since).To(BeTemporally("~", time.Now().Add(-application.DefaultSpecOutputSearchWindow), time.Minute))
+			Expect(searches[0].Limit).To(Equal(50))
+			Expect(searches[1].Since).To(BeTemporally("~", time.Now().Add(-application.MaxSpecOutputSearchWindow), time.Minute))
+			Expect(searches[1].Limit).To(Equal(200))
+		})
+
+		It("should return the lines around the first match", func() {
+			match := &domain.SpecOutputMatch{
+				SpecRun: &domain.SpecRun{ID: 3, Name: "charges"},
+				Output: &domain.SpecOutput{
+					Stdout: "connecting\nopening pool\nrequest   timed out after 5s\nretrying\ngave up",
+				},
+			}
+			mockOutputRepo.On("Search", ctx, mock.Anything).Return([]*domain.SpecOutputMatch{match}, nil).Once()
+
+			matches, err := service.SearchSpecOutput(ctx, domain.SpecOutputSearch{ProjectID: "proj-1", Query: `-retrying "Timed Out"`})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matches).To(HaveLen(1))
+			Expect(matches[0].Snippet).To(Equal("opening pool\nrequest   timed out after 5s\nretrying"))
+		})
+
+		It("should look for matches in stderr", func() {
+			match := &domain.SpecOutputMatch{
+				SpecRun: &domain.SpecRun{ID: 3},
+				Output:  &domain.SpecOutput{Stdout: "ok", Stderr: "panic: nil map"},
+			}
+			mockOutputRepo.On("Search", ctx, mock.Anything).Return([]*domain.SpecOutputMatch{match}, nil).Once()
+
+			matches, err := service.SearchSpecOutput(ctx, domain.SpecOutputSearch{ProjectID: "proj-1", Query: "panic"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matches[0].Snippet).To(Equal("panic: nil map"))
+		})
+	})
+})
@@ -0,0 +1,119 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+const (
+	// DefaultTestHistoryWindow is how far back test histories look by default
+	DefaultTestHistoryWindow = 30 * 24 * time.Hour
+
+	// MaxTestHistoryWindow is how far back test histories may look
+	MaxTestHistoryWindow = 365 * 24 * time.Hour
+
+	defaultTestHistoryLimit = 200
+	maxTestHistoryLimit     = 1000
+
+	defaultTestCaseListLimit = 50
+	maxTestCaseListLimit     = 500
+
+	// testCaseBackfillBatchSize is the number of spec runs linked per backfill query
+	testCaseBackfillBatchSize = 1000
+)
+
+// ErrTestCasesUnavailable is returned when test cases cannot be read back
+var ErrTestCasesUnavailable = errors.New("test cases are not available")
+
+// GetTestCase retrieves a test case by ID
+func (s *TestRunService) GetTestCase(ctx context.Context, id uint) (*domain.TestCase, error) {
+	if s.testCaseRepo == nil {
+		return nil, ErrTestCasesUnavailable
+	}
+	return s.testCaseRepo.GetByID(ctx, id)
+}
+
+// ListTestCases retrieves a project's test cases whose name contains search, most recently
+// seen first
+func (s *TestRunService) ListTestCases(ctx context.Context, projectID string, search string, limit int) ([]*domain.TestCase, error) {
+	if s.testCaseRepo == nil {
+		return nil, ErrTestCasesUnavailable
+	}
+	if projectID == "" {
+		return nil, fmt.Errorf("project ID is required")
+	}
+	if limit <= 0 {
+		limit = defaultTestCaseListLimit
+	}
+	if limit > maxTestCaseListLimit {
+		limit = maxTestCaseListLimit
+	}
+	return s.testCaseRepo.FindByProject(ctx, projectID, strings.TrimSpace(search), limit)
+}
+
+// GetTestCaseHistory retrieves how a test case behaved over time across branches and
+// environments, newest first. The history of a parameterised test rolls up all of its
+// parameter sets. Histories look back DefaultTestHistoryWindow unless Since is set,
+// and never further than MaxTestHistoryWindow.
+func (s *TestRunService) GetTestCaseHistory(ctx context.Context, testCaseID uint, filter domain.TestCaseHistoryFilter) (*domain.TestCaseHistory, error) {
+	if s.testCaseRepo == nil {
+		return nil, ErrTestCasesUnavailable
+	}
+
+	testCase, err := s.testCaseRepo.GetByID(ctx, testCaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if filter.Since.IsZero() {
+		filter.Since = now.Add(-DefaultTestHistoryWindow)
+	}
+	if earliest := now.Add(-MaxTestHistoryWindow); filter.Since.Before(earliest) {
+		filter.Since = earliest
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultTestHistoryLimit
+	}
+	if filter.Limit > maxTestHistoryLimit {
+		filter.Limit = maxTestHistoryLimit
+	}
+
+	executions, err := s.testCaseRepo.GetHistory(ctx, testCaseID, filter)
+	if err != nil {
+		return nil, err
+	}
+	history := domain.NewTestCaseHistory(testCase, executions)
+
+	if history.ParameterSets, err = s.testCaseRepo.FindParameterSets(ctx, testCaseID); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// BackfillTestCases links spec runs stored before test cases existed to their test cases.
+// It returns the number of spec runs linked.
+func (s *TestRunService) BackfillTestCases(ctx context.Context) (int, error) {
+	if s.testCaseRepo == nil {
+		return 0, ErrTestCasesUnavailable
+	}
+
+	linked := 0
+	var afterID uint
+	for {
+		lastID, n, err := s.testCaseRepo.LinkSpecRuns(ctx, afterID, testCaseBackfillBatchSize)
+		linked += n
+		if err != nil {
+			return linked, fmt.Errorf("failed to link spec runs after %d: %w", afterID, err)
+		}
+		if lastID == 0 {
+			return linked, nil
+		}
+		afterID = lastID
+	}
+}
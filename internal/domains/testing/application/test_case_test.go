@@ -0,0 +1,191 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// MockTestCaseRepository is a mock implementation of domain.TestCaseRepository
+type MockTestCaseRepository struct {
+	mock.Mock
+}
+
+func (m *MockTestCaseRepository) GetByID(ctx context.Context, id uint) (*domain.TestCase, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TestCase), args.Error(1)
+}
+
+func (m *MockTestCaseRepository) FindByProject(ctx context.Context, projectID string, search string, limit int) ([]*domain.TestCase, error) {
+	args := m.Called(ctx, projectID, search, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.TestCase), args.Error(1)
+}
+
+func (m *MockTestCaseRepository) FindParameterSets(ctx context.Context, parentID uint) ([]*domain.TestCase, error) {
+	args := m.Called(ctx, parentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.TestCase), args.Error(1)
+}
+
+func (m *MockTestCaseRepository) GetHistory(ctx context.Context, testCaseID uint, filter domain.TestCaseHistoryFilter) ([]domain.TestCaseExecution, error) {
+	args := m.Called(ctx, testCaseID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.TestCaseExecution), args.Error(1)
+}
+
+func (m *MockTestCaseRepository) LinkSpecRuns(ctx context.Context, afterID uint, limit int) (uint, int, error) {
+	args := m.Called(ctx, afterID, limit)
+	return args.Get(0).(uint), args.Int(1), args.Error(2)
+}
+
+var _ = Describe("Test cases", Label("unit", "application", "testing"), func() {
+	var (
+		service          *application.TestRunService
+		mockTestCaseRepo *MockTestCaseRepository
+		ctx              context.Context
+	)
+
+	BeforeEach(func() {
+		mockTestCaseRepo = new(MockTestCaseRepository)
+		service = application.NewTestRunService(new(MockTestRunRepository), new(MockSuiteRunRepository), new(MockSpecRunRepository))
+		service.SetTestCaseRepository(mockTestCaseRepo)
+		ctx = context.Background()
+	})
+
+	Describe("GetTestCaseHistory", func() {
+		It("should look back over the default window", func() {
+			testCase := &domain.TestCase{ID: 7, Name: "adds an item"}
+			mockTestCaseRepo.On("GetByID", ctx, uint(7)).Return(testCase, nil)
+			mockTestCaseRepo.On("GetHistory", ctx, uint(7), mock.MatchedBy(func(filter domain.TestCaseHistoryFilter) bool {
+				window := time.Since(filter.Since)
+				return filter.Branch == "main" && filter.Limit == 200 &&
+					window >= application.DefaultTestHistoryWindow && window < application.DefaultTestHistoryWindow+time.Minute
+			})).Return([]domain.TestCaseExecution{{Status: "passed", Branch: "main"}}, nil)
+			mockTestCaseRepo.On("FindParameterSets", ctx, uint(7)).Return([]*domain.TestCase{}, nil)
+
+			history, err := service.GetTestCaseHistory(ctx, 7, domain.TestCaseHistoryFilter{Branch: "main"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history.TestCase).To(Equal(testCase))
+			Expect(history.Summary.Passed).To(Equal(1))
+			mockTestCaseRepo.AssertExpectations(GinkgoT())
+		})
+
+		It("should clamp the window and limit", func() {
+			mockTestCaseRepo.On("GetByID", ctx, uint(7)).Return(&domain.TestCase{ID: 7}, nil)
+			mockTestCaseRepo.On("GetHistory", ctx, uint(7), mock.MatchedBy(func(filter domain.TestCaseHistoryFilter) bool {
+				return filter.Limit == 1000 && time.Since(filter.Since) < application.MaxTestHistoryWindow+time.Minute
+			})).Return([]domain.TestCaseExecution{}, nil)
+			mockTestCaseRepo.On("FindParameterSets", ctx, uint(7)).Return([]*domain.TestCase{}, nil)
+
+			_, err := service.GetTestCaseHistory(ctx, 7, domain.TestCaseHistoryFilter{
+				Since: time.Now().AddDate(-5, 0, 0),
+				Limit: 50000,
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			mockTestCaseRepo.AssertExpectations(GinkgoT())
+		})
+
+		It("should roll up the parameter sets of a parameterised test", func() {
+			parent := &domain.TestCase{ID: 7, Name: "TestAdd"}
+			parameterSets := []*domain.TestCase{
+				{ID: 8, ParentID: &parent.ID, Name: "TestAdd/negative", Parameters: map[string]string{"subtest": "negative"}},
+				{ID: 9, ParentID: &parent.ID, Name: "TestAdd/zero", Parameters: map[string]string{"subtest": "zero"}},
+			}
+			mockTestCaseRepo.On("GetByID", ctx, uint(7)).Return(parent, nil)
+			mockTestCaseRepo.On("GetHistory", ctx, uint(7), mock.Anything).Return([]domain.TestCaseExecution{
+				{TestCaseID: 8, Status: "failed", Parameters: parameterSets[0].Parameters},
+				{TestCaseID: 9, Status: "passed", Parameters: parameterSets[1].Parameters},
+				{TestCaseID: 8, Status: "passed", Parameters: parameterSets[0].Parameters},
+			}, nil)
+			mockTestCaseRepo.On("FindParameterSets", ctx, uint(7)).Return(parameterSets, nil)
+
+			history, err := service.GetTestCaseHistory(ctx, 7, domain.TestCaseHistoryFilter{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history.Summary.Total).To(Equal(3))
+			Expect(history.Summary.Failed).To(Equal(1))
+			Expect(history.ParameterSets).To(Equal(parameterSets))
+			Expect(history.ByParameters).To(HaveLen(2))
+			Expect(history.ByParameters[0].Key).To(Equal("subtest=negative"))
+			Expect(history.ByParameters[0].PassRate).To(Equal(50.0))
+			Expect(history.ByParameters[1].Key).To(Equal("subtest=zero"))
+		})
+
+		It("should return not found for an unknown test case", func() {
+			mockTestCaseRepo.On("GetByID", ctx, uint(8)).Return(nil, domain.ErrTestCaseNotFound)
+
+			_, err := service.GetTestCaseHistory(ctx, 8, domain.TestCaseHistoryFilter{})
+
+			Expect(err).To(MatchError(domain.ErrTestCaseNotFound))
+			mockTestCaseRepo.AssertNotCalled(GinkgoT(), "GetHistory", mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		It("should fail without a test case repository", func() {
+			service = application.NewTestRunService(new(MockTestRunRepository), new(MockSuiteRunRepository), new(MockSpecRunRepository))
+
+			_, err := service.GetTestCaseHistory(ctx, 7, domain.TestCaseHistoryFilter{})
+
+			Expect(err).To(MatchError(application.ErrTestCasesUnavailable))
+		})
+	})
+
+	Describe("ListTestCases", func() {
+		It("should require a project", func() {
+			_, err := service.ListTestCases(ctx, "", "", 0)
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should default the limit and trim the search", func() {
+			mockTestCaseRepo.On("FindByProject", ctx, "proj-1", "cart", 50).Return([]*domain.TestCase{{ID: 1}}, nil)
+
+			testCases, err := service.ListTestCases(ctx, "proj-1", "  cart ", 0)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testCases).To(HaveLen(1))
+		})
+	})
+
+	Describe("BackfillTestCases", func() {
+		It("should link spec runs batch by batch until none are left", func() {
+			mockTestCaseRepo.On("LinkSpecRuns", ctx, uint(0), 1000).Return(uint(1500), 1000, nil).Once()
+			mockTestCaseRepo.On("LinkSpecRuns", ctx, uint(1500), 1000).Return(uint(1800), 250, nil).Once()
+			mockTestCaseRepo.On("LinkSpecRuns", ctx, uint(1800), 1000).Return(uint(0), 0, nil).Once()
+
+			linked, err := service.BackfillTestCases(ctx)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(linked).To(Equal(1250))
+			mockTestCaseRepo.AssertExpectations(GinkgoT())
+		})
+
+		It("should report what was linked before a failure", func() {
+			mockTestCaseRepo.On("LinkSpecRuns", ctx, uint(0), 1000).Return(uint(1500), 1000, nil).Once()
+			mockTestCaseRepo.On("LinkSpecRuns", ctx, uint(1500), 1000).Return(uint(0), 0, errors.New("connection reset")).Once()
+
+			linked, err := service.BackfillTestCases(ctx)
+
+			Expect(err).To(MatchError(ContainSubstring("connection reset")))
+			Expect(linked).To(Equal(1000))
+		})
+	})
+})
@@ -0,0 +1,295 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// Report formats accepted by ImportReport
+const (
+	ReportFormatJUnit    = "junit"
+	ReportFormatGoTest   = "go-test"
+	ReportFormatTestRun  = "test-run"
+	ReportFormatTRX      = "trx"
+	ReportFormatNUnit    = "nunit"
+	ReportFormatXUnit    = "xunit"
+	ReportFormatCucumber = "cucumber"
+	ReportFormatAllure   = "allure"
+	ReportFormatCTRF     = "ctrf"
+)
+
+// ImportOptions carries run-level details that report formats usually don't contain
+type ImportOptions struct {
+	ProjectID   string
+	RunID       string
+	Branch      string
+	GitCommit   string
+	Environment string
+	Metadata    map[string]interface{}
+
+	// Shard is set when the report is one shard of a run split across parallel CI jobs
+	Shard *domain.ShardInfo
+}
+
+// InvalidReportError indicates that an uploaded report could not be parsed
+type InvalidReportError struct {
+	Err error
+}
+
+func (e *InvalidReportError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *InvalidReportError) Unwrap() error {
+	return e.Err
+}
+
+// IsSupportedReportFormat returns true if ImportReport can parse the given format
+func IsSupportedReportFormat(format string) bool {
+	switch format {
+	case ReportFormatJUnit, ReportFormatGoTest, ReportFormatTestRun,
+		ReportFormatTRX, ReportFormatNUnit, ReportFormatXUnit, ReportFormatCucumber, ReportFormatAllure, ReportFormatCTRF:
+		return true
+	default:
+		return false
+	}
+}
+
+// ImportReport parses a report in the given format and records it as a new test run
+func (s *TestRunService) ImportReport(ctx context.Context, format string, r io.Reader, opts ImportOptions) (*domain.TestRun, error) {
+	switch format {
+	case ReportFormatJUnit:
+		return s.ImportJUnitXML(ctx, r, opts)
+	case ReportFormatGoTest:
+		return s.ImportGoTestJSON(ctx, r, opts)
+	case ReportFormatTestRun:
+		return s.ImportTestRunJSON(ctx, r, opts)
+	case ReportFormatTRX:
+		return s.ImportTRX(ctx, r, opts)
+	case ReportFormatNUnit:
+		return s.ImportNUnitXML(ctx, r, opts)
+	case ReportFormatXUnit:
+		return s.ImportXUnitXML(ctx, r, opts)
+	case ReportFormatCucumber:
+		return s.ImportCucumberJSON(ctx, r, opts)
+	case ReportFormatAllure:
+		return s.ImportAllureResults(ctx, r, opts)
+	case ReportFormatCTRF:
+		return s.ImportCTRF(ctx, r, opts)
+	default:
+		return nil, &InvalidReportError{Err: fmt.Errorf("unsupported report format %q", format)}
+	}
+}
+
+// importTestRun applies the import options to a parsed test run and persists the whole hierarchy
+func (s *TestRunService) importTestRun(ctx context.Context, testRun *domain.TestRun, opts ImportOptions) error {
+	if opts.ProjectID == "" {
+		return fmt.Errorf("project ID is required")
+	}
+
+	testRun.ProjectID = opts.ProjectID
+	testRun.RunID = opts.RunID
+	if testRun.RunID == "" {
+		testRun.RunID = uuid.New().String()
+	}
+	// Query parameters take precedence over build details recorded in the report itself
+	if opts.Branch != "" {
+		testRun.Branch = opts.Branch
+		testRun.GitBranch = opts.Branch
+	}
+	if opts.GitCommit != "" {
+		testRun.GitCommit = opts.GitCommit
+	}
+	if opts.Environment != "" {
+		testRun.Environment = opts.Environment
+	}
+
+	if testRun.Metadata == nil {
+		testRun.Metadata = make(map[string]interface{})
+	}
+	for k, v := range opts.Metadata {
+		testRun.Metadata[k] = v
+	}
+
+	// Reports without timestamps are recorded as ending now
+	if testRun.StartTime.IsZero() {
+		now := time.Now()
+		testRun.StartTime = now.Add(-testRun.Duration)
+		testRun.EndTime = &now
+		offset := testRun.StartTime
+		for i := range testRun.SuiteRuns {
+			backfillSuiteTimes(&testRun.SuiteRuns[i], offset)
+			offset = testRun.SuiteRuns[i].StartTime.Add(testRun.SuiteRuns[i].Duration)
+		}
+	}
+
+	if s.attachments != nil {
+		if err := s.attachments.storeImportedAttachments(ctx, testRun); err != nil {
+			return err
+		}
+	}
+
+	if opts.Shard != nil {
+		return s.IngestShard(ctx, testRun, *opts.Shard, nil)
+	}
+	return s.IngestTestRun(ctx, testRun, nil)
+}
+
+// IngestTestRun records a finished test run with all of its suites, specs and tags
+// in a single transaction. Suite and run counters are derived from the specs.
+func (s *TestRunService) IngestTestRun(ctx context.Context, testRun *domain.TestRun, tagNames []string) error {
+	if testRun.ProjectID == "" {
+		return fmt.Errorf("project ID is required")
+	}
+	if testRun.RunID == "" {
+		testRun.RunID = uuid.New().String()
+	}
+
+	summariseTestRun(testRun)
+	s.prepareTestRun(testRun)
+
+	if err := s.testRunRepo.CreateWithHierarchy(ctx, testRun, tagNames); err != nil {
+		return fmt.Errorf("failed to record test run: %w", err)
+	}
+
+	s.publishCompleted(ctx, testRun)
+	return nil
+}
+
+// IngestShard merges one shard of a run split across parallel CI jobs into the run for its
+// group, creating that run for the first shard. The merged run stays running until every
+// shard has been received or FinalizeTimedOutShardGroups closes it. On return testRun holds
+// the merged run's counters and status.
+func (s *TestRunService) IngestShard(ctx context.Context, testRun *domain.TestRun, shard domain.ShardInfo, tagNames []string) error {
+	if testRun.ProjectID == "" {
+		return fmt.Errorf("project ID is required")
+	}
+	if err := shard.Validate(); err != nil {
+		return &InvalidReportError{Err: err}
+	}
+
+	// Summarise the shard on its own; the repository merges it into the group's totals
+	testRun.RunID = shard.GroupID
+	testRun.Status = ""
+	summariseTestRun(testRun)
+	s.prepareTestRun(testRun)
+
+	if err := s.testRunRepo.MergeShard(ctx, testRun, shard, tagNames); err != nil {
+		return fmt.Errorf("failed to merge shard: %w", err)
+	}
+
+	s.publishCompleted(ctx, testRun)
+	return nil
+}
+
+// FinalizeTimedOutShardGroups closes sharded runs that have been waiting for shards for
+// longer than timeout. Runs closed with shards missing are marked failed.
+func (s *TestRunService) FinalizeTimedOutShardGroups(ctx context.Context, timeout time.Duration) (int, error) {
+	return s.testRunRepo.FinalizeShardGroups(ctx, time.Now().Add(-timeout))
+}
+
+// summariseTestRun recomputes a run's counters from its suites and fills in its
+// status and duration when they were not reported
+func summariseTestRun(testRun *domain.TestRun) {
+	testRun.TotalTests, testRun.PassedTests, testRun.FailedTests, testRun.SkippedTests = 0, 0, 0, 0
+	for i := range testRun.SuiteRuns {
+		suite := &testRun.SuiteRuns[i]
+		summariseSuiteRun(suite)
+
+		testRun.TotalTests += suite.TotalTests
+		testRun.PassedTests += suite.PassedTests
+		testRun.FailedTests += suite.FailedTests
+		testRun.SkippedTests += suite.SkippedTests
+	}
+
+	if testRun.Status == "" {
+		testRun.Status = "passed"
+		if testRun.FailedTests > 0 {
+			testRun.Status = "failed"
+		}
+	}
+	if testRun.Duration == 0 && testRun.EndTime != nil {
+		testRun.Duration = testRun.EndTime.Sub(testRun.StartTime)
+	}
+}
+
+// summariseSuiteRun recomputes a suite's counters from its specs and fills in
+// its status and duration when they were not reported
+func summariseSuiteRun(suite *domain.SuiteRun) {
+	status := suite.Status
+	specs := suite.SpecRuns
+
+	suite.TotalTests, suite.PassedTests, suite.FailedTests, suite.SkippedTests = 0, 0, 0, 0
+	suite.Status = "passed"
+	suite.SpecRuns = make([]*domain.SpecRun, 0, len(specs))
+	for _, spec := range specs {
+		appendSpecRun(suite, spec)
+	}
+
+	if status != "" {
+		suite.Status = status
+	}
+	if suite.Duration == 0 && suite.EndTime != nil {
+		suite.Duration = suite.EndTime.Sub(suite.StartTime)
+	}
+}
+
+// backfillSuiteTimes fills in missing start/end times for a suite and its specs,
+// laying specs out sequentially from the suite start
+func backfillSuiteTimes(suite *domain.SuiteRun, startTime time.Time) {
+	if suite.StartTime.IsZero() {
+		suite.StartTime = startTime
+		endTime := startTime.Add(suite.Duration)
+		suite.EndTime = &endTime
+	}
+
+	offset := suite.StartTime
+	for _, spec := range suite.SpecRuns {
+		if spec.StartTime.IsZero() {
+			spec.StartTime = offset
+			endTime := offset.Add(spec.Duration)
+			spec.EndTime = &endTime
+		}
+		offset = spec.StartTime.Add(spec.Duration)
+	}
+}
+
+// summariseSuiteTimes times a suite from its specs: from the earliest start to the latest
+// end when the report timestamps its tests, otherwise as the sum of their durations
+func summariseSuiteTimes(suite *domain.SuiteRun) {
+	var endTime time.Time
+	for _, spec := range suite.SpecRuns {
+		suite.Duration += spec.Duration
+		if !spec.StartTime.IsZero() && (suite.StartTime.IsZero() || spec.StartTime.Before(suite.StartTime)) {
+			suite.StartTime = spec.StartTime
+		}
+		if spec.EndTime != nil && spec.EndTime.After(endTime) {
+			endTime = *spec.EndTime
+		}
+	}
+	if !suite.StartTime.IsZero() && !endTime.IsZero() {
+		suite.Duration = endTime.Sub(suite.StartTime)
+		suite.EndTime = &endTime
+	}
+}
+
+// appendSpecRun adds a spec to a suite, updating the suite counters and marking
+// the suite failed when the spec failed
+func appendSpecRun(suite *domain.SuiteRun, spec *domain.SpecRun) {
+	suite.TotalTests++
+	switch spec.Status {
+	case "passed":
+		suite.PassedTests++
+	case "failed":
+		suite.FailedTests++
+		suite.Status = "failed"
+	case "skipped":
+		suite.SkippedTests++
+	}
+	suite.SpecRuns = append(suite.SpecRuns, spec)
+}
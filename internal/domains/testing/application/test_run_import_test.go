@@ -0,0 +1,239 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/events"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// recordingPublisher collects the events a service publishes
+type recordingPublisher struct {
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, event events.Event) {
+	p.events = append(p.events, event)
+}
+
+var _ = Describe("IngestTestRun", Label("unit", "application", "testing"), func() {
+	var (
+		service         *application.TestRunService
+		mockTestRunRepo *MockTestRunRepository
+		ctx             context.Context
+		startTime       time.Time
+	)
+
+	BeforeEach(func() {
+		mockTestRunRepo = new(MockTestRunRepository)
+		service = application.NewTestRunService(mockTestRunRepo, new(MockSuiteRunRepository), new(MockSpecRunRepository))
+		ctx = context.Background()
+		startTime = time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	})
+
+	newTestRun := func() *domain.TestRun {
+		endTime := startTime.Add(5 * time.Minute)
+		suiteEnd := startTime.Add(2 * time.Second)
+		return &domain.TestRun{
+			ProjectID: "proj-1",
+			StartTime: startTime,
+			EndTime:   &endTime,
+			SuiteRuns: []domain.SuiteRun{
+				{
+					Name:      "checkout",
+					StartTime: startTime,
+					EndTime:   &suiteEnd,
+					SpecRuns: []*domain.SpecRun{
+						{Name: "adds", Status: "passed"},
+						{Name: "removes", Status: "failed"},
+						{Name: "coupons", Status: "skipped"},
+					},
+				},
+				{
+					Name:     "payments",
+					SpecRuns: []*domain.SpecRun{{Name: "charges", Status: "passed"}},
+				},
+			},
+		}
+	}
+
+	It("should derive counters and status before writing the hierarchy in one call", func() {
+		mockTestRunRepo.On("CreateWithHierarchy", ctx, mock.Anything, []string{"nightly"}).Return(nil).Once()
+
+		testRun := newTestRun()
+		err := service.IngestTestRun(ctx, testRun, []string{"nightly"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(testRun.RunID).NotTo(BeEmpty())
+		Expect(testRun.Status).To(Equal("failed"))
+		Expect(testRun.Duration).To(Equal(5 * time.Minute))
+		Expect(testRun.TotalTests).To(Equal(4))
+		Expect(testRun.PassedTests).To(Equal(2))
+		Expect(testRun.FailedTests).To(Equal(1))
+		Expect(testRun.SkippedTests).To(Equal(1))
+
+		Expect(testRun.SuiteRuns[0].Status).To(Equal("failed"))
+		Expect(testRun.SuiteRuns[0].Duration).To(Equal(2 * time.Second))
+		Expect(testRun.SuiteRuns[1].Status).To(Equal("passed"))
+		Expect(testRun.SuiteRuns[1].TotalTests).To(Equal(1))
+
+		mockTestRunRepo.AssertExpectations(GinkgoT())
+	})
+
+	It("should keep an explicitly reported status", func() {
+		mockTestRunRepo.On("CreateWithHierarchy", ctx, mock.Anything, []string(nil)).Return(nil).Once()
+
+		testRun := newTestRun()
+		testRun.Status = "completed"
+		Expect(service.IngestTestRun(ctx, testRun, nil)).To(Succeed())
+		Expect(testRun.Status).To(Equal("completed"))
+	})
+
+	It("should mark specs that passed after a failed attempt as flaky", func() {
+		mockTestRunRepo.On("CreateWithHierarchy", ctx, mock.Anything, []string(nil)).Return(nil).Once()
+
+		testRun := newTestRun()
+		spec := testRun.SuiteRuns[0].SpecRuns[0]
+		spec.Attempts = []domain.SpecAttempt{
+			{Status: "failed", ErrorMessage: "connection reset"},
+			{Status: "passed"},
+		}
+		Expect(service.IngestTestRun(ctx, testRun, nil)).To(Succeed())
+
+		Expect(spec.IsFlaky).To(BeTrue())
+		Expect(spec.RetryCount).To(Equal(1))
+		Expect(spec.Attempts[1].Attempt).To(Equal(2))
+		Expect(testRun.PassedTests).To(Equal(2))
+	})
+
+	It("should announce the stored run", func() {
+		publisher := &recordingPublisher{}
+		service.SetEventPublisher(publisher)
+		mockTestRunRepo.On("CreateWithHierarchy", ctx, mock.Anything, []string(nil)).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.TestRun).ID = 12
+		}).Return(nil).Once()
+
+		testRun := newTestRun()
+		Expect(service.IngestTestRun(ctx, testRun, nil)).To(Succeed())
+
+		Expect(publisher.events).To(HaveLen(1))
+		completed := publisher.events[0].(domain.TestRunCompleted)
+		Expect(completed.TestRunID).To(Equal(uint(12)))
+		Expect(completed.RunID).To(Equal(testRun.RunID))
+		Expect(completed.ProjectID).To(Equal("proj-1"))
+		Expect(completed.Status).To(Equal("failed"))
+	})
+
+	It("should surface transaction failures", func() {
+		publisher := &recordingPublisher{}
+		service.SetEventPublisher(publisher)
+		mockTestRunRepo.On("CreateWithHierarchy", ctx, mock.Anything, []string(nil)).Return(errors.New("deadlock detected")).Once()
+
+		err := service.IngestTestRun(ctx, newTestRun(), nil)
+		Expect(err).To(MatchError(ContainSubstring("deadlock detected")))
+		Expect(publisher.events).To(BeEmpty())
+	})
+
+	It("should require a project ID", func() {
+		err := service.IngestTestRun(ctx, &domain.TestRun{}, nil)
+		Expect(err).To(MatchError(ContainSubstring("project ID is required")))
+		mockTestRunRepo.AssertNotCalled(GinkgoT(), "CreateWithHierarchy", mock.Anything, mock.Anything, mock.Anything)
+	})
+})
+
+var _ = Describe("IngestShard", Label("unit", "application", "testing"), func() {
+	var (
+		service         *application.TestRunService
+		mockTestRunRepo *MockTestRunRepository
+		ctx             context.Context
+		startTime       time.Time
+	)
+
+	BeforeEach(func() {
+		mockTestRunRepo = new(MockTestRunRepository)
+		service = application.NewTestRunService(mockTestRunRepo, new(MockSuiteRunRepository), new(MockSpecRunRepository))
+		ctx = context.Background()
+		startTime = time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	})
+
+	newShardRun := func() *domain.TestRun {
+		return &domain.TestRun{
+			ProjectID: "proj-1",
+			RunID:     "shard-job-7",
+			Status:    "passed",
+			StartTime: startTime,
+			SuiteRuns: []domain.SuiteRun{
+				{
+					Name: "checkout",
+					SpecRuns: []*domain.SpecRun{
+						{Name: "adds", Status: "passed"},
+						{Name: "removes", Status: "failed"},
+					},
+				},
+			},
+		}
+	}
+
+	It("should merge the shard's own counters into the group's run", func() {
+		shard := domain.ShardInfo{GroupID: "build-42", Index: 1, Total: 3}
+		mockTestRunRepo.On("MergeShard", ctx, mock.MatchedBy(func(tr *domain.TestRun) bool {
+			return tr.RunID == "build-42" && tr.TotalTests == 2 && tr.FailedTests == 1
+		}), shard, []string{"nightly"}).Return(nil).Once()
+
+		testRun := newShardRun()
+		Expect(service.IngestShard(ctx, testRun, shard, []string{"nightly"})).To(Succeed())
+
+		// The shard's reported status describes the shard, not the merged run
+		Expect(testRun.Status).To(Equal("failed"))
+		mockTestRunRepo.AssertExpectations(GinkgoT())
+	})
+
+	It("should announce every merged shard under the group's run", func() {
+		publisher := &recordingPublisher{}
+		service.SetEventPublisher(publisher)
+		shard := domain.ShardInfo{GroupID: "build-42", Index: 0, Total: 2}
+		mockTestRunRepo.On("MergeShard", ctx, mock.Anything, shard, []string(nil)).Run(func(args mock.Arguments) {
+			tr := args.Get(1).(*domain.TestRun)
+			tr.ID = 7
+			tr.Status = "running"
+		}).Return(nil).Once()
+
+		Expect(service.IngestShard(ctx, newShardRun(), shard, nil)).To(Succeed())
+
+		Expect(publisher.events).To(ConsistOf(HaveField("TestRunID", uint(7))))
+		Expect(publisher.events[0].EventName()).To(Equal(domain.TestRunCompletedEvent))
+	})
+
+	It("should reject a shard outside its group", func() {
+		err := service.IngestShard(ctx, newShardRun(), domain.ShardInfo{GroupID: "build-42", Index: 3, Total: 3}, nil)
+
+		var invalid *application.InvalidReportError
+		Expect(errors.As(err, &invalid)).To(BeTrue())
+		mockTestRunRepo.AssertNotCalled(GinkgoT(), "MergeShard", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	It("should surface shards that disagree with their group", func() {
+		shard := domain.ShardInfo{GroupID: "build-42", Index: 0, Total: 2}
+		mockTestRunRepo.On("MergeShard", ctx, mock.Anything, shard, []string(nil)).Return(domain.ErrShardMismatch).Once()
+
+		err := service.IngestShard(ctx, newShardRun(), shard, nil)
+		Expect(errors.Is(err, domain.ErrShardMismatch)).To(BeTrue())
+	})
+
+	It("should close groups created before the timeout", func() {
+		mockTestRunRepo.On("FinalizeShardGroups", ctx, mock.MatchedBy(func(cutoff time.Time) bool {
+			return cutoff.Before(time.Now().Add(-time.Hour + time.Minute))
+		})).Return(2, nil).Once()
+
+		finalized, err := service.FinalizeTimedOutShardGroups(ctx, time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(finalized).To(Equal(2))
+	})
+})
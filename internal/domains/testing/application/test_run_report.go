@@ -0,0 +1,231 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// TestRunReport is a complete test run submitted as JSON in a single request
+type TestRunReport struct {
+	RunID       string                 `json:"runId"`
+	Branch      string                 `json:"branch"`
+	CommitSha   string                 `json:"commitSha"`
+	Environment string                 `json:"environment"`
+	Status      string                 `json:"status"`
+	StartTime   time.Time              `json:"startTime"`
+	EndTime     *time.Time             `json:"endTime"`
+	Tags        []string               `json:"tags"`
+	Metadata    map[string]interface{} `json:"metadata"`
+	Suites      []SuiteRunReport       `json:"suites"`
+
+	// Shards of a run split across parallel CI jobs share a group ID
+	GroupID    string `json:"groupId"`
+	ShardIndex int    `json:"shardIndex"`
+	ShardTotal int    `json:"shardTotal"`
+}
+
+// SuiteRunReport is a suite of a TestRunReport
+type SuiteRunReport struct {
+	SuiteName string          `json:"suiteName"`
+	Status    string          `json:"status"`
+	StartTime *time.Time      `json:"startTime"`
+	EndTime   *time.Time      `json:"endTime"`
+	Duration  int64           `json:"duration"` // milliseconds
+	Specs     []SpecRunReport `json:"specs"`
+}
+
+// SpecRunReport is a spec of a SuiteRunReport
+type SpecRunReport struct {
+	SpecName     string     `json:"specName"`
+	Status       string     `json:"status"`
+	StartTime    *time.Time `json:"startTime"`
+	EndTime      *time.Time `json:"endTime"`
+	Duration     int64      `json:"duration"` // milliseconds
+	ErrorMessage string     `json:"errorMessage"`
+	StackTrace   string     `json:"stackTrace"`
+	RetryCount   int        `json:"retryCount"`
+	Stdout       string     `json:"stdout"`
+	Stderr       string     `json:"stderr"`
+
+	// ParentName and Parameters identify one parameter set of a parameterised or table-driven
+	// test. ParentName defaults to SpecName when only parameters are given.
+	ParentName string            `json:"parentName,omitempty"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// Attempts lists every attempt of a retried spec, oldest first; the last one is the spec itself
+	Attempts []SpecAttemptReport `json:"attempts,omitempty"`
+}
+
+// SpecAttemptReport is one attempt of a retried spec
+type SpecAttemptReport struct {
+	Status       string     `json:"status"`
+	StartTime    *time.Time `json:"startTime"`
+	Duration     int64      `json:"duration"` // milliseconds
+	ErrorMessage string     `json:"errorMessage"`
+	StackTrace   string     `json:"stackTrace"`
+}
+
+// ToSpecAttempts converts the reported attempts of a spec
+func ToSpecAttempts(reports []SpecAttemptReport) []domain.SpecAttempt {
+	if len(reports) == 0 {
+		return nil
+	}
+
+	attempts := make([]domain.SpecAttempt, len(reports))
+	for i, report := range reports {
+		attempts[i] = domain.SpecAttempt{
+			Status:       report.Status,
+			Duration:     time.Duration(report.Duration) * time.Millisecond,
+			ErrorMessage: report.ErrorMessage,
+			StackTrace:   report.StackTrace,
+		}
+		if report.StartTime != nil {
+			attempts[i].StartTime = *report.StartTime
+		}
+	}
+	return attempts
+}
+
+// ParseTestRunReport decodes and validates a JSON test run report
+func ParseTestRunReport(r io.Reader) (*TestRunReport, error) {
+	var report TestRunReport
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return nil, fmt.Errorf("invalid test run report: %w", err)
+	}
+
+	if report.StartTime.IsZero() {
+		return nil, fmt.Errorf("invalid test run report: startTime is required")
+	}
+	for i, suite := range report.Suites {
+		if suite.SuiteName == "" {
+			return nil, fmt.Errorf("invalid test run report: suites[%d].suiteName is required", i)
+		}
+		for j, spec := range suite.Specs {
+			if spec.SpecName == "" {
+				return nil, fmt.Errorf("invalid test run report: suites[%d].specs[%d].specName is required", i, j)
+			}
+			if spec.Status == "" {
+				return nil, fmt.Errorf("invalid test run report: suites[%d].specs[%d].status is required", i, j)
+			}
+			for k, attempt := range spec.Attempts {
+				if attempt.Status == "" {
+					return nil, fmt.Errorf("invalid test run report: suites[%d].specs[%d].attempts[%d].status is required", i, j, k)
+				}
+			}
+		}
+	}
+
+	return &report, nil
+}
+
+// ToTestRun builds the domain test run, defaulting missing times from the run start
+func (report *TestRunReport) ToTestRun(projectID string) *domain.TestRun {
+	testRun := &domain.TestRun{
+		ProjectID:   projectID,
+		RunID:       report.RunID,
+		Branch:      report.Branch,
+		GitBranch:   report.Branch,
+		GitCommit:   report.CommitSha,
+		Environment: report.Environment,
+		Status:      report.Status,
+		StartTime:   report.StartTime,
+		EndTime:     report.EndTime,
+		Metadata:    report.Metadata,
+		SuiteRuns:   make([]domain.SuiteRun, len(report.Suites)),
+	}
+
+	for i, suiteReport := range report.Suites {
+		suite := domain.SuiteRun{
+			Name:     suiteReport.SuiteName,
+			Status:   suiteReport.Status,
+			Duration: time.Duration(suiteReport.Duration) * time.Millisecond,
+			EndTime:  suiteReport.EndTime,
+			SpecRuns: make([]*domain.SpecRun, len(suiteReport.Specs)),
+		}
+		suite.StartTime = report.StartTime
+		if suiteReport.StartTime != nil {
+			suite.StartTime = *suiteReport.StartTime
+		}
+
+		for j, specReport := range suiteReport.Specs {
+			suite.SpecRuns[j] = specReport.ToSpecRun(suite.StartTime)
+		}
+
+		testRun.SuiteRuns[i] = suite
+	}
+
+	return testRun
+}
+
+// ToSpecRun builds the domain spec run, starting it at startTime if it has no start time
+func (report *SpecRunReport) ToSpecRun(startTime time.Time) *domain.SpecRun {
+	spec := &domain.SpecRun{
+		Name:         report.SpecName,
+		Status:       report.Status,
+		StartTime:    startTime,
+		EndTime:      report.EndTime,
+		Duration:     time.Duration(report.Duration) * time.Millisecond,
+		ErrorMessage: report.ErrorMessage,
+		StackTrace:   report.StackTrace,
+		RetryCount:   report.RetryCount,
+		ParentName:   report.ParentName,
+		Parameters:   report.Parameters,
+		Output:       domain.NewSpecOutput(report.Stdout, report.Stderr),
+		Attempts:     ToSpecAttempts(report.Attempts),
+	}
+	if report.StartTime != nil {
+		spec.StartTime = *report.StartTime
+	}
+	if spec.Duration == 0 && spec.EndTime != nil {
+		spec.Duration = spec.EndTime.Sub(spec.StartTime)
+	}
+	return spec
+}
+
+// ImportTestRunJSON parses a JSON test run report and records it as a new test run.
+// Run details in the report take precedence over those in opts.
+func (s *TestRunService) ImportTestRunJSON(ctx context.Context, r io.Reader, opts ImportOptions) (*domain.TestRun, error) {
+	report, err := ParseTestRunReport(r)
+	if err != nil {
+		return nil, &InvalidReportError{Err: err}
+	}
+
+	testRun := report.ToTestRun(opts.ProjectID)
+	if testRun.RunID == "" {
+		testRun.RunID = opts.RunID
+	}
+	if testRun.Branch == "" {
+		testRun.Branch = opts.Branch
+		testRun.GitBranch = opts.Branch
+	}
+	if testRun.GitCommit == "" {
+		testRun.GitCommit = opts.GitCommit
+	}
+	if testRun.Environment == "" {
+		testRun.Environment = opts.Environment
+	}
+
+	shard := opts.Shard
+	if report.ShardTotal > 0 {
+		shard = &domain.ShardInfo{GroupID: report.GroupID, Index: report.ShardIndex, Total: report.ShardTotal}
+		if shard.GroupID == "" {
+			shard.GroupID = testRun.RunID
+		}
+	}
+
+	if shard != nil {
+		err = s.IngestShard(ctx, testRun, *shard, report.Tags)
+	} else {
+		err = s.IngestTestRun(ctx, testRun, report.Tags)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return testRun, nil
+}
@@ -5,14 +5,21 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/guidewire-oss/fern-platform/internal/domains/events"
 	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
 )
 
 // TestRunService handles test run business logic
 type TestRunService struct {
-	testRunRepo  domain.TestRunRepository
-	suiteRunRepo domain.SuiteRunRepository
-	specRunRepo  domain.SpecRunRepository
+	testRunRepo       domain.TestRunRepository
+	suiteRunRepo      domain.SuiteRunRepository
+	specRunRepo       domain.SpecRunRepository
+	attachments       *AttachmentService
+	specOutputRepo    domain.SpecOutputRepository
+	testCaseRepo      domain.TestCaseRepository
+	maxSpecOutputSize int
+	heartbeatTimeout  time.Duration
+	events            events.Publisher
 }
 
 // NewTestRunService creates a new test run service
@@ -22,14 +29,101 @@ func NewTestRunService(
 	specRunRepo domain.SpecRunRepository,
 ) *TestRunService {
 	return &TestRunService{
-		testRunRepo:  testRunRepo,
-		suiteRunRepo: suiteRunRepo,
-		specRunRepo:  specRunRepo,
+		testRunRepo:       testRunRepo,
+		suiteRunRepo:      suiteRunRepo,
+		specRunRepo:       specRunRepo,
+		maxSpecOutputSize: DefaultMaxSpecOutputSize,
+		heartbeatTimeout:  DefaultHeartbeatTimeout,
 	}
 }
 
-// CreateTestRun creates a new test run
+// SetAttachmentService makes imports keep report attachments in the attachment service's blob
+// store. Without one, attachments are stored inline in the database.
+func (s *TestRunService) SetAttachmentService(attachments *AttachmentService) {
+	s.attachments = attachments
+}
+
+// SetSpecOutputRepository enables reading and searching the output captured for spec runs
+func (s *TestRunService) SetSpecOutputRepository(specOutputRepo domain.SpecOutputRepository) {
+	s.specOutputRepo = specOutputRepo
+}
+
+// SetTestCaseRepository enables looking up test cases and their history
+func (s *TestRunService) SetTestCaseRepository(testCaseRepo domain.TestCaseRepository) {
+	s.testCaseRepo = testCaseRepo
+}
+
+// SetMaxSpecOutputSize sets how many bytes of each spec's stdout and stderr are kept.
+// Sizes of 0 or less keep DefaultMaxSpecOutputSize.
+func (s *TestRunService) SetMaxSpecOutputSize(size int) {
+	if size <= 0 {
+		size = DefaultMaxSpecOutputSize
+	}
+	s.maxSpecOutputSize = size
+}
+
+// SetHeartbeatTimeout sets how long a streamed run may go without hearing from its reporter
+// before it is aborted. Timeouts of 0 or less keep DefaultHeartbeatTimeout.
+func (s *TestRunService) SetHeartbeatTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultHeartbeatTimeout
+	}
+	s.heartbeatTimeout = timeout
+}
+
+// SetEventPublisher makes the service publish a TestRunCompleted event whenever the results
+// of a run have been stored
+func (s *TestRunService) SetEventPublisher(publisher events.Publisher) {
+	s.events = publisher
+}
+
+// publishCompleted announces that the results of a test run have been stored
+func (s *TestRunService) publishCompleted(ctx context.Context, testRun *domain.TestRun) {
+	if s.events == nil {
+		return
+	}
+	s.events.Publish(ctx, domain.TestRunCompleted{
+		TestRunID:   testRun.ID,
+		RunID:       testRun.RunID,
+		ProjectID:   testRun.ProjectID,
+		Status:      testRun.Status,
+		CompletedAt: time.Now(),
+	})
+}
+
+// prepareTestRun readies the specs of every suite in a run for storage
+func (s *TestRunService) prepareTestRun(testRun *domain.TestRun) {
+	for i := range testRun.SuiteRuns {
+		s.prepareSpecRuns(testRun.SuiteRuns[i].SpecRuns...)
+	}
+}
+
+// prepareSpecRuns readies specs for storage: it classifies their attempts, marking specs that
+// passed on a retry as flaky, and caps their captured output at the configured size
+func (s *TestRunService) prepareSpecRuns(specRuns ...*domain.SpecRun) {
+	for _, specRun := range specRuns {
+		specRun.ClassifyAttempts()
+		if specRun.Output != nil {
+			specRun.Output.Truncate(s.maxSpecOutputSize)
+		}
+	}
+}
+
+// CreateTestRun creates a new test run, announcing it as completed when it is created with a
+// terminal status
 func (s *TestRunService) CreateTestRun(ctx context.Context, testRun *domain.TestRun) error {
+	if err := s.createTestRun(ctx, testRun); err != nil {
+		return err
+	}
+
+	if domain.IsTerminalRunStatus(testRun.Status) {
+		s.publishCompleted(ctx, testRun)
+	}
+	return nil
+}
+
+// createTestRun validates and stores a new test run
+func (s *TestRunService) createTestRun(ctx context.Context, testRun *domain.TestRun) error {
 	// Validate test run
 	if testRun.ProjectID == "" {
 		return fmt.Errorf("project ID is required")
@@ -83,6 +177,7 @@ func (s *TestRunService) CompleteTestRun(ctx context.Context, testRunID uint, st
 		return fmt.Errorf("failed to update test run: %w", err)
 	}
 
+	s.publishCompleted(ctx, testRun)
 	return nil
 }
 
@@ -109,6 +204,7 @@ func (s *TestRunService) AddSpecRun(ctx context.Context, suiteRunID uint, specRu
 	}
 
 	// Create the spec run
+	s.prepareSpecRuns(specRun)
 	if err := s.specRunRepo.Create(ctx, specRun); err != nil {
 		return fmt.Errorf("failed to create spec run: %w", err)
 	}
@@ -126,6 +222,11 @@ func (s *TestRunService) GetTestRun(ctx context.Context, id uint) (*domain.TestR
 	return s.testRunRepo.GetByID(ctx, id)
 }
 
+// GetSuiteRun retrieves a suite run by ID
+func (s *TestRunService) GetSuiteRun(ctx context.Context, id uint) (*domain.SuiteRun, error) {
+	return s.suiteRunRepo.GetByID(ctx, id)
+}
+
 // GetTestRunWithDetails retrieves a test run with all details
 func (s *TestRunService) GetTestRunWithDetails(ctx context.Context, id uint) (*domain.TestRun, error) {
 	return s.testRunRepo.GetWithDetails(ctx, id)
@@ -185,8 +286,8 @@ func (s *TestRunService) updateSuiteStatistics(ctx context.Context, suiteRunID u
 
 // CreateTestRunWithSuites creates a test run with all its suites and specs in one transaction
 func (s *TestRunService) CreateTestRunWithSuites(ctx context.Context, testRun *domain.TestRun, suites []domain.SuiteRun) error {
-	// Create the test run
-	if err := s.CreateTestRun(ctx, testRun); err != nil {
+	// Create the test run; CompleteTestRun announces it once its suites are stored
+	if err := s.createTestRun(ctx, testRun); err != nil {
 		return err
 	}
 
@@ -202,6 +303,7 @@ func (s *TestRunService) CreateTestRunWithSuites(ctx context.Context, testRun *d
 			for _, spec := range suite.SpecRuns {
 				spec.SuiteRunID = suite.ID
 			}
+			s.prepareSpecRuns(suite.SpecRuns...)
 			if err := s.specRunRepo.CreateBatch(ctx, suite.SpecRuns); err != nil {
 				return fmt.Errorf("failed to create spec runs: %w", err)
 			}
@@ -252,6 +354,7 @@ func (s *TestRunService) CreateSpecRun(ctx context.Context, specRun *domain.Spec
 		specRun.Duration = specRun.EndTime.Sub(specRun.StartTime)
 	}
 
+	s.prepareSpecRuns(specRun)
 	return s.specRunRepo.Create(ctx, specRun)
 }
 
@@ -298,3 +401,32 @@ func (s *TestRunService) GetSuiteRunsByTestRunID(ctx context.Context, testRunID
 func (s *TestRunService) UpdateTestRun(ctx context.Context, testRun *domain.TestRun) error {
 	return s.testRunRepo.Update(ctx, testRun)
 }
+
+// UpdateTestRunStatus sets the status of a test run, and its end time when given. A run that
+// moves from an unfinished to a terminal status is announced as completed.
+func (s *TestRunService) UpdateTestRunStatus(ctx context.Context, testRunID uint, status string, endTime *time.Time) (*domain.TestRun, error) {
+	if status == "" {
+		return nil, fmt.Errorf("status is required")
+	}
+
+	testRun, err := s.testRunRepo.GetByID(ctx, testRunID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get test run: %w", err)
+	}
+
+	finished := !domain.IsTerminalRunStatus(testRun.Status) && domain.IsTerminalRunStatus(status)
+	testRun.Status = status
+	if endTime != nil {
+		testRun.EndTime = endTime
+		testRun.Duration = endTime.Sub(testRun.StartTime)
+	}
+
+	if err := s.testRunRepo.Update(ctx, testRun); err != nil {
+		return nil, fmt.Errorf("failed to update test run: %w", err)
+	}
+
+	if finished {
+		s.publishCompleted(ctx, testRun)
+	}
+	return testRun, nil
+}
@@ -141,6 +141,59 @@ func (m *MockTestRunRepository) GetTestRunSummary(ctx context.Context, projectID
 	return args.Get(0).(*domain.TestRunSummary), args.Error(1)
 }
 
+func (m *MockTestRunRepository) MergeShard(ctx context.Context, testRun *domain.TestRun, shard domain.ShardInfo, tagNames []string) error {
+	args := m.Called(ctx, testRun, shard, tagNames)
+	return args.Error(0)
+}
+
+func (m *MockTestRunRepository) FinalizeShardGroups(ctx context.Context, createdBefore time.Time) (int, error) {
+	args := m.Called(ctx, createdBefore)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTestRunRepository) StartLiveRun(ctx context.Context, testRun *domain.TestRun) error {
+	args := m.Called(ctx, testRun)
+	return args.Error(0)
+}
+
+func (m *MockTestRunRepository) AddLiveSpecRun(ctx context.Context, testRun *domain.TestRun, suiteName string, specRun *domain.SpecRun) error {
+	args := m.Called(ctx, testRun, suiteName, specRun)
+	return args.Error(0)
+}
+
+func (m *MockTestRunRepository) RecordHeartbeat(ctx context.Context, testRunID uint, at time.Time) error {
+	args := m.Called(ctx, testRunID, at)
+	return args.Error(0)
+}
+
+func (m *MockTestRunRepository) FinishLiveRun(ctx context.Context, testRun *domain.TestRun) error {
+	args := m.Called(ctx, testRun)
+	return args.Error(0)
+}
+
+func (m *MockTestRunRepository) AbortStalledRuns(ctx context.Context, heartbeatBefore time.Time) (int, error) {
+	args := m.Called(ctx, heartbeatBefore)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTestRunRepository) CreateWithHierarchy(ctx context.Context, testRun *domain.TestRun, tagNames []string) error {
+	args := m.Called(ctx, testRun, tagNames)
+	return args.Error(0)
+}
+
+func (m *MockTestRunRepository) AddTags(ctx context.Context, testRunID uint, tagNames []string) error {
+	args := m.Called(ctx, testRunID, tagNames)
+	return args.Error(0)
+}
+
+func (m *MockTestRunRepository) FindWithUnpersistedSuiteRuns(ctx context.Context, afterID uint, limit int) ([]*domain.TestRun, error) {
+	args := m.Called(ctx, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.TestRun), args.Error(1)
+}
+
 // Mock suite run repository
 type MockSuiteRunRepository struct {
 	mock.Mock
@@ -273,6 +326,21 @@ var _ = Describe("TestRunService", Label("unit", "application", "testing"), func
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("project ID is required"))
 		})
+
+		It("should announce only runs created with a terminal status", func() {
+			publisher := &recordingPublisher{}
+			service.SetEventPublisher(publisher)
+			running := &domain.TestRun{RunID: "run-1", ProjectID: "proj-456", Status: "running"}
+			passed := &domain.TestRun{RunID: "run-2", ProjectID: "proj-456", Status: "passed"}
+
+			mockTestRunRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+			Expect(service.CreateTestRun(ctx, running)).To(Succeed())
+			Expect(service.CreateTestRun(ctx, passed)).To(Succeed())
+
+			Expect(publisher.events).To(HaveLen(1))
+			Expect(publisher.events[0].(domain.TestRunCompleted).RunID).To(Equal("run-2"))
+		})
 	})
 
 	Describe("GetTestRun", func() {
@@ -392,12 +460,80 @@ var _ = Describe("TestRunService", Label("unit", "application", "testing"), func
 			Expect(err).NotTo(HaveOccurred())
 		})
 
+		It("should announce the completed run", func() {
+			existingRun := fixtures.TestRun("proj-123",
+				testhelpers.WithTestRunID("test-123"),
+				testhelpers.WithStatus("running"),
+			)
+			existingRun.ID = 1
+			publisher := &recordingPublisher{}
+			service.SetEventPublisher(publisher)
+
+			mockTestRunRepo.On("GetByID", ctx, uint(1)).Return(existingRun, nil)
+			mockSuiteRepo.On("FindByTestRunID", ctx, uint(1)).Return([]*domain.SuiteRun{}, nil)
+			mockTestRunRepo.On("Update", ctx, mock.Anything).Return(nil)
+
+			Expect(service.CompleteTestRun(ctx, 1, "passed")).To(Succeed())
+
+			Expect(publisher.events).To(HaveLen(1))
+			completed := publisher.events[0].(domain.TestRunCompleted)
+			Expect(completed.TestRunID).To(Equal(uint(1)))
+			Expect(completed.RunID).To(Equal("test-123"))
+			Expect(completed.ProjectID).To(Equal("proj-123"))
+			Expect(completed.Status).To(Equal("passed"))
+		})
+
 		It("should return error when test run not found", func() {
 			mockTestRunRepo.On("GetByID", ctx, uint(999)).Return(nil, errors.New("not found"))
 
 			err := service.CompleteTestRun(ctx, 999, "completed")
 			Expect(err).To(HaveOccurred())
 		})
+
+		It("should announce a run that moves to a terminal status", func() {
+			existingRun := fixtures.TestRun("proj-123",
+				testhelpers.WithTestRunID("test-123"),
+				testhelpers.WithStatus("running"),
+			)
+			existingRun.ID = 1
+			publisher := &recordingPublisher{}
+			service.SetEventPublisher(publisher)
+			endTime := existingRun.StartTime.Add(time.Minute)
+
+			mockTestRunRepo.On("GetByID", ctx, uint(1)).Return(existingRun, nil)
+			mockTestRunRepo.On("Update", ctx, mock.MatchedBy(func(tr *domain.TestRun) bool {
+				return tr.Status == "failed" && tr.Duration == time.Minute
+			})).Return(nil)
+
+			testRun, err := service.UpdateTestRunStatus(ctx, 1, "failed", &endTime)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testRun.EndTime).To(Equal(&endTime))
+
+			Expect(publisher.events).To(HaveLen(1))
+			completed := publisher.events[0].(domain.TestRunCompleted)
+			Expect(completed.TestRunID).To(Equal(uint(1)))
+			Expect(completed.Status).To(Equal("failed"))
+		})
+
+		It("should not announce a run that is still running or had already finished", func() {
+			running := fixtures.TestRun("proj-123", testhelpers.WithStatus("pending"))
+			running.ID = 1
+			finished := fixtures.TestRun("proj-123", testhelpers.WithStatus("passed"))
+			finished.ID = 2
+			publisher := &recordingPublisher{}
+			service.SetEventPublisher(publisher)
+
+			mockTestRunRepo.On("GetByID", ctx, uint(1)).Return(running, nil)
+			mockTestRunRepo.On("GetByID", ctx, uint(2)).Return(finished, nil)
+			mockTestRunRepo.On("Update", ctx, mock.Anything).Return(nil)
+
+			_, err := service.UpdateTestRunStatus(ctx, 1, "running", nil)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = service.UpdateTestRunStatus(ctx, 2, "failed", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(publisher.events).To(BeEmpty())
+		})
 	})
 
 	Describe("DeleteTestRun", func() {
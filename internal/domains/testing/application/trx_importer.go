@@ -0,0 +1,258 @@
+package application
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// trxTestRun is the <TestRun> root of a Visual Studio test results (TRX) file, as written by
+// `dotnet test --logger trx` for MSTest, NUnit and xUnit alike
+type trxTestRun struct {
+	XMLName     xml.Name            `xml:"TestRun"`
+	ID          string              `xml:"id,attr"`
+	Name        string              `xml:"name,attr"`
+	RunUser     string              `xml:"runUser,attr"`
+	Times       trxTimes            `xml:"Times"`
+	Definitions []trxUnitTest       `xml:"TestDefinitions>UnitTest"`
+	Results     []trxUnitTestResult `xml:"Results>UnitTestResult"`
+	Summary     trxResultSummary    `xml:"ResultSummary"`
+}
+
+// trxTimes holds the run-level timestamps
+type trxTimes struct {
+	Creation string `xml:"creation,attr"`
+	Start    string `xml:"start,attr"`
+	Finish   string `xml:"finish,attr"`
+}
+
+// trxUnitTest is a test definition; results refer to it by ID
+type trxUnitTest struct {
+	ID      string        `xml:"id,attr"`
+	Name    string        `xml:"name,attr"`
+	Storage string        `xml:"storage,attr"`
+	Method  trxTestMethod `xml:"TestMethod"`
+}
+
+// trxTestMethod identifies the code a test definition runs
+type trxTestMethod struct {
+	CodeBase  string `xml:"codeBase,attr"`
+	ClassName string `xml:"className,attr"`
+	Name      string `xml:"name,attr"`
+}
+
+// trxUnitTestResult is the outcome of one test execution. Data-driven MSTest results carry
+// one inner result per data row.
+type trxUnitTestResult struct {
+	ExecutionID  string              `xml:"executionId,attr"`
+	TestID       string              `xml:"testId,attr"`
+	TestName     string              `xml:"testName,attr"`
+	ComputerName string              `xml:"computerName,attr"`
+	Duration     string              `xml:"duration,attr"`
+	StartTime    string              `xml:"startTime,attr"`
+	EndTime      string              `xml:"endTime,attr"`
+	Outcome      string              `xml:"outcome,attr"`
+	ResultType   string              `xml:"resultType,attr"`
+	Output       trxOutput           `xml:"Output"`
+	InnerResults []trxUnitTestResult `xml:"InnerResults>UnitTestResult"`
+}
+
+// trxOutput is the output captured for a result
+type trxOutput struct {
+	StdOut     string `xml:"StdOut"`
+	StdErr     string `xml:"StdErr"`
+	DebugTrace string `xml:"DebugTrace"`
+	Message    string `xml:"ErrorInfo>Message"`
+	StackTrace string `xml:"ErrorInfo>StackTrace"`
+}
+
+// trxResultSummary is the run's overall outcome and counters
+type trxResultSummary struct {
+	Outcome  string      `xml:"outcome,attr"`
+	Counters trxCounters `xml:"Counters"`
+	StdOut   string      `xml:"Output>StdOut"`
+}
+
+// trxCounters are the outcome counters reported by vstest
+type trxCounters struct {
+	Total        int `xml:"total,attr"`
+	Executed     int `xml:"executed,attr"`
+	Passed       int `xml:"passed,attr"`
+	Failed       int `xml:"failed,attr"`
+	Error        int `xml:"error,attr"`
+	Timeout      int `xml:"timeout,attr"`
+	Aborted      int `xml:"aborted,attr"`
+	Inconclusive int `xml:"inconclusive,attr"`
+	NotExecuted  int `xml:"notExecuted,attr"`
+}
+
+// ParseTRX converts a Visual Studio TRX report into a test run hierarchy: each test class
+// becomes a suite run and each result a spec run. Data-driven rows become separate specs
+// sharing the test identity of their method. The returned test run has no project or run ID assigned.
+func ParseTRX(r io.Reader) (*domain.TestRun, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trx report: %w", err)
+	}
+
+	root, err := xmlRootElement(data, "trx")
+	if err != nil {
+		return nil, err
+	}
+	if root != "TestRun" {
+		return nil, fmt.Errorf("invalid trx report: unexpected root element <%s>", root)
+	}
+
+	var report trxTestRun
+	if err := xml.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("invalid trx report: %w", err)
+	}
+
+	return report.toTestRun(), nil
+}
+
+// toTestRun resolves each result's definition and builds the domain hierarchy
+func (r *trxTestRun) toTestRun() *domain.TestRun {
+	definitions := make(map[string]trxUnitTest, len(r.Definitions))
+	for _, def := range r.Definitions {
+		definitions[def.ID] = def
+	}
+
+	var results []dotnetTestResult
+	for _, result := range r.Results {
+		results = append(results, result.toResults(definitions[result.TestID], false)...)
+	}
+
+	trx := map[string]interface{}{
+		"outcome": r.Summary.Outcome,
+		"counters": map[string]interface{}{
+			"total":        r.Summary.Counters.Total,
+			"executed":     r.Summary.Counters.Executed,
+			"passed":       r.Summary.Counters.Passed,
+			"failed":       r.Summary.Counters.Failed,
+			"error":        r.Summary.Counters.Error,
+			"timeout":      r.Summary.Counters.Timeout,
+			"aborted":      r.Summary.Counters.Aborted,
+			"inconclusive": r.Summary.Counters.Inconclusive,
+			"not_executed": r.Summary.Counters.NotExecuted,
+		},
+	}
+	if r.ID != "" {
+		trx["id"] = r.ID
+	}
+	if r.RunUser != "" {
+		trx["run_user"] = r.RunUser
+	}
+	if out := strings.TrimSpace(r.Summary.StdOut); out != "" {
+		trx["output"] = out
+	}
+
+	return newDotnetTestRun(ReportFormatTRX, r.Name, parseDotnetTimestamp(r.Times.Start), results, trx)
+}
+
+// toResults converts a result, replacing a data-driven parent by its rows
+func (res trxUnitTestResult) toResults(def trxUnitTest, dataRow bool) []dotnetTestResult {
+	if len(res.InnerResults) > 0 {
+		var rows []dotnetTestResult
+		for _, inner := range res.InnerResults {
+			rows = append(rows, inner.toResults(def, true)...)
+		}
+		return rows
+	}
+
+	// Older MSTest versions write assembly-qualified class names
+	class, _, _ := strings.Cut(def.Method.ClassName, ",")
+	class = strings.TrimSpace(class)
+	method := def.Method.Name
+	if method == "" {
+		method = def.Name
+	}
+
+	name := dotnetShortName(res.TestName, class)
+	if name == "" {
+		name = method
+	}
+
+	spec := newDotnetSpecRun(name, class, res.Outcome,
+		parseDotnetTimestamp(res.StartTime), parseDotnetTimestamp(res.EndTime), parseTimeSpan(res.Duration))
+	setDotnetFailure(spec, res.Output.Message, res.Output.StackTrace, "test "+strings.ToLower(res.Outcome))
+
+	arguments, hasArguments := dotnetArguments(name, class, method)
+	result := dotnetTestResult{
+		assembly:      dotnetAssemblyName(def.Storage),
+		class:         class,
+		spec:          spec,
+		identity:      dotnetTestIdentity(class, method),
+		arguments:     arguments,
+		parameterized: hasArguments || dataRow,
+		meta:          make(map[string]interface{}),
+	}
+	if !isPlainDotnetOutcome(res.Outcome) {
+		result.meta["outcome"] = res.Outcome
+	}
+	if res.ComputerName != "" {
+		result.meta["computer_name"] = res.ComputerName
+	}
+	spec.Output = domain.NewSpecOutput(res.Output.StdOut, res.Output.StdErr)
+	if trace := strings.TrimSpace(res.Output.DebugTrace); trace != "" {
+		result.meta["debug_trace"] = trace
+	}
+
+	return []dotnetTestResult{result}
+}
+
+// parseTimeSpan parses a .NET TimeSpan in its constant ("c") format, [d.]hh:mm:ss[.fffffff]
+func parseTimeSpan(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		return 0
+	}
+
+	var days, hours int
+	var err error
+	if d, h, ok := strings.Cut(parts[0], "."); ok {
+		if days, err = strconv.Atoi(d); err != nil {
+			return 0
+		}
+		parts[0] = h
+	}
+	if hours, err = strconv.Atoi(parts[0]); err != nil {
+		return 0
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0
+	}
+	seconds, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return 0
+	}
+
+	duration := time.Duration(days*24+hours)*time.Hour + time.Duration(minutes)*time.Minute +
+		time.Duration(seconds*float64(time.Second))
+	if duration < 0 {
+		return 0
+	}
+	return duration
+}
+
+// ImportTRX parses a Visual Studio TRX report and records it as a new test run
+func (s *TestRunService) ImportTRX(ctx context.Context, r io.Reader, opts ImportOptions) (*domain.TestRun, error) {
+	testRun, err := ParseTRX(r)
+	if err != nil {
+		return nil, &InvalidReportError{Err: err}
+	}
+
+	if err := s.importTestRun(ctx, testRun, opts); err != nil {
+		return nil, err
+	}
+
+	return testRun, nil
+}
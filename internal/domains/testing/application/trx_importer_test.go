@@ -0,0 +1,167 @@
+package application_test
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+const trxReport = `<?xml version="1.0" encoding="utf-8"?>
+<TestRun id="7f7c2ac4-0b3a-4a44-9d41-2c7a1f1e0c55" name="ci@agent 2024-03-01 10:00:00" runUser="ci" xmlns="http://microsoft.com/schemas/VisualStudio/TeamTest/2010">
+  <Times creation="2024-03-01T10:00:00.0000000+00:00" start="2024-03-01T10:00:00.0000000+00:00" finish="2024-03-01T10:00:05.0000000+00:00"/>
+  <Results>
+    <UnitTestResult executionId="e1" testId="t1" testName="AddsItem" computerName="agent" duration="00:00:00.5000000" startTime="2024-03-01T10:00:00.0000000+00:00" endTime="2024-03-01T10:00:00.5000000+00:00" outcome="Passed">
+      <Output><StdOut>added 1 item</StdOut></Output>
+    </UnitTestResult>
+    <UnitTestResult executionId="e2" testId="t2" testName="Acme.Cart.CartTests.Adds(a: 1, b: 2)" duration="00:00:00.1000000" startTime="2024-03-01T10:00:01.0000000+00:00" endTime="2024-03-01T10:00:01.1000000+00:00" outcome="Passed"/>
+    <UnitTestResult executionId="e3" testId="t3" testName="Acme.Cart.CartTests.Adds(a: 2, b: 2)" duration="00:00:00.2000000" startTime="2024-03-01T10:00:01.1000000+00:00" endTime="2024-03-01T10:00:01.3000000+00:00" outcome="Failed">
+      <Output>
+        <ErrorInfo>
+          <Message>Assert.Equal() Failure: expected 4, actual 5</Message>
+          <StackTrace>at Acme.Cart.CartTests.Adds(Int32 a, Int32 b) in CartTests.cs:line 42</StackTrace>
+        </ErrorInfo>
+      </Output>
+    </UnitTestResult>
+    <UnitTestResult executionId="e4" testId="t4" testName="Checkout" duration="00:00:30.0000000" startTime="2024-03-01T10:00:02.0000000+00:00" endTime="2024-03-01T10:00:32.0000000+00:00" outcome="Timeout"/>
+    <UnitTestResult executionId="e5" testId="t5" testName="AppliesCoupon" outcome="NotExecuted"/>
+    <UnitTestResult executionId="e6" testId="t6" testName="Charges" duration="00:00:00.0100000" outcome="Inconclusive"/>
+    <UnitTestResult executionId="e7" testId="t7" testName="Rounds" duration="00:00:00.3000000" startTime="2024-03-01T10:00:03.0000000+00:00" endTime="2024-03-01T10:00:03.3000000+00:00" outcome="Passed" resultType="DataDrivenTest">
+      <InnerResults>
+        <UnitTestResult executionId="e8" testId="t7" testName="Rounds (1.5,2)" duration="00:00:00.1000000" startTime="2024-03-01T10:00:03.0000000+00:00" endTime="2024-03-01T10:00:03.1000000+00:00" outcome="Passed" resultType="DataDrivenDataRow"/>
+        <UnitTestResult executionId="e9" testId="t7" testName="Rounds (2.5,3)" duration="00:00:00.2000000" startTime="2024-03-01T10:00:03.1000000+00:00" endTime="2024-03-01T10:00:03.3000000+00:00" outcome="Passed" resultType="DataDrivenDataRow"/>
+      </InnerResults>
+    </UnitTestResult>
+  </Results>
+  <TestDefinitions>
+    <UnitTest name="AddsItem" storage="/src/tests/bin/Acme.Tests.dll" id="t1"><TestMethod className="Acme.Cart.CartTests" name="AddsItem"/></UnitTest>
+    <UnitTest name="Acme.Cart.CartTests.Adds(a: 1, b: 2)" storage="/src/tests/bin/Acme.Tests.dll" id="t2"><TestMethod className="Acme.Cart.CartTests" name="Adds"/></UnitTest>
+    <UnitTest name="Acme.Cart.CartTests.Adds(a: 2, b: 2)" storage="/src/tests/bin/Acme.Tests.dll" id="t3"><TestMethod className="Acme.Cart.CartTests" name="Adds"/></UnitTest>
+    <UnitTest name="Checkout" storage="/src/tests/bin/Acme.Tests.dll" id="t4"><TestMethod className="Acme.Cart.CartTests" name="Checkout"/></UnitTest>
+    <UnitTest name="AppliesCoupon" storage="/src/tests/bin/Acme.Tests.dll" id="t5"><TestMethod className="Acme.Cart.CartTests" name="AppliesCoupon"/></UnitTest>
+    <UnitTest name="Charges" storage="/src/tests/bin/Acme.Tests.dll" id="t6"><TestMethod className="Acme.Payments.PaymentTests, Acme.Tests, Version=1.0.0.0" name="Charges"/></UnitTest>
+    <UnitTest name="Rounds" storage="/src/tests/bin/Acme.Tests.dll" id="t7"><TestMethod className="Acme.Payments.PaymentTests" name="Rounds"/></UnitTest>
+  </TestDefinitions>
+  <ResultSummary outcome="Failed">
+    <Counters total="8" executed="7" passed="4" failed="1" timeout="1" inconclusive="1" notExecuted="1"/>
+  </ResultSummary>
+</TestRun>`
+
+var _ = Describe("TRX import", Label("unit", "application", "testing"), func() {
+	Describe("ParseTRX", func() {
+		It("should group results by class and normalise outcomes", func() {
+			testRun, err := application.ParseTRX(strings.NewReader(trxReport))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(testRun.Source).To(Equal("trx"))
+			Expect(testRun.Status).To(Equal("failed"))
+			Expect(testRun.TotalTests).To(Equal(8))
+			Expect(testRun.PassedTests).To(Equal(4))
+			Expect(testRun.FailedTests).To(Equal(2))
+			Expect(testRun.SkippedTests).To(Equal(2))
+			Expect(testRun.StartTime).To(Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)))
+			Expect(testRun.SuiteRuns).To(HaveLen(2))
+
+			cart := testRun.SuiteRuns[0]
+			Expect(cart.Name).To(Equal("Acme.Cart.CartTests"))
+			Expect(cart.PackageName).To(Equal("Acme.Tests"))
+			Expect(cart.Duration).To(Equal(32 * time.Second))
+			Expect(cart.SpecRuns).To(HaveLen(5))
+
+			failed := cart.SpecRuns[2]
+			Expect(failed.Name).To(Equal("Adds(a: 2, b: 2)"))
+			Expect(failed.ClassName).To(Equal("Acme.Cart.CartTests"))
+			Expect(failed.Status).To(Equal("failed"))
+			Expect(failed.Duration).To(Equal(200 * time.Millisecond))
+			Expect(failed.ErrorMessage).To(Equal("Assert.Equal() Failure: expected 4, actual 5"))
+			Expect(failed.StackTrace).To(ContainSubstring("CartTests.cs:line 42"))
+
+			timedOut := cart.SpecRuns[3]
+			Expect(timedOut.Status).To(Equal("failed"))
+			Expect(timedOut.ErrorMessage).To(Equal("test timeout"))
+			Expect(cart.SpecRuns[4].Status).To(Equal("skipped"))
+
+			payments := testRun.SuiteRuns[1]
+			Expect(payments.Name).To(Equal("Acme.Payments.PaymentTests"))
+			Expect(payments.SpecRuns[0].Status).To(Equal("skipped"))
+		})
+
+		It("should expand data-driven results into rows sharing a test identity", func() {
+			testRun, err := application.ParseTRX(strings.NewReader(trxReport))
+			Expect(err).NotTo(HaveOccurred())
+
+			payments := testRun.SuiteRuns[1]
+			Expect(payments.SpecRuns).To(HaveLen(3))
+			Expect(payments.SpecRuns[1].Name).To(Equal("Rounds (1.5,2)"))
+			Expect(payments.SpecRuns[2].Name).To(Equal("Rounds (2.5,3)"))
+
+			trx := testRun.Metadata["trx"].(map[string]interface{})
+			specs := trx["specs"].(map[string]interface{})
+			Expect(specs["Acme.Payments.PaymentTests/Rounds (1.5,2)"]).To(HaveKeyWithValue("test_identity", "Acme.Payments.PaymentTests.Rounds"))
+			Expect(specs["Acme.Payments.PaymentTests/Rounds (1.5,2)"]).To(HaveKeyWithValue("arguments", "1.5,2"))
+			Expect(specs["Acme.Cart.CartTests/Adds(a: 1, b: 2)"]).To(HaveKeyWithValue("arguments", "a: 1, b: 2"))
+
+			parameterized := trx["parameterized_tests"].(map[string]interface{})
+			Expect(parameterized).To(HaveLen(2))
+			Expect(parameterized["Acme.Cart.CartTests.Adds"]).To(Equal([]string{
+				"Acme.Cart.CartTests/Adds(a: 1, b: 2)",
+				"Acme.Cart.CartTests/Adds(a: 2, b: 2)",
+			}))
+		})
+
+		It("should capture spec output", func() {
+			testRun, err := application.ParseTRX(strings.NewReader(trxReport))
+			Expect(err).NotTo(HaveOccurred())
+
+			output := testRun.SuiteRuns[0].SpecRuns[0].Output
+			Expect(output).NotTo(BeNil())
+			Expect(output.Stdout).To(Equal("added 1 item"))
+		})
+
+		It("should keep the original outcome in metadata", func() {
+			testRun, err := application.ParseTRX(strings.NewReader(trxReport))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(testRun.Metadata).To(HaveKeyWithValue("source_format", "trx"))
+			trx := testRun.Metadata["trx"].(map[string]interface{})
+			Expect(trx).To(HaveKeyWithValue("outcome", "Failed"))
+
+			specs := trx["specs"].(map[string]interface{})
+			Expect(specs["Acme.Cart.CartTests/AddsItem"]).NotTo(HaveKey("system_out"))
+			Expect(specs["Acme.Cart.CartTests/AddsItem"]).NotTo(HaveKey("outcome"))
+			Expect(specs["Acme.Cart.CartTests/Checkout"]).To(HaveKeyWithValue("outcome", "Timeout"))
+			Expect(specs["Acme.Cart.CartTests/AppliesCoupon"]).To(HaveKeyWithValue("outcome", "NotExecuted"))
+			Expect(specs["Acme.Payments.PaymentTests/Charges"]).To(HaveKeyWithValue("outcome", "Inconclusive"))
+		})
+
+		It("should reject documents that are not TRX reports", func() {
+			_, err := application.ParseTRX(strings.NewReader(`<testsuites></testsuites>`))
+			Expect(err).To(MatchError(ContainSubstring("unexpected root element")))
+		})
+	})
+
+	Describe("ImportReport", func() {
+		It("should persist a TRX report uploaded in the trx format", func() {
+			mockTestRunRepo := new(MockTestRunRepository)
+			service := application.NewTestRunService(mockTestRunRepo, new(MockSuiteRunRepository), new(MockSpecRunRepository))
+			ctx := context.Background()
+
+			mockTestRunRepo.On("CreateWithHierarchy", ctx, mock.MatchedBy(func(tr *domain.TestRun) bool {
+				return tr.ProjectID == "proj-123" && tr.TotalTests == 8 && tr.FailedTests == 2
+			}), []string(nil)).Return(nil)
+
+			Expect(application.IsSupportedReportFormat(application.ReportFormatTRX)).To(BeTrue())
+			_, err := service.ImportReport(ctx, application.ReportFormatTRX, strings.NewReader(trxReport), application.ImportOptions{
+				ProjectID: "proj-123",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			mockTestRunRepo.AssertExpectations(GinkgoT())
+		})
+	})
+})
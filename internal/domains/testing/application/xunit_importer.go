@@ -0,0 +1,235 @@
+package application
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// xunitAssemblies is the <assemblies> root of an xUnit v2 result file
+type xunitAssemblies struct {
+	XMLName    xml.Name        `xml:"assemblies"`
+	Assemblies []xunitAssembly `xml:"assembly"`
+}
+
+// xunitAssembly is the result of running one test assembly
+type xunitAssembly struct {
+	XMLName     xml.Name          `xml:"assembly"`
+	Name        string            `xml:"name,attr"`
+	Environment string            `xml:"environment,attr"`
+	Framework   string            `xml:"test-framework,attr"`
+	RunDate     string            `xml:"run-date,attr"`
+	RunTime     string            `xml:"run-time,attr"`
+	Time        string            `xml:"time,attr"`
+	Total       int               `xml:"total,attr"`
+	Passed      int               `xml:"passed,attr"`
+	Failed      int               `xml:"failed,attr"`
+	Skipped     int               `xml:"skipped,attr"`
+	Errors      []xunitError      `xml:"errors>error"`
+	Collections []xunitCollection `xml:"collection"`
+}
+
+// xunitError is a failure outside of any test, such as a failing fixture cleanup
+type xunitError struct {
+	Type    string       `xml:"type,attr"`
+	Name    string       `xml:"name,attr"`
+	Failure xunitFailure `xml:"failure"`
+}
+
+// xunitCollection is a test collection; by default one per test class
+type xunitCollection struct {
+	Name  string      `xml:"name,attr"`
+	Tests []xunitTest `xml:"test"`
+}
+
+// xunitTest is a single <test> element; every row of a theory is its own test
+type xunitTest struct {
+	Name    string          `xml:"name,attr"`
+	Type    string          `xml:"type,attr"`
+	Method  string          `xml:"method,attr"`
+	Time    string          `xml:"time,attr"`
+	Result  string          `xml:"result,attr"`
+	Traits  []junitProperty `xml:"traits>trait"`
+	Output  string          `xml:"output"`
+	Reason  string          `xml:"reason"`
+	Failure *xunitFailure   `xml:"failure"`
+}
+
+// xunitFailure holds the payload of a <failure> element
+type xunitFailure struct {
+	ExceptionType string `xml:"exception-type,attr"`
+	Message       string `xml:"message"`
+	StackTrace    string `xml:"stack-trace"`
+}
+
+// ParseXUnitXML converts an xUnit v2 result file into a test run hierarchy: each test class
+// becomes a suite run and each test a spec run. Theory rows become separate specs sharing the
+// test identity of their method. Errors raised outside of tests are recorded as failed specs.
+// The returned test run has no project or run ID assigned.
+func ParseXUnitXML(r io.Reader) (*domain.TestRun, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read xunit report: %w", err)
+	}
+
+	root, err := xmlRootElement(data, "xunit")
+	if err != nil {
+		return nil, err
+	}
+
+	var report xunitAssemblies
+	switch root {
+	case "assemblies":
+		if err := xml.Unmarshal(data, &report); err != nil {
+			return nil, fmt.Errorf("invalid xunit report: %w", err)
+		}
+	case "assembly":
+		var assembly xunitAssembly
+		if err := xml.Unmarshal(data, &assembly); err != nil {
+			return nil, fmt.Errorf("invalid xunit report: %w", err)
+		}
+		report.Assemblies = []xunitAssembly{assembly}
+	default:
+		return nil, fmt.Errorf("invalid xunit report: unexpected root element <%s>", root)
+	}
+
+	return report.toTestRun(), nil
+}
+
+// toTestRun collects the tests of every assembly and builds the domain hierarchy
+func (r *xunitAssemblies) toTestRun() *domain.TestRun {
+	var results []dotnetTestResult
+	var startTime time.Time
+	assemblyMetadata := make([]map[string]interface{}, 0, len(r.Assemblies))
+	for _, assembly := range r.Assemblies {
+		results = append(results, assembly.results()...)
+
+		if ts := assembly.startTime(); !ts.IsZero() && (startTime.IsZero() || ts.Before(startTime)) {
+			startTime = ts
+		}
+
+		meta := map[string]interface{}{
+			"name":    assembly.Name,
+			"total":   assembly.Total,
+			"passed":  assembly.Passed,
+			"failed":  assembly.Failed,
+			"skipped": assembly.Skipped,
+		}
+		if assembly.Framework != "" {
+			meta["test_framework"] = assembly.Framework
+		}
+		if assembly.Environment != "" {
+			meta["environment"] = assembly.Environment
+		}
+		assemblyMetadata = append(assemblyMetadata, meta)
+	}
+
+	xunit := map[string]interface{}{
+		"assemblies": assemblyMetadata,
+	}
+
+	return newDotnetTestRun(ReportFormatXUnit, "", startTime, results, xunit)
+}
+
+// startTime combines the run-date and run-time attributes, which xUnit writes in local time
+// without a zone; they are interpreted as UTC
+func (a xunitAssembly) startTime() time.Time {
+	if a.RunDate == "" {
+		return time.Time{}
+	}
+	return parseDotnetTimestamp(strings.TrimSpace(a.RunDate + " " + a.RunTime))
+}
+
+// results converts the assembly's tests and errors
+func (a xunitAssembly) results() []dotnetTestResult {
+	assembly := dotnetAssemblyName(a.Name)
+
+	var results []dotnetTestResult
+	for _, collection := range a.Collections {
+		for _, test := range collection.Tests {
+			results = append(results, test.toResult(assembly))
+		}
+	}
+
+	// Errors outside of tests fail the run without failing a test; record each as a
+	// failed spec of the assembly so the failure is counted
+	for _, e := range a.Errors {
+		name := fmt.Sprintf("[%s]", e.Type)
+		if e.Name != "" {
+			name = fmt.Sprintf("[%s] %s", e.Type, e.Name)
+		}
+		spec := &domain.SpecRun{Name: name, Status: "failed"}
+		setDotnetFailure(spec, e.Failure.Message, e.Failure.StackTrace, e.Failure.ExceptionType)
+
+		meta := map[string]interface{}{"error_type": e.Type}
+		if e.Failure.ExceptionType != "" {
+			meta["exception_type"] = e.Failure.ExceptionType
+		}
+		results = append(results, dotnetTestResult{
+			assembly: assembly,
+			class:    assembly,
+			spec:     spec,
+			identity: name,
+			meta:     meta,
+		})
+	}
+
+	return results
+}
+
+// toResult converts a test
+func (t xunitTest) toResult(assembly string) dotnetTestResult {
+	name := dotnetShortName(t.Name, t.Type)
+
+	spec := newDotnetSpecRun(name, t.Type, t.Result, time.Time{}, time.Time{}, parseJUnitDuration(t.Time))
+	var failure xunitFailure
+	if t.Failure != nil {
+		failure = *t.Failure
+	}
+	setDotnetFailure(spec, failure.Message, failure.StackTrace, failure.ExceptionType)
+
+	arguments, hasArguments := dotnetArguments(name, t.Type, t.Method)
+	result := dotnetTestResult{
+		assembly:      assembly,
+		class:         t.Type,
+		spec:          spec,
+		identity:      dotnetTestIdentity(t.Type, t.Method),
+		arguments:     arguments,
+		parameterized: hasArguments,
+		meta:          make(map[string]interface{}),
+	}
+	if !isPlainDotnetOutcome(t.Result) {
+		result.meta["outcome"] = t.Result
+	}
+	if failure.ExceptionType != "" {
+		result.meta["exception_type"] = failure.ExceptionType
+	}
+	if reason := strings.TrimSpace(t.Reason); reason != "" {
+		result.meta["skipped_reason"] = reason
+	}
+	if traits := junitPropertiesToMap(t.Traits); len(traits) > 0 {
+		result.meta["traits"] = traits
+	}
+	spec.Output = domain.NewSpecOutput(t.Output, "")
+
+	return result
+}
+
+// ImportXUnitXML parses an xUnit v2 result file and records it as a new test run
+func (s *TestRunService) ImportXUnitXML(ctx context.Context, r io.Reader, opts ImportOptions) (*domain.TestRun, error) {
+	testRun, err := ParseXUnitXML(r)
+	if err != nil {
+		return nil, &InvalidReportError{Err: err}
+	}
+
+	if err := s.importTestRun(ctx, testRun, opts); err != nil {
+		return nil, err
+	}
+
+	return testRun, nil
+}
@@ -0,0 +1,139 @@
+package application_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+const xunitReport = `<?xml version="1.0" encoding="utf-8"?>
+<assemblies timestamp="03/01/2024 10:00:00">
+  <assembly name="/src/tests/bin/Acme.Tests.dll" environment="64-bit .NET 8.0" test-framework="xUnit.net 2.6.2" run-date="2024-03-01" run-time="10:00:00" total="5" passed="2" failed="1" skipped="2" time="0.750" errors="1">
+    <errors>
+      <error type="fixture-cleanup" name="Acme.DatabaseFixture">
+        <failure exception-type="System.InvalidOperationException">
+          <message><![CDATA[connection already closed]]></message>
+          <stack-trace><![CDATA[at Acme.DatabaseFixture.Dispose()]]></stack-trace>
+        </failure>
+      </error>
+    </errors>
+    <collection name="Test collection for Acme.CartTests" total="4" passed="2" failed="1" skipped="1" time="0.600">
+      <test name="Acme.CartTests.AddsItem" type="Acme.CartTests" method="AddsItem" time="0.2000000" result="Pass">
+        <traits><trait name="Category" value="smoke"/></traits>
+        <output><![CDATA[added 1 item]]></output>
+      </test>
+      <test name="Acme.CartTests.Adds(a: 1, b: 2)" type="Acme.CartTests" method="Adds" time="0.1000000" result="Pass"/>
+      <test name="Acme.CartTests.Adds(a: 2, b: 2)" type="Acme.CartTests" method="Adds" time="0.3000000" result="Fail">
+        <failure exception-type="Xunit.Sdk.EqualException">
+          <message><![CDATA[Assert.Equal() Failure: expected 4, actual 5]]></message>
+          <stack-trace><![CDATA[at Acme.CartTests.Adds(Int32 a, Int32 b) in CartTests.cs:line 12]]></stack-trace>
+        </failure>
+      </test>
+      <test name="Applies a coupon" type="Acme.CartTests" method="AppliesCoupon" time="0" result="Skip">
+        <reason><![CDATA[coupons disabled]]></reason>
+      </test>
+    </collection>
+    <collection name="Test collection for Acme.PaymentTests" total="1" skipped="1" time="0">
+      <test name="Acme.PaymentTests.Charges" type="Acme.PaymentTests" method="Charges" time="0" result="NotRun"/>
+    </collection>
+  </assembly>
+</assemblies>`
+
+var _ = Describe("xUnit import", Label("unit", "application", "testing"), func() {
+	Describe("ParseXUnitXML", func() {
+		It("should build suites from test classes and normalise results", func() {
+			testRun, err := application.ParseXUnitXML(strings.NewReader(xunitReport))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(testRun.Source).To(Equal("xunit"))
+			Expect(testRun.Status).To(Equal("failed"))
+			Expect(testRun.TotalTests).To(Equal(6))
+			Expect(testRun.PassedTests).To(Equal(2))
+			Expect(testRun.FailedTests).To(Equal(2))
+			Expect(testRun.SkippedTests).To(Equal(2))
+			Expect(testRun.StartTime).To(Equal(time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)))
+			Expect(testRun.SuiteRuns).To(HaveLen(3))
+
+			cart := testRun.SuiteRuns[0]
+			Expect(cart.Name).To(Equal("Acme.CartTests"))
+			Expect(cart.PackageName).To(Equal("Acme.Tests"))
+			Expect(cart.Duration).To(Equal(600 * time.Millisecond))
+			Expect(cart.SpecRuns).To(HaveLen(4))
+			Expect(cart.SpecRuns[0].Name).To(Equal("AddsItem"))
+			Expect(cart.SpecRuns[1].StartTime).To(Equal(cart.StartTime.Add(200 * time.Millisecond)))
+
+			failed := cart.SpecRuns[2]
+			Expect(failed.Name).To(Equal("Adds(a: 2, b: 2)"))
+			Expect(failed.Status).To(Equal("failed"))
+			Expect(failed.ErrorMessage).To(Equal("Assert.Equal() Failure: expected 4, actual 5"))
+			Expect(failed.StackTrace).To(ContainSubstring("CartTests.cs:line 12"))
+			Expect(cart.SpecRuns[3].Name).To(Equal("Applies a coupon"))
+			Expect(cart.SpecRuns[3].Status).To(Equal("skipped"))
+
+			Expect(testRun.SuiteRuns[1].SpecRuns[0].Status).To(Equal("skipped"))
+		})
+
+		It("should record errors outside of tests as failed specs", func() {
+			testRun, err := application.ParseXUnitXML(strings.NewReader(xunitReport))
+			Expect(err).NotTo(HaveOccurred())
+
+			errors := testRun.SuiteRuns[2]
+			Expect(errors.Name).To(Equal("Acme.Tests"))
+			Expect(errors.SpecRuns).To(HaveLen(1))
+			Expect(errors.SpecRuns[0].Name).To(Equal("[fixture-cleanup] Acme.DatabaseFixture"))
+			Expect(errors.SpecRuns[0].Status).To(Equal("failed"))
+			Expect(errors.SpecRuns[0].ErrorMessage).To(Equal("connection already closed"))
+		})
+
+		It("should group theory rows under one test identity and keep extras in metadata", func() {
+			testRun, err := application.ParseXUnitXML(strings.NewReader(xunitReport))
+			Expect(err).NotTo(HaveOccurred())
+
+			xunit := testRun.Metadata["xunit"].(map[string]interface{})
+			Expect(xunit["parameterized_tests"]).To(Equal(map[string]interface{}{
+				"Acme.CartTests.Adds": []string{"Acme.CartTests/Adds(a: 1, b: 2)", "Acme.CartTests/Adds(a: 2, b: 2)"},
+			}))
+
+			specs := xunit["specs"].(map[string]interface{})
+			Expect(specs["Acme.CartTests/Adds(a: 2, b: 2)"]).To(HaveKeyWithValue("arguments", "a: 2, b: 2"))
+			Expect(specs["Acme.CartTests/Adds(a: 2, b: 2)"]).To(HaveKeyWithValue("exception_type", "Xunit.Sdk.EqualException"))
+			Expect(specs["Acme.CartTests/Applies a coupon"]).To(HaveKeyWithValue("test_identity", "Acme.CartTests.AppliesCoupon"))
+			Expect(specs["Acme.CartTests/Applies a coupon"]).To(HaveKeyWithValue("skipped_reason", "coupons disabled"))
+			Expect(specs["Acme.CartTests/AddsItem"]).NotTo(HaveKey("system_out"))
+			Expect(testRun.SuiteRuns[0].SpecRuns[0].Output.Stdout).To(Equal("added 1 item"))
+			Expect(specs["Acme.CartTests/AddsItem"].(map[string]interface{})["traits"]).To(HaveKeyWithValue("Category", "smoke"))
+			Expect(specs["Acme.PaymentTests/Charges"]).To(HaveKeyWithValue("outcome", "NotRun"))
+
+			byName := make(map[string]*domain.SpecRun)
+			for _, spec := range testRun.SuiteRuns[0].SpecRuns {
+				byName[spec.Name] = spec
+			}
+			Expect(byName).To(HaveKey("Adds(a: 2, b: 2)"))
+			Expect(byName["Adds(a: 2, b: 2)"].ParentName).To(Equal("Adds"))
+			Expect(byName["Adds(a: 2, b: 2)"].Parameters).To(Equal(map[string]string{"a": "2", "b": "2"}))
+		})
+
+		It("should accept a single <assembly> root", func() {
+			report := `<assembly name="Acme.Tests.dll" total="1" passed="1">
+  <collection name="Test collection for Acme.CartTests">
+    <test name="Acme.CartTests.AddsItem" type="Acme.CartTests" method="AddsItem" time="0.1" result="Pass"/>
+  </collection>
+</assembly>`
+
+			testRun, err := application.ParseXUnitXML(strings.NewReader(report))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testRun.Status).To(Equal("passed"))
+			Expect(testRun.TotalTests).To(Equal(1))
+		})
+
+		It("should reject documents that are not xUnit reports", func() {
+			_, err := application.ParseXUnitXML(strings.NewReader(`<html></html>`))
+			Expect(err).To(MatchError(ContainSubstring("unexpected root element")))
+		})
+	})
+})
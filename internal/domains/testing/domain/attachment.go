@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// StorageBackendDatabase marks attachments whose content is kept inline in the database.
+// Attachments uploaded before blob storage existed, and those recorded while no blob store
+// is configured, are stored this way.
+const StorageBackendDatabase = "database"
+
+// Attachment is a file captured during a test run, such as a screenshot, a video or a log.
+// It belongs to a test run and optionally to one of its suites or specs.
+type Attachment struct {
+	ID          uint      `json:"id"`
+	ProjectID   string    `json:"project_id"`
+	TestRunID   uint      `json:"test_run_id"`
+	SuiteRunID  *uint     `json:"suite_run_id,omitempty"`
+	SpecRunID   *uint     `json:"spec_run_id,omitempty"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	SHA256      string    `json:"sha256"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// StorageBackend names the blob store holding the content and StorageKey locates it there
+	StorageBackend string `json:"-"`
+	StorageKey     string `json:"-"`
+
+	// Content holds the file until it is written to a blob store or, for the database
+	// backend, to the attachment row itself
+	Content []byte `json:"-"`
+}
+
+// AttachmentFilter selects the attachments of a test run, or of one of its suites or specs
+type AttachmentFilter struct {
+	TestRunID  uint
+	SuiteRunID *uint
+	SpecRunID  *uint
+}
+
+// AttachmentRepository defines the interface for attachment persistence
+type AttachmentRepository interface {
+	// Create records an attachment whose content has already been stored
+	Create(ctx context.Context, attachment *Attachment) error
+
+	// GetByID retrieves an attachment, returning ErrAttachmentNotFound if there is none
+	GetByID(ctx context.Context, id uint) (*Attachment, error)
+
+	// Find retrieves the attachments matching the filter, oldest first
+	Find(ctx context.Context, filter AttachmentFilter) ([]*Attachment, error)
+
+	// GetContent retrieves the content of an attachment kept in the database
+	GetContent(ctx context.Context, id uint) ([]byte, error)
+
+	// TotalSizeByProject sums the size of all of a project's attachments
+	TotalSizeByProject(ctx context.Context, projectID string) (int64, error)
+}
+
+// BlobStore stores attachment content by key
+type BlobStore interface {
+	// Name identifies the backend, e.g. "local" or "s3"
+	Name() string
+
+	// Put stores size bytes read from r under key
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+
+	// Get opens the content stored under key, returning ErrBlobNotFound if there is none
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the content stored under key, if any
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "errors"
+
+var (
+	// ErrRunIDConflict is returned when a run ID is already used by another project
+	ErrRunIDConflict = errors.New("run ID is already used by another project")
+
+	// ErrIdempotencyKeyExists is returned when an idempotency key has already been claimed
+	ErrIdempotencyKeyExists = errors.New("idempotency key already exists")
+
+	// ErrIdempotencyKeyMismatch is returned when an idempotency key is reused with a different payload
+	ErrIdempotencyKeyMismatch = errors.New("idempotency key was already used with a different request payload")
+
+	// ErrIdempotencyKeyInProgress is returned when the original request for a key has not finished yet
+	ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still being processed")
+
+	// ErrIngestionQueueFull is returned when the ingestion queue is at capacity
+	ErrIngestionQueueFull = errors.New("ingestion queue is full, retry later")
+
+	// ErrIngestionJobNotFound is returned when an ingestion job does not exist
+	ErrIngestionJobNotFound = errors.New("ingestion job not found")
+
+	// ErrShardMismatch is returned when a shard disagrees with the run it is merged into
+	ErrShardMismatch = errors.New("shard does not match the run group it belongs to")
+
+	// ErrAttachmentNotFound is returned when an attachment does not exist
+	ErrAttachmentNotFound = errors.New("attachment not found")
+
+	// ErrAttachmentTooLarge is returned when a single attachment exceeds the size limit
+	ErrAttachmentTooLarge = errors.New("attachment exceeds the maximum attachment size")
+
+	// ErrAttachmentQuotaExceeded is returned when an attachment would take a project over its storage quota
+	ErrAttachmentQuotaExceeded = errors.New("project attachment storage quota exceeded")
+
+	// ErrBlobNotFound is returned when a blob store holds nothing under a key
+	ErrBlobNotFound = errors.New("blob not found")
+
+	// ErrSpecOutputNotFound is returned when no output was captured for a spec run
+	ErrSpecOutputNotFound = errors.New("spec output not found")
+
+	// ErrTestCaseNotFound is returned when a test case does not exist
+	ErrTestCaseNotFound = errors.New("test case not found")
+
+	// ErrTestRunFinished is returned when results are streamed to a run that is no longer running
+	ErrTestRunFinished = errors.New("test run has already finished")
+
+	// ErrQuarantineNotFound is returned when a quarantine does not exist
+	ErrQuarantineNotFound = errors.New("quarantine not found")
+
+	// ErrTestAlreadyQuarantined is returned when quarantining a test that is already quarantined
+	ErrTestAlreadyQuarantined = errors.New("test is already quarantined")
+)
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// TestRunCompletedEvent is the name of TestRunCompleted events
+const TestRunCompletedEvent = "testing.test_run_completed"
+
+// TestRunCompleted is published once the results of a test run are stored: when a run is
+// completed or ingested, created or updated with a terminal status, when a shard is merged
+// into its run and when a streamed run finishes
+type TestRunCompleted struct {
+	TestRunID   uint
+	RunID       string
+	ProjectID   string
+	Status      string
+	CompletedAt time.Time
+}
+
+// EventName identifies TestRunCompleted events
+func (TestRunCompleted) EventName() string {
+	return TestRunCompletedEvent
+}
+
+// IsTerminalRunStatus reports whether a run with the given status has finished. Runs that are
+// pending, queued or still running have not.
+func IsTerminalRunStatus(status string) bool {
+	switch status {
+	case "", "pending", "queued", "running":
+		return false
+	}
+	return true
+}
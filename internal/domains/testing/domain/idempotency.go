@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// IdempotencyRecord remembers the outcome of an ingestion request submitted with an idempotency key
+type IdempotencyRecord struct {
+	ProjectID      string
+	Key            string
+	RequestHash    string
+	TestRunID      uint
+	ResponseStatus int
+	ResponseBody   []byte
+	CreatedAt      time.Time
+	ClaimedAt      time.Time // When the request processing the key claimed it
+	ExpiresAt      time.Time
+}
+
+// IsCompleted returns true once the original request has stored its response
+func (r *IdempotencyRecord) IsCompleted() bool {
+	return r.ResponseStatus != 0
+}
+
+// IsAbandoned returns true if the request that claimed the key has held it for longer than lease
+// without completing it
+func (r *IdempotencyRecord) IsAbandoned(now time.Time, lease time.Duration) bool {
+	return !r.IsCompleted() && !now.Before(r.ClaimedAt.Add(lease))
+}
+
+// IsExpired returns true if the record is past its TTL
+func (r *IdempotencyRecord) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+// IdempotencyKeyRepository defines the interface for idempotency key persistence
+type IdempotencyKeyRepository interface {
+	// Create stores a new in-progress record, returning ErrIdempotencyKeyExists if the key is taken
+	Create(ctx context.Context, record *IdempotencyRecord) error
+
+	// Get retrieves the record for a project's key, returning nil if there is none
+	Get(ctx context.Context, projectID, key string) (*IdempotencyRecord, error)
+
+	// Reclaim claims an abandoned record again. It returns false if the record was completed or
+	// reclaimed since it was read.
+	Reclaim(ctx context.Context, record *IdempotencyRecord, claimedAt time.Time) (bool, error)
+
+	// Complete stores the response of the original request
+	Complete(ctx context.Context, record *IdempotencyRecord) error
+
+	// Delete removes the record for a project's key
+	Delete(ctx context.Context, projectID, key string) error
+
+	// DeleteExpired removes all records that expired before the given time
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// IngestionJobStatus is the processing state of a queued ingestion
+type IngestionJobStatus string
+
+const (
+	// IngestionJobQueued jobs are waiting for a worker, either for the first time or for a retry
+	IngestionJobQueued IngestionJobStatus = "queued"
+
+	// IngestionJobProcessing jobs have been claimed by a worker
+	IngestionJobProcessing IngestionJobStatus = "processing"
+
+	// IngestionJobSucceeded jobs have been recorded as a test run
+	IngestionJobSucceeded IngestionJobStatus = "succeeded"
+
+	// IngestionJobDeadLettered jobs failed permanently and will not be retried
+	IngestionJobDeadLettered IngestionJobStatus = "dead_lettered"
+)
+
+// IngestionJob is a test report accepted for asynchronous ingestion
+type IngestionJob struct {
+	ID             string
+	ProjectID      string
+	Format         string
+	Payload        []byte
+	RunID          string
+	Branch         string
+	GitCommit      string
+	Environment    string
+	Shard          *ShardInfo
+	Status         IngestionJobStatus
+	Attempts       int
+	MaxAttempts    int
+	Errors         []IngestionJobError
+	TestRunID      uint
+	NextAttemptAt  time.Time
+	LeaseExpiresAt *time.Time
+	StartedAt      *time.Time
+	CompletedAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// IngestionJobError records why a processing attempt failed
+type IngestionJobError struct {
+	Attempt    int       `json:"attempt"`
+	Message    string    `json:"message"`
+	Retryable  bool      `json:"retryable"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+// IsFinished returns true once the job has succeeded or been dead-lettered
+func (j *IngestionJob) IsFinished() bool {
+	return j.Status == IngestionJobSucceeded || j.Status == IngestionJobDeadLettered
+}
+
+// IngestionJobRepository defines the interface for the durable ingestion queue
+type IngestionJobRepository interface {
+	// Enqueue stores a new queued job unless capacity jobs are already queued or processing,
+	// in which case it fails with ErrIngestionQueueFull. The check and the insert are atomic.
+	Enqueue(ctx context.Context, job *IngestionJob, capacity int) error
+
+	// Get retrieves a job by ID without its payload, returning nil if there is none
+	Get(ctx context.Context, id string) (*IngestionJob, error)
+
+	// ClaimNext marks the oldest due job as processing, leasing it until leaseExpiresAt, and
+	// returns it with its attempt counter incremented. Processing jobs whose lease has expired
+	// are claimed again. It returns nil when no job is due.
+	ClaimNext(ctx context.Context, now, leaseExpiresAt time.Time) (*IngestionJob, error)
+
+	// Succeed marks a claimed job as succeeded with the test run it produced, dropping its payload
+	Succeed(ctx context.Context, id string, testRunID uint, completedAt time.Time) error
+
+	// Fail records a failed attempt. A nil retryAt dead-letters the job, otherwise it is
+	// queued again for retryAt.
+	Fail(ctx context.Context, id string, jobErr IngestionJobError, retryAt *time.Time) error
+
+	// Release returns a claimed job to the queue without counting the attempt
+	Release(ctx context.Context, id string) error
+
+	// DeleteFinished removes succeeded and dead-lettered jobs completed before the given time,
+	// returning how many were removed
+	DeleteFinished(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,28 @@
+package domain
+
+// LiveRunStatus returns the status a streamed run or suite finishes with. A status sent by the
+// reporter wins; otherwise it fails if any spec failed.
+func LiveRunStatus(reported string, failedTests int) string {
+	if reported != "" {
+		return reported
+	}
+	if failedTests > 0 {
+		return "failed"
+	}
+	return "passed"
+}
+
+// LiveSpecCounts returns how a spec with the given status changes the running total, passed,
+// failed and skipped counters of its suite and run
+func LiveSpecCounts(status string) (total, passed, failed, skipped int) {
+	switch status {
+	case "passed":
+		return 1, 1, 0, 0
+	case "failed":
+		return 1, 0, 1, 0
+	case "skipped":
+		return 1, 0, 0, 1
+	default:
+		return 1, 0, 0, 0
+	}
+}
@@ -0,0 +1,40 @@
+package domain_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+var _ = Describe("Live runs", Label("unit", "domain", "testing"), func() {
+	Describe("LiveRunStatus", func() {
+		It("should keep the status sent by the reporter", func() {
+			Expect(domain.LiveRunStatus("cancelled", 0)).To(Equal("cancelled"))
+		})
+
+		It("should fail when a spec failed", func() {
+			Expect(domain.LiveRunStatus("", 1)).To(Equal("failed"))
+		})
+
+		It("should pass when no spec failed", func() {
+			Expect(domain.LiveRunStatus("", 0)).To(Equal("passed"))
+		})
+	})
+
+	Describe("LiveSpecCounts", func() {
+		It("should count a spec under its status", func() {
+			Expect(counts(domain.LiveSpecCounts("passed"))).To(Equal([4]int{1, 1, 0, 0}))
+			Expect(counts(domain.LiveSpecCounts("failed"))).To(Equal([4]int{1, 0, 1, 0}))
+			Expect(counts(domain.LiveSpecCounts("skipped"))).To(Equal([4]int{1, 0, 0, 1}))
+		})
+
+		It("should only count other statuses towards the total", func() {
+			Expect(counts(domain.LiveSpecCounts("pending"))).To(Equal([4]int{1, 0, 0, 0}))
+		})
+	})
+})
+
+func counts(total, passed, failed, skipped int) [4]int {
+	return [4]int{total, passed, failed, skipped}
+}
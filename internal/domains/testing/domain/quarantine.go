@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+	"time"
+)
+
+// QuarantineStatus is where a quarantine is in its lifetime
+type QuarantineStatus string
+
+const (
+	QuarantineActive  QuarantineStatus = "active"  // Failures of the test are left out of pass rates
+	QuarantineExpired QuarantineStatus = "expired" // Ran past its expiry
+	QuarantineLifted  QuarantineStatus = "lifted"  // Lifted before it expired
+)
+
+// Quarantine keeps a test that is known to be flaky from failing its runs while it is fixed.
+// CI fetches the active quarantines of a project to skip or soft-fail their tests, and failures
+// of a quarantined test are still ingested but left out of pass rates. A quarantine of a
+// parameterised test covers all of its parameter sets. Quarantines expire on their own.
+type Quarantine struct {
+	ID         uint       `json:"id"`
+	ProjectID  string     `json:"project_id"`
+	TestCaseID uint       `json:"test_case_id"`
+	TestCase   *TestCase  `json:"test_case,omitempty"`
+	Reason     string     `json:"reason"`
+	Owner      string     `json:"owner"`      // Who is fixing the test
+	JiraIssue  string     `json:"jira_issue"` // Key of the Jira issue tracking the fix, e.g. "PAY-123"
+	CreatedBy  string     `json:"created_by"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LiftedAt   *time.Time `json:"lifted_at,omitempty"`
+	LiftedBy   string     `json:"lifted_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Status returns whether the quarantine is active, expired or lifted at a given time
+func (q *Quarantine) Status(now time.Time) QuarantineStatus {
+	switch {
+	case q.LiftedAt != nil:
+		return QuarantineLifted
+	case !now.Before(q.ExpiresAt):
+		return QuarantineExpired
+	default:
+		return QuarantineActive
+	}
+}
+
+// QuarantineFilter selects the quarantines of a project
+type QuarantineFilter struct {
+	ProjectID  string
+	TestCaseID uint      // Only quarantines of this test case, or of every test when 0
+	ActiveAt   time.Time // Only quarantines active at this time, or every quarantine when zero
+}
+
+// QuarantineRepository defines the interface for quarantine persistence
+type QuarantineRepository interface {
+	// Create records a quarantine
+	Create(ctx context.Context, quarantine *Quarantine) error
+
+	// GetByID retrieves a quarantine with its test case, returning ErrQuarantineNotFound if
+	// there is none
+	GetByID(ctx context.Context, id uint) (*Quarantine, error)
+
+	// Find retrieves the quarantines matching the filter with their test cases, newest first
+	Find(ctx context.Context, filter QuarantineFilter) ([]*Quarantine, error)
+
+	// Lift ends a quarantine that has not been lifted yet, returning ErrQuarantineNotFound if
+	// there is no such quarantine
+	Lift(ctx context.Context, id uint, liftedBy string, at time.Time) error
+}
+
+// SkipPattern returns a regular expression for Ginkgo's --skip flag matching the spec of the
+// quarantined test, or "" when its test case is not loaded. Ginkgo matches the texts of a spec's
+// containers and its own, joined by spaces, so the test's name matches at the end of the text.
+func (q *Quarantine) SkipPattern() string {
+	if q.TestCase == nil || q.TestCase.Name == "" {
+		return ""
+	}
+	return "(^| )" + regexp.QuoteMeta(q.TestCase.Name) + "$"
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
 // TestRunRepository defines the interface for test run persistence
@@ -35,6 +36,48 @@ type TestRunRepository interface {
 
 	// GetRecent retrieves recent test runs across all projects
 	GetRecent(ctx context.Context, limit int) ([]*TestRun, error)
+
+	// CreateWithHierarchy persists a test run together with its suites, specs and
+	// tags in a single transaction
+	CreateWithHierarchy(ctx context.Context, testRun *TestRun, tagNames []string) error
+
+	// AddTags attaches tags to a test run by name, creating missing tags and
+	// keeping existing associations
+	AddTags(ctx context.Context, testRunID uint, tagNames []string) error
+
+	// FindWithUnpersistedSuiteRuns retrieves test runs with an ID greater than afterID whose
+	// suites are only recorded in metadata["suite_runs"], ordered by ID
+	FindWithUnpersistedSuiteRuns(ctx context.Context, afterID uint, limit int) ([]*TestRun, error)
+
+	// MergeShard adds one shard's suites, specs and tags to the run identified by the shard's
+	// group ID, creating the run for the first shard. A resubmitted shard replaces its earlier
+	// suites. testRun is updated with the merged run's ID, counters, times and status.
+	MergeShard(ctx context.Context, testRun *TestRun, shard ShardInfo, tagNames []string) error
+
+	// FinalizeShardGroups closes sharded runs created before the given time that are still
+	// waiting for shards, and returns how many were closed
+	FinalizeShardGroups(ctx context.Context, createdBefore time.Time) (int, error)
+
+	// StartLiveRun creates a running test run whose results are streamed in as they finish, or
+	// resumes the running streamed run with the same run ID. testRun is updated with the run's
+	// ID and counters. Returns ErrTestRunFinished if the run has already finished.
+	StartLiveRun(ctx context.Context, testRun *TestRun) error
+
+	// AddLiveSpecRun stores a finished spec under the suite of a streamed run with the given
+	// name, creating the suite for its first spec, and adds it to the suite's and run's counters.
+	// testRun is updated with the run's counters.
+	AddLiveSpecRun(ctx context.Context, testRun *TestRun, suiteName string, specRun *SpecRun) error
+
+	// RecordHeartbeat notes that the reporter of a streamed run is still alive
+	RecordHeartbeat(ctx context.Context, testRunID uint, at time.Time) error
+
+	// FinishLiveRun closes a streamed run and its suites with testRun's status, or with a status
+	// derived from their counters if it has none. testRun is updated with the final run.
+	FinishLiveRun(ctx context.Context, testRun *TestRun) error
+
+	// AbortStalledRuns marks streamed runs still running whose last heartbeat was before the
+	// given time as aborted, and returns how many were aborted
+	AbortStalledRuns(ctx context.Context, heartbeatBefore time.Time) (int, error)
 }
 
 // SuiteRunRepository defines the interface for suite run persistence
@@ -0,0 +1,55 @@
+package domain
+
+import "fmt"
+
+// ShardInfo identifies one shard of a test run split across parallel CI jobs.
+// Shards sharing a GroupID are merged into a single test run whose run ID is the group ID.
+type ShardInfo struct {
+	GroupID string
+	Index   int // zero-based
+	Total   int
+}
+
+// Validate checks that the shard belongs to a group and its index is within range
+func (s ShardInfo) Validate() error {
+	if s.GroupID == "" {
+		return fmt.Errorf("shard group ID is required")
+	}
+	if s.Total < 1 {
+		return fmt.Errorf("shard total must be at least 1")
+	}
+	if s.Index < 0 || s.Index >= s.Total {
+		return fmt.Errorf("shard index %d is out of range for %d shards", s.Index, s.Total)
+	}
+	return nil
+}
+
+// ShardedRunStatus returns the status of a merged run. The run keeps running until every
+// shard has reported or the group has timed out; it then fails if any spec failed or any
+// shard is missing.
+func ShardedRunStatus(shardTotal, shardsReceived, failedTests int, timedOut bool) string {
+	complete := shardsReceived >= shardTotal
+	if !complete && !timedOut {
+		return "running"
+	}
+	if failedTests > 0 || !complete {
+		return "failed"
+	}
+	return "passed"
+}
+
+// MissingShards returns the indexes of a group's shards that have not been received
+func MissingShards(shardTotal int, received []int) []int {
+	seen := make(map[int]bool, len(received))
+	for _, index := range received {
+		seen[index] = true
+	}
+
+	missing := []int{}
+	for index := 0; index < shardTotal; index++ {
+		if !seen[index] {
+			missing = append(missing, index)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,55 @@
+package domain_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+var _ = Describe("Shards", Label("unit", "domain", "testing"), func() {
+	Describe("ShardInfo.Validate", func() {
+		It("should accept an index within the group", func() {
+			Expect(domain.ShardInfo{GroupID: "build-42", Index: 2, Total: 3}.Validate()).To(Succeed())
+		})
+
+		It("should reject a missing group ID", func() {
+			err := domain.ShardInfo{Index: 0, Total: 2}.Validate()
+			Expect(err).To(MatchError(ContainSubstring("group ID is required")))
+		})
+
+		It("should reject an index outside the group", func() {
+			Expect(domain.ShardInfo{GroupID: "build-42", Index: 3, Total: 3}.Validate()).NotTo(Succeed())
+			Expect(domain.ShardInfo{GroupID: "build-42", Index: -1, Total: 3}.Validate()).NotTo(Succeed())
+			Expect(domain.ShardInfo{GroupID: "build-42", Index: 0, Total: 0}.Validate()).NotTo(Succeed())
+		})
+	})
+
+	Describe("ShardedRunStatus", func() {
+		It("should keep running until every shard has reported", func() {
+			Expect(domain.ShardedRunStatus(3, 2, 1, false)).To(Equal("running"))
+		})
+
+		It("should pass once every shard has reported without failures", func() {
+			Expect(domain.ShardedRunStatus(3, 3, 0, false)).To(Equal("passed"))
+		})
+
+		It("should fail once every shard has reported with failures", func() {
+			Expect(domain.ShardedRunStatus(3, 3, 2, false)).To(Equal("failed"))
+		})
+
+		It("should fail a timed out group with missing shards", func() {
+			Expect(domain.ShardedRunStatus(3, 2, 0, true)).To(Equal("failed"))
+		})
+	})
+
+	Describe("MissingShards", func() {
+		It("should list the indexes that have not been received", func() {
+			Expect(domain.MissingShards(4, []int{0, 2})).To(Equal([]int{1, 3}))
+		})
+
+		It("should return an empty list when every shard has been received", func() {
+			Expect(domain.MissingShards(2, []int{1, 0})).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// SpecAttempt is one attempt at a spec that was retried within a run, such as a Ginkgo
+// FlakeAttempts retry, a Surefire rerun or a Playwright retry
+type SpecAttempt struct {
+	ID           uint          `json:"id"`
+	SpecRunID    uint          `json:"spec_run_id"`
+	Attempt      int           `json:"attempt"` // 1 for the first attempt
+	Status       string        `json:"status"`
+	StartTime    time.Time     `json:"start_time"`
+	Duration     time.Duration `json:"duration"`
+	ErrorMessage string        `json:"error_message"`
+	StackTrace   string        `json:"stack_trace"`
+}
+
+// ClassifyAttempts numbers the attempts of a retried spec in the order they ran, derives its
+// retry count from them and marks it flaky when it passed after a failed attempt. The spec's
+// own status is the outcome of its last attempt.
+func (s *SpecRun) ClassifyAttempts() {
+	if len(s.Attempts) == 0 {
+		return
+	}
+	for i := range s.Attempts {
+		s.Attempts[i].Attempt = i + 1
+	}
+	if retries := len(s.Attempts) - 1; retries > s.RetryCount {
+		s.RetryCount = retries
+	}
+	if s.FlakyInRun() {
+		s.IsFlaky = true
+	}
+}
+
+// FlakyInRun reports whether the spec passed after at least one failed attempt in the same run
+func (s *SpecRun) FlakyInRun() bool {
+	if s.Status != "passed" {
+		return false
+	}
+	for _, attempt := range s.Attempts {
+		if attempt.Status == "failed" {
+			return true
+		}
+	}
+	return false
+}
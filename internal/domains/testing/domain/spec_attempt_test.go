@@ -0,0 +1,68 @@
+package domain_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+var _ = Describe("SpecAttempt", Label("unit", "domain", "testing"), func() {
+	Describe("ClassifyAttempts", func() {
+		It("should leave a spec without attempts alone", func() {
+			spec := &domain.SpecRun{Status: "passed", RetryCount: 2}
+
+			spec.ClassifyAttempts()
+
+			Expect(spec.RetryCount).To(Equal(2))
+			Expect(spec.IsFlaky).To(BeFalse())
+		})
+
+		It("should number attempts and mark a spec that passed on retry as flaky", func() {
+			spec := &domain.SpecRun{
+				Status: "passed",
+				Attempts: []domain.SpecAttempt{
+					{Status: "failed", ErrorMessage: "timeout"},
+					{Status: "failed", ErrorMessage: "timeout"},
+					{Status: "passed"},
+				},
+			}
+
+			spec.ClassifyAttempts()
+
+			Expect(spec.Attempts[0].Attempt).To(Equal(1))
+			Expect(spec.Attempts[2].Attempt).To(Equal(3))
+			Expect(spec.RetryCount).To(Equal(2))
+			Expect(spec.FlakyInRun()).To(BeTrue())
+			Expect(spec.IsFlaky).To(BeTrue())
+		})
+
+		It("should not mark a spec that failed every attempt as flaky", func() {
+			spec := &domain.SpecRun{
+				Status: "failed",
+				Attempts: []domain.SpecAttempt{
+					{Status: "failed"},
+					{Status: "failed"},
+				},
+			}
+
+			spec.ClassifyAttempts()
+
+			Expect(spec.RetryCount).To(Equal(1))
+			Expect(spec.FlakyInRun()).To(BeFalse())
+			Expect(spec.IsFlaky).To(BeFalse())
+		})
+
+		It("should keep a flaky mark set by hand", func() {
+			spec := &domain.SpecRun{
+				Status:   "passed",
+				IsFlaky:  true,
+				Attempts: []domain.SpecAttempt{{Status: "passed"}},
+			}
+
+			spec.ClassifyAttempts()
+
+			Expect(spec.IsFlaky).To(BeTrue())
+		})
+	})
+})
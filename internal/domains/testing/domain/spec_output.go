@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// SpecOutput is the console output captured while a spec ran
+type SpecOutput struct {
+	SpecRunID  uint      `json:"spec_run_id"`
+	ProjectID  string    `json:"project_id,omitempty"` // Project of the spec run's test run, set when read back
+	Stdout     string    `json:"stdout"`
+	Stderr     string    `json:"stderr"`
+	StdoutSize int64     `json:"stdout_size"` // Bytes captured, before truncation
+	StderrSize int64     `json:"stderr_size"`
+	Truncated  bool      `json:"truncated"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewSpecOutput captures a spec's output, returning nil when there is none
+func NewSpecOutput(stdout, stderr string) *SpecOutput {
+	stdout = strings.TrimRight(stdout, " \t\r\n")
+	stderr = strings.TrimRight(stderr, " \t\r\n")
+	if strings.TrimSpace(stdout) == "" && strings.TrimSpace(stderr) == "" {
+		return nil
+	}
+	return &SpecOutput{
+		Stdout:     stdout,
+		Stderr:     stderr,
+		StdoutSize: int64(len(stdout)),
+		StderrSize: int64(len(stderr)),
+	}
+}
+
+// Truncate caps each stream at maxSize bytes, keeping its beginning and end around a marker
+// saying how much was left out. A maxSize of 0 or less keeps everything.
+func (o *SpecOutput) Truncate(maxSize int) {
+	if maxSize <= 0 {
+		return
+	}
+	var truncated bool
+	o.Stdout, truncated = truncateOutput(o.Stdout, maxSize)
+	o.Truncated = o.Truncated || truncated
+	o.Stderr, truncated = truncateOutput(o.Stderr, maxSize)
+	o.Truncated = o.Truncated || truncated
+}
+
+// truncateOutput keeps the first and last halves of s when it is longer than maxSize,
+// cutting on rune boundaries
+func truncateOutput(s string, maxSize int) (string, bool) {
+	if len(s) <= maxSize {
+		return s, false
+	}
+
+	head := maxSize / 2
+	for head > 0 && !utf8.RuneStart(s[head]) {
+		head--
+	}
+	tail := len(s) - (maxSize - maxSize/2)
+	for tail < len(s) && !utf8.RuneStart(s[tail]) {
+		tail++
+	}
+	return fmt.Sprintf("%s\n... [%d bytes truncated] ...\n%s", s[:head], tail-head, s[tail:]), true
+}
+
+// SpecOutputSearch selects recent spec runs of a project whose output matches a full-text query
+type SpecOutputSearch struct {
+	ProjectID string
+	Query     string // Web search syntax: words, "quoted phrases", or, -excluded
+	Since     time.Time
+	Status    string // Only spec runs with this status, or any status when empty
+	Limit     int
+}
+
+// SpecOutputMatch is a spec run whose output matched a search, with the run it belongs to
+type SpecOutputMatch struct {
+	SpecRun   *SpecRun
+	SuiteName string
+	TestRunID uint
+	RunID     string
+	Branch    string
+	StartTime time.Time // Start of the test run
+	Output    *SpecOutput
+	Snippet   string // Lines of the output around the first match
+}
+
+// SpecOutputRepository reads captured spec output; it is written together with its spec run
+type SpecOutputRepository interface {
+	GetBySpecRunID(ctx context.Context, specRunID uint) (*SpecOutput, error)
+	Search(ctx context.Context, search SpecOutputSearch) ([]*SpecOutputMatch, error)
+}
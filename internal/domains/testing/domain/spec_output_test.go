@@ -0,0 +1,70 @@
+package domain_test
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+var _ = Describe("SpecOutput", Label("unit", "domain", "testing"), func() {
+	Describe("NewSpecOutput", func() {
+		It("should return nil when nothing was captured", func() {
+			Expect(domain.NewSpecOutput("", " \n\t")).To(BeNil())
+		})
+
+		It("should trim trailing whitespace and record sizes", func() {
+			output := domain.NewSpecOutput("  indented\nline\n\n", "warning\n")
+
+			Expect(output.Stdout).To(Equal("  indented\nline"))
+			Expect(output.Stderr).To(Equal("warning"))
+			Expect(output.StdoutSize).To(Equal(int64(15)))
+			Expect(output.StderrSize).To(Equal(int64(7)))
+			Expect(output.Truncated).To(BeFalse())
+		})
+	})
+
+	Describe("Truncate", func() {
+		It("should leave output within the limit alone", func() {
+			output := domain.NewSpecOutput("short", "")
+
+			output.Truncate(10)
+
+			Expect(output.Stdout).To(Equal("short"))
+			Expect(output.Truncated).To(BeFalse())
+		})
+
+		It("should keep the beginning and end of long output", func() {
+			output := domain.NewSpecOutput("start-"+strings.Repeat("x", 100)+"-end", "")
+
+			output.Truncate(20)
+
+			Expect(output.Stdout).To(HavePrefix("start-xxxx"))
+			Expect(output.Stdout).To(HaveSuffix("xxxxxx-end"))
+			Expect(output.Stdout).To(ContainSubstring("[90 bytes truncated]"))
+			Expect(output.StdoutSize).To(Equal(int64(110)))
+			Expect(output.Truncated).To(BeTrue())
+		})
+
+		It("should not split multi-byte characters", func() {
+			output := domain.NewSpecOutput(strings.Repeat("é", 50), "")
+
+			output.Truncate(11)
+
+			Expect(utf8.ValidString(output.Stdout)).To(BeTrue())
+			Expect(output.Truncated).To(BeTrue())
+		})
+
+		It("should keep everything when the limit is not positive", func() {
+			output := domain.NewSpecOutput(strings.Repeat("x", 100), "")
+
+			output.Truncate(0)
+
+			Expect(output.Stdout).To(HaveLen(100))
+			Expect(output.Truncated).To(BeFalse())
+		})
+	})
+})
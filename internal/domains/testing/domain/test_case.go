@@ -0,0 +1,260 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TestCase is the stable identity of a test across runs. Spec runs of the same test in the same
+// project share a test case, whichever branch, environment or report format they came from.
+// Each parameter set of a parameterised or table-driven test is a test case of its own, whose
+// parent is the test case of the test as a whole.
+type TestCase struct {
+	ID          uint              `json:"id"`
+	ProjectID   string            `json:"project_id"`
+	ParentID    *uint             `json:"parent_id,omitempty"` // Test this is one parameter set of
+	SuiteName   string            `json:"suite_name"`
+	PackageName string            `json:"package_name"`
+	ClassName   string            `json:"class_name"`
+	Name        string            `json:"name"`
+	Parameters  map[string]string `json:"parameters,omitempty"`
+	Fingerprint string            `json:"fingerprint"`
+	FirstSeenAt time.Time         `json:"first_seen_at"`
+	LastSeenAt  time.Time         `json:"last_seen_at"`
+}
+
+// NewTestCase identifies the test a spec run of a suite executed. A parameter set whose spec is
+// named after its test alone is told apart by its parameters, as in "adds [a=1, b=2]".
+func NewTestCase(projectID string, suite *SuiteRun, spec *SpecRun) TestCase {
+	testCase := newTestCase(projectID, suite, spec, spec.Name)
+	if parent := spec.ParentTestName(); parent != "" && len(spec.Parameters) > 0 {
+		testCase.Parameters = make(map[string]string, len(spec.Parameters))
+		for key, value := range spec.Parameters {
+			testCase.Parameters[key] = value
+		}
+		if normalizeTestCasePart(testCase.Name) == normalizeTestCasePart(parent) {
+			testCase.Name = fmt.Sprintf("%s [%s]", testCase.Name, ParameterLabel(spec.Parameters))
+		}
+	}
+	testCase.Fingerprint = TestCaseFingerprint(projectID, testCase.SuiteName, testCase.PackageName, testCase.ClassName, testCase.Name)
+	return testCase
+}
+
+// NewParentTestCase identifies the test a spec run is one parameter set of. It returns false
+// when the spec isn't parameterised.
+func NewParentTestCase(projectID string, suite *SuiteRun, spec *SpecRun) (TestCase, bool) {
+	parent := spec.ParentTestName()
+	if parent == "" {
+		return TestCase{}, false
+	}
+	testCase := newTestCase(projectID, suite, spec, parent)
+	testCase.Fingerprint = TestCaseFingerprint(projectID, testCase.SuiteName, testCase.PackageName, testCase.ClassName, testCase.Name)
+	return testCase, true
+}
+
+func newTestCase(projectID string, suite *SuiteRun, spec *SpecRun, name string) TestCase {
+	testCase := TestCase{
+		ProjectID:   projectID,
+		SuiteName:   strings.TrimSpace(suite.Name),
+		PackageName: strings.TrimSpace(suite.PackageName),
+		ClassName:   strings.TrimSpace(spec.ClassName),
+		Name:        strings.TrimSpace(name),
+	}
+	if testCase.ClassName == "" {
+		testCase.ClassName = strings.TrimSpace(suite.ClassName)
+	}
+	return testCase
+}
+
+// ParentTestName returns the name of the test the spec is one parameter set of, or "" when it
+// isn't parameterised. Specs with parameters but no parent name are parameter sets of the test
+// they are named after.
+func (s *SpecRun) ParentTestName() string {
+	parent := strings.TrimSpace(s.ParentName)
+	if parent == "" && len(s.Parameters) > 0 {
+		parent = strings.TrimSpace(s.Name)
+	}
+	// A spec named after its test is only a parameter set of it when its parameters tell it apart
+	if normalizeTestCasePart(parent) == normalizeTestCasePart(s.Name) && len(s.Parameters) == 0 {
+		return ""
+	}
+	return parent
+}
+
+// ParameterLabel formats a parameter set as "key=value" pairs ordered by key
+func ParameterLabel(parameters map[string]string) string {
+	keys := make([]string, 0, len(parameters))
+	for key := range parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + "=" + parameters[key]
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// TestCaseFingerprint derives the key of a test case from its project, suite, package, class and
+// name. Case and runs of whitespace are ignored, so cosmetic differences between report formats
+// don't split a test's history.
+func TestCaseFingerprint(projectID, suiteName, packageName, className, name string) string {
+	parts := []string{projectID, suiteName, packageName, className, name}
+	for i, part := range parts {
+		parts[i] = normalizeTestCasePart(part)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x1f")))
+	return hex.EncodeToString(sum[:])
+}
+
+func normalizeTestCasePart(part string) string {
+	return strings.ToLower(strings.Join(strings.Fields(part), " "))
+}
+
+// TestCaseExecution is one spec run of a test case, or of one of its parameter sets, with the
+// run it belongs to
+type TestCaseExecution struct {
+	SpecRunID    uint              `json:"spec_run_id"`
+	TestCaseID   uint              `json:"test_case_id"`
+	TestRunID    uint              `json:"test_run_id"`
+	RunID        string            `json:"run_id"`
+	Branch       string            `json:"branch"`
+	Environment  string            `json:"environment"`
+	GitCommit    string            `json:"git_commit"`
+	Parameters   map[string]string `json:"parameters,omitempty"` // Parameter set the spec ran with
+	Status       string            `json:"status"`
+	StartTime    time.Time         `json:"start_time"`
+	Duration     time.Duration     `json:"duration"`
+	ErrorMessage string            `json:"error_message"`
+	RetryCount   int               `json:"retry_count"`
+	IsFlaky      bool              `json:"is_flaky"`
+	Quarantined  bool              `json:"quarantined"` // Failed while the test was quarantined
+}
+
+// TestCaseHistoryFilter selects the executions of a test case
+type TestCaseHistoryFilter struct {
+	Since       time.Time
+	Branch      string // Only runs of this branch, or every branch when empty
+	Environment string // Only runs in this environment, or every environment when empty
+	Limit       int
+}
+
+// TestCaseStats summarises a set of executions of a test case
+type TestCaseStats struct {
+	Key             string        `json:"key,omitempty"` // Branch, environment or parameter set the stats are for
+	Total           int           `json:"total"`
+	Passed          int           `json:"passed"`
+	Failed          int           `json:"failed"`
+	Skipped         int           `json:"skipped"`
+	Flaky           int           `json:"flaky"`
+	Quarantined     int           `json:"quarantined"` // Failures while the test was quarantined, included in Failed
+	PassRate        float64       `json:"pass_rate"`   // Percentage of executed (not skipped) runs that passed, quarantined failures left out
+	AverageDuration time.Duration `json:"average_duration"`
+}
+
+// TestCaseHistory is how a test case behaved over time, newest execution first. The history of
+// a parameterised test rolls up the executions of all its parameter sets.
+type TestCaseHistory struct {
+	TestCase      *TestCase           `json:"test_case"`
+	Executions    []TestCaseExecution `json:"executions"`
+	Summary       TestCaseStats       `json:"summary"`
+	ByBranch      []TestCaseStats     `json:"by_branch"`
+	ByEnvironment []TestCaseStats     `json:"by_environment"`
+	ByParameters  []TestCaseStats     `json:"by_parameters"`            // Keyed by ParameterLabel
+	ParameterSets []*TestCase         `json:"parameter_sets,omitempty"` // Parameter sets of a parameterised test
+}
+
+// NewTestCaseHistory summarises the executions of a test case overall, per branch, per
+// environment and per parameter set
+func NewTestCaseHistory(testCase *TestCase, executions []TestCaseExecution) *TestCaseHistory {
+	history := &TestCaseHistory{TestCase: testCase, Executions: executions}
+	history.Summary = summarizeExecutions("", executions)
+
+	byBranch := make(map[string][]TestCaseExecution)
+	byEnvironment := make(map[string][]TestCaseExecution)
+	byParameters := make(map[string][]TestCaseExecution)
+	for _, execution := range executions {
+		byBranch[execution.Branch] = append(byBranch[execution.Branch], execution)
+		byEnvironment[execution.Environment] = append(byEnvironment[execution.Environment], execution)
+		if len(execution.Parameters) > 0 {
+			label := ParameterLabel(execution.Parameters)
+			byParameters[label] = append(byParameters[label], execution)
+		}
+	}
+	history.ByBranch = summarizeGroups(byBranch)
+	history.ByEnvironment = summarizeGroups(byEnvironment)
+	history.ByParameters = summarizeGroups(byParameters)
+	return history
+}
+
+// summarizeGroups summarises each group of executions, busiest first
+func summarizeGroups(groups map[string][]TestCaseExecution) []TestCaseStats {
+	stats := make([]TestCaseStats, 0, len(groups))
+	for key, executions := range groups {
+		stats = append(stats, summarizeExecutions(key, executions))
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Total != stats[j].Total {
+			return stats[i].Total > stats[j].Total
+		}
+		return stats[i].Key < stats[j].Key
+	})
+	return stats
+}
+
+func summarizeExecutions(key string, executions []TestCaseExecution) TestCaseStats {
+	stats := TestCaseStats{Key: key, Total: len(executions)}
+	var totalDuration time.Duration
+	for _, execution := range executions {
+		switch execution.Status {
+		case "passed":
+			stats.Passed++
+		case "failed":
+			stats.Failed++
+		case "skipped":
+			stats.Skipped++
+		}
+		if execution.IsFlaky {
+			stats.Flaky++
+		}
+		if execution.Quarantined {
+			stats.Quarantined++
+		}
+		totalDuration += execution.Duration
+	}
+	if executed := stats.Passed + stats.Failed - stats.Quarantined; executed > 0 {
+		stats.PassRate = float64(stats.Passed) / float64(executed) * 100
+	}
+	if stats.Total > 0 {
+		stats.AverageDuration = totalDuration / time.Duration(stats.Total)
+	}
+	return stats
+}
+
+// TestCaseRepository defines the interface for test case persistence. Test cases are created
+// and linked to their spec runs when the spec runs are stored.
+type TestCaseRepository interface {
+	// GetByID retrieves a test case by ID
+	GetByID(ctx context.Context, id uint) (*TestCase, error)
+
+	// FindByProject retrieves a project's test cases whose name contains search, most recently
+	// seen first
+	FindByProject(ctx context.Context, projectID string, search string, limit int) ([]*TestCase, error)
+
+	// FindParameterSets retrieves the parameter sets of a parameterised test case
+	FindParameterSets(ctx context.Context, parentID uint) ([]*TestCase, error)
+
+	// GetHistory retrieves the executions of a test case and of its parameter sets matching the
+	// filter, newest first
+	GetHistory(ctx context.Context, testCaseID uint, filter TestCaseHistoryFilter) ([]TestCaseExecution, error)
+
+	// LinkSpecRuns links up to limit spec runs with an ID greater than afterID that have no test
+	// case yet, in ID order. It returns the last spec run ID it looked at, or 0 when none were
+	// left, and how many spec runs it linked.
+	LinkSpecRuns(ctx context.Context, afterID uint, limit int) (uint, int, error)
+}
@@ -0,0 +1,160 @@
+package domain_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+var _ = Describe("TestCase", Label("unit", "domain", "testing"), func() {
+	Describe("NewTestCase", func() {
+		It("should identify a spec by project, suite, package, class and name", func() {
+			suite := &domain.SuiteRun{Name: "CartTest", PackageName: "com.example"}
+			spec := &domain.SpecRun{Name: " addsItem ", ClassName: "com.example.CartTest"}
+
+			testCase := domain.NewTestCase("proj-1", suite, spec)
+
+			Expect(testCase.ProjectID).To(Equal("proj-1"))
+			Expect(testCase.SuiteName).To(Equal("CartTest"))
+			Expect(testCase.PackageName).To(Equal("com.example"))
+			Expect(testCase.ClassName).To(Equal("com.example.CartTest"))
+			Expect(testCase.Name).To(Equal("addsItem"))
+			Expect(testCase.Fingerprint).To(HaveLen(64))
+		})
+
+		It("should fall back to the suite's class", func() {
+			suite := &domain.SuiteRun{Name: "Cart", ClassName: "CartTests"}
+
+			testCase := domain.NewTestCase("proj-1", suite, &domain.SpecRun{Name: "adds"})
+
+			Expect(testCase.ClassName).To(Equal("CartTests"))
+		})
+
+		It("should tell apart specs with the same name in different suites", func() {
+			spec := &domain.SpecRun{Name: "handles errors"}
+
+			cart := domain.NewTestCase("proj-1", &domain.SuiteRun{Name: "Cart"}, spec)
+			payments := domain.NewTestCase("proj-1", &domain.SuiteRun{Name: "Payments"}, spec)
+			otherProject := domain.NewTestCase("proj-2", &domain.SuiteRun{Name: "Cart"}, spec)
+
+			Expect(cart.Fingerprint).NotTo(Equal(payments.Fingerprint))
+			Expect(cart.Fingerprint).NotTo(Equal(otherProject.Fingerprint))
+		})
+	})
+
+	Describe("parameter sets", func() {
+		suite := &domain.SuiteRun{Name: "Calc"}
+
+		It("should be parameter sets of the test they name as parent", func() {
+			spec := &domain.SpecRun{Name: "test_add[1-2]", ParentName: "test_add", Parameters: map[string]string{"id": "1-2"}}
+
+			testCase := domain.NewTestCase("proj-1", suite, spec)
+			parent, ok := domain.NewParentTestCase("proj-1", suite, spec)
+
+			Expect(ok).To(BeTrue())
+			Expect(testCase.Name).To(Equal("test_add[1-2]"))
+			Expect(testCase.Parameters).To(Equal(map[string]string{"id": "1-2"}))
+			Expect(parent.Name).To(Equal("test_add"))
+			Expect(parent.Parameters).To(BeNil())
+			Expect(parent.Fingerprint).To(Equal(domain.TestCaseFingerprint("proj-1", "Calc", "", "", "test_add")))
+		})
+
+		It("should tell apart parameter sets named after their test by their parameters", func() {
+			first := &domain.SpecRun{Name: "adds", Parameters: map[string]string{"b": "2", "a": "1"}}
+			second := &domain.SpecRun{Name: "adds", Parameters: map[string]string{"a": "3", "b": "4"}}
+
+			firstCase := domain.NewTestCase("proj-1", suite, first)
+			secondCase := domain.NewTestCase("proj-1", suite, second)
+			parent, ok := domain.NewParentTestCase("proj-1", suite, first)
+
+			Expect(ok).To(BeTrue())
+			Expect(firstCase.Name).To(Equal("adds [a=1, b=2]"))
+			Expect(firstCase.Fingerprint).NotTo(Equal(secondCase.Fingerprint))
+			Expect(parent.Name).To(Equal("adds"))
+		})
+
+		It("should not treat a spec named after its parent without parameters as a parameter set", func() {
+			spec := &domain.SpecRun{Name: "adds", ParentName: "adds"}
+
+			_, ok := domain.NewParentTestCase("proj-1", suite, spec)
+
+			Expect(ok).To(BeFalse())
+			Expect(domain.NewTestCase("proj-1", suite, spec).Name).To(Equal("adds"))
+		})
+
+		It("should label parameters in key order", func() {
+			Expect(domain.ParameterLabel(map[string]string{"y": "2", "x": "1"})).To(Equal("x=1, y=2"))
+		})
+	})
+
+	Describe("TestCaseFingerprint", func() {
+		It("should ignore case and runs of whitespace", func() {
+			Expect(domain.TestCaseFingerprint("proj-1", "Cart", "", "", "adds  an\titem")).
+				To(Equal(domain.TestCaseFingerprint("proj-1", " cart", "", "", "Adds an item ")))
+		})
+
+		It("should not let parts run into each other", func() {
+			Expect(domain.TestCaseFingerprint("proj-1", "a", "b", "", "c")).
+				NotTo(Equal(domain.TestCaseFingerprint("proj-1", "a b", "", "", "c")))
+		})
+	})
+
+	Describe("NewTestCaseHistory", func() {
+		It("should summarise executions overall, per branch and per environment", func() {
+			executions := []domain.TestCaseExecution{
+				{Branch: "main", Environment: "staging", Status: "passed", Duration: 2 * time.Second},
+				{Branch: "main", Environment: "prod", Status: "failed", Duration: 4 * time.Second},
+				{Branch: "feature", Environment: "staging", Status: "passed", Duration: 3 * time.Second, IsFlaky: true},
+				{Branch: "main", Environment: "staging", Status: "skipped"},
+			}
+
+			history := domain.NewTestCaseHistory(&domain.TestCase{ID: 7}, executions)
+
+			Expect(history.TestCase.ID).To(Equal(uint(7)))
+			Expect(history.Executions).To(HaveLen(4))
+			Expect(history.Summary.Total).To(Equal(4))
+			Expect(history.Summary.Passed).To(Equal(2))
+			Expect(history.Summary.Failed).To(Equal(1))
+			Expect(history.Summary.Skipped).To(Equal(1))
+			Expect(history.Summary.Flaky).To(Equal(1))
+			Expect(history.Summary.PassRate).To(BeNumerically("~", 66.67, 0.01))
+			Expect(history.Summary.AverageDuration).To(Equal(2250 * time.Millisecond))
+
+			Expect(history.ByBranch).To(HaveLen(2))
+			Expect(history.ByBranch[0].Key).To(Equal("main"))
+			Expect(history.ByBranch[0].Total).To(Equal(3))
+			Expect(history.ByBranch[1].Key).To(Equal("feature"))
+			Expect(history.ByBranch[1].PassRate).To(Equal(100.0))
+
+			Expect(history.ByEnvironment).To(HaveLen(2))
+			Expect(history.ByEnvironment[0].Key).To(Equal("staging"))
+			Expect(history.ByEnvironment[1].Failed).To(Equal(1))
+			Expect(history.ByParameters).To(BeEmpty())
+		})
+
+		It("should leave quarantined failures out of the pass rate", func() {
+			executions := []domain.TestCaseExecution{
+				{Status: "passed"},
+				{Status: "failed", Quarantined: true},
+				{Status: "failed"},
+			}
+
+			history := domain.NewTestCaseHistory(&domain.TestCase{ID: 7}, executions)
+
+			Expect(history.Summary.Failed).To(Equal(2))
+			Expect(history.Summary.Quarantined).To(Equal(1))
+			Expect(history.Summary.PassRate).To(Equal(50.0))
+		})
+
+		It("should handle a test with no executions", func() {
+			history := domain.NewTestCaseHistory(&domain.TestCase{ID: 7}, nil)
+
+			Expect(history.Summary.Total).To(BeZero())
+			Expect(history.Summary.PassRate).To(BeZero())
+			Expect(history.ByBranch).To(BeEmpty())
+		})
+	})
+})
@@ -26,6 +26,17 @@ type TestRun struct {
 	SessionID    string                 `json:"session_id"`
 	Metadata     map[string]interface{} `json:"metadata"`
 	SuiteRuns    []SuiteRun             `json:"suite_runs"`
+
+	// ShardTotal is the number of CI shards merged into this run, or 0 if it isn't sharded
+	ShardTotal     int `json:"shard_total"`
+	ShardsReceived int `json:"shards_received"`
+
+	// LastHeartbeatAt is when the reporter of a streamed run was last heard from
+	LastHeartbeatAt *time.Time `json:"last_heartbeat_at,omitempty"`
+
+	// QuarantinedTests counts the failed tests that were quarantined when they ran. They are
+	// included in FailedTests but left out of pass rates.
+	QuarantinedTests int `json:"quarantined_tests"`
 }
 
 // SuiteRun represents a test suite execution
@@ -43,30 +54,54 @@ type SuiteRun struct {
 	FailedTests  int           `json:"failed_tests"`
 	SkippedTests int           `json:"skipped_tests"`
 	Duration     time.Duration `json:"duration"`
+	ShardIndex   *int          `json:"shard_index,omitempty"` // Shard that reported the suite, if the run is sharded
 	SpecRuns     []*SpecRun    `json:"spec_runs"`
+
+	// QuarantinedTests counts the failed specs that were quarantined when they ran
+	QuarantinedTests int `json:"quarantined_tests"`
 }
 
 // SpecRun represents a single test specification execution
 type SpecRun struct {
-	ID             uint          `json:"id"`
-	SuiteRunID     uint          `json:"suite_run_id"`
-	Name           string        `json:"name"`
-	ClassName      string        `json:"class_name"`
-	Status         string        `json:"status"`
-	StartTime      time.Time     `json:"start_time"`
-	EndTime        *time.Time    `json:"end_time"`
-	Duration       time.Duration `json:"duration"`
-	ErrorMessage   string        `json:"error_message"`
-	FailureMessage string        `json:"failure_message"`
-	StackTrace     string        `json:"stack_trace"`
-	RetryCount     int           `json:"retry_count"`
-	IsFlaky        bool          `json:"is_flaky"`
+	ID             uint              `json:"id"`
+	SuiteRunID     uint              `json:"suite_run_id"`
+	TestCaseID     *uint             `json:"test_case_id,omitempty"` // Stable identity of the test, set when the spec run is stored
+	Name           string            `json:"name"`
+	ClassName      string            `json:"class_name"`
+	ParentName     string            `json:"parent_name,omitempty"` // Test the spec is one parameter set of, for table-driven and parameterised tests
+	Parameters     map[string]string `json:"parameters,omitempty"`  // Parameter set the spec ran with
+	Status         string            `json:"status"`
+	StartTime      time.Time         `json:"start_time"`
+	EndTime        *time.Time        `json:"end_time"`
+	Duration       time.Duration     `json:"duration"`
+	ErrorMessage   string            `json:"error_message"`
+	FailureMessage string            `json:"failure_message"`
+	StackTrace     string            `json:"stack_trace"`
+	RetryCount     int               `json:"retry_count"`
+	IsFlaky        bool              `json:"is_flaky"`
+	Quarantined    bool              `json:"quarantined"`        // Failed while its test was quarantined, set when the spec run is stored
+	Steps          []SpecStep        `json:"steps,omitempty"`    // Steps of a BDD scenario, in execution order
+	Attempts       []SpecAttempt     `json:"attempts,omitempty"` // Every attempt, last one included, when the spec was retried
+	Attachments    []Attachment      `json:"attachments,omitempty"`
+	Output         *SpecOutput       `json:"-"` // Captured stdout/stderr, written with the spec and read on demand
+}
+
+// SpecStep represents one step of a BDD scenario, such as a Gherkin Given/When/Then
+type SpecStep struct {
+	ID           uint          `json:"id"`
+	SpecRunID    uint          `json:"spec_run_id"`
+	Position     int           `json:"position"`
+	Keyword      string        `json:"keyword"`
+	Name         string        `json:"name"`
+	Status       string        `json:"status"`
+	Duration     time.Duration `json:"duration"`
+	ErrorMessage string        `json:"error_message"`
 }
 
 // TestRunSummary represents aggregated test run statistics
 type TestRunSummary struct {
 	TotalRuns      int           `json:"total_runs"`
-	PassedRuns     int           `json:"passed_runs"`
+	PassedRuns     int           `json:"passed_runs"` // Including runs that failed only through quarantined tests
 	FailedRuns     int           `json:"failed_runs"`
 	AverageRunTime time.Duration `json:"average_run_time"`
 	SuccessRate    float64       `json:"success_rate"`
@@ -0,0 +1,155 @@
+package infrastructure_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/infrastructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalBlobStore_PutGetDelete(t *testing.T) {
+	// Arrange
+	store, err := infrastructure.NewLocalBlobStore(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// Act
+	require.NoError(t, store.Put(ctx, "proj-1/2024/03/01/abc", strings.NewReader("hello"), 5, "text/plain"))
+	content, err := store.Get(ctx, "proj-1/2024/03/01/abc")
+	require.NoError(t, err)
+	data, err := io.ReadAll(content)
+	content.Close()
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	require.NoError(t, store.Delete(ctx, "proj-1/2024/03/01/abc"))
+	require.NoError(t, store.Delete(ctx, "proj-1/2024/03/01/abc"))
+	_, err = store.Get(ctx, "proj-1/2024/03/01/abc")
+	assert.ErrorIs(t, err, domain.ErrBlobNotFound)
+}
+
+func TestLocalBlobStore_RejectsShortWrites(t *testing.T) {
+	store, err := infrastructure.NewLocalBlobStore(t.TempDir())
+	require.NoError(t, err)
+
+	err = store.Put(context.Background(), "key", strings.NewReader("hel"), 5, "")
+
+	assert.Error(t, err)
+	_, err = store.Get(context.Background(), "key")
+	assert.ErrorIs(t, err, domain.ErrBlobNotFound)
+}
+
+func TestLocalBlobStore_RejectsKeysOutsideRoot(t *testing.T) {
+	store, err := infrastructure.NewLocalBlobStore(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "../escape", "a/../../escape", "/etc/passwd"} {
+		assert.Error(t, store.Put(context.Background(), key, strings.NewReader(""), 0, ""), key)
+	}
+}
+
+// fakeS3 stores objects in memory and records the requests it receives
+type fakeS3 struct {
+	mu       sync.Mutex
+	objects  map[string]string
+	requests []*http.Request
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	fake := &fakeS3{objects: make(map[string]string)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		fake.requests = append(fake.requests, r)
+
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			fake.objects[r.URL.EscapedPath()] = string(body)
+		case http.MethodGet:
+			body, ok := fake.objects[r.URL.EscapedPath()]
+			if !ok {
+				http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+				return
+			}
+			_, _ = io.WriteString(w, body)
+		case http.MethodDelete:
+			delete(fake.objects, r.URL.EscapedPath())
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+var s3Authorization = regexp.MustCompile(`^AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/\d{8}/eu-west-1/s3/aws4_request, SignedHeaders=([a-z0-9;-]+), Signature=[0-9a-f]{64}$`)
+
+func TestS3BlobStore_PutGetDelete(t *testing.T) {
+	// Arrange
+	fake, server := newFakeS3(t)
+	store, err := infrastructure.NewS3BlobStore(infrastructure.S3BlobStoreConfig{
+		Endpoint:        server.URL,
+		Region:          "eu-west-1",
+		Bucket:          "fern",
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+		Prefix:          "attachments/",
+		PathStyle:       true,
+	}, server.Client())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// Act
+	require.NoError(t, store.Put(ctx, "proj 1/log.txt", strings.NewReader("hello"), 5, "text/plain"))
+	content, err := store.Get(ctx, "proj 1/log.txt")
+	require.NoError(t, err)
+	data, err := io.ReadAll(content)
+	content.Close()
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	assert.Contains(t, fake.objects, "/fern/attachments/proj%201/log.txt")
+
+	put := fake.requests[0]
+	assert.Equal(t, "UNSIGNED-PAYLOAD", put.Header.Get("X-Amz-Content-Sha256"))
+	match := s3Authorization.FindStringSubmatch(put.Header.Get("Authorization"))
+	require.NotNil(t, match, put.Header.Get("Authorization"))
+	assert.Equal(t, "content-type;host;x-amz-content-sha256;x-amz-date", match[1])
+
+	require.NoError(t, store.Delete(ctx, "proj 1/log.txt"))
+	_, err = store.Get(ctx, "proj 1/log.txt")
+	assert.ErrorIs(t, err, domain.ErrBlobNotFound)
+}
+
+func TestS3BlobStore_ReportsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "<Error><Code>AccessDenied</Code></Error>", http.StatusForbidden)
+	}))
+	defer server.Close()
+	store, err := infrastructure.NewS3BlobStore(infrastructure.S3BlobStoreConfig{
+		Endpoint: server.URL, Bucket: "fern", PathStyle: true,
+	}, server.Client())
+	require.NoError(t, err)
+
+	err = store.Put(context.Background(), "key", strings.NewReader("x"), 1, "")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "AccessDenied")
+}
+
+func TestNewS3BlobStore_RequiresBucket(t *testing.T) {
+	_, err := infrastructure.NewS3BlobStore(infrastructure.S3BlobStoreConfig{}, nil)
+
+	assert.Error(t, err)
+}
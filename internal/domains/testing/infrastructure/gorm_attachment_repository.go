@@ -0,0 +1,170 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+	"github.com/guidewire-oss/fern-platform/pkg/database"
+	"gorm.io/gorm"
+)
+
+// GormAttachmentRepository implements domain.AttachmentRepository using GORM
+type GormAttachmentRepository struct {
+	db *gorm.DB
+}
+
+// NewGormAttachmentRepository creates a new GORM-based attachment repository
+func NewGormAttachmentRepository(db *gorm.DB) *GormAttachmentRepository {
+	return &GormAttachmentRepository{db: db}
+}
+
+// Create records an attachment
+func (r *GormAttachmentRepository) Create(ctx context.Context, attachment *domain.Attachment) error {
+	dbAttachment := toDatabaseAttachment(attachment)
+	if err := r.db.WithContext(ctx).Create(dbAttachment).Error; err != nil {
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	attachment.ID = dbAttachment.ID
+	attachment.Size = dbAttachment.Size
+	attachment.SHA256 = dbAttachment.SHA256
+	attachment.StorageBackend = dbAttachment.StorageBackend
+	attachment.CreatedAt = dbAttachment.CreatedAt
+	return nil
+}
+
+// GetByID retrieves an attachment without its inline content
+func (r *GormAttachmentRepository) GetByID(ctx context.Context, id uint) (*domain.Attachment, error) {
+	var dbAttachment database.Attachment
+	err := r.db.WithContext(ctx).Omit("content").First(&dbAttachment, id).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, domain.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	return toDomainAttachment(&dbAttachment), nil
+}
+
+// Find retrieves the attachments matching the filter without their inline content
+func (r *GormAttachmentRepository) Find(ctx context.Context, filter domain.AttachmentFilter) ([]*domain.Attachment, error) {
+	query := r.db.WithContext(ctx).Omit("content").Where("test_run_id = ?", filter.TestRunID)
+	if filter.SuiteRunID != nil {
+		query = query.Where("suite_run_id = ?", *filter.SuiteRunID)
+	}
+	if filter.SpecRunID != nil {
+		query = query.Where("spec_run_id = ?", *filter.SpecRunID)
+	}
+
+	var dbAttachments []database.Attachment
+	if err := query.Order("id ASC").Find(&dbAttachments).Error; err != nil {
+		return nil, fmt.Errorf("failed to find attachments: %w", err)
+	}
+
+	attachments := make([]*domain.Attachment, len(dbAttachments))
+	for i := range dbAttachments {
+		attachments[i] = toDomainAttachment(&dbAttachments[i])
+	}
+	return attachments, nil
+}
+
+// GetContent retrieves the inline content of an attachment
+func (r *GormAttachmentRepository) GetContent(ctx context.Context, id uint) ([]byte, error) {
+	var dbAttachment database.Attachment
+	err := r.db.WithContext(ctx).Select("id", "content").First(&dbAttachment, id).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, domain.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment content: %w", err)
+	}
+
+	return dbAttachment.Content, nil
+}
+
+// TotalSizeByProject sums the size of all of a project's attachments
+func (r *GormAttachmentRepository) TotalSizeByProject(ctx context.Context, projectID string) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&database.Attachment{}).
+		Where("project_id = ?", projectID).
+		Select("COALESCE(SUM(size_bytes), 0)").
+		Scan(&total).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum attachment sizes: %w", err)
+	}
+
+	return total, nil
+}
+
+// createSpecAttachments records the attachments of spec runs that have already been assigned IDs.
+// Attachments are written one at a time since a batch of inline content could exceed the statement
+// size limit.
+func createSpecAttachments(ctx context.Context, db *gorm.DB, projectID string, testRunID uint, specRuns []*domain.SpecRun) error {
+	for _, specRun := range specRuns {
+		for i := range specRun.Attachments {
+			attachment := &specRun.Attachments[i]
+			suiteRunID, specRunID := specRun.SuiteRunID, specRun.ID
+			attachment.ProjectID = projectID
+			attachment.TestRunID = testRunID
+			attachment.SuiteRunID = &suiteRunID
+			attachment.SpecRunID = &specRunID
+
+			if err := NewGormAttachmentRepository(db).Create(ctx, attachment); err != nil {
+				return fmt.Errorf("failed to create spec attachment: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// toDatabaseAttachment converts an attachment, keeping its content inline only for the
+// database backend
+func toDatabaseAttachment(attachment *domain.Attachment) *database.Attachment {
+	dbAttachment := &database.Attachment{
+		ProjectID:      attachment.ProjectID,
+		TestRunID:      attachment.TestRunID,
+		SuiteRunID:     attachment.SuiteRunID,
+		SpecRunID:      attachment.SpecRunID,
+		Name:           attachment.Name,
+		ContentType:    attachment.ContentType,
+		Size:           attachment.Size,
+		SHA256:         attachment.SHA256,
+		StorageBackend: attachment.StorageBackend,
+		StorageKey:     attachment.StorageKey,
+	}
+	if dbAttachment.StorageBackend == "" {
+		dbAttachment.StorageBackend = domain.StorageBackendDatabase
+	}
+	if dbAttachment.StorageBackend == domain.StorageBackendDatabase {
+		dbAttachment.Content = attachment.Content
+		dbAttachment.Size = int64(len(attachment.Content))
+		if dbAttachment.SHA256 == "" {
+			sum := sha256.Sum256(attachment.Content)
+			dbAttachment.SHA256 = hex.EncodeToString(sum[:])
+		}
+	}
+	return dbAttachment
+}
+
+// toDomainAttachment converts a loaded attachment row
+func toDomainAttachment(dbAttachment *database.Attachment) *domain.Attachment {
+	return &domain.Attachment{
+		ID:             dbAttachment.ID,
+		ProjectID:      dbAttachment.ProjectID,
+		TestRunID:      dbAttachment.TestRunID,
+		SuiteRunID:     dbAttachment.SuiteRunID,
+		SpecRunID:      dbAttachment.SpecRunID,
+		Name:           dbAttachment.Name,
+		ContentType:    dbAttachment.ContentType,
+		Size:           dbAttachment.Size,
+		SHA256:         dbAttachment.SHA256,
+		StorageBackend: dbAttachment.StorageBackend,
+		StorageKey:     dbAttachment.StorageKey,
+		CreatedAt:      dbAttachment.CreatedAt,
+	}
+}
@@ -0,0 +1,121 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+	"github.com/guidewire-oss/fern-platform/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormIdempotencyKeyRepository implements domain.IdempotencyKeyRepository using GORM
+type GormIdempotencyKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewGormIdempotencyKeyRepository creates a new GORM-based idempotency key repository
+func NewGormIdempotencyKeyRepository(db *gorm.DB) *GormIdempotencyKeyRepository {
+	return &GormIdempotencyKeyRepository{db: db}
+}
+
+// Create stores a new in-progress record unless the key is already taken
+func (r *GormIdempotencyKeyRepository) Create(ctx context.Context, record *domain.IdempotencyRecord) error {
+	dbRecord := &database.IngestionIdempotencyKey{
+		ProjectID:      record.ProjectID,
+		IdempotencyKey: record.Key,
+		RequestHash:    record.RequestHash,
+		CreatedAt:      record.CreatedAt,
+		ClaimedAt:      record.ClaimedAt,
+		ExpiresAt:      record.ExpiresAt,
+	}
+
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(dbRecord)
+	if result.Error != nil {
+		return fmt.Errorf("failed to create idempotency key: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrIdempotencyKeyExists
+	}
+
+	return nil
+}
+
+// Get retrieves the record for a project's key
+func (r *GormIdempotencyKeyRepository) Get(ctx context.Context, projectID, key string) (*domain.IdempotencyRecord, error) {
+	var dbRecord database.IngestionIdempotencyKey
+	err := r.db.WithContext(ctx).Where("project_id = ? AND idempotency_key = ?", projectID, key).First(&dbRecord).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+
+	record := &domain.IdempotencyRecord{
+		ProjectID:      dbRecord.ProjectID,
+		Key:            dbRecord.IdempotencyKey,
+		RequestHash:    dbRecord.RequestHash,
+		ResponseStatus: dbRecord.ResponseStatus,
+		ResponseBody:   dbRecord.ResponseBody,
+		CreatedAt:      dbRecord.CreatedAt,
+		ClaimedAt:      dbRecord.ClaimedAt,
+		ExpiresAt:      dbRecord.ExpiresAt,
+	}
+	if dbRecord.TestRunID != nil {
+		record.TestRunID = *dbRecord.TestRunID
+	}
+
+	return record, nil
+}
+
+// Reclaim claims an abandoned record again, unless it was completed or reclaimed since it was read
+func (r *GormIdempotencyKeyRepository) Reclaim(ctx context.Context, record *domain.IdempotencyRecord, claimedAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&database.IngestionIdempotencyKey{}).
+		Where("project_id = ? AND idempotency_key = ? AND response_status = 0 AND claimed_at = ?", record.ProjectID, record.Key, record.ClaimedAt).
+		Update("claimed_at", claimedAt)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to reclaim idempotency key: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Complete stores the response of the original request
+func (r *GormIdempotencyKeyRepository) Complete(ctx context.Context, record *domain.IdempotencyRecord) error {
+	updates := map[string]interface{}{
+		"response_status": record.ResponseStatus,
+		"response_body":   record.ResponseBody,
+	}
+	if record.TestRunID != 0 {
+		updates["test_run_id"] = record.TestRunID
+	}
+
+	result := r.db.WithContext(ctx).Model(&database.IngestionIdempotencyKey{}).
+		Where("project_id = ? AND idempotency_key = ?", record.ProjectID, record.Key).
+		Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", result.Error)
+	}
+
+	return nil
+}
+
+// Delete removes the record for a project's key
+func (r *GormIdempotencyKeyRepository) Delete(ctx context.Context, projectID, key string) error {
+	if err := r.db.WithContext(ctx).Where("project_id = ? AND idempotency_key = ?", projectID, key).
+		Delete(&database.IngestionIdempotencyKey{}).Error; err != nil {
+		return fmt.Errorf("failed to delete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired removes all records that expired before the given time
+func (r *GormIdempotencyKeyRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at <= ?", before).Delete(&database.IngestionIdempotencyKey{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
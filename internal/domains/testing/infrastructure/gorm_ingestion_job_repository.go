@@ -0,0 +1,251 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+	"github.com/guidewire-oss/fern-platform/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ingestionQueueLockKey is the transaction-level advisory lock that serialises enqueues,
+// so that concurrent submissions cannot push the queue past its capacity
+const ingestionQueueLockKey int64 = 0x6665726e_71756575
+
+// GormIngestionJobRepository implements domain.IngestionJobRepository using GORM
+type GormIngestionJobRepository struct {
+	db *gorm.DB
+}
+
+// NewGormIngestionJobRepository creates a new GORM-based ingestion job repository
+func NewGormIngestionJobRepository(db *gorm.DB) *GormIngestionJobRepository {
+	return &GormIngestionJobRepository{db: db}
+}
+
+// Enqueue stores a new queued job unless the queue already holds capacity unfinished jobs
+func (r *GormIngestionJobRepository) Enqueue(ctx context.Context, job *domain.IngestionJob, capacity int) error {
+	dbJob := &database.IngestionJob{
+		ID:            job.ID,
+		ProjectID:     job.ProjectID,
+		Format:        job.Format,
+		Payload:       job.Payload,
+		RunID:         job.RunID,
+		Branch:        job.Branch,
+		GitCommit:     job.GitCommit,
+		Environment:   job.Environment,
+		Status:        string(job.Status),
+		MaxAttempts:   job.MaxAttempts,
+		NextAttemptAt: job.NextAttemptAt,
+		CreatedAt:     job.CreatedAt,
+		UpdatedAt:     job.CreatedAt,
+	}
+	if job.Shard != nil {
+		dbJob.GroupID = job.Shard.GroupID
+		dbJob.ShardIndex = &job.Shard.Index
+		dbJob.ShardTotal = &job.Shard.Total
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", ingestionQueueLockKey).Error; err != nil {
+			return fmt.Errorf("failed to lock ingestion queue: %w", err)
+		}
+
+		var unfinished int64
+		err := tx.Model(&database.IngestionJob{}).
+			Where("status IN ?", []string{string(domain.IngestionJobQueued), string(domain.IngestionJobProcessing)}).
+			Count(&unfinished).Error
+		if err != nil {
+			return fmt.Errorf("failed to count ingestion jobs: %w", err)
+		}
+		if unfinished >= int64(capacity) {
+			return domain.ErrIngestionQueueFull
+		}
+
+		if err := tx.Create(dbJob).Error; err != nil {
+			return fmt.Errorf("failed to enqueue ingestion job: %w", err)
+		}
+		return nil
+	})
+}
+
+// Get retrieves a job by ID, leaving out the payload that only workers need
+func (r *GormIngestionJobRepository) Get(ctx context.Context, id string) (*domain.IngestionJob, error) {
+	var dbJob database.IngestionJob
+	err := r.db.WithContext(ctx).Omit("payload").Where("id = ?", id).First(&dbJob).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ingestion job: %w", err)
+	}
+
+	return r.toDomainModel(&dbJob)
+}
+
+// ClaimNext locks the oldest due job, skipping jobs locked by other workers, and leases it
+func (r *GormIngestionJobRepository) ClaimNext(ctx context.Context, now, leaseExpiresAt time.Time) (*domain.IngestionJob, error) {
+	var claimed *domain.IngestionJob
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var dbJob database.IngestionJob
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("(status = ? AND next_attempt_at <= ?) OR (status = ? AND lease_expires_at <= ?)",
+				domain.IngestionJobQueued, now, domain.IngestionJobProcessing, now).
+			Order("next_attempt_at").
+			Limit(1).
+			Find(&dbJob).Error
+		if err != nil {
+			return err
+		}
+		if dbJob.ID == "" {
+			return nil
+		}
+
+		dbJob.Status = string(domain.IngestionJobProcessing)
+		dbJob.Attempts++
+		dbJob.LeaseExpiresAt = &leaseExpiresAt
+		if dbJob.StartedAt == nil {
+			dbJob.StartedAt = &now
+		}
+		err = tx.Model(&database.IngestionJob{}).Where("id = ?", dbJob.ID).Updates(map[string]interface{}{
+			"status":           dbJob.Status,
+			"attempts":         dbJob.Attempts,
+			"lease_expires_at": dbJob.LeaseExpiresAt,
+			"started_at":       dbJob.StartedAt,
+			"updated_at":       now,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		claimed, err = r.toDomainModel(&dbJob)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim ingestion job: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// Succeed marks a claimed job as succeeded and drops its payload, which is recorded as the test run
+func (r *GormIngestionJobRepository) Succeed(ctx context.Context, id string, testRunID uint, completedAt time.Time) error {
+	err := r.db.WithContext(ctx).Model(&database.IngestionJob{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":           string(domain.IngestionJobSucceeded),
+		"payload":          nil,
+		"test_run_id":      testRunID,
+		"lease_expires_at": nil,
+		"completed_at":     completedAt,
+		"updated_at":       completedAt,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to complete ingestion job: %w", err)
+	}
+	return nil
+}
+
+// Fail appends the attempt's error and either schedules a retry or dead-letters the job
+func (r *GormIngestionJobRepository) Fail(ctx context.Context, id string, jobErr domain.IngestionJobError, retryAt *time.Time) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var dbJob database.IngestionJob
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&dbJob).Error; err != nil {
+			return err
+		}
+
+		var jobErrors []domain.IngestionJobError
+		if len(dbJob.Errors) > 0 {
+			if err := json.Unmarshal(dbJob.Errors, &jobErrors); err != nil {
+				return err
+			}
+		}
+		encoded, err := json.Marshal(append(jobErrors, jobErr))
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"errors":           json.RawMessage(encoded),
+			"lease_expires_at": nil,
+			"updated_at":       jobErr.OccurredAt,
+		}
+		if retryAt != nil {
+			updates["status"] = string(domain.IngestionJobQueued)
+			updates["next_attempt_at"] = *retryAt
+		} else {
+			updates["status"] = string(domain.IngestionJobDeadLettered)
+			updates["completed_at"] = jobErr.OccurredAt
+		}
+
+		return tx.Model(&database.IngestionJob{}).Where("id = ?", id).Updates(updates).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record ingestion job failure: %w", err)
+	}
+	return nil
+}
+
+// Release returns a claimed job to the queue without counting the attempt
+func (r *GormIngestionJobRepository) Release(ctx context.Context, id string) error {
+	err := r.db.WithContext(ctx).Model(&database.IngestionJob{}).
+		Where("id = ? AND status = ?", id, domain.IngestionJobProcessing).
+		Updates(map[string]interface{}{
+			"status":           string(domain.IngestionJobQueued),
+			"attempts":         gorm.Expr("attempts - 1"),
+			"lease_expires_at": nil,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to release ingestion job: %w", err)
+	}
+	return nil
+}
+
+// DeleteFinished removes succeeded and dead-lettered jobs completed before the given time
+func (r *GormIngestionJobRepository) DeleteFinished(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("status IN ? AND completed_at < ?", []string{string(domain.IngestionJobSucceeded), string(domain.IngestionJobDeadLettered)}, before).
+		Delete(&database.IngestionJob{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete finished ingestion jobs: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// toDomainModel converts a database ingestion job to the domain model
+func (r *GormIngestionJobRepository) toDomainModel(dbJob *database.IngestionJob) (*domain.IngestionJob, error) {
+	job := &domain.IngestionJob{
+		ID:             dbJob.ID,
+		ProjectID:      dbJob.ProjectID,
+		Format:         dbJob.Format,
+		Payload:        dbJob.Payload,
+		RunID:          dbJob.RunID,
+		Branch:         dbJob.Branch,
+		GitCommit:      dbJob.GitCommit,
+		Environment:    dbJob.Environment,
+		Status:         domain.IngestionJobStatus(dbJob.Status),
+		Attempts:       dbJob.Attempts,
+		MaxAttempts:    dbJob.MaxAttempts,
+		NextAttemptAt:  dbJob.NextAttemptAt,
+		LeaseExpiresAt: dbJob.LeaseExpiresAt,
+		StartedAt:      dbJob.StartedAt,
+		CompletedAt:    dbJob.CompletedAt,
+		CreatedAt:      dbJob.CreatedAt,
+		UpdatedAt:      dbJob.UpdatedAt,
+	}
+	if dbJob.TestRunID != nil {
+		job.TestRunID = *dbJob.TestRunID
+	}
+	if dbJob.ShardIndex != nil && dbJob.ShardTotal != nil {
+		job.Shard = &domain.ShardInfo{GroupID: dbJob.GroupID, Index: *dbJob.ShardIndex, Total: *dbJob.ShardTotal}
+	}
+	if len(dbJob.Errors) > 0 {
+		if err := json.Unmarshal(dbJob.Errors, &job.Errors); err != nil {
+			return nil, fmt.Errorf("failed to decode ingestion job errors: %w", err)
+		}
+	}
+
+	return job, nil
+}
@@ -0,0 +1,174 @@
+package infrastructure_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/infrastructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGormIngestionJobRepository_ClaimNext_SkipsLockedJobs(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormIngestionJobRepository(gormDB)
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	lease := now.Add(time.Minute)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "ingestion_jobs" WHERE .* ORDER BY next_attempt_at LIMIT .* FOR UPDATE SKIP LOCKED`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "project_id", "format", "status", "attempts", "max_attempts"}).
+			AddRow("6f1c1f36-0b0a-4bd3-9d59-0a4f2f1b8c11", "proj-1", "junit", "queued", 1, 5))
+	mock.ExpectExec(`UPDATE "ingestion_jobs" SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	job, err := repo.ClaimNext(context.Background(), now, lease)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, domain.IngestionJobProcessing, job.Status)
+	assert.Equal(t, 2, job.Attempts)
+	assert.Equal(t, lease, *job.LeaseExpiresAt)
+	assert.Equal(t, now, *job.StartedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormIngestionJobRepository_ClaimNext_NoJobDue(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormIngestionJobRepository(gormDB)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "ingestion_jobs"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	// Act
+	job, err := repo.ClaimNext(context.Background(), time.Now(), time.Now().Add(time.Minute))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Nil(t, job)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormIngestionJobRepository_Enqueue_QueueFull(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormIngestionJobRepository(gormDB)
+	job := &domain.IngestionJob{ID: "6f1c1f36-0b0a-4bd3-9d59-0a4f2f1b8c11", ProjectID: "proj-1", Format: "junit"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock\(.*\)`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "ingestion_jobs" WHERE status IN`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectRollback()
+
+	// Act
+	err := repo.Enqueue(context.Background(), job, 2)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrIngestionQueueFull)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormIngestionJobRepository_Enqueue_InsertsUnderLock(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormIngestionJobRepository(gormDB)
+	job := &domain.IngestionJob{ID: "6f1c1f36-0b0a-4bd3-9d59-0a4f2f1b8c11", ProjectID: "proj-1", Format: "junit"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock\(.*\)`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "ingestion_jobs" WHERE status IN`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectExec(`INSERT INTO "ingestion_jobs"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	err := repo.Enqueue(context.Background(), job, 2)
+
+	// Assert
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormIngestionJobRepository_Get_OmitsPayload(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormIngestionJobRepository(gormDB)
+
+	mock.ExpectQuery(`SELECT "ingestion_jobs"."id","ingestion_jobs"."project_id","ingestion_jobs"."format","ingestion_jobs"."run_id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "project_id", "format", "status"}).
+			AddRow("6f1c1f36-0b0a-4bd3-9d59-0a4f2f1b8c11", "proj-1", "junit", "succeeded"))
+
+	// Act
+	job, err := repo.Get(context.Background(), "6f1c1f36-0b0a-4bd3-9d59-0a4f2f1b8c11")
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, domain.IngestionJobSucceeded, job.Status)
+	assert.Nil(t, job.Payload)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormIngestionJobRepository_Succeed_DropsPayload(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormIngestionJobRepository(gormDB)
+	completedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "ingestion_jobs" SET .*"payload"=\$`).
+		WithArgs(completedAt, nil, nil, "succeeded", uint(42), completedAt, "6f1c1f36-0b0a-4bd3-9d59-0a4f2f1b8c11").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	err := repo.Succeed(context.Background(), "6f1c1f36-0b0a-4bd3-9d59-0a4f2f1b8c11", 42, completedAt)
+
+	// Assert
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormIngestionJobRepository_DeleteFinished(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormIngestionJobRepository(gormDB)
+	before := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "ingestion_jobs" WHERE status IN \(\$1,\$2\) AND completed_at < \$3`).
+		WithArgs("succeeded", "dead_lettered", before).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	// Act
+	deleted, err := repo.DeleteFinished(context.Background(), before)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
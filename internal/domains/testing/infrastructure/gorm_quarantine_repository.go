@@ -0,0 +1,185 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+	"github.com/guidewire-oss/fern-platform/pkg/database"
+	"gorm.io/gorm"
+)
+
+// GormQuarantineRepository implements domain.QuarantineRepository using GORM
+type GormQuarantineRepository struct {
+	db *gorm.DB
+}
+
+// NewGormQuarantineRepository creates a new GORM-based quarantine repository
+func NewGormQuarantineRepository(db *gorm.DB) *GormQuarantineRepository {
+	return &GormQuarantineRepository{db: db}
+}
+
+// Create records a quarantine
+func (r *GormQuarantineRepository) Create(ctx context.Context, quarantine *domain.Quarantine) error {
+	dbQuarantine := &database.TestQuarantine{
+		ProjectID:  quarantine.ProjectID,
+		TestCaseID: quarantine.TestCaseID,
+		Reason:     quarantine.Reason,
+		Owner:      quarantine.Owner,
+		JiraIssue:  quarantine.JiraIssue,
+		CreatedBy:  quarantine.CreatedBy,
+		ExpiresAt:  quarantine.ExpiresAt,
+	}
+	if err := r.db.WithContext(ctx).Create(dbQuarantine).Error; err != nil {
+		return fmt.Errorf("failed to create quarantine: %w", err)
+	}
+
+	quarantine.ID = dbQuarantine.ID
+	quarantine.CreatedAt = dbQuarantine.CreatedAt
+	return nil
+}
+
+// GetByID retrieves a quarantine with its test case
+func (r *GormQuarantineRepository) GetByID(ctx context.Context, id uint) (*domain.Quarantine, error) {
+	var dbQuarantine database.TestQuarantine
+	err := r.db.WithContext(ctx).Preload("TestCase").First(&dbQuarantine, id).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, domain.ErrQuarantineNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quarantine: %w", err)
+	}
+
+	return toDomainQuarantine(&dbQuarantine), nil
+}
+
+// Find retrieves the quarantines matching the filter with their test cases, newest first
+func (r *GormQuarantineRepository) Find(ctx context.Context, filter domain.QuarantineFilter) ([]*domain.Quarantine, error) {
+	query := r.db.WithContext(ctx).Preload("TestCase").Where("project_id = ?", filter.ProjectID)
+	if filter.TestCaseID != 0 {
+		query = query.Where("test_case_id = ?", filter.TestCaseID)
+	}
+	if !filter.ActiveAt.IsZero() {
+		query = query.Where("lifted_at IS NULL AND expires_at > ?", filter.ActiveAt)
+	}
+
+	var dbQuarantines []database.TestQuarantine
+	if err := query.Order("created_at DESC, id DESC").Find(&dbQuarantines).Error; err != nil {
+		return nil, fmt.Errorf("failed to find quarantines: %w", err)
+	}
+
+	quarantines := make([]*domain.Quarantine, len(dbQuarantines))
+	for i := range dbQuarantines {
+		quarantines[i] = toDomainQuarantine(&dbQuarantines[i])
+	}
+	return quarantines, nil
+}
+
+// Lift ends a quarantine that has not been lifted yet
+func (r *GormQuarantineRepository) Lift(ctx context.Context, id uint, liftedBy string, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&database.TestQuarantine{}).
+		Where("id = ? AND lifted_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"lifted_at":  at,
+			"lifted_by":  liftedBy,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to lift quarantine: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrQuarantineNotFound
+	}
+	return nil
+}
+
+func toDomainQuarantine(dbQuarantine *database.TestQuarantine) *domain.Quarantine {
+	quarantine := &domain.Quarantine{
+		ID:         dbQuarantine.ID,
+		ProjectID:  dbQuarantine.ProjectID,
+		TestCaseID: dbQuarantine.TestCaseID,
+		Reason:     dbQuarantine.Reason,
+		Owner:      dbQuarantine.Owner,
+		JiraIssue:  dbQuarantine.JiraIssue,
+		CreatedBy:  dbQuarantine.CreatedBy,
+		ExpiresAt:  dbQuarantine.ExpiresAt,
+		LiftedAt:   dbQuarantine.LiftedAt,
+		LiftedBy:   dbQuarantine.LiftedBy,
+		CreatedAt:  dbQuarantine.CreatedAt,
+	}
+	if dbQuarantine.TestCase != nil {
+		quarantine.TestCase = toDomainTestCase(dbQuarantine.TestCase)
+	}
+	return quarantine
+}
+
+// markQuarantinedSpecRuns flags the failed spec runs whose test, or the test they are a
+// parameter set of, is quarantined right now. Spec runs must be linked to their test cases.
+func markQuarantinedSpecRuns(ctx context.Context, db *gorm.DB, specRuns []*domain.SpecRun) error {
+	var testCaseIDs []uint
+	for _, spec := range specRuns {
+		if spec.Status == "failed" && spec.TestCaseID != nil {
+			testCaseIDs = append(testCaseIDs, *spec.TestCaseID)
+		}
+	}
+	if len(testCaseIDs) == 0 {
+		return nil
+	}
+
+	var quarantinedIDs []uint
+	err := db.WithContext(ctx).Table("test_cases AS tc").
+		Distinct("tc.id").
+		Joins("JOIN test_quarantines q ON q.test_case_id = tc.id OR q.test_case_id = tc.parent_id").
+		Where("tc.id IN ? AND q.lifted_at IS NULL AND q.expires_at > ?", testCaseIDs, time.Now()).
+		Pluck("tc.id", &quarantinedIDs).Error
+	if err != nil {
+		return fmt.Errorf("failed to look up quarantined tests: %w", err)
+	}
+
+	quarantined := make(map[uint]bool, len(quarantinedIDs))
+	for _, id := range quarantinedIDs {
+		quarantined[id] = true
+	}
+	for _, spec := range specRuns {
+		spec.Quarantined = spec.Status == "failed" && spec.TestCaseID != nil && quarantined[*spec.TestCaseID]
+	}
+	return nil
+}
+
+// countQuarantinedSpecRuns adds stored spec runs that were quarantined to the counters of their
+// suite and test runs
+func countQuarantinedSpecRuns(ctx context.Context, db *gorm.DB, specRuns []*domain.SpecRun) error {
+	counts := make(map[uint]int)
+	for _, spec := range specRuns {
+		if spec.Quarantined {
+			counts[spec.SuiteRunID]++
+		}
+	}
+
+	// Update suites in a consistent order so concurrent ingestions can't deadlock
+	suiteIDs := make([]uint, 0, len(counts))
+	for suiteID := range counts {
+		suiteIDs = append(suiteIDs, suiteID)
+	}
+	sort.Slice(suiteIDs, func(i, j int) bool { return suiteIDs[i] < suiteIDs[j] })
+
+	db = db.WithContext(ctx)
+	for _, suiteID := range suiteIDs {
+		count := counts[suiteID]
+		err := db.Model(&database.SuiteRun{}).Where("id = ?", suiteID).
+			UpdateColumn("quarantined_specs", gorm.Expr("quarantined_specs + ?", count)).Error
+		if err != nil {
+			return fmt.Errorf("failed to count quarantined specs: %w", err)
+		}
+
+		testRunID := db.Model(&database.SuiteRun{}).Select("test_run_id").Where("id = ?", suiteID)
+		err = db.Model(&database.TestRun{}).Where("id = (?)", testRunID).
+			UpdateColumn("quarantined_tests", gorm.Expr("quarantined_tests + ?", count)).Error
+		if err != nil {
+			return fmt.Errorf("failed to count quarantined tests: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+package infrastructure
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+	"github.com/guidewire-oss/fern-platform/pkg/database"
+	"gorm.io/gorm"
+)
+
+const (
+	// specOutputBatchSize is the number of spec outputs written per INSERT statement
+	specOutputBatchSize = 100
+
+	// maxIndexedOutputSize is how much of a spec's output is indexed for search; Postgres
+	// rejects tsvectors over 1MB
+	maxIndexedOutputSize = 256 << 10
+)
+
+// GormSpecOutputRepository implements domain.SpecOutputRepository using GORM
+type GormSpecOutputRepository struct {
+	db *gorm.DB
+}
+
+// NewGormSpecOutputRepository creates a new GORM-based spec output repository
+func NewGormSpecOutputRepository(db *gorm.DB) *GormSpecOutputRepository {
+	return &GormSpecOutputRepository{db: db}
+}
+
+// specOutputRow is a spec output with the project of its test run
+type specOutputRow struct {
+	database.SpecOutput
+	ProjectID string
+}
+
+// GetBySpecRunID retrieves and decompresses the output captured for a spec run, with the
+// project of its test run
+func (r *GormSpecOutputRepository) GetBySpecRunID(ctx context.Context, specRunID uint) (*domain.SpecOutput, error) {
+	var row specOutputRow
+	result := r.db.WithContext(ctx).Table("spec_outputs AS o").
+		Select("o.*, tr.project_id").
+		Joins("JOIN spec_runs sp ON sp.id = o.spec_run_id AND sp.deleted_at IS NULL").
+		Joins("JOIN suite_runs su ON su.id = sp.suite_run_id AND su.deleted_at IS NULL").
+		Joins("JOIN test_runs tr ON tr.id = su.test_run_id AND tr.deleted_at IS NULL").
+		Where("o.spec_run_id = ?", specRunID).
+		Limit(1).
+		Scan(&row)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get spec output: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, domain.ErrSpecOutputNotFound
+	}
+
+	output, err := toDomainSpecOutput(&row.SpecOutput)
+	if err != nil {
+		return nil, err
+	}
+	output.ProjectID = row.ProjectID
+	return output, nil
+}
+
+// specOutputMatchRow is a row of a spec output search
+type specOutputMatchRow struct {
+	database.SpecOutput
+	SpecName     string
+	Status       string
+	SuiteRunID   uint
+	SpecStart    time.Time
+	SpecEnd      *time.Time
+	DurationMs   int64
+	ErrorMessage string
+	StackTrace   string
+	RetryCount   int
+	IsFlaky      bool
+	SuiteName    string
+	TestRunID    uint
+	RunID        string
+	Branch       string
+	StartTime    time.Time
+}
+
+// Search finds the most recent spec runs of a project whose output matches the query
+func (r *GormSpecOutputRepository) Search(ctx context.Context, search domain.SpecOutputSearch) ([]*domain.SpecOutputMatch, error) {
+	query := r.db.WithContext(ctx).Table("spec_outputs AS o").
+		Select(`o.spec_run_id, o.stdout, o.stderr, o.stdout_bytes, o.stderr_bytes, o.truncated, o.created_at,
+			sp.spec_name, sp.status, sp.suite_run_id, sp.start_time AS spec_start, sp.end_time AS spec_end,
+			sp.duration_ms, sp.error_message, sp.stack_trace, sp.retry_count, sp.is_flaky,
+			su.suite_name, su.test_run_id, tr.run_id, tr.branch, tr.start_time`).
+		Joins("JOIN spec_runs sp ON sp.id = o.spec_run_id AND sp.deleted_at IS NULL").
+		Joins("JOIN suite_runs su ON su.id = sp.suite_run_id AND su.deleted_at IS NULL").
+		Joins("JOIN test_runs tr ON tr.id = su.test_run_id AND tr.deleted_at IS NULL").
+		Where("tr.project_id = ?", search.ProjectID).
+		Where("o.search_vector @@ websearch_to_tsquery('simple', ?)", search.Query)
+	if !search.Since.IsZero() {
+		query = query.Where("o.created_at >= ?", search.Since)
+	}
+	if search.Status != "" {
+		query = query.Where("sp.status = ?", search.Status)
+	}
+	if search.Limit > 0 {
+		query = query.Limit(search.Limit)
+	}
+
+	var rows []specOutputMatchRow
+	if err := query.Order("o.created_at DESC, o.spec_run_id DESC").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to search spec output: %w", err)
+	}
+
+	matches := make([]*domain.SpecOutputMatch, len(rows))
+	for i := range rows {
+		row := &rows[i]
+		output, err := toDomainSpecOutput(&row.SpecOutput)
+		if err != nil {
+			return nil, err
+		}
+		matches[i] = &domain.SpecOutputMatch{
+			SpecRun: &domain.SpecRun{
+				ID:             row.SpecRunID,
+				SuiteRunID:     row.SuiteRunID,
+				Name:           row.SpecName,
+				Status:         row.Status,
+				StartTime:      row.SpecStart,
+				EndTime:        row.SpecEnd,
+				Duration:       time.Duration(row.DurationMs) * time.Millisecond,
+				ErrorMessage:   row.ErrorMessage,
+				FailureMessage: row.ErrorMessage,
+				StackTrace:     row.StackTrace,
+				RetryCount:     row.RetryCount,
+				IsFlaky:        row.IsFlaky,
+			},
+			SuiteName: row.SuiteName,
+			TestRunID: row.TestRunID,
+			RunID:     row.RunID,
+			Branch:    row.Branch,
+			StartTime: row.StartTime,
+			Output:    output,
+		}
+	}
+	return matches, nil
+}
+
+// createSpecOutputs compresses and writes the output of spec runs that have already been
+// assigned IDs, indexing the start of it for search
+func createSpecOutputs(ctx context.Context, db *gorm.DB, specRuns []*domain.SpecRun) error {
+	var rows []map[string]interface{}
+	for _, specRun := range specRuns {
+		if specRun.Output == nil {
+			continue
+		}
+		specRun.Output.SpecRunID = specRun.ID
+
+		stdout, err := compressOutput(specRun.Output.Stdout)
+		if err != nil {
+			return err
+		}
+		stderr, err := compressOutput(specRun.Output.Stderr)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, map[string]interface{}{
+			"spec_run_id":   specRun.ID,
+			"stdout":        stdout,
+			"stderr":        stderr,
+			"stdout_bytes":  specRun.Output.StdoutSize,
+			"stderr_bytes":  specRun.Output.StderrSize,
+			"truncated":     specRun.Output.Truncated,
+			"search_vector": gorm.Expr("to_tsvector('simple', ?)", indexedOutput(specRun.Output)),
+			"created_at":    time.Now(),
+		})
+	}
+
+	for start := 0; start < len(rows); start += specOutputBatchSize {
+		end := min(start+specOutputBatchSize, len(rows))
+		if err := db.WithContext(ctx).Model(&database.SpecOutput{}).Create(rows[start:end]).Error; err != nil {
+			return fmt.Errorf("failed to create spec output: %w", err)
+		}
+	}
+	return nil
+}
+
+// indexedOutput returns the text of an output that is indexed for search. Postgres text
+// cannot hold NUL bytes or invalid UTF-8.
+func indexedOutput(output *domain.SpecOutput) string {
+	text := output.Stdout
+	if output.Stderr != "" {
+		text += "\n" + output.Stderr
+	}
+	if len(text) > maxIndexedOutputSize {
+		text = text[:maxIndexedOutputSize]
+	}
+	return strings.ReplaceAll(strings.ToValidUTF8(text, ""), "\x00", "")
+}
+
+// compressOutput gzips a stream of output, returning nil for an empty stream
+func compressOutput(s string) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := io.WriteString(w, s); err != nil {
+		return nil, fmt.Errorf("failed to compress spec output: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress spec output: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decompressOutput reverses compressOutput
+func decompressOutput(data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", nil
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress spec output: %w", err)
+	}
+	defer r.Close()
+	text, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress spec output: %w", err)
+	}
+	return string(text), nil
+}
+
+// toDomainSpecOutput decompresses a loaded spec output row
+func toDomainSpecOutput(dbOutput *database.SpecOutput) (*domain.SpecOutput, error) {
+	stdout, err := decompressOutput(dbOutput.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := decompressOutput(dbOutput.Stderr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.SpecOutput{
+		SpecRunID:  dbOutput.SpecRunID,
+		Stdout:     stdout,
+		Stderr:     stderr,
+		StdoutSize: dbOutput.StdoutBytes,
+		StderrSize: dbOutput.StderrBytes,
+		Truncated:  dbOutput.Truncated,
+		CreatedAt:  dbOutput.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,99 @@
+package infrastructure_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/infrastructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gzipString(t *testing.T, s string) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(s))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestGormSpecOutputRepository_GetBySpecRunID_Decompresses(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormSpecOutputRepository(gormDB)
+	mock.ExpectQuery(`SELECT o\.\*, tr\.project_id FROM spec_outputs AS o JOIN spec_runs sp .* JOIN test_runs tr .* WHERE o\.spec_run_id = \$1 LIMIT \$2`).
+		WithArgs(7, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"spec_run_id", "stdout", "stderr", "stdout_bytes", "stderr_bytes", "truncated", "project_id"}).
+			AddRow(7, gzipString(t, "added 1 item"), nil, 12, 0, false, "proj-a"))
+
+	// Act
+	output, err := repo.GetBySpecRunID(context.Background(), 7)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), output.SpecRunID)
+	assert.Equal(t, "proj-a", output.ProjectID)
+	assert.Equal(t, "added 1 item", output.Stdout)
+	assert.Empty(t, output.Stderr)
+	assert.Equal(t, int64(12), output.StdoutSize)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormSpecOutputRepository_GetBySpecRunID_NotFound(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormSpecOutputRepository(gormDB)
+	mock.ExpectQuery(`FROM spec_outputs AS o`).WillReturnRows(sqlmock.NewRows([]string{"spec_run_id"}))
+
+	// Act
+	_, err := repo.GetBySpecRunID(context.Background(), 7)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrSpecOutputNotFound)
+}
+
+func TestGormSpecOutputRepository_Search(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormSpecOutputRepository(gormDB)
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	runStart := since.Add(time.Hour)
+
+	mock.ExpectQuery(`SELECT .* FROM spec_outputs AS o JOIN spec_runs sp .* WHERE tr.project_id = \$1 AND o.search_vector @@ websearch_to_tsquery\('simple', \$2\) AND o.created_at >= \$3 AND sp.status = \$4 ORDER BY o.created_at DESC, o.spec_run_id DESC LIMIT \$5`).
+		WithArgs("proj-1", "timed out", since, "failed", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"spec_run_id", "stdout", "spec_name", "status", "suite_run_id", "duration_ms", "suite_name", "test_run_id", "run_id", "branch", "start_time"}).
+			AddRow(3, gzipString(t, "request timed out"), "charges", "failed", 2, 1500, "payments", 1, "run-1", "main", runStart))
+
+	// Act
+	matches, err := repo.Search(context.Background(), domain.SpecOutputSearch{
+		ProjectID: "proj-1",
+		Query:     "timed out",
+		Since:     since,
+		Status:    "failed",
+		Limit:     10,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, uint(3), matches[0].SpecRun.ID)
+	assert.Equal(t, "charges", matches[0].SpecRun.Name)
+	assert.Equal(t, 1500*time.Millisecond, matches[0].SpecRun.Duration)
+	assert.Equal(t, "payments", matches[0].SuiteName)
+	assert.Equal(t, "run-1", matches[0].RunID)
+	assert.Equal(t, runStart, matches[0].StartTime)
+	assert.Equal(t, "request timed out", matches[0].Output.Stdout)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -10,6 +10,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// specRunBatchSize is the number of spec runs written per INSERT statement
+const specRunBatchSize = 500
+
 // GormSpecRunRepository implements domain.SpecRunRepository using GORM
 type GormSpecRunRepository struct {
 	db *gorm.DB
@@ -22,8 +25,16 @@ func NewGormSpecRunRepository(db *gorm.DB) *GormSpecRunRepository {
 
 // Create creates a new spec run
 func (r *GormSpecRunRepository) Create(ctx context.Context, specRun *domain.SpecRun) error {
+	if err := linkSpecRunTestCases(ctx, r.db, []*domain.SpecRun{specRun}); err != nil {
+		return err
+	}
+	if err := markQuarantinedSpecRuns(ctx, r.db, []*domain.SpecRun{specRun}); err != nil {
+		return err
+	}
+
 	dbSpecRun := &database.SpecRun{
 		SuiteRunID:   specRun.SuiteRunID,
+		TestCaseID:   specRun.TestCaseID,
 		SpecName:     specRun.Name,
 		Status:       specRun.Status,
 		StartTime:    specRun.StartTime,
@@ -33,6 +44,7 @@ func (r *GormSpecRunRepository) Create(ctx context.Context, specRun *domain.Spec
 		StackTrace:   specRun.StackTrace,
 		RetryCount:   specRun.RetryCount,
 		IsFlaky:      specRun.IsFlaky,
+		Quarantined:  specRun.Quarantined,
 	}
 
 	if err := r.db.WithContext(ctx).Create(dbSpecRun).Error; err != nil {
@@ -40,7 +52,16 @@ func (r *GormSpecRunRepository) Create(ctx context.Context, specRun *domain.Spec
 	}
 
 	specRun.ID = dbSpecRun.ID
-	return nil
+	if err := countQuarantinedSpecRuns(ctx, r.db, []*domain.SpecRun{specRun}); err != nil {
+		return err
+	}
+	if err := createSpecSteps(ctx, r.db, []*domain.SpecRun{specRun}); err != nil {
+		return err
+	}
+	if err := createSpecAttempts(ctx, r.db, []*domain.SpecRun{specRun}); err != nil {
+		return err
+	}
+	return createSpecOutputs(ctx, r.db, []*domain.SpecRun{specRun})
 }
 
 // CreateBatch creates multiple spec runs in a batch
@@ -49,10 +70,18 @@ func (r *GormSpecRunRepository) CreateBatch(ctx context.Context, specRuns []*dom
 		return nil
 	}
 
+	if err := linkSpecRunTestCases(ctx, r.db, specRuns); err != nil {
+		return err
+	}
+	if err := markQuarantinedSpecRuns(ctx, r.db, specRuns); err != nil {
+		return err
+	}
+
 	dbSpecRuns := make([]*database.SpecRun, len(specRuns))
 	for i, specRun := range specRuns {
 		dbSpecRuns[i] = &database.SpecRun{
 			SuiteRunID:   specRun.SuiteRunID,
+			TestCaseID:   specRun.TestCaseID,
 			SpecName:     specRun.Name,
 			Status:       specRun.Status,
 			StartTime:    specRun.StartTime,
@@ -62,10 +91,11 @@ func (r *GormSpecRunRepository) CreateBatch(ctx context.Context, specRuns []*dom
 			StackTrace:   specRun.StackTrace,
 			RetryCount:   specRun.RetryCount,
 			IsFlaky:      specRun.IsFlaky,
+			Quarantined:  specRun.Quarantined,
 		}
 	}
 
-	if err := r.db.WithContext(ctx).CreateInBatches(dbSpecRuns, 100).Error; err != nil {
+	if err := r.db.WithContext(ctx).CreateInBatches(dbSpecRuns, specRunBatchSize).Error; err != nil {
 		return fmt.Errorf("failed to create spec runs in batch: %w", err)
 	}
 
@@ -73,6 +103,87 @@ func (r *GormSpecRunRepository) CreateBatch(ctx context.Context, specRuns []*dom
 	for i, dbSpecRun := range dbSpecRuns {
 		specRuns[i].ID = dbSpecRun.ID
 	}
+	if err := countQuarantinedSpecRuns(ctx, r.db, specRuns); err != nil {
+		return err
+	}
+
+	if err := createSpecSteps(ctx, r.db, specRuns); err != nil {
+		return err
+	}
+	if err := createSpecAttempts(ctx, r.db, specRuns); err != nil {
+		return err
+	}
+	return createSpecOutputs(ctx, r.db, specRuns)
+}
+
+// createSpecSteps writes the steps of spec runs that have already been assigned IDs
+func createSpecSteps(ctx context.Context, db *gorm.DB, specRuns []*domain.SpecRun) error {
+	var dbSteps []*database.SpecStep
+	var steps []*domain.SpecStep
+	for _, specRun := range specRuns {
+		for i := range specRun.Steps {
+			step := &specRun.Steps[i]
+			step.SpecRunID = specRun.ID
+			step.Position = i
+			steps = append(steps, step)
+			dbSteps = append(dbSteps, &database.SpecStep{
+				SpecRunID:    specRun.ID,
+				Position:     i,
+				Keyword:      step.Keyword,
+				Name:         step.Name,
+				Status:       step.Status,
+				Duration:     int64(step.Duration / time.Millisecond),
+				ErrorMessage: step.ErrorMessage,
+			})
+		}
+	}
+	if len(dbSteps) == 0 {
+		return nil
+	}
+
+	if err := db.WithContext(ctx).CreateInBatches(dbSteps, specRunBatchSize).Error; err != nil {
+		return fmt.Errorf("failed to create spec steps: %w", err)
+	}
+
+	for i, dbStep := range dbSteps {
+		steps[i].ID = dbStep.ID
+	}
+
+	return nil
+}
+
+// createSpecAttempts writes the attempts of retried spec runs that have already been assigned IDs
+func createSpecAttempts(ctx context.Context, db *gorm.DB, specRuns []*domain.SpecRun) error {
+	var dbAttempts []*database.SpecAttempt
+	var attempts []*domain.SpecAttempt
+	for _, specRun := range specRuns {
+		for i := range specRun.Attempts {
+			attempt := &specRun.Attempts[i]
+			attempt.SpecRunID = specRun.ID
+			attempt.Attempt = i + 1
+			attempts = append(attempts, attempt)
+			dbAttempts = append(dbAttempts, &database.SpecAttempt{
+				SpecRunID:    specRun.ID,
+				Attempt:      i + 1,
+				Status:       attempt.Status,
+				StartTime:    attempt.StartTime,
+				Duration:     int64(attempt.Duration / time.Millisecond),
+				ErrorMessage: attempt.ErrorMessage,
+				StackTrace:   attempt.StackTrace,
+			})
+		}
+	}
+	if len(dbAttempts) == 0 {
+		return nil
+	}
+
+	if err := db.WithContext(ctx).CreateInBatches(dbAttempts, specRunBatchSize).Error; err != nil {
+		return fmt.Errorf("failed to create spec attempts: %w", err)
+	}
+
+	for i, dbAttempt := range dbAttempts {
+		attempts[i].ID = dbAttempt.ID
+	}
 
 	return nil
 }
@@ -141,6 +252,7 @@ func (r *GormSpecRunRepository) toDomainSpecRun(dbSpecRun *database.SpecRun) *do
 	return &domain.SpecRun{
 		ID:             dbSpecRun.ID,
 		SuiteRunID:     dbSpecRun.SuiteRunID,
+		TestCaseID:     dbSpecRun.TestCaseID,
 		Name:           dbSpecRun.SpecName,
 		ClassName:      "", // Not stored in database
 		Status:         dbSpecRun.Status,
@@ -152,5 +264,52 @@ func (r *GormSpecRunRepository) toDomainSpecRun(dbSpecRun *database.SpecRun) *do
 		StackTrace:     dbSpecRun.StackTrace,
 		RetryCount:     dbSpecRun.RetryCount,
 		IsFlaky:        dbSpecRun.IsFlaky,
+		Quarantined:    dbSpecRun.Quarantined,
+		Steps:          toDomainSpecSteps(dbSpecRun.Steps),
+		Attempts:       toDomainSpecAttempts(dbSpecRun.Attempts),
+	}
+}
+
+// toDomainSpecSteps converts loaded spec steps, returning nil when none were loaded
+func toDomainSpecSteps(dbSteps []database.SpecStep) []domain.SpecStep {
+	if len(dbSteps) == 0 {
+		return nil
+	}
+
+	steps := make([]domain.SpecStep, len(dbSteps))
+	for i, dbStep := range dbSteps {
+		steps[i] = domain.SpecStep{
+			ID:           dbStep.ID,
+			SpecRunID:    dbStep.SpecRunID,
+			Position:     dbStep.Position,
+			Keyword:      dbStep.Keyword,
+			Name:         dbStep.Name,
+			Status:       dbStep.Status,
+			Duration:     time.Duration(dbStep.Duration) * time.Millisecond,
+			ErrorMessage: dbStep.ErrorMessage,
+		}
+	}
+	return steps
+}
+
+// toDomainSpecAttempts converts loaded spec attempts, returning nil when none were loaded
+func toDomainSpecAttempts(dbAttempts []database.SpecAttempt) []domain.SpecAttempt {
+	if len(dbAttempts) == 0 {
+		return nil
+	}
+
+	attempts := make([]domain.SpecAttempt, len(dbAttempts))
+	for i, dbAttempt := range dbAttempts {
+		attempts[i] = domain.SpecAttempt{
+			ID:           dbAttempt.ID,
+			SpecRunID:    dbAttempt.SpecRunID,
+			Attempt:      dbAttempt.Attempt,
+			Status:       dbAttempt.Status,
+			StartTime:    dbAttempt.StartTime,
+			Duration:     time.Duration(dbAttempt.Duration) * time.Millisecond,
+			ErrorMessage: dbAttempt.ErrorMessage,
+			StackTrace:   dbAttempt.StackTrace,
+		}
 	}
+	return attempts
 }
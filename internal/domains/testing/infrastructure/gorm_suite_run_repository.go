@@ -62,6 +62,7 @@ func (r *GormSuiteRunRepository) CreateBatch(ctx context.Context, suiteRuns []*d
 			FailedSpecs:  suiteRun.FailedTests,
 			SkippedSpecs: suiteRun.SkippedTests,
 			Duration:     int64(suiteRun.Duration / time.Millisecond),
+			ShardIndex:   suiteRun.ShardIndex,
 		}
 	}
 
@@ -160,6 +161,7 @@ func (r *GormSuiteRunRepository) GetWithSpecRuns(ctx context.Context, id uint) (
 				StackTrace:     dbSpecRun.StackTrace,
 				RetryCount:     dbSpecRun.RetryCount,
 				IsFlaky:        dbSpecRun.IsFlaky,
+				Quarantined:    dbSpecRun.Quarantined,
 			}
 		}
 	}
@@ -183,5 +185,8 @@ func (r *GormSuiteRunRepository) toDomainSuiteRun(dbSuiteRun *database.SuiteRun)
 		FailedTests:  dbSuiteRun.FailedSpecs,
 		SkippedTests: dbSuiteRun.SkippedSpecs,
 		Duration:     time.Duration(dbSuiteRun.Duration) * time.Millisecond,
+		ShardIndex:   dbSuiteRun.ShardIndex,
+
+		QuarantinedTests: dbSuiteRun.QuarantinedSpecs,
 	}
 }
@@ -0,0 +1,410 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+	"github.com/guidewire-oss/fern-platform/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// testCaseBatchSize is the number of test cases upserted per INSERT statement
+const testCaseBatchSize = 500
+
+// GormTestCaseRepository implements domain.TestCaseRepository using GORM
+type GormTestCaseRepository struct {
+	db *gorm.DB
+}
+
+// NewGormTestCaseRepository creates a new GORM-based test case repository
+func NewGormTestCaseRepository(db *gorm.DB) *GormTestCaseRepository {
+	return &GormTestCaseRepository{db: db}
+}
+
+// GetByID retrieves a test case by ID
+func (r *GormTestCaseRepository) GetByID(ctx context.Context, id uint) (*domain.TestCase, error) {
+	var dbTestCase database.TestCase
+	err := r.db.WithContext(ctx).First(&dbTestCase, id).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, domain.ErrTestCaseNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get test case: %w", err)
+	}
+
+	return toDomainTestCase(&dbTestCase), nil
+}
+
+// FindByProject retrieves a project's test cases whose name contains search, most recently seen first
+func (r *GormTestCaseRepository) FindByProject(ctx context.Context, projectID string, search string, limit int) ([]*domain.TestCase, error) {
+	query := r.db.WithContext(ctx).Where("project_id = ?", projectID)
+	if search != "" {
+		query = query.Where("name ILIKE ?", "%"+escapeLike(search)+"%")
+	}
+
+	var dbTestCases []database.TestCase
+	if err := query.Order("last_seen_at DESC, id").Limit(limit).Find(&dbTestCases).Error; err != nil {
+		return nil, fmt.Errorf("failed to find test cases: %w", err)
+	}
+
+	testCases := make([]*domain.TestCase, len(dbTestCases))
+	for i := range dbTestCases {
+		testCases[i] = toDomainTestCase(&dbTestCases[i])
+	}
+	return testCases, nil
+}
+
+// FindParameterSets retrieves the parameter sets of a parameterised test case
+func (r *GormTestCaseRepository) FindParameterSets(ctx context.Context, parentID uint) ([]*domain.TestCase, error) {
+	var dbTestCases []database.TestCase
+	if err := r.db.WithContext(ctx).Where("parent_id = ?", parentID).Order("name, id").Find(&dbTestCases).Error; err != nil {
+		return nil, fmt.Errorf("failed to find parameter sets: %w", err)
+	}
+
+	testCases := make([]*domain.TestCase, len(dbTestCases))
+	for i := range dbTestCases {
+		testCases[i] = toDomainTestCase(&dbTestCases[i])
+	}
+	return testCases, nil
+}
+
+// testCaseExecutionRow is a row of a test case history query
+type testCaseExecutionRow struct {
+	SpecRunID    uint
+	TestCaseID   uint
+	TestRunID    uint
+	RunID        string
+	Branch       string
+	Environment  string
+	CommitSHA    string
+	Parameters   database.JSONMap `gorm:"type:jsonb"`
+	Status       string
+	StartTime    time.Time
+	DurationMs   int64
+	ErrorMessage string
+	RetryCount   int
+	IsFlaky      bool
+	Quarantined  bool
+}
+
+// GetHistory retrieves the executions of a test case and of its parameter sets matching the
+// filter, newest first
+func (r *GormTestCaseRepository) GetHistory(ctx context.Context, testCaseID uint, filter domain.TestCaseHistoryFilter) ([]domain.TestCaseExecution, error) {
+	query := r.db.WithContext(ctx).Table("spec_runs AS sp").
+		Select(`sp.id AS spec_run_id, sp.test_case_id, su.test_run_id, tr.run_id,
+			COALESCE(tr.branch, '') AS branch, COALESCE(tr.environment, '') AS environment,
+			COALESCE(tr.commit_sha, '') AS commit_sha, tc.parameters, sp.status, sp.start_time, sp.duration_ms,
+			COALESCE(sp.error_message, '') AS error_message, sp.retry_count, sp.is_flaky, sp.quarantined`).
+		Joins("JOIN test_cases tc ON tc.id = sp.test_case_id").
+		Joins("JOIN suite_runs su ON su.id = sp.suite_run_id AND su.deleted_at IS NULL").
+		Joins("JOIN test_runs tr ON tr.id = su.test_run_id AND tr.deleted_at IS NULL").
+		Where("(tc.id = ? OR tc.parent_id = ?) AND sp.deleted_at IS NULL", testCaseID, testCaseID)
+	if !filter.Since.IsZero() {
+		query = query.Where("sp.start_time >= ?", filter.Since)
+	}
+	if filter.Branch != "" {
+		query = query.Where("tr.branch = ?", filter.Branch)
+	}
+	if filter.Environment != "" {
+		query = query.Where("tr.environment = ?", filter.Environment)
+	}
+
+	var rows []testCaseExecutionRow
+	if err := query.Order("sp.start_time DESC, sp.id DESC").Limit(filter.Limit).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get test case history: %w", err)
+	}
+
+	executions := make([]domain.TestCaseExecution, len(rows))
+	for i, row := range rows {
+		executions[i] = domain.TestCaseExecution{
+			SpecRunID:    row.SpecRunID,
+			TestCaseID:   row.TestCaseID,
+			TestRunID:    row.TestRunID,
+			RunID:        row.RunID,
+			Branch:       row.Branch,
+			Environment:  row.Environment,
+			GitCommit:    row.CommitSHA,
+			Parameters:   toDomainParameters(row.Parameters),
+			Status:       row.Status,
+			StartTime:    row.StartTime,
+			Duration:     time.Duration(row.DurationMs) * time.Millisecond,
+			ErrorMessage: row.ErrorMessage,
+			RetryCount:   row.RetryCount,
+			IsFlaky:      row.IsFlaky,
+			Quarantined:  row.Quarantined,
+		}
+	}
+	return executions, nil
+}
+
+// LinkSpecRuns links spec runs stored without a test case, such as those recorded before test
+// cases existed, in ID order
+func (r *GormTestCaseRepository) LinkSpecRuns(ctx context.Context, afterID uint, limit int) (uint, int, error) {
+	var lastID uint
+	var linked int
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var dbSpecRuns []database.SpecRun
+		err := tx.Select("id", "suite_run_id", "spec_name").
+			Where("test_case_id IS NULL AND id > ?", afterID).
+			Order("id").Limit(limit).Find(&dbSpecRuns).Error
+		if err != nil {
+			return fmt.Errorf("failed to find unlinked spec runs: %w", err)
+		}
+		if len(dbSpecRuns) == 0 {
+			return nil
+		}
+
+		specRuns := make([]*domain.SpecRun, len(dbSpecRuns))
+		for i, dbSpecRun := range dbSpecRuns {
+			specRuns[i] = &domain.SpecRun{ID: dbSpecRun.ID, SuiteRunID: dbSpecRun.SuiteRunID, Name: dbSpecRun.SpecName}
+		}
+		if err := linkSpecRunTestCases(ctx, tx, specRuns); err != nil {
+			return err
+		}
+
+		specRunIDs := make(map[uint][]uint)
+		for _, specRun := range specRuns {
+			if specRun.TestCaseID != nil {
+				specRunIDs[*specRun.TestCaseID] = append(specRunIDs[*specRun.TestCaseID], specRun.ID)
+			}
+		}
+		for testCaseID, ids := range specRunIDs {
+			if err := tx.Model(&database.SpecRun{}).Where("id IN ?", ids).UpdateColumn("test_case_id", testCaseID).Error; err != nil {
+				return fmt.Errorf("failed to link spec runs: %w", err)
+			}
+			linked += len(ids)
+		}
+
+		lastID = dbSpecRuns[len(dbSpecRuns)-1].ID
+		return nil
+	})
+	return lastID, linked, err
+}
+
+// testCaseLink is a spec run with the test case it executed and, for a parameter set of a
+// parameterised test, the test case of that test
+type testCaseLink struct {
+	specRun  *domain.SpecRun
+	testCase domain.TestCase
+	parent   *domain.TestCase
+}
+
+func newTestCaseLink(projectID string, suite *domain.SuiteRun, spec *domain.SpecRun) testCaseLink {
+	link := testCaseLink{specRun: spec, testCase: domain.NewTestCase(projectID, suite, spec)}
+	if parent, ok := domain.NewParentTestCase(projectID, suite, spec); ok {
+		link.parent = &parent
+	}
+	return link
+}
+
+// linkTestCases sets the test case of every spec of a run's suites that doesn't have one yet,
+// creating the test cases seen for the first time
+func linkTestCases(ctx context.Context, db *gorm.DB, projectID string, suites []*domain.SuiteRun) error {
+	var links []testCaseLink
+	for _, suite := range suites {
+		for _, spec := range suite.SpecRuns {
+			if spec.TestCaseID == nil {
+				links = append(links, newTestCaseLink(projectID, suite, spec))
+			}
+		}
+	}
+	return assignTestCases(ctx, db, links)
+}
+
+// linkSpecRunTestCases sets the test case of spec runs that don't have one yet from the suite
+// and test run they were stored under
+func linkSpecRunTestCases(ctx context.Context, db *gorm.DB, specRuns []*domain.SpecRun) error {
+	var suiteIDs []uint
+	seen := make(map[uint]bool)
+	for _, spec := range specRuns {
+		if spec.TestCaseID == nil && !seen[spec.SuiteRunID] {
+			seen[spec.SuiteRunID] = true
+			suiteIDs = append(suiteIDs, spec.SuiteRunID)
+		}
+	}
+	if len(suiteIDs) == 0 {
+		return nil
+	}
+
+	var rows []struct {
+		ID        uint
+		SuiteName string
+		ProjectID string
+	}
+	err := db.WithContext(ctx).Table("suite_runs AS su").
+		Select("su.id, su.suite_name, tr.project_id").
+		Joins("JOIN test_runs tr ON tr.id = su.test_run_id").
+		Where("su.id IN ?", suiteIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return fmt.Errorf("failed to look up suites of spec runs: %w", err)
+	}
+	suites := make(map[uint]*domain.SuiteRun, len(rows))
+	projects := make(map[uint]string, len(rows))
+	for _, row := range rows {
+		suites[row.ID] = &domain.SuiteRun{ID: row.ID, Name: row.SuiteName}
+		projects[row.ID] = row.ProjectID
+	}
+
+	var links []testCaseLink
+	for _, spec := range specRuns {
+		suite, ok := suites[spec.SuiteRunID]
+		if spec.TestCaseID != nil || !ok {
+			continue
+		}
+		links = append(links, newTestCaseLink(projects[spec.SuiteRunID], suite, spec))
+	}
+	return assignTestCases(ctx, db, links)
+}
+
+type testCaseKey struct{ projectID, fingerprint string }
+
+func keyOf(testCase domain.TestCase) testCaseKey {
+	return testCaseKey{testCase.ProjectID, testCase.Fingerprint}
+}
+
+// assignTestCases upserts the test cases of links and sets the ID of each link's test case on
+// its spec run. Parameterised tests are upserted before their parameter sets, so that the
+// parameter sets can point at them.
+func assignTestCases(ctx context.Context, db *gorm.DB, links []testCaseLink) error {
+	if len(links) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	byKey := make(map[testCaseKey]*database.TestCase)
+	parentKeys := make(map[*database.TestCase]testCaseKey)
+	var tests, parameterSets []*database.TestCase
+	add := func(testCase domain.TestCase) *database.TestCase {
+		dbTestCase := &database.TestCase{
+			ProjectID:   testCase.ProjectID,
+			SuiteName:   testCase.SuiteName,
+			PackageName: testCase.PackageName,
+			ClassName:   testCase.ClassName,
+			Name:        testCase.Name,
+			Fingerprint: testCase.Fingerprint,
+			FirstSeenAt: now,
+			LastSeenAt:  now,
+		}
+		if len(testCase.Parameters) > 0 {
+			dbTestCase.Parameters = make(database.JSONMap, len(testCase.Parameters))
+			for name, value := range testCase.Parameters {
+				dbTestCase.Parameters[name] = value
+			}
+		}
+		byKey[keyOf(testCase)] = dbTestCase
+		return dbTestCase
+	}
+
+	// A test that is both run on its own and the parent of a parameter set, like a Go test and
+	// its subtests, is one test case
+	for _, link := range links {
+		if link.parent != nil && byKey[keyOf(link.testCase)] == nil {
+			dbTestCase := add(link.testCase)
+			parentKeys[dbTestCase] = keyOf(*link.parent)
+			parameterSets = append(parameterSets, dbTestCase)
+		}
+	}
+	for _, link := range links {
+		testCase := link.testCase
+		if link.parent != nil {
+			testCase = *link.parent
+		}
+		if byKey[keyOf(testCase)] == nil {
+			tests = append(tests, add(testCase))
+		}
+	}
+
+	if err := upsertTestCases(ctx, db, tests, []string{"last_seen_at", "updated_at"}); err != nil {
+		return err
+	}
+	// Parameter sets may themselves be parents, such as nested Go subtests; upsert them a level
+	// at a time once their parents have an ID
+	for pending := parameterSets; len(pending) > 0; {
+		var ready, waiting []*database.TestCase
+		for _, dbTestCase := range pending {
+			if parent := byKey[parentKeys[dbTestCase]]; parent != nil && parent.ID != 0 {
+				parentID := parent.ID
+				dbTestCase.ParentID = &parentID
+				ready = append(ready, dbTestCase)
+			} else {
+				waiting = append(waiting, dbTestCase)
+			}
+		}
+		if len(ready) == 0 {
+			// Parameter sets naming each other as parents are stored without one
+			ready, waiting = waiting, nil
+		}
+		if err := upsertTestCases(ctx, db, ready, []string{"last_seen_at", "updated_at", "parent_id", "parameters"}); err != nil {
+			return err
+		}
+		pending = waiting
+	}
+
+	for _, link := range links {
+		id := byKey[keyOf(link.testCase)].ID
+		link.specRun.TestCaseID = &id
+	}
+	return nil
+}
+
+// upsertTestCases creates test cases, updating the given columns of those that already exist
+func upsertTestCases(ctx context.Context, db *gorm.DB, dbTestCases []*database.TestCase, updates []string) error {
+	if len(dbTestCases) == 0 {
+		return nil
+	}
+
+	// Lock rows in a consistent order so concurrent ingestions of the same tests can't deadlock
+	sort.Slice(dbTestCases, func(i, j int) bool {
+		if dbTestCases[i].ProjectID != dbTestCases[j].ProjectID {
+			return dbTestCases[i].ProjectID < dbTestCases[j].ProjectID
+		}
+		return dbTestCases[i].Fingerprint < dbTestCases[j].Fingerprint
+	})
+	err := db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}, {Name: "fingerprint"}},
+		DoUpdates: clause.AssignmentColumns(updates),
+	}).CreateInBatches(dbTestCases, testCaseBatchSize).Error
+	if err != nil {
+		return fmt.Errorf("failed to create test cases: %w", err)
+	}
+	return nil
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func toDomainTestCase(dbTestCase *database.TestCase) *domain.TestCase {
+	return &domain.TestCase{
+		ID:          dbTestCase.ID,
+		ProjectID:   dbTestCase.ProjectID,
+		ParentID:    dbTestCase.ParentID,
+		SuiteName:   dbTestCase.SuiteName,
+		PackageName: dbTestCase.PackageName,
+		ClassName:   dbTestCase.ClassName,
+		Name:        dbTestCase.Name,
+		Parameters:  toDomainParameters(dbTestCase.Parameters),
+		Fingerprint: dbTestCase.Fingerprint,
+		FirstSeenAt: dbTestCase.FirstSeenAt,
+		LastSeenAt:  dbTestCase.LastSeenAt,
+	}
+}
+
+// toDomainParameters converts a stored parameter set, or returns nil when there is none
+func toDomainParameters(parameters database.JSONMap) map[string]string {
+	if len(parameters) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(parameters))
+	for name, value := range parameters {
+		result[name] = fmt.Sprint(value)
+	}
+	return result
+}
@@ -0,0 +1,185 @@
+package infrastructure_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/infrastructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGormTestCaseRepository_GetByID_NotFound(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormTestCaseRepository(gormDB)
+	mock.ExpectQuery(`SELECT \* FROM "test_cases" WHERE "test_cases"."id" = \$1`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	// Act
+	_, err := repo.GetByID(context.Background(), 7)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrTestCaseNotFound)
+}
+
+func TestGormTestCaseRepository_GetHistory(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormTestCaseRepository(gormDB)
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	start := since.Add(time.Hour)
+
+	mock.ExpectQuery(`SELECT .* FROM spec_runs AS sp JOIN test_cases tc .* WHERE \(\(tc.id = \$1 OR tc.parent_id = \$2\) AND sp.deleted_at IS NULL\) AND sp.start_time >= \$3 AND tr.branch = \$4 ORDER BY sp.start_time DESC, sp.id DESC LIMIT \$5`).
+		WithArgs(7, 7, since, "main", 50).
+		WillReturnRows(sqlmock.NewRows([]string{"spec_run_id", "test_case_id", "test_run_id", "run_id", "branch", "environment", "commit_sha", "parameters", "status", "start_time", "duration_ms", "error_message", "retry_count", "is_flaky"}).
+			AddRow(31, 7, 4, "build-4", "main", "staging", "abc123", nil, "failed", start, 1500, "timed out", 1, false).
+			AddRow(30, 8, 3, "build-3", "main", "staging", "abc122", []byte(`{"subtest":"zero"}`), "passed", start, 900, "", 0, false))
+
+	// Act
+	executions, err := repo.GetHistory(context.Background(), 7, domain.TestCaseHistoryFilter{Since: since, Branch: "main", Limit: 50})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, executions, 2)
+	assert.Equal(t, uint(31), executions[0].SpecRunID)
+	assert.Nil(t, executions[0].Parameters)
+	assert.Equal(t, "build-4", executions[0].RunID)
+	assert.Equal(t, "staging", executions[0].Environment)
+	assert.Equal(t, "abc123", executions[0].GitCommit)
+	assert.Equal(t, 1500*time.Millisecond, executions[0].Duration)
+	assert.Equal(t, "timed out", executions[0].ErrorMessage)
+	assert.Equal(t, uint(8), executions[1].TestCaseID)
+	assert.Equal(t, map[string]string{"subtest": "zero"}, executions[1].Parameters)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormSpecRunRepository_Create_LinksTestCase(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormSpecRunRepository(gormDB)
+	specRun := &domain.SpecRun{SuiteRunID: 3, Name: "adds an item", Status: "passed"}
+	fingerprint := domain.TestCaseFingerprint("proj-1", "Cart", "", "", "adds an item")
+
+	mock.ExpectQuery(`SELECT su.id, su.suite_name, tr.project_id FROM suite_runs AS su JOIN test_runs tr`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "suite_name", "project_id"}).AddRow(3, "Cart", "proj-1"))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "test_cases" .* ON CONFLICT \("project_id","fingerprint"\) DO UPDATE SET "last_seen_at"="excluded"."last_seen_at","updated_at"="excluded"."updated_at" RETURNING "id"`).
+		WithArgs("proj-1", nil, "Cart", "", "", "adds an item", nil, fingerprint, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "spec_runs"`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 3, 12, "adds an item", "passed",
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), false).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(40))
+	mock.ExpectCommit()
+
+	// Act
+	err := repo.Create(context.Background(), specRun)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, specRun.TestCaseID)
+	assert.Equal(t, uint(12), *specRun.TestCaseID)
+	assert.Equal(t, uint(40), specRun.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormSpecRunRepository_CreateBatch_LinksParameterSets(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormSpecRunRepository(gormDB)
+	specRuns := []*domain.SpecRun{
+		{SuiteRunID: 3, Name: "add", ParentName: "add", Parameters: map[string]string{"a": "1"}, Status: "passed"},
+		{SuiteRunID: 3, Name: "add", Parameters: map[string]string{"a": "2"}, Status: "failed"},
+	}
+	parent := domain.TestCaseFingerprint("proj-1", "Calc", "", "", "add")
+
+	mock.ExpectQuery(`SELECT su.id, su.suite_name, tr.project_id FROM suite_runs AS su JOIN test_runs tr`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "suite_name", "project_id"}).AddRow(3, "Calc", "proj-1"))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "test_cases" .* DO UPDATE SET "last_seen_at"="excluded"."last_seen_at","updated_at"="excluded"."updated_at" RETURNING "id"`).
+		WithArgs("proj-1", nil, "Calc", "", "", "add", nil, parent, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(20))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "test_cases" .* DO UPDATE SET "last_seen_at"="excluded"."last_seen_at","updated_at"="excluded"."updated_at","parent_id"="excluded"."parent_id","parameters"="excluded"."parameters" RETURNING "id"`).
+		WithArgs(
+			"proj-1", 20, "Calc", "", "", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			"proj-1", 20, "Calc", "", "", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+		).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(21).AddRow(22))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`SELECT DISTINCT tc.id FROM test_cases AS tc JOIN test_quarantines q`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "spec_runs"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(40).AddRow(41))
+	mock.ExpectCommit()
+
+	// Act
+	err := repo.CreateBatch(context.Background(), specRuns)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotNil(t, specRuns[0].TestCaseID)
+	require.NotNil(t, specRuns[1].TestCaseID)
+	assert.NotEqual(t, *specRuns[0].TestCaseID, *specRuns[1].TestCaseID)
+	assert.ElementsMatch(t, []uint{21, 22}, []uint{*specRuns[0].TestCaseID, *specRuns[1].TestCaseID})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormSpecRunRepository_Create_CountsQuarantinedFailure(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormSpecRunRepository(gormDB)
+	specRun := &domain.SpecRun{SuiteRunID: 3, Name: "adds an item", Status: "failed"}
+
+	mock.ExpectQuery(`SELECT su.id, su.suite_name, tr.project_id FROM suite_runs AS su JOIN test_runs tr`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "suite_name", "project_id"}).AddRow(3, "Cart", "proj-1"))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "test_cases"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`SELECT DISTINCT tc.id FROM test_cases AS tc JOIN test_quarantines q ON q.test_case_id = tc.id OR q.test_case_id = tc.parent_id WHERE tc.id IN \(\$1\) AND q.lifted_at IS NULL AND q.expires_at > \$2`).
+		WithArgs(12, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "spec_runs"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(40))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "suite_runs" SET "quarantined_specs"=quarantined_specs \+ \$1 WHERE id = \$2`).
+		WithArgs(1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "test_runs" SET "quarantined_tests"=quarantined_tests \+ \$1 WHERE id = \(SELECT "test_run_id" FROM "suite_runs" WHERE id = \$2`).
+		WithArgs(1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	err := repo.Create(context.Background(), specRun)
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, specRun.Quarantined)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,260 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+	"github.com/guidewire-oss/fern-platform/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StartLiveRun creates a streamed run, or resumes the running run with the same run ID so that
+// a reporter whose connection dropped can carry on where it left off
+func (r *GormTestRunRepository) StartLiveRun(ctx context.Context, testRun *domain.TestRun) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		var run database.TestRun
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Where("run_id = ?", testRun.RunID).First(&run).Error
+		if err == gorm.ErrRecordNotFound {
+			testRun.Status = "running"
+			testRun.LastHeartbeatAt = &now
+			return NewGormTestRunRepository(tx).Create(ctx, testRun)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to look up test run: %w", err)
+		}
+		if run.ProjectID != testRun.ProjectID {
+			return domain.ErrRunIDConflict
+		}
+		if run.ShardTotal > 0 {
+			return domain.ErrShardMismatch
+		}
+		if run.DeletedAt.Valid || run.Status != "running" {
+			return domain.ErrTestRunFinished
+		}
+
+		err = tx.Model(&database.TestRun{}).Where("id = ?", run.ID).Update("last_heartbeat_at", now).Error
+		if err != nil {
+			return fmt.Errorf("failed to resume test run: %w", err)
+		}
+
+		run.LastHeartbeatAt = &now
+		setLiveRun(testRun, &run)
+		return nil
+	})
+}
+
+// AddLiveSpecRun stores a spec of a streamed run. The run's row is locked while the spec is
+// added so that specs streamed concurrently for the same suite share it.
+func (r *GormTestRunRepository) AddLiveSpecRun(ctx context.Context, testRun *domain.TestRun, suiteName string, specRun *domain.SpecRun) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		run, err := lockLiveRun(tx, testRun.ID)
+		if err != nil {
+			return err
+		}
+
+		suiteID, err := liveSuiteRunID(ctx, tx, run.ID, suiteName, specRun.StartTime)
+		if err != nil {
+			return err
+		}
+
+		specRun.SuiteRunID = suiteID
+		if err := NewGormSpecRunRepository(tx).Create(ctx, specRun); err != nil {
+			return err
+		}
+		if err := createSpecAttachments(ctx, tx, run.ProjectID, run.ID, []*domain.SpecRun{specRun}); err != nil {
+			return err
+		}
+
+		total, passed, failed, skipped := domain.LiveSpecCounts(specRun.Status)
+		err = tx.Model(&database.SuiteRun{}).Where("id = ?", suiteID).Updates(map[string]interface{}{
+			"total_specs":   gorm.Expr("total_specs + ?", total),
+			"passed_specs":  gorm.Expr("passed_specs + ?", passed),
+			"failed_specs":  gorm.Expr("failed_specs + ?", failed),
+			"skipped_specs": gorm.Expr("skipped_specs + ?", skipped),
+			"duration_ms":   gorm.Expr("duration_ms + ?", int64(specRun.Duration/time.Millisecond)),
+			"updated_at":    time.Now(),
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to update suite run counters: %w", err)
+		}
+
+		now := time.Now()
+		run.TotalTests += total
+		run.PassedTests += passed
+		run.FailedTests += failed
+		run.SkippedTests += skipped
+		if specRun.Quarantined {
+			run.QuarantinedTests++ // Counted in the database when the spec was stored
+		}
+		run.LastHeartbeatAt = &now
+		err = tx.Model(&database.TestRun{}).Where("id = ?", run.ID).Updates(map[string]interface{}{
+			"total_tests":       run.TotalTests,
+			"passed_tests":      run.PassedTests,
+			"failed_tests":      run.FailedTests,
+			"skipped_tests":     run.SkippedTests,
+			"last_heartbeat_at": now,
+			"updated_at":        now,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to update test run counters: %w", err)
+		}
+
+		setLiveRun(testRun, run)
+		return nil
+	})
+}
+
+// RecordHeartbeat moves the last heartbeat of a running streamed run forward
+func (r *GormTestRunRepository) RecordHeartbeat(ctx context.Context, testRunID uint, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&database.TestRun{}).
+		Where("id = ? AND status = ?", testRunID, "running").
+		Update("last_heartbeat_at", at)
+	if result.Error != nil {
+		return fmt.Errorf("failed to record heartbeat: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrTestRunFinished
+	}
+	return nil
+}
+
+// FinishLiveRun closes a streamed run when its reporter says it is done
+func (r *GormTestRunRepository) FinishLiveRun(ctx context.Context, testRun *domain.TestRun) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		run, err := lockLiveRun(tx, testRun.ID)
+		if err != nil {
+			return err
+		}
+
+		endTime := time.Now()
+		if testRun.EndTime != nil {
+			endTime = *testRun.EndTime
+		}
+		if err := closeLiveRun(tx, run, domain.LiveRunStatus(testRun.Status, run.FailedTests), "", endTime); err != nil {
+			return err
+		}
+
+		setLiveRun(testRun, run)
+		return nil
+	})
+}
+
+// AbortStalledRuns closes streamed runs whose reporter went away without finishing them.
+// They end at their last heartbeat, the last time anything was heard from the reporter.
+func (r *GormTestRunRepository) AbortStalledRuns(ctx context.Context, heartbeatBefore time.Time) (int, error) {
+	aborted := 0
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var runs []database.TestRun
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND last_heartbeat_at <= ?", "running", heartbeatBefore).
+			Find(&runs).Error
+		if err != nil {
+			return err
+		}
+
+		for i := range runs {
+			if err := closeLiveRun(tx, &runs[i], "aborted", "aborted", *runs[i].LastHeartbeatAt); err != nil {
+				return err
+			}
+			aborted++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to abort stalled runs: %w", err)
+	}
+
+	return aborted, nil
+}
+
+// lockLiveRun locks a streamed run that is still running
+func lockLiveRun(tx *gorm.DB, testRunID uint) (*database.TestRun, error) {
+	var run database.TestRun
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&run, testRunID).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("test run not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get test run: %w", err)
+	}
+	if run.Status != "running" {
+		return nil, domain.ErrTestRunFinished
+	}
+	return &run, nil
+}
+
+// liveSuiteRunID gets the suite of a streamed run with the given name, creating it for its first spec
+func liveSuiteRunID(ctx context.Context, tx *gorm.DB, testRunID uint, suiteName string, startTime time.Time) (uint, error) {
+	var suite database.SuiteRun
+	err := tx.Where("test_run_id = ? AND suite_name = ?", testRunID, suiteName).Order("id").First(&suite).Error
+	if err == nil {
+		return suite.ID, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return 0, fmt.Errorf("failed to look up suite run: %w", err)
+	}
+
+	suiteRun := &domain.SuiteRun{TestRunID: testRunID, Name: suiteName, Status: "running", StartTime: startTime}
+	if err := NewGormSuiteRunRepository(tx).Create(ctx, suiteRun); err != nil {
+		return 0, err
+	}
+	return suiteRun.ID, nil
+}
+
+// closeLiveRun ends a streamed run and the suites still running in it at endTime. Suites are
+// closed with suiteStatus, or as failed or passed by their counters if it is empty.
+func closeLiveRun(tx *gorm.DB, run *database.TestRun, status, suiteStatus string, endTime time.Time) error {
+	var suites []database.SuiteRun
+	if err := tx.Where("test_run_id = ? AND status = ?", run.ID, "running").Find(&suites).Error; err != nil {
+		return fmt.Errorf("failed to load suite runs: %w", err)
+	}
+	for _, suite := range suites {
+		closedStatus := suiteStatus
+		if closedStatus == "" {
+			closedStatus = domain.LiveRunStatus("", suite.FailedSpecs)
+		}
+		err := tx.Model(&database.SuiteRun{}).Where("id = ?", suite.ID).Updates(map[string]interface{}{
+			"status":     closedStatus,
+			"end_time":   endTime,
+			"updated_at": time.Now(),
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to close suite run: %w", err)
+		}
+	}
+
+	run.Status = status
+	run.EndTime = &endTime
+	run.Duration = int64(endTime.Sub(run.StartTime) / time.Millisecond)
+	err := tx.Model(&database.TestRun{}).Where("id = ?", run.ID).Updates(map[string]interface{}{
+		"status":      run.Status,
+		"end_time":    run.EndTime,
+		"duration_ms": run.Duration,
+		"updated_at":  time.Now(),
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to close test run: %w", err)
+	}
+	return nil
+}
+
+// setLiveRun copies a streamed run's progress into testRun
+func setLiveRun(testRun *domain.TestRun, run *database.TestRun) {
+	testRun.ID = run.ID
+	testRun.Status = run.Status
+	testRun.StartTime = run.StartTime
+	testRun.EndTime = run.EndTime
+	testRun.Duration = time.Duration(run.Duration) * time.Millisecond
+	testRun.TotalTests = run.TotalTests
+	testRun.PassedTests = run.PassedTests
+	testRun.FailedTests = run.FailedTests
+	testRun.SkippedTests = run.SkippedTests
+	testRun.QuarantinedTests = run.QuarantinedTests
+	testRun.LastHeartbeatAt = run.LastHeartbeatAt
+}
@@ -0,0 +1,92 @@
+package infrastructure_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/infrastructure"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGormTestRunRepository_StartLiveRun_FinishedRun(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormTestRunRepository(gormDB)
+	testRun := &domain.TestRun{ProjectID: "proj-a", RunID: "build-42"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "test_runs" WHERE run_id = .* FOR UPDATE`).
+		WithArgs("build-42", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "project_id", "run_id", "status"}).AddRow(9, "proj-a", "build-42", "passed"))
+	mock.ExpectRollback()
+
+	// Act
+	err := repo.StartLiveRun(context.Background(), testRun)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrTestRunFinished)
+	assert.Zero(t, testRun.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormTestRunRepository_RecordHeartbeat_RunNotRunning(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormTestRunRepository(gormDB)
+	at := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "test_runs" SET "last_heartbeat_at"=.* WHERE \(id = .* AND status = .*\)`).
+		WithArgs(at, sqlmock.AnyArg(), 9, "running").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	// Act
+	err := repo.RecordHeartbeat(context.Background(), 9, at)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrTestRunFinished)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormTestRunRepository_AbortStalledRuns(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormTestRunRepository(gormDB)
+	cutoff := time.Now().Add(-5 * time.Minute)
+	startTime := cutoff.Add(-time.Hour)
+	lastHeartbeat := cutoff.Add(-time.Minute)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "test_runs" WHERE \(status = .* AND last_heartbeat_at <= .*\) .* FOR UPDATE SKIP LOCKED`).
+		WithArgs("running", cutoff).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "start_time", "last_heartbeat_at"}).
+			AddRow(9, "running", startTime, lastHeartbeat))
+	mock.ExpectQuery(`SELECT \* FROM "suite_runs" WHERE \(test_run_id = .* AND status = .*\)`).
+		WithArgs(9, "running").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "test_run_id", "status"}).AddRow(3, 9, "running"))
+	mock.ExpectExec(`UPDATE "suite_runs" SET "end_time"=.*,"status"=.*,"updated_at"=.* WHERE id = .*`).
+		WithArgs(lastHeartbeat, "aborted", sqlmock.AnyArg(), 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "test_runs" SET "duration_ms"=.*,"end_time"=.*,"status"=.*,"updated_at"=.* WHERE id = .*`).
+		WithArgs(int64(59*time.Minute/time.Millisecond), lastHeartbeat, "aborted", sqlmock.AnyArg(), 9).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	aborted, err := repo.AbortStalledRuns(context.Background(), cutoff)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, aborted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
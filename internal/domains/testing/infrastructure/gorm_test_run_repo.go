@@ -8,6 +8,7 @@ import (
 	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
 	"github.com/guidewire-oss/fern-platform/pkg/database"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GormTestRunRepository implements domain.TestRunRepository using GORM
@@ -37,6 +38,11 @@ func (r *GormTestRunRepository) Create(ctx context.Context, testRun *domain.Test
 		SkippedTests: testRun.SkippedTests,
 		Environment:  testRun.Environment,
 		Metadata:     database.JSONMap(testRun.Metadata),
+		ShardTotal:   testRun.ShardTotal,
+		Name:         testRun.Name,
+		Source:       testRun.Source,
+
+		LastHeartbeatAt: testRun.LastHeartbeatAt,
 	}
 
 	if err := r.db.WithContext(ctx).Create(dbTestRun).Error; err != nil {
@@ -138,7 +144,13 @@ func (r *GormTestRunRepository) GetLatestByProjectID(ctx context.Context, projec
 // GetWithDetails retrieves a test run with all its suites and specs
 func (r *GormTestRunRepository) GetWithDetails(ctx context.Context, id uint) (*domain.TestRun, error) {
 	var dbTestRun database.TestRun
-	if err := r.db.WithContext(ctx).Preload("SuiteRuns.SpecRuns").First(&dbTestRun, id).Error; err != nil {
+	err := r.db.WithContext(ctx).
+		Preload("SuiteRuns", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("SuiteRuns.SpecRuns", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("SuiteRuns.SpecRuns.Steps", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Preload("SuiteRuns.SpecRuns.Attempts", func(db *gorm.DB) *gorm.DB { return db.Order("attempt") }).
+		First(&dbTestRun, id).Error
+	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("test run not found")
 		}
@@ -176,16 +188,21 @@ func (r *GormTestRunRepository) GetTestRunSummary(ctx context.Context, projectID
 	}
 	summary.TotalRuns = int(totalCount)
 
-	// Get passed runs
+	// Get passed runs. A run that failed only through quarantined tests counts as passed, as
+	// quarantined failures are left out of pass rates.
 	var passedCount int64
-	if err := r.db.WithContext(ctx).Model(&database.TestRun{}).Where("project_id = ? AND status = ?", projectID, "passed").Count(&passedCount).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&database.TestRun{}).
+		Where("project_id = ? AND (status = ? OR (status = ? AND failed_tests > 0 AND failed_tests <= quarantined_tests))", projectID, "passed", "failed").
+		Count(&passedCount).Error; err != nil {
 		return nil, fmt.Errorf("failed to count passed runs: %w", err)
 	}
 	summary.PassedRuns = int(passedCount)
 
 	// Get failed runs
 	var failedCount int64
-	if err := r.db.WithContext(ctx).Model(&database.TestRun{}).Where("project_id = ? AND status = ?", projectID, "failed").Count(&failedCount).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&database.TestRun{}).
+		Where("project_id = ? AND status = ? AND (failed_tests = 0 OR failed_tests > quarantined_tests)", projectID, "failed").
+		Count(&failedCount).Error; err != nil {
 		return nil, fmt.Errorf("failed to count failed runs: %w", err)
 	}
 	summary.FailedRuns = int(failedCount)
@@ -225,7 +242,7 @@ func (r *GormTestRunRepository) toDomainTestRun(dbTestRun *database.TestRun) *do
 		ID:           dbTestRun.ID,
 		RunID:        dbTestRun.RunID,
 		ProjectID:    dbTestRun.ProjectID,
-		Name:         "", // Not stored in database model
+		Name:         dbTestRun.Name,
 		Status:       dbTestRun.Status,
 		Branch:       dbTestRun.Branch,
 		GitBranch:    dbTestRun.Branch, // Use same value
@@ -238,10 +255,17 @@ func (r *GormTestRunRepository) toDomainTestRun(dbTestRun *database.TestRun) *do
 		FailedTests:  dbTestRun.FailedTests,
 		SkippedTests: dbTestRun.SkippedTests,
 		Environment:  dbTestRun.Environment,
-		Source:       "", // Not stored in database model
+		Source:       dbTestRun.Source,
 		SessionID:    "", // Not stored in database model
 		Metadata:     metadata,
 		SuiteRuns:    suiteRuns,
+
+		QuarantinedTests: dbTestRun.QuarantinedTests,
+
+		ShardTotal:     dbTestRun.ShardTotal,
+		ShardsReceived: dbTestRun.ShardsReceived,
+
+		LastHeartbeatAt: dbTestRun.LastHeartbeatAt,
 	}
 }
 
@@ -267,7 +291,10 @@ func (r *GormTestRunRepository) toDomainSuiteRun(dbSuite *database.SuiteRun) dom
 		FailedTests:  dbSuite.FailedSpecs,
 		SkippedTests: dbSuite.SkippedSpecs,
 		Duration:     time.Duration(dbSuite.Duration) * time.Millisecond,
+		ShardIndex:   dbSuite.ShardIndex,
 		SpecRuns:     specRuns,
+
+		QuarantinedTests: dbSuite.QuarantinedSpecs,
 	}
 }
 
@@ -276,6 +303,7 @@ func (r *GormTestRunRepository) toDomainSpecRun(dbSpec *database.SpecRun) *domai
 	return &domain.SpecRun{
 		ID:             dbSpec.ID,
 		SuiteRunID:     dbSpec.SuiteRunID,
+		TestCaseID:     dbSpec.TestCaseID,
 		Name:           dbSpec.SpecName,
 		ClassName:      "", // Not in database model
 		Status:         dbSpec.Status,
@@ -287,6 +315,9 @@ func (r *GormTestRunRepository) toDomainSpecRun(dbSpec *database.SpecRun) *domai
 		StackTrace:     dbSpec.StackTrace,
 		RetryCount:     dbSpec.RetryCount,
 		IsFlaky:        dbSpec.IsFlaky,
+		Quarantined:    dbSpec.Quarantined,
+		Steps:          toDomainSpecSteps(dbSpec.Steps),
+		Attempts:       toDomainSpecAttempts(dbSpec.Attempts),
 	}
 }
 
@@ -324,3 +355,161 @@ func (r *GormTestRunRepository) GetRecent(ctx context.Context, limit int) ([]*do
 
 	return testRuns, nil
 }
+
+// CreateWithHierarchy creates a test run with its suites, specs and tags in one transaction.
+// A run with the same run ID in the same project is replaced rather than duplicated.
+func (r *GormTestRunRepository) CreateWithHierarchy(ctx context.Context, testRun *domain.TestRun, tagNames []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := upsertTestRun(ctx, tx, testRun); err != nil {
+			return err
+		}
+
+		suites := make([]*domain.SuiteRun, len(testRun.SuiteRuns))
+		for i := range testRun.SuiteRuns {
+			testRun.SuiteRuns[i].TestRunID = testRun.ID
+			suites[i] = &testRun.SuiteRuns[i]
+		}
+		if err := NewGormSuiteRunRepository(tx).CreateBatch(ctx, suites); err != nil {
+			return err
+		}
+
+		if err := linkTestCases(ctx, tx, testRun.ProjectID, suites); err != nil {
+			return err
+		}
+
+		var specs []*domain.SpecRun
+		for _, suite := range suites {
+			for _, spec := range suite.SpecRuns {
+				spec.SuiteRunID = suite.ID
+				specs = append(specs, spec)
+			}
+		}
+		if err := NewGormSpecRunRepository(tx).CreateBatch(ctx, specs); err != nil {
+			return err
+		}
+		if err := createSpecAttachments(ctx, tx, testRun.ProjectID, testRun.ID, specs); err != nil {
+			return err
+		}
+
+		return addTestRunTags(tx, testRun.ID, tagNames)
+	})
+}
+
+// upsertTestRun creates the test run, or overwrites the existing run with the same run ID
+// and discards its suites, specs and tags
+func upsertTestRun(ctx context.Context, tx *gorm.DB, testRun *domain.TestRun) error {
+	var existing database.TestRun
+	err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Where("run_id = ?", testRun.RunID).First(&existing).Error
+	if err == gorm.ErrRecordNotFound {
+		return NewGormTestRunRepository(tx).Create(ctx, testRun)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up test run: %w", err)
+	}
+	if existing.ProjectID != testRun.ProjectID {
+		return domain.ErrRunIDConflict
+	}
+
+	suiteIDs := tx.Unscoped().Model(&database.SuiteRun{}).Select("id").Where("test_run_id = ?", existing.ID)
+	specIDs := tx.Unscoped().Model(&database.SpecRun{}).Select("id").Where("suite_run_id IN (?)", suiteIDs)
+	if err := tx.Where("suite_run_id IN (?)", suiteIDs).Delete(&database.Attachment{}).Error; err != nil {
+		return fmt.Errorf("failed to replace attachments: %w", err)
+	}
+	if err := tx.Where("spec_run_id IN (?)", specIDs).Delete(&database.SpecStep{}).Error; err != nil {
+		return fmt.Errorf("failed to replace spec steps: %w", err)
+	}
+	if err := tx.Where("spec_run_id IN (?)", specIDs).Delete(&database.SpecOutput{}).Error; err != nil {
+		return fmt.Errorf("failed to replace spec output: %w", err)
+	}
+	if err := tx.Where("spec_run_id IN (?)", specIDs).Delete(&database.SpecAttempt{}).Error; err != nil {
+		return fmt.Errorf("failed to replace spec attempts: %w", err)
+	}
+	if err := tx.Unscoped().Where("suite_run_id IN (?)", suiteIDs).Delete(&database.SpecRun{}).Error; err != nil {
+		return fmt.Errorf("failed to replace spec runs: %w", err)
+	}
+	if err := tx.Unscoped().Where("test_run_id = ?", existing.ID).Delete(&database.SuiteRun{}).Error; err != nil {
+		return fmt.Errorf("failed to replace suite runs: %w", err)
+	}
+	if err := tx.Where("test_run_id = ?", existing.ID).Delete(&database.TestRunShard{}).Error; err != nil {
+		return fmt.Errorf("failed to replace test run shards: %w", err)
+	}
+	if err := tx.Where("test_run_id = ?", existing.ID).Delete(&database.TestRunTag{}).Error; err != nil {
+		return fmt.Errorf("failed to replace test run tags: %w", err)
+	}
+
+	updates := map[string]interface{}{
+		"branch":            testRun.Branch,
+		"commit_sha":        testRun.GitCommit,
+		"status":            testRun.Status,
+		"start_time":        testRun.StartTime,
+		"end_time":          testRun.EndTime,
+		"duration_ms":       int64(testRun.Duration / time.Millisecond),
+		"total_tests":       testRun.TotalTests,
+		"passed_tests":      testRun.PassedTests,
+		"failed_tests":      testRun.FailedTests,
+		"skipped_tests":     testRun.SkippedTests,
+		"quarantined_tests": 0,
+		"environment":       testRun.Environment,
+		"metadata":          database.JSONMap(testRun.Metadata),
+		"shard_total":       testRun.ShardTotal,
+		"shards_received":   0,
+		"updated_at":        time.Now(),
+		"deleted_at":        nil,
+		"name":              testRun.Name,
+		"source":            testRun.Source,
+	}
+	if err := tx.Unscoped().Model(&database.TestRun{}).Where("id = ?", existing.ID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to replace test run: %w", err)
+	}
+
+	testRun.ID = existing.ID
+	return nil
+}
+
+// AddTags attaches tags to a test run by name, creating tags that don't exist yet
+func (r *GormTestRunRepository) AddTags(ctx context.Context, testRunID uint, tagNames []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return addTestRunTags(tx, testRunID, tagNames)
+	})
+}
+
+// addTestRunTags gets or creates each tag and links it to the test run, ignoring existing links
+func addTestRunTags(tx *gorm.DB, testRunID uint, tagNames []string) error {
+	for _, name := range tagNames {
+		tag := database.Tag{Name: name}
+		if err := tx.Where("name = ?", name).FirstOrCreate(&tag).Error; err != nil {
+			return fmt.Errorf("failed to get or create tag %q: %w", name, err)
+		}
+
+		link := database.TestRunTag{TestRunID: testRunID, TagID: tag.ID}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&link).Error; err != nil {
+			return fmt.Errorf("failed to tag test run: %w", err)
+		}
+	}
+	return nil
+}
+
+// FindWithUnpersistedSuiteRuns finds legacy test runs whose suites only live in metadata
+func (r *GormTestRunRepository) FindWithUnpersistedSuiteRuns(ctx context.Context, afterID uint, limit int) ([]*domain.TestRun, error) {
+	var dbTestRuns []database.TestRun
+	query := r.db.WithContext(ctx).
+		Where("id > ?", afterID).
+		Where("metadata->'suite_runs' IS NOT NULL").
+		Where("NOT EXISTS (SELECT 1 FROM suite_runs WHERE suite_runs.test_run_id = test_runs.id AND suite_runs.deleted_at IS NULL)").
+		Order("id ASC")
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Find(&dbTestRuns).Error; err != nil {
+		return nil, fmt.Errorf("failed to find test runs with unpersisted suites: %w", err)
+	}
+
+	testRuns := make([]*domain.TestRun, len(dbTestRuns))
+	for i, dbTestRun := range dbTestRuns {
+		testRuns[i] = r.toDomainTestRun(&dbTestRun)
+	}
+
+	return testRuns, nil
+}
@@ -0,0 +1,117 @@
+package infrastructure_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/infrastructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func setupMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *gorm.DB) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{})
+	require.NoError(t, err)
+
+	return db, mock, gormDB
+}
+
+func TestGormTestRunRepository_CreateWithHierarchy_RunIDOwnedByOtherProject(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormTestRunRepository(gormDB)
+	testRun := &domain.TestRun{ProjectID: "proj-a", RunID: "shared-run-1"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "test_runs" WHERE run_id = .* FOR UPDATE`).
+		WithArgs("shared-run-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "project_id", "run_id"}).AddRow(9, "proj-b", "shared-run-1"))
+	mock.ExpectRollback()
+
+	// Act
+	err := repo.CreateWithHierarchy(context.Background(), testRun, nil)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrRunIDConflict)
+	assert.Zero(t, testRun.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormTestRunRepository_CreateWithHierarchy_ReplacesExistingRun(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormTestRunRepository(gormDB)
+	testRun := &domain.TestRun{ProjectID: "proj-a", RunID: "run-1", Name: "nightly", Source: "fern-ginkgo-client"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "test_runs" WHERE run_id = .* FOR UPDATE`).
+		WithArgs("run-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "project_id", "run_id"}).AddRow(9, "proj-a", "run-1"))
+	mock.ExpectExec(`DELETE FROM "attachments"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM "spec_steps"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM "spec_outputs"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM "spec_attempts"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM "spec_runs"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM "suite_runs"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM "test_run_shards"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM "test_run_tags" WHERE test_run_id = `).
+		WithArgs(9).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`UPDATE "test_runs" SET .*"name"=.*"source"=.* WHERE id = `).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	err := repo.CreateWithHierarchy(context.Background(), testRun, nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, uint(9), testRun.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormTestRunRepository_GetTestRunSummary_CountsRunsFailedOnlyByQuarantinedTestsAsPassed(t *testing.T) {
+	// Arrange
+	db, mock, gormDB := setupMockDB(t)
+	defer db.Close()
+
+	repo := infrastructure.NewGormTestRunRepository(gormDB)
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "test_runs" WHERE project_id = \$1`).
+		WithArgs("proj-a").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "test_runs" WHERE \(project_id = \$1 AND \(status = \$2 OR \(status = \$3 AND failed_tests > 0 AND failed_tests <= quarantined_tests\)\)\)`).
+		WithArgs("proj-a", "passed", "failed").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "test_runs" WHERE \(project_id = \$1 AND status = \$2 AND \(failed_tests = 0 OR failed_tests > quarantined_tests\)\)`).
+		WithArgs("proj-a", "failed").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT AVG\(duration\) FROM "test_runs" WHERE project_id = \$1`).
+		WithArgs("proj-a").
+		WillReturnRows(sqlmock.NewRows([]string{"avg"}).AddRow(1500.0))
+
+	// Act
+	summary, err := repo.GetTestRunSummary(context.Background(), "proj-a")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 4, summary.TotalRuns)
+	assert.Equal(t, 3, summary.PassedRuns)
+	assert.Equal(t, 1, summary.FailedRuns)
+	assert.Equal(t, 0.75, summary.SuccessRate)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
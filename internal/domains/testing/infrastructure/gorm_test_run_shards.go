@@ -0,0 +1,294 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+	"github.com/guidewire-oss/fern-platform/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MergeShard adds a shard to its run group in one transaction. The group's run row is locked
+// for the duration so that concurrently arriving shards are merged one after another.
+func (r *GormTestRunRepository) MergeShard(ctx context.Context, testRun *domain.TestRun, shard domain.ShardInfo, tagNames []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		run, err := lockShardGroup(tx, testRun, shard)
+		if err != nil {
+			return err
+		}
+
+		// A resubmitted shard replaces what it reported before
+		if err := deleteShardSuites(tx, run.ID, shard.Index); err != nil {
+			return err
+		}
+
+		suites := make([]*domain.SuiteRun, len(testRun.SuiteRuns))
+		for i := range testRun.SuiteRuns {
+			index := shard.Index
+			testRun.SuiteRuns[i].TestRunID = run.ID
+			testRun.SuiteRuns[i].ShardIndex = &index
+			suites[i] = &testRun.SuiteRuns[i]
+		}
+		if err := NewGormSuiteRunRepository(tx).CreateBatch(ctx, suites); err != nil {
+			return err
+		}
+
+		if err := linkTestCases(ctx, tx, run.ProjectID, suites); err != nil {
+			return err
+		}
+
+		var specs []*domain.SpecRun
+		for _, suite := range suites {
+			for _, spec := range suite.SpecRuns {
+				spec.SuiteRunID = suite.ID
+				specs = append(specs, spec)
+			}
+		}
+		if err := NewGormSpecRunRepository(tx).CreateBatch(ctx, specs); err != nil {
+			return err
+		}
+		if err := createSpecAttachments(ctx, tx, run.ProjectID, run.ID, specs); err != nil {
+			return err
+		}
+
+		shardRow := &database.TestRunShard{
+			TestRunID:    run.ID,
+			ShardIndex:   shard.Index,
+			Status:       testRun.Status,
+			StartTime:    testRun.StartTime,
+			EndTime:      testRun.EndTime,
+			TotalTests:   testRun.TotalTests,
+			PassedTests:  testRun.PassedTests,
+			FailedTests:  testRun.FailedTests,
+			SkippedTests: testRun.SkippedTests,
+			ReceivedAt:   time.Now(),
+		}
+		err = tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "test_run_id"}, {Name: "shard_index"}},
+			UpdateAll: true,
+		}).Create(shardRow).Error
+		if err != nil {
+			return fmt.Errorf("failed to record shard: %w", err)
+		}
+
+		if err := addTestRunTags(tx, run.ID, tagNames); err != nil {
+			return err
+		}
+
+		if err := refreshShardGroup(tx, run, false); err != nil {
+			return err
+		}
+
+		testRun.ID = run.ID
+		testRun.Status = run.Status
+		testRun.StartTime = run.StartTime
+		testRun.EndTime = run.EndTime
+		testRun.Duration = time.Duration(run.Duration) * time.Millisecond
+		testRun.TotalTests = run.TotalTests
+		testRun.PassedTests = run.PassedTests
+		testRun.FailedTests = run.FailedTests
+		testRun.SkippedTests = run.SkippedTests
+		testRun.QuarantinedTests = run.QuarantinedTests
+		testRun.Metadata = run.Metadata
+		testRun.ShardTotal = run.ShardTotal
+		testRun.ShardsReceived = run.ShardsReceived
+		return nil
+	})
+}
+
+// FinalizeShardGroups closes timed out shard groups, skipping groups that are being merged into
+func (r *GormTestRunRepository) FinalizeShardGroups(ctx context.Context, createdBefore time.Time) (int, error) {
+	finalized := 0
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var runs []database.TestRun
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("shard_total > 0 AND status = ? AND created_at <= ?", "running", createdBefore).
+			Find(&runs).Error
+		if err != nil {
+			return err
+		}
+
+		for i := range runs {
+			if err := refreshShardGroup(tx, &runs[i], true); err != nil {
+				return err
+			}
+			finalized++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to finalize shard groups: %w", err)
+	}
+
+	return finalized, nil
+}
+
+// lockShardGroup gets or creates the run for a shard group and locks it
+func lockShardGroup(tx *gorm.DB, testRun *domain.TestRun, shard domain.ShardInfo) (*database.TestRun, error) {
+	group := &database.TestRun{
+		ProjectID:   testRun.ProjectID,
+		RunID:       shard.GroupID,
+		Branch:      testRun.Branch,
+		CommitSHA:   testRun.GitCommit,
+		Status:      "running",
+		StartTime:   testRun.StartTime,
+		Environment: testRun.Environment,
+		Metadata:    database.JSONMap(testRun.Metadata),
+		ShardTotal:  shard.Total,
+	}
+	err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "run_id"}}, DoNothing: true}).Create(group).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to create shard group: %w", err)
+	}
+
+	var run database.TestRun
+	err = tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Where("run_id = ?", shard.GroupID).First(&run).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up shard group: %w", err)
+	}
+	if run.ProjectID != testRun.ProjectID {
+		return nil, domain.ErrRunIDConflict
+	}
+
+	// A deleted run is started over as a new group
+	if run.DeletedAt.Valid {
+		if err := deleteShardSuites(tx, run.ID, -1); err != nil {
+			return nil, err
+		}
+		if err := tx.Where("test_run_id = ?", run.ID).Delete(&database.TestRunShard{}).Error; err != nil {
+			return nil, fmt.Errorf("failed to reset shard group: %w", err)
+		}
+		err := tx.Unscoped().Model(&database.TestRun{}).Where("id = ?", run.ID).Updates(map[string]interface{}{
+			"branch":      group.Branch,
+			"commit_sha":  group.CommitSHA,
+			"status":      group.Status,
+			"start_time":  group.StartTime,
+			"environment": group.Environment,
+			"metadata":    group.Metadata,
+			"shard_total": group.ShardTotal,
+			"created_at":  time.Now(),
+			"deleted_at":  nil,
+		}).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to reset shard group: %w", err)
+		}
+		run.Status = group.Status
+		run.Metadata = group.Metadata
+		run.ShardTotal = group.ShardTotal
+	}
+
+	if run.ShardTotal != shard.Total {
+		return nil, domain.ErrShardMismatch
+	}
+
+	return &run, nil
+}
+
+// deleteShardSuites hard-deletes the suites and specs a shard reported for a run.
+// A negative shard index deletes the suites of every shard.
+func deleteShardSuites(tx *gorm.DB, testRunID uint, shardIndex int) error {
+	suites := tx.Unscoped().Model(&database.SuiteRun{}).Select("id").Where("test_run_id = ?", testRunID)
+	if shardIndex >= 0 {
+		suites = suites.Where("shard_index = ?", shardIndex)
+	}
+	specs := tx.Unscoped().Model(&database.SpecRun{}).Select("id").Where("suite_run_id IN (?)", suites)
+	if err := tx.Where("suite_run_id IN (?)", suites).Delete(&database.Attachment{}).Error; err != nil {
+		return fmt.Errorf("failed to replace shard attachments: %w", err)
+	}
+	if err := tx.Where("spec_run_id IN (?)", specs).Delete(&database.SpecStep{}).Error; err != nil {
+		return fmt.Errorf("failed to replace shard spec steps: %w", err)
+	}
+	if err := tx.Where("spec_run_id IN (?)", specs).Delete(&database.SpecOutput{}).Error; err != nil {
+		return fmt.Errorf("failed to replace shard spec output: %w", err)
+	}
+	if err := tx.Where("spec_run_id IN (?)", specs).Delete(&database.SpecAttempt{}).Error; err != nil {
+		return fmt.Errorf("failed to replace shard spec attempts: %w", err)
+	}
+	if err := tx.Unscoped().Where("suite_run_id IN (?)", suites).Delete(&database.SpecRun{}).Error; err != nil {
+		return fmt.Errorf("failed to replace shard spec runs: %w", err)
+	}
+
+	deleteSuites := tx.Unscoped().Where("test_run_id = ?", testRunID)
+	if shardIndex >= 0 {
+		deleteSuites = deleteSuites.Where("shard_index = ?", shardIndex)
+	}
+	if err := deleteSuites.Delete(&database.SuiteRun{}).Error; err != nil {
+		return fmt.Errorf("failed to replace shard suite runs: %w", err)
+	}
+	return nil
+}
+
+// refreshShardGroup recomputes a group's counters, times and status from its shards.
+// timedOut closes a group that is still waiting for shards.
+func refreshShardGroup(tx *gorm.DB, run *database.TestRun, timedOut bool) error {
+	var shards []database.TestRunShard
+	if err := tx.Where("test_run_id = ?", run.ID).Order("shard_index").Find(&shards).Error; err != nil {
+		return fmt.Errorf("failed to load shards: %w", err)
+	}
+
+	run.TotalTests, run.PassedTests, run.FailedTests, run.SkippedTests = 0, 0, 0, 0
+	run.EndTime = nil
+	received := make([]int, len(shards))
+	for i, shard := range shards {
+		received[i] = shard.ShardIndex
+		run.TotalTests += shard.TotalTests
+		run.PassedTests += shard.PassedTests
+		run.FailedTests += shard.FailedTests
+		run.SkippedTests += shard.SkippedTests
+		if i == 0 || shard.StartTime.Before(run.StartTime) {
+			run.StartTime = shard.StartTime
+		}
+		if shard.EndTime != nil && (run.EndTime == nil || shard.EndTime.After(*run.EndTime)) {
+			endTime := *shard.EndTime
+			run.EndTime = &endTime
+		}
+	}
+	run.ShardsReceived = len(shards)
+
+	// Quarantined failures are counted per suite as their specs are stored
+	err := tx.Model(&database.SuiteRun{}).Where("test_run_id = ?", run.ID).
+		Select("COALESCE(SUM(quarantined_specs), 0)").Scan(&run.QuarantinedTests).Error
+	if err != nil {
+		return fmt.Errorf("failed to count quarantined tests: %w", err)
+	}
+
+	// Shards arriving after the group timed out don't reopen it
+	timedOut = timedOut || run.Status != "running"
+	run.Status = domain.ShardedRunStatus(run.ShardTotal, run.ShardsReceived, run.FailedTests, timedOut)
+
+	if run.Metadata == nil {
+		run.Metadata = database.JSONMap{}
+	}
+	delete(run.Metadata, "missing_shards")
+	if missing := domain.MissingShards(run.ShardTotal, received); run.Status != "running" && len(missing) > 0 {
+		run.Metadata["missing_shards"] = missing
+	}
+
+	run.Duration = 0
+	if run.EndTime != nil {
+		run.Duration = int64(run.EndTime.Sub(run.StartTime) / time.Millisecond)
+	}
+
+	err = tx.Model(&database.TestRun{}).Where("id = ?", run.ID).Updates(map[string]interface{}{
+		"status":            run.Status,
+		"start_time":        run.StartTime,
+		"end_time":          run.EndTime,
+		"duration_ms":       run.Duration,
+		"total_tests":       run.TotalTests,
+		"passed_tests":      run.PassedTests,
+		"failed_tests":      run.FailedTests,
+		"skipped_tests":     run.SkippedTests,
+		"quarantined_tests": run.QuarantinedTests,
+		"shards_received":   run.ShardsReceived,
+		"metadata":          run.Metadata,
+		"updated_at":        time.Now(),
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update shard group: %w", err)
+	}
+	return nil
+}
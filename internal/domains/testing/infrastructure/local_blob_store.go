@@ -0,0 +1,107 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// LocalBlobStore implements domain.BlobStore on the local filesystem
+type LocalBlobStore struct {
+	root string
+}
+
+// NewLocalBlobStore creates a blob store keeping files under root, creating the directory if needed
+func NewLocalBlobStore(root string) (*LocalBlobStore, error) {
+	if root == "" {
+		return nil, fmt.Errorf("local blob store path is required")
+	}
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create blob store directory: %w", err)
+	}
+	return &LocalBlobStore{root: root}, nil
+}
+
+// Name identifies the backend
+func (s *LocalBlobStore) Name() string {
+	return "local"
+}
+
+// Put writes the content to a temporary file and moves it into place, so readers never
+// see a partially written blob
+func (s *LocalBlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create blob: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+	if written != size {
+		return fmt.Errorf("failed to write blob: expected %d bytes, got %d", size, written)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store blob: %w", err)
+	}
+	return nil
+}
+
+// Get opens the file stored under key
+func (s *LocalBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, domain.ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blob: %w", err)
+	}
+	return file, nil
+}
+
+// Delete removes the file stored under key
+func (s *LocalBlobStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	return nil
+}
+
+// path maps a slash-separated key to a file under the root, rejecting keys that escape it
+func (s *LocalBlobStore) path(key string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(key))
+	if key == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.root, clean), nil
+}
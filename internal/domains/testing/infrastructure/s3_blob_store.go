@@ -0,0 +1,249 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
+)
+
+// s3UnsignedPayload tells S3 the request body is not covered by the signature, so uploads
+// can be streamed without hashing them first
+const s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3BlobStoreConfig configures a blob store backed by Amazon S3 or an S3-compatible
+// service such as MinIO
+type S3BlobStoreConfig struct {
+	Endpoint        string // e.g. "https://s3.eu-west-1.amazonaws.com" or "http://minio:9000"
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	Prefix          string // Prepended to every key
+	PathStyle       bool   // Address the bucket in the path rather than as a subdomain
+}
+
+// S3BlobStore implements domain.BlobStore with requests signed using AWS Signature Version 4
+type S3BlobStore struct {
+	config   S3BlobStoreConfig
+	endpoint *url.URL
+	client   *http.Client
+}
+
+// NewS3BlobStore creates an S3 blob store
+func NewS3BlobStore(config S3BlobStoreConfig, client *http.Client) (*S3BlobStore, error) {
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("s3 bucket is required")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", config.Region)
+	}
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", config.Endpoint)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &S3BlobStore{config: config, endpoint: endpoint, client: client}, nil
+}
+
+// Name identifies the backend
+func (s *S3BlobStore) Name() string {
+	return "s3"
+}
+
+// Put uploads the content as a single object
+func (s *S3BlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if size == 0 {
+		req.Body = http.NoBody
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload blob: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get downloads an object
+func (s *S3BlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download blob: %w", err)
+	}
+	return resp.Body, nil
+}
+
+// Delete removes an object; S3 treats deleting a missing object as success
+func (s *S3BlobStore) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// newRequest builds a request for the object stored under key
+func (s *S3BlobStore) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	if key == "" {
+		return nil, fmt.Errorf("invalid blob key %q", key)
+	}
+	objectPath := "/" + strings.TrimPrefix(s.config.Prefix+key, "/")
+
+	target := *s.endpoint
+	if s.config.PathStyle {
+		objectPath = "/" + s.config.Bucket + objectPath
+	} else {
+		target.Host = s.config.Bucket + "." + target.Host
+	}
+	target.Path = strings.TrimSuffix(target.Path, "/") + objectPath
+	target.RawPath = s3EscapePath(target.Path)
+
+	return http.NewRequestWithContext(ctx, method, target.String(), body)
+}
+
+// do signs and sends a request, turning error responses into errors
+func (s *S3BlobStore) do(req *http.Request) (*http.Response, error) {
+	signS3Request(req, s.config.AccessKeyID, s.config.SecretAccessKey, s.config.Region, time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound && req.Method == http.MethodGet {
+		resp.Body.Close()
+		return nil, domain.ErrBlobNotFound
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("s3 returned %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	return resp, nil
+}
+
+// signS3Request adds AWS Signature Version 4 headers to a request. The host, the
+// x-amz-* headers and the content type are signed; the payload is not.
+func signS3Request(req *http.Request, accessKeyID, secretAccessKey, region string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedPayload)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") || lower == "content-type" {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		s3CanonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		s3UnsignedPayload,
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKeyID, scope, signedHeaders, signature))
+}
+
+// s3CanonicalQuery sorts and escapes query parameters for signing
+func s3CanonicalQuery(query url.Values) string {
+	pairs := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, s3Escape(name, true)+"="+s3Escape(value, true))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// s3EscapePath escapes every path segment the way S3 expects in the canonical request
+func s3EscapePath(path string) string {
+	return s3Escape(path, false)
+}
+
+// s3Escape percent-encodes everything but unreserved characters and, unless escapeSlash is set, slashes
+func s3Escape(s string, escapeSlash bool) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !escapeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
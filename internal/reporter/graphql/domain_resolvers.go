@@ -2,16 +2,21 @@ package graphql
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	analyticsDomain "github.com/guidewire-oss/fern-platform/internal/domains/analytics/domain"
 	authDomain "github.com/guidewire-oss/fern-platform/internal/domains/auth/domain"
 	projectsApp "github.com/guidewire-oss/fern-platform/internal/domains/projects/application"
 	projectsDomain "github.com/guidewire-oss/fern-platform/internal/domains/projects/domain"
 	tagsDomain "github.com/guidewire-oss/fern-platform/internal/domains/tags/domain"
+	testingApp "github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
 	testingDomain "github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
 	"github.com/guidewire-oss/fern-platform/internal/reporter/graphql/model"
 )
@@ -62,6 +67,8 @@ func (r *Resolver) convertTestRunToGraphQL(testRun *testingDomain.TestRun) *mode
 		SuiteRuns:    suiteRuns,
 		CreatedAt:    testRun.StartTime, // Use StartTime as CreatedAt
 		UpdatedAt:    testRun.StartTime, // Use StartTime as UpdatedAt
+
+		QuarantinedTests: testRun.QuarantinedTests,
 	}
 }
 
@@ -106,6 +113,7 @@ func (r *Resolver) convertSpecRunToGraphQL(spec *testingDomain.SpecRun) *model.S
 	return &model.SpecRun{
 		ID:           strconv.FormatUint(uint64(spec.ID), 10),
 		SuiteRunID:   strconv.FormatUint(uint64(spec.SuiteRunID), 10),
+		TestCaseID:   convertIDPtr(spec.TestCaseID),
 		SpecName:     spec.Name,
 		Status:       spec.Status,
 		StartTime:    spec.StartTime,
@@ -115,13 +123,96 @@ func (r *Resolver) convertSpecRunToGraphQL(spec *testingDomain.SpecRun) *model.S
 		StackTrace:   stackTrace,
 		RetryCount:   spec.RetryCount,
 		IsFlaky:      spec.IsFlaky,
+		Quarantined:  spec.Quarantined,
 		CreatedAt:    spec.StartTime,
 		UpdatedAt:    spec.StartTime,
 	}
 }
 
+// convertTestCaseToGraphQL converts a domain test case to GraphQL model
+func convertTestCaseToGraphQL(testCase *testingDomain.TestCase) *model.TestCase {
+	return &model.TestCase{
+		ID:          strconv.FormatUint(uint64(testCase.ID), 10),
+		ProjectID:   testCase.ProjectID,
+		ParentID:    convertIDPtr(testCase.ParentID),
+		SuiteName:   testCase.SuiteName,
+		PackageName: testCase.PackageName,
+		ClassName:   testCase.ClassName,
+		Name:        testCase.Name,
+		Parameters:  convertParametersToGraphQL(testCase.Parameters),
+		Fingerprint: testCase.Fingerprint,
+		FirstSeenAt: testCase.FirstSeenAt,
+		LastSeenAt:  testCase.LastSeenAt,
+	}
+}
+
+// convertTestCasesToGraphQL converts domain test cases to GraphQL models
+func convertTestCasesToGraphQL(testCases []*testingDomain.TestCase) []*model.TestCase {
+	result := make([]*model.TestCase, len(testCases))
+	for i, testCase := range testCases {
+		result[i] = convertTestCaseToGraphQL(testCase)
+	}
+	return result
+}
+
+// convertParametersToGraphQL converts a parameter set to a JSON object, or nil when there is none
+func convertParametersToGraphQL(parameters map[string]string) map[string]any {
+	if len(parameters) == 0 {
+		return nil
+	}
+	result := make(map[string]any, len(parameters))
+	for name, value := range parameters {
+		result[name] = value
+	}
+	return result
+}
+
+// convertParametersFromGraphQL converts a JSON object of parameters to a parameter set
+func convertParametersFromGraphQL(parameters map[string]any) map[string]string {
+	if len(parameters) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(parameters))
+	for name, value := range parameters {
+		if s, ok := value.(string); ok {
+			result[name] = s
+		} else {
+			result[name] = fmt.Sprint(value)
+		}
+	}
+	return result
+}
+
+// convertTestStatsToGraphQL converts test case statistics to GraphQL model
+func convertTestStatsToGraphQL(stats testingDomain.TestCaseStats) *model.TestStats {
+	return &model.TestStats{
+		Key:             convertStringPtr(stats.Key),
+		Total:           stats.Total,
+		Passed:          stats.Passed,
+		Failed:          stats.Failed,
+		Skipped:         stats.Skipped,
+		Flaky:           stats.Flaky,
+		Quarantined:     stats.Quarantined,
+		PassRate:        stats.PassRate,
+		AverageDuration: int(stats.AverageDuration.Milliseconds()),
+	}
+}
+
+// convertTestStatsListToGraphQL converts per-branch or per-environment statistics to GraphQL models
+func convertTestStatsListToGraphQL(statsList []testingDomain.TestCaseStats) []*model.TestStats {
+	result := make([]*model.TestStats, len(statsList))
+	for i, stats := range statsList {
+		result[i] = convertTestStatsToGraphQL(stats)
+	}
+	return result
+}
+
 // RecentTestRuns_domain retrieves recent test runs using domain service
 func (r *queryResolver) RecentTestRuns_domain(ctx context.Context, projectID *string, limit *int) ([]*model.TestRun, error) {
+	if err := checkProjectRead(ctx, projectOrAll(projectID)); err != nil {
+		return nil, err
+	}
+
 	limitVal := 10
 	if limit != nil {
 		limitVal = *limit
@@ -202,6 +293,9 @@ func (r *queryResolver) GetTestRun_domain(ctx context.Context, id string) (*mode
 		if err != nil {
 			return nil, err
 		}
+		if err := checkProjectRead(ctx, testRun.ProjectID); err != nil {
+			return nil, err
+		}
 		return r.convertTestRunToGraphQL(testRun), nil
 	}
 
@@ -343,6 +437,139 @@ func (r *mutationResolver) CreateProject_domain(ctx context.Context, input model
 	return r.convertProjectToGraphQL(project), nil
 }
 
+// IngestTestRun implementation using domain service
+func (r *mutationResolver) IngestTestRun_domain(ctx context.Context, input model.IngestTestRunInput) (*model.TestRun, error) {
+	if _, err := getCurrentUser(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+	if err := checkProjectWrite(ctx, input.ProjectID); err != nil {
+		return nil, err
+	}
+
+	if _, err := r.projectService.GetProject(ctx, projectsDomain.ProjectID(input.ProjectID)); err != nil {
+		return nil, fmt.Errorf("project not found: %s", input.ProjectID)
+	}
+
+	key := getStringValue(input.IdempotencyKey)
+	if key != "" {
+		payload := input
+		payload.IdempotencyKey = nil
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash input: %w", err)
+		}
+
+		record, err := r.idempotencyService.Begin(ctx, input.ProjectID, key, testingApp.HashRequest([]byte("ingestTestRun"), body))
+		if err != nil {
+			return nil, err
+		}
+		if record != nil {
+			testRun, err := r.testingService.GetTestRunWithDetails(ctx, record.TestRunID)
+			if err != nil {
+				return nil, err
+			}
+			return r.convertTestRunToGraphQL(testRun), nil
+		}
+	}
+
+	testRun := convertIngestTestRunInput(input)
+	if err := r.testingService.IngestTestRun(ctx, testRun, input.Tags); err != nil {
+		if key != "" {
+			if releaseErr := r.idempotencyService.Release(ctx, input.ProjectID, key); releaseErr != nil {
+				r.logger.WithError(releaseErr).Error("Failed to release idempotency key")
+			}
+		}
+		return nil, err
+	}
+
+	if key != "" {
+		if err := r.idempotencyService.Complete(ctx, input.ProjectID, key, testRun.ID, http.StatusOK, nil); err != nil {
+			r.logger.WithError(err).Error("Failed to complete idempotency key")
+		}
+	}
+
+	return r.convertTestRunToGraphQL(testRun), nil
+}
+
+// convertIngestTestRunInput builds a domain test run, defaulting missing times from the run start
+func convertIngestTestRunInput(input model.IngestTestRunInput) *testingDomain.TestRun {
+	testRun := &testingDomain.TestRun{
+		ProjectID:   input.ProjectID,
+		RunID:       getStringValue(input.RunID),
+		Branch:      getStringValue(input.Branch),
+		GitBranch:   getStringValue(input.Branch),
+		GitCommit:   getStringValue(input.CommitSha),
+		Environment: getStringValue(input.Environment),
+		Status:      getStringValue(input.Status),
+		StartTime:   input.StartTime,
+		EndTime:     input.EndTime,
+		Metadata:    input.Metadata,
+		SuiteRuns:   make([]testingDomain.SuiteRun, len(input.Suites)),
+	}
+
+	for i, suiteInput := range input.Suites {
+		suite := testingDomain.SuiteRun{
+			Name:      suiteInput.SuiteName,
+			Status:    getStringValue(suiteInput.Status),
+			StartTime: input.StartTime,
+			EndTime:   suiteInput.EndTime,
+			Duration:  convertMillisPtr(suiteInput.Duration),
+			SpecRuns:  make([]*testingDomain.SpecRun, len(suiteInput.Specs)),
+		}
+		if suiteInput.StartTime != nil {
+			suite.StartTime = *suiteInput.StartTime
+		}
+
+		for j, specInput := range suiteInput.Specs {
+			spec := &testingDomain.SpecRun{
+				Name:         specInput.SpecName,
+				Status:       specInput.Status,
+				StartTime:    suite.StartTime,
+				EndTime:      specInput.EndTime,
+				Duration:     convertMillisPtr(specInput.Duration),
+				ErrorMessage: getStringValue(specInput.ErrorMessage),
+				StackTrace:   getStringValue(specInput.StackTrace),
+				ParentName:   getStringValue(specInput.ParentName),
+				Parameters:   convertParametersFromGraphQL(specInput.Parameters),
+			}
+			if specInput.StartTime != nil {
+				spec.StartTime = *specInput.StartTime
+			}
+			if specInput.RetryCount != nil {
+				spec.RetryCount = *specInput.RetryCount
+			}
+			for _, attemptInput := range specInput.Attempts {
+				attempt := testingDomain.SpecAttempt{
+					Status:       attemptInput.Status,
+					Duration:     convertMillisPtr(attemptInput.Duration),
+					ErrorMessage: getStringValue(attemptInput.ErrorMessage),
+					StackTrace:   getStringValue(attemptInput.StackTrace),
+				}
+				if attemptInput.StartTime != nil {
+					attempt.StartTime = *attemptInput.StartTime
+				}
+				spec.Attempts = append(spec.Attempts, attempt)
+			}
+			if spec.Duration == 0 && spec.EndTime != nil {
+				spec.Duration = spec.EndTime.Sub(spec.StartTime)
+			}
+			suite.SpecRuns[j] = spec
+		}
+
+		testRun.SuiteRuns[i] = suite
+	}
+
+	return testRun
+}
+
+// convertMillisPtr converts an optional millisecond count to a duration
+func convertMillisPtr(ms *int) time.Duration {
+	if ms == nil {
+		return 0
+	}
+	return time.Duration(*ms) * time.Millisecond
+}
+
 // CreateTag implementation using domain service
 func (r *mutationResolver) CreateTag_domain(ctx context.Context, input model.CreateTagInput) (*model.Tag, error) {
 	// CreateTag only takes a name parameter
@@ -662,6 +889,9 @@ func (r *queryResolver) Project_domain(ctx context.Context, id string) (*model.P
 
 	for _, p := range projects {
 		if p.ID() == uint(idUint) {
+			if err := checkProjectRead(ctx, string(p.ProjectID())); err != nil {
+				return nil, err
+			}
 			return r.convertProjectToGraphQL(p), nil
 		}
 	}
@@ -675,6 +905,9 @@ func (r *queryResolver) ProjectByProjectID_domain(ctx context.Context, projectID
 	if err != nil {
 		return nil, fmt.Errorf("failed to get project: %w", err)
 	}
+	if err := checkProjectRead(ctx, string(project.ProjectID())); err != nil {
+		return nil, err
+	}
 
 	return r.convertProjectToGraphQL(project), nil
 }
@@ -817,18 +1050,20 @@ func (r *queryResolver) DashboardSummary_domain(ctx context.Context) (*model.Das
 	avgDuration := 0
 
 	if len(recentRuns) > 0 {
-		var totalTests, passedTests int
+		var totalTests, passedTests, quarantinedTests int
 		var totalDuration int64
 
 		for _, tr := range recentRuns {
 			totalTests += tr.TotalTests
 			passedTests += tr.PassedTests
+			quarantinedTests += tr.QuarantinedTests
 			totalTestsExecuted += tr.TotalTests
 			totalDuration += tr.Duration.Milliseconds()
 		}
 
-		if totalTests > 0 {
-			overallPassRate = float64(passedTests) / float64(totalTests) * 100
+		// Failures of quarantined tests don't count against the pass rate
+		if executed := totalTests - quarantinedTests; executed > 0 {
+			overallPassRate = float64(passedTests) / float64(executed) * 100
 		}
 
 		if len(recentRuns) > 0 {
@@ -856,6 +1091,10 @@ func (r *queryResolver) DashboardSummary_domain(ctx context.Context) (*model.Das
 
 // TreemapData implementation using domain service
 func (r *queryResolver) TreemapData_domain(ctx context.Context, projectID *string, days *int) (*model.TreemapData, error) {
+	if err := checkProjectRead(ctx, projectOrAll(projectID)); err != nil {
+		return nil, err
+	}
+
 	// Default to 7 days if not specified
 	daysToQuery := 7
 	if days != nil && *days > 0 {
@@ -915,6 +1154,7 @@ func (r *queryResolver) TreemapData_domain(ctx context.Context, projectID *strin
 	totalDuration := 0
 	totalTests := 0
 	totalPassed := 0
+	totalQuarantined := 0
 
 	for projectID, runs := range projectRuns {
 		project, ok := projectMap[projectID]
@@ -930,6 +1170,8 @@ func (r *queryResolver) TreemapData_domain(ctx context.Context, projectID *strin
 		projectDuration := 0
 		projectTests := 0
 		projectPassed := 0
+		projectQuarantined := 0
+		suiteQuarantined := make(map[string]int)
 
 		for _, run := range runs {
 			// Get test run with details including suite runs
@@ -941,6 +1183,7 @@ func (r *queryResolver) TreemapData_domain(ctx context.Context, projectID *strin
 
 			for _, suite := range testRunWithDetails.SuiteRuns {
 				key := suite.Name
+				suiteQuarantined[key] += suite.QuarantinedTests
 
 				if node, exists := suiteMap[key]; exists {
 					// Update existing suite node
@@ -979,21 +1222,22 @@ func (r *queryResolver) TreemapData_domain(ctx context.Context, projectID *strin
 			projectDuration += int(run.Duration.Milliseconds())
 			projectTests += run.TotalTests
 			projectPassed += run.PassedTests
+			projectQuarantined += run.QuarantinedTests
 		}
 
-		// Convert suite map to slice and calculate pass rates
+		// Convert suite map to slice and calculate pass rates, leaving out quarantined failures
 		var suiteNodes []*model.SuiteTreemapNode
-		for _, node := range suiteMap {
-			if node.TotalSpecs > 0 {
-				node.PassRate = float64(node.PassedSpecs) / float64(node.TotalSpecs)
+		for key, node := range suiteMap {
+			if executed := node.TotalSpecs - suiteQuarantined[key]; executed > 0 {
+				node.PassRate = float64(node.PassedSpecs) / float64(executed)
 			}
 			suiteNodes = append(suiteNodes, node)
 		}
 
 		// Calculate project pass rate
 		projectPassRate := float64(0)
-		if projectTests > 0 {
-			projectPassRate = float64(projectPassed) / float64(projectTests)
+		if executed := projectTests - projectQuarantined; executed > 0 {
+			projectPassRate = float64(projectPassed) / float64(executed)
 		}
 
 		projectNode := &model.ProjectTreemapNode{
@@ -1011,12 +1255,13 @@ func (r *queryResolver) TreemapData_domain(ctx context.Context, projectID *strin
 		totalDuration += projectDuration
 		totalTests += projectTests
 		totalPassed += projectPassed
+		totalQuarantined += projectQuarantined
 	}
 
 	// Calculate overall pass rate
 	overallPassRate := float64(0)
-	if totalTests > 0 {
-		overallPassRate = float64(totalPassed) / float64(totalTests)
+	if executed := totalTests - totalQuarantined; executed > 0 {
+		overallPassRate = float64(totalPassed) / float64(executed)
 	}
 
 	return &model.TreemapData{
@@ -1048,6 +1293,9 @@ func (r *queryResolver) TestRuns_domain(ctx context.Context, filter *model.TestR
 	if filter != nil && filter.ProjectID != nil {
 		projectID = *filter.ProjectID
 	}
+	if err := checkProjectRead(ctx, projectOrAll(&projectID)); err != nil {
+		return nil, err
+	}
 
 	// Get test runs with pagination
 	testRuns, totalCount, err := r.testingService.ListTestRuns(ctx, projectID, pageSize, offset)
@@ -1156,3 +1404,157 @@ func (r *queryResolver) Tags_domain(ctx context.Context, filter *model.TagFilter
 		TotalCount: len(filteredTags),
 	}, nil
 }
+
+// FlakyTest implementation using domain service
+func (r *queryResolver) FlakyTest_domain(ctx context.Context, id string) (*model.FlakyTest, error) {
+	flakyTest, err := r.flakyDetectionService.GetFlakyTest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkProjectRead(ctx, flakyTest.ProjectID); err != nil {
+		return nil, err
+	}
+
+	return convertFlakyTestToGraphQL(flakyTest), nil
+}
+
+// FlakyTests implementation using domain service with pagination
+func (r *queryResolver) FlakyTests_domain(ctx context.Context, filter *model.FlakyTestFilter, first *int, after *string, orderBy *string, orderDirection *model.OrderDirection) (*model.FlakyTestConnection, error) {
+	if filter == nil || filter.ProjectID == nil || *filter.ProjectID == "" {
+		return nil, fmt.Errorf("filter.projectId is required")
+	}
+	if err := checkProjectRead(ctx, *filter.ProjectID); err != nil {
+		return nil, err
+	}
+
+	status := analyticsDomain.StatusActive
+	if filter.Status != nil && *filter.Status != "" {
+		status = analyticsDomain.FlakyTestStatus(*filter.Status)
+	}
+
+	flakyTests, err := r.flakyDetectionService.ListFlakyTests(ctx, *filter.ProjectID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply the remaining filters
+	filtered := make([]*analyticsDomain.FlakyTest, 0, len(flakyTests))
+	for _, flakyTest := range flakyTests {
+		if filter.Severity != nil && *filter.Severity != "" && flakyTest.Severity() != *filter.Severity {
+			continue
+		}
+		if filter.MinFlakeRate != nil && flakyTest.FlakeScore < *filter.MinFlakeRate {
+			continue
+		}
+		if filter.MaxFlakeRate != nil && flakyTest.FlakeScore > *filter.MaxFlakeRate {
+			continue
+		}
+		filtered = append(filtered, flakyTest)
+	}
+
+	// Apply ordering, most flaky first by default
+	less := func(a, b *analyticsDomain.FlakyTest) bool { return a.FlakeScore < b.FlakeScore }
+	if orderBy != nil {
+		switch *orderBy {
+		case "flakeRate", "":
+		case "lastSeenAt":
+			less = func(a, b *analyticsDomain.FlakyTest) bool { return a.LastSeen.Before(b.LastSeen) }
+		case "totalExecutions":
+			less = func(a, b *analyticsDomain.FlakyTest) bool { return a.TotalRuns < b.TotalRuns }
+		default:
+			return nil, fmt.Errorf("cannot order flaky tests by %s", *orderBy)
+		}
+	}
+	descending := orderDirection == nil || *orderDirection == model.OrderDirectionDesc
+	sort.SliceStable(filtered, func(i, j int) bool {
+		if descending {
+			return less(filtered[j], filtered[i])
+		}
+		return less(filtered[i], filtered[j])
+	})
+
+	// Apply pagination
+	pageSize := 20
+	if first != nil && *first > 0 && *first <= 100 {
+		pageSize = *first
+	}
+
+	offset := 0
+	if after != nil && *after != "" {
+		// Simple cursor: just the index
+		if idx, err := strconv.Atoi(*after); err == nil && idx >= 0 {
+			offset = idx + 1
+		}
+	}
+
+	start := offset
+	end := offset + pageSize
+	if start > len(filtered) {
+		start = len(filtered)
+	}
+	if end > len(filtered) {
+		end = len(filtered)
+	}
+
+	edges := make([]*model.FlakyTestEdge, end-start)
+	for i, flakyTest := range filtered[start:end] {
+		edges[i] = &model.FlakyTestEdge{
+			Node:   convertFlakyTestToGraphQL(flakyTest),
+			Cursor: fmt.Sprintf("%d", start+i),
+		}
+	}
+
+	pageInfo := &model.PageInfo{
+		HasNextPage:     end < len(filtered),
+		HasPreviousPage: offset > 0,
+	}
+	if len(edges) > 0 {
+		pageInfo.StartCursor = &edges[0].Cursor
+		pageInfo.EndCursor = &edges[len(edges)-1].Cursor
+	}
+
+	return &model.FlakyTestConnection{
+		Edges:      edges,
+		PageInfo:   pageInfo,
+		TotalCount: len(filtered),
+	}, nil
+}
+
+// convertFlakyTestToGraphQL converts a domain flaky test to GraphQL model
+func convertFlakyTestToGraphQL(flakyTest *analyticsDomain.FlakyTest) *model.FlakyTest {
+	flips := make([]*model.FlipEvidence, len(flakyTest.Metadata.Flips))
+	for i, flip := range flakyTest.Metadata.Flips {
+		flips[i] = &model.FlipEvidence{
+			Commit:       convertStringPtr(flip.Commit),
+			PassedRunIds: nonNilStrings(flip.PassedRunIDs),
+			FailedRunIds: nonNilStrings(flip.FailedRunIDs),
+			InRun:        flip.InRun,
+		}
+	}
+
+	return &model.FlakyTest{
+		ID:               flakyTest.TestID,
+		ProjectID:        flakyTest.ProjectID,
+		TestName:         flakyTest.TestName,
+		SuiteName:        convertStringPtr(flakyTest.SuiteName),
+		FlakeRate:        flakyTest.FlakeScore,
+		TotalExecutions:  flakyTest.TotalRuns,
+		FlakyExecutions:  flakyTest.FailureCount,
+		LastSeenAt:       flakyTest.LastSeen,
+		FirstSeenAt:      flakyTest.FirstSeen,
+		Status:           string(flakyTest.Status),
+		Severity:         flakyTest.Severity(),
+		LastErrorMessage: convertStringPtr(flakyTest.LastErrorMessage()),
+		Flips:            flips,
+		CreatedAt:        flakyTest.FirstSeen, // Tracked from when the test was first found flaky
+		UpdatedAt:        flakyTest.LastSeen,
+	}
+}
+
+// nonNilStrings returns an empty slice for nil, as non-null GraphQL lists cannot be null
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,263 @@
+package graphql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+
+	analyticsApp "github.com/guidewire-oss/fern-platform/internal/domains/analytics/application"
+	analyticsDomain "github.com/guidewire-oss/fern-platform/internal/domains/analytics/domain"
+	authDomain "github.com/guidewire-oss/fern-platform/internal/domains/auth/domain"
+	"github.com/guidewire-oss/fern-platform/internal/reporter/graphql"
+	"github.com/guidewire-oss/fern-platform/internal/reporter/graphql/generated"
+	"github.com/guidewire-oss/fern-platform/internal/reporter/graphql/model"
+	"github.com/guidewire-oss/fern-platform/internal/testhelpers"
+	"github.com/guidewire-oss/fern-platform/pkg/config"
+	"github.com/guidewire-oss/fern-platform/pkg/logging"
+)
+
+func TestGraphQL(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GraphQL Suite")
+}
+
+// signedIn returns a context for a request made by a user, with an access token when it has
+// scopes
+func signedIn(scopes ...string) context.Context {
+	user := &authDomain.User{UserID: "user-1", Role: authDomain.RoleUser}
+	ctx := context.Background()
+	for _, scope := range scopes {
+		user.Scopes = append(user.Scopes, authDomain.UserScope{UserID: user.UserID, Scope: scope})
+	}
+	if len(scopes) > 0 {
+		ctx = context.WithValue(ctx, "access_token", &authDomain.AccessToken{})
+	}
+	return context.WithValue(ctx, "user", user)
+}
+
+var _ = Describe("Flaky test resolvers", Label("unit", "graphql", "analytics"), func() {
+	var (
+		mockRepo *testhelpers.MockFlakyDetectionRepository
+		query    generated.QueryResolver
+	)
+
+	BeforeEach(func() {
+		logger, err := logging.NewLogger(&config.LoggingConfig{Level: "error", Format: "json"})
+		Expect(err).NotTo(HaveOccurred())
+
+		mockRepo = new(testhelpers.MockFlakyDetectionRepository)
+		service := analyticsApp.NewFlakyDetectionService(mockRepo, analyticsDomain.DefaultFlakyTestDetectionConfig())
+		query = graphql.NewResolver(nil, nil, nil, nil, nil, service, nil, nil, logger).Query()
+	})
+
+	flakyTest := func(testID string, flakeScore float64, flips ...analyticsDomain.FlipEvidence) *analyticsDomain.FlakyTest {
+		seen := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		return &analyticsDomain.FlakyTest{
+			TestID:     testID,
+			ProjectID:  "proj-1",
+			TestName:   "test " + testID,
+			FirstSeen:  seen,
+			LastSeen:   seen,
+			FlakeScore: flakeScore,
+			Status:     analyticsDomain.StatusActive,
+			Metadata:   analyticsDomain.FlakyTestMetadata{Flips: flips},
+		}
+	}
+
+	projectFilter := &model.FlakyTestFilter{ProjectID: stringPtr("proj-1")}
+
+	Describe("flakyTests", func() {
+		It("should return the flips of each flaky test", func() {
+			mockRepo.On("FindFlakyTestsByProject", mock.Anything, "proj-1", analyticsDomain.StatusActive).Return([]*analyticsDomain.FlakyTest{
+				flakyTest("1", 0.5,
+					analyticsDomain.FlipEvidence{Commit: "abc", PassedRunIDs: []string{"run-2"}, FailedRunIDs: []string{"run-1"}},
+					analyticsDomain.FlipEvidence{PassedRunIDs: []string{"run-3"}, FailedRunIDs: []string{"run-3"}, InRun: true},
+				),
+				flakyTest("2", 0.2),
+			}, nil)
+
+			connection, err := query.FlakyTests(signedIn(), projectFilter, nil, nil, nil, nil)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(connection.TotalCount).To(Equal(2))
+			Expect(connection.Edges[0].Node.Flips).To(Equal([]*model.FlipEvidence{
+				{Commit: stringPtr("abc"), PassedRunIds: []string{"run-2"}, FailedRunIds: []string{"run-1"}},
+				{PassedRunIds: []string{"run-3"}, FailedRunIds: []string{"run-3"}, InRun: true},
+			}))
+			Expect(connection.Edges[1].Node.Flips).To(BeEmpty())
+			Expect(connection.Edges[1].Node.Flips).NotTo(BeNil())
+		})
+
+		It("should filter, order and paginate flaky tests", func() {
+			mockRepo.On("FindFlakyTestsByProject", mock.Anything, "proj-1", analyticsDomain.StatusActive).Return([]*analyticsDomain.FlakyTest{
+				flakyTest("1", 0.9), flakyTest("2", 0.5), flakyTest("3", 0.4), flakyTest("4", 0.05),
+			}, nil)
+			filter := &model.FlakyTestFilter{ProjectID: stringPtr("proj-1"), MinFlakeRate: floatPtr(0.1)}
+			ascending := model.OrderDirectionAsc
+
+			connection, err := query.FlakyTests(signedIn(), filter, intPtr(2), stringPtr("0"), nil, &ascending)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(connection.TotalCount).To(Equal(3))
+			Expect(connection.Edges).To(HaveLen(2))
+			Expect(connection.Edges[0].Node.ID).To(Equal("2"))
+			Expect(connection.Edges[1].Node.ID).To(Equal("1"))
+			Expect(connection.PageInfo.HasPreviousPage).To(BeTrue())
+			Expect(connection.PageInfo.HasNextPage).To(BeFalse())
+		})
+
+		It("should require a project", func() {
+			_, err := query.FlakyTests(signedIn(), nil, nil, nil, nil, nil)
+
+			Expect(err).To(MatchError(ContainSubstring("projectId is required")))
+		})
+
+		It("should refuse access tokens without a scope on the project", func() {
+			_, err := query.FlakyTests(signedIn("project:read:other"), projectFilter, nil, nil, nil, nil)
+
+			Expect(err).To(MatchError("forbidden"))
+			mockRepo.AssertNotCalled(GinkgoT(), "FindFlakyTestsByProject", mock.Anything, mock.Anything, mock.Anything)
+		})
+	})
+
+	Describe("flakyTest", func() {
+		It("should return a flaky test with its flips", func() {
+			mockRepo.On("GetFlakyTest", mock.Anything, "1").Return(flakyTest("1", 0.5,
+				analyticsDomain.FlipEvidence{Commit: "abc", PassedRunIDs: []string{"run-2"}, FailedRunIDs: []string{"run-1"}},
+			), nil)
+
+			result, err := query.FlakyTest(signedIn("project:read:proj-1"), "1")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Severity).To(Equal("high"))
+			Expect(result.Flips).To(HaveLen(1))
+			Expect(*result.Flips[0].Commit).To(Equal("abc"))
+		})
+	})
+
+	Describe("flakyTestTrends", func() {
+		It("should return a count of flaky tests for every day of the period", func() {
+			today := analyticsDomain.TrendDate(time.Now())
+			mockRepo.On("GetFlakyTestTrends", mock.Anything, "proj-1", today.AddDate(0, 0, -1)).Return([]analyticsDomain.FlakyTestTrend{
+				{Date: today, ActiveCount: 2, NewCount: 1},
+			}, nil)
+
+			trends, err := query.FlakyTestTrends(signedIn("project:read:proj-1"), "proj-1", intPtr(2))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(trends).To(Equal([]*model.FlakyTestTrend{
+				{Date: today.AddDate(0, 0, -1)},
+				{Date: today, ActiveCount: 2, NewCount: 1},
+			}))
+		})
+
+		It("should refuse access tokens without a scope on the project", func() {
+			_, err := query.FlakyTestTrends(signedIn("project:read:other"), "proj-1", nil)
+
+			Expect(err).To(MatchError("forbidden"))
+			mockRepo.AssertNotCalled(GinkgoT(), "GetFlakyTestTrends", mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		It("should require a signed in user", func() {
+			_, err := query.FlakyTestTrends(context.Background(), "proj-1", nil)
+
+			Expect(err).To(HaveOccurred())
+			mockRepo.AssertNotCalled(GinkgoT(), "GetFlakyTestTrends", mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		It("should reject more than a year of days", func() {
+			_, err := query.FlakyTestTrends(signedIn(), "proj-1", intPtr(366))
+
+			Expect(err).To(MatchError("days must be between 1 and 365"))
+		})
+	})
+})
+
+var _ = Describe("Project access", Label("unit", "graphql", "auth"), func() {
+	var query generated.QueryResolver
+
+	BeforeEach(func() {
+		logger, err := logging.NewLogger(&config.LoggingConfig{Level: "error", Format: "json"})
+		Expect(err).NotTo(HaveOccurred())
+
+		query = graphql.NewResolver(nil, nil, nil, nil, nil, nil, nil, nil, logger).Query()
+	})
+
+	DescribeTable("refusing access tokens without a scope on the projects queried",
+		func(resolve func(context.Context) error) {
+			Expect(resolve(signedIn("project:read:proj-1"))).To(MatchError("forbidden"))
+		},
+		Entry("test runs of another project", func(ctx context.Context) error {
+			_, err := query.TestRuns(ctx, &model.TestRunFilter{ProjectID: stringPtr("proj-2")}, nil, nil, nil, nil)
+			return err
+		}),
+		Entry("test runs of all projects", func(ctx context.Context) error {
+			_, err := query.TestRuns(ctx, nil, nil, nil, nil, nil)
+			return err
+		}),
+		Entry("recent test runs of all projects", func(ctx context.Context) error {
+			_, err := query.RecentTestRuns(ctx, nil, nil)
+			return err
+		}),
+		Entry("the spec output of another project", func(ctx context.Context) error {
+			_, err := query.SearchSpecOutput(ctx, "proj-2", "timeout", nil, nil, nil)
+			return err
+		}),
+		Entry("the tests of another project", func(ctx context.Context) error {
+			_, err := query.TestCases(ctx, "proj-2", nil, nil)
+			return err
+		}),
+		Entry("the treemap of all projects", func(ctx context.Context) error {
+			_, err := query.TreemapData(ctx, nil, nil)
+			return err
+		}),
+	)
+})
+
+var _ = Describe("Ingesting test runs", Label("unit", "graphql", "testing"), func() {
+	var mutation generated.MutationResolver
+
+	BeforeEach(func() {
+		logger, err := logging.NewLogger(&config.LoggingConfig{Level: "error", Format: "json"})
+		Expect(err).NotTo(HaveOccurred())
+
+		mutation = graphql.NewResolver(nil, nil, nil, nil, nil, nil, nil, nil, logger).Mutation()
+	})
+
+	input := model.IngestTestRunInput{ProjectID: "proj-1"}
+
+	DescribeTable("refusing requests without write access to the project",
+		func(ctx context.Context) {
+			_, err := mutation.IngestTestRun(ctx, input)
+
+			Expect(err).To(MatchError("forbidden"))
+		},
+		Entry("a user without scopes", context.WithValue(context.Background(), "user", &authDomain.User{UserID: "user-1", Role: authDomain.RoleUser})),
+		Entry("a user with a scope on another project", context.WithValue(context.Background(), "user", &authDomain.User{
+			UserID: "user-1",
+			Role:   authDomain.RoleUser,
+			Scopes: []authDomain.UserScope{{UserID: "user-1", Scope: "project:write:proj-2"}},
+		})),
+		Entry("an access token with a read scope", signedIn("project:read:proj-1")),
+		Entry("an access token of an admin without a write scope", context.WithValue(
+			context.WithValue(context.Background(), "access_token", &authDomain.AccessToken{}),
+			"user", &authDomain.User{UserID: "admin-1", Role: authDomain.RoleAdmin},
+		)),
+	)
+})
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
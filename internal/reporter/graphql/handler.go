@@ -3,6 +3,7 @@ package graphql
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -16,8 +17,11 @@ import (
 	"github.com/gorilla/websocket"
 
 	// "github.com/guidewire-oss/fern-platform/internal/reporter/graphql/dataloader"
+	authApp "github.com/guidewire-oss/fern-platform/internal/domains/auth/application"
+	authDomain "github.com/guidewire-oss/fern-platform/internal/domains/auth/domain"
 	authInterfaces "github.com/guidewire-oss/fern-platform/internal/domains/auth/interfaces"
 	"github.com/guidewire-oss/fern-platform/internal/reporter/graphql/generated"
+	"github.com/vektah/gqlparser/v2/ast"
 	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
@@ -104,6 +108,16 @@ func NewHandler(resolver *Resolver, roleGroupNames *RoleGroupNames) *Handler {
 		return graphql.DefaultRecover(ctx, err)
 	})
 
+	// Access tokens are checked for a read scope by the auth middleware, since queries are sent
+	// with POST; mutations additionally need a write scope on all projects
+	srv.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		oc := graphql.GetOperationContext(ctx)
+		if oc.Operation != nil && oc.Operation.Operation == ast.Mutation && !tokenAllowsMutation(ctx) {
+			return graphql.OneShot(graphql.ErrorResponse(ctx, "access token does not have the required scope: project:write:*"))
+		}
+		return next(ctx)
+	})
+
 	// Enable field middleware for timing and logging
 	srv.AroundFields(func(ctx context.Context, next graphql.Resolver) (res interface{}, err error) {
 		start := time.Now()
@@ -147,7 +161,7 @@ func (h *Handler) RegisterRoutes(router *gin.Engine, authMiddleware *authInterfa
 	})
 
 	// GraphQL endpoint with authentication
-	router.POST("/query", authMiddleware.RequireAuth(), h.graphqlHandler())
+	router.POST("/query", authInterfaces.WithScopeAction(authDomain.ScopeActionRead), authMiddleware.RequireAuth(), h.graphqlHandler())
 
 	// WebSocket endpoint for subscriptions
 	router.GET("/query", authMiddleware.RequireAuth(), h.graphqlHandler())
@@ -172,6 +186,9 @@ func (h *Handler) graphqlHandler() gin.HandlerFunc {
 		if user, exists := authInterfaces.GetAuthUser(c); exists {
 			ctx = context.WithValue(ctx, "user", user)
 		}
+		if token, exists := authInterfaces.GetAccessToken(c); exists {
+			ctx = context.WithValue(ctx, "access_token", token)
+		}
 
 		// Update request with new context
 		c.Request = c.Request.WithContext(ctx)
@@ -180,3 +197,51 @@ func (h *Handler) graphqlHandler() gin.HandlerFunc {
 		h.server.ServeHTTP(c.Writer, c.Request)
 	}
 }
+
+// tokenAllowsMutation checks if a request made with an access token may run mutations. Requests
+// authenticated with a session are left to the resolvers' own checks.
+func tokenAllowsMutation(ctx context.Context) bool {
+	if ctx.Value("access_token") == nil {
+		return true
+	}
+	user, ok := ctx.Value("user").(*authDomain.User)
+	return ok && authApp.HasProjectScope(user, "*", authDomain.ScopeActionWrite)
+}
+
+// checkProjectRead checks if a request may read a project. Users signed in with a session may
+// read every project, while access tokens need a read scope on it.
+func checkProjectRead(ctx context.Context, projectID string) error {
+	user, err := getCurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+	if ctx.Value("access_token") != nil && !authApp.HasProjectScope(user, projectID, authDomain.ScopeActionRead) {
+		return fmt.Errorf("forbidden")
+	}
+	return nil
+}
+
+// checkProjectWrite checks if a request may write to a project. Like
+// AuthorizationService.CanAccessProject, admins may write to every project and other users need a
+// write scope on it; access tokens need a write scope whatever the role of their owner.
+func checkProjectWrite(ctx context.Context, projectID string) error {
+	user, err := getCurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+	if ctx.Value("access_token") == nil && user.IsAdmin() {
+		return nil
+	}
+	if !authApp.HasProjectScope(user, projectID, authDomain.ScopeActionWrite) {
+		return fmt.Errorf("forbidden")
+	}
+	return nil
+}
+
+// projectOrAll returns the project a query is limited to, or "*" when it spans all projects
+func projectOrAll(projectID *string) string {
+	if projectID == nil || *projectID == "" {
+		return "*"
+	}
+	return *projectID
+}
@@ -3,6 +3,7 @@ package graphql
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -61,6 +62,15 @@ func convertStringPtr(s string) *string {
 	return &s
 }
 
+// convertIDPtr converts an optional database ID to *string
+func convertIDPtr(id *uint) *string {
+	if id == nil {
+		return nil
+	}
+	s := strconv.FormatUint(uint64(*id), 10)
+	return &s
+}
+
 // paginateSlice applies pagination to a slice
 func paginateSlice[T any](items []T, first int, after string) ([]T, bool) {
 	start := 0
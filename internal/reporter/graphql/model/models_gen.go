@@ -10,6 +10,16 @@ import (
 	"time"
 )
 
+type Attachment struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"contentType"`
+	Size        int       `json:"size"`
+	Sha256      string    `json:"sha256"`
+	URL         string    `json:"url"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
 type CreateJiraConnectionInput struct {
 	ProjectID          string `json:"projectId"`
 	Name               string `json:"name"`
@@ -58,20 +68,21 @@ type DashboardSummary struct {
 }
 
 type FlakyTest struct {
-	ID               string    `json:"id"`
-	ProjectID        string    `json:"projectId"`
-	TestName         string    `json:"testName"`
-	SuiteName        *string   `json:"suiteName,omitempty"`
-	FlakeRate        float64   `json:"flakeRate"`
-	TotalExecutions  int       `json:"totalExecutions"`
-	FlakyExecutions  int       `json:"flakyExecutions"`
-	LastSeenAt       time.Time `json:"lastSeenAt"`
-	FirstSeenAt      time.Time `json:"firstSeenAt"`
-	Status           string    `json:"status"`
-	Severity         string    `json:"severity"`
-	LastErrorMessage *string   `json:"lastErrorMessage,omitempty"`
-	CreatedAt        time.Time `json:"createdAt"`
-	UpdatedAt        time.Time `json:"updatedAt"`
+	ID               string          `json:"id"`
+	ProjectID        string          `json:"projectId"`
+	TestName         string          `json:"testName"`
+	SuiteName        *string         `json:"suiteName,omitempty"`
+	FlakeRate        float64         `json:"flakeRate"`
+	TotalExecutions  int             `json:"totalExecutions"`
+	FlakyExecutions  int             `json:"flakyExecutions"`
+	LastSeenAt       time.Time       `json:"lastSeenAt"`
+	FirstSeenAt      time.Time       `json:"firstSeenAt"`
+	Status           string          `json:"status"`
+	Severity         string          `json:"severity"`
+	LastErrorMessage *string         `json:"lastErrorMessage,omitempty"`
+	Flips            []*FlipEvidence `json:"flips"`
+	CreatedAt        time.Time       `json:"createdAt"`
+	UpdatedAt        time.Time       `json:"updatedAt"`
 }
 
 type FlakyTestConnection struct {
@@ -100,6 +111,20 @@ type FlakyTestStats struct {
 	MostFlakyTest    *FlakyTest       `json:"mostFlakyTest,omitempty"`
 }
 
+type FlakyTestTrend struct {
+	Date          time.Time `json:"date"`
+	ActiveCount   int       `json:"activeCount"`
+	NewCount      int       `json:"newCount"`
+	ResolvedCount int       `json:"resolvedCount"`
+}
+
+type FlipEvidence struct {
+	Commit       *string  `json:"commit,omitempty"`
+	PassedRunIds []string `json:"passedRunIds"`
+	FailedRunIds []string `json:"failedRunIds"`
+	InRun        bool     `json:"inRun"`
+}
+
 type HealthStatus struct {
 	Status    string    `json:"status"`
 	Service   string    `json:"service"`
@@ -107,6 +132,52 @@ type HealthStatus struct {
 	Version   *string   `json:"version,omitempty"`
 }
 
+type IngestSpecAttemptInput struct {
+	Status       string     `json:"status"`
+	StartTime    *time.Time `json:"startTime,omitempty"`
+	Duration     *int       `json:"duration,omitempty"`
+	ErrorMessage *string    `json:"errorMessage,omitempty"`
+	StackTrace   *string    `json:"stackTrace,omitempty"`
+}
+
+type IngestSpecRunInput struct {
+	SpecName     string                    `json:"specName"`
+	Status       string                    `json:"status"`
+	StartTime    *time.Time                `json:"startTime,omitempty"`
+	EndTime      *time.Time                `json:"endTime,omitempty"`
+	Duration     *int                      `json:"duration,omitempty"`
+	ErrorMessage *string                   `json:"errorMessage,omitempty"`
+	StackTrace   *string                   `json:"stackTrace,omitempty"`
+	RetryCount   *int                      `json:"retryCount,omitempty"`
+	Attempts     []*IngestSpecAttemptInput `json:"attempts,omitempty"`
+	ParentName   *string                   `json:"parentName,omitempty"`
+	Parameters   map[string]any            `json:"parameters,omitempty"`
+}
+
+type IngestSuiteRunInput struct {
+	SuiteName string                `json:"suiteName"`
+	Status    *string               `json:"status,omitempty"`
+	StartTime *time.Time            `json:"startTime,omitempty"`
+	EndTime   *time.Time            `json:"endTime,omitempty"`
+	Duration  *int                  `json:"duration,omitempty"`
+	Specs     []*IngestSpecRunInput `json:"specs"`
+}
+
+type IngestTestRunInput struct {
+	ProjectID      string                 `json:"projectId"`
+	RunID          *string                `json:"runId,omitempty"`
+	Branch         *string                `json:"branch,omitempty"`
+	CommitSha      *string                `json:"commitSha,omitempty"`
+	Environment    *string                `json:"environment,omitempty"`
+	Status         *string                `json:"status,omitempty"`
+	StartTime      time.Time              `json:"startTime"`
+	EndTime        *time.Time             `json:"endTime,omitempty"`
+	Metadata       map[string]any         `json:"metadata,omitempty"`
+	Tags           []string               `json:"tags,omitempty"`
+	Suites         []*IngestSuiteRunInput `json:"suites"`
+	IdempotencyKey *string                `json:"idempotencyKey,omitempty"`
+}
+
 type JiraConnection struct {
 	ID                 string     `json:"id"`
 	ProjectID          string     `json:"projectId"`
@@ -198,20 +269,63 @@ type SeverityCount struct {
 	Count    int    `json:"count"`
 }
 
-type SpecRun struct {
-	ID           string     `json:"id"`
-	SuiteRunID   string     `json:"suiteRunId"`
-	SpecName     string     `json:"specName"`
+type SpecAttempt struct {
+	Attempt      int        `json:"attempt"`
 	Status       string     `json:"status"`
-	StartTime    time.Time  `json:"startTime"`
-	EndTime      *time.Time `json:"endTime,omitempty"`
+	StartTime    *time.Time `json:"startTime,omitempty"`
 	Duration     int        `json:"duration"`
 	ErrorMessage *string    `json:"errorMessage,omitempty"`
 	StackTrace   *string    `json:"stackTrace,omitempty"`
-	RetryCount   int        `json:"retryCount"`
-	IsFlaky      bool       `json:"isFlaky"`
-	CreatedAt    time.Time  `json:"createdAt"`
-	UpdatedAt    time.Time  `json:"updatedAt"`
+}
+
+type SpecOutput struct {
+	Stdout     string `json:"stdout"`
+	Stderr     string `json:"stderr"`
+	StdoutSize int    `json:"stdoutSize"`
+	StderrSize int    `json:"stderrSize"`
+	Truncated  bool   `json:"truncated"`
+}
+
+type SpecOutputMatch struct {
+	SpecRun   *SpecRun  `json:"specRun"`
+	SuiteName string    `json:"suiteName"`
+	TestRunID string    `json:"testRunId"`
+	RunID     string    `json:"runId"`
+	Branch    *string   `json:"branch,omitempty"`
+	StartTime time.Time `json:"startTime"`
+	Snippet   string    `json:"snippet"`
+}
+
+type SpecRun struct {
+	ID           string         `json:"id"`
+	SuiteRunID   string         `json:"suiteRunId"`
+	TestCaseID   *string        `json:"testCaseId,omitempty"`
+	SpecName     string         `json:"specName"`
+	Status       string         `json:"status"`
+	StartTime    time.Time      `json:"startTime"`
+	EndTime      *time.Time     `json:"endTime,omitempty"`
+	Duration     int            `json:"duration"`
+	ErrorMessage *string        `json:"errorMessage,omitempty"`
+	StackTrace   *string        `json:"stackTrace,omitempty"`
+	RetryCount   int            `json:"retryCount"`
+	IsFlaky      bool           `json:"isFlaky"`
+	Quarantined  bool           `json:"quarantined"`
+	Attempts     []*SpecAttempt `json:"attempts"`
+	Steps        []*SpecStep    `json:"steps"`
+	Attachments  []*Attachment  `json:"attachments"`
+	Output       *SpecOutput    `json:"output,omitempty"`
+	CreatedAt    time.Time      `json:"createdAt"`
+	UpdatedAt    time.Time      `json:"updatedAt"`
+}
+
+type SpecStep struct {
+	ID           string  `json:"id"`
+	Position     int     `json:"position"`
+	Keyword      string  `json:"keyword"`
+	Name         string  `json:"name"`
+	Status       string  `json:"status"`
+	Duration     int     `json:"duration"`
+	ErrorMessage *string `json:"errorMessage,omitempty"`
 }
 
 type SpecTreemapNode struct {
@@ -293,26 +407,69 @@ type TagUsage struct {
 	UsageCount  int     `json:"usageCount"`
 }
 
-type TestRun struct {
-	ID           string         `json:"id"`
-	ProjectID    string         `json:"projectId"`
+type TestCase struct {
+	ID          string         `json:"id"`
+	ProjectID   string         `json:"projectId"`
+	ParentID    *string        `json:"parentId,omitempty"`
+	SuiteName   string         `json:"suiteName"`
+	PackageName string         `json:"packageName"`
+	ClassName   string         `json:"className"`
+	Name        string         `json:"name"`
+	Parameters  map[string]any `json:"parameters,omitempty"`
+	Fingerprint string         `json:"fingerprint"`
+	FirstSeenAt time.Time      `json:"firstSeenAt"`
+	LastSeenAt  time.Time      `json:"lastSeenAt"`
+}
+
+type TestExecution struct {
+	SpecRunID    string         `json:"specRunId"`
+	TestCaseID   string         `json:"testCaseId"`
+	TestRunID    string         `json:"testRunId"`
 	RunID        string         `json:"runId"`
 	Branch       *string        `json:"branch,omitempty"`
-	CommitSha    *string        `json:"commitSha,omitempty"`
+	Environment  *string        `json:"environment,omitempty"`
+	GitCommit    *string        `json:"gitCommit,omitempty"`
+	Parameters   map[string]any `json:"parameters,omitempty"`
 	Status       string         `json:"status"`
 	StartTime    time.Time      `json:"startTime"`
-	EndTime      *time.Time     `json:"endTime,omitempty"`
-	TotalTests   int            `json:"totalTests"`
-	PassedTests  int            `json:"passedTests"`
-	FailedTests  int            `json:"failedTests"`
-	SkippedTests int            `json:"skippedTests"`
 	Duration     int            `json:"duration"`
-	Environment  *string        `json:"environment,omitempty"`
-	Metadata     map[string]any `json:"metadata,omitempty"`
-	Tags         []*Tag         `json:"tags"`
-	SuiteRuns    []*SuiteRun    `json:"suiteRuns"`
-	CreatedAt    time.Time      `json:"createdAt"`
-	UpdatedAt    time.Time      `json:"updatedAt"`
+	ErrorMessage *string        `json:"errorMessage,omitempty"`
+	RetryCount   int            `json:"retryCount"`
+	IsFlaky      bool           `json:"isFlaky"`
+	Quarantined  bool           `json:"quarantined"`
+}
+
+type TestHistory struct {
+	Test          *TestCase        `json:"test"`
+	Executions    []*TestExecution `json:"executions"`
+	Summary       *TestStats       `json:"summary"`
+	ByBranch      []*TestStats     `json:"byBranch"`
+	ByEnvironment []*TestStats     `json:"byEnvironment"`
+	ByParameters  []*TestStats     `json:"byParameters"`
+	ParameterSets []*TestCase      `json:"parameterSets"`
+}
+
+type TestRun struct {
+	ID               string         `json:"id"`
+	ProjectID        string         `json:"projectId"`
+	RunID            string         `json:"runId"`
+	Branch           *string        `json:"branch,omitempty"`
+	CommitSha        *string        `json:"commitSha,omitempty"`
+	Status           string         `json:"status"`
+	StartTime        time.Time      `json:"startTime"`
+	EndTime          *time.Time     `json:"endTime,omitempty"`
+	TotalTests       int            `json:"totalTests"`
+	PassedTests      int            `json:"passedTests"`
+	FailedTests      int            `json:"failedTests"`
+	SkippedTests     int            `json:"skippedTests"`
+	QuarantinedTests int            `json:"quarantinedTests"`
+	Duration         int            `json:"duration"`
+	Environment      *string        `json:"environment,omitempty"`
+	Metadata         map[string]any `json:"metadata,omitempty"`
+	Tags             []*Tag         `json:"tags"`
+	SuiteRuns        []*SuiteRun    `json:"suiteRuns"`
+	CreatedAt        time.Time      `json:"createdAt"`
+	UpdatedAt        time.Time      `json:"updatedAt"`
 }
 
 type TestRunConnection struct {
@@ -343,6 +500,18 @@ type TestRunStats struct {
 	SuccessRate     float64        `json:"successRate"`
 }
 
+type TestStats struct {
+	Key             *string `json:"key,omitempty"`
+	Total           int     `json:"total"`
+	Passed          int     `json:"passed"`
+	Failed          int     `json:"failed"`
+	Skipped         int     `json:"skipped"`
+	Flaky           int     `json:"flaky"`
+	Quarantined     int     `json:"quarantined"`
+	PassRate        float64 `json:"passRate"`
+	AverageDuration int     `json:"averageDuration"`
+}
+
 type TreemapData struct {
 	Projects        []*ProjectTreemapNode `json:"projects"`
 	TotalDuration   int                   `json:"totalDuration"`
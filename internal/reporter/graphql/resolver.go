@@ -19,6 +19,8 @@ import (
 // Resolver is the root GraphQL resolver
 type Resolver struct {
 	testingService        *testingApp.TestRunService
+	idempotencyService    *testingApp.IdempotencyService
+	attachmentService     *testingApp.AttachmentService
 	projectService        *projectsApp.ProjectService
 	tagService            *tagsApp.TagService
 	flakyDetectionService *analyticsApp.FlakyDetectionService
@@ -31,6 +33,8 @@ type Resolver struct {
 // NewResolver creates a new GraphQL resolver
 func NewResolver(
 	testingService *testingApp.TestRunService,
+	idempotencyService *testingApp.IdempotencyService,
+	attachmentService *testingApp.AttachmentService,
 	projectService *projectsApp.ProjectService,
 	tagService *tagsApp.TagService,
 	flakyDetectionService *analyticsApp.FlakyDetectionService,
@@ -40,6 +44,8 @@ func NewResolver(
 ) *Resolver {
 	return &Resolver{
 		testingService:        testingService,
+		idempotencyService:    idempotencyService,
+		attachmentService:     attachmentService,
 		projectService:        projectService,
 		tagService:            tagService,
 		flakyDetectionService: flakyDetectionService,
@@ -2,19 +2,23 @@ package graphql
 
 // This file will be automatically regenerated based on the schema, any resolver implementations
 // will be copied through when generating and any unknown code will be moved to the end.
-// Code generated by github.com/99designs/gqlgen version v0.17.76
+// Code generated by github.com/99designs/gqlgen version v0.17.78
 
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
+	analyticsApp "github.com/guidewire-oss/fern-platform/internal/domains/analytics/application"
 	authDomain "github.com/guidewire-oss/fern-platform/internal/domains/auth/domain"
 	"github.com/guidewire-oss/fern-platform/internal/domains/integrations"
 	projectsDomain "github.com/guidewire-oss/fern-platform/internal/domains/projects/domain"
+	testingApp "github.com/guidewire-oss/fern-platform/internal/domains/testing/application"
+	testingDomain "github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
 	"github.com/guidewire-oss/fern-platform/internal/reporter/graphql/generated"
 	"github.com/guidewire-oss/fern-platform/internal/reporter/graphql/model"
 	"github.com/guidewire-oss/fern-platform/pkg/database"
@@ -26,6 +30,12 @@ func (r *mutationResolver) CreateTestRun(ctx context.Context, input model.Create
 	return nil, fmt.Errorf("CreateTestRun not yet implemented")
 }
 
+// IngestTestRun is the resolver for the ingestTestRun field.
+func (r *mutationResolver) IngestTestRun(ctx context.Context, input model.IngestTestRunInput) (*model.TestRun, error) {
+	// Use domain service implementation
+	return r.IngestTestRun_domain(ctx, input)
+}
+
 // UpdateTestRunStatus is the resolver for the updateTestRunStatus field.
 func (r *mutationResolver) UpdateTestRunStatus(ctx context.Context, runID string, status string, endTime *time.Time) (*model.TestRun, error) {
 	return nil, fmt.Errorf("UpdateTestRunStatus not yet implemented")
@@ -429,7 +439,7 @@ func (r *mutationResolver) TestJiraConnection(ctx context.Context, id string) (b
 	r.logger.Infof("Testing JIRA connection %s for project %s", id, project.ProjectID())
 	if err := r.jiraConnectionService.TestConnection(ctx, id); err != nil {
 		r.logger.Errorf("TestJiraConnection failed: %v", err)
-		return false, nil  // Return false but no error so GraphQL returns the boolean
+		return false, nil // Return false but no error so GraphQL returns the boolean
 	}
 
 	r.logger.Infof("TestJiraConnection successful for connection %s", id)
@@ -694,6 +704,9 @@ func (r *queryResolver) TestRunByRunID(ctx context.Context, runID string) (*mode
 	if err != nil {
 		return nil, fmt.Errorf("failed to get test run: %w", err)
 	}
+	if err := checkProjectRead(ctx, testRun.ProjectID); err != nil {
+		return nil, err
+	}
 	return r.convertTestRunToGraphQL(testRun), nil
 }
 
@@ -720,6 +733,157 @@ func (r *queryResolver) RecentTestRuns(ctx context.Context, projectID *string, l
 	return r.RecentTestRuns_domain(ctx, projectID, limit)
 }
 
+// SearchSpecOutput is the resolver for the searchSpecOutput field.
+func (r *queryResolver) SearchSpecOutput(ctx context.Context, projectID string, query string, days *int, status *string, limit *int) ([]*model.SpecOutputMatch, error) {
+	if err := checkProjectRead(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	search := testingDomain.SpecOutputSearch{
+		ProjectID: projectID,
+		Query:     query,
+	}
+	if days != nil && *days > 0 {
+		search.Since = time.Now().AddDate(0, 0, -*days)
+	}
+	if status != nil {
+		search.Status = *status
+	}
+	if limit != nil {
+		search.Limit = *limit
+	}
+
+	matches, err := r.testingService.SearchSpecOutput(ctx, search)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search spec output: %w", err)
+	}
+
+	result := make([]*model.SpecOutputMatch, len(matches))
+	for i, match := range matches {
+		result[i] = &model.SpecOutputMatch{
+			SpecRun:   r.convertSpecRunToGraphQL(match.SpecRun),
+			SuiteName: match.SuiteName,
+			TestRunID: strconv.FormatUint(uint64(match.TestRunID), 10),
+			RunID:     match.RunID,
+			Branch:    convertStringPtr(match.Branch),
+			StartTime: match.StartTime,
+			Snippet:   match.Snippet,
+		}
+	}
+	return result, nil
+}
+
+// TestCase is the resolver for the testCase field.
+func (r *queryResolver) TestCase(ctx context.Context, id string) (*model.TestCase, error) {
+	testCaseID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid test ID: %w", err)
+	}
+
+	testCase, err := r.testingService.GetTestCase(ctx, uint(testCaseID))
+	if errors.Is(err, testingDomain.ErrTestCaseNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get test case: %w", err)
+	}
+	if err := checkProjectRead(ctx, testCase.ProjectID); err != nil {
+		return nil, err
+	}
+	return convertTestCaseToGraphQL(testCase), nil
+}
+
+// TestCases is the resolver for the testCases field.
+func (r *queryResolver) TestCases(ctx context.Context, projectID string, search *string, limit *int) ([]*model.TestCase, error) {
+	if err := checkProjectRead(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	var searchVal string
+	if search != nil {
+		searchVal = *search
+	}
+	limitVal := 0
+	if limit != nil {
+		limitVal = *limit
+	}
+
+	testCases, err := r.testingService.ListTestCases(ctx, projectID, searchVal, limitVal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list test cases: %w", err)
+	}
+
+	result := make([]*model.TestCase, len(testCases))
+	for i, testCase := range testCases {
+		result[i] = convertTestCaseToGraphQL(testCase)
+	}
+	return result, nil
+}
+
+// TestHistory is the resolver for the testHistory field.
+func (r *queryResolver) TestHistory(ctx context.Context, testID string, days *int, branch *string, environment *string, limit *int) (*model.TestHistory, error) {
+	testCaseID, err := strconv.ParseUint(testID, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid test ID: %w", err)
+	}
+
+	var filter testingDomain.TestCaseHistoryFilter
+	if days != nil && *days > 0 {
+		filter.Since = time.Now().AddDate(0, 0, -*days)
+	}
+	if branch != nil {
+		filter.Branch = *branch
+	}
+	if environment != nil {
+		filter.Environment = *environment
+	}
+	if limit != nil {
+		filter.Limit = *limit
+	}
+
+	history, err := r.testingService.GetTestCaseHistory(ctx, uint(testCaseID), filter)
+	if errors.Is(err, testingDomain.ErrTestCaseNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get test history: %w", err)
+	}
+	if err := checkProjectRead(ctx, history.TestCase.ProjectID); err != nil {
+		return nil, err
+	}
+
+	executions := make([]*model.TestExecution, len(history.Executions))
+	for i, execution := range history.Executions {
+		executions[i] = &model.TestExecution{
+			SpecRunID:    strconv.FormatUint(uint64(execution.SpecRunID), 10),
+			TestCaseID:   strconv.FormatUint(uint64(execution.TestCaseID), 10),
+			TestRunID:    strconv.FormatUint(uint64(execution.TestRunID), 10),
+			RunID:        execution.RunID,
+			Branch:       convertStringPtr(execution.Branch),
+			Environment:  convertStringPtr(execution.Environment),
+			GitCommit:    convertStringPtr(execution.GitCommit),
+			Parameters:   convertParametersToGraphQL(execution.Parameters),
+			Status:       execution.Status,
+			StartTime:    execution.StartTime,
+			Duration:     int(execution.Duration.Milliseconds()),
+			ErrorMessage: convertStringPtr(execution.ErrorMessage),
+			RetryCount:   execution.RetryCount,
+			IsFlaky:      execution.IsFlaky,
+			Quarantined:  execution.Quarantined,
+		}
+	}
+
+	return &model.TestHistory{
+		Test:          convertTestCaseToGraphQL(history.TestCase),
+		Executions:    executions,
+		Summary:       convertTestStatsToGraphQL(history.Summary),
+		ByBranch:      convertTestStatsListToGraphQL(history.ByBranch),
+		ByParameters:  convertTestStatsListToGraphQL(history.ByParameters),
+		ParameterSets: convertTestCasesToGraphQL(history.ParameterSets),
+		ByEnvironment: convertTestStatsListToGraphQL(history.ByEnvironment),
+	}, nil
+}
+
 // Project is the resolver for the project field.
 func (r *queryResolver) Project(ctx context.Context, id string) (*model.Project, error) {
 	// Use domain service implementation
@@ -766,12 +930,12 @@ func (r *queryResolver) PopularTags(ctx context.Context, limit *int) ([]*model.T
 
 // FlakyTest is the resolver for the flakyTest field.
 func (r *queryResolver) FlakyTest(ctx context.Context, id string) (*model.FlakyTest, error) {
-	return nil, fmt.Errorf("FlakyTest not yet implemented")
+	return r.FlakyTest_domain(ctx, id)
 }
 
 // FlakyTests is the resolver for the flakyTests field.
 func (r *queryResolver) FlakyTests(ctx context.Context, filter *model.FlakyTestFilter, first *int, after *string, orderBy *string, orderDirection *model.OrderDirection) (*model.FlakyTestConnection, error) {
-	return nil, fmt.Errorf("FlakyTests not yet implemented")
+	return r.FlakyTests_domain(ctx, filter, first, after, orderBy, orderDirection)
 }
 
 // FlakyTestStats is the resolver for the flakyTestStats field.
@@ -784,6 +948,36 @@ func (r *queryResolver) RecentlyAddedFlakyTests(ctx context.Context, projectID *
 	return nil, fmt.Errorf("RecentlyAddedFlakyTests not yet implemented")
 }
 
+// FlakyTestTrends is the resolver for the flakyTestTrends field.
+func (r *queryResolver) FlakyTestTrends(ctx context.Context, projectID string, days *int) ([]*model.FlakyTestTrend, error) {
+	daysVal := 30
+	if days != nil {
+		daysVal = *days
+	}
+	if daysVal <= 0 || daysVal > analyticsApp.MaxHistoryDays {
+		return nil, fmt.Errorf("days must be between 1 and %d", analyticsApp.MaxHistoryDays)
+	}
+	if err := checkProjectRead(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	trends, err := r.flakyDetectionService.GetFlakyTestTrends(ctx, projectID, time.Duration(daysVal)*24*time.Hour)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flaky test trends: %w", err)
+	}
+
+	result := make([]*model.FlakyTestTrend, len(trends))
+	for i, trend := range trends {
+		result[i] = &model.FlakyTestTrend{
+			Date:          trend.Date,
+			ActiveCount:   trend.ActiveCount,
+			NewCount:      trend.NewCount,
+			ResolvedCount: trend.ResolvedCount,
+		}
+	}
+	return result, nil
+}
+
 // JiraConnection is the resolver for the jiraConnection field.
 func (r *queryResolver) JiraConnection(ctx context.Context, id string) (*model.JiraConnection, error) {
 	conn, err := r.jiraConnectionService.GetConnection(ctx, id)
@@ -815,6 +1009,128 @@ func (r *queryResolver) JiraConnections(ctx context.Context, projectID string) (
 	return models, nil
 }
 
+// Attempts is the resolver for the attempts field.
+func (r *specRunResolver) Attempts(ctx context.Context, obj *model.SpecRun) ([]*model.SpecAttempt, error) {
+	intID, err := strconv.Atoi(obj.ID)
+	if err != nil {
+		r.logger.WithError(err).WithField("spec_run_id", obj.ID).Error("Failed to parse spec run ID")
+		return nil, fmt.Errorf("invalid spec run ID: %w", err)
+	}
+
+	var attempts []*database.SpecAttempt
+	if err := r.db.Where("spec_run_id = ?", intID).Order("attempt ASC").Find(&attempts).Error; err != nil {
+		r.logger.WithError(err).WithField("spec_run_id", obj.ID).Error("Failed to load spec attempts")
+		return nil, fmt.Errorf("failed to load spec attempts: %w", err)
+	}
+
+	result := make([]*model.SpecAttempt, len(attempts))
+	for i, attempt := range attempts {
+		result[i] = &model.SpecAttempt{
+			Attempt:      attempt.Attempt,
+			Status:       attempt.Status,
+			Duration:     int(attempt.Duration),
+			ErrorMessage: convertStringPtr(attempt.ErrorMessage),
+			StackTrace:   convertStringPtr(attempt.StackTrace),
+		}
+		if !attempt.StartTime.IsZero() {
+			result[i].StartTime = &attempt.StartTime
+		}
+	}
+
+	return result, nil
+}
+
+// Steps is the resolver for the steps field.
+func (r *specRunResolver) Steps(ctx context.Context, obj *model.SpecRun) ([]*model.SpecStep, error) {
+	intID, err := strconv.Atoi(obj.ID)
+	if err != nil {
+		r.logger.WithError(err).WithField("spec_run_id", obj.ID).Error("Failed to parse spec run ID")
+		return nil, fmt.Errorf("invalid spec run ID: %w", err)
+	}
+
+	var steps []*database.SpecStep
+	if err := r.db.Where("spec_run_id = ?", intID).Order("position ASC").Find(&steps).Error; err != nil {
+		r.logger.WithError(err).WithField("spec_run_id", obj.ID).Error("Failed to load spec steps")
+		return nil, fmt.Errorf("failed to load spec steps: %w", err)
+	}
+
+	result := make([]*model.SpecStep, len(steps))
+	for i, step := range steps {
+		result[i] = &model.SpecStep{
+			ID:           fmt.Sprintf("%d", step.ID),
+			Position:     step.Position,
+			Keyword:      step.Keyword,
+			Name:         step.Name,
+			Status:       step.Status,
+			Duration:     int(step.Duration),
+			ErrorMessage: convertStringPtr(step.ErrorMessage),
+		}
+	}
+
+	return result, nil
+}
+
+// Attachments is the resolver for the attachments field.
+func (r *specRunResolver) Attachments(ctx context.Context, obj *model.SpecRun) ([]*model.Attachment, error) {
+	intID, err := strconv.Atoi(obj.ID)
+	if err != nil {
+		r.logger.WithError(err).WithField("spec_run_id", obj.ID).Error("Failed to parse spec run ID")
+		return nil, fmt.Errorf("invalid spec run ID: %w", err)
+	}
+
+	var attachments []*database.Attachment
+	if err := r.db.Omit("content").Where("spec_run_id = ?", intID).Order("id ASC").Find(&attachments).Error; err != nil {
+		r.logger.WithError(err).WithField("spec_run_id", obj.ID).Error("Failed to load spec attachments")
+		return nil, fmt.Errorf("failed to load spec attachments: %w", err)
+	}
+
+	result := make([]*model.Attachment, len(attachments))
+	for i, attachment := range attachments {
+		result[i] = &model.Attachment{
+			ID:          fmt.Sprintf("%d", attachment.ID),
+			Name:        attachment.Name,
+			ContentType: attachment.ContentType,
+			Size:        int(attachment.Size),
+			Sha256:      attachment.SHA256,
+			CreatedAt:   attachment.CreatedAt,
+		}
+		if r.attachmentService != nil {
+			result[i].URL = r.attachmentService.SignedURL(&testingDomain.Attachment{ID: attachment.ID})
+		}
+	}
+
+	return result, nil
+}
+
+// Output is the resolver for the output field.
+func (r *specRunResolver) Output(ctx context.Context, obj *model.SpecRun) (*model.SpecOutput, error) {
+	intID, err := strconv.Atoi(obj.ID)
+	if err != nil {
+		r.logger.WithError(err).WithField("spec_run_id", obj.ID).Error("Failed to parse spec run ID")
+		return nil, fmt.Errorf("invalid spec run ID: %w", err)
+	}
+
+	output, err := r.testingService.GetSpecOutput(ctx, uint(intID))
+	if errors.Is(err, testingDomain.ErrSpecOutputNotFound) || errors.Is(err, testingApp.ErrSpecOutputUnavailable) {
+		return nil, nil
+	}
+	if err != nil {
+		r.logger.WithError(err).WithField("spec_run_id", obj.ID).Error("Failed to load spec output")
+		return nil, fmt.Errorf("failed to load spec output: %w", err)
+	}
+	if err := checkProjectRead(ctx, output.ProjectID); err != nil {
+		return nil, err
+	}
+
+	return &model.SpecOutput{
+		Stdout:     output.Stdout,
+		Stderr:     output.Stderr,
+		StdoutSize: int(output.StdoutSize),
+		StderrSize: int(output.StderrSize),
+		Truncated:  output.Truncated,
+	}, nil
+}
+
 // TestRunCreated is the resolver for the testRunCreated field.
 func (r *subscriptionResolver) TestRunCreated(ctx context.Context, projectID *string) (<-chan *model.TestRun, error) {
 	ch := make(chan *model.TestRun)
@@ -888,6 +1204,7 @@ func (r *suiteRunResolver) SpecRuns(ctx context.Context, obj *model.SuiteRun) ([
 		result[i] = &model.SpecRun{
 			ID:           fmt.Sprintf("%d", sp.ID),
 			SuiteRunID:   fmt.Sprintf("%d", sp.SuiteRunID),
+			TestCaseID:   convertIDPtr(sp.TestCaseID),
 			SpecName:     sp.SpecName,
 			Status:       sp.Status,
 			StartTime:    startTime,
@@ -897,6 +1214,7 @@ func (r *suiteRunResolver) SpecRuns(ctx context.Context, obj *model.SuiteRun) ([
 			StackTrace:   convertStringPtr(sp.StackTrace),
 			RetryCount:   sp.RetryCount,
 			IsFlaky:      sp.IsFlaky,
+			Quarantined:  sp.Quarantined,
 			CreatedAt:    sp.CreatedAt,
 			UpdatedAt:    sp.UpdatedAt,
 		}
@@ -969,6 +1287,9 @@ func (r *Resolver) Project() generated.ProjectResolver { return &projectResolver
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
+// SpecRun returns generated.SpecRunResolver implementation.
+func (r *Resolver) SpecRun() generated.SpecRunResolver { return &specRunResolver{r} }
+
 // Subscription returns generated.SubscriptionResolver implementation.
 func (r *Resolver) Subscription() generated.SubscriptionResolver { return &subscriptionResolver{r} }
 
@@ -981,6 +1302,7 @@ func (r *Resolver) TestRun() generated.TestRunResolver { return &testRunResolver
 type mutationResolver struct{ *Resolver }
 type projectResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type specRunResolver struct{ *Resolver }
 type subscriptionResolver struct{ *Resolver }
 type suiteRunResolver struct{ *Resolver }
 type testRunResolver struct{ *Resolver }
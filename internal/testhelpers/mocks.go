@@ -2,10 +2,12 @@ package testhelpers
 
 import (
 	"context"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	
 	"github.com/guidewire-oss/fern-platform/internal/domains/projects/domain"
+	analyticsDomain "github.com/guidewire-oss/fern-platform/internal/domains/analytics/domain"
 	authDomain "github.com/guidewire-oss/fern-platform/internal/domains/auth/domain"
 	testingDomain "github.com/guidewire-oss/fern-platform/internal/domains/testing/domain"
 	tagsDomain "github.com/guidewire-oss/fern-platform/internal/domains/tags/domain"
@@ -211,6 +213,79 @@ func (m *MockTagRepository) FindAll(ctx context.Context) ([]*tagsDomain.Tag, err
 	return args.Get(0).([]*tagsDomain.Tag), args.Error(1)
 }
 
+// MockFlakyDetectionRepository is a mock implementation of analyticsDomain.FlakyDetectionRepository
+type MockFlakyDetectionRepository struct {
+	mock.Mock
+}
+
+func (m *MockFlakyDetectionRepository) SaveFlakyTest(ctx context.Context, flaky *analyticsDomain.FlakyTest) error {
+	args := m.Called(ctx, flaky)
+	return args.Error(0)
+}
+
+func (m *MockFlakyDetectionRepository) GetFlakyTest(ctx context.Context, testID string) (*analyticsDomain.FlakyTest, error) {
+	args := m.Called(ctx, testID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*analyticsDomain.FlakyTest), args.Error(1)
+}
+
+func (m *MockFlakyDetectionRepository) FindFlakyTestsByProject(ctx context.Context, projectID string, status analyticsDomain.FlakyTestStatus) ([]*analyticsDomain.FlakyTest, error) {
+	args := m.Called(ctx, projectID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*analyticsDomain.FlakyTest), args.Error(1)
+}
+
+func (m *MockFlakyDetectionRepository) UpdateFlakyTestStatus(ctx context.Context, testID string, status analyticsDomain.FlakyTestStatus) error {
+	args := m.Called(ctx, testID, status)
+	return args.Error(0)
+}
+
+func (m *MockFlakyDetectionRepository) SaveTestRunAnalysis(ctx context.Context, analysis *analyticsDomain.TestRunAnalysis) error {
+	args := m.Called(ctx, analysis)
+	return args.Error(0)
+}
+
+func (m *MockFlakyDetectionRepository) SaveFlakyTestTrends(ctx context.Context, projectID string, trends []analyticsDomain.FlakyTestTrend) error {
+	args := m.Called(ctx, projectID, trends)
+	return args.Error(0)
+}
+
+func (m *MockFlakyDetectionRepository) GetFlakyTestTrends(ctx context.Context, projectID string, since time.Time) ([]analyticsDomain.FlakyTestTrend, error) {
+	args := m.Called(ctx, projectID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]analyticsDomain.FlakyTestTrend), args.Error(1)
+}
+
+func (m *MockFlakyDetectionRepository) GetTestRunHistory(ctx context.Context, testCaseID uint, since time.Time) ([]analyticsDomain.TestExecutionResult, error) {
+	args := m.Called(ctx, testCaseID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]analyticsDomain.TestExecutionResult), args.Error(1)
+}
+
+func (m *MockFlakyDetectionRepository) GetRecentTests(ctx context.Context, projectID string, since time.Time) ([]analyticsDomain.TestIdentity, error) {
+	args := m.Called(ctx, projectID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]analyticsDomain.TestIdentity), args.Error(1)
+}
+
+func (m *MockFlakyDetectionRepository) GetTestsInRuns(ctx context.Context, projectID string, testRunIDs []uint) ([]analyticsDomain.TestIdentity, error) {
+	args := m.Called(ctx, projectID, testRunIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]analyticsDomain.TestIdentity), args.Error(1)
+}
+
 // MockBuilder provides a fluent interface for setting up mocks
 type MockBuilder struct {
 	mock *mock.Mock
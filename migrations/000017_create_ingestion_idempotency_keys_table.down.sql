@@ -0,0 +1,2 @@
+-- Drop ingestion_idempotency_keys table
+DROP TABLE IF EXISTS ingestion_idempotency_keys;
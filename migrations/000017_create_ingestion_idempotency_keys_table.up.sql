@@ -0,0 +1,19 @@
+-- Create ingestion_idempotency_keys table
+CREATE TABLE IF NOT EXISTS ingestion_idempotency_keys (
+    id BIGSERIAL PRIMARY KEY,
+    project_id VARCHAR(255) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash VARCHAR(64) NOT NULL,
+    test_run_id BIGINT,
+    response_status INTEGER NOT NULL DEFAULT 0, -- 0 while the request is still being processed
+    response_body BYTEA,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    UNIQUE(project_id, idempotency_key)
+);
+
+-- Create indexes for ingestion_idempotency_keys
+CREATE INDEX IF NOT EXISTS idx_ingestion_idempotency_keys_expires_at ON ingestion_idempotency_keys(expires_at);
+
+COMMENT ON TABLE ingestion_idempotency_keys IS 'Outcome of ingestion requests submitted with an Idempotency-Key, kept until expires_at';
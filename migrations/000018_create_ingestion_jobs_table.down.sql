@@ -0,0 +1,2 @@
+-- Drop ingestion_jobs table
+DROP TABLE IF EXISTS ingestion_jobs;
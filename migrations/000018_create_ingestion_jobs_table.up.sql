@@ -0,0 +1,30 @@
+-- Create ingestion_jobs table
+CREATE TABLE IF NOT EXISTS ingestion_jobs (
+    id UUID PRIMARY KEY,
+    project_id VARCHAR(255) NOT NULL,
+    format VARCHAR(50) NOT NULL,
+    payload BYTEA NOT NULL,
+    run_id VARCHAR(255),
+    branch VARCHAR(255),
+    git_commit VARCHAR(255),
+    environment VARCHAR(255),
+    status VARCHAR(50) NOT NULL DEFAULT 'queued', -- queued, processing, succeeded, dead_lettered
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL,
+    errors JSONB,
+    test_run_id BIGINT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    lease_expires_at TIMESTAMP WITH TIME ZONE,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create indexes for ingestion_jobs
+CREATE INDEX IF NOT EXISTS idx_ingestion_jobs_status_next_attempt_at ON ingestion_jobs(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_ingestion_jobs_project_id ON ingestion_jobs(project_id);
+
+COMMENT ON TABLE ingestion_jobs IS 'Durable queue of test reports accepted for asynchronous ingestion';
+COMMENT ON COLUMN ingestion_jobs.errors IS 'Error recorded for each failed processing attempt';
+COMMENT ON COLUMN ingestion_jobs.lease_expires_at IS 'When a processing job is considered abandoned and may be claimed again';
@@ -0,0 +1,10 @@
+-- Remove test run shard tracking
+ALTER TABLE ingestion_jobs DROP COLUMN IF EXISTS shard_total;
+ALTER TABLE ingestion_jobs DROP COLUMN IF EXISTS shard_index;
+ALTER TABLE ingestion_jobs DROP COLUMN IF EXISTS group_id;
+DROP INDEX IF EXISTS idx_suite_runs_test_run_id_shard_index;
+DROP INDEX IF EXISTS idx_test_runs_open_shard_groups;
+DROP TABLE IF EXISTS test_run_shards;
+ALTER TABLE suite_runs DROP COLUMN IF EXISTS shard_index;
+ALTER TABLE test_runs DROP COLUMN IF EXISTS shards_received;
+ALTER TABLE test_runs DROP COLUMN IF EXISTS shard_total;
@@ -0,0 +1,35 @@
+-- Track runs that are split across parallel CI shards and merged into one logical run
+ALTER TABLE test_runs ADD COLUMN IF NOT EXISTS shard_total INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE test_runs ADD COLUMN IF NOT EXISTS shards_received INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE suite_runs ADD COLUMN IF NOT EXISTS shard_index INTEGER;
+
+-- Create test_run_shards table
+CREATE TABLE IF NOT EXISTS test_run_shards (
+    test_run_id BIGINT NOT NULL,
+    shard_index INTEGER NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE,
+    total_tests INTEGER DEFAULT 0,
+    passed_tests INTEGER DEFAULT 0,
+    failed_tests INTEGER DEFAULT 0,
+    skipped_tests INTEGER DEFAULT 0,
+    received_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    PRIMARY KEY (test_run_id, shard_index),
+    CONSTRAINT fk_test_run_shards_test_run_id
+        FOREIGN KEY (test_run_id)
+        REFERENCES test_runs(id)
+        ON DELETE CASCADE
+);
+
+-- Sharded runs still waiting for shards are swept for timeouts
+CREATE INDEX IF NOT EXISTS idx_test_runs_open_shard_groups ON test_runs(created_at) WHERE shard_total > 0 AND status = 'running';
+CREATE INDEX IF NOT EXISTS idx_suite_runs_test_run_id_shard_index ON suite_runs(test_run_id, shard_index);
+
+-- Queued ingestions can belong to a shard group
+ALTER TABLE ingestion_jobs ADD COLUMN IF NOT EXISTS group_id VARCHAR(255);
+ALTER TABLE ingestion_jobs ADD COLUMN IF NOT EXISTS shard_index INTEGER;
+ALTER TABLE ingestion_jobs ADD COLUMN IF NOT EXISTS shard_total INTEGER;
+
+COMMENT ON TABLE test_run_shards IS 'Shards received for a test run split across parallel CI jobs';
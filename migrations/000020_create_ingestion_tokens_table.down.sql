@@ -0,0 +1,2 @@
+-- Drop ingestion_tokens table
+DROP TABLE IF EXISTS ingestion_tokens;
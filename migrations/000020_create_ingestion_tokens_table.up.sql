@@ -0,0 +1,22 @@
+-- Create ingestion_tokens table
+CREATE TABLE IF NOT EXISTS ingestion_tokens (
+    id UUID PRIMARY KEY,
+    project_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    token_prefix VARCHAR(32) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    created_by VARCHAR(255),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create indexes for ingestion_tokens
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ingestion_tokens_token_hash ON ingestion_tokens(token_hash);
+CREATE INDEX IF NOT EXISTS idx_ingestion_tokens_project_id ON ingestion_tokens(project_id);
+
+COMMENT ON TABLE ingestion_tokens IS 'Project-scoped bearer tokens that authorize test report ingestion';
+COMMENT ON COLUMN ingestion_tokens.token_hash IS 'Hex-encoded SHA-256 of the token; the token itself is never stored';
+COMMENT ON COLUMN ingestion_tokens.token_prefix IS 'Leading characters of the token, shown to help identify it';
@@ -0,0 +1,4 @@
+-- Drop access_tokens table and service accounts
+DROP TABLE IF EXISTS access_tokens;
+DROP INDEX IF EXISTS idx_users_service_account;
+ALTER TABLE users DROP COLUMN IF EXISTS service_account;
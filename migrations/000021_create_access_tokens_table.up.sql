@@ -0,0 +1,27 @@
+-- Service accounts are users that only authenticate with access tokens
+ALTER TABLE users ADD COLUMN IF NOT EXISTS service_account BOOLEAN NOT NULL DEFAULT false;
+CREATE INDEX IF NOT EXISTS idx_users_service_account ON users(service_account) WHERE service_account;
+
+-- Create access_tokens table
+CREATE TABLE IF NOT EXISTS access_tokens (
+    id UUID PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    token_prefix VARCHAR(32) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    scopes TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(255),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create indexes for access_tokens
+CREATE UNIQUE INDEX IF NOT EXISTS idx_access_tokens_token_hash ON access_tokens(token_hash);
+CREATE INDEX IF NOT EXISTS idx_access_tokens_user_id ON access_tokens(user_id);
+
+COMMENT ON TABLE access_tokens IS 'Personal access tokens and service account tokens for API and GraphQL access';
+COMMENT ON COLUMN access_tokens.token_hash IS 'Hex-encoded SHA-256 of the token; the token itself is never stored';
+COMMENT ON COLUMN access_tokens.scopes IS 'Space-separated scopes such as project:read:*, the only permissions a request made with the token has';
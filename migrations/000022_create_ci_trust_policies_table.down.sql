@@ -0,0 +1,2 @@
+-- Drop ci_trust_policies table
+DROP TABLE IF EXISTS ci_trust_policies;
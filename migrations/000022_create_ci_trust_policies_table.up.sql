@@ -0,0 +1,20 @@
+-- Create ci_trust_policies table
+CREATE TABLE IF NOT EXISTS ci_trust_policies (
+    id UUID PRIMARY KEY,
+    project_id VARCHAR(255) NOT NULL,
+    issuer VARCHAR(255) NOT NULL,
+    repository VARCHAR(500) NOT NULL,
+    ref VARCHAR(500) NOT NULL DEFAULT '',
+    workflow VARCHAR(500) NOT NULL DEFAULT '',
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create indexes for ci_trust_policies
+CREATE INDEX IF NOT EXISTS idx_ci_trust_policies_project_id ON ci_trust_policies(project_id);
+CREATE INDEX IF NOT EXISTS idx_ci_trust_policies_issuer ON ci_trust_policies(issuer);
+
+COMMENT ON TABLE ci_trust_policies IS 'Map claims of CI OIDC tokens to permission to upload test reports to a project';
+COMMENT ON COLUMN ci_trust_policies.issuer IS 'Name of a CI OIDC issuer from the auth.ciOidc configuration';
+COMMENT ON COLUMN ci_trust_policies.repository IS 'Glob pattern for the repository claim; ref and workflow patterns match anything when empty';
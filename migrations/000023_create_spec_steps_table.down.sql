@@ -0,0 +1,2 @@
+-- Drop spec_steps table
+DROP TABLE IF EXISTS spec_steps CASCADE;
@@ -0,0 +1,22 @@
+-- Create spec_steps table
+CREATE TABLE IF NOT EXISTS spec_steps (
+    id BIGSERIAL PRIMARY KEY,
+    spec_run_id BIGINT NOT NULL,
+    position INTEGER NOT NULL,
+    keyword VARCHAR(50) NOT NULL,
+    name TEXT NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    duration_ms BIGINT DEFAULT 0,
+    error_message TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT fk_spec_steps_spec_run_id
+        FOREIGN KEY (spec_run_id)
+        REFERENCES spec_runs(id)
+        ON DELETE CASCADE
+);
+
+-- Steps are always read in order for one spec
+CREATE INDEX IF NOT EXISTS idx_spec_steps_spec_run_id_position ON spec_steps(spec_run_id, position);
+
+COMMENT ON TABLE spec_steps IS 'Steps of BDD scenarios (Gherkin Given/When/Then and failed hooks) recorded for a spec run';
@@ -0,0 +1,2 @@
+-- Drop spec_attachments table
+DROP TABLE IF EXISTS spec_attachments CASCADE;
@@ -0,0 +1,19 @@
+-- Create spec_attachments table
+CREATE TABLE IF NOT EXISTS spec_attachments (
+    id BIGSERIAL PRIMARY KEY,
+    spec_run_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    content BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT fk_spec_attachments_spec_run_id
+        FOREIGN KEY (spec_run_id)
+        REFERENCES spec_runs(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_spec_attachments_spec_run_id ON spec_attachments(spec_run_id);
+
+COMMENT ON TABLE spec_attachments IS 'Files captured while a spec ran (screenshots, logs, ...) uploaded with its report';
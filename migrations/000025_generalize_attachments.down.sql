@@ -0,0 +1,22 @@
+-- Only spec attachments with inline content fit the old table
+DELETE FROM attachments WHERE spec_run_id IS NULL OR content IS NULL;
+
+DROP INDEX IF EXISTS idx_attachments_project_id;
+DROP INDEX IF EXISTS idx_attachments_suite_run_id;
+DROP INDEX IF EXISTS idx_attachments_test_run_id;
+
+ALTER TABLE attachments
+    DROP CONSTRAINT IF EXISTS fk_attachments_suite_run_id,
+    DROP CONSTRAINT IF EXISTS fk_attachments_test_run_id,
+    DROP COLUMN storage_key,
+    DROP COLUMN storage_backend,
+    DROP COLUMN checksum_sha256,
+    DROP COLUMN suite_run_id,
+    DROP COLUMN test_run_id,
+    DROP COLUMN project_id,
+    ALTER COLUMN spec_run_id SET NOT NULL,
+    ALTER COLUMN content SET NOT NULL;
+
+ALTER TABLE attachments RENAME CONSTRAINT fk_attachments_spec_run_id TO fk_spec_attachments_spec_run_id;
+ALTER INDEX IF EXISTS idx_attachments_spec_run_id RENAME TO idx_spec_attachments_spec_run_id;
+ALTER TABLE attachments RENAME TO spec_attachments;
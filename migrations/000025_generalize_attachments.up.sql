@@ -0,0 +1,45 @@
+-- Generalise spec attachments into attachments of test runs, suites or specs whose content
+-- lives in a blob store. Existing rows keep their content inline (storage_backend 'database').
+ALTER TABLE spec_attachments RENAME TO attachments;
+ALTER INDEX IF EXISTS idx_spec_attachments_spec_run_id RENAME TO idx_attachments_spec_run_id;
+ALTER TABLE attachments RENAME CONSTRAINT fk_spec_attachments_spec_run_id TO fk_attachments_spec_run_id;
+
+ALTER TABLE attachments
+    ADD COLUMN project_id VARCHAR(255),
+    ADD COLUMN test_run_id BIGINT,
+    ADD COLUMN suite_run_id BIGINT,
+    ADD COLUMN checksum_sha256 VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN storage_backend VARCHAR(32) NOT NULL DEFAULT 'database',
+    ADD COLUMN storage_key VARCHAR(1024) NOT NULL DEFAULT '',
+    ALTER COLUMN spec_run_id DROP NOT NULL,
+    ALTER COLUMN content DROP NOT NULL;
+
+UPDATE attachments a
+SET suite_run_id = sp.suite_run_id,
+    test_run_id = su.test_run_id,
+    project_id = tr.project_id,
+    checksum_sha256 = encode(sha256(a.content), 'hex')
+FROM spec_runs sp
+JOIN suite_runs su ON su.id = sp.suite_run_id
+JOIN test_runs tr ON tr.id = su.test_run_id
+WHERE sp.id = a.spec_run_id;
+
+ALTER TABLE attachments
+    ALTER COLUMN project_id SET NOT NULL,
+    ALTER COLUMN test_run_id SET NOT NULL,
+    ADD CONSTRAINT fk_attachments_test_run_id
+        FOREIGN KEY (test_run_id)
+        REFERENCES test_runs(id)
+        ON DELETE CASCADE,
+    ADD CONSTRAINT fk_attachments_suite_run_id
+        FOREIGN KEY (suite_run_id)
+        REFERENCES suite_runs(id)
+        ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_attachments_test_run_id ON attachments(test_run_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_suite_run_id ON attachments(suite_run_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_project_id ON attachments(project_id);
+
+COMMENT ON TABLE attachments IS 'Files captured during test runs (screenshots, videos, logs, ...) linked to a run, suite or spec';
+COMMENT ON COLUMN attachments.storage_key IS 'Key of the content in the blob store named by storage_backend';
+COMMENT ON COLUMN attachments.content IS 'Inline content of attachments kept in the database';
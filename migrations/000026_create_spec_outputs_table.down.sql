@@ -0,0 +1,2 @@
+-- Drop spec_outputs table
+DROP TABLE IF EXISTS spec_outputs CASCADE;
@@ -0,0 +1,27 @@
+-- Create spec_outputs table
+CREATE TABLE IF NOT EXISTS spec_outputs (
+    spec_run_id BIGINT PRIMARY KEY,
+    stdout BYTEA,
+    stderr BYTEA,
+    stdout_bytes BIGINT NOT NULL DEFAULT 0,
+    stderr_bytes BIGINT NOT NULL DEFAULT 0,
+    truncated BOOLEAN NOT NULL DEFAULT FALSE,
+    search_vector TSVECTOR,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT fk_spec_outputs_spec_run_id
+        FOREIGN KEY (spec_run_id)
+        REFERENCES spec_runs(id)
+        ON DELETE CASCADE
+);
+
+-- Full-text search over output, usually limited to the last few days
+CREATE INDEX IF NOT EXISTS idx_spec_outputs_search_vector ON spec_outputs USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_spec_outputs_created_at ON spec_outputs(created_at);
+
+COMMENT ON TABLE spec_outputs IS 'Console output captured for a spec run, kept out of spec_runs so it is only loaded on demand';
+COMMENT ON COLUMN spec_outputs.stdout IS 'Gzip-compressed stdout, truncated to the configured maximum';
+COMMENT ON COLUMN spec_outputs.stderr IS 'Gzip-compressed stderr, truncated to the configured maximum';
+COMMENT ON COLUMN spec_outputs.stdout_bytes IS 'Size of stdout as captured, before truncation';
+COMMENT ON COLUMN spec_outputs.stderr_bytes IS 'Size of stderr as captured, before truncation';
+COMMENT ON COLUMN spec_outputs.search_vector IS 'Lexemes of the start of the output, using the simple configuration so log text is matched as written';
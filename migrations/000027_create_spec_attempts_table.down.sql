@@ -0,0 +1,2 @@
+-- Drop spec_attempts table
+DROP TABLE IF EXISTS spec_attempts CASCADE;
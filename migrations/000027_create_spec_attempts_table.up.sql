@@ -0,0 +1,23 @@
+-- Create spec_attempts table
+CREATE TABLE IF NOT EXISTS spec_attempts (
+    id BIGSERIAL PRIMARY KEY,
+    spec_run_id BIGINT NOT NULL,
+    attempt INTEGER NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    start_time TIMESTAMP WITH TIME ZONE,
+    duration_ms BIGINT DEFAULT 0,
+    error_message TEXT,
+    stack_trace TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT fk_spec_attempts_spec_run_id
+        FOREIGN KEY (spec_run_id)
+        REFERENCES spec_runs(id)
+        ON DELETE CASCADE
+);
+
+-- Attempts are always read in order for one spec
+CREATE UNIQUE INDEX IF NOT EXISTS idx_spec_attempts_spec_run_id_attempt ON spec_attempts(spec_run_id, attempt);
+
+COMMENT ON TABLE spec_attempts IS 'Attempts of a spec retried within a test run (Ginkgo FlakeAttempts, Surefire reruns, Playwright retries)';
+COMMENT ON COLUMN spec_attempts.attempt IS 'Attempt number, starting at 1; the last attempt is the outcome recorded on the spec run';
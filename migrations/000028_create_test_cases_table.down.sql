@@ -0,0 +1,11 @@
+-- Remove test case identity
+DROP INDEX IF EXISTS idx_flaky_tests_test_case_id;
+ALTER TABLE flaky_tests DROP CONSTRAINT IF EXISTS fk_flaky_tests_test_case_id;
+ALTER TABLE flaky_tests DROP COLUMN IF EXISTS test_case_id;
+DELETE FROM flaky_tests;
+ALTER TABLE flaky_tests ADD CONSTRAINT flaky_tests_project_id_test_name_suite_name_key UNIQUE (project_id, test_name, suite_name);
+DROP INDEX IF EXISTS idx_spec_runs_unlinked;
+DROP INDEX IF EXISTS idx_spec_runs_test_case_id;
+ALTER TABLE spec_runs DROP CONSTRAINT IF EXISTS fk_spec_runs_test_case_id;
+ALTER TABLE spec_runs DROP COLUMN IF EXISTS test_case_id;
+DROP TABLE IF EXISTS test_cases;
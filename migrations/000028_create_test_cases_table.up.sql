@@ -0,0 +1,92 @@
+-- Create test_cases table
+CREATE TABLE IF NOT EXISTS test_cases (
+    id BIGSERIAL PRIMARY KEY,
+    project_id VARCHAR(255) NOT NULL,
+    suite_name VARCHAR(255) NOT NULL DEFAULT '',
+    package_name VARCHAR(500) NOT NULL DEFAULT '',
+    class_name VARCHAR(500) NOT NULL DEFAULT '',
+    name TEXT NOT NULL,
+    fingerprint CHAR(64) NOT NULL,
+    first_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_test_cases_project_id_fingerprint ON test_cases(project_id, fingerprint);
+CREATE INDEX IF NOT EXISTS idx_test_cases_project_id_last_seen_at ON test_cases(project_id, last_seen_at DESC);
+
+-- Link every spec run to the test it executed; existing spec runs are linked by
+-- POST /api/v1/admin/tests/backfill
+ALTER TABLE spec_runs ADD COLUMN IF NOT EXISTS test_case_id BIGINT;
+ALTER TABLE spec_runs ADD CONSTRAINT fk_spec_runs_test_case_id
+    FOREIGN KEY (test_case_id)
+    REFERENCES test_cases(id)
+    ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_spec_runs_test_case_id ON spec_runs(test_case_id);
+CREATE INDEX IF NOT EXISTS idx_spec_runs_unlinked ON spec_runs(id) WHERE test_case_id IS NULL;
+
+-- Flaky tests are tracked per test case rather than per test name. Existing rows are linked to
+-- the test case of their project, suite and name, keeping the statuses users set on them.
+-- Spec runs record no package or class, so the test cases are those the backfill finds.
+CREATE OR REPLACE FUNCTION test_case_fingerprint_part(part TEXT) RETURNS TEXT AS $$
+    SELECT lower(btrim(regexp_replace(COALESCE(part, ''), '\s+', ' ', 'g')))
+$$ LANGUAGE SQL IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION test_case_fingerprint(project_id TEXT, suite_name TEXT, test_name TEXT) RETURNS TEXT AS $$
+    SELECT encode(sha256(convert_to(concat_ws(chr(31),
+        test_case_fingerprint_part(project_id),
+        test_case_fingerprint_part(suite_name),
+        '',
+        '',
+        test_case_fingerprint_part(test_name)
+    ), 'UTF8')), 'hex')
+$$ LANGUAGE SQL IMMUTABLE;
+
+ALTER TABLE flaky_tests DROP CONSTRAINT IF EXISTS flaky_tests_project_id_test_name_suite_name_key;
+ALTER TABLE flaky_tests ADD COLUMN IF NOT EXISTS test_case_id BIGINT;
+
+INSERT INTO test_cases (project_id, suite_name, name, fingerprint, first_seen_at, last_seen_at)
+SELECT project_id, MIN(suite_name), MIN(test_name), fingerprint, MIN(first_seen_at), MAX(last_seen_at)
+FROM (
+    SELECT project_id,
+           btrim(COALESCE(suite_name, '')) AS suite_name,
+           btrim(test_name) AS test_name,
+           test_case_fingerprint(project_id, suite_name, test_name) AS fingerprint,
+           first_seen_at,
+           last_seen_at
+    FROM flaky_tests
+) AS flaky
+GROUP BY project_id, fingerprint
+ON CONFLICT (project_id, fingerprint) DO NOTHING;
+
+UPDATE flaky_tests
+SET test_case_id = test_cases.id
+FROM test_cases
+WHERE test_cases.project_id = flaky_tests.project_id
+  AND test_cases.fingerprint = test_case_fingerprint(flaky_tests.project_id, flaky_tests.suite_name, flaky_tests.test_name);
+
+-- Only rows that cannot be linked are dropped: names differing only in case or whitespace are
+-- now one test, which keeps its most recently seen row
+DELETE FROM flaky_tests
+WHERE test_case_id IS NULL
+   OR id IN (
+       SELECT id FROM (
+           SELECT id, ROW_NUMBER() OVER (PARTITION BY test_case_id ORDER BY last_seen_at DESC, id DESC) AS position
+           FROM flaky_tests
+       ) AS ranked
+       WHERE position > 1
+   );
+
+DROP FUNCTION test_case_fingerprint(TEXT, TEXT, TEXT);
+DROP FUNCTION test_case_fingerprint_part(TEXT);
+
+ALTER TABLE flaky_tests ALTER COLUMN test_case_id SET NOT NULL;
+ALTER TABLE flaky_tests ADD CONSTRAINT fk_flaky_tests_test_case_id
+    FOREIGN KEY (test_case_id)
+    REFERENCES test_cases(id)
+    ON DELETE CASCADE;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_flaky_tests_test_case_id ON flaky_tests(test_case_id);
+
+COMMENT ON TABLE test_cases IS 'Stable identity of a test across runs, branches and environments';
+COMMENT ON COLUMN test_cases.fingerprint IS 'SHA-256 of the project, suite, package, class and name, lower-cased with whitespace collapsed';
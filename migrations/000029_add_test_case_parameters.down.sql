@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_test_cases_parent_id;
+ALTER TABLE test_cases DROP CONSTRAINT IF EXISTS fk_test_cases_parent_id;
+ALTER TABLE test_cases DROP COLUMN IF EXISTS parameters;
+ALTER TABLE test_cases DROP COLUMN IF EXISTS parent_id;
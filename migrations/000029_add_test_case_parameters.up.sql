@@ -0,0 +1,12 @@
+-- Parameterised and table-driven tests: each parameter set is a test case of its own, linked to
+-- the test case of the test as a whole
+ALTER TABLE test_cases ADD COLUMN IF NOT EXISTS parent_id BIGINT;
+ALTER TABLE test_cases ADD COLUMN IF NOT EXISTS parameters JSONB;
+ALTER TABLE test_cases ADD CONSTRAINT fk_test_cases_parent_id
+    FOREIGN KEY (parent_id)
+    REFERENCES test_cases(id)
+    ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_test_cases_parent_id ON test_cases(parent_id) WHERE parent_id IS NOT NULL;
+
+COMMENT ON COLUMN test_cases.parent_id IS 'Test case of the parameterised test this is one parameter set of';
+COMMENT ON COLUMN test_cases.parameters IS 'Parameter set of a parameterised test, by parameter name';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_test_runs_last_heartbeat_at;
+ALTER TABLE test_runs DROP COLUMN IF EXISTS last_heartbeat_at;
@@ -0,0 +1,7 @@
+-- Streamed runs: results arrive while the run is in progress and the reporter sends heartbeats.
+-- Running streamed runs that stop sending heartbeats are aborted.
+ALTER TABLE test_runs ADD COLUMN IF NOT EXISTS last_heartbeat_at TIMESTAMP WITH TIME ZONE;
+CREATE INDEX IF NOT EXISTS idx_test_runs_last_heartbeat_at ON test_runs(last_heartbeat_at)
+    WHERE status = 'running' AND last_heartbeat_at IS NOT NULL;
+
+COMMENT ON COLUMN test_runs.last_heartbeat_at IS 'When the reporter of a streamed run was last heard from, NULL for runs that were not streamed';
@@ -0,0 +1 @@
+ALTER TABLE flaky_tests DROP COLUMN IF EXISTS flip_evidence;
@@ -0,0 +1,5 @@
+-- Same-commit flip detection: a test that both passed and failed on one commit, or within one
+-- run, is flaky. The conflicting runs are kept as evidence.
+ALTER TABLE flaky_tests ADD COLUMN IF NOT EXISTS flip_evidence JSONB;
+
+COMMENT ON COLUMN flaky_tests.flip_evidence IS 'Commits the test both passed and failed on, with the test runs of each outcome';
@@ -0,0 +1,2 @@
+-- Drop flaky_test_snapshots table
+DROP TABLE IF EXISTS flaky_test_snapshots CASCADE;
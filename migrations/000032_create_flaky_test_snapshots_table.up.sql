@@ -0,0 +1,18 @@
+-- Create flaky_test_snapshots table
+CREATE TABLE IF NOT EXISTS flaky_test_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    project_id VARCHAR(255) NOT NULL,
+    date DATE NOT NULL,
+    active_count INTEGER NOT NULL DEFAULT 0,
+    new_count INTEGER NOT NULL DEFAULT 0,
+    resolved_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- One snapshot per project and day, read by date range
+CREATE UNIQUE INDEX IF NOT EXISTS idx_flaky_test_snapshots_project_id_date ON flaky_test_snapshots(project_id, date);
+
+COMMENT ON TABLE flaky_test_snapshots IS 'Daily flaky test counts of a project, updated by every analysis and rebuilt by backfills';
+COMMENT ON COLUMN flaky_test_snapshots.date IS 'Day of the snapshot, in UTC';
+COMMENT ON COLUMN flaky_test_snapshots.active_count IS 'Active flaky tests as of the last analysis of the day';
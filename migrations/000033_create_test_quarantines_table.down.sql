@@ -0,0 +1,5 @@
+-- Remove test quarantines
+ALTER TABLE test_runs DROP COLUMN IF EXISTS quarantined_tests;
+ALTER TABLE suite_runs DROP COLUMN IF EXISTS quarantined_specs;
+ALTER TABLE spec_runs DROP COLUMN IF EXISTS quarantined;
+DROP TABLE IF EXISTS test_quarantines CASCADE;
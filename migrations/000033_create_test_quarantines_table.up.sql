@@ -0,0 +1,35 @@
+-- Create test_quarantines table
+CREATE TABLE IF NOT EXISTS test_quarantines (
+    id BIGSERIAL PRIMARY KEY,
+    project_id VARCHAR(255) NOT NULL,
+    test_case_id BIGINT NOT NULL,
+    reason TEXT NOT NULL,
+    owner VARCHAR(255) NOT NULL DEFAULT '',
+    jira_issue VARCHAR(255) NOT NULL DEFAULT '',
+    created_by VARCHAR(255) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    lifted_at TIMESTAMP WITH TIME ZONE,
+    lifted_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT fk_test_quarantines_test_case_id
+        FOREIGN KEY (test_case_id)
+        REFERENCES test_cases(id)
+        ON DELETE CASCADE
+);
+
+-- CI fetches the quarantines of a project that are neither lifted nor expired
+CREATE INDEX IF NOT EXISTS idx_test_quarantines_project_id_expires_at ON test_quarantines(project_id, expires_at)
+    WHERE lifted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_test_quarantines_test_case_id ON test_quarantines(test_case_id);
+
+-- Failures of quarantined tests are ingested but left out of pass rates
+ALTER TABLE spec_runs ADD COLUMN IF NOT EXISTS quarantined BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE suite_runs ADD COLUMN IF NOT EXISTS quarantined_specs INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE test_runs ADD COLUMN IF NOT EXISTS quarantined_tests INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON TABLE test_quarantines IS 'Flaky tests that CI skips or soft-fails until the quarantine expires or is lifted';
+COMMENT ON COLUMN test_quarantines.jira_issue IS 'Key of the Jira issue tracking the fix, e.g. PAY-123';
+COMMENT ON COLUMN spec_runs.quarantined IS 'The spec failed while its test, or the test it is a parameter set of, was quarantined';
+COMMENT ON COLUMN test_runs.quarantined_tests IS 'Failed tests that were quarantined when they ran, included in failed_tests';
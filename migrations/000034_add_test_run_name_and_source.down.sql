@@ -0,0 +1,2 @@
+ALTER TABLE test_runs DROP COLUMN IF EXISTS source;
+ALTER TABLE test_runs DROP COLUMN IF EXISTS name;
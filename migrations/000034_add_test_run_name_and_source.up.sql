@@ -0,0 +1,6 @@
+-- A run re-ingested under its run ID replaces the name and source of the earlier report
+ALTER TABLE test_runs ADD COLUMN IF NOT EXISTS name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE test_runs ADD COLUMN IF NOT EXISTS source VARCHAR(255) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN test_runs.name IS 'Display name of the run given by the reporter';
+COMMENT ON COLUMN test_runs.source IS 'Reporter or client that submitted the run, e.g. fern-ginkgo-client';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_ingestion_jobs_completed_at;
+UPDATE ingestion_jobs SET payload = '' WHERE payload IS NULL;
+ALTER TABLE ingestion_jobs ALTER COLUMN payload SET NOT NULL;
@@ -0,0 +1,5 @@
+-- Succeeded jobs no longer keep the report they were queued with, and finished jobs are
+-- purged once they are older than ingestion.jobRetention
+ALTER TABLE ingestion_jobs ALTER COLUMN payload DROP NOT NULL;
+UPDATE ingestion_jobs SET payload = NULL WHERE status = 'succeeded';
+CREATE INDEX IF NOT EXISTS idx_ingestion_jobs_completed_at ON ingestion_jobs(completed_at) WHERE completed_at IS NOT NULL;
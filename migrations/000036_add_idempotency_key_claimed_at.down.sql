@@ -0,0 +1 @@
+ALTER TABLE ingestion_idempotency_keys DROP COLUMN IF EXISTS claimed_at;
@@ -0,0 +1,6 @@
+-- A key claimed by a request that never completed or released it, e.g. because its server
+-- crashed, may be claimed again once its lease from claimed_at has run out
+ALTER TABLE ingestion_idempotency_keys ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+UPDATE ingestion_idempotency_keys SET claimed_at = created_at WHERE created_at IS NOT NULL;
+
+COMMENT ON COLUMN ingestion_idempotency_keys.claimed_at IS 'When the request processing the key claimed it';
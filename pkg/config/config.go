@@ -20,6 +20,8 @@ type Config struct {
 	Redis      RedisConfig      `mapstructure:"redis"`
 	LLM        LLMConfig        `mapstructure:"llm"`
 	Monitoring MonitoringConfig `mapstructure:"monitoring"`
+	Ingestion  IngestionConfig  `mapstructure:"ingestion"`
+	Storage    StorageConfig    `mapstructure:"storage"`
 }
 
 type ServerConfig struct {
@@ -62,6 +64,25 @@ type AuthConfig struct {
 	TokenExpiry   time.Duration `mapstructure:"tokenExpiry"`
 	RefreshExpiry time.Duration `mapstructure:"refreshExpiry"`
 	OAuth         OAuthConfig   `mapstructure:"oauth"`
+	CIOIDC        CIOIDCConfig  `mapstructure:"ciOidc"`
+}
+
+// CIOIDCConfig configures the CI systems whose OIDC ID tokens are accepted for test report ingestion
+type CIOIDCConfig struct {
+	Issuers []CIOIDCIssuerConfig `mapstructure:"issuers"`
+}
+
+// CIOIDCIssuerConfig configures a trusted CI token issuer such as GitHub Actions or GitLab
+type CIOIDCIssuerConfig struct {
+	Name      string `mapstructure:"name"`      // Name referenced by trust policies, e.g. "github-actions"
+	IssuerURL string `mapstructure:"issuerUrl"` // Expected "iss" claim
+	JWKSUrl   string `mapstructure:"jwksUrl"`   // JWKS endpoint (default: discovered from the issuer)
+	Audience  string `mapstructure:"audience"`  // Expected "aud" claim
+
+	// Token claim field mappings (customize based on your CI system)
+	RepositoryClaim string `mapstructure:"repositoryClaim"` // Claim naming the repository (default: "repository")
+	RefClaim        string `mapstructure:"refClaim"`        // Claim naming the git ref (default: "ref")
+	WorkflowClaim   string `mapstructure:"workflowClaim"`   // Claim naming the workflow (default: "workflow")
 }
 
 type OAuthConfig struct {
@@ -143,6 +164,52 @@ type LLMProvider struct {
 	Enabled bool   `mapstructure:"enabled"`
 }
 
+// IngestionConfig configures the asynchronous ingestion queue and its worker pool
+type IngestionConfig struct {
+	Workers         int           `mapstructure:"workers"`
+	PollInterval    time.Duration `mapstructure:"pollInterval"`
+	QueueCapacity   int           `mapstructure:"queueCapacity"`
+	MaxAttempts     int           `mapstructure:"maxAttempts"`
+	RetryBackoff    time.Duration `mapstructure:"retryBackoff"`
+	MaxRetryBackoff time.Duration `mapstructure:"maxRetryBackoff"`
+	LeaseDuration   time.Duration `mapstructure:"leaseDuration"`
+	ShardTimeout    time.Duration `mapstructure:"shardTimeout"`
+	// JobRetention is how long finished ingestion jobs are kept before they are purged
+	JobRetention time.Duration `mapstructure:"jobRetention"`
+	// HeartbeatTimeout is how long a streamed run may go without hearing from its reporter
+	// before it is aborted
+	HeartbeatTimeout time.Duration `mapstructure:"heartbeatTimeout"`
+	// MaxSpecOutputSize is how many bytes of each spec's stdout and stderr are kept
+	MaxSpecOutputSize int `mapstructure:"maxSpecOutputSize"`
+}
+
+// StorageConfig configures where test attachments are stored and how they are served
+type StorageConfig struct {
+	Backend           string             `mapstructure:"backend"` // "local" or "s3"
+	Local             LocalStorageConfig `mapstructure:"local"`
+	S3                S3StorageConfig    `mapstructure:"s3"`
+	MaxAttachmentSize int64              `mapstructure:"maxAttachmentSize"` // Largest single attachment in bytes
+	ProjectQuota      int64              `mapstructure:"projectQuota"`      // Default attachment bytes per project, 0 for unlimited
+	SigningKey        string             `mapstructure:"signingKey"`        // Key for signed download URLs (default: random per process)
+	URLExpiry         time.Duration      `mapstructure:"urlExpiry"`         // Lifetime of signed download URLs
+}
+
+// LocalStorageConfig configures the local filesystem attachment store
+type LocalStorageConfig struct {
+	Path string `mapstructure:"path"`
+}
+
+// S3StorageConfig configures an S3-compatible attachment store such as AWS S3 or MinIO
+type S3StorageConfig struct {
+	Endpoint        string `mapstructure:"endpoint"`
+	Region          string `mapstructure:"region"`
+	Bucket          string `mapstructure:"bucket"`
+	AccessKeyID     string `mapstructure:"accessKeyId"`
+	SecretAccessKey string `mapstructure:"secretAccessKey"`
+	Prefix          string `mapstructure:"prefix"`
+	PathStyle       bool   `mapstructure:"pathStyle"` // Required by most S3 stand-ins such as MinIO
+}
+
 type MonitoringConfig struct {
 	Metrics MetricsConfig `mapstructure:"metrics"`
 	Tracing TracingConfig `mapstructure:"tracing"`
@@ -297,6 +364,27 @@ func (m *Manager) setDefaults() {
 	viper.SetDefault("monitoring.health.path", "/health")
 	viper.SetDefault("monitoring.health.interval", "30s")
 	viper.SetDefault("monitoring.health.timeout", "5s")
+
+	// Ingestion defaults
+	viper.SetDefault("ingestion.workers", 4)
+	viper.SetDefault("ingestion.pollInterval", "1s")
+	viper.SetDefault("ingestion.queueCapacity", 1000)
+	viper.SetDefault("ingestion.maxAttempts", 5)
+	viper.SetDefault("ingestion.retryBackoff", "5s")
+	viper.SetDefault("ingestion.maxRetryBackoff", "10m")
+	viper.SetDefault("ingestion.leaseDuration", "10m")
+	viper.SetDefault("ingestion.shardTimeout", "2h")
+	viper.SetDefault("ingestion.jobRetention", "168h")
+	viper.SetDefault("ingestion.heartbeatTimeout", "5m")
+	viper.SetDefault("ingestion.maxSpecOutputSize", 1048576) // 1 MiB
+
+	// Attachment storage defaults
+	viper.SetDefault("storage.backend", "local")
+	viper.SetDefault("storage.local.path", "./data/attachments")
+	viper.SetDefault("storage.s3.region", "us-east-1")
+	viper.SetDefault("storage.maxAttachmentSize", 50<<20)
+	viper.SetDefault("storage.projectQuota", 5<<30)
+	viper.SetDefault("storage.urlExpiry", "15m")
 }
 
 func (m *Manager) bindEnvVars() error {
@@ -440,6 +528,40 @@ func (m *Manager) bindEnvVars() error {
 	if err := viper.BindEnv("logging.format", "LOG_FORMAT"); err != nil {
 		return err
 	}
+
+	// Ingestion
+	if err := viper.BindEnv("ingestion.workers", "INGESTION_WORKERS"); err != nil {
+		return err
+	}
+	if err := viper.BindEnv("ingestion.queueCapacity", "INGESTION_QUEUE_CAPACITY"); err != nil {
+		return err
+	}
+
+	// Attachment storage
+	if err := viper.BindEnv("storage.backend", "STORAGE_BACKEND"); err != nil {
+		return err
+	}
+	if err := viper.BindEnv("storage.local.path", "STORAGE_LOCAL_PATH"); err != nil {
+		return err
+	}
+	if err := viper.BindEnv("storage.s3.endpoint", "S3_ENDPOINT"); err != nil {
+		return err
+	}
+	if err := viper.BindEnv("storage.s3.region", "S3_REGION"); err != nil {
+		return err
+	}
+	if err := viper.BindEnv("storage.s3.bucket", "S3_BUCKET"); err != nil {
+		return err
+	}
+	if err := viper.BindEnv("storage.s3.accessKeyId", "S3_ACCESS_KEY_ID", "AWS_ACCESS_KEY_ID"); err != nil {
+		return err
+	}
+	if err := viper.BindEnv("storage.s3.secretAccessKey", "S3_SECRET_ACCESS_KEY", "AWS_SECRET_ACCESS_KEY"); err != nil {
+		return err
+	}
+	if err := viper.BindEnv("storage.signingKey", "STORAGE_SIGNING_KEY"); err != nil {
+		return err
+	}
 	
 	return nil
 }
@@ -481,6 +603,34 @@ func (m *Manager) validate(config *Config) error {
 				return fmt.Errorf("auth is enabled but no JWT secret or JWKS URL provided")
 			}
 		}
+
+		names := make(map[string]bool)
+		for _, issuer := range config.Auth.CIOIDC.Issuers {
+			if issuer.Name == "" || issuer.IssuerURL == "" {
+				return fmt.Errorf("CI OIDC issuers need a name and issuer URL")
+			}
+			if issuer.Audience == "" {
+				return fmt.Errorf("CI OIDC issuer %s has no audience", issuer.Name)
+			}
+			if names[issuer.Name] {
+				return fmt.Errorf("CI OIDC issuer %s is configured more than once", issuer.Name)
+			}
+			names[issuer.Name] = true
+		}
+	}
+
+	// Attachment storage validation
+	switch config.Storage.Backend {
+	case "local":
+		if config.Storage.Local.Path == "" {
+			return fmt.Errorf("local attachment storage needs a path")
+		}
+	case "s3":
+		if config.Storage.S3.Bucket == "" {
+			return fmt.Errorf("s3 attachment storage needs a bucket")
+		}
+	default:
+		return fmt.Errorf("unknown attachment storage backend %q", config.Storage.Backend)
 	}
 
 	return nil
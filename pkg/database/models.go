@@ -87,6 +87,16 @@ type TestRun struct {
 	Tags         []Tag      `gorm:"many2many:test_run_tags;" json:"tags"`
 	SuiteRuns    []SuiteRun `gorm:"foreignKey:TestRunID" json:"suite_runs,omitempty"`
 	Metadata     JSONMap    `gorm:"type:jsonb" json:"metadata,omitempty"`
+	// ShardTotal is the number of CI shards merged into this run, or 0 if it isn't sharded
+	ShardTotal     int `gorm:"not null;default:0" json:"shard_total"`
+	ShardsReceived int `gorm:"not null;default:0" json:"shards_received"`
+	// LastHeartbeatAt is when the reporter of a streamed run was last heard from
+	LastHeartbeatAt *time.Time `json:"last_heartbeat_at,omitempty"`
+	// QuarantinedTests counts the failed tests that were quarantined when they ran
+	QuarantinedTests int `gorm:"not null;default:0" json:"quarantined_tests"`
+	// Name and Source are the run's display name and the reporter that submitted it
+	Name   string `gorm:"not null;default:''" json:"name"`
+	Source string `gorm:"not null;default:''" json:"source"`
 }
 
 // SuiteRun represents a test suite execution within a test run
@@ -102,22 +112,117 @@ type SuiteRun struct {
 	FailedSpecs  int        `json:"failed_specs"`
 	SkippedSpecs int        `json:"skipped_specs"`
 	Duration     int64      `gorm:"column:duration_ms" json:"duration_ms"`
+	ShardIndex   *int       `gorm:"index:idx_suite_runs_test_run_id_shard_index" json:"shard_index,omitempty"`
 	SpecRuns     []SpecRun  `gorm:"foreignKey:SuiteRunID" json:"spec_runs,omitempty"`
+	// QuarantinedSpecs counts the failed specs that were quarantined when they ran
+	QuarantinedSpecs int `gorm:"not null;default:0" json:"quarantined_specs"`
+}
+
+// TestRunShard records one shard received for a test run split across parallel CI jobs
+type TestRunShard struct {
+	TestRunID    uint       `gorm:"primaryKey" json:"test_run_id"`
+	ShardIndex   int        `gorm:"primaryKey" json:"shard_index"`
+	Status       string     `gorm:"not null" json:"status"`
+	StartTime    time.Time  `json:"start_time"`
+	EndTime      *time.Time `json:"end_time,omitempty"`
+	TotalTests   int        `json:"total_tests"`
+	PassedTests  int        `json:"passed_tests"`
+	FailedTests  int        `json:"failed_tests"`
+	SkippedTests int        `json:"skipped_tests"`
+	ReceivedAt   time.Time  `json:"received_at"`
 }
 
 // SpecRun represents an individual test spec execution
 type SpecRun struct {
 	BaseModel
-	SuiteRunID   uint       `gorm:"not null;index" json:"suite_run_id"`
-	SpecName     string     `gorm:"not null;index" json:"spec_name"`
-	Status       string     `gorm:"index" json:"status"`
-	StartTime    time.Time  `json:"start_time"`
-	EndTime      *time.Time `json:"end_time,omitempty"`
-	Duration     int64      `gorm:"column:duration_ms" json:"duration_ms"`
-	ErrorMessage string     `gorm:"type:text" json:"error_message,omitempty"`
-	StackTrace   string     `gorm:"type:text" json:"stack_trace,omitempty"`
-	RetryCount   int        `json:"retry_count"`
-	IsFlaky      bool       `gorm:"index" json:"is_flaky"`
+	SuiteRunID   uint          `gorm:"not null;index" json:"suite_run_id"`
+	TestCaseID   *uint         `gorm:"index" json:"test_case_id,omitempty"`
+	SpecName     string        `gorm:"not null;index" json:"spec_name"`
+	Status       string        `gorm:"index" json:"status"`
+	StartTime    time.Time     `json:"start_time"`
+	EndTime      *time.Time    `json:"end_time,omitempty"`
+	Duration     int64         `gorm:"column:duration_ms" json:"duration_ms"`
+	ErrorMessage string        `gorm:"type:text" json:"error_message,omitempty"`
+	StackTrace   string        `gorm:"type:text" json:"stack_trace,omitempty"`
+	RetryCount   int           `json:"retry_count"`
+	IsFlaky      bool          `gorm:"index" json:"is_flaky"`
+	Quarantined  bool          `gorm:"not null;default:false" json:"quarantined"`
+	Steps        []SpecStep    `gorm:"foreignKey:SpecRunID" json:"steps,omitempty"`
+	Attempts     []SpecAttempt `gorm:"foreignKey:SpecRunID" json:"attempts,omitempty"`
+	Attachments  []Attachment  `gorm:"foreignKey:SpecRunID" json:"attachments,omitempty"`
+}
+
+// TestCase is the stable identity of a test that spec runs of it link to
+type TestCase struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	ProjectID   string    `gorm:"not null;uniqueIndex:idx_test_cases_project_id_fingerprint" json:"project_id"`
+	ParentID    *uint     `gorm:"index" json:"parent_id,omitempty"`
+	SuiteName   string    `gorm:"not null;default:''" json:"suite_name"`
+	PackageName string    `gorm:"not null;default:''" json:"package_name"`
+	ClassName   string    `gorm:"not null;default:''" json:"class_name"`
+	Name        string    `gorm:"type:text;not null" json:"name"`
+	Parameters  JSONMap   `gorm:"type:jsonb" json:"parameters,omitempty"`
+	Fingerprint string    `gorm:"not null;uniqueIndex:idx_test_cases_project_id_fingerprint" json:"fingerprint"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SpecStep represents one step of a BDD scenario recorded for a spec run
+type SpecStep struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	SpecRunID    uint      `gorm:"not null;index:idx_spec_steps_spec_run_id_position" json:"spec_run_id"`
+	Position     int       `gorm:"not null;index:idx_spec_steps_spec_run_id_position" json:"position"`
+	Keyword      string    `gorm:"not null" json:"keyword"`
+	Name         string    `gorm:"type:text;not null" json:"name"`
+	Status       string    `gorm:"not null" json:"status"`
+	Duration     int64     `gorm:"column:duration_ms" json:"duration_ms"`
+	ErrorMessage string    `gorm:"type:text" json:"error_message,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// SpecAttempt records one attempt at a spec that was retried within its test run
+type SpecAttempt struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	SpecRunID    uint      `gorm:"not null;uniqueIndex:idx_spec_attempts_spec_run_id_attempt" json:"spec_run_id"`
+	Attempt      int       `gorm:"not null;uniqueIndex:idx_spec_attempts_spec_run_id_attempt" json:"attempt"`
+	Status       string    `gorm:"not null" json:"status"`
+	StartTime    time.Time `json:"start_time"`
+	Duration     int64     `gorm:"column:duration_ms" json:"duration_ms"`
+	ErrorMessage string    `gorm:"type:text" json:"error_message,omitempty"`
+	StackTrace   string    `gorm:"type:text" json:"stack_trace,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Attachment stores a file captured during a test run, such as a screenshot or a log.
+// Its content lives in the blob store named by StorageBackend, or inline in Content.
+type Attachment struct {
+	ID             uint      `gorm:"primarykey" json:"id"`
+	ProjectID      string    `gorm:"not null;index" json:"project_id"`
+	TestRunID      uint      `gorm:"not null;index" json:"test_run_id"`
+	SuiteRunID     *uint     `gorm:"index" json:"suite_run_id,omitempty"`
+	SpecRunID      *uint     `gorm:"index" json:"spec_run_id,omitempty"`
+	Name           string    `gorm:"not null" json:"name"`
+	ContentType    string    `gorm:"not null" json:"content_type"`
+	Size           int64     `gorm:"column:size_bytes" json:"size_bytes"`
+	SHA256         string    `gorm:"column:checksum_sha256" json:"checksum_sha256"`
+	StorageBackend string    `gorm:"not null" json:"storage_backend"`
+	StorageKey     string    `json:"-"`
+	Content        []byte    `json:"-"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// SpecOutput stores the gzip-compressed console output captured for a spec run. Its
+// search_vector column is written alongside it and is not mapped.
+type SpecOutput struct {
+	SpecRunID   uint      `gorm:"primaryKey;autoIncrement:false" json:"spec_run_id"`
+	Stdout      []byte    `json:"-"`
+	Stderr      []byte    `json:"-"`
+	StdoutBytes int64     `gorm:"not null;default:0" json:"stdout_bytes"`
+	StderrBytes int64     `gorm:"not null;default:0" json:"stderr_bytes"`
+	Truncated   bool      `gorm:"not null;default:false" json:"truncated"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 // Tag represents a test run tag for categorization
@@ -194,13 +299,54 @@ type TestRunTag struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// IngestionIdempotencyKey records the outcome of an ingestion request submitted with an Idempotency-Key
+type IngestionIdempotencyKey struct {
+	ID             uint      `gorm:"primarykey" json:"id"`
+	ProjectID      string    `gorm:"not null;uniqueIndex:idx_ingestion_idempotency_project_key" json:"project_id"`
+	IdempotencyKey string    `gorm:"not null;uniqueIndex:idx_ingestion_idempotency_project_key" json:"idempotency_key"`
+	RequestHash    string    `gorm:"not null" json:"request_hash"`
+	TestRunID      *uint     `json:"test_run_id,omitempty"`
+	ResponseStatus int       `gorm:"not null;default:0" json:"response_status"` // 0 while the request is in progress
+	ResponseBody   []byte    `json:"response_body,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	ClaimedAt      time.Time `gorm:"not null" json:"claimed_at"`
+	ExpiresAt      time.Time `gorm:"not null;index" json:"expires_at"`
+}
+
+// IngestionJob is a test report queued for asynchronous ingestion
+type IngestionJob struct {
+	ID             string          `gorm:"type:uuid;primaryKey" json:"id"`
+	ProjectID      string          `gorm:"not null;index" json:"project_id"`
+	Format         string          `gorm:"not null" json:"format"`
+	Payload        []byte          `json:"-"` // Cleared once the job has succeeded
+	RunID          string          `json:"run_id,omitempty"`
+	Branch         string          `json:"branch,omitempty"`
+	GitCommit      string          `json:"git_commit,omitempty"`
+	Environment    string          `json:"environment,omitempty"`
+	GroupID        string          `json:"group_id,omitempty"`
+	ShardIndex     *int            `json:"shard_index,omitempty"`
+	ShardTotal     *int            `json:"shard_total,omitempty"`
+	Status         string          `gorm:"not null;default:'queued';index:idx_ingestion_jobs_status_next_attempt_at" json:"status"`
+	Attempts       int             `gorm:"not null;default:0" json:"attempts"`
+	MaxAttempts    int             `gorm:"not null" json:"max_attempts"`
+	Errors         json.RawMessage `gorm:"type:jsonb" json:"errors,omitempty"`
+	TestRunID      *uint           `json:"test_run_id,omitempty"`
+	NextAttemptAt  time.Time       `gorm:"not null;index:idx_ingestion_jobs_status_next_attempt_at" json:"next_attempt_at"`
+	LeaseExpiresAt *time.Time      `json:"lease_expires_at,omitempty"`
+	StartedAt      *time.Time      `json:"started_at,omitempty"`
+	CompletedAt    *time.Time      `json:"completed_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
 // FlakyTest represents test flakiness analysis data
 type FlakyTest struct {
 	BaseModel
 	ProjectID        string    `gorm:"not null;index" json:"project_id"`
+	TestCaseID       uint      `gorm:"not null;uniqueIndex" json:"test_case_id"`
 	TestName         string    `gorm:"not null;index" json:"test_name"`
 	SuiteName        string    `gorm:"index" json:"suite_name"`
-	FlakeRate        float64   `json:"flake_rate"` // Percentage of flaky executions
+	FlakeRate        float64   `json:"flake_rate"` // Flake score, from 0 to 1
 	TotalExecutions  int       `json:"total_executions"`
 	FlakyExecutions  int       `json:"flaky_executions"`
 	LastSeenAt       time.Time `json:"last_seen_at"`
@@ -208,24 +354,56 @@ type FlakyTest struct {
 	Status           string    `gorm:"default:'active'" json:"status"`
 	Severity         string    `json:"severity"` // low, medium, high, critical
 	LastErrorMessage string    `gorm:"type:text" json:"last_error_message,omitempty"`
+	// FlipEvidence lists the commits and runs the test both passed and failed on
+	FlipEvidence json.RawMessage `gorm:"type:jsonb" json:"flip_evidence,omitempty"`
+}
+
+// FlakyTestSnapshot records a project's flaky test counts for one day
+type FlakyTestSnapshot struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	ProjectID     string    `gorm:"not null;uniqueIndex:idx_flaky_test_snapshots_project_id_date" json:"project_id"`
+	Date          time.Time `gorm:"type:date;not null;uniqueIndex:idx_flaky_test_snapshots_project_id_date" json:"date"`
+	ActiveCount   int       `gorm:"not null;default:0" json:"active_count"`
+	NewCount      int       `gorm:"not null;default:0" json:"new_count"`
+	ResolvedCount int       `gorm:"not null;default:0" json:"resolved_count"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// TestQuarantine keeps a flaky test from failing its runs until it expires or is lifted
+type TestQuarantine struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	ProjectID  string     `gorm:"not null;index" json:"project_id"`
+	TestCaseID uint       `gorm:"not null;index" json:"test_case_id"`
+	TestCase   *TestCase  `gorm:"foreignKey:TestCaseID" json:"test_case,omitempty"`
+	Reason     string     `gorm:"type:text;not null" json:"reason"`
+	Owner      string     `gorm:"not null;default:''" json:"owner"`
+	JiraIssue  string     `gorm:"not null;default:''" json:"jira_issue"`
+	CreatedBy  string     `gorm:"not null;default:''" json:"created_by"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	LiftedAt   *time.Time `json:"lifted_at,omitempty"`
+	LiftedBy   string     `gorm:"not null;default:''" json:"lifted_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
 }
 
 // User represents a system user with OAuth authentication
 type User struct {
 	BaseModel
-	UserID        string          `gorm:"uniqueIndex;not null" json:"user_id"`  // OAuth provider user ID
-	Email         string          `gorm:"uniqueIndex;not null" json:"email"`    // User email
-	Name          string          `gorm:"not null" json:"name"`                 // Display name
-	Role          string          `gorm:"default:'user';index" json:"role"`     // user, admin
-	Status        string          `gorm:"default:'active';index" json:"status"` // active, suspended, inactive
-	LastLoginAt   *time.Time      `json:"last_login_at,omitempty"`
-	ProfileURL    string          `json:"profile_url,omitempty"`               // Avatar/profile picture URL
-	FirstName     string          `json:"first_name,omitempty"`                // First name from OAuth
-	LastName      string          `json:"last_name,omitempty"`                 // Last name from OAuth
-	EmailVerified bool            `gorm:"default:false" json:"email_verified"` // Email verification status
-	ProjectAccess []ProjectAccess `gorm:"foreignKey:UserID;references:UserID" json:"project_access,omitempty"`
-	UserGroups    []UserGroup     `gorm:"foreignKey:UserID;references:UserID" json:"user_groups,omitempty"`
-	UserScopes    []UserScope     `gorm:"foreignKey:UserID;references:UserID" json:"user_scopes,omitempty"`
+	UserID         string          `gorm:"uniqueIndex;not null" json:"user_id"`  // OAuth provider user ID
+	Email          string          `gorm:"uniqueIndex;not null" json:"email"`    // User email
+	Name           string          `gorm:"not null" json:"name"`                 // Display name
+	Role           string          `gorm:"default:'user';index" json:"role"`     // user, admin
+	Status         string          `gorm:"default:'active';index" json:"status"` // active, suspended, inactive
+	LastLoginAt    *time.Time      `json:"last_login_at,omitempty"`
+	ProfileURL     string          `json:"profile_url,omitempty"`                // Avatar/profile picture URL
+	FirstName      string          `json:"first_name,omitempty"`                 // First name from OAuth
+	LastName       string          `json:"last_name,omitempty"`                  // Last name from OAuth
+	EmailVerified  bool            `gorm:"default:false" json:"email_verified"`  // Email verification status
+	ServiceAccount bool            `gorm:"default:false" json:"service_account"` // Non-human account that authenticates with access tokens
+	ProjectAccess  []ProjectAccess `gorm:"foreignKey:UserID;references:UserID" json:"project_access,omitempty"`
+	UserGroups     []UserGroup     `gorm:"foreignKey:UserID;references:UserID" json:"user_groups,omitempty"`
+	UserScopes     []UserScope     `gorm:"foreignKey:UserID;references:UserID" json:"user_scopes,omitempty"`
 }
 
 // UserGroup represents a user's group membership
@@ -250,6 +428,52 @@ type UserSession struct {
 	LastActivity time.Time `gorm:"index" json:"last_activity"`
 }
 
+// IngestionToken is a project-scoped bearer token for submitting test reports.
+// Only a hash of the token is stored.
+type IngestionToken struct {
+	ID          string     `gorm:"type:uuid;primaryKey" json:"id"`
+	ProjectID   string     `gorm:"not null;index" json:"project_id"`
+	Name        string     `gorm:"not null" json:"name"`
+	TokenPrefix string     `gorm:"not null" json:"token_prefix"`
+	TokenHash   string     `gorm:"uniqueIndex;not null" json:"-"`
+	CreatedBy   string     `json:"created_by,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// AccessToken represents a personal access token or service account token. Scopes holds the
+// token's space-separated scopes.
+type AccessToken struct {
+	ID          string     `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID      string     `gorm:"not null;index" json:"user_id"`
+	Name        string     `gorm:"not null" json:"name"`
+	TokenPrefix string     `gorm:"not null" json:"token_prefix"`
+	TokenHash   string     `gorm:"uniqueIndex;not null" json:"-"`
+	Scopes      string     `gorm:"not null;default:''" json:"scopes"`
+	CreatedBy   string     `json:"created_by,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// CITrustPolicy maps claims of CI OIDC tokens to permission to upload test reports to a project
+type CITrustPolicy struct {
+	ID         string    `gorm:"type:uuid;primaryKey" json:"id"`
+	ProjectID  string    `gorm:"not null;index" json:"project_id"`
+	Issuer     string    `gorm:"not null;index" json:"issuer"`
+	Repository string    `gorm:"not null" json:"repository"`
+	Ref        string    `gorm:"not null;default:''" json:"ref"`
+	Workflow   string    `gorm:"not null;default:''" json:"workflow"`
+	CreatedBy  string    `json:"created_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
 // ProjectAccess represents user access permissions for specific projects
 type ProjectAccess struct {
 	BaseModel
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
@@ -16,15 +17,21 @@ import (
 // AuthMiddleware provides JWT authentication middleware
 type AuthMiddleware struct {
 	config *config.AuthConfig
+	jwks   *JWKS
 	logger *logging.Logger
 }
 
-// NewAuthMiddleware creates a new authentication middleware
+// NewAuthMiddleware creates a new authentication middleware. Tokens are verified with the keys
+// at the configured JWKS URL, or with the JWT secret if there is none.
 func NewAuthMiddleware(cfg *config.AuthConfig, logger *logging.Logger) *AuthMiddleware {
-	return &AuthMiddleware{
+	m := &AuthMiddleware{
 		config: cfg,
 		logger: logger,
 	}
+	if cfg.JWKSUrl != "" {
+		m.jwks = NewJWKS(cfg.JWKSUrl, &http.Client{Timeout: 10 * time.Second})
+	}
+	return m
 }
 
 // RequireAuth middleware validates JWT tokens
@@ -112,15 +119,51 @@ func (m *AuthMiddleware) extractToken(c *gin.Context) string {
 
 // validateToken validates a JWT token and returns the claims
 func (m *AuthMiddleware) validateToken(tokenString string) (jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+	validation := JWTValidation{
+		Issuer:   m.config.Issuer,
+		Audience: m.config.Audience,
+	}
+	if m.jwks != nil {
+		validation.Methods = AsymmetricSigningMethods
+		return ParseJWT(tokenString, m.jwks.Keyfunc, validation)
+	}
+
+	return ParseJWT(tokenString, func(token *jwt.Token) (interface{}, error) {
 		// Validate the signing method
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 
 		return []byte(m.config.JWTSecret), nil
-	})
+	}, validation)
+}
+
+// JWTValidation describes what a token must satisfy besides a valid signature
+type JWTValidation struct {
+	Issuer        string   // Required "iss" claim, if set
+	Audience      string   // Required "aud" claim or member of it, if set
+	Methods       []string // Accepted signing algorithms, if set
+	RequireExpiry bool     // Reject tokens without an "exp" claim
+}
+
+// ParseJWT verifies a token's signature with keyfunc, checks its expiry, issuer and audience and
+// returns the claims
+func ParseJWT(tokenString string, keyfunc jwt.Keyfunc, validation JWTValidation) (jwt.MapClaims, error) {
+	var options []jwt.ParserOption
+	if validation.Issuer != "" {
+		options = append(options, jwt.WithIssuer(validation.Issuer))
+	}
+	if validation.Audience != "" {
+		options = append(options, jwt.WithAudience(validation.Audience))
+	}
+	if len(validation.Methods) > 0 {
+		options = append(options, jwt.WithValidMethods(validation.Methods))
+	}
+	if validation.RequireExpiry {
+		options = append(options, jwt.WithExpirationRequired())
+	}
 
+	token, err := jwt.NewParser(options...).Parse(tokenString, keyfunc)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %w", err)
 	}
@@ -134,20 +177,6 @@ func (m *AuthMiddleware) validateToken(tokenString string) (jwt.MapClaims, error
 		return nil, fmt.Errorf("failed to parse claims")
 	}
 
-	// Validate issuer if configured
-	if m.config.Issuer != "" {
-		if iss, ok := claims["iss"]; !ok || iss != m.config.Issuer {
-			return nil, fmt.Errorf("invalid issuer")
-		}
-	}
-
-	// Validate audience if configured
-	if m.config.Audience != "" {
-		if aud, ok := claims["aud"]; !ok || aud != m.config.Audience {
-			return nil, fmt.Errorf("invalid audience")
-		}
-	}
-
 	return claims, nil
 }
 
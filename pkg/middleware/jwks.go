@@ -0,0 +1,206 @@
+package middleware
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// AsymmetricSigningMethods are the JWT algorithms accepted for keys published in a JWKS
+var AsymmetricSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// jwksMinRefreshInterval limits how often an unknown key ID triggers a refetch, so that tokens
+// with made-up key IDs cannot be used to flood the issuer
+const jwksMinRefreshInterval = time.Minute
+
+// JWKS fetches and caches the signing keys published at a JSON Web Key Set URL
+type JWKS struct {
+	url    string
+	client *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+// NewJWKS creates a key set for the given JWKS URL. Keys are fetched on first use.
+func NewJWKS(url string, client *http.Client) *JWKS {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &JWKS{
+		url:    url,
+		client: client,
+	}
+}
+
+// Keyfunc returns the key a token was signed with, for use with jwt.Parse. The key set is
+// refetched when a token names a key that is not cached, so that issuers can rotate keys.
+func (j *JWKS) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if key, ok := j.lookup(kid); ok {
+		return key, nil
+	}
+	if !j.fetchedAt.IsZero() && time.Since(j.fetchedAt) < jwksMinRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	if err := j.refresh(context.Background()); err != nil {
+		return nil, err
+	}
+	if key, ok := j.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// lookup finds a cached key. Tokens without a key ID are accepted if the set has a single key.
+func (j *JWKS) lookup(kid string) (interface{}, bool) {
+	if kid == "" && len(j.keys) == 1 {
+		for _, key := range j.keys {
+			return key, true
+		}
+	}
+	key, ok := j.keys[kid]
+	return key, ok
+}
+
+// refresh fetches the key set, skipping keys of unsupported types
+func (j *JWKS) refresh(ctx context.Context) error {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := getJSON(ctx, j.client, j.url, &set); err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+
+	j.keys = keys
+	j.fetchedAt = time.Now()
+	return nil
+}
+
+// DiscoverJWKSURL reads the JWKS URL from an OpenID Connect issuer's discovery document
+func DiscoverJWKSURL(ctx context.Context, client *http.Client, issuer string) (string, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	var discovery struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := getJSON(ctx, client, strings.TrimSuffix(issuer, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+		return "", fmt.Errorf("failed to discover OIDC configuration: %w", err)
+	}
+	if discovery.JWKSURI == "" {
+		return "", fmt.Errorf("OIDC configuration of %s has no jwks_uri", issuer)
+	}
+	return discovery.JWKSURI, nil
+}
+
+// jsonWebKey is a public key in JWK format (RFC 7517)
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey converts an RSA or EC key to its crypto type
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("invalid EC key")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}